@@ -0,0 +1,310 @@
+// Package agent implements the session agent: a background process that
+// holds credentials read from the keyring in memory for a short time, so
+// keyring backends that prompt on every access prompt once per session.
+//
+// The agent listens on a unix socket only the user can reach. It never opens
+// the keyring itself; clients put what they read and get it back later.
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	envSocket  = "BB_AGENT_SOCKET"
+	envRuntime = "XDG_RUNTIME_DIR"
+
+	// DefaultTTL is how long an entry is kept, and how long an idle agent
+	// runs before it exits
+	DefaultTTL = 15 * time.Minute
+
+	// dialTimeout keeps commands fast when no agent is running
+	dialTimeout = 200 * time.Millisecond
+	// requestTimeout bounds a whole request, so a stuck agent cannot hang bbc
+	requestTimeout = 2 * time.Second
+)
+
+// ErrNotRunning indicates no agent is listening on the socket.
+var ErrNotRunning = errors.New("agent not running")
+
+// SocketPath returns the agent's socket: BB_AGENT_SOCKET, else agent.sock in
+// $XDG_RUNTIME_DIR/bb, else in a per-user directory under the temp directory.
+func SocketPath() string {
+	if path := strings.TrimSpace(os.Getenv(envSocket)); path != "" {
+		return path
+	}
+	if dir := strings.TrimSpace(os.Getenv(envRuntime)); dir != "" {
+		return filepath.Join(dir, "bb", "agent.sock")
+	}
+	return filepath.Join(os.TempDir(), "bb-"+strconv.Itoa(os.Getuid()), "agent.sock")
+}
+
+type request struct {
+	Op    string `json:"op"` // get, put, forget, status or stop
+	Key   string `json:"key,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+type response struct {
+	Value  string `json:"value,omitempty"`
+	Found  bool   `json:"found,omitempty"`
+	Status Status `json:"status,omitzero"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Status describes a running agent.
+type Status struct {
+	Running bool      `json:"running"`
+	PID     int       `json:"pid"`
+	Socket  string    `json:"socket"`
+	TTL     string    `json:"ttl"`
+	Entries int       `json:"entries"` // unexpired entries held
+	Started time.Time `json:"started"`
+}
+
+type entry struct {
+	value   string
+	expires time.Time
+}
+
+// Server holds entries in memory until they expire.
+type Server struct {
+	ttl     time.Duration
+	started time.Time
+
+	mu       sync.Mutex
+	entries  map[string]entry
+	lastUsed time.Time
+	stop     context.CancelFunc
+}
+
+// NewServer returns a server keeping entries for ttl.
+func NewServer(ttl time.Duration) *Server {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	now := time.Now()
+	return &Server{ttl: ttl, started: now, entries: make(map[string]entry), lastUsed: now}
+}
+
+// Listen creates the socket at path, in a directory only the user can
+// access. The directory is created if missing; an existing one must already
+// be the user's with mode 700, as Listen never changes the mode of a
+// directory it did not create. A socket left behind by an agent that died is
+// replaced; a live agent is an error.
+func Listen(path string) (net.Listener, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(filepath.Dir(dir), 0o700); err != nil {
+		return nil, err
+	}
+	switch err := os.Mkdir(dir, 0o700); {
+	case err == nil:
+		// The umask may have taken more than the group and other bits
+		if err := os.Chmod(dir, 0o700); err != nil {
+			return nil, err
+		}
+	case !errors.Is(err, fs.ErrExist):
+		return nil, err
+	}
+	if err := privateDir(dir); err != nil {
+		return nil, fmt.Errorf("unsafe socket directory: %w", err)
+	}
+	if conn, err := net.DialTimeout("unix", path, dialTimeout); err == nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("an agent is already listening on %s", path)
+	}
+	_ = os.Remove(path)
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		_ = ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
+
+// Serve answers requests on ln until ctx is done, a stop request arrives, or
+// no request came for the TTL. It closes ln.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	s.mu.Lock()
+	s.stop = cancel
+	s.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(s.ttl / 10)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				_ = ln.Close()
+				return
+			case <-ticker.C:
+				if s.idle() {
+					cancel()
+				}
+			}
+		}
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go s.handle(ln.Addr().String(), conn)
+	}
+}
+
+// idle drops expired entries and reports whether the server went unused for
+// the TTL
+func (s *Server) idle() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for key, e := range s.entries {
+		if now.After(e.expires) {
+			delete(s.entries, key)
+		}
+	}
+	return now.Sub(s.lastUsed) > s.ttl
+}
+
+func (s *Server) handle(socket string, conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(time.Now().Add(requestTimeout))
+
+	var req request
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+		return
+	}
+	_ = json.NewEncoder(conn).Encode(s.answer(socket, req))
+}
+
+func (s *Server) answer(socket string, req request) response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	s.lastUsed = now
+
+	switch req.Op {
+	case "get":
+		e, ok := s.entries[req.Key]
+		if !ok || now.After(e.expires) {
+			delete(s.entries, req.Key)
+			return response{}
+		}
+		return response{Value: e.value, Found: true}
+	case "put":
+		s.entries[req.Key] = entry{value: req.Value, expires: now.Add(s.ttl)}
+		return response{}
+	case "forget":
+		delete(s.entries, req.Key)
+		return response{}
+	case "status":
+		count := 0
+		for _, e := range s.entries {
+			if !now.After(e.expires) {
+				count++
+			}
+		}
+		return response{Status: Status{Running: true, PID: os.Getpid(), Socket: socket, TTL: s.ttl.String(), Entries: count, Started: s.started}}
+	case "stop":
+		if s.stop != nil {
+			s.stop()
+		}
+		return response{}
+	}
+	return response{Error: fmt.Sprintf("unknown op %q", req.Op)}
+}
+
+// call sends one request to the agent at SocketPath, once it made sure the
+// agent is the user's own: the socket's directory is private to the user and
+// the process listening on it runs as the user. Otherwise another local user
+// could collect the credentials put, or answer gets with their own.
+func call(req request) (response, error) {
+	path := SocketPath()
+	if err := privateDir(filepath.Dir(path)); errors.Is(err, fs.ErrNotExist) {
+		return response{}, ErrNotRunning
+	} else if err != nil {
+		return response{}, fmt.Errorf("agent: unsafe socket directory: %w", err)
+	}
+
+	conn, err := net.DialTimeout("unix", path, dialTimeout)
+	if err != nil {
+		return response{}, ErrNotRunning
+	}
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(time.Now().Add(requestTimeout))
+
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return response{}, fmt.Errorf("agent: %s is not a unix socket", path)
+	}
+	if uid, err := peerUID(unixConn); err != nil {
+		return response{}, fmt.Errorf("agent: %w", err)
+	} else if uid != os.Getuid() {
+		return response{}, fmt.Errorf("agent: %s is served by another user (uid %d)", path, uid)
+	}
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return response{}, fmt.Errorf("agent: %w", err)
+	}
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return response{}, fmt.Errorf("agent: %w", err)
+	}
+	if resp.Error != "" {
+		return response{}, fmt.Errorf("agent: %s", resp.Error)
+	}
+	return resp, nil
+}
+
+// Get returns the value the agent holds for key, if any.
+func Get(key string) (string, bool, error) {
+	resp, err := call(request{Op: "get", Key: key})
+	return resp.Value, resp.Found, err
+}
+
+// Put hands a value to the agent for its TTL.
+func Put(key, value string) error {
+	_, err := call(request{Op: "put", Key: key, Value: value})
+	return err
+}
+
+// Forget drops the agent's value for key, so the next Get misses.
+func Forget(key string) error {
+	_, err := call(request{Op: "forget", Key: key})
+	return err
+}
+
+// GetStatus describes the running agent.
+func GetStatus() (Status, error) {
+	resp, err := call(request{Op: "status"})
+	return resp.Status, err
+}
+
+// Stop asks the running agent to exit.
+func Stop() error {
+	_, err := call(request{Op: "stop"})
+	return err
+}
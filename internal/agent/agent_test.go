@@ -0,0 +1,126 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// startAgent runs an agent on a fresh socket for the duration of the test.
+// The socket lives in a short temp path, as unix socket paths are limited to
+// about 100 bytes.
+func startAgent(t *testing.T, ttl time.Duration) {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "bbag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	path := filepath.Join(dir, "s", "agent.sock")
+	t.Setenv(envSocket, path)
+
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- NewServer(ttl).Serve(ctx, ln) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("serve: %v", err)
+		}
+	})
+}
+
+func TestAgent(t *testing.T) {
+	startAgent(t, time.Minute)
+
+	if _, err := Listen(SocketPath()); err == nil {
+		t.Fatal("expected a second agent on the same socket to fail")
+	}
+	info, err := os.Stat(filepath.Dir(SocketPath()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o700 {
+		t.Errorf("socket directory mode = %o, want 700", perm)
+	}
+
+	if _, ok, err := Get("k"); err != nil || ok {
+		t.Fatalf("get before put: ok=%v err=%v", ok, err)
+	}
+	if err := Put("k", "v"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if got, ok, err := Get("k"); err != nil || !ok || got != "v" {
+		t.Fatalf("get: %q ok=%v err=%v", got, ok, err)
+	}
+	status, err := GetStatus()
+	if err != nil || !status.Running || status.Entries != 1 || status.PID != os.Getpid() {
+		t.Fatalf("status: %+v err=%v", status, err)
+	}
+	if err := Forget("k"); err != nil {
+		t.Fatalf("forget: %v", err)
+	}
+	if _, ok, _ := Get("k"); ok {
+		t.Fatal("expected forget to drop the entry")
+	}
+}
+
+func TestAgentExpiry(t *testing.T) {
+	startAgent(t, 50*time.Millisecond)
+
+	if err := Put("k", "v"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	time.Sleep(80 * time.Millisecond)
+	if _, ok, _ := Get("k"); ok {
+		t.Fatal("expected the entry to expire")
+	}
+
+	// Idle for longer than the TTL, the agent exits
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := GetStatus(); errors.Is(err, ErrNotRunning) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected an idle agent to exit")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNotRunning(t *testing.T) {
+	t.Setenv(envSocket, filepath.Join(t.TempDir(), "bb", "none.sock"))
+	if _, _, err := Get("k"); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("err=%v want %v", err, ErrNotRunning)
+	}
+}
+
+func TestSharedSocketDirectory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "agent.sock")
+	t.Setenv(envSocket, path)
+
+	// Neither the agent nor its clients use a directory others can reach,
+	// and the agent leaves its mode alone
+	if ln, err := Listen(path); err == nil {
+		_ = ln.Close()
+		t.Fatal("expected listening in a directory others can read to fail")
+	}
+	if info, err := os.Stat(dir); err != nil || info.Mode().Perm() != 0o755 {
+		t.Fatalf("directory mode changed: %v, %v", info.Mode(), err)
+	}
+	if err := Put("k", "secret"); err == nil || errors.Is(err, ErrNotRunning) {
+		t.Fatalf("put err=%v, want an unsafe directory error", err)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !freebsd
+
+package agent
+
+import (
+	"errors"
+	"net"
+)
+
+// peerUID fails where the peer's credentials cannot be read, so the agent is
+// never trusted there
+func peerUID(*net.UnixConn) (int, error) {
+	return 0, errors.New("cannot verify the agent's user on this platform")
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package agent
+
+import "errors"
+
+var errUnsupported = errors.New("the agent is not supported on this platform")
+
+func privateDir(string) error {
+	return errUnsupported
+}
@@ -5,12 +5,18 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"os/signal"
 	"syscall"
 
+	"github.com/spf13/cobra"
+
 	"github.com/ghoseb/bb/internal/build"
+	"github.com/ghoseb/bb/pkg/cmd/alias"
+	"github.com/ghoseb/bb/pkg/cmd/extension"
 	"github.com/ghoseb/bb/pkg/cmd/root"
 	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/httpx"
 	"github.com/ghoseb/bb/pkg/iostreams"
 )
 
@@ -18,14 +24,51 @@ import (
 func Main() int {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	// Each unique API read is sent at most once per invocation
+	ctx = httpx.WithMemo(ctx)
 
 	ios := iostreams.System()
 	f := cmdutil.NewFactory(build.Version, ios)
 
+	// Apply configured colour mode; config errors surface when commands load it
+	if cfg, err := f.Config(); err == nil {
+		switch cfg.Color() {
+		case "always":
+			ios.SetColorEnabled(true)
+		case "never":
+			ios.SetColorEnabled(false)
+		}
+	}
+	// The pager runs a command, so it comes from the user config, never .bb.yml
+	if cfg, err := f.UserConfig(); err == nil && cfg.Pager() != "" {
+		ios.SetPager(cfg.Pager())
+	} else {
+		ios.SetPager(os.Getenv("PAGER"))
+	}
+
 	rootCmd := root.NewCmdRoot(f)
 	rootCmd.SetContext(ctx)
 
-	if err := rootCmd.ExecuteContext(ctx); err != nil {
+	args, isShell, err := expandArgs(f, rootCmd, os.Args[1:])
+	if err != nil {
+		_, _ = fmt.Fprintf(ios.ErrOut, "Error: %v\n", err)
+		return 1
+	}
+	if isShell {
+		return runShellAlias(ctx, ios, args)
+	}
+	if exe, ok := findExtension(rootCmd, args); ok {
+		return runExternal(ctx, ios, exe, args[1:], extension.Environ(f))
+	}
+	rootCmd.SetArgs(args)
+
+	err = rootCmd.ExecuteContext(ctx)
+	warnStale(f)
+	if err != nil {
+		// The request was printed instead of sent, as asked
+		if errors.Is(err, httpx.ErrDryRun) {
+			return 0
+		}
 		var exitErr *cmdutil.ExitError
 		if errors.As(err, &exitErr) {
 			if exitErr.Msg != "" {
@@ -39,3 +82,79 @@ func Main() int {
 
 	return 0
 }
+
+// warnStale marks output that came from the response cache rather than the
+// API, on stderr so it never mixes with JSON
+func warnStale(f *cmdutil.Factory) {
+	if t := f.StaleAsOf(); !t.IsZero() {
+		_, _ = fmt.Fprintf(f.IOStreams.ErrOut, "stale as of %s: served from the offline cache\n",
+			t.Local().Format("2006-01-02 15:04:05"))
+	}
+}
+
+// expandArgs expands a leading alias from the user or local config, never a
+// repository's .bb.yml, whose shell aliases would run for whoever clones it.
+// Arguments naming a built-in command are returned unchanged, so aliases can
+// never shadow commands.
+func expandArgs(f *cmdutil.Factory, rootCmd *cobra.Command, args []string) ([]string, bool, error) {
+	if len(args) == 0 {
+		return args, false, nil
+	}
+	if _, _, err := rootCmd.Find(args); err == nil {
+		return args, false, nil
+	}
+
+	cfg, err := f.UserConfig()
+	if err != nil {
+		// Config errors surface when the command itself loads the config
+		return args, false, nil
+	}
+	aliases := cfg.Aliases()
+	if _, ok := aliases[args[0]]; !ok {
+		return args, false, nil
+	}
+
+	return alias.ExpandAlias(aliases, args)
+}
+
+// findExtension returns the extension executable args[0] names, unless it is
+// a built-in command, so extensions can never shadow commands either
+func findExtension(rootCmd *cobra.Command, args []string) (string, bool) {
+	if len(args) == 0 {
+		return "", false
+	}
+	if _, _, err := rootCmd.Find(args); err == nil {
+		return "", false
+	}
+	return extension.Lookup(args[0])
+}
+
+// runShellAlias runs an expanded shell alias and returns its exit code
+func runShellAlias(ctx context.Context, ios *iostreams.IOStreams, args []string) int {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		_, _ = fmt.Fprintf(ios.ErrOut, "Error: shell aliases require sh: %v\n", err)
+		return 1
+	}
+	return runExternal(ctx, ios, sh, args, nil)
+}
+
+// runExternal runs an executable attached to the terminal and returns its
+// exit code. A nil env inherits the environment.
+func runExternal(ctx context.Context, ios *iostreams.IOStreams, exe string, args []string, env []string) int {
+	cmd := exec.CommandContext(ctx, exe, args...)
+	cmd.Env = env
+	cmd.Stdin = ios.In
+	cmd.Stdout = ios.Out
+	cmd.Stderr = ios.ErrOut
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode()
+		}
+		_, _ = fmt.Fprintf(ios.ErrOut, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
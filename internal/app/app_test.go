@@ -0,0 +1,54 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/internal/config"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/iostreams"
+)
+
+func TestExpandArgsIgnoresProjectAliases(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+
+	f := cmdutil.NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}})
+	f.GitClient.Dir = t.TempDir()
+	if _, err := f.GitClient.Run(context.Background(), "init", "--quiet"); err != nil {
+		t.Fatalf("git init: %v", err)
+	}
+	project := "aliases:\n  co: \"!echo PWNED\"\n  rl: review list\n"
+	if err := os.WriteFile(filepath.Join(f.GitClient.Dir, ".bb.yml"), []byte(project), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	user := config.New(config.DefaultPath())
+	user.Set("aliases.mine", "review list --mine")
+	if err := user.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd := &cobra.Command{Use: "bbc"}
+	rootCmd.AddCommand(&cobra.Command{Use: "review"})
+	for _, args := range [][]string{{"co"}, {"rl"}} {
+		got, isShell, err := expandArgs(f, rootCmd, args)
+		if err != nil || isShell || strings.Join(got, " ") != args[0] {
+			t.Errorf("expandArgs(%v) = %v, %v, %v, want it unexpanded", args, got, isShell, err)
+		}
+	}
+
+	got, _, err := expandArgs(f, rootCmd, []string{"mine"})
+	if err != nil || strings.Join(got, " ") != "review list --mine" {
+		t.Errorf("expandArgs(mine) = %v, %v, want the user alias", got, err)
+	}
+}
@@ -0,0 +1,709 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	envConfigDir = "BB_CONFIG_DIR"
+	envXDGConfig = "XDG_CONFIG_HOME"
+	envXDGCache  = "XDG_CACHE_HOME"
+	envXDGData   = "XDG_DATA_HOME"
+
+	fileName        = "config.yml"
+	localFileName   = "bb.yml"
+	projectFileName = ".bb.yml"
+)
+
+// Option describes a known configuration key.
+type Option struct {
+	Key           string
+	Description   string
+	Default       string
+	AllowedValues []string
+	// PositiveInt requires the value to be a whole number of at least 1
+	PositiveInt bool
+	// Duration requires the value to be a duration such as 30s or 5m
+	Duration bool
+	// Project allows the key in a checked-in .bb.yml; other keys there are
+	// ignored (see ProjectSettings)
+	Project bool
+	// Flag marks a <command path>.<flag> key that sets a flag's default; no
+	// other keys are applied to flags
+	Flag bool
+}
+
+// Options lists the settings bb understands, in display order.
+var Options = []Option{
+	{Key: "default_workspace", Description: "Workspace used when --workspace is not set"},
+	{Key: "default_repo", Description: "Repository used when --repo is not set"},
+	{Key: "format", Description: "Default output format for read commands", Default: "markdown", AllowedValues: []string{"markdown", "json", "table"}, Project: true},
+	{Key: "pager", Description: "Pager for long output on a terminal (else $PAGER)"},
+	{Key: "editor", Description: "Editor for composing descriptions and comments"},
+	{Key: "color", Description: "When to use colour output", Default: "auto", AllowedValues: []string{"auto", "always", "never"}},
+	{Key: "git_protocol", Description: "Protocol used by repo clone", Default: "https", AllowedValues: []string{"https", "ssh"}},
+	{Key: "reviewers", Description: "Default PR reviewers (comma-separated UUIDs or account IDs)", Project: true},
+	{Key: "pr_template", Description: "PR description template, relative to the repository root", Project: true},
+	{Key: "target_branch", Description: "Default PR target branch", Project: true},
+	{Key: "review_checklist", Description: "Review checklist items shown by review start and posted by review submit", Project: true},
+	{Key: "review.list.state", Description: "Default state for review list", AllowedValues: []string{"OPEN", "MERGED", "DECLINED"}, Flag: true},
+	{Key: "review.limit", Description: "Default --limit for review commands", Flag: true},
+	{Key: "workspaces.<workspace>.default_repo", Description: "Repository used in a workspace when --repo is not set"},
+	{Key: "host", Description: "Host used when --host is not set (see the hosts section)", Default: DefaultHost},
+	{Key: "profile", Description: "Active auth profile when --profile is not set (see auth switch)"},
+	{Key: "concurrency", Description: "Maximum API requests multi-request commands send at once (--concurrency)", Default: "5", PositiveInt: true},
+	{Key: "rate_limit_wait", Description: "Longest a request waits for the API rate limit to reset (e.g. 30s, 5m; 0 fails at once)", Default: "1m", Duration: true},
+	{Key: "rate_limit_pacing", Description: "Space requests out once the API rate limit runs low, instead of waiting after a 429", Default: "false", AllowedValues: []string{"true", "false"}},
+	{Key: "agent", Description: "Ask the session agent (bbc agent start) for credentials before the keyring", Default: "false", AllowedValues: []string{"true", "false"}},
+	{Key: "audit_log", Description: "Record successful mutating API requests in the audit log", Default: "true", AllowedValues: []string{"true", "false"}},
+	{Key: "changelog.labels", Description: "Title labels that get their own changelog section (label=Section, comma-separated)"},
+}
+
+// profilePrefix namespaces settings that apply only while a profile is active,
+// e.g. profiles.work.default_workspace.
+const profilePrefix = "profiles."
+
+// DefaultHost is the Bitbucket Cloud host, available without a hosts entry.
+const DefaultHost = "bitbucket.org"
+
+// Host describes a Bitbucket instance, configured under hosts.<hostname>:
+//
+//	hosts:
+//	  bitbucket.example.com:
+//	    api_url: https://bitbucket.example.com/api/2.0
+//	    auth: bearer
+//	    profile: work
+type Host struct {
+	Name    string
+	APIURL  string // API base URL; empty means the Bitbucket Cloud default
+	Auth    string // "basic" (username + App Password) or "bearer" (access token)
+	Profile string // credentials profile; empty uses the default credentials
+}
+
+// Config is a YAML-backed settings tree. Keys are dot-separated paths into
+// nested maps, so "review.limit" addresses {review: {limit: ...}}.
+type Config struct {
+	path string
+	data map[string]any
+}
+
+// Dir returns the directory holding bb's configuration files.
+//
+// BB_CONFIG_DIR wins, then XDG_CONFIG_HOME/bb on every platform, then the
+// platform default (~/.config/bb on Linux, ~/Library/Application Support/bb
+// on macOS, %AppData%\bb on Windows).
+func Dir() string {
+	if dir := strings.TrimSpace(os.Getenv(envConfigDir)); dir != "" {
+		return dir
+	}
+	if xdg := strings.TrimSpace(os.Getenv(envXDGConfig)); xdg != "" {
+		return filepath.Join(xdg, "bb")
+	}
+	if runtime.GOOS != "windows" && runtime.GOOS != "darwin" {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, ".config", "bb")
+		}
+	}
+	if dir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(dir, "bb")
+	}
+	return ".bb"
+}
+
+// CacheDir returns the directory for state bb can rebuild, such as the inbox
+// watermark.
+//
+// With BB_CONFIG_DIR set it is the cache directory inside it, so one variable
+// isolates all of bb's files; otherwise XDG_CACHE_HOME/bb, then the platform
+// default (~/.cache/bb on Linux, ~/Library/Caches/bb on macOS,
+// %LocalAppData%\bb on Windows).
+func CacheDir() string {
+	if dir := strings.TrimSpace(os.Getenv(envConfigDir)); dir != "" {
+		return filepath.Join(dir, "cache")
+	}
+	if xdg := strings.TrimSpace(os.Getenv(envXDGCache)); xdg != "" {
+		return filepath.Join(xdg, "bb")
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "bb")
+	}
+	return filepath.Join(Dir(), "cache")
+}
+
+// DataDir returns the directory for records bb keeps but cannot rebuild, such
+// as the audit log.
+//
+// With BB_CONFIG_DIR set it is the data directory inside it; otherwise
+// XDG_DATA_HOME/bb, then ~/.local/share/bb on Linux and the configuration
+// directory on macOS and Windows.
+func DataDir() string {
+	if dir := strings.TrimSpace(os.Getenv(envConfigDir)); dir != "" {
+		return filepath.Join(dir, "data")
+	}
+	if xdg := strings.TrimSpace(os.Getenv(envXDGData)); xdg != "" {
+		return filepath.Join(xdg, "bb")
+	}
+	if runtime.GOOS != "windows" && runtime.GOOS != "darwin" {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, ".local", "share", "bb")
+		}
+	}
+	return Dir()
+}
+
+// DefaultPath returns the path of the user configuration file.
+func DefaultPath() string {
+	return filepath.Join(Dir(), fileName)
+}
+
+// LocalPath returns the path of the per-repository configuration file stored
+// inside gitDir, so it is never committed.
+func LocalPath(gitDir string) string {
+	return filepath.Join(gitDir, localFileName)
+}
+
+// ProjectPath returns the path of the checked-in project configuration file
+// at the repository root. It is shared by everyone working on the repository.
+func ProjectPath(repoRoot string) string {
+	return filepath.Join(repoRoot, projectFileName)
+}
+
+// LookupOption returns the known option for key. Option keys may contain
+// <placeholder> segments that match any single segment of key.
+func LookupOption(key string) (Option, bool) {
+	// profiles.<profile>.<key> overrides <key> for that profile
+	if rest, ok := strings.CutPrefix(key, profilePrefix); ok {
+		if name, sub, ok := strings.Cut(rest, "."); ok && name != "" && sub != "profile" {
+			return LookupOption(sub)
+		}
+		return Option{}, false
+	}
+	for _, opt := range Options {
+		if matchKey(opt.Key, key) {
+			return opt, true
+		}
+	}
+	return Option{}, false
+}
+
+// IsPattern reports whether the option key contains placeholder segments.
+func (o Option) IsPattern() bool {
+	return strings.Contains(o.Key, "<")
+}
+
+// Match reports whether key is an instance of the option.
+func (o Option) Match(key string) bool {
+	return matchKey(o.Key, key)
+}
+
+func matchKey(pattern, key string) bool {
+	pp, kp := strings.Split(pattern, "."), strings.Split(key, ".")
+	if len(pp) != len(kp) {
+		return false
+	}
+	for i := range pp {
+		if strings.HasPrefix(pp[i], "<") {
+			if kp[i] == "" {
+				return false
+			}
+			continue
+		}
+		if pp[i] != kp[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks that key is a known option and value is one it allows.
+func Validate(key, value string) error {
+	opt, ok := LookupOption(key)
+	if !ok {
+		return fmt.Errorf("unknown config key %q", key)
+	}
+	if opt.PositiveInt {
+		if n, err := strconv.Atoi(value); err != nil || n < 1 {
+			return fmt.Errorf("invalid value %q for %s (a whole number of at least 1)", value, key)
+		}
+	}
+	if opt.Duration {
+		if d, err := time.ParseDuration(value); err != nil || d < 0 {
+			return fmt.Errorf("invalid value %q for %s (a duration such as 30s or 5m)", value, key)
+		}
+	}
+	if len(opt.AllowedValues) == 0 {
+		return nil
+	}
+	for _, v := range opt.AllowedValues {
+		if v == value {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid value %q for %s (allowed: %s)", value, key, strings.Join(opt.AllowedValues, ", "))
+}
+
+// New returns an empty configuration that saves to path.
+func New(path string) *Config {
+	return &Config{path: path, data: map[string]any{}}
+}
+
+// Load reads the configuration at path. A missing file yields an empty config.
+func Load(path string) (*Config, error) {
+	cfg := New(path)
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(raw, &cfg.data); err != nil {
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	if cfg.data == nil {
+		cfg.data = map[string]any{}
+	}
+
+	return cfg, nil
+}
+
+// Merge combines layers into a single read-only view; later layers override
+// earlier ones key by key. The result has no path and cannot be saved.
+func Merge(layers ...*Config) *Config {
+	merged := New("")
+	for _, layer := range layers {
+		if layer != nil {
+			mergeMaps(merged.data, layer.data)
+		}
+	}
+	return merged
+}
+
+// Profile returns the settings scoped to the named profile, or nil when there
+// are none. Merge it over the base config to apply the profile.
+func (c *Config) Profile(name string) *Config {
+	if name == "" {
+		return nil
+	}
+	profiles, ok := c.data["profiles"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	sub, ok := profiles[name].(map[string]any)
+	if !ok {
+		return nil
+	}
+	overlay := New("")
+	mergeMaps(overlay.data, sub)
+	return overlay
+}
+
+// Path returns the file the configuration is loaded from and saved to.
+func (c *Config) Path() string {
+	return c.path
+}
+
+// Get returns the value at key, or "" when unset. Lists are joined with commas.
+func (c *Config) Get(key string) string {
+	v, ok := lookup(c.data, strings.Split(key, "."))
+	if !ok || v == nil {
+		return ""
+	}
+	switch val := v.(type) {
+	case map[string]any:
+		return ""
+	case []any:
+		return strings.Join(toStrings(val), ",")
+	}
+	return fmt.Sprint(v)
+}
+
+// GetList returns the list at key. A YAML sequence is returned element-wise and
+// a scalar is split on commas, so both forms work in files and via config set.
+func (c *Config) GetList(key string) []string {
+	v, ok := lookup(c.data, strings.Split(key, "."))
+	if !ok || v == nil {
+		return nil
+	}
+
+	var items []string
+	switch val := v.(type) {
+	case []any:
+		for _, item := range val {
+			items = append(items, fmt.Sprint(item))
+		}
+	case map[string]any:
+		return nil
+	default:
+		items = strings.Split(fmt.Sprint(val), ",")
+	}
+
+	var out []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// GetOrDefault returns the value at key, falling back to the option default.
+func (c *Config) GetOrDefault(key string) string {
+	if v := c.Get(key); v != "" {
+		return v
+	}
+	opt, _ := LookupOption(key)
+	return opt.Default
+}
+
+// Set stores value at key, creating intermediate maps as needed.
+func (c *Config) Set(key, value string) {
+	c.set(key, value)
+}
+
+func (c *Config) set(key string, value any) {
+	parts := strings.Split(key, ".")
+	m := c.data
+	for _, p := range parts[:len(parts)-1] {
+		next, ok := m[p].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			m[p] = next
+		}
+		m = next
+	}
+	m[parts[len(parts)-1]] = value
+}
+
+// Unset removes key. Empty parent maps are left in place.
+func (c *Config) Unset(key string) {
+	parts := strings.Split(key, ".")
+	m := c.data
+	for _, p := range parts[:len(parts)-1] {
+		next, ok := m[p].(map[string]any)
+		if !ok {
+			return
+		}
+		m = next
+	}
+	delete(m, parts[len(parts)-1])
+}
+
+// All returns every scalar setting keyed by its dotted path.
+func (c *Config) All() map[string]string {
+	out := make(map[string]string)
+	flatten("", c.data, out)
+	return out
+}
+
+// Keys returns the dotted paths of every scalar setting, sorted.
+func (c *Config) Keys() []string {
+	all := c.All()
+	keys := make([]string, 0, len(all))
+	for k := range all {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Save writes the configuration to its path, creating the directory if needed.
+func (c *Config) Save() error {
+	if c.path == "" {
+		return fmt.Errorf("config path is not set")
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("create config dir: %w", err)
+	}
+
+	raw, err := yaml.Marshal(c.data)
+	if err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+	if err := os.WriteFile(c.path, raw, 0o600); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	return nil
+}
+
+// Workspace returns the default workspace.
+func (c *Config) Workspace() string {
+	return c.Get("default_workspace")
+}
+
+// Repo returns the default repository slug.
+func (c *Config) Repo() string {
+	return c.Get("default_repo")
+}
+
+// Format returns the default output format ("markdown", "json", or "table").
+func (c *Config) Format() string {
+	return c.GetOrDefault("format")
+}
+
+// Pager returns the configured pager command.
+func (c *Config) Pager() string {
+	return c.Get("pager")
+}
+
+// Editor returns the configured editor command.
+func (c *Config) Editor() string {
+	return c.Get("editor")
+}
+
+// Color returns the colour mode ("auto", "always", or "never").
+func (c *Config) Color() string {
+	return c.GetOrDefault("color")
+}
+
+// GitProtocol returns the protocol used for cloning ("https" or "ssh").
+func (c *Config) GitProtocol() string {
+	return c.GetOrDefault("git_protocol")
+}
+
+// Reviewers returns the default PR reviewers.
+func (c *Config) Reviewers() []string {
+	return c.GetList("reviewers")
+}
+
+// PRTemplate returns the PR description template path.
+func (c *Config) PRTemplate() string {
+	return c.Get("pr_template")
+}
+
+// TargetBranch returns the default PR target branch.
+func (c *Config) TargetBranch() string {
+	return c.Get("target_branch")
+}
+
+// Agent reports whether credentials are cached in the session agent.
+func (c *Config) Agent() bool {
+	return c.GetOrDefault("agent") == "true"
+}
+
+// AuditLog reports whether successful mutating requests are recorded in the
+// audit log.
+func (c *Config) AuditLog() bool {
+	return c.GetOrDefault("audit_log") != "false"
+}
+
+// Concurrency returns how many API requests commands send at once; an unset
+// or invalid setting yields the default of 5.
+func (c *Config) Concurrency() int {
+	if n, err := strconv.Atoi(c.Get("concurrency")); err == nil && n >= 1 {
+		return n
+	}
+	return 5
+}
+
+// RateLimitWait returns the longest a request waits for the rate limit to
+// reset, -1 when it must not wait at all. An unset or invalid setting yields
+// the default of a minute.
+func (c *Config) RateLimitWait() time.Duration {
+	d, err := time.ParseDuration(c.Get("rate_limit_wait"))
+	switch {
+	case err != nil || d < 0:
+		return time.Minute
+	case d == 0:
+		return -1
+	}
+	return d
+}
+
+// RateLimitPacing reports whether requests are spaced out once the rate limit
+// runs low.
+func (c *Config) RateLimitPacing() bool {
+	return c.GetOrDefault("rate_limit_pacing") == "true"
+}
+
+// ChangelogLabels returns the changelog label sections as label=Section
+// entries, in the order the sections are shown.
+func (c *Config) ChangelogLabels() []string {
+	return c.GetList("changelog.labels")
+}
+
+// ReviewChecklist returns the team's review checklist items.
+func (c *Config) ReviewChecklist() []string {
+	return c.GetList("review_checklist")
+}
+
+// WorkspaceRepo returns the default repository for workspace, stored under
+// workspaces.<workspace>.default_repo.
+func (c *Config) WorkspaceRepo(workspace string) string {
+	if workspace == "" {
+		return ""
+	}
+	return c.Get("workspaces." + workspace + ".default_repo")
+}
+
+// HasWorkspaceSettings reports whether any workspace-scoped settings exist.
+func (c *Config) HasWorkspaceSettings() bool {
+	m, ok := c.data["workspaces"].(map[string]any)
+	return ok && len(m) > 0
+}
+
+// Hosts returns the configured hosts keyed by hostname. Hostnames contain dots,
+// so entries are read from the hosts map directly rather than by dotted key.
+func (c *Config) Hosts() map[string]Host {
+	hosts := make(map[string]Host)
+	m, ok := c.data["hosts"].(map[string]any)
+	if !ok {
+		return hosts
+	}
+	for name, v := range m {
+		entry, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		str := func(k string) string {
+			if s, ok := entry[k].(string); ok {
+				return s
+			}
+			return ""
+		}
+		hosts[name] = Host{Name: name, APIURL: str("api_url"), Auth: str("auth"), Profile: str("profile")}
+	}
+	return hosts
+}
+
+// LookupHost returns the host named name. DefaultHost is always known and
+// always talks to the Bitbucket Cloud API, so its credentials cannot be sent
+// elsewhere; other hosts must have a hosts entry with an api_url.
+func (c *Config) LookupHost(name string) (Host, error) {
+	host, ok := c.Hosts()[name]
+	switch {
+	case ok && name == DefaultHost && host.APIURL != "":
+		return Host{}, fmt.Errorf("host %s cannot set api_url (add the API under another host name)", name)
+	case ok && (host.APIURL != "" || name == DefaultHost):
+	case name == DefaultHost:
+		host = Host{Name: DefaultHost}
+	case ok:
+		return Host{}, fmt.Errorf("host %s has no api_url in config", name)
+	default:
+		return Host{}, fmt.Errorf("unknown host %q (add it under hosts in %s)", name, DefaultPath())
+	}
+	if host.Auth == "" {
+		host.Auth = "basic"
+	}
+	if host.Auth != "basic" && host.Auth != "bearer" {
+		return Host{}, fmt.Errorf("host %s: invalid auth %q (allowed: basic, bearer)", name, host.Auth)
+	}
+	return host, nil
+}
+
+// ProjectSettings returns the settings of c that a checked-in project config
+// may hold, the options marked Project, and the keys of all others, which it
+// drops. Anyone can commit a .bb.yml to a repository that others clone, so it
+// must not choose hosts, aliases, the editor, the profile or flag defaults.
+func (c *Config) ProjectSettings() (*Config, []string) {
+	kept := New(c.path)
+	var dropped []string
+	for _, key := range c.Keys() {
+		opt, ok := LookupOption(key)
+		if !ok || !opt.Project || strings.HasPrefix(key, profilePrefix) {
+			dropped = append(dropped, key)
+			continue
+		}
+		if v, ok := lookup(c.data, strings.Split(key, ".")); ok {
+			kept.set(key, v)
+		}
+	}
+	return kept, dropped
+}
+
+// ProjectKeys returns the keys a checked-in project config may set.
+func ProjectKeys() []string {
+	var keys []string
+	for _, opt := range Options {
+		if opt.Project {
+			keys = append(keys, opt.Key)
+		}
+	}
+	return keys
+}
+
+// Aliases returns command aliases keyed by name, stored under the "aliases" key.
+func (c *Config) Aliases() map[string]string {
+	aliases := make(map[string]string)
+	m, ok := c.data["aliases"].(map[string]any)
+	if !ok {
+		return aliases
+	}
+	for name, v := range m {
+		switch v.(type) {
+		case map[string]any, []any, nil:
+			continue
+		}
+		aliases[name] = fmt.Sprint(v)
+	}
+	return aliases
+}
+
+func lookup(m map[string]any, parts []string) (any, bool) {
+	v, ok := m[parts[0]]
+	if !ok {
+		return nil, false
+	}
+	if len(parts) == 1 {
+		return v, true
+	}
+	next, ok := v.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	return lookup(next, parts[1:])
+}
+
+func mergeMaps(dst, src map[string]any) {
+	for k, v := range src {
+		if sub, ok := v.(map[string]any); ok {
+			existing, ok := dst[k].(map[string]any)
+			if !ok {
+				existing = map[string]any{}
+				dst[k] = existing
+			}
+			mergeMaps(existing, sub)
+			continue
+		}
+		dst[k] = v
+	}
+}
+
+func toStrings(items []any) []string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = fmt.Sprint(item)
+	}
+	return out
+}
+
+func flatten(prefix string, m map[string]any, out map[string]string) {
+	for k, v := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch val := v.(type) {
+		case map[string]any:
+			flatten(key, val, out)
+		case []any:
+			out[key] = strings.Join(toStrings(val), ",")
+		case nil:
+			// Empty values are not addressable by dotted keys
+		default:
+			out[key] = fmt.Sprint(val)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadMissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "config.yml"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := cfg.Get("default_repo"); got != "" {
+		t.Errorf("expected empty value, got %q", got)
+	}
+	if got := cfg.Format(); got != "markdown" {
+		t.Errorf("expected default format markdown, got %q", got)
+	}
+}
+
+func TestSetGetNested(t *testing.T) {
+	cfg := New("")
+	cfg.Set("default_repo", "api")
+	cfg.Set("review.list.state", "MERGED")
+
+	if got := cfg.Get("default_repo"); got != "api" {
+		t.Errorf("default_repo = %q, want %q", got, "api")
+	}
+	if got := cfg.Get("review.list.state"); got != "MERGED" {
+		t.Errorf("review.list.state = %q, want %q", got, "MERGED")
+	}
+	if got := cfg.Get("review.list"); got != "" {
+		t.Errorf("expected map key to read as empty, got %q", got)
+	}
+
+	cfg.Unset("review.list.state")
+	if got := cfg.Get("review.list.state"); got != "" {
+		t.Errorf("expected unset key to be empty, got %q", got)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "config.yml")
+
+	cfg := New(path)
+	cfg.Set("default_workspace", "acme")
+	cfg.Set("review.limit", "50")
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := loaded.Workspace(); got != "acme" {
+		t.Errorf("workspace = %q, want %q", got, "acme")
+	}
+
+	keys := loaded.Keys()
+	if len(keys) != 2 || keys[0] != "default_workspace" || keys[1] != "review.limit" {
+		t.Errorf("keys = %v", keys)
+	}
+}
+
+func TestLoadNonStringValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(path, []byte("review:\n  limit: 50\nempty:\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := cfg.Get("review.limit"); got != "50" {
+		t.Errorf("review.limit = %q, want %q", got, "50")
+	}
+	if got := cfg.Get("empty"); got != "" {
+		t.Errorf("empty = %q, want empty", got)
+	}
+}
+
+func TestDir(t *testing.T) {
+	t.Setenv(envConfigDir, "")
+	t.Setenv(envXDGConfig, "/tmp/xdg")
+	if got := Dir(); got != filepath.Join("/tmp/xdg", "bb") {
+		t.Errorf("Dir with XDG = %q", got)
+	}
+
+	t.Setenv(envConfigDir, "/tmp/custom")
+	if got := Dir(); got != "/tmp/custom" {
+		t.Errorf("Dir with %s = %q", envConfigDir, got)
+	}
+}
+
+func TestCacheDir(t *testing.T) {
+	t.Setenv(envConfigDir, "")
+	t.Setenv(envXDGCache, "/tmp/xdg-cache")
+	if got := CacheDir(); got != filepath.Join("/tmp/xdg-cache", "bb") {
+		t.Errorf("CacheDir with XDG = %q", got)
+	}
+
+	t.Setenv(envConfigDir, "/tmp/custom")
+	if got := CacheDir(); got != filepath.Join("/tmp/custom", "cache") {
+		t.Errorf("CacheDir with %s = %q", envConfigDir, got)
+	}
+}
+
+func TestDataDir(t *testing.T) {
+	t.Setenv(envConfigDir, "")
+	t.Setenv(envXDGData, "/tmp/xdg-data")
+	if got := DataDir(); got != filepath.Join("/tmp/xdg-data", "bb") {
+		t.Errorf("DataDir with XDG = %q", got)
+	}
+
+	t.Setenv(envConfigDir, "/tmp/custom")
+	if got := DataDir(); got != filepath.Join("/tmp/custom", "data") {
+		t.Errorf("DataDir with %s = %q", envConfigDir, got)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	user := New("")
+	user.Set("default_repo", "api")
+	user.Set("review.limit", "20")
+	user.Set("review.list.state", "OPEN")
+
+	local := New("")
+	local.Set("review.limit", "50")
+
+	merged := Merge(user, local)
+	if got := merged.Get("default_repo"); got != "api" {
+		t.Errorf("default_repo = %q, want %q", got, "api")
+	}
+	if got := merged.Get("review.limit"); got != "50" {
+		t.Errorf("review.limit = %q, want local override %q", got, "50")
+	}
+	if got := merged.Get("review.list.state"); got != "OPEN" {
+		t.Errorf("review.list.state = %q, want %q", got, "OPEN")
+	}
+	if got := user.Get("review.limit"); got != "20" {
+		t.Errorf("merge modified its input: review.limit = %q", got)
+	}
+	if err := merged.Save(); err == nil {
+		t.Error("expected merged config to refuse Save")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate("git_protocol", "ssh"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := Validate("editor", "vim"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := Validate("git_protocol", "ftp"); err == nil {
+		t.Error("expected error for disallowed value")
+	}
+	if err := Validate("nope", "x"); err == nil {
+		t.Error("expected error for unknown key")
+	}
+	if err := Validate("mcp.serve.allow-write", "true"); err == nil {
+		t.Error("expected error for a flag without a flag default option")
+	}
+	if err := Validate("concurrency", "8"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, v := range []string{"0", "many"} {
+		if err := Validate("concurrency", v); err == nil {
+			t.Errorf("expected error for concurrency %q", v)
+		}
+	}
+	if err := Validate("rate_limit_wait", "5m"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := Validate("rate_limit_wait", "5"); err == nil {
+		t.Error("expected error for a duration without a unit")
+	}
+}
+
+func TestGetList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(path, []byte("reviewers:\n  - alice\n  - bob\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := cfg.GetList("reviewers"); len(got) != 2 || got[0] != "alice" || got[1] != "bob" {
+		t.Errorf("list form = %v", got)
+	}
+	if got := cfg.Get("reviewers"); got != "alice,bob" {
+		t.Errorf("Get = %q, want %q", got, "alice,bob")
+	}
+
+	cfg.Set("reviewers", "carol, dave,")
+	if got := cfg.GetList("reviewers"); len(got) != 2 || got[0] != "carol" || got[1] != "dave" {
+		t.Errorf("comma form = %v", got)
+	}
+}
+
+func TestLookupHost(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	raw := `hosts:
+  bitbucket.example.com:
+    api_url: https://bitbucket.example.com/api/2.0
+    auth: bearer
+    profile: work
+  broken.example.com:
+    auth: basic
+`
+	if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	host, err := cfg.LookupHost("bitbucket.example.com")
+	if err != nil {
+		t.Fatalf("LookupHost: %v", err)
+	}
+	want := Host{Name: "bitbucket.example.com", APIURL: "https://bitbucket.example.com/api/2.0", Auth: "bearer", Profile: "work"}
+	if host != want {
+		t.Errorf("got %+v, want %+v", host, want)
+	}
+
+	host, err = cfg.LookupHost(DefaultHost)
+	if err != nil || host.APIURL != "" || host.Auth != "basic" {
+		t.Errorf("default host = %+v, %v", host, err)
+	}
+
+	if _, err := cfg.LookupHost("broken.example.com"); err == nil {
+		t.Error("expected error for host without api_url")
+	}
+	if _, err := cfg.LookupHost("unknown.example.com"); err == nil {
+		t.Error("expected error for unknown host")
+	}
+
+	redirect := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(redirect, []byte("hosts:\n  bitbucket.org:\n    api_url: http://127.0.0.1:18765/api\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if cfg, err = Load(redirect); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if _, err := cfg.LookupHost(DefaultHost); err == nil {
+		t.Error("expected error for an api_url on the default host")
+	}
+}
+
+func TestValidateProfileKeys(t *testing.T) {
+	if err := Validate("profiles.work.format", "json"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := Validate("profiles.work.format", "yaml"); err == nil {
+		t.Error("expected profile key to validate its value")
+	}
+	if err := Validate("profiles.work.profile", "x"); err == nil {
+		t.Error("expected nested profile selection to be rejected")
+	}
+}
+
+func TestReviewChecklist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".bb.yml")
+	if err := os.WriteFile(path, []byte("review_checklist:\n  - Tests cover the change\n  - Docs updated\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := cfg.ReviewChecklist(); len(got) != 2 || got[1] != "Docs updated" {
+		t.Errorf("ReviewChecklist() = %v", got)
+	}
+}
@@ -0,0 +1,59 @@
+package secret
+
+import (
+	"maps"
+	"os"
+	"slices"
+	"sync"
+)
+
+// MemoryStore is a Store that keeps secrets in memory, so tests can exercise
+// credential handling without a keyring. It is safe for concurrent use.
+type MemoryStore struct {
+	mu      sync.Mutex
+	secrets map[string]string
+}
+
+// NewMemoryStore returns an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{secrets: make(map[string]string)}
+}
+
+// Get retrieves a secret value.
+func (m *MemoryStore) Get(key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.secrets[key]
+	if !ok {
+		return "", os.ErrNotExist
+	}
+	return value, nil
+}
+
+// Set writes a secret value.
+func (m *MemoryStore) Set(key, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.secrets[key] = value
+	return nil
+}
+
+// Delete removes a stored secret.
+func (m *MemoryStore) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.secrets, key)
+	return nil
+}
+
+// Keys lists the keys of the stored secrets, sorted.
+func (m *MemoryStore) Keys() ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Sorted(maps.Keys(m.secrets)), nil
+}
+
+// Backend returns "memory".
+func (m *MemoryStore) Backend() string {
+	return "memory"
+}
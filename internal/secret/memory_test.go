@@ -0,0 +1,37 @@
+package secret
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestMemoryStore(t *testing.T) {
+	t.Parallel()
+
+	var store Store = NewMemoryStore()
+	if _, err := store.Get("a"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("get missing: err=%v want %v", err, os.ErrNotExist)
+	}
+	for _, key := range []string{"b", "a"} {
+		if err := store.Set(key, "value-"+key); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+	}
+	if got, err := store.Get("a"); err != nil || got != "value-a" {
+		t.Fatalf("get: %q err=%v", got, err)
+	}
+	if keys, _ := store.Keys(); !slices.Equal(keys, []string{"a", "b"}) {
+		t.Fatalf("keys = %q", keys)
+	}
+	if err := store.Delete("a"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := store.Delete("a"); err != nil {
+		t.Fatalf("delete missing: %v", err)
+	}
+	if keys, _ := store.Keys(); !slices.Equal(keys, []string{"b"}) {
+		t.Fatalf("keys after delete = %q", keys)
+	}
+}
@@ -0,0 +1,102 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	sealedVersion = 1
+	sealedKDF     = "pbkdf2-sha256"
+	// sealedIterations follows the OWASP recommendation for PBKDF2-SHA256
+	sealedIterations = 600_000
+	// Unseal only derives keys within these bounds, so a crafted export can
+	// neither hang the import nor pass with a trivially weak key
+	minSealedIterations = 100_000
+	maxSealedIterations = 10_000_000
+)
+
+// ErrWrongPassphrase indicates sealed data could not be decrypted, because the
+// passphrase is wrong or the data was modified.
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted data")
+
+// sealed is the envelope of data encrypted with a passphrase
+type sealed struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Seal encrypts data with a key derived from passphrase (PBKDF2-SHA256,
+// AES-256-GCM) and returns a self-describing JSON envelope for Unseal.
+func Seal(data []byte, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, errors.New("passphrase is required")
+	}
+
+	env := sealed{Version: sealedVersion, KDF: sealedKDF, Iterations: sealedIterations, Salt: make([]byte, 16)}
+	if _, err := rand.Read(env.Salt); err != nil {
+		return nil, err
+	}
+	gcm, err := sealedCipher(passphrase, env.Salt, env.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	env.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(env.Nonce); err != nil {
+		return nil, err
+	}
+	env.Ciphertext = gcm.Seal(nil, env.Nonce, data, nil)
+	return json.MarshalIndent(env, "", "  ")
+}
+
+// Unseal decrypts an envelope written by Seal.
+func Unseal(data []byte, passphrase string) ([]byte, error) {
+	var env sealed
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("not an encrypted export: %w", err)
+	}
+	if env.Version != sealedVersion || env.KDF != sealedKDF {
+		return nil, fmt.Errorf("unsupported export format (version %d, kdf %q)", env.Version, env.KDF)
+	}
+	if env.Iterations <= 0 || len(env.Salt) == 0 {
+		return nil, errors.New("not an encrypted export: missing key parameters")
+	}
+	if env.Iterations < minSealedIterations || env.Iterations > maxSealedIterations {
+		return nil, fmt.Errorf("unsupported export: %d key iterations (want %d to %d)", env.Iterations, minSealedIterations, maxSealedIterations)
+	}
+
+	gcm, err := sealedCipher(passphrase, env.Salt, env.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(env.Nonce) != gcm.NonceSize() {
+		return nil, ErrWrongPassphrase
+	}
+	plain, err := gcm.Open(nil, env.Nonce, env.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plain, nil
+}
+
+func sealedCipher(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,61 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestSealUnseal(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{"token":"abc"}`)
+	sealedData, err := Seal(data, "correct horse")
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if bytes.Contains(sealedData, []byte("abc")) {
+		t.Fatal("sealed data contains the plaintext")
+	}
+
+	got, err := Unseal(sealedData, "correct horse")
+	if err != nil {
+		t.Fatalf("unseal: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("got=%q want %q", got, data)
+	}
+
+	if _, err := Unseal(sealedData, "wrong"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("wrong passphrase: err=%v want %v", err, ErrWrongPassphrase)
+	}
+	if _, err := Unseal([]byte("plain text"), "correct horse"); err == nil {
+		t.Fatal("expected an error for data that is not sealed")
+	}
+	if _, err := Seal(data, ""); err == nil {
+		t.Fatal("expected an error for an empty passphrase")
+	}
+}
+
+func TestUnsealIterationBounds(t *testing.T) {
+	t.Parallel()
+
+	for _, iterations := range []int{1, minSealedIterations - 1, maxSealedIterations + 1, 1e12} {
+		env, err := json.Marshal(sealed{
+			Version:    sealedVersion,
+			KDF:        sealedKDF,
+			Iterations: iterations,
+			Salt:       []byte("0123456789abcdef"),
+			Nonce:      make([]byte, 12),
+			Ciphertext: []byte("x"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = Unseal(env, "correct horse")
+		if err == nil || errors.Is(err, ErrWrongPassphrase) {
+			t.Errorf("iterations=%d: err=%v, want an unsupported export error", iterations, err)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -102,9 +103,25 @@ func timeoutHint() string {
 	return fmt.Sprintf("keyring prompt may need more time. Increase timeout via %s (e.g. 60s or 2m)", envTimeout)
 }
 
-// Store wraps access to the configured keyring backend.
-type Store struct {
-	kr keyring.Keyring
+// Store holds secrets by key. Open returns one backed by the system keyring;
+// NewMemoryStore one that keeps them in memory, for tests.
+type Store interface {
+	// Get returns the secret at key, or an error wrapping os.ErrNotExist
+	Get(key string) (string, error)
+	// Set writes the secret at key
+	Set(key, value string) error
+	// Delete removes the secret at key; a missing key is no error
+	Delete(key string) error
+	// Keys lists the keys of the stored secrets
+	Keys() ([]string, error)
+	// Backend names where the secrets are kept
+	Backend() string
+}
+
+// keyringStore wraps access to the configured keyring backend.
+type keyringStore struct {
+	kr      keyring.Keyring
+	backend keyring.BackendType
 }
 
 type openOptions struct {
@@ -134,6 +151,16 @@ func WithPassphrase(pass string) Option {
 	}
 }
 
+// WithBackend restricts the store to the comma-separated backends in raw,
+// overriding KEYRING_BACKEND. Naming the file backend permits it.
+func WithBackend(raw string) Option {
+	return func(o *openOptions) {
+		if strings.TrimSpace(raw) != "" {
+			o.allowedBackends = parseBackendList(raw, true)
+		}
+	}
+}
+
 // WithFileDir sets the directory for the encrypted file backend.
 func WithFileDir(dir string) Option {
 	return func(o *openOptions) {
@@ -144,7 +171,7 @@ func WithFileDir(dir string) Option {
 }
 
 // Open initialises the keyring-backed secret store.
-func Open(opts ...Option) (*Store, error) {
+func Open(opts ...Option) (Store, error) {
 	cfg := keyring.Config{
 		ServiceName: serviceName,
 	}
@@ -173,7 +200,7 @@ func Open(opts ...Option) (*Store, error) {
 		}
 	}
 
-	kr, err := openKeyringWithTimeout(cfg)
+	kr, backend, err := openKeyringWithTimeout(cfg)
 	if err != nil {
 		if errors.Is(err, ErrKeyringTimeout) {
 			return nil, fmt.Errorf("open keyring: %w; %s", err, timeoutHint())
@@ -184,21 +211,73 @@ func Open(opts ...Option) (*Store, error) {
 		return nil, fmt.Errorf("open keyring: %w", err)
 	}
 
-	return &Store{kr: kr}, nil
+	return &keyringStore{kr: kr, backend: backend}, nil
+}
+
+// Backend returns the name of the keyring backend the store uses.
+func (s *keyringStore) Backend() string {
+	if s == nil {
+		return ""
+	}
+	return string(s.backend)
+}
+
+// backendNames are the names parseBackendList accepts
+var backendNames = []string{"keychain", "wincred", "secret-service", "kwallet", "keyctl", "pass", "file"}
+
+// ValidateBackends reports an error if the comma-separated list raw names an
+// unknown backend.
+func ValidateBackends(raw string) error {
+	for _, part := range strings.Split(raw, ",") {
+		name := strings.TrimSpace(strings.ToLower(part))
+		if name == "secretservice" || slices.Contains(backendNames, name) {
+			continue
+		}
+		return fmt.Errorf("unknown keyring backend %q (valid: %s)", strings.TrimSpace(part), strings.Join(backendNames, ", "))
+	}
+	return nil
+}
+
+// Backends returns the keyring backends Open tries with opts, in order of
+// preference, leaving out those this platform does not support.
+func Backends(opts ...Option) []string {
+	settings := openOptions{allowFile: envEnabled(os.Getenv(envAllowInsecure))}
+	for _, opt := range opts {
+		opt(&settings)
+	}
+
+	supported := make(map[keyring.BackendType]bool)
+	for _, b := range keyring.AvailableBackends() {
+		supported[b] = true
+	}
+	var names []string
+	for _, b := range resolveAllowedBackends(settings) {
+		if supported[b] {
+			names = append(names, string(b))
+		}
+	}
+	return names
+}
+
+// Headless reports whether keyring unlock prompts are likely impossible (SSH
+// without display forwarding, containers, CI), so GUI keyrings are skipped.
+func Headless() bool {
+	return isHeadless()
 }
 
 // openKeyringWithTimeout opens the keyring with a timeout to prevent hangs
 // when GUI-based keyrings try to show prompts in headless environments.
-func openKeyringWithTimeout(cfg keyring.Config) (keyring.Keyring, error) {
+func openKeyringWithTimeout(cfg keyring.Config) (keyring.Keyring, keyring.BackendType, error) {
 	type result struct {
-		kr  keyring.Keyring
-		err error
+		kr      keyring.Keyring
+		backend keyring.BackendType
+		err     error
 	}
 
 	ch := make(chan result, 1)
 	go func() {
-		kr, err := keyring.Open(cfg)
-		ch <- result{kr, err}
+		kr, backend, err := openKeyring(cfg)
+		ch <- result{kr, backend, err}
 	}()
 
 	ctx, cancel := context.WithTimeout(context.Background(), keyringTimeout())
@@ -206,14 +285,36 @@ func openKeyringWithTimeout(cfg keyring.Config) (keyring.Keyring, error) {
 
 	select {
 	case res := <-ch:
-		return res.kr, res.err
+		return res.kr, res.backend, res.err
 	case <-ctx.Done():
-		return nil, ErrKeyringTimeout
+		return nil, "", ErrKeyringTimeout
+	}
+}
+
+// openKeyring opens the first allowed backend that works, like keyring.Open,
+// but remembers which one it was.
+func openKeyring(cfg keyring.Config) (keyring.Keyring, keyring.BackendType, error) {
+	allowed := cfg.AllowedBackends
+	if len(allowed) == 0 {
+		allowed = keyring.AvailableBackends()
+	}
+	supported := keyring.AvailableBackends()
+
+	for _, backend := range allowed {
+		if !slices.Contains(supported, backend) {
+			continue
+		}
+		one := cfg
+		one.AllowedBackends = []keyring.BackendType{backend}
+		if kr, err := keyring.Open(one); err == nil {
+			return kr, backend, nil
+		}
 	}
+	return nil, "", keyring.ErrNoAvailImpl
 }
 
 // Set writes a secret value.
-func (s *Store) Set(key, value string) error {
+func (s *keyringStore) Set(key, value string) error {
 	if s == nil || s.kr == nil {
 		return errors.New("secret store not initialized")
 	}
@@ -228,7 +329,7 @@ func (s *Store) Set(key, value string) error {
 }
 
 // Get retrieves a secret value.
-func (s *Store) Get(key string) (string, error) {
+func (s *keyringStore) Get(key string) (string, error) {
 	if s == nil || s.kr == nil {
 		return "", errors.New("secret store not initialized")
 	}
@@ -249,8 +350,23 @@ func (s *Store) Get(key string) (string, error) {
 	return string(item.Data), nil
 }
 
+// Keys lists the keys of the stored secrets.
+func (s *keyringStore) Keys() ([]string, error) {
+	if s == nil || s.kr == nil {
+		return nil, errors.New("secret store not initialized")
+	}
+
+	var keys []string
+	err := s.withTimeout(func() error {
+		var keysErr error
+		keys, keysErr = s.kr.Keys()
+		return keysErr
+	})
+	return keys, err
+}
+
 // Delete removes a stored secret.
-func (s *Store) Delete(key string) error {
+func (s *keyringStore) Delete(key string) error {
 	if s == nil || s.kr == nil {
 		return errors.New("secret store not initialized")
 	}
@@ -265,7 +381,7 @@ func (s *Store) Delete(key string) error {
 }
 
 // withTimeout runs fn with a timeout to prevent keyring operations from hanging.
-func (s *Store) withTimeout(fn func() error) error {
+func (s *keyringStore) withTimeout(fn func() error) error {
 	ch := make(chan error, 1)
 	go func() {
 		ch <- fn()
@@ -43,3 +43,25 @@ func TestKeyringTimeout_EnvOverride(t *testing.T) {
 		t.Fatalf("got=%v want %v", got, 2*time.Minute)
 	}
 }
+
+func TestValidateBackends(t *testing.T) {
+	t.Parallel()
+
+	if err := ValidateBackends("pass, secret-service,file"); err != nil {
+		t.Fatalf("valid list: %v", err)
+	}
+	if err := ValidateBackends("pass,gnome"); err == nil {
+		t.Fatal("expected an error for an unknown backend")
+	}
+}
+
+func TestWithBackend(t *testing.T) {
+	t.Setenv(envBackend, "pass")
+	store, err := Open(WithBackend("file"), WithFileDir(t.TempDir()), WithPassphrase("test-passphrase"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if got := store.Backend(); got != "file" {
+		t.Fatalf("backend=%q want file", got)
+	}
+}
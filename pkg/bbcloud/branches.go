@@ -0,0 +1,230 @@
+package bbcloud
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// BranchListOptions selects and orders the branches returned by ListBranches
+type BranchListOptions struct {
+	// Query is a Bitbucket query language (BBQL) filter
+	Query string
+	// Sort is the field to order by, prefixed with "-" for descending;
+	// empty uses the API default
+	Sort string
+	// Limit caps the results; 0 returns every match (with pagination)
+	Limit int
+}
+
+// ListBranches lists a repository's branches; each branch's target is its
+// head commit, including author and date
+func (c *Client) ListBranches(ctx context.Context, repoSlug string, opts BranchListOptions) ([]Branch, error) {
+	if repoSlug == "" {
+		return nil, fmt.Errorf("repository slug is required")
+	}
+
+	pageLen := 100
+	if opts.Limit > 0 && opts.Limit < pageLen {
+		pageLen = opts.Limit
+	}
+
+	return collectPages[Branch](ctx, c, c.branchesPath(repoSlug, opts, 1, pageLen), "list branches", opts.Limit)
+}
+
+// BranchesPage fetches one page of a repository's branches (opts.Limit is
+// ignored). It returns the number of the next page, or 0 on the last page.
+func (c *Client) BranchesPage(ctx context.Context, repoSlug string, opts BranchListOptions, page, perPage int) ([]Branch, int, error) {
+	if repoSlug == "" {
+		return nil, 0, fmt.Errorf("repository slug is required")
+	}
+
+	var result BranchList
+	if err := c.Get(ctx, c.branchesPath(repoSlug, opts, page, perPage), &result); err != nil {
+		return nil, 0, fmt.Errorf("list branches (page %d): %w", page, err)
+	}
+
+	return result.Values, nextPage(result.PaginatedResponse, page), nil
+}
+
+// branchesPath is the path of one page of a repository's branches
+func (c *Client) branchesPath(repoSlug string, opts BranchListOptions, page, perPage int) string {
+	params := url.Values{}
+	params.Set("pagelen", strconv.Itoa(perPage))
+	params.Set("page", strconv.Itoa(page))
+	if opts.Query != "" {
+		params.Set("q", opts.Query)
+	}
+	if opts.Sort != "" {
+		params.Set("sort", opts.Sort)
+	}
+
+	return fmt.Sprintf("/repositories/%s/%s/refs/branches?%s",
+		url.PathEscape(c.workspace),
+		url.PathEscape(repoSlug),
+		params.Encode())
+}
+
+// CountCommits counts the commits reachable from include but not from
+// exclude (git rev-list --count exclude..include), stopping at limit. It
+// reports whether the count was capped; limit 0 counts every commit.
+func (c *Client) CountCommits(ctx context.Context, repoSlug, include, exclude string, limit int) (int, bool, error) {
+	if repoSlug == "" {
+		return 0, false, fmt.Errorf("repository slug is required")
+	}
+
+	params := url.Values{}
+	params.Set("include", include)
+	params.Set("exclude", exclude)
+	params.Set("pagelen", "100")
+	// Only the hashes are needed to count
+	params.Set("fields", "next,values.hash")
+
+	path := fmt.Sprintf("/repositories/%s/%s/commits?%s",
+		url.PathEscape(c.workspace),
+		url.PathEscape(repoSlug),
+		params.Encode())
+
+	// Capping needs to know whether more pages follow, so the next links are
+	// followed here rather than through eachPage
+	count := 0
+	for page := 1; ; page++ {
+		var result CommitList
+		if err := c.Get(ctx, path, &result); err != nil {
+			return 0, false, fmt.Errorf("count commits (page %d): %w", page, err)
+		}
+
+		count += len(result.Values)
+		if result.Next == "" {
+			break
+		}
+		if limit > 0 && count >= limit {
+			return limit, true, nil
+		}
+
+		path = result.Next
+	}
+
+	return count, false, nil
+}
+
+// CommitListOptions selects the commits returned by ListCommits
+type CommitListOptions struct {
+	// Include and Exclude name branches, tags or hashes: the commits
+	// reachable from Include but not from Exclude are listed (git log
+	// Exclude..Include); without Include every branch is listed.
+	Include string
+	Exclude string
+	// Since stops the listing at the first commit older than it
+	Since time.Time
+	// Limit caps the results; 0 returns every match (with pagination)
+	Limit int
+}
+
+// ListCommits lists commits newest first
+func (c *Client) ListCommits(ctx context.Context, repoSlug string, opts CommitListOptions) ([]CommitReference, error) {
+	if repoSlug == "" {
+		return nil, fmt.Errorf("repository slug is required")
+	}
+
+	params := url.Values{}
+	if opts.Include != "" {
+		params.Set("include", opts.Include)
+	}
+	if opts.Exclude != "" {
+		params.Set("exclude", opts.Exclude)
+	}
+	params.Set("pagelen", "100")
+
+	path := fmt.Sprintf("/repositories/%s/%s/commits?%s",
+		url.PathEscape(c.workspace),
+		url.PathEscape(repoSlug),
+		params.Encode())
+
+	var commits []CommitReference
+	err := eachPage(ctx, c, path, "list commits", func(values []CommitReference) bool {
+		for _, commit := range values {
+			if !opts.Since.IsZero() && commit.Date.Before(opts.Since) {
+				return false
+			}
+			commits = append(commits, commit)
+			if opts.Limit > 0 && len(commits) == opts.Limit {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return commits, nil
+}
+
+// GetTag retrieves a tag by name
+func (c *Client) GetTag(ctx context.Context, repoSlug, name string) (*Tag, error) {
+	if repoSlug == "" {
+		return nil, fmt.Errorf("repository slug is required")
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s/refs/tags/%s",
+		url.PathEscape(c.workspace),
+		url.PathEscape(repoSlug),
+		url.PathEscape(name))
+
+	var tag Tag
+	if err := c.Get(ctx, path, &tag); err != nil {
+		return nil, fmt.Errorf("get tag %q: %w", name, err)
+	}
+
+	return &tag, nil
+}
+
+// GetBranch retrieves a branch by name; its target is the head commit
+func (c *Client) GetBranch(ctx context.Context, repoSlug, name string) (*Branch, error) {
+	if repoSlug == "" {
+		return nil, fmt.Errorf("repository slug is required")
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s/refs/branches/%s",
+		url.PathEscape(c.workspace),
+		url.PathEscape(repoSlug),
+		url.PathEscape(name))
+
+	var branch Branch
+	if err := c.Get(ctx, path, &branch); err != nil {
+		return nil, fmt.Errorf("get branch %q: %w", name, err)
+	}
+
+	return &branch, nil
+}
+
+// CreateTag tags the commit hash; a message makes it an annotated tag
+func (c *Client) CreateTag(ctx context.Context, repoSlug, name, hash, message string) (*Tag, error) {
+	if repoSlug == "" {
+		return nil, fmt.Errorf("repository slug is required")
+	}
+	if name == "" || hash == "" {
+		return nil, fmt.Errorf("tag name and commit are required")
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s/refs/tags",
+		url.PathEscape(c.workspace),
+		url.PathEscape(repoSlug))
+
+	body := map[string]any{
+		"name":   name,
+		"target": map[string]string{"hash": hash},
+	}
+	if message != "" {
+		body["message"] = message
+	}
+
+	var tag Tag
+	if err := c.Post(ctx, path, body, &tag); err != nil {
+		return nil, fmt.Errorf("create tag %q: %w", name, err)
+	}
+
+	return &tag, nil
+}
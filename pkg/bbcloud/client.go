@@ -3,7 +3,9 @@ package bbcloud
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/ghoseb/bb/pkg/httpx"
@@ -15,12 +17,24 @@ const (
 	
 	// DefaultUserAgent is the default User-Agent header value
 	DefaultUserAgent = "bb-cli"
+
+	// AuthBasic authenticates with username and App Password (the default)
+	AuthBasic = "basic"
+
+	// AuthBearer authenticates with an access token sent as a Bearer token
+	AuthBearer = "bearer"
 )
 
 // Client provides access to the Bitbucket Cloud API
 type Client struct {
 	client    *httpx.Client
 	workspace string
+
+	// reuse is set when responses fetched moments ago may be served from the
+	// response cache (see prcache.go)
+	reuse      bool
+	mu         sync.Mutex
+	prVersions map[string]string // updated_on of the PRs fetched, by repo/id
 }
 
 // Options configures a Bitbucket Cloud client
@@ -33,8 +47,12 @@ type Options struct {
 	
 	// Token is the Bitbucket App Password or API token
 	Token string
+
+	// AuthType selects how Token is sent: AuthBasic (default) or AuthBearer
+	AuthType string
 	
-	// Workspace is the Bitbucket workspace slug
+	// Workspace is the Bitbucket workspace slug; it may be empty when only
+	// account-level calls (CurrentUser, ListWorkspaces) are made
 	Workspace string
 	
 	// UserAgent is the User-Agent header value (defaults to DefaultUserAgent)
@@ -45,20 +63,51 @@ type Options struct {
 	
 	// Debug enables debug logging
 	Debug bool
+
+	// Transport tunes connection reuse (see httpx.TransportOptions)
+	Transport httpx.TransportOptions
+
+	// Cassette, when set, records or replays the client's HTTP exchanges
+	Cassette *httpx.Cassette
+
+	// DryRun, when set, receives the requests that would change something
+	// instead of them being sent (see httpx.Options.DryRun)
+	DryRun io.Writer
+
+	// Audit, when set, logs the client's successful mutations
+	Audit *httpx.AuditLog
+
+	// ResponseCache, when set, serves GET requests offline or when the
+	// network fails (see httpx.ResponseCache), and PR data fetched moments
+	// ago by an earlier command
+	ResponseCache *httpx.ResponseCache
+
+	// RateLimitWait and RateLimitProgress bound and report waits for the
+	// rate limit to reset (see httpx.Options)
+	RateLimitWait     time.Duration
+	RateLimitProgress io.Writer
+
+	// RateLimitPacing spaces requests out before the rate limit runs out
+	// (see httpx.Options.Pace)
+	RateLimitPacing bool
+
+	// TotalTimeout is the wall-clock budget of all the client's requests
+	// (see httpx.Options.TotalTimeout); 0 means none
+	TotalTimeout time.Duration
 }
 
 // New creates a new Bitbucket Cloud API client
 func New(opts Options) (*Client, error) {
-	if opts.Username == "" {
+	bearer := opts.AuthType == AuthBearer
+	if opts.AuthType != "" && opts.AuthType != AuthBasic && !bearer {
+		return nil, fmt.Errorf("unknown auth type %q", opts.AuthType)
+	}
+	if opts.Username == "" && !bearer {
 		return nil, fmt.Errorf("username is required")
 	}
 	if opts.Token == "" {
 		return nil, fmt.Errorf("token is required")
 	}
-	if opts.Workspace == "" {
-		return nil, fmt.Errorf("workspace is required")
-	}
-	
 	baseURL := opts.BaseURL
 	if baseURL == "" {
 		baseURL = DefaultBaseURL
@@ -81,7 +130,7 @@ func New(opts Options) (*Client, error) {
 		MaxBackoff:     30 * time.Second,
 	}
 	
-	httpClient, err := httpx.New(httpx.Options{
+	httpOpts := httpx.Options{
 		BaseURL:   baseURL,
 		Username:  opts.Username,
 		Password:  opts.Token, // Bitbucket uses Basic Auth with username:app_password
@@ -89,7 +138,23 @@ func New(opts Options) (*Client, error) {
 		Timeout:   timeout,
 		Retry:     retryPolicy,
 		Debug:     opts.Debug,
-	})
+		Transport: opts.Transport,
+		Cassette:  opts.Cassette,
+		DryRun:    opts.DryRun,
+		Audit:     opts.Audit,
+
+		ResponseCache: opts.ResponseCache,
+
+		RateLimitWait:     opts.RateLimitWait,
+		RateLimitProgress: opts.RateLimitProgress,
+		Pace:              opts.RateLimitPacing,
+		TotalTimeout:      opts.TotalTimeout,
+	}
+	if bearer {
+		httpOpts.Username, httpOpts.Password, httpOpts.BearerToken = "", "", opts.Token
+	}
+
+	httpClient, err := httpx.New(httpOpts)
 	if err != nil {
 		return nil, fmt.Errorf("create HTTP client: %w", err)
 	}
@@ -97,6 +162,9 @@ func New(opts Options) (*Client, error) {
 	return &Client{
 		client:    httpClient,
 		workspace: opts.Workspace,
+		// A recording must capture every exchange
+		reuse:      opts.ResponseCache != nil && opts.Cassette == nil,
+		prVersions: make(map[string]string),
 	}, nil
 }
 
@@ -105,6 +173,12 @@ func (c *Client) HTTP() *httpx.Client {
 	return c.client
 }
 
+// RateLimit returns the rate limit reported by the latest API response; it is
+// zero until a response carried rate limit headers
+func (c *Client) RateLimit() httpx.RateLimit {
+	return c.client.RateLimitState()
+}
+
 // Workspace returns the configured workspace slug
 func (c *Client) Workspace() string {
 	return c.workspace
@@ -117,7 +191,18 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, body any)
 
 // Do executes an HTTP request and decodes the response into v
 func (c *Client) Do(req *http.Request, v any) error {
-	return c.client.Do(req, v)
+	return c.do(req, v)
+}
+
+// do sends req through the HTTP client, decoding a failure into *Error
+func (c *Client) do(req *http.Request, v any) error {
+	return apiError(c.client.Do(req, v))
+}
+
+// doWithHeaders is do for callers that need the response headers
+func (c *Client) doWithHeaders(req *http.Request, v any) (http.Header, error) {
+	header, err := c.client.DoWithHeaders(req, v)
+	return header, apiError(err)
 }
 
 // Get is a convenience method for GET requests
@@ -126,7 +211,7 @@ func (c *Client) Get(ctx context.Context, path string, v any) error {
 	if err != nil {
 		return err
 	}
-	return c.client.Do(req, v)
+	return c.do(req, v)
 }
 
 // GetWithHeaders is a convenience method for GET requests that need response headers
@@ -135,7 +220,7 @@ func (c *Client) GetWithHeaders(ctx context.Context, path string, v any) (http.H
 	if err != nil {
 		return nil, err
 	}
-	return c.client.DoWithHeaders(req, v)
+	return c.doWithHeaders(req, v)
 }
 
 // Post is a convenience method for POST requests
@@ -144,7 +229,7 @@ func (c *Client) Post(ctx context.Context, path string, body any, v any) error {
 	if err != nil {
 		return err
 	}
-	return c.client.Do(req, v)
+	return c.do(req, v)
 }
 
 // Put is a convenience method for PUT requests
@@ -153,7 +238,7 @@ func (c *Client) Put(ctx context.Context, path string, body any, v any) error {
 	if err != nil {
 		return err
 	}
-	return c.client.Do(req, v)
+	return c.do(req, v)
 }
 
 // Delete is a convenience method for DELETE requests
@@ -162,5 +247,5 @@ func (c *Client) Delete(ctx context.Context, path string) error {
 	if err != nil {
 		return err
 	}
-	return c.client.Do(req, nil)
+	return c.do(req, nil)
 }
@@ -4,42 +4,84 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strconv"
+
+	"golang.org/x/sync/errgroup"
 )
 
+// commentPageConcurrency bounds the comment pages fetched at once
+const commentPageConcurrency = 4
+
 // ListPRComments retrieves all comments for a pull request
 // Returns both general and inline comments
+//
+// The first page reports the total comment count, so the remaining pages are
+// fetched concurrently by number, at most commentPageConcurrency at a time,
+// and joined in page order.
 func (c *Client) ListPRComments(ctx context.Context, repoSlug string, prID int) ([]Comment, error) {
 	if err := c.validatePRArgs(repoSlug, prID); err != nil {
 		return nil, err
 	}
-	
+
 	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%d/comments",
 		url.PathEscape(c.workspace),
 		url.PathEscape(repoSlug),
 		prID)
-	
-	var allComments []Comment
-	page := 1
-	
-	for {
-		pagedPath := fmt.Sprintf("%s?pagelen=100&page=%d", path, page)
-		
+
+	ctx = c.freshPRData(ctx, repoSlug, prID)
+	getPage := func(ctx context.Context, page int) (CommentList, error) {
 		var result CommentList
-		err := c.Get(ctx, pagedPath, &result)
-		if err != nil {
-			return nil, fmt.Errorf("list PR comments (page %d): %w", page, err)
+		params := url.Values{}
+		params.Set("pagelen", "100")
+		params.Set("page", strconv.Itoa(page))
+		params.Set("fields", commentListFields)
+		if err := c.Get(ctx, path+"?"+params.Encode(), &result); err != nil {
+			return result, fmt.Errorf("list PR comments (page %d): %w", page, err)
 		}
-		
-		allComments = append(allComments, result.Values...)
-		
-		// Check if there's a next page
-		if result.Next == "" {
-			break
+		return result, nil
+	}
+
+	first, err := getPage(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+	if first.Next == "" {
+		return first.Values, nil
+	}
+
+	// Without a size the page count is unknown, as with cursor pagination;
+	// follow the next links in turn
+	if first.Size == 0 || len(first.Values) == 0 {
+		rest, err := collectPages[Comment](ctx, c, first.Next, "list PR comments", 0)
+		if err != nil {
+			return nil, err
 		}
-		
-		page++
+		return append(first.Values, rest...), nil
+	}
+
+	pageLen := len(first.Values)
+	pages := make([][]Comment, (first.Size+pageLen-1)/pageLen)
+	pages[0] = first.Values
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(commentPageConcurrency)
+	for i := 1; i < len(pages); i++ {
+		g.Go(func() error {
+			result, err := getPage(gctx, i+1)
+			if err != nil {
+				return err
+			}
+			pages[i] = result.Values
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	allComments := make([]Comment, 0, first.Size)
+	for _, values := range pages {
+		allComments = append(allComments, values...)
 	}
-	
 	return allComments, nil
 }
 
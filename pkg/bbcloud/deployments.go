@@ -0,0 +1,86 @@
+package bbcloud
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+)
+
+// Environment is a deployment environment of a repository, a target of
+// Pipelines deployment steps
+type Environment struct {
+	UUID            string                  `json:"uuid"`
+	Name            string                  `json:"name"`
+	Slug            string                  `json:"slug,omitempty"`
+	Rank            int                     `json:"rank"`
+	Hidden          bool                    `json:"hidden"`
+	EnvironmentType *EnvironmentType        `json:"environment_type,omitempty"`
+	Lock            *EnvironmentLock        `json:"lock,omitempty"`
+	Restrictions    *EnvironmentRestriction `json:"restrictions,omitempty"`
+}
+
+// EnvironmentType is the stage of an environment: Test, Staging or
+// Production, ranked in that order
+type EnvironmentType struct {
+	Name string `json:"name"`
+	Rank int    `json:"rank"`
+}
+
+// EnvironmentLock says whether a deployment holds an environment; only one
+// deployment runs in an environment at a time
+type EnvironmentLock struct {
+	// Type is "deployment_environment_lock_open" or
+	// "deployment_environment_lock_closed"
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// EnvironmentRestriction limits who may deploy to an environment
+type EnvironmentRestriction struct {
+	AdminOnly bool `json:"admin_only"`
+}
+
+// TypeName returns the environment's type (Test, Staging or Production), or
+// "" if it has none
+func (e *Environment) TypeName() string {
+	if e.EnvironmentType == nil {
+		return ""
+	}
+	return e.EnvironmentType.Name
+}
+
+// Locked reports whether a deployment currently holds the environment
+func (e *Environment) Locked() bool {
+	return e.Lock != nil && (e.Lock.Type == "deployment_environment_lock_closed" || e.Lock.Name == "CLOSED")
+}
+
+// ListEnvironments lists a repository's deployment environments in the
+// order Bitbucket shows them: by type (Test, Staging, Production), then rank
+func (c *Client) ListEnvironments(ctx context.Context, repoSlug string) ([]Environment, error) {
+	if repoSlug == "" {
+		return nil, fmt.Errorf("repository slug is required")
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s/environments/?pagelen=100",
+		url.PathEscape(c.workspace),
+		url.PathEscape(repoSlug))
+	envs, err := collectPages[Environment](ctx, c, path, "list environments", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	typeRank := func(e Environment) int {
+		if e.EnvironmentType == nil {
+			return 0
+		}
+		return e.EnvironmentType.Rank
+	}
+	sort.SliceStable(envs, func(i, j int) bool {
+		if ti, tj := typeRank(envs[i]), typeRank(envs[j]); ti != tj {
+			return ti < tj
+		}
+		return envs[i].Rank < envs[j].Rank
+	})
+	return envs, nil
+}
@@ -0,0 +1,36 @@
+package bbcloud
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/ghoseb/bb/pkg/httpx"
+)
+
+// UploadDownload adds a file to a repository's downloads, replacing any file
+// of the same name. The content is buffered in memory.
+func (c *Client) UploadDownload(ctx context.Context, repoSlug, name string, content io.Reader) error {
+	if repoSlug == "" {
+		return fmt.Errorf("repository slug is required")
+	}
+	if name == "" {
+		return fmt.Errorf("file name is required")
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s/downloads",
+		url.PathEscape(c.workspace),
+		url.PathEscape(repoSlug))
+
+	req, err := c.client.NewMultipartRequest(ctx, "POST", path, []httpx.MultipartFile{
+		{FieldName: "files", FileName: name, Reader: content},
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.do(req, nil); err != nil {
+		return fmt.Errorf("upload %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package bbcloud
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ghoseb/bb/pkg/httpx"
+)
+
+// Errors a failed request matches with errors.Is, by the response status
+var (
+	ErrUnauthorized = errors.New("unauthorized") // 401: missing or invalid credentials
+	ErrForbidden    = errors.New("forbidden")    // 403: the credentials lack a permission or scope
+	ErrNotFound     = errors.New("not found")    // 404: no such resource, or no access to it
+	ErrConflict     = errors.New("conflict")     // 409: the resource changed or is in the wrong state
+	ErrRateLimited  = errors.New("rate limited") // 429: beyond the client's rate limit wait budget
+)
+
+// Error represents a Bitbucket API error response. Every failed request the
+// client makes returns one (wrapping the httpx error it came from), so
+// callers can tell failures apart with errors.Is against the sentinels above
+// and read the API's message with errors.As.
+type Error struct {
+	Type      string      `json:"type"`
+	ErrorInfo ErrorDetail `json:"error"`
+	RequestID string      `json:"request_id,omitempty"`
+
+	// StatusCode is the HTTP status of the response
+	StatusCode int `json:"-"`
+
+	err error
+}
+
+// ErrorDetail contains error details
+type ErrorDetail struct {
+	Message string                 `json:"message"`
+	Detail  string                 `json:"detail,omitempty"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+}
+
+func (e *Error) Error() string {
+	msg := e.ErrorInfo.Message
+	if msg == "" {
+		if e.err != nil {
+			return e.err.Error()
+		}
+		return http.StatusText(e.StatusCode)
+	}
+	if e.ErrorInfo.Detail != "" {
+		msg += ": " + e.ErrorInfo.Detail
+	}
+	var statusErr *httpx.StatusError
+	if errors.As(e.err, &statusErr) {
+		return statusErr.Status + ": " + msg
+	}
+	return msg
+}
+
+// Unwrap returns the httpx error the API error was decoded from
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether target is the sentinel error for e's status
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// apiError converts the error of a failed request to *Error: a non-2xx
+// response has its Bitbucket error document decoded, and running out of rate
+// limit wait budget counts as a 429. Other errors (network, dry run, offline)
+// are returned as they are.
+func apiError(err error) error {
+	var statusErr *httpx.StatusError
+	if errors.As(err, &statusErr) {
+		e := &Error{StatusCode: statusErr.StatusCode, err: err}
+		// Bodies that are not an error document leave the message to err
+		_ = json.Unmarshal(statusErr.Body, e)
+		return e
+	}
+	var rateErr *httpx.RateLimitError
+	if errors.As(err, &rateErr) {
+		return &Error{StatusCode: http.StatusTooManyRequests, err: err}
+	}
+	return err
+}
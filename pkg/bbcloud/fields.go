@@ -0,0 +1,65 @@
+package bbcloud
+
+import "strings"
+
+// Field selections for the hot-path list calls. Bitbucket returns every
+// field, links and rendered HTML included, unless a fields= parameter names
+// the ones wanted; these name what the types in this package map and bb
+// reads, so pages transfer and decode in a fraction of the time.
+var (
+	userFields = []string{"uuid", "username", "display_name", "account_id", "nickname", "type"}
+
+	pageFields = []string{"size", "page", "pagelen", "next"}
+
+	pullRequestListFields = joinFields(pageFields, withPrefix("values.",
+		[]string{"id", "title", "description", "state", "type", "created_on", "updated_on",
+			"close_source_branch", "comment_count", "task_count", "merge_commit.hash", "links.html"},
+		withPrefix("author.", userFields),
+		withPrefix("source.", branchFields()),
+		withPrefix("destination.", branchFields()),
+		withPrefix("reviewers.", userFields),
+		withPrefix("participants.", []string{"role", "approved", "state", "participated_on"}),
+		withPrefix("participants.user.", userFields),
+	))
+
+	repositoryListFields = joinFields(pageFields, withPrefix("values.",
+		[]string{"uuid", "name", "slug", "full_name", "is_private", "description", "created_on",
+			"updated_on", "type", "scm", "language", "size", "mainbranch.name", "links.html",
+			"links.clone", "project.uuid", "project.key", "project.name", "project.type"},
+		withPrefix("owner.", userFields),
+	))
+
+	commentListFields = joinFields(pageFields, withPrefix("values.",
+		[]string{"id", "type", "created_on", "updated_on", "deleted", "content.raw", "parent.id",
+			"inline.path", "inline.from", "inline.to", "inline.start_to", "links.html",
+			"resolution.type", "resolution.created_on"},
+		withPrefix("user.", userFields),
+		withPrefix("resolution.user.", userFields),
+	))
+)
+
+// branchFields selects a PR source or destination
+func branchFields() []string {
+	return []string{"branch.name", "commit.hash", "repository.uuid", "repository.name",
+		"repository.slug", "repository.full_name", "repository.type"}
+}
+
+// withPrefix prefixes every field of the groups with prefix
+func withPrefix(prefix string, groups ...[]string) []string {
+	var fields []string
+	for _, group := range groups {
+		for _, field := range group {
+			fields = append(fields, prefix+field)
+		}
+	}
+	return fields
+}
+
+// joinFields joins field groups into a fields= parameter value
+func joinFields(groups ...[]string) string {
+	var fields []string
+	for _, group := range groups {
+		fields = append(fields, group...)
+	}
+	return strings.Join(fields, ",")
+}
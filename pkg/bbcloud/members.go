@@ -0,0 +1,77 @@
+package bbcloud
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+)
+
+// ListWorkspaceMembers retrieves all users who are members of the client's workspace
+func (c *Client) ListWorkspaceMembers(ctx context.Context) ([]User, error) {
+	if c.workspace == "" {
+		return nil, fmt.Errorf("workspace is required")
+	}
+
+	path := fmt.Sprintf("/workspaces/%s/members?pagelen=100", url.PathEscape(c.workspace))
+
+	var members []User
+	err := eachPage(ctx, c, path, "list workspace members", func(values []WorkspaceMembership) bool {
+		for _, m := range values {
+			if m.User != nil {
+				members = append(members, *m.User)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// ListWorkspaces retrieves the workspaces the current user belongs to, with
+// the user's permission in each, sorted by slug. It does not need the
+// client's workspace to be set.
+func (c *Client) ListWorkspaces(ctx context.Context) ([]WorkspacePermission, error) {
+	var workspaces []WorkspacePermission
+	err := eachPage(ctx, c, workspacesPath(1, 100), "list workspaces", func(values []WorkspacePermission) bool {
+		workspaces = append(workspaces, withWorkspace(values)...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(workspaces, func(i, j int) bool {
+		return workspaces[i].Workspace.Slug < workspaces[j].Workspace.Slug
+	})
+	return workspaces, nil
+}
+
+// WorkspacesPage fetches one page of the current user's workspaces, ordered
+// by slug. It returns the number of the next page, or 0 on the last page.
+func (c *Client) WorkspacesPage(ctx context.Context, page, perPage int) ([]WorkspacePermission, int, error) {
+	var result WorkspacePermissionList
+	if err := c.Get(ctx, workspacesPath(page, perPage), &result); err != nil {
+		return nil, 0, fmt.Errorf("list workspaces (page %d): %w", page, err)
+	}
+
+	return withWorkspace(result.Values), nextPage(result.PaginatedResponse, page), nil
+}
+
+// workspacesPath is the path of one page of the current user's workspaces
+func workspacesPath(page, perPage int) string {
+	return fmt.Sprintf("/user/permissions/workspaces?pagelen=%d&page=%d&sort=workspace.slug", perPage, page)
+}
+
+// withWorkspace drops the permissions that carry no workspace
+func withWorkspace(permissions []WorkspacePermission) []WorkspacePermission {
+	workspaces := make([]WorkspacePermission, 0, len(permissions))
+	for _, p := range permissions {
+		if p.Workspace != nil {
+			workspaces = append(workspaces, p)
+		}
+	}
+	return workspaces
+}
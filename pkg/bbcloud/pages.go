@@ -0,0 +1,44 @@
+package bbcloud
+
+import (
+	"context"
+	"fmt"
+)
+
+// eachPage fetches path and then every later page by following the next URL
+// each response links to, so cursor-paginated endpoints work the same as
+// numbered ones. visit receives the values of each page in turn; returning
+// false stops the walk. what names the listing in errors.
+func eachPage[T any](ctx context.Context, c *Client, path, what string, visit func([]T) bool) error {
+	for n := 1; path != ""; n++ {
+		var result struct {
+			PaginatedResponse
+			Values []T `json:"values"`
+		}
+		if err := c.Get(ctx, path, &result); err != nil {
+			return fmt.Errorf("%s (page %d): %w", what, n, err)
+		}
+		if !visit(result.Values) {
+			return nil
+		}
+		path = result.Next
+	}
+	return nil
+}
+
+// collectPages returns the values of every page from path on, or just the
+// first limit of them when limit > 0
+func collectPages[T any](ctx context.Context, c *Client, path, what string, limit int) ([]T, error) {
+	var all []T
+	err := eachPage(ctx, c, path, what, func(values []T) bool {
+		all = append(all, values...)
+		return limit <= 0 || len(all) < limit
+	})
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(all) > limit {
+		all = all[:limit]
+	}
+	return all, nil
+}
@@ -35,29 +35,7 @@ func (c *Client) GetPRPipelines(ctx context.Context, repoSlug string, prID int)
 		url.PathEscape(repoSlug),
 		commitHash)
 	
-	var statuses []CommitStatus
-	page := 1
-	
-	for {
-		pagedPath := fmt.Sprintf("%s?pagelen=100&page=%d", path, page)
-		
-		var result CommitStatusList
-		err := c.Get(ctx, pagedPath, &result)
-		if err != nil {
-			return nil, fmt.Errorf("get commit statuses (page %d): %w", page, err)
-		}
-		
-		statuses = append(statuses, result.Values...)
-		
-		// Check if there's a next page
-		if result.Next == "" {
-			break
-		}
-		
-		page++
-	}
-	
-	return statuses, nil
+	return collectPages[CommitStatus](ctx, c, path+"?pagelen=100", "get commit statuses", 0)
 }
 
 // GetPipelineStatus retrieves the status of a specific pipeline by UUID
@@ -89,49 +67,21 @@ func (c *Client) GetPipelineStatus(ctx context.Context, repoSlug string, pipelin
 	return &pipeline, nil
 }
 
-// ListPipelines lists all pipelines for a repository
+// ListPipelines lists pipelines for a repository, newest first
 func (c *Client) ListPipelines(ctx context.Context, repoSlug string, limit int) ([]Pipeline, error) {
 	if repoSlug == "" {
 		return nil, fmt.Errorf("repository slug is required")
 	}
 	
-	var allPipelines []Pipeline
-	page := 1
 	pageLen := 50 // Reasonable default for pipelines
-	
 	if limit > 0 && limit < pageLen {
 		pageLen = limit
 	}
 	
-	for {
-		path := fmt.Sprintf("/repositories/%s/%s/pipelines/?pagelen=%d&page=%d",
-			url.PathEscape(c.workspace),
-			url.PathEscape(repoSlug),
-			pageLen,
-			page)
-		
-		var result PipelineList
-		err := c.Get(ctx, path, &result)
-		if err != nil {
-			return nil, fmt.Errorf("list pipelines (page %d): %w", page, err)
-		}
-		
-		allPipelines = append(allPipelines, result.Values...)
-		
-		// Check if we've hit the limit or there's no more data
-		if limit > 0 && len(allPipelines) >= limit {
-			if len(allPipelines) > limit {
-				allPipelines = allPipelines[:limit]
-			}
-			break
-		}
-		
-		if result.Next == "" {
-			break
-		}
-		
-		page++
-	}
+	path := fmt.Sprintf("/repositories/%s/%s/pipelines/?sort=-created_on&pagelen=%d",
+		url.PathEscape(c.workspace),
+		url.PathEscape(repoSlug),
+		pageLen)
 	
-	return allPipelines, nil
+	return collectPages[Pipeline](ctx, c, path, "list pipelines", limit)
 }
@@ -0,0 +1,56 @@
+package bbcloud
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ghoseb/bb/pkg/httpx"
+)
+
+// Agents often run several commands on a PR in quick succession, each
+// fetching the same PR, diffstat and comments. With a response cache, a PR
+// fetched less than prFreshFor ago is reused, and so are its diffstat and
+// comments as long as its updated_on has not moved, for up to prDataFreshFor.
+// Any change made through bb makes them stale.
+const (
+	prFreshFor     = 10 * time.Second
+	prDataFreshFor = 2 * time.Minute
+)
+
+// prKey identifies a PR of the client's workspace
+func (c *Client) prKey(repoSlug string, prID int) string {
+	return fmt.Sprintf("%s/%s/%d", c.workspace, repoSlug, prID)
+}
+
+// freshPR returns ctx allowing a PR fetched moments ago to be reused
+func (c *Client) freshPR(ctx context.Context) context.Context {
+	if !c.reuse {
+		return ctx
+	}
+	return httpx.WithFreshResponse(ctx, "", prFreshFor)
+}
+
+// rememberPR records the updated_on of a fetched PR, which versions its
+// cached diffstat and comments
+func (c *Client) rememberPR(repoSlug string, pr *PullRequest) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prVersions[c.prKey(repoSlug, pr.ID)] = pr.UpdatedOn.UTC().Format(time.RFC3339Nano)
+}
+
+// freshPRData returns ctx allowing the diffstat or comments of a PR to be
+// served from the cache while the PR is unchanged. Without the PR fetched
+// first its version is unknown and ctx is returned as is.
+func (c *Client) freshPRData(ctx context.Context, repoSlug string, prID int) context.Context {
+	if !c.reuse {
+		return ctx
+	}
+	c.mu.Lock()
+	version, ok := c.prVersions[c.prKey(repoSlug, prID)]
+	c.mu.Unlock()
+	if !ok {
+		return ctx
+	}
+	return httpx.WithFreshResponse(ctx, version, prDataFreshFor)
+}
@@ -0,0 +1,38 @@
+package bbcloud
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// ListProjects retrieves all projects in the client's workspace
+func (c *Client) ListProjects(ctx context.Context) ([]Project, error) {
+	if c.workspace == "" {
+		return nil, fmt.Errorf("workspace is required")
+	}
+
+	return collectPages[Project](ctx, c, c.projectsPath(1, 100), "list projects", 0)
+}
+
+// ProjectsPage fetches one page of the projects in the client's workspace,
+// ordered by key. It returns the number of the next page, or 0 on the last
+// page.
+func (c *Client) ProjectsPage(ctx context.Context, page, perPage int) ([]Project, int, error) {
+	if c.workspace == "" {
+		return nil, 0, fmt.Errorf("workspace is required")
+	}
+
+	var result ProjectList
+	if err := c.Get(ctx, c.projectsPath(page, perPage), &result); err != nil {
+		return nil, 0, fmt.Errorf("list projects (page %d): %w", page, err)
+	}
+
+	return result.Values, nextPage(result.PaginatedResponse, page), nil
+}
+
+// projectsPath is the path of one page of the workspace's projects
+func (c *Client) projectsPath(page, perPage int) string {
+	return fmt.Sprintf("/workspaces/%s/projects?pagelen=%d&page=%d&sort=key",
+		url.PathEscape(c.workspace), perPage, page)
+}
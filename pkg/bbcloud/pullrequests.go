@@ -5,6 +5,11 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
 )
 
 // GetPullRequest retrieves a single pull request by ID
@@ -19,38 +24,78 @@ func (c *Client) GetPullRequest(ctx context.Context, repoSlug string, prID int)
 		prID)
 	
 	var pr PullRequest
-	err := c.Get(ctx, path, &pr)
+	err := c.Get(c.freshPR(ctx), path, &pr)
 	if err != nil {
 		return nil, fmt.Errorf("get pull request %d: %w", prID, err)
 	}
+	c.rememberPR(repoSlug, &pr)
 	
 	return &pr, nil
 }
 
 // GetPRDiffStats retrieves the diffstat for a pull request
-// Returns file-level statistics (lines added/removed per file)
+// Returns file-level statistics (lines added/removed per file) of every file,
+// however many pages they span
 func (c *Client) GetPRDiffStats(ctx context.Context, repoSlug string, prID int) ([]FileStats, error) {
+	files, _, err := c.ListPRDiffStats(ctx, repoSlug, prID, 0)
+	return files, err
+}
+
+// ListPRDiffStats returns the diffstat of the first limit files of a pull
+// request (every file when limit <= 0) and how many files the whole diffstat
+// has. The count is the size the API reports; when a page reports none, the
+// remaining pages are read to count the files.
+func (c *Client) ListPRDiffStats(ctx context.Context, repoSlug string, prID, limit int) ([]FileStats, int, error) {
 	if err := c.validatePRArgs(repoSlug, prID); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	
+
 	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%d/diffstat",
 		url.PathEscape(c.workspace),
 		url.PathEscape(repoSlug),
 		prID)
-	
-	var result FileStatsList
-	err := c.Get(ctx, path, &result)
-	if err != nil {
-		return nil, fmt.Errorf("get PR diffstat: %w", err)
+
+	ctx = c.freshPRData(ctx, repoSlug, prID)
+	var files []FileStats
+	total := 0
+	for n := 1; path != ""; n++ {
+		var page FileStatsList
+		if err := c.Get(ctx, path, &page); err != nil {
+			return nil, 0, fmt.Errorf("get PR diffstat (page %d): %w", n, err)
+		}
+		files = append(files, page.Values...)
+		total = page.Size
+		if limit > 0 && len(files) >= limit && total > 0 {
+			break
+		}
+		path = page.Next
 	}
-	
-	return result.Values, nil
+	total = max(total, len(files))
+	if limit > 0 && len(files) > limit {
+		files = files[:limit]
+	}
+	return files, total, nil
+}
+
+// DiffOptions controls how the API renders unified diffs
+type DiffOptions struct {
+	// Context is the number of unchanged lines shown around each change;
+	// nil keeps the API default of 3
+	Context *int
+}
+
+// query returns the diff query parameters
+func (o DiffOptions) query() url.Values {
+	q := url.Values{}
+	if o.Context != nil {
+		q.Set("context", strconv.Itoa(*o.Context))
+	}
+	return q
 }
 
 // GetPRDiff retrieves the full unified diff for a pull request
 // Returns the diff as a string in unified diff format
-func (c *Client) GetPRDiff(ctx context.Context, repoSlug string, prID int) (string, error) {
+func (c *Client) GetPRDiff(ctx context.Context, repoSlug string, prID int, opts DiffOptions) (string, error) {
 	if repoSlug == "" {
 		return "", fmt.Errorf("repository slug is required")
 	}
@@ -62,6 +107,9 @@ func (c *Client) GetPRDiff(ctx context.Context, repoSlug string, prID int) (stri
 		url.PathEscape(c.workspace),
 		url.PathEscape(repoSlug),
 		prID)
+	if q := opts.query(); len(q) > 0 {
+		path += "?" + q.Encode()
+	}
 	
 	req, err := c.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
@@ -73,7 +121,7 @@ func (c *Client) GetPRDiff(ctx context.Context, repoSlug string, prID int) (stri
 	
 	// Use a bytes.Buffer as an io.Writer to capture the response
 	var buf bytes.Buffer
-	err = c.client.Do(req, &buf)
+	err = c.do(req, &buf)
 	if err != nil {
 		return "", fmt.Errorf("get PR diff: %w", err)
 	}
@@ -83,7 +131,7 @@ func (c *Client) GetPRDiff(ctx context.Context, repoSlug string, prID int) (stri
 
 // GetPRFileDiff retrieves the diff for a specific file in a pull request
 // filePath should be the path to the file relative to the repository root
-func (c *Client) GetPRFileDiff(ctx context.Context, repoSlug string, prID int, filePath string) (string, error) {
+func (c *Client) GetPRFileDiff(ctx context.Context, repoSlug string, prID int, filePath string, opts DiffOptions) (string, error) {
 	if repoSlug == "" {
 		return "", fmt.Errorf("repository slug is required")
 	}
@@ -95,11 +143,13 @@ func (c *Client) GetPRFileDiff(ctx context.Context, repoSlug string, prID int, f
 	}
 	
 	// Use the PR diff endpoint with path query parameter
-	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%d/diff?path=%s",
+	q := opts.query()
+	q.Set("path", filePath)
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%d/diff?%s",
 		url.PathEscape(c.workspace),
 		url.PathEscape(repoSlug),
 		prID,
-		url.QueryEscape(filePath))
+		q.Encode())
 	
 	req, err := c.client.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
@@ -111,7 +161,7 @@ func (c *Client) GetPRFileDiff(ctx context.Context, repoSlug string, prID int, f
 	
 	// Use a bytes.Buffer as an io.Writer to capture the response
 	var buf bytes.Buffer
-	err = c.client.Do(req, &buf)
+	err = c.do(req, &buf)
 	if err != nil {
 		return "", fmt.Errorf("get file diff: %w", err)
 	}
@@ -131,33 +181,7 @@ func (c *Client) GetPRActivity(ctx context.Context, repoSlug string, prID int) (
 		url.PathEscape(repoSlug),
 		prID)
 	
-	var activities []Activity
-	page := 1
-	
-	for {
-		pagedPath := fmt.Sprintf("%s?page=%d", path, page)
-		
-		var result struct {
-			PaginatedResponse
-			Values []Activity `json:"values"`
-		}
-		
-		err := c.Get(ctx, pagedPath, &result)
-		if err != nil {
-			return nil, fmt.Errorf("get PR activity (page %d): %w", page, err)
-		}
-		
-		activities = append(activities, result.Values...)
-		
-		// Check if there's a next page
-		if result.Next == "" {
-			break
-		}
-		
-		page++
-	}
-	
-	return activities, nil
+	return collectPages[Activity](ctx, c, path, "get PR activity", 0)
 }
 
 // GetPRStatuses retrieves the commit statuses (build checks) for a pull request
@@ -181,58 +205,176 @@ func (c *Client) GetPRStatuses(ctx context.Context, repoSlug string, prID int) (
 	return result.Values, nil
 }
 
+// PRListOptions selects and orders the pull requests returned by QueryPullRequests
+type PRListOptions struct {
+	// State is "OPEN", "MERGED", "DECLINED", or "" for the API default
+	State string
+	// Query is a Bitbucket query language (BBQL) filter
+	Query string
+	// Sort is the field to order by, prefixed with "-" for descending;
+	// empty sorts by most recently updated
+	Sort string
+	// Limit caps the results; 0 returns every match (with pagination)
+	Limit int
+}
+
 // ListPullRequests lists pull requests for a repository
 // state can be "OPEN", "MERGED", "DECLINED", or "" for all states
 func (c *Client) ListPullRequests(ctx context.Context, repoSlug string, state string, limit int) ([]PullRequest, error) {
+	return c.QueryPullRequests(ctx, repoSlug, PRListOptions{State: state, Limit: limit})
+}
+
+// SearchPullRequests lists pull requests matching a Bitbucket query language (BBQL) filter
+// If limit is 0, all matches are returned (with pagination)
+func (c *Client) SearchPullRequests(ctx context.Context, repoSlug string, query string, limit int) ([]PullRequest, error) {
+	return c.QueryPullRequests(ctx, repoSlug, PRListOptions{Query: query, Limit: limit})
+}
+
+// QueryPullRequests lists pull requests filtered and sorted by opts
+func (c *Client) QueryPullRequests(ctx context.Context, repoSlug string, opts PRListOptions) ([]PullRequest, error) {
 	if repoSlug == "" {
 		return nil, fmt.Errorf("repository slug is required")
 	}
-	
-	var allPRs []PullRequest
-	page := 1
+
 	pageLen := 50 // Reasonable default for PRs
-	
-	if limit > 0 && limit < pageLen {
-		pageLen = limit
+
+	if opts.Limit > 0 && opts.Limit < pageLen {
+		pageLen = opts.Limit
 	}
-	
-	for {
-		path := fmt.Sprintf("/repositories/%s/%s/pullrequests?pagelen=%d&page=%d&sort=-updated_on",
-			url.PathEscape(c.workspace),
-			url.PathEscape(repoSlug),
-			pageLen,
-			page)
-		
-		if state != "" {
-			path += "&state=" + url.QueryEscape(state)
-		}
-		
-		var result PullRequestList
-		err := c.Get(ctx, path, &result)
-		if err != nil {
-			return nil, fmt.Errorf("list pull requests (page %d): %w", page, err)
-		}
-		
-		allPRs = append(allPRs, result.Values...)
-		
-		// Check if we've hit the limit or there's no more data
-		if limit > 0 && len(allPRs) >= limit {
-			if len(allPRs) > limit {
-				allPRs = allPRs[:limit]
+
+	params := url.Values{}
+	params.Set("pagelen", strconv.Itoa(pageLen))
+	params.Set("fields", pullRequestListFields)
+	params.Set("sort", "-updated_on")
+	if opts.Sort != "" {
+		params.Set("sort", opts.Sort)
+	}
+	if opts.State != "" {
+		params.Set("state", opts.State)
+	}
+	if opts.Query != "" {
+		params.Set("q", opts.Query)
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests?%s",
+		url.PathEscape(c.workspace),
+		url.PathEscape(repoSlug),
+		params.Encode())
+
+	return collectPages[PullRequest](ctx, c, path, "list pull requests", opts.Limit)
+}
+
+// CountPullRequests returns how many of a repository's pull requests are in
+// state ("OPEN", "MERGED", "DECLINED", or "" for all states)
+func (c *Client) CountPullRequests(ctx context.Context, repoSlug string, state string) (int, error) {
+	if repoSlug == "" {
+		return 0, fmt.Errorf("repository slug is required")
+	}
+
+	params := url.Values{}
+	params.Set("pagelen", "1")
+	params.Set("fields", "size")
+	if state != "" {
+		params.Set("state", state)
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests?%s",
+		url.PathEscape(c.workspace),
+		url.PathEscape(repoSlug),
+		params.Encode())
+
+	var result PullRequestList
+	if err := c.Get(ctx, path, &result); err != nil {
+		return 0, fmt.Errorf("count pull requests: %w", err)
+	}
+	return result.Size, nil
+}
+
+// ListWorkspacePullRequests lists pull requests across every repository in the
+// workspace. Bitbucket has no workspace-wide listing for arbitrary filters, so
+// repositories are queried concurrently, at most concurrency at a time, and the
+// results merged with the most recently updated first. Each PR's destination
+// repository slug is set. opts.Limit caps both
+// each repository's results and the merged list; opts.Sort is ignored.
+func (c *Client) ListWorkspacePullRequests(ctx context.Context, opts PRListOptions, concurrency int) ([]PullRequest, error) {
+	repos, err := c.ListRepositories(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([][]PullRequest, len(repos))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(concurrency, 1))
+	for i, repo := range repos {
+		g.Go(func() error {
+			prs, err := c.QueryPullRequests(gctx, repo.Slug, opts)
+			if err != nil {
+				return fmt.Errorf("repository %s: %w", repo.Slug, err)
 			}
-			break
-		}
-		
-		if result.Next == "" {
-			break
-		}
-		
-		page++
+			// PR payloads omit the repository slug; record where each PR lives
+			for j := range prs {
+				if prs[j].Destination == nil {
+					prs[j].Destination = &PullRequestBranch{}
+				}
+				if prs[j].Destination.Repository == nil {
+					prs[j].Destination.Repository = &Repository{Name: repo.Name, FullName: repo.FullName}
+				}
+				prs[j].Destination.Repository.Slug = repo.Slug
+			}
+			results[i] = prs
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var allPRs []PullRequest
+	for _, prs := range results {
+		allPRs = append(allPRs, prs...)
+	}
+	sort.SliceStable(allPRs, func(i, j int) bool {
+		return allPRs[i].UpdatedOn.After(allPRs[j].UpdatedOn)
+	})
+	if opts.Limit > 0 && len(allPRs) > opts.Limit {
+		allPRs = allPRs[:opts.Limit]
 	}
-	
 	return allPRs, nil
 }
 
+// FindPullRequestsForBranch lists open pull requests whose source branch is branch
+func (c *Client) FindPullRequestsForBranch(ctx context.Context, repoSlug string, branch string) ([]PullRequest, error) {
+	if branch == "" {
+		return nil, fmt.Errorf("branch is required")
+	}
+
+	query := fmt.Sprintf(`source.branch.name = "%s" AND state = "OPEN"`, EscapeQueryString(branch))
+	prs, err := c.SearchPullRequests(ctx, repoSlug, query, 0)
+	if err != nil {
+		return nil, fmt.Errorf("find pull requests for branch %q: %w", branch, err)
+	}
+
+	return prs, nil
+}
+
+// EscapeQueryString escapes a value for use inside a double-quoted BBQL string
+func EscapeQueryString(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+// UserQueryTerm builds a BBQL term matching field (e.g. "author") against a
+// user given by UUID ({...}), account ID (contains ':') or nickname
+func UserQueryTerm(field, who string) string {
+	switch {
+	case strings.HasPrefix(who, "{"):
+		return fmt.Sprintf(`%s.uuid = "%s"`, field, EscapeQueryString(who))
+	case strings.Contains(who, ":"):
+		return fmt.Sprintf(`%s.account_id = "%s"`, field, EscapeQueryString(who))
+	default:
+		return fmt.Sprintf(`%s.nickname = "%s"`, field, EscapeQueryString(who))
+	}
+}
+
 // ApprovePR approves a pull request
 // Returns the updated participant information showing the approval
 func (c *Client) ApprovePR(ctx context.Context, repoSlug string, prID int) (*Participant, error) {
@@ -251,7 +393,7 @@ func (c *Client) ApprovePR(ctx context.Context, repoSlug string, prID int) (*Par
 	}
 
 	var participant Participant
-	err = c.client.Do(req, &participant)
+	err = c.do(req, &participant)
 	if err != nil {
 		return nil, fmt.Errorf("approve PR %d: %w", prID, err)
 	}
@@ -276,7 +418,7 @@ func (c *Client) UnapprovePR(ctx context.Context, repoSlug string, prID int) err
 	}
 
 	// DELETE returns 204 No Content, so we don't expect a response body
-	err = c.client.Do(req, nil)
+	err = c.do(req, nil)
 	if err != nil {
 		return fmt.Errorf("unapprove PR %d: %w", prID, err)
 	}
@@ -302,7 +444,7 @@ func (c *Client) RequestChangesPR(ctx context.Context, repoSlug string, prID int
 	}
 
 	var participant Participant
-	err = c.client.Do(req, &participant)
+	err = c.do(req, &participant)
 	if err != nil {
 		return nil, fmt.Errorf("request changes on PR %d: %w", prID, err)
 	}
@@ -327,7 +469,7 @@ func (c *Client) UnrequestChangesPR(ctx context.Context, repoSlug string, prID i
 	}
 
 	// DELETE returns 204 No Content
-	err = c.client.Do(req, nil)
+	err = c.do(req, nil)
 	if err != nil {
 		return fmt.Errorf("unrequest changes on PR %d: %w", prID, err)
 	}
@@ -335,6 +477,31 @@ func (c *Client) UnrequestChangesPR(ctx context.Context, repoSlug string, prID i
 	return nil
 }
 
+// DeclinePR declines an open pull request
+func (c *Client) DeclinePR(ctx context.Context, repoSlug string, prID int) (*PullRequest, error) {
+	if err := c.validatePRArgs(repoSlug, prID); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%d/decline",
+		url.PathEscape(c.workspace),
+		url.PathEscape(repoSlug),
+		prID)
+
+	req, err := c.client.NewRequest(ctx, "POST", path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	var pr PullRequest
+	err = c.do(req, &pr)
+	if err != nil {
+		return nil, fmt.Errorf("decline PR %d: %w", prID, err)
+	}
+
+	return &pr, nil
+}
+
 // CreatePROptions holds options for creating a pull request
 type CreatePROptions struct {
 	Title             string
@@ -343,6 +510,15 @@ type CreatePROptions struct {
 	DestinationBranch string // empty = repo mainbranch
 	CloseSourceBranch bool
 	Draft             bool
+	Reviewers         []string // user UUIDs ("{...}") or account IDs
+}
+
+// reviewerRef identifies a user by UUID when braced, otherwise by account ID
+func reviewerRef(id string) map[string]string {
+	if strings.HasPrefix(id, "{") {
+		return map[string]string{"uuid": id}
+	}
+	return map[string]string{"account_id": id}
 }
 
 // CreatePR creates a new pull request
@@ -381,6 +557,14 @@ func (c *Client) CreatePR(ctx context.Context, repoSlug string, opts CreatePROpt
 		}
 	}
 
+	if len(opts.Reviewers) > 0 {
+		reviewers := make([]map[string]string, len(opts.Reviewers))
+		for i, r := range opts.Reviewers {
+			reviewers[i] = reviewerRef(r)
+		}
+		body["reviewers"] = reviewers
+	}
+
 	var pr PullRequest
 	err := c.Post(ctx, path, body, &pr)
 	if err != nil {
@@ -392,8 +576,10 @@ func (c *Client) CreatePR(ctx context.Context, repoSlug string, opts CreatePROpt
 
 // UpdatePROptions holds options for updating a pull request
 type UpdatePROptions struct {
-	Title       string
-	Description string
+	Title             string
+	Description       *string  // nil = keep current description; "" clears it
+	DestinationBranch string   // empty = keep current destination
+	Reviewers         []string // user UUIDs or account IDs; nil = keep current reviewers
 }
 
 // UpdatePR updates an existing pull request
@@ -411,8 +597,23 @@ func (c *Client) UpdatePR(ctx context.Context, repoSlug string, prID int, opts U
 	if opts.Title != "" {
 		body["title"] = opts.Title
 	}
-	if opts.Description != "" {
-		body["description"] = opts.Description
+	if opts.Description != nil {
+		body["description"] = *opts.Description
+	}
+	if opts.DestinationBranch != "" {
+		body["destination"] = map[string]any{
+			"branch": map[string]string{
+				"name": opts.DestinationBranch,
+			},
+		}
+	}
+
+	if opts.Reviewers != nil {
+		reviewers := make([]map[string]string, len(opts.Reviewers))
+		for i, r := range opts.Reviewers {
+			reviewers[i] = reviewerRef(r)
+		}
+		body["reviewers"] = reviewers
 	}
 
 	var pr PullRequest
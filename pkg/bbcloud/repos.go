@@ -4,51 +4,93 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strconv"
 )
 
+// RepoListOptions selects and orders the repositories returned by QueryRepositories
+type RepoListOptions struct {
+	// Query is a Bitbucket query language (BBQL) filter
+	Query string
+	// Role limits results to repositories where the user is at least
+	// "member", "contributor", "admin" or "owner"
+	Role string
+	// Sort is the field to order by, prefixed with "-" for descending;
+	// empty uses the API default
+	Sort string
+	// Limit caps the results; 0 returns every match (with pagination)
+	Limit int
+}
+
 // ListRepositories lists repositories in the configured workspace
 // If limit is 0, all repositories are returned (with pagination)
 // If limit > 0, at most limit repositories are returned
 func (c *Client) ListRepositories(ctx context.Context, limit int) ([]Repository, error) {
-	var allRepos []Repository
-	page := 1
+	return c.QueryRepositories(ctx, RepoListOptions{Limit: limit})
+}
+
+// QueryRepositories lists repositories in the configured workspace filtered
+// and sorted by opts
+func (c *Client) QueryRepositories(ctx context.Context, opts RepoListOptions) ([]Repository, error) {
 	pageLen := 100 // Bitbucket Cloud max page size
-	
+
 	// If limit is set and less than pageLen, use it
-	if limit > 0 && limit < pageLen {
-		pageLen = limit
+	if opts.Limit > 0 && opts.Limit < pageLen {
+		pageLen = opts.Limit
 	}
-	
-	for {
-		path := fmt.Sprintf("/repositories/%s?pagelen=%d&page=%d", 
-			url.PathEscape(c.workspace), pageLen, page)
-		
-		var result RepositoryList
-		err := c.Get(ctx, path, &result)
-		if err != nil {
-			return nil, fmt.Errorf("list repositories (page %d): %w", page, err)
-		}
-		
-		allRepos = append(allRepos, result.Values...)
-		
-		// Check if we've hit the limit or there's no more data
-		if limit > 0 && len(allRepos) >= limit {
-			// Trim to exact limit if we exceeded it
-			if len(allRepos) > limit {
-				allRepos = allRepos[:limit]
-			}
-			break
-		}
-		
-		// Check if there's a next page
-		if result.Next == "" {
-			break
-		}
-		
-		page++
+
+	return collectPages[Repository](ctx, c, c.repositoriesPath(opts, 1, pageLen), "list repositories", opts.Limit)
+}
+
+// RepositoriesPage fetches one page of the repositories matching opts
+// (opts.Limit is ignored). It returns the number of the next page, or 0 on
+// the last page.
+func (c *Client) RepositoriesPage(ctx context.Context, opts RepoListOptions, page, perPage int) ([]Repository, int, error) {
+	var result RepositoryList
+	if err := c.Get(ctx, c.repositoriesPath(opts, page, perPage), &result); err != nil {
+		return nil, 0, fmt.Errorf("list repositories (page %d): %w", page, err)
 	}
-	
-	return allRepos, nil
+
+	return result.Values, nextPage(result.PaginatedResponse, page), nil
+}
+
+// repositoriesPath is the path of one page of the repositories matching opts
+func (c *Client) repositoriesPath(opts RepoListOptions, page, perPage int) string {
+	params := url.Values{}
+	params.Set("pagelen", strconv.Itoa(perPage))
+	params.Set("page", strconv.Itoa(page))
+	params.Set("fields", repositoryListFields)
+	if opts.Query != "" {
+		params.Set("q", opts.Query)
+	}
+	if opts.Role != "" {
+		params.Set("role", opts.Role)
+	}
+	if opts.Sort != "" {
+		params.Set("sort", opts.Sort)
+	}
+
+	return fmt.Sprintf("/repositories/%s?%s",
+		url.PathEscape(c.workspace), params.Encode())
+}
+
+// CountRepositories returns how many repositories in the configured
+// workspace match the BBQL query
+func (c *Client) CountRepositories(ctx context.Context, query string) (int, error) {
+	params := url.Values{}
+	params.Set("pagelen", "1")
+	params.Set("fields", "size")
+	if query != "" {
+		params.Set("q", query)
+	}
+
+	path := fmt.Sprintf("/repositories/%s?%s",
+		url.PathEscape(c.workspace), params.Encode())
+
+	var result RepositoryList
+	if err := c.Get(ctx, path, &result); err != nil {
+		return 0, fmt.Errorf("count repositories: %w", err)
+	}
+	return result.Size, nil
 }
 
 // GetRepository retrieves a single repository by slug
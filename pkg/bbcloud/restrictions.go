@@ -0,0 +1,240 @@
+package bbcloud
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Merge check kinds of branch restrictions: conditions a pull request into a
+// matching branch must meet before it is merged
+const (
+	CheckApprovals                = "require_approvals_to_merge"
+	CheckDefaultReviewerApprovals = "require_default_reviewer_approvals_to_merge"
+	CheckPassingBuilds            = "require_passing_builds_to_merge"
+	CheckTasksCompleted           = "require_tasks_to_be_completed"
+	CheckNoChangesRequested       = "require_no_changes_requested"
+	CheckCommitsBehind            = "require_commits_behind"
+
+	// CheckEnforced makes unmet merge checks block the merge (Premium plans);
+	// without it they are only shown as warnings
+	CheckEnforced = "enforce_merge_checks"
+)
+
+// mergeCheckKinds are the restriction kinds GetPRMergeRestrictions returns
+var mergeCheckKinds = map[string]bool{
+	CheckApprovals:                true,
+	CheckDefaultReviewerApprovals: true,
+	CheckPassingBuilds:            true,
+	CheckTasksCompleted:           true,
+	CheckNoChangesRequested:       true,
+	CheckCommitsBehind:            true,
+	CheckEnforced:                 true,
+}
+
+// BranchRestriction is a branch permission or merge check of a repository
+type BranchRestriction struct {
+	ID   int    `json:"id"`
+	Kind string `json:"kind"`
+	// BranchMatchKind is "glob" (Pattern names the branches) or
+	// "branching_model" (BranchType does)
+	BranchMatchKind string `json:"branch_match_kind"`
+	Pattern         string `json:"pattern,omitempty"`
+	BranchType      string `json:"branch_type,omitempty"`
+	// Value is the number the check requires, e.g. approvals; nil for checks
+	// without one
+	Value *int `json:"value,omitempty"`
+}
+
+// BranchingModel is a repository's branching model: its development and
+// production branches and the prefixes of its branch types
+type BranchingModel struct {
+	Development *ModelBranch `json:"development,omitempty"`
+	Production  *ModelBranch `json:"production,omitempty"`
+	BranchTypes []BranchType `json:"branch_types,omitempty"`
+}
+
+// BranchType is a kind of branch in a branching model (feature, bugfix,
+// release, hotfix), named by its prefix
+type BranchType struct {
+	Kind   string `json:"kind"`
+	Prefix string `json:"prefix"`
+}
+
+// ModelBranch is the development or production branch of a branching model
+type ModelBranch struct {
+	Name   string  `json:"name"`
+	Branch *Branch `json:"branch,omitempty"`
+}
+
+// ListBranchRestrictions lists a repository's branch restrictions. Only
+// repository administrators may read them; others get ErrForbidden.
+func (c *Client) ListBranchRestrictions(ctx context.Context, repoSlug string) ([]BranchRestriction, error) {
+	if repoSlug == "" {
+		return nil, fmt.Errorf("repository slug is required")
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s/branch-restrictions?pagelen=100",
+		url.PathEscape(c.workspace),
+		url.PathEscape(repoSlug))
+	return collectPages[BranchRestriction](ctx, c, path, "list branch restrictions", 0)
+}
+
+// GetBranchingModel returns a repository's effective branching model
+func (c *Client) GetBranchingModel(ctx context.Context, repoSlug string) (*BranchingModel, error) {
+	if repoSlug == "" {
+		return nil, fmt.Errorf("repository slug is required")
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s/effective-branching-model",
+		url.PathEscape(c.workspace),
+		url.PathEscape(repoSlug))
+	var model BranchingModel
+	if err := c.Get(ctx, path, &model); err != nil {
+		return nil, fmt.Errorf("get branching model: %w", err)
+	}
+	return &model, nil
+}
+
+// ListDefaultReviewers lists the effective default reviewers of a
+// repository, those of its project included
+func (c *Client) ListDefaultReviewers(ctx context.Context, repoSlug string) ([]User, error) {
+	if repoSlug == "" {
+		return nil, fmt.Errorf("repository slug is required")
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s/effective-default-reviewers?pagelen=100",
+		url.PathEscape(c.workspace),
+		url.PathEscape(repoSlug))
+	reviewers, err := collectPages[struct {
+		User *User `json:"user"`
+	}](ctx, c, path, "list default reviewers", 0)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]User, 0, len(reviewers))
+	for _, r := range reviewers {
+		if r.User != nil {
+			users = append(users, *r.User)
+		}
+	}
+	return users, nil
+}
+
+// GetPRMergeRestrictions returns the merge checks that apply to a pull
+// request, those of branch restrictions matching its destination branch.
+// Like ListBranchRestrictions it needs repository admin access.
+func (c *Client) GetPRMergeRestrictions(ctx context.Context, repoSlug string, prID int) ([]BranchRestriction, error) {
+	pr, err := c.GetPullRequest(ctx, repoSlug, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr.Destination == nil || pr.Destination.Branch == nil {
+		return nil, fmt.Errorf("pull request %d has no destination branch", prID)
+	}
+	branch := pr.Destination.Branch.Name
+
+	restrictions, err := c.ListBranchRestrictions(ctx, repoSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		checks []BranchRestriction
+		model  *BranchingModel
+	)
+	for _, r := range restrictions {
+		if !mergeCheckKinds[r.Kind] {
+			continue
+		}
+		// The branching model is only read when a check refers to it
+		if r.BranchMatchKind == "branching_model" && model == nil {
+			if model, err = c.GetBranchingModel(ctx, repoSlug); err != nil {
+				return nil, err
+			}
+		}
+		if r.Matches(branch, model) {
+			checks = append(checks, r)
+		}
+	}
+	return checks, nil
+}
+
+// Matches reports whether the restriction applies to branch; model resolves
+// branching model restrictions and may be nil for glob ones
+func (r BranchRestriction) Matches(branch string, model *BranchingModel) bool {
+	if r.BranchMatchKind != "branching_model" {
+		return globMatch(r.Pattern, branch)
+	}
+	if model == nil {
+		return false
+	}
+	modelBranch := func(b *ModelBranch) bool {
+		return b != nil && b.Branch != nil && b.Branch.Name == branch
+	}
+	switch r.BranchType {
+	case "development":
+		return modelBranch(model.Development)
+	case "production":
+		return modelBranch(model.Production)
+	}
+	for _, t := range model.BranchTypes {
+		if t.Kind == r.BranchType && t.Prefix != "" && strings.HasPrefix(branch, t.Prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// globMatch matches a branch restriction pattern, where * matches any run of
+// characters, slashes included, and ? any one character
+func globMatch(pattern, name string) bool {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String()).MatchString(name)
+}
+
+// Conflicted reports whether the file has merge conflicts between the source
+// and destination branches: Bitbucket marks them in the diffstat status
+func (f FileStats) Conflicted() bool {
+	switch f.Status {
+	case "merge conflict", "local deleted", "remote deleted":
+		return true
+	}
+	return false
+}
+
+// GetPRConflicts returns the paths of the files of a pull request that
+// conflict with its destination branch, from the statuses of its diffstat
+func (c *Client) GetPRConflicts(ctx context.Context, repoSlug string, prID int) ([]string, error) {
+	files, _, err := c.ListPRDiffStats(ctx, repoSlug, prID, 0)
+	if err != nil {
+		return nil, err
+	}
+	var conflicted []string
+	for _, f := range files {
+		if !f.Conflicted() {
+			continue
+		}
+		switch {
+		case f.New != nil:
+			conflicted = append(conflicted, f.New.Path)
+		case f.Old != nil:
+			conflicted = append(conflicted, f.Old.Path)
+		}
+	}
+	return conflicted, nil
+}
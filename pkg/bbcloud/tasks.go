@@ -0,0 +1,48 @@
+package bbcloud
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// ListPRTasks retrieves all tasks on a pull request
+func (c *Client) ListPRTasks(ctx context.Context, repoSlug string, prID int) ([]Task, error) {
+	if err := c.validatePRArgs(repoSlug, prID); err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%d/tasks?pagelen=100",
+		url.PathEscape(c.workspace),
+		url.PathEscape(repoSlug),
+		prID)
+
+	return collectPages[Task](ctx, c, path, "list PR tasks", 0)
+}
+
+// CreatePRTask adds a task to a pull request
+func (c *Client) CreatePRTask(ctx context.Context, repoSlug string, prID int, content string) (*Task, error) {
+	if err := c.validatePRArgs(repoSlug, prID); err != nil {
+		return nil, err
+	}
+	if content == "" {
+		return nil, fmt.Errorf("task content is required")
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%d/tasks",
+		url.PathEscape(c.workspace),
+		url.PathEscape(repoSlug),
+		prID)
+
+	body := map[string]any{
+		"content": map[string]string{
+			"raw": content,
+		},
+	}
+
+	var task Task
+	if err := c.Post(ctx, path, body, &task); err != nil {
+		return nil, fmt.Errorf("create task: %w", err)
+	}
+	return &task, nil
+}
@@ -1,6 +1,9 @@
 package bbcloud
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // User represents a Bitbucket Cloud user or account
 type User struct {
@@ -48,6 +51,16 @@ type Repository struct {
 	Size        int64        `json:"size,omitempty"`
 }
 
+// CloneURL returns the clone link for protocol ("https" or "ssh"), or "" if absent
+func (r *Repository) CloneURL(protocol string) string {
+	for _, link := range r.Links.Clone {
+		if link.Name == protocol {
+			return link.Href
+		}
+	}
+	return ""
+}
+
 // Branch represents a repository branch
 type Branch struct {
 	Name   string           `json:"name"`
@@ -55,12 +68,21 @@ type Branch struct {
 	Type   string           `json:"type"`
 }
 
+// Tag represents a repository tag; its target is the tagged commit
+type Tag struct {
+	Name   string           `json:"name"`
+	Target *CommitReference `json:"target,omitempty"`
+	Type   string           `json:"type"`
+}
+
 // Project represents a Bitbucket Cloud project
 type Project struct {
-	UUID string `json:"uuid"`
-	Key  string `json:"key"`
-	Name string `json:"name"`
-	Type string `json:"type"`
+	UUID        string `json:"uuid"`
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	IsPrivate   bool   `json:"is_private,omitempty"`
+	Type        string `json:"type"`
 }
 
 // PullRequest represents a Bitbucket Cloud pull request
@@ -84,6 +106,16 @@ type PullRequest struct {
 	TaskCount    int                 `json:"task_count,omitempty"`
 }
 
+// ApprovedBy reports whether the user with uuid has approved the PR
+func (pr *PullRequest) ApprovedBy(uuid string) bool {
+	for _, p := range pr.Participants {
+		if p.User != nil && p.User.UUID == uuid && p.Approved {
+			return true
+		}
+	}
+	return false
+}
+
 // PullRequestBranch represents source or destination branch in a PR
 type PullRequestBranch struct {
 	Branch     *Branch      `json:"branch,omitempty"`
@@ -93,10 +125,28 @@ type PullRequestBranch struct {
 
 // CommitReference represents a commit reference
 type CommitReference struct {
-	Hash  string    `json:"hash"`
-	Type  string    `json:"type"`
-	Links Links     `json:"links,omitempty"`
-	Date  time.Time `json:"date,omitempty"`
+	Hash    string        `json:"hash"`
+	Type    string        `json:"type"`
+	Links   Links         `json:"links,omitempty"`
+	Date    time.Time     `json:"date,omitempty"`
+	Author  *CommitAuthor `json:"author,omitempty"`
+	Message string        `json:"message,omitempty"`
+}
+
+// CommitAuthor is a commit's author: the raw "Name <email>" from git, plus
+// the Bitbucket user when the email maps to an account
+type CommitAuthor struct {
+	Raw  string `json:"raw"`
+	User *User  `json:"user,omitempty"`
+}
+
+// Name returns the author's display name, falling back to the git name
+func (a *CommitAuthor) Name() string {
+	if a.User != nil && a.User.DisplayName != "" {
+		return a.User.DisplayName
+	}
+	name, _, _ := strings.Cut(a.Raw, " <")
+	return name
 }
 
 // Participant represents a PR participant
@@ -153,6 +203,14 @@ type Comment struct {
 	Links     Links            `json:"links,omitempty"`
 	Type      string           `json:"type"`
 	Deleted   bool             `json:"deleted,omitempty"`
+	Resolution *CommentResolution `json:"resolution,omitempty"` // set once an inline thread is resolved
+}
+
+// CommentResolution records who resolved an inline comment thread and when
+type CommentResolution struct {
+	Type      string    `json:"type"`
+	User      *User     `json:"user,omitempty"`
+	CreatedOn time.Time `json:"created_on"`
 }
 
 // Content represents rich content (markdown, raw, html)
@@ -181,11 +239,27 @@ func (c *Comment) IsInline() bool {
 	return c.Inline != nil
 }
 
+// IsResolved returns true if the comment's thread has been resolved
+func (c *Comment) IsResolved() bool {
+	return c.Resolution != nil
+}
+
+// Task represents a pull request task, optionally anchored to a comment
+type Task struct {
+	ID        int         `json:"id"`
+	State     string      `json:"state"` // "RESOLVED" or "UNRESOLVED"
+	Content   *Content    `json:"content,omitempty"`
+	Creator   *User       `json:"creator,omitempty"`
+	Comment   *CommentRef `json:"comment,omitempty"`
+	CreatedOn time.Time   `json:"created_on"`
+}
+
 // Activity represents an activity item in a PR timeline
 type Activity struct {
 	Update    *ActivityUpdate  `json:"update,omitempty"`
 	Comment   *Comment         `json:"comment,omitempty"`
 	Approval  *ActivityApproval `json:"approval,omitempty"`
+	ChangesRequested *ActivityApproval `json:"changes_requested,omitempty"`
 }
 
 // ActivityUpdate represents a PR update activity
@@ -219,6 +293,18 @@ type Pipeline struct {
 	Type         string           `json:"type"`
 }
 
+// Status returns the pipeline's result once completed (SUCCESSFUL, FAILED,
+// ERROR, STOPPED), otherwise its state (PENDING, IN_PROGRESS, PAUSED)
+func (p *Pipeline) Status() string {
+	if p.State == nil {
+		return ""
+	}
+	if p.State.Result != nil && p.State.Result.Name != "" {
+		return p.State.Result.Name
+	}
+	return p.State.Name
+}
+
 // CommitStatus represents a commit status (build/check result)
 // This is different from Pipeline - it's used for the /statuses endpoint
 type CommitStatus struct {
@@ -276,6 +362,7 @@ type Links struct {
 	Statuses   *Link `json:"statuses,omitempty"`
 	Decline    *Link `json:"decline,omitempty"`
 	Merge      *Link `json:"merge,omitempty"`
+	Clone      []Link `json:"clone,omitempty"`
 }
 
 // Link represents a single HAL link
@@ -293,6 +380,15 @@ type PaginatedResponse struct {
 	Previous string `json:"previous,omitempty"`
 }
 
+// nextPage returns the number of the page after page, or 0 when resp is the
+// last page
+func nextPage(resp PaginatedResponse, page int) int {
+	if resp.Next == "" {
+		return 0
+	}
+	return page + 1
+}
+
 // RepositoryList represents a paginated list of repositories
 type RepositoryList struct {
 	PaginatedResponse
@@ -305,6 +401,61 @@ type PullRequestList struct {
 	Values []PullRequest `json:"values"`
 }
 
+// WorkspaceMembership links a user to a workspace
+type WorkspaceMembership struct {
+	User *User `json:"user"`
+}
+
+// Workspace represents a Bitbucket workspace
+type Workspace struct {
+	UUID  string `json:"uuid"`
+	Slug  string `json:"slug"`
+	Name  string `json:"name"`
+	Links Links  `json:"links,omitempty"`
+}
+
+// WorkspacePermission is the current user's role in a workspace
+type WorkspacePermission struct {
+	Permission string     `json:"permission"` // "owner", "collaborator" or "member"
+	Workspace  *Workspace `json:"workspace"`
+}
+
+// WorkspacePermissionList represents a paginated list of workspace permissions
+type WorkspacePermissionList struct {
+	PaginatedResponse
+	Values []WorkspacePermission `json:"values"`
+}
+
+// ProjectList represents a paginated list of projects
+type ProjectList struct {
+	PaginatedResponse
+	Values []Project `json:"values"`
+}
+
+// WorkspaceMembershipList represents a paginated list of workspace memberships
+type WorkspaceMembershipList struct {
+	PaginatedResponse
+	Values []WorkspaceMembership `json:"values"`
+}
+
+// TaskList represents a paginated list of tasks
+type TaskList struct {
+	PaginatedResponse
+	Values []Task `json:"values"`
+}
+
+// BranchList represents a paginated list of branches
+type BranchList struct {
+	PaginatedResponse
+	Values []Branch `json:"values"`
+}
+
+// CommitList represents a paginated list of commits
+type CommitList struct {
+	PaginatedResponse
+	Values []CommitReference `json:"values"`
+}
+
 // CommentList represents a paginated list of comments
 type CommentList struct {
 	PaginatedResponse
@@ -328,24 +479,3 @@ type FileStatsList struct {
 	PaginatedResponse
 	Values []FileStats `json:"values"`
 }
-
-// Error represents a Bitbucket API error response
-type Error struct {
-	Type      string       `json:"type"`
-	ErrorInfo ErrorDetail  `json:"error"`
-	RequestID string       `json:"request_id,omitempty"`
-}
-
-// ErrorDetail contains error details
-type ErrorDetail struct {
-	Message string                 `json:"message"`
-	Detail  string                 `json:"detail,omitempty"`
-	Fields  map[string]interface{} `json:"fields,omitempty"`
-}
-
-func (e *Error) Error() string {
-	if e.ErrorInfo.Detail != "" {
-		return e.ErrorInfo.Message + ": " + e.ErrorInfo.Detail
-	}
-	return e.ErrorInfo.Message
-}
@@ -6,6 +6,32 @@ import (
 	"strings"
 )
 
+// RequiredScopes lists the OAuth scopes needed for bb to function correctly
+var RequiredScopes = []string{
+	"read:user:bitbucket",
+	"read:workspace:bitbucket",
+	"read:repository:bitbucket",
+	"read:pullrequest:bitbucket",
+	"write:pullrequest:bitbucket",
+	"read:pipeline:bitbucket",
+}
+
+// MissingScopes returns the RequiredScopes not among granted
+func MissingScopes(granted []string) []string {
+	grantedSet := make(map[string]bool)
+	for _, scope := range granted {
+		grantedSet[scope] = true
+	}
+
+	var missing []string
+	for _, scope := range RequiredScopes {
+		if !grantedSet[scope] {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
 // CurrentUser returns information about the currently authenticated user
 func (c *Client) CurrentUser(ctx context.Context) (*User, error) {
 	var user User
@@ -0,0 +1,62 @@
+package bbcloud
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Webhook is a repository webhook subscription
+type Webhook struct {
+	UUID        string   `json:"uuid,omitempty"`
+	URL         string   `json:"url"`
+	Description string   `json:"description,omitempty"`
+	Active      bool     `json:"active"`
+	Events      []string `json:"events"`
+	// Secret signs deliveries with an X-Hub-Signature HMAC-SHA256 header; it
+	// is write-only
+	Secret string `json:"secret,omitempty"`
+}
+
+// CreateWebhook subscribes url to events on a repository
+func (c *Client) CreateWebhook(ctx context.Context, repoSlug string, hook Webhook) (*Webhook, error) {
+	if repoSlug == "" {
+		return nil, fmt.Errorf("repository slug is required")
+	}
+	if hook.URL == "" {
+		return nil, fmt.Errorf("webhook URL is required")
+	}
+	if len(hook.Events) == 0 {
+		return nil, fmt.Errorf("at least one event is required")
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s/hooks",
+		url.PathEscape(c.workspace),
+		url.PathEscape(repoSlug))
+
+	var created Webhook
+	if err := c.Post(ctx, path, hook, &created); err != nil {
+		return nil, fmt.Errorf("create webhook: %w", err)
+	}
+	return &created, nil
+}
+
+// DeleteWebhook removes a repository webhook by UUID
+func (c *Client) DeleteWebhook(ctx context.Context, repoSlug string, uuid string) error {
+	if repoSlug == "" {
+		return fmt.Errorf("repository slug is required")
+	}
+	if uuid == "" {
+		return fmt.Errorf("webhook UUID is required")
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s/hooks/%s",
+		url.PathEscape(c.workspace),
+		url.PathEscape(repoSlug),
+		url.PathEscape(uuid))
+
+	if err := c.Delete(ctx, path); err != nil {
+		return fmt.Errorf("delete webhook: %w", err)
+	}
+	return nil
+}
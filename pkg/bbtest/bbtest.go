@@ -0,0 +1,593 @@
+// Package bbtest provides a fake Bitbucket Cloud API server for testing code
+// built on pkg/bbcloud without network access or credentials.
+//
+// A Server starts empty apart from its user. Tests add the repositories, pull
+// requests, comments and pipelines they need, point a client at it, and then
+// assert on the requests it received:
+//
+//	srv := bbtest.NewServer(t)
+//	srv.AddRepository("acme", bbcloud.Repository{Slug: "api"})
+//	srv.AddPullRequest("acme", "api", bbcloud.PullRequest{ID: 1, Title: "Fix login", State: "OPEN"})
+//
+//	client := srv.Client(t, "acme")
+//	prs, err := client.ListPullRequests(ctx, "api", "OPEN", 0)
+//	...
+//	srv.AssertRequested(t, "GET", "/repositories/acme/api/pullrequests")
+//
+// The server implements the endpoints most integrations use: the current
+// user, repositories, pull requests (list, get, create), PR comments (list,
+// get, create), approvals, change requests and declines, and pipelines. Lists honour
+// page and pagelen and filter pull requests by state; BBQL queries (q) and
+// sort are ignored. Anything else answers 404 unless a handler is added with
+// Handle.
+package bbtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+)
+
+// DefaultUser is the user a new Server authenticates every request as
+var DefaultUser = bbcloud.User{
+	UUID:        "{test-uuid}",
+	Username:    "testuser",
+	DisplayName: "Test User",
+	AccountID:   "test-account-id",
+	Nickname:    "testuser",
+	Type:        "user",
+}
+
+const apiPrefix = "/2.0"
+
+// Request is a request received by a Server
+type Request struct {
+	Method string
+	// Path is the URL path without the /2.0 API prefix, e.g.
+	// /repositories/acme/api/pullrequests
+	Path  string
+	Query string
+	Body  []byte
+}
+
+// Server is a fake Bitbucket Cloud API backed by in-memory fixtures. It is
+// safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	user      bbcloud.User
+	repos     map[string][]bbcloud.Repository        // by workspace
+	prs       map[string][]bbcloud.PullRequest       // by workspace/repo
+	comments  map[string][]bbcloud.Comment           // by workspace/repo/pr
+	diffstats map[string][]bbcloud.FileStats         // by workspace/repo/pr
+	pipelines map[string][]bbcloud.Pipeline          // by workspace/repo
+	restricts map[string][]bbcloud.BranchRestriction // by workspace/repo
+	handlers  map[string]http.HandlerFunc            // by "METHOD path"
+	requests  []Request
+}
+
+// NewServer starts a Server, closed when the test ends
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	s := &Server{
+		user:      DefaultUser,
+		repos:     make(map[string][]bbcloud.Repository),
+		prs:       make(map[string][]bbcloud.PullRequest),
+		comments:  make(map[string][]bbcloud.Comment),
+		diffstats: make(map[string][]bbcloud.FileStats),
+		pipelines: make(map[string][]bbcloud.Pipeline),
+		restricts: make(map[string][]bbcloud.BranchRestriction),
+		handlers:  make(map[string]http.HandlerFunc),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// BaseURL returns the API base URL to use as bbcloud.Options.BaseURL
+func (s *Server) BaseURL() string {
+	return s.URL + apiPrefix
+}
+
+// Client returns a bbcloud client for workspace talking to the server
+func (s *Server) Client(t testing.TB, workspace string) *bbcloud.Client {
+	t.Helper()
+	s.mu.Lock()
+	username := s.user.Username
+	s.mu.Unlock()
+	client, err := bbcloud.New(bbcloud.Options{
+		BaseURL:   s.BaseURL(),
+		Workspace: workspace,
+		Username:  username,
+		Token:     "test-token",
+	})
+	if err != nil {
+		t.Fatalf("bbtest: create client: %v", err)
+	}
+	return client
+}
+
+// SetUser replaces the user returned by /user and recorded as the author of
+// comments, pull requests and approvals created through the server
+func (s *Server) SetUser(u bbcloud.User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.user = u
+}
+
+// AddRepository adds a repository to workspace. Name, FullName and Type are
+// filled in when empty.
+func (s *Server) AddRepository(workspace string, repo bbcloud.Repository) {
+	if repo.FullName == "" {
+		repo.FullName = workspace + "/" + repo.Slug
+	}
+	if repo.Name == "" {
+		repo.Name = repo.Slug
+	}
+	if repo.Type == "" {
+		repo.Type = "repository"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repos[workspace] = append(s.repos[workspace], repo)
+}
+
+// AddPullRequest adds a pull request to workspace/repo. A zero ID is assigned
+// the next free one and an empty State means OPEN.
+func (s *Server) AddPullRequest(workspace, repo string, pr bbcloud.PullRequest) bbcloud.PullRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addPullRequest(repoKey(workspace, repo), pr)
+}
+
+func (s *Server) addPullRequest(key string, pr bbcloud.PullRequest) bbcloud.PullRequest {
+	if pr.ID == 0 {
+		for _, existing := range s.prs[key] {
+			pr.ID = max(pr.ID, existing.ID)
+		}
+		pr.ID++
+	}
+	if pr.State == "" {
+		pr.State = "OPEN"
+	}
+	if pr.Type == "" {
+		pr.Type = "pullrequest"
+	}
+	s.prs[key] = append(s.prs[key], pr)
+	return pr
+}
+
+// AddComment adds a comment to pull request prID of workspace/repo. A zero ID
+// is assigned the next free one.
+func (s *Server) AddComment(workspace, repo string, prID int, c bbcloud.Comment) bbcloud.Comment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addComment(prKey(workspace, repo, prID), c)
+}
+
+func (s *Server) addComment(key string, c bbcloud.Comment) bbcloud.Comment {
+	if c.ID == 0 {
+		for _, existing := range s.comments[key] {
+			c.ID = max(c.ID, existing.ID)
+		}
+		c.ID++
+	}
+	if c.Type == "" {
+		c.Type = "pullrequest_comment"
+	}
+	s.comments[key] = append(s.comments[key], c)
+	return c
+}
+
+// AddDiffStat adds files to the diffstat of pull request prID of
+// workspace/repo. Status defaults to "modified" and Type to "diffstat".
+func (s *Server) AddDiffStat(workspace, repo string, prID int, files ...bbcloud.FileStats) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := prKey(workspace, repo, prID)
+	for _, f := range files {
+		if f.Status == "" {
+			f.Status = "modified"
+		}
+		if f.Type == "" {
+			f.Type = "diffstat"
+		}
+		s.diffstats[key] = append(s.diffstats[key], f)
+	}
+}
+
+// AddPipeline adds a pipeline to workspace/repo. Pipelines are listed newest
+// first, by build number.
+func (s *Server) AddPipeline(workspace, repo string, p bbcloud.Pipeline) {
+	if p.Type == "" {
+		p.Type = "pipeline"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := repoKey(workspace, repo)
+	s.pipelines[key] = append(s.pipelines[key], p)
+	sort.SliceStable(s.pipelines[key], func(i, j int) bool {
+		return s.pipelines[key][i].BuildNumber > s.pipelines[key][j].BuildNumber
+	})
+}
+
+// AddBranchRestriction adds a branch restriction to workspace/repo, numbering
+// it when r.ID is zero. BranchMatchKind defaults to "glob".
+func (s *Server) AddBranchRestriction(workspace, repo string, r bbcloud.BranchRestriction) bbcloud.BranchRestriction {
+	if r.BranchMatchKind == "" {
+		r.BranchMatchKind = "glob"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := repoKey(workspace, repo)
+	if r.ID == 0 {
+		r.ID = len(s.restricts[key]) + 1
+	}
+	s.restricts[key] = append(s.restricts[key], r)
+	return r
+}
+
+// PullRequest returns the current state of a pull request, including changes
+// made through the API
+func (s *Server) PullRequest(workspace, repo string, id int) (bbcloud.PullRequest, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pr := s.findPR(repoKey(workspace, repo), id); pr != nil {
+		return *pr, true
+	}
+	return bbcloud.PullRequest{}, false
+}
+
+// Comments returns the comments on a pull request, including those created
+// through the API
+func (s *Server) Comments(workspace, repo string, prID int) []bbcloud.Comment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]bbcloud.Comment(nil), s.comments[prKey(workspace, repo, prID)]...)
+}
+
+// Handle answers method requests for path (without the /2.0 prefix) with h,
+// taking precedence over the built-in endpoints. Use it for endpoints the
+// server does not implement or to inject errors.
+func (s *Server) Handle(method, path string, h http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method+" "+path] = h
+}
+
+// Requests returns the requests received so far, oldest first
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Reset forgets the requests received so far
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// Requested returns the requests received for method and path
+func (s *Server) Requested(method, path string) []Request {
+	var matched []Request
+	for _, r := range s.Requests() {
+		if r.Method == method && r.Path == path {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+// AssertRequested fails the test unless method path was requested, and
+// returns the last such request
+func (s *Server) AssertRequested(t testing.TB, method, path string) Request {
+	t.Helper()
+	matched := s.Requested(method, path)
+	if len(matched) == 0 {
+		t.Errorf("bbtest: no %s %s request; got:\n%s", method, path, s.describeRequests())
+		return Request{}
+	}
+	return matched[len(matched)-1]
+}
+
+// AssertNotRequested fails the test if method path was requested
+func (s *Server) AssertNotRequested(t testing.TB, method, path string) {
+	t.Helper()
+	if n := len(s.Requested(method, path)); n > 0 {
+		t.Errorf("bbtest: got %d unexpected %s %s request(s)", n, method, path)
+	}
+}
+
+func (s *Server) describeRequests() string {
+	var b strings.Builder
+	for _, r := range s.Requests() {
+		fmt.Fprintf(&b, "  %s %s\n", r.Method, r.Path)
+	}
+	if b.Len() == 0 {
+		return "  (none)"
+	}
+	return b.String()
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	path := strings.TrimPrefix(r.URL.Path, apiPrefix)
+	// Bitbucket accepts a trailing slash on collections (pipelines/)
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: path, Query: r.URL.RawQuery, Body: body})
+	h := s.handlers[r.Method+" "+path]
+	s.mu.Unlock()
+
+	if h != nil {
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+		h(w, r)
+		return
+	}
+	if !strings.HasPrefix(r.URL.Path, apiPrefix+"/") {
+		writeError(w, http.StatusNotFound, "Resource not found")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.route(w, r, strings.Split(strings.Trim(path, "/"), "/"), body)
+}
+
+// route dispatches a request on its path segments; called with s.mu held
+func (s *Server) route(w http.ResponseWriter, r *http.Request, seg []string, body []byte) {
+	get := r.Method == http.MethodGet
+	switch {
+	case len(seg) == 1 && seg[0] == "user" && get:
+		writeJSON(w, http.StatusOK, s.user)
+		return
+	case len(seg) < 2 || seg[0] != "repositories":
+		writeError(w, http.StatusNotFound, "Resource not found")
+		return
+	case len(seg) == 2 && get:
+		writePage(w, r, s.repos[seg[1]])
+		return
+	}
+
+	ws := seg[1]
+	repo := s.findRepo(ws, seg[2])
+	if repo == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Repository %s/%s not found", ws, seg[2]))
+		return
+	}
+	key := repoKey(ws, seg[2])
+	rest := seg[3:]
+
+	switch {
+	case len(rest) == 0 && get:
+		writeJSON(w, http.StatusOK, repo)
+	case len(rest) == 1 && rest[0] == "pullrequests":
+		s.servePullRequests(w, r, key, body)
+	case len(rest) >= 2 && rest[0] == "pullrequests":
+		s.servePullRequest(w, r, key, rest[1:], body)
+	case len(rest) == 1 && rest[0] == "pipelines" && get:
+		writePage(w, r, s.pipelines[key])
+	case len(rest) == 2 && rest[0] == "pipelines" && get:
+		for _, p := range s.pipelines[key] {
+			if p.UUID == rest[1] {
+				writeJSON(w, http.StatusOK, p)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "Pipeline not found")
+	case len(rest) == 1 && rest[0] == "branch-restrictions" && get:
+		writePage(w, r, s.restricts[key])
+	default:
+		writeError(w, http.StatusNotFound, "Resource not found")
+	}
+}
+
+func (s *Server) servePullRequests(w http.ResponseWriter, r *http.Request, key string, body []byte) {
+	switch r.Method {
+	case http.MethodGet:
+		prs := s.prs[key]
+		if state := r.URL.Query().Get("state"); state != "" {
+			prs = nil
+			for _, pr := range s.prs[key] {
+				if strings.EqualFold(pr.State, state) {
+					prs = append(prs, pr)
+				}
+			}
+		}
+		writePage(w, r, prs)
+	case http.MethodPost:
+		var pr bbcloud.PullRequest
+		if err := json.Unmarshal(body, &pr); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+			return
+		}
+		if pr.Title == "" || pr.Source == nil || pr.Source.Branch == nil || pr.Source.Branch.Name == "" {
+			writeError(w, http.StatusBadRequest, "title and source branch are required")
+			return
+		}
+		now, author := time.Now().UTC(), s.user
+		pr.ID, pr.State = 0, ""
+		pr.Author, pr.CreatedOn, pr.UpdatedOn = &author, now, now
+		writeJSON(w, http.StatusCreated, s.addPullRequest(key, pr))
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// servePullRequest serves /pullrequests/{id}/...; seg starts at the id
+func (s *Server) servePullRequest(w http.ResponseWriter, r *http.Request, key string, seg []string, body []byte) {
+	id, err := strconv.Atoi(seg[0])
+	pr := s.findPR(key, id)
+	if err != nil || pr == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Pull request %s not found", seg[0]))
+		return
+	}
+	ckey := key + "/" + seg[0]
+
+	switch {
+	case len(seg) == 1 && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, pr)
+	case len(seg) == 2 && seg[1] == "comments" && r.Method == http.MethodGet:
+		writePage(w, r, s.comments[ckey])
+	case len(seg) == 2 && seg[1] == "diffstat" && r.Method == http.MethodGet:
+		writePage(w, r, s.diffstats[ckey])
+	case len(seg) == 2 && seg[1] == "comments" && r.Method == http.MethodPost:
+		var c bbcloud.Comment
+		if err := json.Unmarshal(body, &c); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+			return
+		}
+		if c.Content == nil || strings.TrimSpace(c.Content.Raw) == "" {
+			writeError(w, http.StatusBadRequest, "content.raw is required")
+			return
+		}
+		now, author := time.Now().UTC(), s.user
+		c.ID, c.User, c.CreatedOn, c.UpdatedOn = 0, &author, now, now
+		c = s.addComment(ckey, c)
+		pr.CommentCount++
+		writeJSON(w, http.StatusCreated, c)
+	case len(seg) == 3 && seg[1] == "comments" && r.Method == http.MethodGet:
+		cid, _ := strconv.Atoi(seg[2])
+		for _, c := range s.comments[ckey] {
+			if c.ID == cid {
+				writeJSON(w, http.StatusOK, c)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "Comment not found")
+	case len(seg) == 2 && (seg[1] == "approve" || seg[1] == "request-changes"):
+		s.serveReview(w, r, pr, seg[1])
+	case len(seg) == 2 && seg[1] == "decline" && r.Method == http.MethodPost:
+		if pr.State != "OPEN" {
+			writeError(w, http.StatusBadRequest, "You can't decline a pull request that is "+strings.ToLower(pr.State)+".")
+			return
+		}
+		pr.State, pr.UpdatedOn = "DECLINED", time.Now().UTC()
+		writeJSON(w, http.StatusOK, pr)
+	default:
+		writeError(w, http.StatusNotFound, "Resource not found")
+	}
+}
+
+// serveReview records or withdraws the user's approval or change request
+func (s *Server) serveReview(w http.ResponseWriter, r *http.Request, pr *bbcloud.PullRequest, action string) {
+	if pr.Author != nil && pr.Author.UUID == s.user.UUID && action == "approve" {
+		writeError(w, http.StatusBadRequest, "You can't approve your own pull request.")
+		return
+	}
+	idx := -1
+	for i, p := range pr.Participants {
+		if p.User != nil && p.User.UUID == s.user.UUID {
+			idx = i
+		}
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		if idx < 0 {
+			user := s.user
+			pr.Participants = append(pr.Participants, bbcloud.Participant{User: &user, Role: "PARTICIPANT"})
+			idx = len(pr.Participants) - 1
+		}
+		p := &pr.Participants[idx]
+		p.ParticipatedOn = time.Now().UTC()
+		if action == "approve" {
+			p.Approved, p.State = true, "approved"
+		} else {
+			p.Approved, p.State = false, "changes_requested"
+		}
+		writeJSON(w, http.StatusOK, p)
+	case http.MethodDelete:
+		if idx >= 0 {
+			pr.Participants[idx].Approved, pr.Participants[idx].State = false, ""
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (s *Server) findRepo(workspace, slug string) *bbcloud.Repository {
+	for i := range s.repos[workspace] {
+		if s.repos[workspace][i].Slug == slug {
+			return &s.repos[workspace][i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) findPR(key string, id int) *bbcloud.PullRequest {
+	for i := range s.prs[key] {
+		if s.prs[key][i].ID == id {
+			return &s.prs[key][i]
+		}
+	}
+	return nil
+}
+
+func repoKey(workspace, repo string) string {
+	return workspace + "/" + repo
+}
+
+func prKey(workspace, repo string, prID int) string {
+	return repoKey(workspace, repo) + "/" + strconv.Itoa(prID)
+}
+
+// writePage writes the page of values selected by the page and pagelen query
+// parameters, linking the next page when there is one
+func writePage[T any](w http.ResponseWriter, r *http.Request, values []T) {
+	q := r.URL.Query()
+	page, _ := strconv.Atoi(q.Get("page"))
+	page = max(page, 1)
+	pageLen, _ := strconv.Atoi(q.Get("pagelen"))
+	if pageLen <= 0 {
+		pageLen = 10
+	}
+
+	start := min((page-1)*pageLen, len(values))
+	end := min(start+pageLen, len(values))
+	resp := struct {
+		bbcloud.PaginatedResponse
+		Values []T `json:"values"`
+	}{
+		PaginatedResponse: bbcloud.PaginatedResponse{Size: len(values), Page: page, PageLen: pageLen},
+		Values:            append([]T{}, values[start:end]...),
+	}
+	if end < len(values) {
+		q.Set("page", strconv.Itoa(page+1))
+		next := *r.URL
+		next.RawQuery = q.Encode()
+		resp.Next = "http://" + r.Host + next.RequestURI()
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error in Bitbucket's format
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{
+		"type":  "error",
+		"error": map[string]string{"message": message},
+	})
+}
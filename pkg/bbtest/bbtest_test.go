@@ -0,0 +1,309 @@
+package bbtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/httpx"
+)
+
+func TestServerFixtures(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer(t)
+	srv.AddRepository("acme", bbcloud.Repository{Slug: "api"})
+	srv.AddRepository("acme", bbcloud.Repository{Slug: "web"})
+	for i := 0; i < 12; i++ {
+		srv.AddPullRequest("acme", "api", bbcloud.PullRequest{Title: "PR"})
+	}
+	srv.AddPullRequest("acme", "api", bbcloud.PullRequest{ID: 40, Title: "Old", State: "MERGED"})
+	srv.AddComment("acme", "api", 1, bbcloud.Comment{Content: &bbcloud.Content{Raw: "first"}})
+	srv.AddPipeline("acme", "api", bbcloud.Pipeline{UUID: "{p1}", BuildNumber: 1})
+	srv.AddPipeline("acme", "api", bbcloud.Pipeline{UUID: "{p2}", BuildNumber: 2})
+
+	client := srv.Client(t, "acme")
+
+	user, err := client.CurrentUser(ctx)
+	if err != nil || user.UUID != DefaultUser.UUID {
+		t.Fatalf("CurrentUser = %+v, %v", user, err)
+	}
+	repos, err := client.ListRepositories(ctx, 0)
+	if err != nil || len(repos) != 2 || repos[0].FullName != "acme/api" {
+		t.Fatalf("ListRepositories = %+v, %v", repos, err)
+	}
+
+	// 12 open PRs span two pages of 10
+	prs, err := client.ListPullRequests(ctx, "api", "OPEN", 0)
+	if err != nil || len(prs) != 12 || prs[11].ID != 12 {
+		t.Fatalf("ListPullRequests = %d PRs, %v", len(prs), err)
+	}
+	if pr, err := client.GetPullRequest(ctx, "api", 40); err != nil || pr.State != "MERGED" {
+		t.Errorf("GetPullRequest(40) = %+v, %v", pr, err)
+	}
+	comments, err := client.ListPRComments(ctx, "api", 1)
+	if err != nil || len(comments) != 1 || comments[0].Content.Raw != "first" {
+		t.Errorf("ListPRComments = %+v, %v", comments, err)
+	}
+	pipelines, err := client.ListPipelines(ctx, "api", 1)
+	if err != nil || len(pipelines) != 1 || pipelines[0].UUID != "{p2}" {
+		t.Errorf("ListPipelines(1) = %+v, %v, want the newest", pipelines, err)
+	}
+
+	if _, err := client.GetPullRequest(ctx, "api", 99); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("GetPullRequest(99) = %v, want 404", err)
+	}
+}
+
+func TestAPIErrors(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer(t)
+	srv.AddRepository("acme", bbcloud.Repository{Slug: "api"})
+	srv.AddPullRequest("acme", "api", bbcloud.PullRequest{Title: "Done", State: "MERGED"})
+	srv.Handle(http.MethodPost, "/repositories/acme/api/pullrequests/1/approve", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusConflict, "This pull request has already been merged.")
+	})
+	client := srv.Client(t, "acme")
+
+	_, err := client.GetPullRequest(ctx, "api", 99)
+	var apiErr *bbcloud.Error
+	if !errors.Is(err, bbcloud.ErrNotFound) || !errors.As(err, &apiErr) {
+		t.Fatalf("GetPullRequest(99) = %v, want a bbcloud.ErrNotFound *Error", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.ErrorInfo.Message != "Pull request 99 not found" {
+		t.Errorf("API error = %+v", apiErr)
+	}
+	if got := apiErr.Error(); got != "404 Not Found: Pull request 99 not found" {
+		t.Errorf("message = %q", got)
+	}
+
+	_, err = client.ApprovePR(ctx, "api", 1)
+	if !errors.Is(err, bbcloud.ErrConflict) || errors.Is(err, bbcloud.ErrNotFound) {
+		t.Errorf("ApprovePR = %v, want only bbcloud.ErrConflict", err)
+	}
+	var statusErr *httpx.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusConflict {
+		t.Errorf("ApprovePR = %v, want it to wrap the httpx.StatusError", err)
+	}
+}
+
+func TestUpdatePRClearsDescription(t *testing.T) {
+	srv := NewServer(t)
+	srv.AddRepository("acme", bbcloud.Repository{Slug: "api"})
+	var bodies []map[string]any
+	srv.Handle(http.MethodPut, "/repositories/acme/api/pullrequests/1", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	})
+	client := srv.Client(t, "acme")
+
+	empty := ""
+	for _, opts := range []bbcloud.UpdatePROptions{{Title: "Only the title"}, {Description: &empty}} {
+		if _, err := client.UpdatePR(context.Background(), "api", 1, opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := bodies[0]["description"]; ok {
+		t.Errorf("title update sent a description: %v", bodies[0])
+	}
+	if d, ok := bodies[1]["description"]; !ok || d != "" {
+		t.Errorf("clearing update = %v, want an empty description", bodies[1])
+	}
+}
+
+func TestDiffStatPages(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer(t)
+	srv.AddRepository("acme", bbcloud.Repository{Slug: "api"})
+	srv.AddPullRequest("acme", "api", bbcloud.PullRequest{Title: "Monster"})
+	for i := 0; i < 25; i++ {
+		srv.AddDiffStat("acme", "api", 1, bbcloud.FileStats{LinesAdded: 1, New: &bbcloud.FileInfo{Path: fmt.Sprintf("f%d.go", i)}})
+	}
+	client := srv.Client(t, "acme")
+	const path = "/repositories/acme/api/pullrequests/1/diffstat"
+
+	// Three pages of 10
+	files, err := client.GetPRDiffStats(ctx, "api", 1)
+	if err != nil || len(files) != 25 || files[24].GetPath() != "f24.go" {
+		t.Fatalf("GetPRDiffStats = %d files, %v", len(files), err)
+	}
+	if n := len(srv.Requested(http.MethodGet, path)); n != 3 {
+		t.Errorf("requests = %d, want 3", n)
+	}
+
+	// The reported size counts the files without reading every page
+	files, total, err := client.ListPRDiffStats(ctx, "api", 1, 12)
+	if err != nil || len(files) != 12 || total != 25 {
+		t.Fatalf("ListPRDiffStats(12) = %d files of %d, %v", len(files), total, err)
+	}
+	if n := len(srv.Requested(http.MethodGet, path)); n != 5 {
+		t.Errorf("requests = %d, want 2 more", n)
+	}
+}
+
+func TestMergeRestrictionsAndConflicts(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer(t)
+	srv.AddRepository("acme", bbcloud.Repository{Slug: "api"})
+	srv.AddPullRequest("acme", "api", bbcloud.PullRequest{
+		Title:       "Release",
+		Destination: &bbcloud.PullRequestBranch{Branch: &bbcloud.Branch{Name: "release/2.0"}},
+	})
+	two := 2
+	srv.AddBranchRestriction("acme", "api", bbcloud.BranchRestriction{Kind: bbcloud.CheckApprovals, Pattern: "release/*", Value: &two})
+	srv.AddBranchRestriction("acme", "api", bbcloud.BranchRestriction{Kind: bbcloud.CheckTasksCompleted, Pattern: "main"})
+	srv.AddBranchRestriction("acme", "api", bbcloud.BranchRestriction{Kind: "push", Pattern: "release/*"})
+	srv.AddBranchRestriction("acme", "api", bbcloud.BranchRestriction{Kind: bbcloud.CheckEnforced, Pattern: "*"})
+	srv.AddDiffStat("acme", "api", 1,
+		bbcloud.FileStats{New: &bbcloud.FileInfo{Path: "go.mod"}, Status: "merge conflict"},
+		bbcloud.FileStats{New: &bbcloud.FileInfo{Path: "main.go"}},
+		bbcloud.FileStats{Old: &bbcloud.FileInfo{Path: "old.go"}, Status: "remote deleted"},
+	)
+	client := srv.Client(t, "acme")
+
+	// Only merge checks whose pattern matches the destination branch
+	checks, err := client.GetPRMergeRestrictions(ctx, "api", 1)
+	if err != nil || len(checks) != 2 || checks[0].Kind != bbcloud.CheckApprovals || checks[1].Kind != bbcloud.CheckEnforced {
+		t.Fatalf("GetPRMergeRestrictions = %+v, %v", checks, err)
+	}
+
+	conflicts, err := client.GetPRConflicts(ctx, "api", 1)
+	if err != nil || strings.Join(conflicts, ",") != "go.mod,old.go" {
+		t.Errorf("GetPRConflicts = %v, %v", conflicts, err)
+	}
+
+	// Branching model restrictions follow the repository's model
+	model := &bbcloud.BranchingModel{
+		Production:  &bbcloud.ModelBranch{Branch: &bbcloud.Branch{Name: "main"}},
+		BranchTypes: []bbcloud.BranchType{{Kind: "release", Prefix: "release/"}},
+	}
+	for _, tt := range []struct {
+		r      bbcloud.BranchRestriction
+		branch string
+		want   bool
+	}{
+		{bbcloud.BranchRestriction{BranchMatchKind: "branching_model", BranchType: "production"}, "main", true},
+		{bbcloud.BranchRestriction{BranchMatchKind: "branching_model", BranchType: "release"}, "release/2.0", true},
+		{bbcloud.BranchRestriction{BranchMatchKind: "branching_model", BranchType: "release"}, "main", false},
+		{bbcloud.BranchRestriction{BranchMatchKind: "glob", Pattern: "feature/?"}, "feature/a", true},
+		{bbcloud.BranchRestriction{BranchMatchKind: "glob", Pattern: "rel.*"}, "release", false},
+	} {
+		if got := tt.r.Matches(tt.branch, model); got != tt.want {
+			t.Errorf("%+v.Matches(%q) = %v, want %v", tt.r, tt.branch, got, tt.want)
+		}
+	}
+}
+
+func TestListPRCommentsPages(t *testing.T) {
+	srv := NewServer(t)
+	srv.AddRepository("acme", bbcloud.Repository{Slug: "api"})
+	srv.AddPullRequest("acme", "api", bbcloud.PullRequest{Title: "Busy"})
+	for i := 0; i < 450; i++ {
+		srv.AddComment("acme", "api", 1, bbcloud.Comment{})
+	}
+
+	// Five pages of 100: the first, then the other four concurrently
+	comments, err := srv.Client(t, "acme").ListPRComments(context.Background(), "api", 1)
+	if err != nil || len(comments) != 450 {
+		t.Fatalf("ListPRComments = %d comments, %v", len(comments), err)
+	}
+	for i, c := range comments {
+		if c.ID != i+1 {
+			t.Fatalf("comments[%d].ID = %d, want page order", i, c.ID)
+		}
+	}
+	requests := srv.Requests()
+	if len(requests) != 5 {
+		t.Errorf("requests = %d, want 5", len(requests))
+	}
+	if q, _ := url.ParseQuery(requests[0].Query); !strings.Contains(q.Get("fields"), "values.content.raw") {
+		t.Errorf("fields = %q, want the mapped comment fields", q.Get("fields"))
+	}
+}
+
+func TestListFollowsCursorLinks(t *testing.T) {
+	srv := NewServer(t)
+	srv.AddRepository("acme", bbcloud.Repository{Slug: "api"})
+	srv.AddPullRequest("acme", "api", bbcloud.PullRequest{Title: "Busy"})
+
+	// Pages linked by an opaque cursor rather than page numbers
+	srv.Handle(http.MethodGet, "/repositories/acme/api/pullrequests/1/tasks", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "" {
+			next := "http://" + r.Host + r.URL.Path + "?cursor=abc"
+			_, _ = w.Write([]byte(`{"values":[{"id":1}],"next":"` + next + `"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"values":[{"id":2}]}`))
+	})
+
+	tasks, err := srv.Client(t, "acme").ListPRTasks(context.Background(), "api", 1)
+	if err != nil || len(tasks) != 2 || tasks[1].ID != 2 {
+		t.Fatalf("ListPRTasks = %+v, %v", tasks, err)
+	}
+	if q := srv.Requests()[1].Query; q != "cursor=abc" {
+		t.Errorf("second request query = %q, want the cursor from next", q)
+	}
+}
+
+func TestServerMutationsAndAssertions(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer(t)
+	srv.AddRepository("acme", bbcloud.Repository{Slug: "api"})
+	client := srv.Client(t, "acme")
+
+	pr, err := client.CreatePR(ctx, "api", bbcloud.CreatePROptions{Title: "Add auth", SourceBranch: "feat/auth"})
+	if err != nil || pr.ID != 1 || pr.State != "OPEN" {
+		t.Fatalf("CreatePR = %+v, %v", pr, err)
+	}
+	if _, err := client.CreateComment(ctx, "api", pr.ID, "LGTM"); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Comments("acme", "api", pr.ID); len(got) != 1 || got[0].User.UUID != DefaultUser.UUID {
+		t.Errorf("Comments = %+v", got)
+	}
+
+	// Authors cannot approve their own PRs
+	if _, err := client.ApprovePR(ctx, "api", pr.ID); err == nil {
+		t.Error("approving own PR succeeded")
+	}
+	srv.SetUser(bbcloud.User{UUID: "{reviewer}", Username: "rev"})
+	if _, err := client.ApprovePR(ctx, "api", pr.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := srv.PullRequest("acme", "api", pr.ID); !got.ApprovedBy("{reviewer}") {
+		t.Errorf("participants = %+v, want approved by {reviewer}", got.Participants)
+	}
+
+	if declined, err := client.DeclinePR(ctx, "api", pr.ID); err != nil || declined.State != "DECLINED" {
+		t.Fatalf("DeclinePR = %+v, %v", declined, err)
+	}
+	if _, err := client.DeclinePR(ctx, "api", pr.ID); err == nil {
+		t.Error("declining a declined PR succeeded")
+	}
+
+	req := srv.AssertRequested(t, http.MethodPost, "/repositories/acme/api/pullrequests/1/comments")
+	if string(req.Body) == "" {
+		t.Error("comment request body not recorded")
+	}
+	srv.AssertNotRequested(t, http.MethodDelete, "/repositories/acme/api/pullrequests/1/approve")
+
+	// Custom handlers take precedence and can inject errors
+	srv.Handle(http.MethodGet, "/repositories/acme/api/pullrequests/1/tasks", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"message":"tasks are disabled"}}`, http.StatusConflict)
+	})
+	srv.Reset()
+	if _, err := client.ListPRTasks(ctx, "api", pr.ID); err == nil || !strings.Contains(err.Error(), "409") {
+		t.Errorf("ListPRTasks = %v, want the injected conflict", err)
+	}
+	if n := len(srv.Requests()); n != 1 {
+		t.Errorf("requests after Reset = %d, want 1", n)
+	}
+}
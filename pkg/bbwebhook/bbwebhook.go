@@ -0,0 +1,181 @@
+// Package bbwebhook receives Bitbucket Cloud webhook deliveries: it verifies
+// their signatures and parses their payloads into pkg/bbcloud types.
+//
+// A receiver either uses Handler:
+//
+//	http.Handle("/hook", bbwebhook.Handler(secret, func(e *bbwebhook.Event) error {
+//		if e.Key == "pullrequest:created" {
+//			log.Printf("#%d %s", e.PullRequest.ID, e.PullRequest.Title)
+//		}
+//		return nil
+//	}))
+//
+// or calls ParseRequest from its own handler. Deliveries are signed when the
+// webhook has a secret: the X-Hub-Signature header carries "sha256=" and the
+// hex HMAC-SHA256 of the body keyed with the secret.
+package bbwebhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+)
+
+// Delivery headers
+const (
+	// SignatureHeader carries the HMAC-SHA256 of the body
+	SignatureHeader = "X-Hub-Signature"
+	// EventHeader names the event, e.g. pullrequest:created
+	EventHeader       = "X-Event-Key"
+	HookUUIDHeader    = "X-Hook-UUID"
+	RequestUUIDHeader = "X-Request-UUID"
+	AttemptHeader     = "X-Attempt-Number"
+)
+
+// MaxBodySize is the largest delivery body ParseRequest accepts
+const MaxBodySize = 10 << 20
+
+var (
+	// ErrNoSignature is returned for an unsigned delivery when a secret is set
+	ErrNoSignature = errors.New("missing " + SignatureHeader + " header")
+	// ErrInvalidSignature is returned when the signature does not match
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// Event is a parsed webhook delivery
+type Event struct {
+	// Key is the event, e.g. repo:push or pullrequest:comment_created
+	Key         string
+	HookUUID    string
+	RequestUUID string
+	// Attempt counts deliveries of this event, starting at 1
+	Attempt int
+
+	Payload
+
+	// Body is the delivery body as received
+	Body []byte
+}
+
+// Payload is the body of a delivery. Each event fills the fields it concerns;
+// the rest are nil.
+type Payload struct {
+	Actor          *bbcloud.User             `json:"actor,omitempty"`
+	Repository     *bbcloud.Repository       `json:"repository,omitempty"`
+	PullRequest    *bbcloud.PullRequest      `json:"pullrequest,omitempty"`
+	Comment        *bbcloud.Comment          `json:"comment,omitempty"`
+	Approval       *bbcloud.ActivityApproval `json:"approval,omitempty"`
+	ChangesRequest *bbcloud.ActivityApproval `json:"changes_request,omitempty"`
+	CommitStatus   *bbcloud.CommitStatus     `json:"commit_status,omitempty"`
+	Push           *Push                     `json:"push,omitempty"`
+}
+
+// Push describes the refs a repo:push changed
+type Push struct {
+	Changes []PushChange `json:"changes"`
+}
+
+// PushChange is the change to one ref. Old is nil for a created ref and New
+// is nil for a deleted one.
+type PushChange struct {
+	Old     *PushRef                  `json:"old,omitempty"`
+	New     *PushRef                  `json:"new,omitempty"`
+	Created bool                      `json:"created"`
+	Closed  bool                      `json:"closed"`
+	Forced  bool                      `json:"forced"`
+	Commits []bbcloud.CommitReference `json:"commits,omitempty"`
+}
+
+// PushRef is a branch or tag and the commit it points at
+type PushRef struct {
+	Type   string                   `json:"type"` // branch or tag
+	Name   string                   `json:"name"`
+	Target *bbcloud.CommitReference `json:"target,omitempty"`
+}
+
+// Sign returns the X-Hub-Signature value of body for secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks that signature is body's X-Hub-Signature for secret
+func Verify(secret string, body []byte, signature string) error {
+	if signature == "" {
+		return ErrNoSignature
+	}
+	if !hmac.Equal([]byte(Sign(secret, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Parse parses the body of a delivery of the event key
+func Parse(key string, body []byte) (*Event, error) {
+	event := &Event{Key: key, Body: body}
+	if err := json.Unmarshal(body, &event.Payload); err != nil {
+		return nil, fmt.Errorf("parse %s payload: %w", key, err)
+	}
+	return event, nil
+}
+
+// ParseRequest reads, verifies and parses a delivery. The signature is only
+// checked when secret is not empty.
+func ParseRequest(r *http.Request, secret string) (*Event, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read delivery: %w", err)
+	}
+	if len(body) > MaxBodySize {
+		return nil, fmt.Errorf("delivery larger than %d bytes", MaxBodySize)
+	}
+	if secret != "" {
+		if err := Verify(secret, body, r.Header.Get(SignatureHeader)); err != nil {
+			return nil, err
+		}
+	}
+
+	event, err := Parse(r.Header.Get(EventHeader), body)
+	if err != nil {
+		return nil, err
+	}
+	event.HookUUID = r.Header.Get(HookUUIDHeader)
+	event.RequestUUID = r.Header.Get(RequestUUIDHeader)
+	event.Attempt, _ = strconv.Atoi(r.Header.Get(AttemptHeader))
+	return event, nil
+}
+
+// Handler returns an HTTP handler that passes every verified delivery to fn.
+// It answers 401 to deliveries with a bad signature, 400 to ones that do not
+// parse and 500 when fn fails, which makes Bitbucket retry; otherwise 204.
+func Handler(secret string, fn func(*Event) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		event, err := ParseRequest(r, secret)
+		switch {
+		case errors.Is(err, ErrNoSignature), errors.Is(err, ErrInvalidSignature):
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := fn(event); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,94 @@
+package bbwebhook
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const commentCreated = `{
+  "actor": {"uuid": "{u1}", "display_name": "Ada"},
+  "repository": {"full_name": "acme/api", "slug": "api"},
+  "pullrequest": {"id": 7, "title": "Fix login", "state": "OPEN"},
+  "comment": {"id": 42, "content": {"raw": "LGTM"}}
+}`
+
+func TestSignature(t *testing.T) {
+	body := []byte(`{"ok":true}`)
+	sig := Sign("s3cret", body)
+	if !strings.HasPrefix(sig, "sha256=") || len(sig) != len("sha256=")+64 {
+		t.Errorf("Sign = %q", sig)
+	}
+	if err := Verify("s3cret", body, sig); err != nil {
+		t.Errorf("own signature: %v", err)
+	}
+	if err := Verify("other", body, sig); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("wrong secret: %v", err)
+	}
+	if err := Verify("s3cret", body, ""); !errors.Is(err, ErrNoSignature) {
+		t.Errorf("missing signature: %v", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	e, err := Parse("pullrequest:comment_created", []byte(commentCreated))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Actor.DisplayName != "Ada" || e.Repository.FullName != "acme/api" ||
+		e.PullRequest.ID != 7 || e.Comment.ID != 42 || e.Comment.Content.Raw != "LGTM" {
+		t.Errorf("event = %+v", e.Payload)
+	}
+	if e.Push != nil || e.Approval != nil {
+		t.Error("fields of other events set")
+	}
+
+	push := `{"push": {"changes": [{"new": {"type": "branch", "name": "main", "target": {"hash": "abc123"}}, "old": null, "created": true}]}}`
+	e, err = Parse("repo:push", []byte(push))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := e.Push.Changes[0]; c.Old != nil || !c.Created || c.New.Name != "main" || c.New.Target.Hash != "abc123" {
+		t.Errorf("push change = %+v", c)
+	}
+
+	if _, err := Parse("repo:push", []byte("not json")); err == nil {
+		t.Error("invalid payload parsed")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var got *Event
+	h := Handler("s3cret", func(e *Event) error {
+		got = e
+		return nil
+	})
+	deliver := func(sig string) int {
+		req := httptest.NewRequest(http.MethodPost, "/hook", strings.NewReader(commentCreated))
+		req.Header.Set(EventHeader, "pullrequest:comment_created")
+		req.Header.Set(RequestUUIDHeader, "{r1}")
+		req.Header.Set(AttemptHeader, "2")
+		if sig != "" {
+			req.Header.Set(SignatureHeader, sig)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := deliver(""); code != http.StatusUnauthorized || got != nil {
+		t.Errorf("unsigned delivery: status %d", code)
+	}
+	if code := deliver("sha256=bad"); code != http.StatusUnauthorized || got != nil {
+		t.Errorf("badly signed delivery: status %d", code)
+	}
+	if code := deliver(Sign("s3cret", []byte(commentCreated))); code != http.StatusNoContent {
+		t.Fatalf("signed delivery: status %d", code)
+	}
+	if got.Key != "pullrequest:comment_created" || got.RequestUUID != "{r1}" || got.Attempt != 2 ||
+		got.Comment.ID != 42 || string(got.Body) != commentCreated {
+		t.Errorf("event = %+v", got)
+	}
+}
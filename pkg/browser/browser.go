@@ -0,0 +1,61 @@
+package browser
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const envBrowser = "BROWSER"
+
+// Browser opens URLs in the user's web browser
+type Browser interface {
+	Browse(url string) error
+}
+
+// New creates a Browser that uses launcher (or $BROWSER, or the platform default) to open URLs
+func New(launcher string) Browser {
+	if launcher == "" {
+		launcher = strings.TrimSpace(os.Getenv(envBrowser))
+	}
+	return &systemBrowser{launcher: launcher}
+}
+
+type systemBrowser struct {
+	launcher string
+}
+
+// Browse starts the browser process without waiting for it to exit
+func (b *systemBrowser) Browse(url string) error {
+	cmd, err := b.command(url)
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("open browser: %w", err)
+	}
+	// Reap the child in the background so it doesn't linger as a zombie
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
+func (b *systemBrowser) command(url string) (*exec.Cmd, error) {
+	if b.launcher != "" {
+		args := strings.Fields(b.launcher)
+		return exec.Command(args[0], append(args[1:], url)...), nil
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", url), nil
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url), nil
+	default:
+		if _, err := exec.LookPath("xdg-open"); err != nil {
+			return nil, fmt.Errorf("no browser found (set %s or use --no-browser)", envBrowser)
+		}
+		return exec.Command("xdg-open", url), nil
+	}
+}
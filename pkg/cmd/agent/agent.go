@@ -0,0 +1,176 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/internal/agent"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// startTimeout bounds the wait for a detached agent to listen
+const startTimeout = 3 * time.Second
+
+// NewCmdAgent creates the agent command group
+func NewCmdAgent(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "agent",
+		Short: "Run a session agent that caches credentials",
+		Long: `Manage the session agent, a background process that keeps the credentials
+read from the keyring in memory for a short time. With the agent setting on
+('bbc config set agent true') or BB_AGENT_SOCKET set, bbc asks the agent
+before the keyring, so keyrings that prompt on every access prompt once per
+session. Otherwise bbc never contacts an agent.
+
+The agent listens on a unix socket only you can access
+($XDG_RUNTIME_DIR/bb/agent.sock, or BB_AGENT_SOCKET). bbc only talks to it
+when the socket's directory is yours with mode 700 and the agent runs as you;
+an existing directory is never loosened or tightened for you. Each entry expires
+after --ttl, and the agent exits once it was not used for that long.
+Logging in again replaces the entry.`,
+	}
+
+	cmd.AddCommand(newCmdStart(f))
+	cmd.AddCommand(newCmdStop(f))
+	cmd.AddCommand(newCmdStatus(f))
+
+	return cmd
+}
+
+func newCmdStart(f *cmdutil.Factory) *cobra.Command {
+	var ttl time.Duration
+	var foreground bool
+
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start the session agent in the background",
+		Long: `Start the session agent in the background and print its status. Starting
+an agent when one is running prints the running agent's status.
+
+With --foreground the agent runs in this process until interrupted.
+
+Examples:
+  bbc agent start
+  bbc agent start --ttl 1h`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if ttl <= 0 {
+				return fmt.Errorf("--ttl must be positive")
+			}
+			if foreground {
+				return serve(cmd, ttl)
+			}
+			return start(f, ttl)
+		},
+	}
+
+	cmd.Flags().DurationVar(&ttl, "ttl", agent.DefaultTTL, "How long credentials are kept and an idle agent runs")
+	cmd.Flags().BoolVar(&foreground, "foreground", false, "Run the agent in this process")
+
+	return cmd
+}
+
+// serve runs the agent until the context ends or it stops itself
+func serve(cmd *cobra.Command, ttl time.Duration) error {
+	// Outlive the terminal the agent was started from
+	signal.Ignore(syscall.SIGHUP)
+
+	path := agent.SocketPath()
+	ln, err := agent.Listen(path)
+	if err != nil {
+		return fmt.Errorf("start agent: %w", err)
+	}
+	defer func() { _ = os.Remove(path) }()
+	return agent.NewServer(ttl).Serve(cmd.Context(), ln)
+}
+
+// start runs the agent detached and waits until it answers
+func start(f *cmdutil.Factory, ttl time.Duration) error {
+	if !f.UseAgent() {
+		_, _ = fmt.Fprintln(f.IOStreams.ErrOut, "note: commands only use the agent with 'bbc config set agent true' or BB_AGENT_SOCKET set")
+	}
+	if status, err := agent.GetStatus(); err == nil {
+		return cmdutil.WriteJSON(f.IOStreams.Out, status)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("start agent: %w", err)
+	}
+	child := exec.Command(exe, "agent", "start", "--foreground", "--ttl", ttl.String())
+	if err := child.Start(); err != nil {
+		return fmt.Errorf("start agent: %w", err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- child.Wait() }()
+
+	deadline := time.Now().Add(startTimeout)
+	for time.Now().Before(deadline) {
+		if status, err := agent.GetStatus(); err == nil {
+			return cmdutil.WriteJSON(f.IOStreams.Out, status)
+		}
+		select {
+		case err := <-exited:
+			return fmt.Errorf("agent exited at startup: %v; run 'bbc agent start --foreground' to see why", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	return fmt.Errorf("agent did not listen on %s within %s", agent.SocketPath(), startTimeout)
+}
+
+func newCmdStop(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "stop",
+		Short: "Stop the session agent",
+		Long: `Stop the session agent, dropping the credentials it holds.
+
+Examples:
+  bbc agent stop`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := agent.Stop()
+			if err != nil && !errors.Is(err, agent.ErrNotRunning) {
+				return err
+			}
+			return cmdutil.WriteJSON(f.IOStreams.Out, map[string]interface{}{
+				"stopped": err == nil,
+			})
+		},
+	}
+}
+
+func newCmdStatus(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show whether the session agent runs",
+		Long: `Show whether the session agent runs, with its PID, socket, TTL and the
+number of credentials it holds. The exit status is 1 if it is not running.
+
+Examples:
+  bbc agent status`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status, err := agent.GetStatus()
+			if errors.Is(err, agent.ErrNotRunning) {
+				if err := cmdutil.WriteJSON(f.IOStreams.Out, map[string]interface{}{
+					"running": false,
+					"socket":  agent.SocketPath(),
+				}); err != nil {
+					return err
+				}
+				return cmdutil.NewExitError(1, "")
+			}
+			if err != nil {
+				return err
+			}
+			return cmdutil.WriteJSON(f.IOStreams.Out, status)
+		},
+	}
+}
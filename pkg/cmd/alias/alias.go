@@ -0,0 +1,45 @@
+package alias
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// NewCmdAlias creates the alias command group
+func NewCmdAlias(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alias <command>",
+		Short: "Create command shortcuts",
+		Long: `Create shortcuts for bbc commands.
+
+Aliases are stored under the "aliases" key of the user config. An alias is
+expanded when it is used as the first argument to bbc:
+
+  $1, $2, ...  are replaced with the arguments given to the alias; without
+               placeholders, arguments are appended to the expansion
+  !command     runs the expansion with sh, passing arguments as "$@"`,
+	}
+
+	cmd.AddCommand(NewCmdSet(f))
+	cmd.AddCommand(NewCmdList(f))
+	cmd.AddCommand(NewCmdDelete(f))
+
+	return cmd
+}
+
+// configKey returns the config path of an alias
+func configKey(name string) string {
+	return "aliases." + name
+}
+
+// validName rejects names that cannot be stored as a single config key or typed as one argument
+func validName(name string) error {
+	if name == "" || strings.ContainsAny(name, ". \t\n") || strings.HasPrefix(name, "-") {
+		return fmt.Errorf("invalid alias name %q", name)
+	}
+	return nil
+}
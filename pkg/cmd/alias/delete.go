@@ -0,0 +1,41 @@
+package alias
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/internal/config"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// NewCmdDelete creates the alias delete command
+func NewCmdDelete(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <alias>",
+		Short: "Delete an alias",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			cfg, err := config.Load(config.DefaultPath())
+			if err != nil {
+				return err
+			}
+			expansion, ok := cfg.Aliases()[name]
+			if !ok {
+				return fmt.Errorf("no such alias: %s", name)
+			}
+			cfg.Unset(configKey(name))
+			if err := cfg.Save(); err != nil {
+				return err
+			}
+
+			return cmdutil.WriteJSON(f.IOStreams.Out, map[string]interface{}{
+				"alias":     name,
+				"expansion": expansion,
+				"deleted":   true,
+			})
+		},
+	}
+}
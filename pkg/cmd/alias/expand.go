@@ -0,0 +1,121 @@
+package alias
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var placeholderRE = regexp.MustCompile(`\$(\d+)`)
+
+// ExpandAlias expands args[0] using aliases. Positional placeholders ($1, $2, ...)
+// in the expansion are replaced with the matching arguments, and the arguments
+// no placeholder used are appended, as gh does.
+//
+// Shell aliases (expansion starting with "!") are returned as a command line for
+// sh -c with the arguments passed as positional parameters, and isShell set.
+func ExpandAlias(aliases map[string]string, args []string) (expanded []string, isShell bool, err error) {
+	if len(args) == 0 {
+		return nil, false, fmt.Errorf("no command given")
+	}
+	expansion, ok := aliases[args[0]]
+	if !ok {
+		return nil, false, fmt.Errorf("unknown alias %q", args[0])
+	}
+	rest := args[1:]
+
+	if script, ok := strings.CutPrefix(expansion, "!"); ok {
+		expanded = []string{"-c", script}
+		if len(rest) > 0 {
+			expanded = append(expanded, "--")
+			expanded = append(expanded, rest...)
+		}
+		return expanded, true, nil
+	}
+
+	words, err := splitArgs(expansion)
+	if err != nil {
+		return nil, false, fmt.Errorf("alias %s: %w", args[0], err)
+	}
+
+	if !placeholderRE.MatchString(expansion) {
+		return append(words, rest...), false, nil
+	}
+
+	var missing error
+	used := make([]bool, len(rest))
+	for _, w := range words {
+		w = placeholderRE.ReplaceAllStringFunc(w, func(p string) string {
+			n, _ := strconv.Atoi(p[1:])
+			if n < 1 || n > len(rest) {
+				missing = fmt.Errorf("not enough arguments for alias %s: %s", args[0], expansion)
+				return p
+			}
+			used[n-1] = true
+			return rest[n-1]
+		})
+		expanded = append(expanded, w)
+	}
+	if missing != nil {
+		return nil, false, missing
+	}
+	for i, arg := range rest {
+		if !used[i] {
+			expanded = append(expanded, arg)
+		}
+	}
+	return expanded, false, nil
+}
+
+// splitArgs splits s into words like a POSIX shell, honouring single quotes,
+// double quotes, and backslash escapes. No other expansion is performed.
+func splitArgs(s string) ([]string, error) {
+	var (
+		words   []string
+		cur     strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,68 @@
+package alias
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpandAlias(t *testing.T) {
+	aliases := map[string]string{
+		"co":   "review checkout",
+		"pr":   "review checkout $1",
+		"prs":  "review list --repo $1 --state MERGED",
+		"sh":   "!bbc review list \"$@\"",
+		"todo": `review comment $1 --repo $2 "needs follow-up"`,
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		isShell bool
+		wantErr bool
+	}{
+		{name: "append args", args: []string{"co", "450", "--repo", "r"}, want: []string{"review", "checkout", "450", "--repo", "r"}},
+		{name: "placeholder", args: []string{"prs", "my repo"}, want: []string{"review", "list", "--repo", "my repo", "--state", "MERGED"}},
+		{name: "unused args appended", args: []string{"pr", "12", "--repo", "x"}, want: []string{"review", "checkout", "12", "--repo", "x"}},
+		{name: "quoted expansion", args: []string{"todo", "7", "r"}, want: []string{"review", "comment", "7", "--repo", "r", "needs follow-up"}},
+		{name: "missing placeholder arg", args: []string{"prs"}, wantErr: true},
+		{name: "shell", args: []string{"sh", "--json"}, want: []string{"-c", `bbc review list "$@"`, "--", "--json"}, isShell: true},
+		{name: "unknown", args: []string{"nope"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, isShell, err := ExpandAlias(aliases, tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if isShell != tt.isShell {
+				t.Errorf("isShell = %v, want %v", isShell, tt.isShell)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	got, err := splitArgs(`a 'b c' "d \"e\"" f\ g`)
+	if err != nil {
+		t.Fatalf("splitArgs: %v", err)
+	}
+	want := []string{"a", "b c", `d "e"`, "f g"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := splitArgs(`"unterminated`); err == nil {
+		t.Error("expected error for unterminated quote")
+	}
+}
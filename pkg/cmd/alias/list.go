@@ -0,0 +1,57 @@
+package alias
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+type listOptions struct {
+	json bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdList creates the alias list command
+func NewCmdList(f *cmdutil.Factory) *cobra.Command {
+	opts := &listOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List aliases",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := opts.factory.UserConfig()
+			if err != nil {
+				return err
+			}
+			aliases := cfg.Aliases()
+
+			ios, _ := opts.factory.Streams()
+			if opts.json {
+				return cmdutil.WriteJSON(ios.Out, aliases)
+			}
+
+			if len(aliases) == 0 {
+				_, _ = fmt.Fprintln(ios.ErrOut, "no aliases configured")
+				return nil
+			}
+			names := make([]string, 0, len(aliases))
+			for name := range aliases {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				_, _ = fmt.Fprintf(ios.Out, "%s: %s\n", name, aliases[name])
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of text")
+
+	return cmd
+}
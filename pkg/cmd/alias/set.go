@@ -0,0 +1,85 @@
+package alias
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/internal/config"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+type setOptions struct {
+	name      string
+	expansion string
+	shell     bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdSet creates the alias set command
+func NewCmdSet(f *cmdutil.Factory) *cobra.Command {
+	opts := &setOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "set <alias> <expansion>",
+		Short: "Create or replace an alias",
+		Long: `Create or replace an alias.
+
+The alias name may not shadow a built-in command, and the expansion must start
+with a valid bbc command unless it is a shell alias (--shell, or an expansion
+beginning with "!").
+
+Examples:
+  # bbc co 450 --repo test_repo  →  bbc review checkout 450 --repo test_repo
+  bbc alias set co "review checkout"
+
+  # bbc prs test_repo --json  →  bbc review list --repo test_repo --state MERGED --json
+  # (arguments no $N placeholder uses are appended)
+  bbc alias set prs 'review list --repo $1 --state MERGED'
+
+  # Shell alias, arguments available as "$@"
+  bbc alias set mine --shell 'bbc review list "$@" --json | jq ".prs[]"'`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name, opts.expansion = args[0], args[1]
+			if opts.shell {
+				opts.expansion = "!" + opts.expansion
+			}
+
+			if err := validName(opts.name); err != nil {
+				return err
+			}
+			if found, _, err := cmd.Root().Find([]string{opts.name}); err == nil && found != cmd.Root() {
+				return fmt.Errorf("%q is already a bbc command", opts.name)
+			}
+			if opts.expansion[0] != '!' {
+				words, err := splitArgs(opts.expansion)
+				if err != nil {
+					return err
+				}
+				if found, _, err := cmd.Root().Find(words); err != nil || found == cmd.Root() {
+					return fmt.Errorf("expansion does not start with a bbc command: %s", opts.expansion)
+				}
+			}
+
+			cfg, err := config.Load(config.DefaultPath())
+			if err != nil {
+				return err
+			}
+			cfg.Set(configKey(opts.name), opts.expansion)
+			if err := cfg.Save(); err != nil {
+				return err
+			}
+
+			return cmdutil.WriteJSON(opts.factory.IOStreams.Out, map[string]interface{}{
+				"alias":     opts.name,
+				"expansion": opts.expansion,
+			})
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.shell, "shell", "s", false, "Run the expansion with sh")
+
+	return cmd
+}
@@ -0,0 +1,366 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/itchyny/gojq"
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+type apiOptions struct {
+	path      string
+	method    string
+	rawFields []string
+	fields    []string
+	headers   []string
+	input     string
+	paginate  bool
+	jq        string
+	template  string
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdAPI creates the api command
+func NewCmdAPI(f *cmdutil.Factory) *cobra.Command {
+	opts := &apiOptions{
+		factory: f,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "api <path>",
+		Short: "Make an authenticated Bitbucket API request",
+		Long: `Make an authenticated request to the Bitbucket Cloud API and print the
+response, for endpoints bbc does not wrap yet.
+
+The path is relative to the API root (https://api.bitbucket.org/2.0), or a
+full URL on the API host. {workspace} is replaced with the current
+workspace and {repo} with the default repository.
+
+-f key=value adds a string field and -F key=value a typed one: true, false,
+null and integers are sent as JSON values, and @file reads the value from a
+file. Fields become query parameters of a GET request and a JSON body
+otherwise. --input sends a file (or "-" for stdin) as the request body.
+The method defaults to GET, or POST when fields or --input are given.
+
+--paginate follows the "next" links of a paginated response and prints the
+"values" of every page as one JSON array. --jq filters the response with a
+jq expression and --template formats it with a Go template.
+
+Example:
+  bbc api /user
+  bbc api '/repositories/{workspace}?q=language="go"' --paginate --jq '.[].slug'
+  bbc api '/repositories/{workspace}/{repo}/pullrequests' -f state=MERGED --jq '.values[].title'
+  bbc api -X POST '/repositories/{workspace}/{repo}/pullrequests/7/comments' --input comment.json
+  bbc api /user --template '{{.display_name}} ({{.account_id}})'`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.path = args[0]
+			if opts.jq != "" && opts.template != "" {
+				return fmt.Errorf("--jq and --template cannot be used together")
+			}
+			workspace, _ := cmd.Flags().GetString("workspace")
+			return runAPI(cmd.Context(), opts, workspace, cmd.Flags().Changed("method"))
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.method, "method", "X", http.MethodGet, "HTTP method")
+	cmd.Flags().StringArrayVarP(&opts.rawFields, "raw-field", "f", nil, "Add a string field in key=value format")
+	cmd.Flags().StringArrayVarP(&opts.fields, "field", "F", nil, "Add a typed field in key=value format (@file reads a file)")
+	cmd.Flags().StringArrayVarP(&opts.headers, "header", "H", nil, "Add a request header in key:value format")
+	cmd.Flags().StringVar(&opts.input, "input", "", "File to send as the request body (\"-\" for stdin)")
+	cmd.Flags().BoolVar(&opts.paginate, "paginate", false, "Fetch every page and print their values as one array")
+	cmd.Flags().StringVarP(&opts.jq, "jq", "q", "", "Filter the response with a jq expression")
+	cmd.Flags().StringVarP(&opts.template, "template", "t", "", "Format the response with a Go template")
+
+	cmd.AddCommand(NewCmdRateLimit(f))
+
+	return cmd
+}
+
+func runAPI(ctx context.Context, opts *apiOptions, workspace string, methodSet bool) error {
+	client, err := opts.factory.NewBBCloudClient(workspace)
+	if err != nil {
+		return err
+	}
+
+	path, err := fillPlaceholders(opts.path, client.Workspace(), func() (string, error) {
+		return defaultRepo(opts.factory, client.Workspace())
+	})
+	if err != nil {
+		return err
+	}
+
+	params, err := parseFields(opts.rawFields, opts.fields, opts.factory.IOStreams.In)
+	if err != nil {
+		return err
+	}
+
+	method := strings.ToUpper(opts.method)
+	if !methodSet && (len(params) > 0 || opts.input != "") {
+		method = http.MethodPost
+	}
+	if opts.paginate && method != http.MethodGet {
+		return fmt.Errorf("--paginate only works with GET requests")
+	}
+
+	var body any
+	switch {
+	case opts.input != "":
+		data, err := readInput(opts.input, opts.factory.IOStreams.In)
+		if err != nil {
+			return err
+		}
+		body = json.RawMessage(data)
+		// The body is taken, so fields go in the query string
+		path = addQuery(path, params)
+	case method == http.MethodGet || method == http.MethodDelete:
+		path = addQuery(path, params)
+	case len(params) > 0:
+		body = params
+	}
+
+	var response []byte
+	if opts.paginate {
+		response, err = fetchAll(ctx, client, path, opts.headers)
+	} else {
+		response, err = request(ctx, client, method, path, body, opts.headers)
+	}
+	if err != nil {
+		return err
+	}
+
+	return writeResponse(opts.factory.IOStreams.Out, response, opts.jq, opts.template)
+}
+
+// request sends one API request and returns the raw response body
+func request(ctx context.Context, client *bbcloud.Client, method, path string, body any, headers []string) ([]byte, error) {
+	req, err := client.NewRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	// Full URLs (and next links) must stay on the API host, or the stored
+	// credentials would be sent elsewhere
+	if base := client.HTTP().BaseURL(); !strings.EqualFold(req.URL.Host, base.Host) || req.URL.Scheme != base.Scheme {
+		return nil, fmt.Errorf("refusing to send credentials to %s://%s (the API is at %s://%s)", req.URL.Scheme, req.URL.Host, base.Scheme, base.Host)
+	}
+	for _, h := range headers {
+		key, value, ok := strings.Cut(h, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid header %q (use key:value)", h)
+		}
+		req.Header.Set(strings.TrimSpace(key), strings.TrimSpace(value))
+	}
+
+	var buf bytes.Buffer
+	if err := client.Do(req, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// fetchAll follows the next links from path and returns every page's values
+// as one JSON array
+func fetchAll(ctx context.Context, client *bbcloud.Client, path string, headers []string) ([]byte, error) {
+	values := []json.RawMessage{}
+	for path != "" {
+		data, err := request(ctx, client, http.MethodGet, path, nil, headers)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Values []json.RawMessage `json:"values"`
+			Next   string            `json:"next"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, fmt.Errorf("--paginate needs a paginated JSON response: %w", err)
+		}
+		values = append(values, page.Values...)
+		path = page.Next
+	}
+	return json.Marshal(values)
+}
+
+// writeResponse prints the response indented when it is JSON, or filtered
+// by a jq expression or Go template
+func writeResponse(w io.Writer, response []byte, jq, tmpl string) error {
+	if jq == "" && tmpl == "" {
+		var indented bytes.Buffer
+		if json.Indent(&indented, response, "", "  ") != nil {
+			// Not JSON (e.g. a diff): print as is
+			_, err := w.Write(response)
+			return err
+		}
+		indented.WriteByte('\n')
+		_, err := indented.WriteTo(w)
+		return err
+	}
+
+	var data any
+	if err := json.Unmarshal(response, &data); err != nil {
+		return fmt.Errorf("response is not JSON: %w", err)
+	}
+
+	if tmpl != "" {
+		t, err := template.New("api").Parse(tmpl)
+		if err != nil {
+			return fmt.Errorf("parse template: %w", err)
+		}
+		if err := t.Execute(w, data); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w)
+		return err
+	}
+
+	query, err := gojq.Parse(jq)
+	if err != nil {
+		return fmt.Errorf("parse jq expression: %w", err)
+	}
+	iter := query.Run(data)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			return nil
+		}
+		if err, ok := v.(error); ok {
+			return fmt.Errorf("jq: %w", err)
+		}
+		// Strings print raw, like jq -r
+		if s, ok := v.(string); ok {
+			_, _ = fmt.Fprintln(w, s)
+			continue
+		}
+		out, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(w, string(out))
+	}
+}
+
+// fillPlaceholders replaces {workspace} and {repo} in path; repo is only
+// looked up when the path uses it
+func fillPlaceholders(path, workspace string, repo func() (string, error)) (string, error) {
+	if strings.Contains(path, "{workspace}") {
+		if workspace == "" {
+			return "", fmt.Errorf("{workspace} used but no workspace is configured")
+		}
+		path = strings.ReplaceAll(path, "{workspace}", url.PathEscape(workspace))
+	}
+	if strings.Contains(path, "{repo}") {
+		slug, err := repo()
+		if err != nil {
+			return "", err
+		}
+		path = strings.ReplaceAll(path, "{repo}", url.PathEscape(slug))
+	}
+	return path, nil
+}
+
+// defaultRepo returns the repository {repo} stands for: the workspace's
+// default repo, then default_repo
+func defaultRepo(f *cmdutil.Factory, workspace string) (string, error) {
+	cfg, err := f.Config()
+	if err != nil {
+		return "", err
+	}
+	repo := cfg.WorkspaceRepo(workspace)
+	if repo == "" {
+		repo = cfg.Repo()
+	}
+	if repo == "" {
+		return "", fmt.Errorf("{repo} used but no default repository is set ('bbc config set default_repo <repo>')")
+	}
+	return repo, nil
+}
+
+// parseFields turns -f and -F flags into request parameters. -F values
+// true, false, null and integers become JSON values, and @file reads the
+// value from a file ("@-" for stdin).
+func parseFields(rawFields, fields []string, stdin io.Reader) (map[string]any, error) {
+	params := make(map[string]any)
+	for _, f := range rawFields {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid field %q (use key=value)", f)
+		}
+		params[key] = value
+	}
+	for _, f := range fields {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid field %q (use key=value)", f)
+		}
+		switch {
+		case value == "true":
+			params[key] = true
+		case value == "false":
+			params[key] = false
+		case value == "null":
+			params[key] = nil
+		case strings.HasPrefix(value, "@"):
+			data, err := readInput(value[1:], stdin)
+			if err != nil {
+				return nil, err
+			}
+			params[key] = string(data)
+		default:
+			if n, err := strconv.Atoi(value); err == nil {
+				params[key] = n
+			} else {
+				params[key] = value
+			}
+		}
+	}
+	return params, nil
+}
+
+// addQuery appends params to path's query string
+func addQuery(path string, params map[string]any) string {
+	if len(params) == 0 {
+		return path
+	}
+	q := url.Values{}
+	for key, value := range params {
+		if value == nil {
+			q.Add(key, "")
+			continue
+		}
+		q.Add(key, fmt.Sprint(value))
+	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + q.Encode()
+}
+
+// readInput reads a file, or stdin for "-"
+func readInput(name string, stdin io.Reader) ([]byte, error) {
+	if name == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("read stdin: %w", err)
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", name, err)
+	}
+	return data, nil
+}
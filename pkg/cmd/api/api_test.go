@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/bbtest"
+)
+
+func TestParseFields(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "body.md")
+	if err := os.WriteFile(file, []byte("from file"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	params, err := parseFields(
+		[]string{"state=OPEN", "n=7"},
+		[]string{"draft=true", "count=3", "parent=null", "title=x=y", "body=@" + file},
+		strings.NewReader(""),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"state": "OPEN", "n": "7", // -f keeps strings
+		"draft": true, "count": 3, "parent": nil, "title": "x=y", "body": "from file",
+	}
+	if fmt.Sprint(params) != fmt.Sprint(want) {
+		t.Errorf("parseFields = %v, want %v", params, want)
+	}
+
+	if _, err := parseFields([]string{"novalue"}, nil, nil); err == nil {
+		t.Error("field without = accepted")
+	}
+}
+
+func TestAddQuery(t *testing.T) {
+	if got := addQuery("/user", nil); got != "/user" {
+		t.Errorf("addQuery without params = %q", got)
+	}
+	if got := addQuery("/repositories/ws?pagelen=5", map[string]any{"q": `name ~ "a"`}); got != "/repositories/ws?pagelen=5&q=name+~+%22a%22" {
+		t.Errorf("addQuery = %q", got)
+	}
+}
+
+func TestFillPlaceholders(t *testing.T) {
+	repo := func() (string, error) { return "api", nil }
+
+	got, err := fillPlaceholders("/repositories/{workspace}/{repo}/pullrequests", "acme", repo)
+	if err != nil || got != "/repositories/acme/api/pullrequests" {
+		t.Errorf("fillPlaceholders = %q, %v", got, err)
+	}
+
+	noRepo := func() (string, error) { return "", fmt.Errorf("no default repository") }
+	if got, err := fillPlaceholders("/user", "", noRepo); err != nil || got != "/user" {
+		t.Errorf("path without placeholders = %q, %v", got, err)
+	}
+	if _, err := fillPlaceholders("/repositories/{workspace}", "", repo); err == nil {
+		t.Error("{workspace} without a workspace accepted")
+	}
+}
+
+func TestWriteResponse(t *testing.T) {
+	response := []byte(`{"values":[{"slug":"api","size":2},{"slug":"web","size":5}]}`)
+
+	tests := []struct {
+		name, jq, tmpl string
+		want           string
+	}{
+		{"indented", "", "", "{\n  \"values\": [\n    {\n      \"slug\": \"api\",\n      \"size\": 2\n    },\n    {\n      \"slug\": \"web\",\n      \"size\": 5\n    }\n  ]\n}\n"},
+		{"jq strings raw", ".values[].slug", "", "api\nweb\n"},
+		{"jq values", "[.values[].size] | add", "", "7\n"},
+		{"template", "", "{{range .values}}{{.slug}} {{end}}", "api web \n"},
+	}
+	for _, tt := range tests {
+		var out strings.Builder
+		if err := writeResponse(&out, response, tt.jq, tt.tmpl); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if out.String() != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, out.String(), tt.want)
+		}
+	}
+
+	var out strings.Builder
+	if err := writeResponse(&out, []byte("diff --git a/x b/x\n"), "", ""); err != nil || out.String() != "diff --git a/x b/x\n" {
+		t.Errorf("non-JSON response = %q, %v", out.String(), err)
+	}
+	if err := writeResponse(&out, response, ".values[", ""); err == nil {
+		t.Error("invalid jq expression accepted")
+	}
+}
+
+func TestRequestStaysOnAPIHost(t *testing.T) {
+	srv := bbtest.NewServer(t)
+	client := srv.Client(t, "acme")
+	srv.Handle(http.MethodGet, "/repositories/acme", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"values":[{"slug":"api"}],"next":"https://evil.example/2.0/repositories/acme?page=2"}`)
+	})
+
+	if _, err := request(context.Background(), client, http.MethodGet, srv.BaseURL()+"/repositories/acme", nil, nil); err != nil {
+		t.Fatalf("full URL on the API host: %v", err)
+	}
+	if _, err := request(context.Background(), client, http.MethodGet, "https://evil.example/2.0/user", nil, nil); err == nil {
+		t.Error("full URL on another host accepted")
+	}
+	if _, err := fetchAll(context.Background(), client, "/repositories/acme", nil); err == nil {
+		t.Error("--paginate followed a next link to another host")
+	}
+	if got := len(srv.Requests()); got != 2 {
+		t.Errorf("sent %d requests, want 2 (none to another host)", got)
+	}
+}
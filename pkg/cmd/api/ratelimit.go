@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// NewCmdRateLimit creates the api rate-limit command
+func NewCmdRateLimit(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rate-limit",
+		Short: "Show the API rate limit status",
+		Long: `Make one cheap request (GET /user) and print the rate limit the API reported
+with it, as JSON: the request budget, what is left of it, and when it resets.
+
+Bitbucket only sends rate limit headers on some responses; "reported" is
+false when this one carried none.
+
+Example:
+  bbc api rate-limit
+  bbc api rate-limit | jq .remaining`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := f.NewBBCloudClient("")
+			if err != nil {
+				return err
+			}
+			status, err := rateLimitStatus(cmd.Context(), client, time.Now())
+			if err != nil {
+				return err
+			}
+			return cmdutil.WriteJSON(f.IOStreams.Out, status)
+		},
+	}
+}
+
+type rateLimitOutput struct {
+	Reported  bool   `json:"reported"` // whether the response had rate limit headers
+	Limit     int    `json:"limit"`
+	Remaining int    `json:"remaining"`
+	Reset     string `json:"reset,omitempty"`    // RFC 3339
+	ResetIn   int    `json:"reset_in,omitempty"` // seconds until Reset
+	Source    string `json:"source,omitempty"`   // "bitbucket" or "atlassian" headers
+}
+
+// rateLimitStatus requests the current user and returns the rate limit the
+// response reported
+func rateLimitStatus(ctx context.Context, client *bbcloud.Client, now time.Time) (rateLimitOutput, error) {
+	if _, err := client.CurrentUser(ctx); err != nil {
+		return rateLimitOutput{}, err
+	}
+	rl := client.RateLimit()
+	out := rateLimitOutput{
+		Reported:  rl.Source != "",
+		Limit:     rl.Limit,
+		Remaining: rl.Remaining,
+		Source:    rl.Source,
+	}
+	if !rl.Reset.IsZero() {
+		out.Reset = rl.Reset.UTC().Format(time.RFC3339)
+		out.ResetIn = max(int(rl.Reset.Sub(now).Seconds()), 0)
+	}
+	return out, nil
+}
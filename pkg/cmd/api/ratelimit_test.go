@@ -0,0 +1,40 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ghoseb/bb/pkg/bbtest"
+)
+
+func TestRateLimitStatus(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	srv := bbtest.NewServer(t)
+	client := srv.Client(t, "acme")
+
+	// No headers: nothing reported
+	out, err := rateLimitStatus(context.Background(), client, now)
+	if err != nil || out.Reported {
+		t.Fatalf("without headers = %+v, %v", out, err)
+	}
+
+	srv.Handle(http.MethodGet, "/user", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-RateLimit-Limit", "1000")
+		w.Header().Set("X-RateLimit-Remaining", "998")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(90*time.Second).Unix(), 10))
+		_ = json.NewEncoder(w).Encode(bbtest.DefaultUser)
+	})
+	out, err = rateLimitStatus(context.Background(), client, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := rateLimitOutput{Reported: true, Limit: 1000, Remaining: 998, Reset: "2026-05-01T12:01:30Z", ResetIn: 90, Source: "bitbucket"}
+	if out != want {
+		t.Errorf("status = %+v, want %+v", out, want)
+	}
+}
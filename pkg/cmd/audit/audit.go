@@ -0,0 +1,26 @@
+package audit
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// NewCmdAudit creates the audit command group
+func NewCmdAudit(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit <command>",
+		Short: "Review the changes bbc has made",
+		Long: `Every successful API request that changes something (a comment posted, a PR
+approved or merged, a branch deleted) is appended to a local audit log with
+its time, the command that made it, and the IDs in the response. Requests
+made with --dry-run or --replay are not logged.
+
+The log is audit.jsonl in the data directory (XDG_DATA_HOME/bb, by default
+~/.local/share/bb). Set audit_log to false to stop recording.`,
+	}
+
+	cmd.AddCommand(NewCmdList(f))
+
+	return cmd
+}
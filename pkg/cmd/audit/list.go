@@ -0,0 +1,121 @@
+package audit
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/httpx"
+)
+
+type listOptions struct {
+	since   string
+	command string
+	limit   int
+	json    bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdList creates the audit list command
+func NewCmdList(f *cmdutil.Factory) *cobra.Command {
+	opts := &listOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List logged changes, newest first",
+		Long: `List the changes recorded in the audit log, newest first.
+
+Examples:
+  bbc audit list
+  bbc audit list --since 1d --command "review approve"
+  bbc audit list --limit 0 --json   # Everything, as JSON`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.since, "since", "", "Only changes since a date or age (e.g. 7d)")
+	cmd.Flags().StringVar(&opts.command, "command", "", `Only changes made by this command (e.g. "review approve")`)
+	cmd.Flags().IntVarP(&opts.limit, "limit", "L", 50, "Maximum number of entries (0 for all)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of markdown")
+
+	return cmd
+}
+
+type listOutput struct {
+	Log     string             `json:"log"`
+	Entries []httpx.AuditEntry `json:"entries"`
+}
+
+func runList(opts *listOptions) error {
+	var since time.Time
+	if opts.since != "" {
+		t, err := cmdutil.ParseSince(opts.since, time.Now())
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		since = t
+	}
+
+	path := cmdutil.AuditLogPath()
+	entries, err := httpx.ReadAuditLog(path)
+	if err != nil {
+		return err
+	}
+	output := listOutput{Log: path, Entries: filterEntries(entries, since, opts.command, opts.limit)}
+
+	ios := opts.factory.IOStreams
+	if opts.json {
+		return cmdutil.WriteJSON(ios.Out, output)
+	}
+	if len(output.Entries) == 0 {
+		_, _ = fmt.Fprintln(ios.ErrOut, "no changes logged")
+		return nil
+	}
+	renderMarkdown(ios.Out, output.Entries)
+	return nil
+}
+
+// filterEntries returns the newest entries at or after since made by command
+// (any when empty), at most limit of them (all when 0)
+func filterEntries(entries []httpx.AuditEntry, since time.Time, command string, limit int) []httpx.AuditEntry {
+	out := []httpx.AuditEntry{}
+	for _, e := range slices.Backward(entries) {
+		if e.Time.Before(since) {
+			break
+		}
+		if command != "" && e.Command != command {
+			continue
+		}
+		out = append(out, e)
+		if limit > 0 && len(out) == limit {
+			break
+		}
+	}
+	return out
+}
+
+func renderMarkdown(w io.Writer, entries []httpx.AuditEntry) {
+	for _, e := range entries {
+		var b strings.Builder
+		fmt.Fprintf(&b, "- %s", e.Time.Local().Format("2006-01-02 15:04:05"))
+		if e.Command != "" {
+			fmt.Fprintf(&b, " `%s`", e.Command)
+		}
+		fmt.Fprintf(&b, " %s %s → %d", e.Method, e.URL, e.Status)
+		switch {
+		case e.ID != "":
+			fmt.Fprintf(&b, " (id:%s)", e.ID)
+		case e.UUID != "":
+			fmt.Fprintf(&b, " (uuid:%s)", e.UUID)
+		}
+		_, _ = fmt.Fprintln(w, b.String())
+	}
+}
@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ghoseb/bb/pkg/httpx"
+)
+
+func TestFilterEntries(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	entries := []httpx.AuditEntry{
+		{Time: now.Add(-72 * time.Hour), Command: "review approve", ID: "1"},
+		{Time: now.Add(-3 * time.Hour), Command: "review comment", ID: "2"},
+		{Time: now.Add(-2 * time.Hour), Command: "review approve", ID: "3"},
+		{Time: now.Add(-time.Hour), Command: "review comment", ID: "4"},
+	}
+
+	ids := func(es []httpx.AuditEntry) (out []string) {
+		for _, e := range es {
+			out = append(out, e.ID)
+		}
+		return out
+	}
+	tests := []struct {
+		name    string
+		since   time.Time
+		command string
+		limit   int
+		want    []string
+	}{
+		{"all, newest first", time.Time{}, "", 0, []string{"4", "3", "2", "1"}},
+		{"limit", time.Time{}, "", 2, []string{"4", "3"}},
+		{"since", now.Add(-24 * time.Hour), "", 0, []string{"4", "3", "2"}},
+		{"command", time.Time{}, "review approve", 0, []string{"3", "1"}},
+	}
+	for _, tt := range tests {
+		got := ids(filterEntries(entries, tt.since, tt.command, tt.limit))
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -3,13 +3,17 @@ package auth
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"regexp"
+	"time"
 
 	"github.com/spf13/cobra"
 
-	"github.com/ghoseb/bb/internal/secret"
+	"github.com/ghoseb/bb/internal/config"
 	"github.com/ghoseb/bb/pkg/bbcloud"
 	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/prompter"
 )
 
 type loginOptions struct {
@@ -32,13 +36,25 @@ func NewCmdAuth(f *cmdutil.Factory) *cobra.Command {
 		Long: `Authenticate with Bitbucket Cloud (default action is login).
 
 Credentials are validated by fetching the authenticated user info,
-then stored securely in your system keyring.
+then stored securely in your system keyring. Without --workspace (or
+BB_WORKSPACE), you choose from the workspaces the account belongs to.
+
+Credentials are kept per host and workspace, and the latest login is the
+profile's default. Log in once per workspace to use a different App
+Password for each; --workspace (or default_workspace) then picks the
+matching credentials.
 
 The token should be a Bitbucket App Password with appropriate permissions.
 You can create one at: https://bitbucket.org/account/settings/app-passwords/
 
+Use the global --host flag to log in to another configured Bitbucket host,
+and --profile to store the credentials under a named profile (see auth switch).
+
 To check authentication status:
-  bb auth status`,
+  bb auth status
+
+To see every stored login:
+  bb auth list`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Default action: run login
 			return runLogin(cmd.Context(), opts)
@@ -55,15 +71,50 @@ To check authentication status:
 
 	// Add subcommands
 	cmd.AddCommand(NewCmdStatus(f))
+	cmd.AddCommand(NewCmdList(f))
+	cmd.AddCommand(NewCmdExport(f))
+	cmd.AddCommand(NewCmdImport(f))
+	cmd.AddCommand(NewCmdKeyringTest(f))
+	cmd.AddCommand(NewCmdSwitch(f))
+	cmd.AddCommand(NewCmdToken(f))
 
 	return cmd
 }
 
 func runLogin(ctx context.Context, opts *loginOptions) error {
 	ios, _ := opts.factory.Streams()
-	prompter := opts.factory.Prompter
+	p := opts.factory.Prompter
+
+	host, err := opts.factory.Host()
+	if err != nil {
+		return err
+	}
+	profile, err := opts.factory.Profile()
+	if err != nil {
+		return err
+	}
+	bearer := host.Auth == bbcloud.AuthBearer
 
-	// Prompt for missing fields interactively
+	// Without prompts, every missing field must come from a flag or the
+	// environment; fail before contacting the API
+	if !ios.CanPrompt() {
+		var missing []string
+		if opts.workspace == "" && os.Getenv("BB_WORKSPACE") == "" {
+			missing = append(missing, "--workspace (or BB_WORKSPACE)")
+		}
+		if opts.username == "" && !bearer && os.Getenv("BB_USERNAME") == "" {
+			missing = append(missing, "--username (or BB_USERNAME)")
+		}
+		if opts.token == "" && os.Getenv("BB_TOKEN") == "" {
+			missing = append(missing, "--token (or BB_TOKEN)")
+		}
+		if len(missing) > 0 {
+			return cmdutil.MissingInputError(missing...)
+		}
+	}
+
+	// Prompt for missing fields interactively; the workspace is asked for
+	// last so it can be chosen from the account's workspaces
 	if opts.workspace == "" {
 		// Try environment variable fallback
 		if envWorkspace := os.Getenv("BB_WORKSPACE"); envWorkspace != "" {
@@ -71,29 +122,20 @@ func runLogin(ctx context.Context, opts *loginOptions) error {
 		} else {
 			_, _ = fmt.Fprintln(ios.ErrOut, "Log in to Bitbucket Cloud")
 			_, _ = fmt.Fprintln(ios.ErrOut)
-			workspace, err := prompter.Input("Bitbucket workspace: ")
-			if err != nil {
-				return fmt.Errorf("read workspace: %w", err)
-			}
-			if workspace == "" {
-				return fmt.Errorf("workspace is required")
-			}
-			opts.workspace = workspace
 		}
 	}
 
-	if opts.username == "" {
+	if opts.username == "" && !bearer {
 		// Try environment variable fallback
 		if envUsername := os.Getenv("BB_USERNAME"); envUsername != "" {
 			opts.username = envUsername
 		} else {
-			username, err := prompter.Input("Bitbucket username: ")
+			username, err := p.Input("Bitbucket username: ",
+				prompter.WithDefault(storedUsername(opts.factory, profile)),
+				prompter.WithValidator(prompter.Required("username")))
 			if err != nil {
 				return fmt.Errorf("read username: %w", err)
 			}
-			if username == "" {
-				return fmt.Errorf("username is required")
-			}
 			opts.username = username
 		}
 	}
@@ -103,8 +145,13 @@ func runLogin(ctx context.Context, opts *loginOptions) error {
 		if envToken := os.Getenv("BB_TOKEN"); envToken != "" {
 			opts.token = envToken
 		} else {
-			_, _ = fmt.Fprintln(ios.ErrOut, "Tip: Create an App Password at https://bitbucket.org/account/settings/app-passwords/")
-			token, err := prompter.Password("App Password (input hidden): ")
+			label := "App Password (input hidden): "
+			if bearer {
+				label = "Access token (input hidden): "
+			} else if host.Name == config.DefaultHost {
+				_, _ = fmt.Fprintln(ios.ErrOut, "Tip: Create an App Password at https://bitbucket.org/account/settings/app-passwords/")
+			}
+			token, err := p.Password(label)
 			if err != nil {
 				return fmt.Errorf("read token: %w", err)
 			}
@@ -117,9 +164,11 @@ func runLogin(ctx context.Context, opts *loginOptions) error {
 
 	// Test credentials by creating a client and fetching user info
 	client, err := bbcloud.New(bbcloud.Options{
+		BaseURL:   host.APIURL,
 		Workspace: opts.workspace,
 		Username:  opts.username,
 		Token:     opts.token,
+		AuthType:  host.Auth,
 	})
 	if err != nil {
 		return fmt.Errorf("create API client: %w", err)
@@ -130,8 +179,18 @@ func runLogin(ctx context.Context, opts *loginOptions) error {
 		return fmt.Errorf("authentication failed: %w", err)
 	}
 
+	if opts.workspace == "" {
+		defaultWorkspace := ""
+		if cfg, err := opts.factory.Config(); err == nil {
+			defaultWorkspace = cfg.Workspace()
+		}
+		if opts.workspace, err = promptWorkspace(ctx, ios.ErrOut, p, client, defaultWorkspace); err != nil {
+			return err
+		}
+	}
+
 	// Credentials are valid, store them in keyring
-	store, err := secret.Open(secret.WithAllowFileFallback(true))
+	store, err := opts.factory.GetSecretStore()
 	if err != nil {
 		return fmt.Errorf("open secret store: %w", err)
 	}
@@ -141,8 +200,17 @@ func runLogin(ctx context.Context, opts *loginOptions) error {
 		Workspace: opts.workspace,
 		Username:  opts.username,
 		Token:     opts.token,
+		Host:      host.Name,
+		CreatedAt: time.Now().UTC(),
+	}
+	// Workspace entries are the default profile's; a named profile's login
+	// must not replace the one other profiles use for the workspace
+	if profile == "" {
+		if err := cmdutil.SaveWorkspaceCredentials(store, creds); err != nil {
+			return err
+		}
 	}
-	if err := cmdutil.SaveCredentialsToStore(store, creds); err != nil {
+	if err := cmdutil.SaveCredentialsToStore(store, profile, creds); err != nil {
 		return err
 	}
 
@@ -151,6 +219,8 @@ func runLogin(ctx context.Context, opts *loginOptions) error {
 		"status":    "success",
 		"username":  user.Username,
 		"workspace": opts.workspace,
+		"host":      host.Name,
+		"profile":   profileName(profile),
 	}
 
 	if err := cmdutil.WriteJSON(ios.Out, result); err != nil {
@@ -159,3 +229,75 @@ func runLogin(ctx context.Context, opts *loginOptions) error {
 
 	return nil
 }
+
+// workspaceSlug matches Bitbucket workspace IDs
+var workspaceSlug = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// validateWorkspace rejects answers that cannot be workspace IDs before they
+// reach the API
+func validateWorkspace(workspace string) error {
+	if workspace == "" {
+		return fmt.Errorf("workspace is required")
+	}
+	if !workspaceSlug.MatchString(workspace) {
+		return fmt.Errorf("%q is not a workspace ID (lowercase letters, digits, - and _)", workspace)
+	}
+	return nil
+}
+
+// storedUsername returns the username last stored for profile, to offer as
+// the default when logging in again
+func storedUsername(f *cmdutil.Factory, profile string) string {
+	store, err := f.GetSecretStore()
+	if err != nil {
+		return ""
+	}
+	creds, err := cmdutil.LoadCredentialsFromStore(store, profile)
+	if err != nil {
+		return ""
+	}
+	return creds.Username
+}
+
+// promptWorkspace asks which workspace to use, offering the account's
+// workspaces when they can be listed and free-text entry, defaulting to
+// defaultWorkspace, otherwise
+func promptWorkspace(ctx context.Context, errOut io.Writer, p prompter.Prompter, client *bbcloud.Client, defaultWorkspace string) (string, error) {
+	workspaces, err := client.ListWorkspaces(ctx)
+	if err != nil {
+		_, _ = fmt.Fprintf(errOut, "warning: failed to list workspaces: %v\n", err)
+	}
+
+	switch len(workspaces) {
+	case 0:
+		workspace, err := p.Input("Bitbucket workspace: ",
+			prompter.WithDefault(defaultWorkspace),
+			prompter.WithValidator(validateWorkspace))
+		if err != nil {
+			return "", fmt.Errorf("read workspace: %w", err)
+		}
+		return workspace, nil
+	case 1:
+		slug := workspaces[0].Workspace.Slug
+		_, _ = fmt.Fprintf(errOut, "Using workspace %s\n", slug)
+		return slug, nil
+	}
+
+	options := make([]string, len(workspaces))
+	for i, w := range workspaces {
+		options[i] = w.Workspace.Slug
+	}
+	i, err := p.Select("Bitbucket workspace:", options)
+	if err != nil {
+		return "", fmt.Errorf("read workspace: %w", err)
+	}
+	return options[i], nil
+}
+
+// profileName labels the default profile for output
+func profileName(profile string) string {
+	if profile == "" {
+		return "default"
+	}
+	return profile
+}
@@ -0,0 +1,16 @@
+package auth
+
+import "testing"
+
+func TestValidateWorkspace(t *testing.T) {
+	for _, ws := range []string{"acme", "acme-corp", "team_42", "7eleven"} {
+		if err := validateWorkspace(ws); err != nil {
+			t.Errorf("validateWorkspace(%q) = %v", ws, err)
+		}
+	}
+	for _, ws := range []string{"", "Acme", "acme corp", "-acme", "acme/api"} {
+		if err := validateWorkspace(ws); err == nil {
+			t.Errorf("validateWorkspace(%q) accepted", ws)
+		}
+	}
+}
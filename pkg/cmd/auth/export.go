@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/internal/secret"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// exportPassphraseEnv supplies the export passphrase without a prompt
+const exportPassphraseEnv = "BB_EXPORT_PASSPHRASE"
+
+// minPassphraseLength is the shortest passphrase accepted for an export
+const minPassphraseLength = 8
+
+// credentialsExport is the plaintext sealed in an export file
+type credentialsExport struct {
+	Version    int                            `json:"version"`
+	ExportedAt time.Time                      `json:"exported_at"`
+	Profiles   map[string]cmdutil.Credentials `json:"profiles"`             // by profile name, "default" included
+	Workspaces map[string]cmdutil.Credentials `json:"workspaces,omitempty"` // by host/workspace
+}
+
+// NewCmdExport creates the auth export command
+func NewCmdExport(f *cmdutil.Factory) *cobra.Command {
+	var file string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export stored credentials to an encrypted file",
+		Long: `Write the credentials of every profile and workspace in the keyring to a
+file encrypted with a passphrase, to move them to another machine or seed a
+CI runner with 'bbc auth import'.
+
+The passphrase is asked for twice, or read from BB_EXPORT_PASSPHRASE. The
+file is encrypted with AES-256-GCM under a key derived from it with PBKDF2;
+keep it as safe as the tokens themselves.
+
+--file - writes the export to stdout.
+
+Examples:
+  bbc auth export --file creds.enc
+  BB_EXPORT_PASSPHRASE=... bbc auth export --file - | ssh ci-runner bbc auth import --file -`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExport(f, file, force)
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "File to write (- for stdout)")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing file")
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func runExport(f *cmdutil.Factory, file string, force bool) error {
+	if file != "-" && !force {
+		if _, err := os.Stat(file); err == nil {
+			return fmt.Errorf("%s already exists; pass --force to overwrite it", file)
+		}
+	}
+
+	store, err := f.GetSecretStore()
+	if err != nil {
+		return fmt.Errorf("open secret store: %w", err)
+	}
+	profiles, err := cmdutil.StoredProfiles(store)
+	if err != nil {
+		return err
+	}
+	workspaces, err := cmdutil.StoredWorkspaces(store)
+	if err != nil {
+		return err
+	}
+	if len(profiles) == 0 && len(workspaces) == 0 {
+		return errors.New("no stored credentials to export; log in with 'bbc auth' first")
+	}
+
+	export := credentialsExport{Version: 1, ExportedAt: time.Now().UTC(), Profiles: make(map[string]cmdutil.Credentials)}
+	names := make([]string, 0, len(profiles))
+	for _, profile := range profiles {
+		creds, err := cmdutil.LoadCredentialsFromStore(store, profile)
+		if err != nil {
+			return fmt.Errorf("profile %s: %w", profileName(profile), err)
+		}
+		export.Profiles[profileName(profile)] = *creds
+		names = append(names, profileName(profile))
+	}
+	if len(workspaces) > 0 {
+		export.Workspaces = make(map[string]cmdutil.Credentials)
+	} else {
+		workspaces = []string{}
+	}
+	for _, hostWorkspace := range workspaces {
+		host, ws, _ := strings.Cut(hostWorkspace, "/")
+		creds, err := cmdutil.LoadWorkspaceCredentials(store, host, ws)
+		if err != nil {
+			return fmt.Errorf("workspace %s: %w", hostWorkspace, err)
+		}
+		export.Workspaces[hostWorkspace] = *creds
+	}
+
+	passphrase, err := readPassphrase(f, true)
+	if err != nil {
+		return err
+	}
+	plain, err := json.Marshal(export)
+	if err != nil {
+		return err
+	}
+	data, err := secret.Seal(plain, passphrase)
+	if err != nil {
+		return fmt.Errorf("encrypt export: %w", err)
+	}
+
+	if file == "-" {
+		_, err := f.IOStreams.Out.Write(append(data, '\n'))
+		return err
+	}
+	if err := writePrivateFile(file, append(data, '\n')); err != nil {
+		return fmt.Errorf("write export: %w", err)
+	}
+	return cmdutil.WriteJSON(f.IOStreams.Out, map[string]interface{}{
+		"file":       file,
+		"profiles":   names,
+		"workspaces": workspaces,
+	})
+}
+
+// writePrivateFile writes data to path readable by the owner only. It goes
+// through a new temp file renamed over path, since os.WriteFile keeps the
+// mode of a file it overwrites
+func writePrivateFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// readPassphrase returns the passphrase from BB_EXPORT_PASSPHRASE or a
+// prompt; a new passphrase is asked for twice
+func readPassphrase(f *cmdutil.Factory, confirm bool) (string, error) {
+	if passphrase := os.Getenv(exportPassphraseEnv); passphrase != "" {
+		if confirm && len(passphrase) < minPassphraseLength {
+			return "", fmt.Errorf("%s must be at least %d characters", exportPassphraseEnv, minPassphraseLength)
+		}
+		return passphrase, nil
+	}
+	if !f.IOStreams.CanPrompt() {
+		return "", fmt.Errorf("no terminal to ask for the passphrase; set %s", exportPassphraseEnv)
+	}
+
+	passphrase, err := f.Prompter.Password("Passphrase (input hidden): ")
+	if err != nil {
+		return "", fmt.Errorf("read passphrase: %w", err)
+	}
+	if !confirm {
+		return passphrase, nil
+	}
+	if len(passphrase) < minPassphraseLength {
+		return "", fmt.Errorf("passphrase must be at least %d characters", minPassphraseLength)
+	}
+	again, err := f.Prompter.Password("Repeat passphrase: ")
+	if err != nil {
+		return "", fmt.Errorf("read passphrase: %w", err)
+	}
+	if again != passphrase {
+		return "", errors.New("passphrases do not match")
+	}
+	return passphrase, nil
+}
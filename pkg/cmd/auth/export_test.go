@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ghoseb/bb/internal/secret"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/iostreams"
+)
+
+// newTestFactory returns a factory keeping credentials in a fresh in-memory
+// store
+func newTestFactory(t *testing.T, out *bytes.Buffer) *cmdutil.Factory {
+	t.Helper()
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv("BB_AGENT_SOCKET", filepath.Join(t.TempDir(), "none.sock"))
+	f := cmdutil.NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: out, ErrOut: &bytes.Buffer{}})
+	f.SecretStore = secret.NewMemoryStore()
+	return f
+}
+
+func TestExportImport(t *testing.T) {
+	t.Setenv(exportPassphraseEnv, "correct horse")
+
+	f := newTestFactory(t, &bytes.Buffer{})
+	store, err := f.GetSecretStore()
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	work := &cmdutil.Credentials{Workspace: "acme", Username: "alice", Token: "work-token"}
+	for profile, creds := range map[string]*cmdutil.Credentials{"": {Workspace: "oss", Token: "default-token"}, "work": work} {
+		if err := cmdutil.SaveCredentialsToStore(store, profile, creds); err != nil {
+			t.Fatalf("save credentials: %v", err)
+		}
+	}
+	other := &cmdutil.Credentials{Host: "bitbucket.org", Workspace: "other", Token: "other-token"}
+	if err := cmdutil.SaveWorkspaceCredentials(store, other); err != nil {
+		t.Fatalf("save workspace credentials: %v", err)
+	}
+
+	file := filepath.Join(t.TempDir(), "creds.enc")
+	if err := runExport(f, file, false); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if err := runExport(f, file, false); err == nil {
+		t.Fatal("expected export to refuse overwriting the file")
+	}
+
+	// A fresh keyring on the new machine, with one profile already set up
+	out := &bytes.Buffer{}
+	f = newTestFactory(t, out)
+	store, err = f.GetSecretStore()
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	if err := cmdutil.SaveCredentialsToStore(store, "", &cmdutil.Credentials{Workspace: "new", Token: "new-token"}); err != nil {
+		t.Fatalf("save credentials: %v", err)
+	}
+
+	if err := runImport(f, file, false); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	var result struct {
+		Imported []string `json:"imported"`
+		Skipped  []string `json:"skipped"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("parse output: %v\n%s", err, out)
+	}
+	if !slices.Equal(result.Imported, []string{"work", "bitbucket.org/other"}) || !slices.Equal(result.Skipped, []string{"default"}) {
+		t.Fatalf("imported=%q skipped=%q", result.Imported, result.Skipped)
+	}
+
+	got, err := cmdutil.LoadCredentialsFromStore(store, "work")
+	if err != nil {
+		t.Fatalf("load credentials: %v", err)
+	}
+	if *got != *work {
+		t.Errorf("work credentials = %+v, want %+v", *got, *work)
+	}
+	if got, err := cmdutil.LoadWorkspaceCredentials(store, "bitbucket.org", "other"); err != nil || *got != *other {
+		t.Errorf("other workspace credentials = %+v, err=%v", got, err)
+	}
+	if got, _ := cmdutil.LoadCredentialsFromStore(store, ""); got.Token != "new-token" {
+		t.Errorf("default profile overwritten without --force: %+v", got)
+	}
+
+	t.Setenv(exportPassphraseEnv, "wrong passphrase")
+	if err := runImport(f, file, true); err == nil {
+		t.Fatal("expected import with the wrong passphrase to fail")
+	}
+}
+
+func TestExportForceTightensMode(t *testing.T) {
+	t.Setenv(exportPassphraseEnv, "correct horse")
+
+	f := newTestFactory(t, &bytes.Buffer{})
+	store, err := f.GetSecretStore()
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	if err := cmdutil.SaveCredentialsToStore(store, "", &cmdutil.Credentials{Workspace: "oss", Token: "default-token"}); err != nil {
+		t.Fatalf("save credentials: %v", err)
+	}
+
+	file := filepath.Join(t.TempDir(), "creds.enc")
+	if err := os.WriteFile(file, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(file, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runExport(f, file, true); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("export mode = %o, want 600", mode)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(file)); len(entries) != 1 {
+		t.Errorf("temp file left behind: %v", entries)
+	}
+}
+
+func TestImportBadEntrySavesNothing(t *testing.T) {
+	t.Setenv(exportPassphraseEnv, "correct horse")
+
+	plain, err := json.Marshal(credentialsExport{
+		Version:    1,
+		Profiles:   map[string]cmdutil.Credentials{"a": {Workspace: "acme", Token: "a-token"}},
+		Workspaces: map[string]cmdutil.Credentials{"bitbucket.org/other": {}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := secret.Seal(plain, "correct horse")
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	file := filepath.Join(t.TempDir(), "creds.enc")
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	f := newTestFactory(t, &bytes.Buffer{})
+	if err := runImport(f, file, false); err == nil {
+		t.Fatal("expected import of a workspace without a token to fail")
+	}
+	store, err := f.GetSecretStore()
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	if profiles, _ := cmdutil.StoredProfiles(store); len(profiles) != 0 {
+		t.Errorf("profiles saved by a failed import: %q", profiles)
+	}
+}
@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/internal/secret"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// NewCmdImport creates the auth import command
+func NewCmdImport(f *cmdutil.Factory) *cobra.Command {
+	var file string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import credentials from an encrypted export",
+		Long: `Store the credentials of a file written by 'bbc auth export' in the
+keyring, under the same profiles and workspaces.
+
+The passphrase is asked for, or read from BB_EXPORT_PASSPHRASE. Profiles
+and workspaces (listed as host/workspace) that already have credentials
+are skipped unless --force is given. The credentials are not verified
+against the API; run 'bbc auth status' to check them.
+
+--file - reads the export from stdin, which requires BB_EXPORT_PASSPHRASE.
+
+Examples:
+  bbc auth import --file creds.enc
+  bbc auth import --file creds.enc --force`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runImport(f, file, force)
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Export file to read (- for stdin)")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite profiles that already have credentials")
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func runImport(f *cmdutil.Factory, file string, force bool) error {
+	var data []byte
+	var err error
+	if file == "-" {
+		if os.Getenv(exportPassphraseEnv) == "" {
+			return fmt.Errorf("--file - needs the passphrase in %s", exportPassphraseEnv)
+		}
+		data, err = io.ReadAll(f.IOStreams.In)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return fmt.Errorf("read export: %w", err)
+	}
+
+	passphrase, err := readPassphrase(f, false)
+	if err != nil {
+		return err
+	}
+	plain, err := secret.Unseal(data, passphrase)
+	if err != nil {
+		return fmt.Errorf("decrypt export: %w", err)
+	}
+	var export credentialsExport
+	if err := json.Unmarshal(plain, &export); err != nil {
+		return fmt.Errorf("parse export: %w", err)
+	}
+	if export.Version != 1 {
+		return fmt.Errorf("unsupported export version %d", export.Version)
+	}
+
+	store, err := f.GetSecretStore()
+	if err != nil {
+		return fmt.Errorf("open secret store: %w", err)
+	}
+	stored, err := cmdutil.StoredProfiles(store)
+	if err != nil {
+		return err
+	}
+
+	storedWorkspaces, err := cmdutil.StoredWorkspaces(store)
+	if err != nil {
+		return err
+	}
+
+	// Check every entry before saving any, so a bad export imports nothing
+	profileNames := slices.Sorted(maps.Keys(export.Profiles))
+	for _, name := range profileNames {
+		if export.Profiles[name].Token == "" {
+			return fmt.Errorf("profile %s has no token in the export", name)
+		}
+	}
+	workspaceNames := slices.Sorted(maps.Keys(export.Workspaces))
+	for _, name := range workspaceNames {
+		if host, ws, ok := strings.Cut(name, "/"); !ok || host == "" || ws == "" {
+			return fmt.Errorf("workspace %q in the export is not host/workspace", name)
+		}
+		if export.Workspaces[name].Token == "" {
+			return fmt.Errorf("workspace %s has no token in the export", name)
+		}
+	}
+
+	imported, skipped := []string{}, []string{}
+	for _, name := range profileNames {
+		profile := name
+		if profile == "default" {
+			profile = ""
+		}
+		if slices.Contains(stored, profile) && !force {
+			skipped = append(skipped, name)
+			continue
+		}
+		creds := export.Profiles[name]
+		if err := cmdutil.SaveCredentialsToStore(store, profile, &creds); err != nil {
+			return fmt.Errorf("profile %s: %w", name, err)
+		}
+		imported = append(imported, name)
+	}
+	for _, name := range workspaceNames {
+		if slices.Contains(storedWorkspaces, name) && !force {
+			skipped = append(skipped, name)
+			continue
+		}
+		creds := export.Workspaces[name]
+		creds.Host, creds.Workspace, _ = strings.Cut(name, "/")
+		if err := cmdutil.SaveWorkspaceCredentials(store, &creds); err != nil {
+			return fmt.Errorf("workspace %s: %w", name, err)
+		}
+		imported = append(imported, name)
+	}
+
+	return cmdutil.WriteJSON(f.IOStreams.Out, map[string]interface{}{
+		"imported": imported,
+		"skipped":  skipped,
+	})
+}
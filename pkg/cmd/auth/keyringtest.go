@@ -0,0 +1,160 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/internal/secret"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// keyringTestKey is the entry written and removed by auth keyring-test
+const keyringTestKey = "bb/keyring-test"
+
+// NewCmdKeyringTest creates the auth keyring-test command
+func NewCmdKeyringTest(f *cmdutil.Factory) *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "keyring-test",
+		Short: "Check that the keyring can store credentials",
+		Long: `Open the keyring the way credential loading does and write, read back and
+delete a test entry, reporting which backend was used and a fix for the
+step that failed.
+
+Choose the backends to try with the global --keyring-backend flag or
+KEYRING_BACKEND, e.g. --keyring-backend pass or --keyring-backend file.
+Stored credentials are not touched. The exit status is 1 if a step failed.
+
+Examples:
+  bbc auth keyring-test
+  bbc auth keyring-test --keyring-backend file --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runKeyringTest(f, asJSON)
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output JSON instead of text")
+
+	return cmd
+}
+
+type keyringStep struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+type keyringTestOutput struct {
+	OK       bool          `json:"ok"`
+	Backend  string        `json:"backend,omitempty"` // the backend that opened
+	Backends []string      `json:"backends"`          // the backends tried, in order
+	Steps    []keyringStep `json:"steps"`
+}
+
+func runKeyringTest(f *cmdutil.Factory, asJSON bool) error {
+	output := keyringTestOutput{Backends: secret.Backends(f.SecretStoreOptions()...)}
+	if output.Backends == nil {
+		output.Backends = []string{}
+	}
+	output.Steps = keyringSteps(f, &output)
+	output.OK = output.Steps[len(output.Steps)-1].OK
+
+	ios := f.IOStreams
+	if asJSON {
+		if err := cmdutil.WriteJSON(ios.Out, output); err != nil {
+			return err
+		}
+	} else {
+		renderKeyringTest(ios.Out, output)
+	}
+	if !output.OK {
+		return cmdutil.NewExitError(1, "")
+	}
+	return nil
+}
+
+// keyringSteps runs the open, set, get and delete steps, stopping at the
+// first failure
+func keyringSteps(f *cmdutil.Factory, output *keyringTestOutput) []keyringStep {
+	store, err := f.GetSecretStore()
+	if err != nil {
+		return []keyringStep{{Name: "open", Detail: err.Error(), Fix: openFix(err, output.Backends)}}
+	}
+	output.Backend = store.Backend()
+	steps := []keyringStep{{Name: "open", OK: true, Detail: "using " + output.Backend}}
+
+	value := fmt.Sprintf("keyring-test-%d", os.Getpid())
+	if err := store.Set(keyringTestKey, value); err != nil {
+		return append(steps, keyringStep{Name: "set", Detail: err.Error(),
+			Fix: stepFix(err, "The keyring refused the write: unlock it, or check that the "+output.Backend+" backend is writable (for pass, that the password store is initialised with 'pass init')")})
+	}
+	steps = append(steps, keyringStep{Name: "set", OK: true, Detail: "wrote " + keyringTestKey})
+
+	got, err := store.Get(keyringTestKey)
+	switch {
+	case err != nil:
+		steps = append(steps, keyringStep{Name: "get", Detail: err.Error(),
+			Fix: stepFix(err, "The entry was written but cannot be read back: check the keyring's access control for bbc")})
+	case got != value:
+		steps = append(steps, keyringStep{Name: "get", Detail: "read back a different value",
+			Fix: "Another program may share the " + output.Backend + " entry; try another backend with --keyring-backend"})
+	default:
+		steps = append(steps, keyringStep{Name: "get", OK: true, Detail: "read the value back"})
+	}
+	if !steps[len(steps)-1].OK {
+		_ = store.Delete(keyringTestKey)
+		return steps
+	}
+
+	if err := store.Delete(keyringTestKey); err != nil {
+		return append(steps, keyringStep{Name: "delete", Detail: err.Error(),
+			Fix: stepFix(err, "Remove the "+keyringTestKey+" entry by hand; logout and credential updates will fail the same way")})
+	}
+	return append(steps, keyringStep{Name: "delete", OK: true, Detail: "removed " + keyringTestKey})
+}
+
+// openFix suggests how to get a keyring backend to open
+func openFix(err error, backends []string) string {
+	switch {
+	case errors.Is(err, secret.ErrKeyringTimeout):
+		return "Unlock your keyring, raise BB_KEYRING_TIMEOUT, or set BB_ALLOW_INSECURE_STORE=1 to use the encrypted file store"
+	case len(backends) == 0:
+		return "None of the chosen backends is supported on this platform; pick another with --keyring-backend or KEYRING_BACKEND"
+	case secret.IsNoKeyringError(err) && secret.Headless():
+		return "This session looks headless, so desktop keyrings are skipped and " + strings.Join(backends, ", ") +
+			" did not open: set BB_ALLOW_INSECURE_STORE=1 (with BB_KEYRING_PASSPHRASE), or export BB_WORKSPACE, BB_USERNAME and BB_TOKEN"
+	case secret.IsNoKeyringError(err):
+		return "No backend of " + strings.Join(backends, ", ") + " opened: install or unlock one (Secret Service, KWallet, pass), or use --keyring-backend file"
+	}
+	return "Check the keyring configuration; BB_KEYRING_PASSPHRASE must match the one the file store was created with"
+}
+
+// stepFix returns the timeout advice for timeouts and fix otherwise
+func stepFix(err error, fix string) string {
+	if errors.Is(err, secret.ErrKeyringTimeout) {
+		return "The keyring did not answer in time: unlock it or raise BB_KEYRING_TIMEOUT"
+	}
+	return fix
+}
+
+func renderKeyringTest(w io.Writer, output keyringTestOutput) {
+	_, _ = fmt.Fprintf(w, "backends tried: %s\n", strings.Join(output.Backends, ", "))
+	for _, s := range output.Steps {
+		status := "[ok]"
+		if !s.OK {
+			status = "[fail]"
+		}
+		_, _ = fmt.Fprintf(w, "%-6s %-7s %s\n", status, s.Name, s.Detail)
+		if s.Fix != "" {
+			_, _ = fmt.Fprintf(w, "%-14s fix: %s\n", "", s.Fix)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/iostreams"
+)
+
+func TestKeyringTest(t *testing.T) {
+	// An empty encrypted file keyring
+	dir := t.TempDir()
+	t.Setenv("BB_CONFIG_DIR", filepath.Join(dir, "config"))
+	t.Setenv("BB_ALLOW_INSECURE_STORE", "1")
+	t.Setenv("KEYRING_BACKEND", "file")
+	t.Setenv("KEYRING_FILE_DIR", filepath.Join(dir, "keyring"))
+	t.Setenv("BB_KEYRING_PASSPHRASE", "keyring-passphrase")
+
+	out := &bytes.Buffer{}
+	f := cmdutil.NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: out, ErrOut: &bytes.Buffer{}})
+	if err := runKeyringTest(f, true); err != nil {
+		t.Fatalf("keyring-test: %v\n%s", err, out)
+	}
+	var result keyringTestOutput
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("parse output: %v\n%s", err, out)
+	}
+	if !result.OK || result.Backend != "file" || len(result.Steps) != 4 {
+		t.Fatalf("result = %+v", result)
+	}
+}
@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/internal/config"
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// NewCmdList creates the auth list command
+func NewCmdList(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List stored credentials",
+		Long: `List every profile and workspace with credentials in the keyring: its
+workspace, username, token type, when it was stored and the last characters
+of its token. Tokens themselves are never printed.
+
+For profiles, hosts lists the hosts bound through hosts.<host>.profile; for
+workspace entries, host is the host they were stored for. The credentials
+in use are marked active; credentials from BB_WORKSPACE, BB_USERNAME
+and BB_TOKEN are listed too, since they take precedence over the keyring.
+
+Examples:
+  bbc auth list`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(f)
+		},
+	}
+}
+
+type listEntry struct {
+	Profile   string    `json:"profile,omitempty"`
+	Host      string    `json:"host,omitempty"` // of workspace entries
+	Source    string    `json:"source"`         // keyring or env
+	Active    bool      `json:"active"`
+	Hosts     []string  `json:"hosts,omitempty"`
+	Workspace string    `json:"workspace"`
+	Username  string    `json:"username,omitempty"`
+	TokenType string    `json:"token_type"` // app_password or access_token
+	CreatedAt time.Time `json:"created_at,omitzero"`
+	Token     string    `json:"token"` // masked
+}
+
+func runList(f *cmdutil.Factory) error {
+	cfg, err := f.Config()
+	if err != nil {
+		return err
+	}
+	active, err := f.Profile()
+	if err != nil {
+		return err
+	}
+	host, err := f.Host()
+	if err != nil {
+		return err
+	}
+	envCreds := cmdutil.EnvCredentialsSet()
+
+	entries := []listEntry{}
+	if envCreds {
+		entries = append(entries, listEntry{
+			Source:    "env",
+			Active:    true,
+			Workspace: os.Getenv("BB_WORKSPACE"),
+			Username:  os.Getenv("BB_USERNAME"),
+			TokenType: tokenType(host.Auth),
+			Token:     cmdutil.MaskSecret(os.Getenv("BB_TOKEN")),
+		})
+	}
+
+	store, err := f.GetSecretStore()
+	if err != nil {
+		if envCreds {
+			return cmdutil.WriteJSON(f.IOStreams.Out, entries)
+		}
+		return fmt.Errorf("open secret store: %w", err)
+	}
+	profiles, err := cmdutil.StoredProfiles(store)
+	if err != nil {
+		return err
+	}
+
+	workspaces, err := cmdutil.StoredWorkspaces(store)
+	if err != nil {
+		return err
+	}
+
+	// A workspace asked for selects its own entry over the default profile's
+	selected := ""
+	if ws, _, err := f.ResolveWorkspace("", false); err == nil && ws != "" && active == "" {
+		if slices.Contains(workspaces, host.Name+"/"+ws) {
+			selected = host.Name + "/" + ws
+		}
+	}
+
+	for _, profile := range profiles {
+		creds, err := cmdutil.LoadCredentialsFromStore(store, profile)
+		if err != nil {
+			return fmt.Errorf("profile %s: %w", profileName(profile), err)
+		}
+		hosts := boundHosts(cfg, profile)
+
+		// The token is used with the hosts bound to the profile, or with
+		// the active host when none is
+		auth := host.Auth
+		if len(hosts) > 0 {
+			if h, err := cfg.LookupHost(hosts[0]); err == nil {
+				auth = h.Auth
+			}
+		}
+
+		entries = append(entries, listEntry{
+			Profile:   profileName(profile),
+			Source:    "keyring",
+			Active:    !envCreds && selected == "" && profile == active,
+			Hosts:     hosts,
+			Workspace: creds.Workspace,
+			Username:  creds.Username,
+			TokenType: tokenType(auth),
+			CreatedAt: creds.CreatedAt,
+			Token:     cmdutil.MaskSecret(creds.Token),
+		})
+	}
+
+	for _, hostWorkspace := range workspaces {
+		name, ws, _ := strings.Cut(hostWorkspace, "/")
+		creds, err := cmdutil.LoadWorkspaceCredentials(store, name, ws)
+		if err != nil {
+			return fmt.Errorf("workspace %s: %w", hostWorkspace, err)
+		}
+		auth := host.Auth
+		if h, err := cfg.LookupHost(name); err == nil {
+			auth = h.Auth
+		}
+
+		entries = append(entries, listEntry{
+			Host:      name,
+			Source:    "keyring",
+			Active:    !envCreds && hostWorkspace == selected,
+			Workspace: ws,
+			Username:  creds.Username,
+			TokenType: tokenType(auth),
+			CreatedAt: creds.CreatedAt,
+			Token:     cmdutil.MaskSecret(creds.Token),
+		})
+	}
+
+	return cmdutil.WriteJSON(f.IOStreams.Out, entries)
+}
+
+// boundHosts returns the hosts whose profile setting selects profile, sorted
+func boundHosts(cfg *config.Config, profile string) []string {
+	var hosts []string
+	for name, h := range cfg.Hosts() {
+		bound := h.Profile
+		if bound == "default" {
+			bound = ""
+		}
+		if h.Profile != "" && bound == profile {
+			hosts = append(hosts, name)
+		}
+	}
+	slices.Sort(hosts)
+	return hosts
+}
+
+// tokenType names the kind of token a host's auth scheme takes
+func tokenType(auth string) string {
+	if auth == bbcloud.AuthBearer {
+		return "access_token"
+	}
+	return "app_password"
+}
@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+func TestList(t *testing.T) {
+	t.Setenv("BB_WORKSPACE", "")
+	t.Setenv("BB_PROFILE", "")
+	t.Setenv("BB_HOST", "")
+
+	out := &bytes.Buffer{}
+	f := newTestFactory(t, out)
+	store, err := f.GetSecretStore()
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	if err := cmdutil.SaveCredentialsToStore(store, "", &cmdutil.Credentials{Workspace: "acme", Username: "alice", Token: "abcdefghijkl1234"}); err != nil {
+		t.Fatalf("save credentials: %v", err)
+	}
+	if err := cmdutil.SaveWorkspaceCredentials(store, &cmdutil.Credentials{Host: "bitbucket.org", Workspace: "other", Token: "short"}); err != nil {
+		t.Fatalf("save workspace credentials: %v", err)
+	}
+	f.WorkspaceOverride = "other"
+
+	if err := runList(f); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	var entries []listEntry
+	if err := json.Unmarshal(out.Bytes(), &entries); err != nil {
+		t.Fatalf("parse output: %v\n%s", err, out)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries = %+v", entries)
+	}
+	if e := entries[0]; e.Profile != "default" || e.Active || e.Token != "****1234" || e.TokenType != "app_password" {
+		t.Errorf("profile entry = %+v", e)
+	}
+	if e := entries[1]; e.Host != "bitbucket.org" || e.Workspace != "other" || !e.Active || e.Token != "****" {
+		t.Errorf("workspace entry = %+v", e)
+	}
+}
@@ -36,16 +36,6 @@ fetching the authenticated user info.`,
 	return cmd
 }
 
-// requiredScopes lists the OAuth scopes needed for bb to function correctly
-var requiredScopes = []string{
-	"read:user:bitbucket",
-	"read:workspace:bitbucket",
-	"read:repository:bitbucket",
-	"read:pullrequest:bitbucket",
-	"write:pullrequest:bitbucket",
-	"read:pipeline:bitbucket",
-}
-
 func runStatus(ctx context.Context, opts *statusOptions) error {
 	ios, _ := opts.factory.Streams()
 
@@ -55,11 +45,22 @@ func runStatus(ctx context.Context, opts *statusOptions) error {
 		return outputNotAuthenticated(ios, fmt.Sprintf("failed to load credentials: %v", err))
 	}
 
+	host, err := opts.factory.Host()
+	if err != nil {
+		return outputNotAuthenticated(ios, fmt.Sprintf("failed to resolve host: %v", err))
+	}
+	profile, err := opts.factory.Profile()
+	if err != nil {
+		return outputNotAuthenticated(ios, fmt.Sprintf("failed to resolve profile: %v", err))
+	}
+
 	// Verify credentials by calling API
 	client, err := bbcloud.New(bbcloud.Options{
+		BaseURL:   host.APIURL,
 		Workspace: creds.Workspace,
 		Username:  creds.Username,
 		Token:     creds.Token,
+		AuthType:  host.Auth,
 	})
 	if err != nil {
 		return outputNotAuthenticated(ios, fmt.Sprintf("failed to create API client: %v", err))
@@ -71,13 +72,15 @@ func runStatus(ctx context.Context, opts *statusOptions) error {
 	}
 
 	// Check for missing scopes
-	missing := checkMissingScopes(grantedScopes, requiredScopes)
+	missing := bbcloud.MissingScopes(grantedScopes)
 	
 	// Output authenticated status
 	result := map[string]interface{}{
 		"authenticated": true,
 		"username":      user.Username,
 		"workspace":     creds.Workspace,
+		"host":          host.Name,
+		"profile":       profileName(profile),
 	}
 	
 	if len(missing) == 0 {
@@ -94,22 +97,6 @@ func runStatus(ctx context.Context, opts *statusOptions) error {
 	return nil
 }
 
-func checkMissingScopes(granted []string, required []string) []string {
-	grantedSet := make(map[string]bool)
-	for _, scope := range granted {
-		grantedSet[scope] = true
-	}
-	
-	var missing []string
-	for _, scope := range required {
-		if !grantedSet[scope] {
-			missing = append(missing, scope)
-		}
-	}
-	
-	return missing
-}
-
 func outputNotAuthenticated(ios *iostreams.IOStreams, reason string) error {
 	result := map[string]interface{}{
 		"authenticated": false,
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/internal/config"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// NewCmdSwitch creates the auth switch command
+func NewCmdSwitch(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "switch <profile>",
+		Short: "Switch the active auth profile",
+		Long: `Make a logged-in profile the active one.
+
+The profile selects the stored credentials and applies any settings under
+profiles.<profile> in the config, e.g.:
+
+  profiles:
+    work:
+      default_workspace: acme
+      format: json
+
+Log in to a new profile with 'bbc auth --profile <name>'. Use "default" to
+return to the default credentials. --profile and BB_PROFILE override the
+active profile for a single invocation.
+
+Examples:
+  bbc auth switch work
+  bbc auth switch default`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile := args[0]
+			if profile == "default" {
+				profile = ""
+			}
+
+			store, err := f.GetSecretStore()
+			if err != nil {
+				return fmt.Errorf("open secret store: %w", err)
+			}
+			creds, err := cmdutil.LoadCredentialsFromStore(store, profile)
+			if err != nil {
+				return fmt.Errorf("profile %s: %w", profileName(profile), err)
+			}
+
+			cfg, err := config.Load(config.DefaultPath())
+			if err != nil {
+				return err
+			}
+			if profile == "" {
+				cfg.Unset("profile")
+			} else {
+				cfg.Set("profile", profile)
+			}
+			if err := cfg.Save(); err != nil {
+				return err
+			}
+
+			return cmdutil.WriteJSON(f.IOStreams.Out, map[string]interface{}{
+				"profile":   profileName(profile),
+				"username":  creds.Username,
+				"workspace": creds.Workspace,
+			})
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// NewCmdToken creates the auth token command
+func NewCmdToken(f *cmdutil.Factory) *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Print the stored token (credential helper)",
+		Long: `Print the token of the active profile, for scripts and extensions that
+call the Bitbucket API themselves.
+
+With --json the username, workspace, host, API URL and auth type ("basic"
+for username + App Password, "bearer" for access tokens) are printed too.
+
+Examples:
+  curl -u "$USER:$(bbc auth token)" https://api.bitbucket.org/2.0/user
+  bbc auth token --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			creds, err := f.GetCredentials()
+			if err != nil {
+				return err
+			}
+			if !asJSON {
+				_, err := fmt.Fprintln(f.IOStreams.Out, creds.Token)
+				return err
+			}
+
+			host, err := f.Host()
+			if err != nil {
+				return err
+			}
+			apiURL := host.APIURL
+			if apiURL == "" {
+				apiURL = bbcloud.DefaultBaseURL
+			}
+			return cmdutil.WriteJSON(f.IOStreams.Out, map[string]interface{}{
+				"host":      host.Name,
+				"api_url":   apiURL,
+				"auth":      host.Auth,
+				"username":  creds.Username,
+				"workspace": creds.Workspace,
+				"token":     creds.Token,
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Print the full credentials as JSON")
+
+	return cmd
+}
@@ -0,0 +1,183 @@
+package browse
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+type browseOptions struct {
+	repo      string
+	noBrowser bool
+	ref       string
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdBrowse creates the browse command
+func NewCmdBrowse(f *cmdutil.Factory) *cobra.Command {
+	opts := &browseOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "browse",
+		Short: "Open a repository, PR, file, or pipeline in the browser",
+		Long: `Open Bitbucket pages in the default web browser.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+Without a subcommand the repository overview is opened. Use --no-browser
+to print the URL instead of launching a browser.
+
+Examples:
+  # Open the repository
+  bbc browse --repo test_repo
+
+  # Open a pull request
+  bbc browse pr 450 --repo test_repo
+
+  # Open a file at a branch, tag, or commit
+  bbc browse file src/auth.ts --ref develop --repo test_repo
+
+  # Print a pipeline URL
+  bbc browse pipeline 1234 --repo test_repo --no-browser`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBrowse(cmd.Context(), opts, func(repo *bbcloud.Repository, _ *bbcloud.Client) (string, error) {
+				return repoURL(repo)
+			})
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+	cmd.PersistentFlags().BoolVar(&opts.noBrowser, "no-browser", false, "Print the URL instead of opening a browser")
+
+	cmd.AddCommand(newCmdBrowsePR(opts))
+	cmd.AddCommand(newCmdBrowseFile(opts))
+	cmd.AddCommand(newCmdBrowsePipeline(opts))
+
+	return cmd
+}
+
+func newCmdBrowsePR(opts *browseOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "pr <pr-number>",
+		Short: "Open a pull request",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			prNum, err := strconv.Atoi(args[0])
+			if err != nil || prNum <= 0 {
+				return fmt.Errorf("invalid PR number: %s", args[0])
+			}
+			return runBrowse(cmd.Context(), opts, func(repo *bbcloud.Repository, client *bbcloud.Client) (string, error) {
+				pr, err := client.GetPullRequest(cmd.Context(), repo.Slug, prNum)
+				if err != nil {
+					return "", err
+				}
+				if pr.Links.HTML == nil || pr.Links.HTML.Href == "" {
+					return "", fmt.Errorf("PR %d has no web link", prNum)
+				}
+				return pr.Links.HTML.Href, nil
+			})
+		},
+	}
+}
+
+func newCmdBrowseFile(opts *browseOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "file <path>",
+		Short: "Open a file at a ref",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filePath := strings.TrimPrefix(args[0], "/")
+			return runBrowse(cmd.Context(), opts, func(repo *bbcloud.Repository, _ *bbcloud.Client) (string, error) {
+				base, err := repoURL(repo)
+				if err != nil {
+					return "", err
+				}
+				ref := opts.ref
+				if ref == "" {
+					if repo.MainBranch == nil || repo.MainBranch.Name == "" {
+						return "", fmt.Errorf("repository has no main branch (set --ref)")
+					}
+					ref = repo.MainBranch.Name
+				}
+				return fmt.Sprintf("%s/src/%s/%s", base, url.PathEscape(ref), escapePath(filePath)), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ref, "ref", "", "Branch, tag, or commit (default: repo main branch)")
+
+	return cmd
+}
+
+func newCmdBrowsePipeline(opts *browseOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "pipeline <build-number>",
+		Short: "Open a pipeline run",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			build, err := strconv.Atoi(args[0])
+			if err != nil || build <= 0 {
+				return fmt.Errorf("invalid build number: %s", args[0])
+			}
+			return runBrowse(cmd.Context(), opts, func(repo *bbcloud.Repository, _ *bbcloud.Client) (string, error) {
+				base, err := repoURL(repo)
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%s/pipelines/results/%d", base, build), nil
+			})
+		},
+	}
+}
+
+// runBrowse resolves the repository, builds the target URL, and opens or prints it
+func runBrowse(ctx context.Context, opts *browseOptions, target func(*bbcloud.Repository, *bbcloud.Client) (string, error)) error {
+	client, err := opts.factory.NewBBCloudClient("")
+	if err != nil {
+		return err
+	}
+
+	repo, err := client.GetRepository(ctx, opts.repo)
+	if err != nil {
+		return err
+	}
+
+	link, err := target(repo, client)
+	if err != nil {
+		return err
+	}
+
+	ios, _ := opts.factory.Streams()
+	if opts.noBrowser {
+		_, _ = fmt.Fprintln(ios.Out, link)
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(ios.ErrOut, "Opening %s in your browser.\n", link)
+	return opts.factory.Browser.Browse(link)
+}
+
+func repoURL(repo *bbcloud.Repository) (string, error) {
+	if repo.Links.HTML == nil || repo.Links.HTML.Href == "" {
+		return "", fmt.Errorf("repository %s has no web link", repo.Slug)
+	}
+	return strings.TrimSuffix(repo.Links.HTML.Href, "/"), nil
+}
+
+// escapePath escapes each segment of a slash-separated file path
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,49 @@
+package browse
+
+import (
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/iostreams"
+)
+
+func TestCommandStructure(t *testing.T) {
+	ios := iostreams.System()
+	factory := cmdutil.NewFactory("test", ios)
+
+	cmd := NewCmdBrowse(factory)
+
+	names := make(map[string]bool)
+	for _, sub := range cmd.Commands() {
+		names[sub.Name()] = true
+	}
+	for _, name := range []string{"pr", "file", "pipeline"} {
+		if !names[name] {
+			t.Errorf("expected %q subcommand", name)
+		}
+	}
+
+	if cmd.PersistentFlags().Lookup("no-browser") == nil {
+		t.Error("expected --no-browser flag")
+	}
+	if cmd.PersistentFlags().Lookup("repo") == nil {
+		t.Error("expected --repo flag")
+	}
+}
+
+func TestEscapePath(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "src/auth.ts", want: "src/auth.ts"},
+		{in: "docs/My File.md", want: "docs/My%20File.md"},
+		{in: "a/b#c", want: "a/b%23c"},
+	}
+
+	for _, tt := range tests {
+		if got := escapePath(tt.in); got != tt.want {
+			t.Errorf("escapePath(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
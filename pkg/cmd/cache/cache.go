@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// areaDescriptions names what each directory of the cache holds
+var areaDescriptions = map[string]string{
+	"http":       "API responses (offline reads and reuse of fresh PR data)",
+	"completion": "Shell completion candidates",
+	"inbox":      "Inbox watermarks",
+}
+
+// NewCmdCache creates the cache command group
+func NewCmdCache(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache <command>",
+		Short: "Inspect and clean bb's disk cache",
+		Long: `Inspect and clean the files bb caches on disk: API responses,
+completion candidates and inbox watermarks. Everything in the cache can be
+rebuilt, so clearing it only costs requests.
+
+The cache lives in ~/.cache/bb (see BB_CONFIG_DIR and XDG_CACHE_HOME). Pass
+--no-cache to any command to fetch fresh data without reading or writing
+cached API responses.`,
+	}
+
+	cmd.AddCommand(NewCmdInfo(f))
+	cmd.AddCommand(NewCmdClear(f))
+	cmd.AddCommand(NewCmdPrune(f))
+
+	return cmd
+}
+
+// area is one directory of the cache
+type area struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Files       int        `json:"files"`
+	Bytes       int64      `json:"bytes"`
+	Oldest      *time.Time `json:"oldest,omitempty"`
+	Newest      *time.Time `json:"newest,omitempty"`
+}
+
+// scan sums the files of each area of the cache at dir; files at the top
+// level are counted under "other"
+func scan(dir string) ([]area, error) {
+	areas := map[string]*area{}
+	err := walkFiles(dir, func(path string, info fs.FileInfo) error {
+		name := "other"
+		if rel, _ := filepath.Rel(dir, path); strings.Contains(rel, string(filepath.Separator)) {
+			name = strings.SplitN(rel, string(filepath.Separator), 2)[0]
+		}
+		a := areas[name]
+		if a == nil {
+			a = &area{Name: name, Description: areaDescriptions[name]}
+			areas[name] = a
+		}
+		a.Files++
+		a.Bytes += info.Size()
+		mod := info.ModTime().UTC()
+		if a.Oldest == nil || mod.Before(*a.Oldest) {
+			a.Oldest = &mod
+		}
+		if a.Newest == nil || mod.After(*a.Newest) {
+			a.Newest = &mod
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]area, 0, len(areas))
+	for _, a := range areas {
+		result = append(result, *a)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// walkFiles calls visit for every regular file under dir; a missing dir has
+// no files
+func walkFiles(dir string, visit func(path string, info fs.FileInfo) error) error {
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			// Removed since the directory was read
+			return nil
+		}
+		return visit(path, info)
+	})
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ghoseb/bb/internal/config"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/iostreams"
+)
+
+func newTestFactory(t *testing.T) (*cmdutil.Factory, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	ios := &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: out, ErrOut: errOut}
+	return cmdutil.NewFactory("test", ios), out, errOut
+}
+
+// writeCacheFile writes a cache file of size bytes last modified age ago
+func writeCacheFile(t *testing.T, rel string, size int, age time.Duration) {
+	t.Helper()
+	path := filepath.Join(config.CacheDir(), rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	mod := time.Now().Add(-age)
+	if err := os.Chtimes(path, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCacheInfoPruneClear(t *testing.T) {
+	f, out, errOut := newTestFactory(t)
+	writeCacheFile(t, "http/old.json", 100, 10*24*time.Hour)
+	writeCacheFile(t, "http/new.json", 50, time.Hour)
+	writeCacheFile(t, "inbox/default.json", 10, 2*24*time.Hour)
+
+	info := NewCmdInfo(f)
+	info.SetArgs([]string{"--json"})
+	if err := info.Execute(); err != nil {
+		t.Fatalf("info: %v", err)
+	}
+	for _, want := range []string{`"files": 3`, `"bytes": 160`, `"name": "http"`, `"name": "inbox"`} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("info output missing %s:\n%s", want, out.String())
+		}
+	}
+
+	prune := NewCmdPrune(f)
+	prune.SetArgs([]string{"--older-than", "7d"})
+	if err := prune.Execute(); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if !strings.Contains(errOut.String(), "Deleted 1 files (100 B)") {
+		t.Errorf("prune reported %q", errOut.String())
+	}
+	if _, err := os.Stat(filepath.Join(config.CacheDir(), "http", "old.json")); !os.IsNotExist(err) {
+		t.Error("expected the 10-day-old response to be pruned")
+	}
+	if _, err := os.Stat(filepath.Join(config.CacheDir(), "http", "new.json")); err != nil {
+		t.Errorf("expected the recent response to be kept: %v", err)
+	}
+
+	clear := NewCmdClear(f)
+	clear.SetArgs([]string{})
+	if err := clear.Execute(); err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	areas, err := scan(config.CacheDir())
+	if err != nil || len(areas) != 0 {
+		t.Errorf("after clear, scan = %v, %v; want empty", areas, err)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[int64]string{0: "0 B", 1023: "1023 B", 1536: "1.5 KiB", 5 << 20: "5.0 MiB"} {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
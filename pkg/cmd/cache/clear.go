@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/internal/config"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// NewCmdClear creates the cache clear command
+func NewCmdClear(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Delete everything in the cache",
+		Long: `Delete every cached file. Later commands fetch from the API again;
+--offline has nothing to answer from until they have.
+
+Examples:
+  bbc cache clear`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := config.CacheDir()
+			areas, err := scan(dir)
+			if err != nil {
+				return fmt.Errorf("read cache: %w", err)
+			}
+			if err := os.RemoveAll(dir); err != nil {
+				return fmt.Errorf("clear cache: %w", err)
+			}
+
+			var files int
+			var bytes int64
+			for _, a := range areas {
+				files += a.Files
+				bytes += a.Bytes
+			}
+			ios, _ := f.Streams()
+			_, _ = fmt.Fprintf(ios.ErrOut, "Deleted %d files (%s) from %s\n", files, formatBytes(bytes), dir)
+			return nil
+		},
+	}
+
+	return cmd
+}
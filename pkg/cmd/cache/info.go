@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/internal/config"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+type infoOptions struct {
+	json bool
+
+	factory *cmdutil.Factory
+}
+
+type infoOutput struct {
+	Dir   string `json:"dir"`
+	Files int    `json:"files"`
+	Bytes int64  `json:"bytes"`
+	Areas []area `json:"areas"`
+}
+
+// NewCmdInfo creates the cache info command
+func NewCmdInfo(f *cmdutil.Factory) *cobra.Command {
+	opts := &infoOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "info",
+		Short: "Show where the cache is and how much it holds",
+		Long: `Show the cache directory and, for each kind of cached data, how many
+files it holds, their size, and when the oldest and newest were written.
+
+Examples:
+  bbc cache info
+  bbc cache info --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := config.CacheDir()
+			areas, err := scan(dir)
+			if err != nil {
+				return fmt.Errorf("read cache: %w", err)
+			}
+
+			out := infoOutput{Dir: dir, Areas: areas}
+			for _, a := range areas {
+				out.Files += a.Files
+				out.Bytes += a.Bytes
+			}
+
+			ios, _ := opts.factory.Streams()
+			if opts.json {
+				return cmdutil.WriteJSON(ios.Out, out)
+			}
+			return renderInfo(ios.Out, out)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of text")
+
+	return cmd
+}
+
+func renderInfo(w io.Writer, out infoOutput) error {
+	_, _ = fmt.Fprintf(w, "Cache: %s\n", out.Dir)
+	if out.Files == 0 {
+		_, _ = fmt.Fprintln(w, "Empty")
+		return nil
+	}
+	_, _ = fmt.Fprintf(w, "Total: %d files, %s\n\n", out.Files, formatBytes(out.Bytes))
+
+	tw := tabwriter.NewWriter(w, 0, 0, cmdutil.TablePadding, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "AREA\tFILES\tSIZE\tOLDEST\tNEWEST\tHOLDS")
+	for _, a := range out.Areas {
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\n", a.Name, a.Files, formatBytes(a.Bytes),
+			a.Oldest.Local().Format("2006-01-02 15:04"), a.Newest.Local().Format("2006-01-02 15:04"),
+			a.Description)
+	}
+	return tw.Flush()
+}
+
+// formatBytes renders a size in binary units
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGT"[exp])
+}
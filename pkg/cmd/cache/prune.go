@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/internal/config"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+type pruneOptions struct {
+	olderThan string
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdPrune creates the cache prune command
+func NewCmdPrune(f *cmdutil.Factory) *cobra.Command {
+	opts := &pruneOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete cached files older than an age",
+		Long: `Delete cached files last written before --older-than, an age such as
+7d or 36h or a date (2006-01-02). API responses also expire on their own
+after 30 days.
+
+Examples:
+  bbc cache prune --older-than 7d
+  bbc cache prune --older-than 2026-01-01`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cutoff, err := cmdutil.ParseSince(opts.olderThan, time.Now())
+			if err != nil {
+				return fmt.Errorf("--older-than: %w", err)
+			}
+
+			dir := config.CacheDir()
+			files, bytes, err := prune(dir, cutoff)
+			if err != nil {
+				return fmt.Errorf("prune cache: %w", err)
+			}
+
+			ios, _ := opts.factory.Streams()
+			_, _ = fmt.Fprintf(ios.ErrOut, "Deleted %d files (%s) from %s\n", files, formatBytes(bytes), dir)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.olderThan, "older-than", "30d", "Delete files last written before this age or date")
+
+	return cmd
+}
+
+// prune deletes the files under dir last modified before cutoff and returns
+// how many it deleted and their size. Directories left empty are kept.
+func prune(dir string, cutoff time.Time) (int, int64, error) {
+	var files int
+	var bytes int64
+	err := walkFiles(dir, func(path string, info fs.FileInfo) error {
+		if !info.ModTime().Before(cutoff) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove %s: %w", filepath.Base(path), err)
+		}
+		files++
+		bytes += info.Size()
+		return nil
+	})
+	return files, bytes, err
+}
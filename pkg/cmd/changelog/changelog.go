@@ -0,0 +1,363 @@
+package changelog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+// Section titles that do not come from configured labels
+const (
+	sectionBreaking = "Breaking Changes"
+	sectionOther    = "Other Changes"
+)
+
+// typeSections maps conventional-commit types to their section
+var typeSections = map[string]string{
+	"feat":     "Features",
+	"feature":  "Features",
+	"fix":      "Bug Fixes",
+	"bugfix":   "Bug Fixes",
+	"perf":     "Performance",
+	"refactor": "Refactoring",
+	"docs":     "Documentation",
+	"test":     "Tests",
+	"tests":    "Tests",
+	"build":    "Build and CI",
+	"ci":       "Build and CI",
+	"chore":    "Chores",
+	"style":    "Chores",
+	"revert":   "Reverts",
+}
+
+// typeSectionOrder is the order of the conventional-commit sections
+var typeSectionOrder = []string{
+	"Features", "Bug Fixes", "Performance", "Refactoring", "Documentation",
+	"Tests", "Build and CI", "Chores", "Reverts",
+}
+
+// conventionalTitle matches "type(scope)!: description"
+var conventionalTitle = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?:\s*(.+)$`)
+
+type changelogOptions struct {
+	repo   string
+	since  string
+	branch string
+	json   bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdChangelog creates the changelog command
+func NewCmdChangelog(f *cmdutil.Factory) *cobra.Command {
+	opts := &changelogOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "changelog",
+		Short: "Generate release notes from merged PRs",
+		Long: `Generate markdown release notes from the pull requests merged into a branch
+after a tag, date or age.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+The commits on --branch (default: the repository's main branch) that are not
+reachable from the --since tag, or are newer than the --since date, are
+matched with the merge commits of the merged pull requests.
+
+Pull requests are grouped by the conventional-commit type of their title
+(feat: ..., fix(api): ...); a "!" after the type puts them under Breaking
+Changes. Labels in titles, such as [security], get their own sections when
+configured in changelog.labels as label=Section entries:
+
+  bbc config set changelog.labels "security=Security,ux=User Experience"
+
+Titles matching neither are listed under Other Changes.
+
+Examples:
+  bbc changelog --repo api --since v1.2.0
+  bbc changelog --repo api --since 2026-01-01 --branch release/2.x
+  bbc changelog --repo api --since 14d --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.since == "" {
+				return fmt.Errorf("--since is required")
+			}
+			return runChangelog(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+	cmd.Flags().StringVar(&opts.since, "since", "", "Tag, date or age (e.g. v1.2.0, 2026-01-01, 14d) to list changes after")
+	cmd.Flags().StringVar(&opts.branch, "branch", "", "Branch the PRs were merged into (default: the repository's main branch)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of markdown")
+
+	return cmd
+}
+
+type entry struct {
+	ID       int    `json:"id"`
+	Title    string `json:"title"`
+	Type     string `json:"type,omitempty"`
+	Scope    string `json:"scope,omitempty"`
+	Breaking bool   `json:"breaking,omitempty"`
+	Author   string `json:"author,omitempty"`
+	MergedOn string `json:"merged_on,omitempty"`
+	URL      string `json:"url,omitempty"`
+
+	// summary is the title without its type, scope and label
+	summary string
+}
+
+type section struct {
+	Title   string  `json:"title"`
+	Entries []entry `json:"entries"`
+}
+
+type changelogOutput struct {
+	Repo     string    `json:"repo"`
+	Branch   string    `json:"branch"`
+	Since    string    `json:"since"`
+	Sections []section `json:"sections"`
+}
+
+// labelSection is a configured title label and its section
+type labelSection struct {
+	label   string
+	section string
+}
+
+func runChangelog(ctx context.Context, opts *changelogOptions) error {
+	f := opts.factory
+	cfg, err := f.Config()
+	if err != nil {
+		return err
+	}
+	labels := parseLabels(cfg.ChangelogLabels())
+
+	client, err := f.NewBBCloudClient("")
+	if err != nil {
+		return err
+	}
+
+	branch := opts.branch
+	if branch == "" {
+		repo, err := client.GetRepository(ctx, opts.repo)
+		if err != nil {
+			return err
+		}
+		if repo.MainBranch == nil || repo.MainBranch.Name == "" {
+			return fmt.Errorf("repository %s has no main branch; pass --branch", opts.repo)
+		}
+		branch = repo.MainBranch.Name
+	}
+
+	commitOpts, after, err := resolveSince(ctx, client, opts.repo, opts.since)
+	if err != nil {
+		return err
+	}
+	commitOpts.Include = branch
+	commits, err := client.ListCommits(ctx, opts.repo, commitOpts)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("destination.branch.name = %q AND updated_on >= %s",
+		branch, after.UTC().Truncate(time.Second).Format(time.RFC3339))
+	prs, err := client.QueryPullRequests(ctx, opts.repo, bbcloud.PRListOptions{State: "MERGED", Query: query})
+	if err != nil {
+		return err
+	}
+
+	output := changelogOutput{
+		Repo:     opts.repo,
+		Branch:   branch,
+		Since:    opts.since,
+		Sections: group(mergedPRs(prs, commits), labels),
+	}
+
+	ios := f.IOStreams
+	if opts.json {
+		return cmdutil.WriteJSON(ios.Out, output)
+	}
+	if len(output.Sections) == 0 {
+		_, _ = fmt.Fprintf(ios.ErrOut, "no pull requests merged into %s since %s\n", branch, opts.since)
+		return nil
+	}
+	renderMarkdown(ios.Out, output)
+	return nil
+}
+
+// resolveSince turns --since into the commit range to list and the time no
+// change can predate. A date or age bounds the commits by time; anything else
+// is a tag whose history is excluded.
+func resolveSince(ctx context.Context, client *bbcloud.Client, repo, since string) (bbcloud.CommitListOptions, time.Time, error) {
+	if t, err := cmdutil.ParseSince(since, time.Now()); err == nil {
+		return bbcloud.CommitListOptions{Since: t}, t, nil
+	}
+	tag, err := client.GetTag(ctx, repo, since)
+	if err != nil {
+		return bbcloud.CommitListOptions{}, time.Time{}, fmt.Errorf("--since %q is neither a date, an age nor a tag: %w", since, err)
+	}
+	var after time.Time
+	if tag.Target != nil {
+		after = tag.Target.Date
+	}
+	return bbcloud.CommitListOptions{Exclude: tag.Name}, after, nil
+}
+
+// mergedPRs returns the pull requests whose merge commit is among commits,
+// oldest merge first, as entries
+func mergedPRs(prs []bbcloud.PullRequest, commits []bbcloud.CommitReference) []entry {
+	// Merge commits in PR listings may be abbreviated
+	byPrefix := make(map[string]bbcloud.CommitReference, len(commits))
+	for _, c := range commits {
+		byPrefix[shortHash(c.Hash)] = c
+	}
+
+	type merged struct {
+		pr bbcloud.PullRequest
+		at time.Time
+	}
+	var found []merged
+	for _, pr := range prs {
+		if pr.MergeCommit == nil || pr.MergeCommit.Hash == "" {
+			continue
+		}
+		c, ok := byPrefix[shortHash(pr.MergeCommit.Hash)]
+		if !ok || !strings.HasPrefix(c.Hash, pr.MergeCommit.Hash) {
+			continue
+		}
+		found = append(found, merged{pr: pr, at: c.Date})
+	}
+	slices.SortStableFunc(found, func(a, b merged) int {
+		return a.at.Compare(b.at)
+	})
+
+	entries := make([]entry, 0, len(found))
+	for _, m := range found {
+		e := entry{ID: m.pr.ID, Title: m.pr.Title, summary: m.pr.Title}
+		if m.pr.Author != nil {
+			e.Author = m.pr.Author.GetName()
+		}
+		if !m.at.IsZero() {
+			e.MergedOn = m.at.UTC().Format(time.RFC3339)
+		}
+		if m.pr.Links.HTML != nil {
+			e.URL = m.pr.Links.HTML.Href
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
+
+// parseLabels parses changelog.labels entries; an entry without "=" names a
+// label and its section alike
+func parseLabels(entries []string) []labelSection {
+	var labels []labelSection
+	for _, e := range entries {
+		label, section, found := strings.Cut(e, "=")
+		label = strings.Trim(strings.TrimSpace(label), "[]")
+		if !found {
+			section = label
+		}
+		if label == "" {
+			continue
+		}
+		labels = append(labels, labelSection{label: label, section: strings.TrimSpace(section)})
+	}
+	return labels
+}
+
+// group sorts entries into sections: Breaking Changes, then the configured
+// label sections, the conventional-commit types and Other Changes. Empty
+// sections are left out.
+func group(entries []entry, labels []labelSection) []section {
+	bySection := make(map[string][]entry)
+	for _, e := range entries {
+		name := classify(&e, labels)
+		bySection[name] = append(bySection[name], e)
+	}
+
+	order := []string{sectionBreaking}
+	for _, l := range labels {
+		order = append(order, l.section)
+	}
+	order = append(order, typeSectionOrder...)
+	order = append(order, sectionOther)
+
+	sections := []section{}
+	for _, name := range order {
+		if list, ok := bySection[name]; ok {
+			sections = append(sections, section{Title: name, Entries: list})
+			delete(bySection, name)
+		}
+	}
+	return sections
+}
+
+// classify parses e's title and returns its section
+func classify(e *entry, labels []labelSection) string {
+	title := e.Title
+	labelled := ""
+	for _, l := range labels {
+		tag := "[" + l.label + "]"
+		if i := strings.Index(strings.ToLower(title), strings.ToLower(tag)); i >= 0 {
+			title = strings.Join(strings.Fields(title[:i]+title[i+len(tag):]), " ")
+			labelled = l.section
+			break
+		}
+	}
+	e.summary = title
+
+	section := sectionOther
+	if m := conventionalTitle.FindStringSubmatch(title); m != nil {
+		if s, ok := typeSections[strings.ToLower(m[1])]; ok {
+			e.Type, e.Scope, e.Breaking, e.summary = strings.ToLower(m[1]), m[2], m[3] == "!", m[4]
+			section = s
+		}
+	}
+	switch {
+	case e.Breaking:
+		return sectionBreaking
+	case labelled != "":
+		return labelled
+	}
+	return section
+}
+
+func renderMarkdown(w io.Writer, output changelogOutput) {
+	_, _ = fmt.Fprintf(w, "## Changes since %s\n", output.Since)
+	for _, s := range output.Sections {
+		_, _ = fmt.Fprintf(w, "\n### %s\n\n", s.Title)
+		for _, e := range s.Entries {
+			var b strings.Builder
+			b.WriteString("- ")
+			if e.Scope != "" {
+				fmt.Fprintf(&b, "**%s:** ", text.Line(e.Scope))
+			}
+			fmt.Fprintf(&b, "%s (#%d", text.Line(e.summary), e.ID)
+			if e.Author != "" {
+				fmt.Fprintf(&b, ", @%s", text.Line(e.Author))
+			}
+			b.WriteString(")")
+			_, _ = fmt.Fprintln(w, b.String())
+		}
+	}
+}
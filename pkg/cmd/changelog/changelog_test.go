@@ -0,0 +1,107 @@
+package changelog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/bbtest"
+)
+
+func TestMergedPRs(t *testing.T) {
+	day := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	commits := []bbcloud.CommitReference{
+		{Hash: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", Date: day.Add(2 * time.Hour)},
+		{Hash: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", Date: day.Add(time.Hour)},
+	}
+	prs := []bbcloud.PullRequest{
+		{ID: 3, Title: "later", MergeCommit: &bbcloud.CommitReference{Hash: "bbbbbbbbbbbb"}},
+		{ID: 2, Title: "before the tag", MergeCommit: &bbcloud.CommitReference{Hash: "cccccccccccc"}},
+		{ID: 1, Title: "earlier", MergeCommit: &bbcloud.CommitReference{Hash: "aaaaaaaaaaaa"}, Author: &bbcloud.User{Username: "ada"}},
+		{ID: 4, Title: "no merge commit"},
+	}
+
+	entries := mergedPRs(prs, commits)
+	if len(entries) != 2 || entries[0].ID != 1 || entries[1].ID != 3 {
+		t.Fatalf("entries = %+v, want #1 then #3", entries)
+	}
+	if entries[0].Author != "ada" || entries[0].MergedOn != "2026-05-01T01:00:00Z" {
+		t.Errorf("first entry = %+v", entries[0])
+	}
+}
+
+func TestGroup(t *testing.T) {
+	titles := []string{
+		"feat(api): add pagination",
+		"fix: crash on empty diff",
+		"[Security] fix: escape comment markdown",
+		"refactor!: drop v1 endpoints",
+		"Update README",
+		"wip: something",
+	}
+	var entries []entry
+	for i, title := range titles {
+		entries = append(entries, entry{ID: i + 1, Title: title})
+	}
+	labels := parseLabels([]string{"security=Security", " [ux] "})
+
+	var out strings.Builder
+	renderMarkdown(&out, changelogOutput{Since: "v1.2.0", Sections: group(entries, labels)})
+	want := `## Changes since v1.2.0
+
+### Breaking Changes
+
+- drop v1 endpoints (#4)
+
+### Security
+
+- escape comment markdown (#3)
+
+### Features
+
+- **api:** add pagination (#1)
+
+### Bug Fixes
+
+- crash on empty diff (#2)
+
+### Other Changes
+
+- Update README (#5)
+- wip: something (#6)
+`
+	if out.String() != want {
+		t.Errorf("markdown =\n%s\nwant\n%s", out.String(), want)
+	}
+	if labels[1] != (labelSection{label: "ux", section: "ux"}) {
+		t.Errorf("bare label = %+v", labels[1])
+	}
+}
+
+func TestResolveSince(t *testing.T) {
+	srv := bbtest.NewServer(t)
+	client := srv.Client(t, "acme")
+	tagged := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+	srv.Handle(http.MethodGet, "/repositories/acme/api/refs/tags/v1.2.0", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(bbcloud.Tag{Name: "v1.2.0", Target: &bbcloud.CommitReference{Hash: "abc", Date: tagged}})
+	})
+
+	opts, after, err := resolveSince(context.Background(), client, "api", "v1.2.0")
+	if err != nil || opts.Exclude != "v1.2.0" || !opts.Since.IsZero() || !after.Equal(tagged) {
+		t.Errorf("tag = %+v, %v, %v", opts, after, err)
+	}
+
+	opts, after, err = resolveSince(context.Background(), client, "api", "2026-03-01")
+	if err != nil || opts.Exclude != "" || !opts.Since.Equal(after) || after.Day() != 1 {
+		t.Errorf("date = %+v, %v, %v", opts, after, err)
+	}
+
+	if _, _, err := resolveSince(context.Background(), client, "api", "v9"); err == nil {
+		t.Error("unknown tag accepted")
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	bbconfig "github.com/ghoseb/bb/internal/config"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// NewCmdConfig creates the config command group
+func NewCmdConfig(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config <command>",
+		Short: "Manage bb settings",
+		Long: `Read and write bb settings.
+
+User settings are stored in ~/.config/bb/config.yml (see BB_CONFIG_DIR and
+XDG_CONFIG_HOME). With --local, settings are stored in the current
+repository's git directory and override user settings for that repository.
+
+Known keys:
+` + knownKeys(),
+	}
+
+	cmd.AddCommand(NewCmdGet(f))
+	cmd.AddCommand(NewCmdSet(f))
+	cmd.AddCommand(NewCmdList(f))
+
+	return cmd
+}
+
+// knownKeys renders the option registry for help text
+func knownKeys() string {
+	var b strings.Builder
+	for _, opt := range bbconfig.Options {
+		fmt.Fprintf(&b, "  %-18s %s", opt.Key, opt.Description)
+		if len(opt.AllowedValues) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(opt.AllowedValues, ", "))
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// loadLocal opens the per-repository config file of the current repository
+func loadLocal(ctx context.Context, f *cmdutil.Factory) (*bbconfig.Config, error) {
+	gitDir, err := f.GitClient.CommonDir(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("--local requires a git repository: %w", err)
+	}
+	return bbconfig.Load(bbconfig.LocalPath(gitDir))
+}
+
+// loadTarget returns the config to read from or write to: the local file with
+// --local, otherwise the user file when writing or the effective config when reading
+func loadTarget(ctx context.Context, f *cmdutil.Factory, local, write bool) (*bbconfig.Config, error) {
+	if local {
+		return loadLocal(ctx, f)
+	}
+	if write {
+		return bbconfig.Load(bbconfig.DefaultPath())
+	}
+	return f.Config()
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	bbconfig "github.com/ghoseb/bb/internal/config"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/iostreams"
+)
+
+func newTestFactory(t *testing.T) (*cmdutil.Factory, *bytes.Buffer) {
+	t.Helper()
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+
+	out := &bytes.Buffer{}
+	ios := &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: out, ErrOut: &bytes.Buffer{}}
+	f := cmdutil.NewFactory("test", ios)
+	f.GitClient.Dir = t.TempDir()
+	return f, out
+}
+
+func TestConfigSetAndGet(t *testing.T) {
+	f, out := newTestFactory(t)
+
+	set := NewCmdSet(f)
+	set.SetArgs([]string{"default_repo", "test_repo"})
+	if err := set.Execute(); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	cfg, err := bbconfig.Load(bbconfig.DefaultPath())
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := cfg.Repo(); got != "test_repo" {
+		t.Errorf("saved default_repo = %q, want %q", got, "test_repo")
+	}
+
+	out.Reset()
+	get := NewCmdGet(cmdutil.NewFactory("test", f.IOStreams))
+	get.SetArgs([]string{"git_protocol"})
+	if err := get.Execute(); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got := strings.TrimSpace(out.String()); got != "https" {
+		t.Errorf("get git_protocol = %q, want default %q", got, "https")
+	}
+}
+
+func TestConfigSetValidation(t *testing.T) {
+	f, _ := newTestFactory(t)
+
+	for _, args := range [][]string{
+		{"no_such_key", "x"},
+		{"format", "yaml"},
+	} {
+		cmd := NewCmdSet(f)
+		cmd.SetArgs(args)
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		if err := cmd.Execute(); err == nil {
+			t.Errorf("set %v: expected error", args)
+		}
+	}
+}
+
+func TestConfigLocalRequiresRepository(t *testing.T) {
+	f, _ := newTestFactory(t)
+
+	cmd := NewCmdSet(f)
+	cmd.SetArgs([]string{"default_repo", "test_repo", "--local"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected --local outside a repository to fail")
+	}
+}
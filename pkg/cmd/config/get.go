@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	bbconfig "github.com/ghoseb/bb/internal/config"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+type getOptions struct {
+	key   string
+	local bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdGet creates the config get command
+func NewCmdGet(f *cmdutil.Factory) *cobra.Command {
+	opts := &getOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print the value of a setting",
+		Long: `Print the effective value of a setting.
+
+Unset keys print their default. With --local only the current repository's
+settings are consulted.
+
+Examples:
+  bbc config get default_repo
+  bbc config get git_protocol --local`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.key = args[0]
+			if _, ok := bbconfig.LookupOption(opts.key); !ok {
+				return fmt.Errorf("unknown config key %q", opts.key)
+			}
+
+			cfg, err := loadTarget(cmd.Context(), opts.factory, opts.local, false)
+			if err != nil {
+				return err
+			}
+
+			value := cfg.Get(opts.key)
+			if !opts.local {
+				value = cfg.GetOrDefault(opts.key)
+			}
+
+			ios, _ := opts.factory.Streams()
+			_, _ = fmt.Fprintln(ios.Out, value)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.local, "local", false, "Read only the current repository's settings")
+
+	return cmd
+}
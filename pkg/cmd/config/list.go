@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	bbconfig "github.com/ghoseb/bb/internal/config"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+type listOptions struct {
+	local bool
+	json  bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdList creates the config list command
+func NewCmdList(f *cmdutil.Factory) *cobra.Command {
+	opts := &listOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List settings",
+		Long: `List the effective value of every known setting.
+
+With --local only settings stored for the current repository are listed.
+
+Examples:
+  bbc config list
+  bbc config list --local --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadTarget(cmd.Context(), opts.factory, opts.local, false)
+			if err != nil {
+				return err
+			}
+
+			settings := make(map[string]string)
+			var keys []string
+			for _, opt := range bbconfig.Options {
+				if opt.IsPattern() {
+					for _, k := range cfg.Keys() {
+						if opt.Match(k) {
+							settings[k] = cfg.Get(k)
+							keys = append(keys, k)
+						}
+					}
+					continue
+				}
+				value := cfg.GetOrDefault(opt.Key)
+				if opts.local {
+					value = cfg.Get(opt.Key)
+				}
+				if value == "" && opts.local {
+					continue
+				}
+				settings[opt.Key] = value
+				keys = append(keys, opt.Key)
+			}
+
+			ios, _ := opts.factory.Streams()
+			if opts.json {
+				return cmdutil.WriteJSON(ios.Out, settings)
+			}
+
+			for _, k := range keys {
+				_, _ = fmt.Fprintf(ios.Out, "%s=%s\n", k, settings[k])
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.local, "local", false, "List only the current repository's settings")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of text")
+
+	return cmd
+}
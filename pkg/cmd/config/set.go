@@ -0,0 +1,61 @@
+package config
+
+import (
+	"github.com/spf13/cobra"
+
+	bbconfig "github.com/ghoseb/bb/internal/config"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+type setOptions struct {
+	key   string
+	value string
+	local bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdSet creates the config set command
+func NewCmdSet(f *cmdutil.Factory) *cobra.Command {
+	opts := &setOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Update a setting",
+		Long: `Update a setting in the user config, or in the current repository with --local.
+
+Keys and values are validated against the known settings listed in
+'bbc config --help'.
+
+Examples:
+  bbc config set default_repo test_repo
+  bbc config set git_protocol ssh --local`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.key, opts.value = args[0], args[1]
+			if err := bbconfig.Validate(opts.key, opts.value); err != nil {
+				return err
+			}
+
+			cfg, err := loadTarget(cmd.Context(), opts.factory, opts.local, true)
+			if err != nil {
+				return err
+			}
+
+			cfg.Set(opts.key, opts.value)
+			if err := cfg.Save(); err != nil {
+				return err
+			}
+
+			return cmdutil.WriteJSON(opts.factory.IOStreams.Out, map[string]interface{}{
+				"key":   opts.key,
+				"value": opts.value,
+				"file":  cfg.Path(),
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.local, "local", false, "Store the setting for the current repository only")
+
+	return cmd
+}
@@ -0,0 +1,253 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+type dashboardOptions struct {
+	workspaces []string
+	json       bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdDashboard creates the dashboard command
+func NewCmdDashboard(f *cmdutil.Factory) *cobra.Command {
+	opts := &dashboardOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "dashboard",
+		Short: "Show your open PRs, review requests and failing builds",
+		Long: `Show a start-of-day overview across every workspace you belong to: your
+open pull requests, open pull requests awaiting your approval, and your PRs
+whose latest build failed.
+
+Each workspace costs one API call per repository plus one per PR you
+authored, so use --workspace to restrict the view on large accounts.
+
+Examples:
+  bb dashboard
+  bb dashboard --workspace acme --workspace acme-labs
+  bb dashboard --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDashboard(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&opts.workspaces, "workspace", "w", nil, "Only these workspaces (default: all you belong to)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of markdown")
+
+	return cmd
+}
+
+type dashboardPR struct {
+	Workspace   string `json:"workspace"`
+	Repo        string `json:"repo"`
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Author      string `json:"author"`
+	Source      string `json:"source"`
+	Target      string `json:"target"`
+	Updated     string `json:"updated"`
+	BuildStatus string `json:"build_status,omitempty"`
+}
+
+type dashboardOutput struct {
+	User            string        `json:"user"`
+	Workspaces      []string      `json:"workspaces"`
+	Authored        []dashboardPR `json:"authored"`
+	AwaitingReview  []dashboardPR `json:"awaiting_review"`
+	FailingBuilds   []dashboardPR `json:"failing_builds"`
+	FailedWorkspace []string      `json:"failed_workspaces,omitempty"` // could not be queried
+}
+
+// workspaceView is one workspace's share of the dashboard
+type workspaceView struct {
+	authored, awaiting []dashboardPR
+}
+
+func runDashboard(ctx context.Context, opts *dashboardOptions) error {
+	client, err := opts.factory.NewBBCloudClient("")
+	if err != nil {
+		return err
+	}
+	me, err := client.CurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	workspaces := opts.workspaces
+	if len(workspaces) == 0 {
+		permissions, err := client.ListWorkspaces(ctx)
+		if err != nil {
+			return err
+		}
+		for _, p := range permissions {
+			workspaces = append(workspaces, p.Workspace.Slug)
+		}
+	}
+
+	ios, _ := opts.factory.Streams()
+	views := make([]*workspaceView, len(workspaces))
+	var g errgroup.Group
+	g.SetLimit(2)
+	for i, ws := range workspaces {
+		g.Go(func() error {
+			view, err := fetchWorkspaceView(ctx, opts.factory, ws, me)
+			if err != nil {
+				// Non-critical: the other workspaces are still shown
+				_, _ = fmt.Fprintf(ios.ErrOut, "warning: failed to query workspace %s: %v\n", ws, err)
+				return nil
+			}
+			views[i] = view
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	output := dashboardOutput{
+		User:           me.DisplayName,
+		Workspaces:     workspaces,
+		Authored:       make([]dashboardPR, 0),
+		AwaitingReview: make([]dashboardPR, 0),
+		FailingBuilds:  make([]dashboardPR, 0),
+	}
+	for i, view := range views {
+		if view == nil {
+			output.FailedWorkspace = append(output.FailedWorkspace, workspaces[i])
+			continue
+		}
+		output.Authored = append(output.Authored, view.authored...)
+		output.AwaitingReview = append(output.AwaitingReview, view.awaiting...)
+		for _, pr := range view.authored {
+			if pr.BuildStatus == "FAILED" {
+				output.FailingBuilds = append(output.FailingBuilds, pr)
+			}
+		}
+	}
+
+	if opts.json {
+		if err := cmdutil.WriteJSON(ios.Out, output); err != nil {
+			return fmt.Errorf("encode output: %w", err)
+		}
+		return nil
+	}
+
+	return renderMarkdownDashboard(ios.Out, output)
+}
+
+// fetchWorkspaceView finds the open PRs in ws that me authored or reviews,
+// and the build status of those me authored
+func fetchWorkspaceView(ctx context.Context, f *cmdutil.Factory, ws string, me *bbcloud.User) (*workspaceView, error) {
+	client, err := f.NewBBCloudClient(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("(%s OR %s)", bbcloud.UserQueryTerm("author", me.UUID), bbcloud.UserQueryTerm("reviewers", me.UUID))
+	prs, err := client.ListWorkspacePullRequests(ctx, bbcloud.PRListOptions{State: "OPEN", Query: query}, f.Concurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	authored, awaiting := splitPRs(prs, me)
+	view := &workspaceView{
+		authored: make([]dashboardPR, len(authored)),
+		awaiting: make([]dashboardPR, len(awaiting)),
+	}
+	for i := range awaiting {
+		view.awaiting[i] = newDashboardPR(ws, &awaiting[i])
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(f.Concurrency())
+	for i := range authored {
+		view.authored[i] = newDashboardPR(ws, &authored[i])
+		g.Go(func() error {
+			pr := &view.authored[i]
+			statuses, err := client.GetPRPipelines(gctx, pr.Repo, pr.ID)
+			if err != nil {
+				return fmt.Errorf("get build status for %s#%d: %w", pr.Repo, pr.ID, err)
+			}
+			pr.BuildStatus = "unknown"
+			if len(statuses) > 0 && statuses[0].State != "" {
+				pr.BuildStatus = statuses[0].State
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
+// splitPRs separates the PRs me authored from those awaiting my approval
+func splitPRs(prs []bbcloud.PullRequest, me *bbcloud.User) (authored, awaiting []bbcloud.PullRequest) {
+	for _, pr := range prs {
+		switch {
+		case pr.Author != nil && pr.Author.UUID == me.UUID:
+			authored = append(authored, pr)
+		case !pr.ApprovedBy(me.UUID):
+			awaiting = append(awaiting, pr)
+		}
+	}
+	return authored, awaiting
+}
+
+func newDashboardPR(ws string, pr *bbcloud.PullRequest) dashboardPR {
+	item := dashboardPR{
+		Workspace: ws,
+		ID:        pr.ID,
+		Title:     pr.Title,
+		Updated:   pr.UpdatedOn.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if pr.Author != nil {
+		item.Author = pr.Author.DisplayName
+	}
+	if pr.Source != nil && pr.Source.Branch != nil {
+		item.Source = pr.Source.Branch.Name
+	}
+	if pr.Destination != nil {
+		if pr.Destination.Branch != nil {
+			item.Target = pr.Destination.Branch.Name
+		}
+		if pr.Destination.Repository != nil {
+			item.Repo = pr.Destination.Repository.Slug
+		}
+	}
+	return item
+}
+
+func renderMarkdownDashboard(w io.Writer, output dashboardOutput) error {
+	_, _ = fmt.Fprintf(w, "# Dashboard — %s\n", output.User)
+	if len(output.FailedWorkspace) > 0 {
+		_, _ = fmt.Fprintf(w, "Incomplete: could not query %v\n", output.FailedWorkspace)
+	}
+
+	section := func(title string, prs []dashboardPR, showAuthor bool) {
+		_, _ = fmt.Fprintf(w, "\n## %s (%d)\n", title, len(prs))
+		for _, pr := range prs {
+			detail := pr.BuildStatus
+			if showAuthor {
+				detail = text.Line(pr.Author)
+			}
+			_, _ = fmt.Fprintf(w, "- %s/%s#%d %s (%s → %s, %s)\n",
+				pr.Workspace, pr.Repo, pr.ID, text.Line(pr.Title), text.Line(pr.Source), text.Line(pr.Target), detail)
+		}
+	}
+	section("Your open PRs", output.Authored, false)
+	section("Awaiting your review", output.AwaitingReview, true)
+	section("Failing builds on your branches", output.FailingBuilds, false)
+	return nil
+}
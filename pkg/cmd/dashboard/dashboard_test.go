@@ -0,0 +1,53 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+)
+
+func TestSplitPRs(t *testing.T) {
+	me := &bbcloud.User{UUID: "{me}"}
+	other := &bbcloud.User{UUID: "{other}"}
+	prs := []bbcloud.PullRequest{
+		{ID: 1, Author: me},
+		{ID: 2, Author: other},
+		{ID: 3, Author: other, Participants: []bbcloud.Participant{{User: me, Role: "REVIEWER", Approved: true}}},
+	}
+
+	authored, awaiting := splitPRs(prs, me)
+	if len(authored) != 1 || authored[0].ID != 1 {
+		t.Errorf("authored = %+v, want PR 1", authored)
+	}
+	if len(awaiting) != 1 || awaiting[0].ID != 2 {
+		t.Errorf("awaiting = %+v, want PR 2 (PR 3 is already approved)", awaiting)
+	}
+}
+
+func TestRenderMarkdownDashboard(t *testing.T) {
+	failing := dashboardPR{Workspace: "acme", Repo: "api", ID: 7, Title: "Fix login", Source: "fix", Target: "main", BuildStatus: "FAILED"}
+	output := dashboardOutput{
+		User:            "Alice",
+		Authored:        []dashboardPR{failing},
+		AwaitingReview:  []dashboardPR{{Workspace: "acme", Repo: "web", ID: 9, Title: "Add page", Author: "Bob", Source: "page", Target: "main"}},
+		FailingBuilds:   []dashboardPR{failing},
+		FailedWorkspace: []string{"labs"},
+	}
+
+	var out strings.Builder
+	if err := renderMarkdownDashboard(&out, output); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# Dashboard — Alice",
+		"could not query [labs]",
+		"## Your open PRs (1)\n- acme/api#7 Fix login (fix → main, FAILED)",
+		"## Awaiting your review (1)\n- acme/web#9 Add page (page → main, Bob)",
+		"## Failing builds on your branches (1)",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
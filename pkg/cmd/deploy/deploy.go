@@ -0,0 +1,21 @@
+package deploy
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// NewCmdDeploy creates the deploy command group
+func NewCmdDeploy(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deploy <command>",
+		Short: "Work with deployment environments",
+		Long: `Work with the deployment environments of a repository, the targets of
+Bitbucket Pipelines deployment steps.`,
+	}
+
+	cmd.AddCommand(NewCmdEnv(f))
+
+	return cmd
+}
@@ -0,0 +1,160 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+// NewCmdEnv creates the deploy env command group
+func NewCmdEnv(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "env <command>",
+		Short: "Work with deployment environments",
+	}
+
+	cmd.AddCommand(NewCmdEnvList(f))
+
+	return cmd
+}
+
+type envListOptions struct {
+	repo string
+	json bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdEnvList creates the deploy env list command
+func NewCmdEnvList(f *cmdutil.Factory) *cobra.Command {
+	opts := &envListOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List deployment environments",
+		Long: `List a repository's deployment environments in the order Bitbucket shows
+them: Test, then Staging, then Production environments.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+An environment is locked while a deployment to it runs; deployments to a
+locked environment wait. Admin-only environments can only be deployed to by
+repository admins.
+
+Examples:
+  bbc deploy env list --repo api
+  bbc deploy env list --repo api --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.factory.NewBBCloudClient("")
+			if err != nil {
+				return err
+			}
+			return runEnvList(cmd.Context(), opts, client)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of markdown (or a table with format: table)")
+
+	return cmd
+}
+
+type envInfo struct {
+	Name      string `json:"name"`
+	Slug      string `json:"slug,omitempty"`
+	Type      string `json:"type"`
+	Locked    bool   `json:"locked"`
+	AdminOnly bool   `json:"admin_only"`
+	Hidden    bool   `json:"hidden,omitempty"`
+	UUID      string `json:"uuid"`
+}
+
+type envListOutput struct {
+	Repo         string    `json:"repo"`
+	Environments []envInfo `json:"environments"`
+}
+
+func runEnvList(ctx context.Context, opts *envListOptions, client *bbcloud.Client) error {
+	envs, err := client.ListEnvironments(ctx, opts.repo)
+	if err != nil {
+		return err
+	}
+
+	output := envListOutput{Repo: opts.repo, Environments: make([]envInfo, 0, len(envs))}
+	for _, e := range envs {
+		output.Environments = append(output.Environments, newEnvInfo(e))
+	}
+
+	ios := opts.factory.IOStreams
+	if opts.json {
+		if err := cmdutil.WriteJSON(ios.Out, output); err != nil {
+			return fmt.Errorf("encode output: %w", err)
+		}
+		return nil
+	}
+
+	if opts.factory.Format() == cmdutil.FormatTable {
+		return renderTableEnvs(ios.Out, output.Environments)
+	}
+	return renderMarkdownEnvs(ios.Out, output)
+}
+
+func newEnvInfo(e bbcloud.Environment) envInfo {
+	info := envInfo{
+		Name:   e.Name,
+		Slug:   e.Slug,
+		Type:   e.TypeName(),
+		Locked: e.Locked(),
+		Hidden: e.Hidden,
+		UUID:   e.UUID,
+	}
+	if e.Restrictions != nil {
+		info.AdminOnly = e.Restrictions.AdminOnly
+	}
+	return info
+}
+
+// lockLabel describes the environment's lock for people
+func (e envInfo) lockLabel() string {
+	if e.Locked {
+		return "locked"
+	}
+	return "open"
+}
+
+// renderTableEnvs prints aligned columns for terminal reading
+func renderTableEnvs(w io.Writer, envs []envInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "NAME\tTYPE\tLOCK\tADMIN ONLY")
+	for _, e := range envs {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%t\n", text.Line(e.Name), e.Type, e.lockLabel(), e.AdminOnly)
+	}
+	return tw.Flush()
+}
+
+func renderMarkdownEnvs(w io.Writer, output envListOutput) error {
+	if len(output.Environments) == 0 {
+		_, _ = fmt.Fprintf(w, "# No deployment environments — %s\n", output.Repo)
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(w, "# Deployment environments — %s\n\n", output.Repo)
+	_, _ = fmt.Fprintf(w, "| Name | Type | Lock | Admin only |\n")
+	_, _ = fmt.Fprintf(w, "|------|------|------|------------|\n")
+	for _, e := range output.Environments {
+		admin := ""
+		if e.AdminOnly {
+			admin = "yes"
+		}
+		_, _ = fmt.Fprintf(w, "| %s | %s | %s | %s |\n", text.Line(e.Name), e.Type, e.lockLabel(), admin)
+	}
+	return nil
+}
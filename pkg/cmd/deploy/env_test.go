@@ -0,0 +1,64 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/bbtest"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/iostreams"
+)
+
+func TestEnvList(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	srv := bbtest.NewServer(t)
+	srv.Handle(http.MethodGet, "/repositories/acme/api/environments", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"values": [
+			{"uuid": "{p}", "name": "Production", "rank": 0, "environment_type": {"name": "Production", "rank": 2},
+			 "lock": {"type": "deployment_environment_lock_closed", "name": "CLOSED"}, "restrictions": {"admin_only": true}},
+			{"uuid": "{s2}", "name": "Staging EU", "rank": 1, "environment_type": {"name": "Staging", "rank": 1},
+			 "lock": {"type": "deployment_environment_lock_open", "name": "OPEN"}},
+			{"uuid": "{t}", "name": "Test", "rank": 0, "environment_type": {"name": "Test", "rank": 0}},
+			{"uuid": "{s1}", "name": "Staging", "rank": 0, "environment_type": {"name": "Staging", "rank": 1}}
+		]}`))
+	})
+	client := srv.Client(t, "acme")
+
+	run := func(asJSON bool) string {
+		t.Helper()
+		out := &bytes.Buffer{}
+		f := cmdutil.NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: out, ErrOut: &bytes.Buffer{}})
+		if err := runEnvList(context.Background(), &envListOptions{repo: "api", json: asJSON, factory: f}, client); err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+
+	var got envListOutput
+	if err := json.Unmarshal([]byte(run(true)), &got); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range got.Environments {
+		names = append(names, e.Name)
+	}
+	// By type, then rank within a type
+	if strings.Join(names, ",") != "Test,Staging,Staging EU,Production" {
+		t.Errorf("environments = %v", names)
+	}
+	if prod := got.Environments[3]; !prod.Locked || !prod.AdminOnly || prod.Type != "Production" {
+		t.Errorf("production = %+v", prod)
+	}
+	if got.Environments[2].Locked {
+		t.Errorf("an open lock reads as locked")
+	}
+
+	if md := run(false); !strings.Contains(md, "| Production | Production | locked | yes |") {
+		t.Errorf("markdown:\n%s", md)
+	}
+}
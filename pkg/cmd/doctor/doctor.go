@@ -0,0 +1,361 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/internal/config"
+	"github.com/ghoseb/bb/internal/secret"
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/git"
+)
+
+// networkTimeout bounds the reachability check
+const networkTimeout = 10 * time.Second
+
+// Check statuses
+const (
+	statusOK   = "ok"
+	statusWarn = "warn"
+	statusFail = "fail"
+	statusSkip = "skip"
+)
+
+type doctorOptions struct {
+	json bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdDoctor creates the doctor command
+func NewCmdDoctor(f *cmdutil.Factory) *cobra.Command {
+	opts := &doctorOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose setup problems",
+		Long: `Check that bbc can work here and suggest a fix for each problem found:
+
+  config       the configuration files parse and known settings are valid
+  network      the API of the active host is reachable
+  keyring      a keyring backend is available for stored credentials
+  credentials  the credentials are accepted by the API
+  scopes       the token grants every scope bbc needs
+  git          the current repository has a remote on the active host
+
+The exit status is 1 if any check failed; warnings do not fail.
+
+Examples:
+  bbc doctor
+  bbc doctor --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDoctor(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of text")
+
+	return cmd
+}
+
+type check struct {
+	Name   string `json:"name"`
+	Status string `json:"status"` // ok, warn, fail or skip
+	Detail string `json:"detail,omitempty"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+type doctorOutput struct {
+	OK     bool    `json:"ok"` // no check failed
+	Checks []check `json:"checks"`
+}
+
+func runDoctor(ctx context.Context, opts *doctorOptions) error {
+	f := opts.factory
+	output := doctorOutput{OK: true}
+	add := func(c check) {
+		output.Checks = append(output.Checks, c)
+		if c.Status == statusFail {
+			output.OK = false
+		}
+	}
+
+	add(checkConfig(f))
+
+	host, hostErr := f.Host()
+	if hostErr != nil {
+		add(check{Name: "network", Status: statusSkip, Detail: "no usable host"})
+	} else {
+		add(checkNetwork(ctx, apiURL(host)))
+	}
+
+	add(checkKeyring(f))
+
+	creds, scopes := checkCredentials(ctx, f, host, hostErr)
+	add(creds)
+	add(scopes)
+
+	add(checkGitRemote(ctx, f.GitClient, host, hostErr))
+
+	ios := f.IOStreams
+	if opts.json {
+		if err := cmdutil.WriteJSON(ios.Out, output); err != nil {
+			return err
+		}
+	} else {
+		renderText(ios.Out, output.Checks)
+	}
+	if !output.OK {
+		return cmdutil.NewExitError(1, "")
+	}
+	return nil
+}
+
+// checkConfig loads every configuration layer and validates the known settings
+func checkConfig(f *cmdutil.Factory) check {
+	c := check{Name: "config"}
+	cfg, err := f.Config()
+	if err != nil {
+		c.Status, c.Detail = statusFail, err.Error()
+		c.Fix = "Fix the YAML syntax in the file named above, or move it aside"
+		return c
+	}
+
+	var invalid []string
+	for _, key := range cfg.Keys() {
+		if _, known := config.LookupOption(key); !known {
+			continue // flag defaults, hosts and aliases are free-form
+		}
+		if err := config.Validate(key, cfg.Get(key)); err != nil {
+			invalid = append(invalid, err.Error())
+		}
+	}
+	if len(invalid) > 0 {
+		c.Status, c.Detail = statusFail, strings.Join(invalid, "; ")
+		c.Fix = "Correct the values with 'bbc config set <key> <value>' (see 'bbc config list')"
+		return c
+	}
+
+	if _, err := f.Host(); err != nil {
+		c.Status, c.Detail = statusFail, err.Error()
+		c.Fix = "Add the host under hosts in " + config.DefaultPath() + " with its api_url, or unset --host/BB_HOST"
+		return c
+	}
+	c.Status, c.Detail = statusOK, config.DefaultPath()
+	return c
+}
+
+// checkNetwork reports whether the API answers at all; any HTTP response,
+// even 401, proves it is reachable
+func checkNetwork(ctx context.Context, api string) check {
+	c := check{Name: "network"}
+	ctx, cancel := context.WithTimeout(ctx, networkTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, api, nil)
+	if err != nil {
+		c.Status, c.Detail = statusFail, err.Error()
+		c.Fix = "Check the api_url of the host in " + config.DefaultPath()
+		return c
+	}
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.Status, c.Detail = statusFail, err.Error()
+		c.Fix = "Check your connection, DNS and proxy settings (HTTPS_PROXY); the API must be reachable at " + api
+		return c
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	c.Status = statusOK
+	c.Detail = fmt.Sprintf("%s answered HTTP %d in %s", hostOf(api), resp.StatusCode, time.Since(start).Round(time.Millisecond))
+	return c
+}
+
+// checkKeyring opens the secret store the way credential loading does
+func checkKeyring(f *cmdutil.Factory) check {
+	c := check{Name: "keyring"}
+	if cmdutil.EnvCredentialsSet() {
+		c.Status, c.Detail = statusSkip, "credentials come from BB_WORKSPACE, BB_USERNAME and BB_TOKEN"
+		return c
+	}
+
+	backends := secret.Backends(f.SecretStoreOptions()...)
+	if _, err := f.GetSecretStore(); err != nil {
+		c.Status, c.Detail = statusFail, err.Error()
+		switch {
+		case errors.Is(err, secret.ErrKeyringTimeout):
+			c.Fix = "Unlock your keyring, raise BB_KEYRING_TIMEOUT, or set BB_ALLOW_INSECURE_STORE=1 to use the encrypted file store"
+		case secret.Headless():
+			c.Fix = "This session looks headless (SSH or no display), so desktop keyrings are skipped: set BB_ALLOW_INSECURE_STORE=1, or export BB_WORKSPACE, BB_USERNAME and BB_TOKEN"
+		default:
+			c.Fix = "Install or unlock a keyring (Secret Service, KWallet, pass), or set BB_ALLOW_INSECURE_STORE=1"
+		}
+		return c
+	}
+	c.Status = statusOK
+	c.Detail = "backends tried: " + strings.Join(backends, ", ")
+	if secret.Headless() {
+		c.Detail += " (headless session: desktop keyrings skipped)"
+	}
+	return c
+}
+
+// checkCredentials verifies the stored credentials against the API and
+// returns that check and the scopes check, which needs its response
+func checkCredentials(ctx context.Context, f *cmdutil.Factory, host config.Host, hostErr error) (check, check) {
+	creds := check{Name: "credentials"}
+	scopes := check{Name: "scopes", Status: statusSkip, Detail: "credentials not verified"}
+	if hostErr != nil {
+		creds.Status, creds.Detail = statusSkip, "no usable host"
+		return creds, scopes
+	}
+
+	stored, err := f.GetCredentials()
+	if err != nil {
+		creds.Status, creds.Detail = statusFail, err.Error()
+		creds.Fix = "Run 'bbc auth' to log in"
+		return creds, scopes
+	}
+	client, err := bbcloud.New(bbcloud.Options{
+		BaseURL:   host.APIURL,
+		Workspace: stored.Workspace,
+		Username:  stored.Username,
+		Token:     stored.Token,
+		AuthType:  host.Auth,
+	})
+	if err != nil {
+		creds.Status, creds.Detail = statusFail, err.Error()
+		creds.Fix = "Run 'bbc auth' to store complete credentials"
+		return creds, scopes
+	}
+	user, granted, err := client.CurrentUserWithScopes(ctx)
+	if err != nil {
+		creds.Status, creds.Detail = statusFail, err.Error()
+		creds.Fix = "The token may be revoked or expired: create a new one and run 'bbc auth'"
+		return creds, scopes
+	}
+	creds.Status = statusOK
+	creds.Detail = fmt.Sprintf("authenticated as %s", user.GetName())
+	if stored.Workspace != "" {
+		creds.Detail += " in workspace " + stored.Workspace
+	}
+
+	return creds, checkScopes(granted)
+}
+
+// checkScopes compares the granted scopes with bbcloud.RequiredScopes
+func checkScopes(granted []string) check {
+	c := check{Name: "scopes"}
+	if len(granted) == 0 {
+		c.Status, c.Detail = statusSkip, "the API did not report the token's scopes"
+		return c
+	}
+	missing := bbcloud.MissingScopes(granted)
+	if len(missing) == 0 {
+		c.Status, c.Detail = statusOK, "all required scopes granted"
+		return c
+	}
+	c.Status, c.Detail = statusFail, "missing "+strings.Join(missing, ", ")
+	c.Fix = "Create a token with these scopes and run 'bbc auth' again"
+	return c
+}
+
+// checkGitRemote looks for a remote of the current repository on the host
+func checkGitRemote(ctx context.Context, client *git.Client, host config.Host, hostErr error) check {
+	c := check{Name: "git"}
+	if hostErr != nil {
+		c.Status, c.Detail = statusSkip, "no usable host"
+		return c
+	}
+	if client == nil {
+		client = git.New("")
+	}
+	remotes, err := client.Remotes(ctx)
+	switch {
+	case errors.Is(err, git.ErrNotRepository):
+		c.Status, c.Detail = statusSkip, "not in a git repository"
+		return c
+	case err != nil:
+		c.Status, c.Detail = statusWarn, err.Error()
+		c.Fix = "Install git and make sure it is on PATH"
+		return c
+	}
+
+	for _, r := range remotes {
+		if ws, repo, ok := parseRemote(r.URL, host.Name); ok {
+			c.Status, c.Detail = statusOK, fmt.Sprintf("%s is %s/%s", r.Name, ws, repo)
+			return c
+		}
+	}
+	c.Status = statusWarn
+	c.Detail = fmt.Sprintf("no remote points at %s; pass --repo or set default_repo", host.Name)
+	c.Fix = fmt.Sprintf("git remote add origin https://%s/<workspace>/<repo>.git", host.Name)
+	return c
+}
+
+// parseRemote returns the workspace and repository of a remote URL on host,
+// in HTTPS (https://[user@]host/ws/repo.git), SSH (ssh://git@host/ws/repo.git)
+// or scp-like (git@host:ws/repo.git) form
+func parseRemote(remote, host string) (workspace, repo string, ok bool) {
+	var path string
+	if u, err := url.Parse(remote); err == nil && u.Scheme != "" && u.Host != "" {
+		if u.Hostname() != host {
+			return "", "", false
+		}
+		path = u.Path
+	} else {
+		addr, p, found := strings.Cut(remote, ":")
+		if !found {
+			return "", "", false
+		}
+		if _, h, hasUser := strings.Cut(addr, "@"); hasUser {
+			addr = h
+		}
+		if addr != host {
+			return "", "", false
+		}
+		path = p
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	workspace, repo, found := strings.Cut(path, "/")
+	if !found || workspace == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", "", false
+	}
+	return workspace, repo, true
+}
+
+// apiURL returns the API base URL of host
+func apiURL(host config.Host) string {
+	if host.APIURL != "" {
+		return host.APIURL
+	}
+	return bbcloud.DefaultBaseURL
+}
+
+func hostOf(api string) string {
+	if u, err := url.Parse(api); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return api
+}
+
+func renderText(w io.Writer, checks []check) {
+	for _, c := range checks {
+		_, _ = fmt.Fprintf(w, "%-6s %-12s %s\n", "["+c.Status+"]", c.Name, c.Detail)
+		if c.Fix != "" {
+			_, _ = fmt.Fprintf(w, "%-19s fix: %s\n", "", c.Fix)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package doctor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+)
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		remote, host string
+		ws, repo     string
+		ok           bool
+	}{
+		{"https://bitbucket.org/acme/api.git", "bitbucket.org", "acme", "api", true},
+		{"https://alice@bitbucket.org/acme/api", "bitbucket.org", "acme", "api", true},
+		{"git@bitbucket.org:acme/api.git", "bitbucket.org", "acme", "api", true},
+		{"ssh://git@bitbucket.org/acme/api.git", "bitbucket.org", "acme", "api", true},
+		{"git@bitbucket.example.com:team/web.git", "bitbucket.example.com", "team", "web", true},
+		{"git@github.com:acme/api.git", "bitbucket.org", "", "", false},
+		{"https://bitbucket.org/acme", "bitbucket.org", "", "", false},
+		{"/srv/git/api.git", "bitbucket.org", "", "", false},
+	}
+	for _, tt := range tests {
+		ws, repo, ok := parseRemote(tt.remote, tt.host)
+		if ws != tt.ws || repo != tt.repo || ok != tt.ok {
+			t.Errorf("parseRemote(%q, %q) = %q, %q, %v; want %q, %q, %v", tt.remote, tt.host, ws, repo, ok, tt.ws, tt.repo, tt.ok)
+		}
+	}
+}
+
+func TestCheckScopes(t *testing.T) {
+	if c := checkScopes(nil); c.Status != statusSkip {
+		t.Errorf("no scopes reported: %+v", c)
+	}
+	if c := checkScopes(bbcloud.RequiredScopes); c.Status != statusOK {
+		t.Errorf("all scopes: %+v", c)
+	}
+	c := checkScopes([]string{"read:user:bitbucket"})
+	if c.Status != statusFail || !strings.Contains(c.Detail, "write:pullrequest:bitbucket") || c.Fix == "" {
+		t.Errorf("missing scopes: %+v", c)
+	}
+}
+
+func TestCheckNetwork(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	if c := checkNetwork(context.Background(), server.URL+"/2.0"); c.Status != statusOK || !strings.Contains(c.Detail, "HTTP 401") {
+		t.Errorf("reachable API: %+v", c)
+	}
+	server.Close()
+	if c := checkNetwork(context.Background(), server.URL+"/2.0"); c.Status != statusFail || c.Fix == "" {
+		t.Errorf("unreachable API: %+v", c)
+	}
+}
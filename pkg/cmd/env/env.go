@@ -0,0 +1,230 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/internal/config"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+type envOptions struct {
+	json bool
+
+	factory *cmdutil.Factory
+}
+
+// knownVars lists the environment variables bb reads, in display order
+var knownVars = []struct {
+	name        string
+	description string
+	secret      bool
+}{
+	{"BB_WORKSPACE", "Workspace (credentials when set with BB_USERNAME and BB_TOKEN)", false},
+	{"BB_USERNAME", "Username for environment credentials", false},
+	{"BB_TOKEN", "App Password or access token for environment credentials", true},
+	{"BB_HOST", "Host from the hosts config", false},
+	{"BB_PROFILE", "Auth profile", false},
+	{"BB_CONFIG_DIR", "Configuration directory", false},
+	{"BB_ALLOW_INSECURE_STORE", "Allow the encrypted file keyring fallback", false},
+	{"BB_KEYRING_PASSPHRASE", "Passphrase for the file keyring", true},
+	{"BB_EXPORT_PASSPHRASE", "Passphrase for auth export and import", true},
+	{"KEYRING_BACKEND", "Keyring backends to try (--keyring-backend)", false},
+	{"BB_AGENT_SOCKET", "Socket of the session agent", false},
+	{"BB_KEYRING_TIMEOUT", "Keyring operation timeout", false},
+	{"BB_NO_INPUT", "Fail instead of prompting (--no-input)", false},
+	{"BB_HTTP_DEBUG", "Log HTTP requests to stderr", false},
+	{"XDG_CONFIG_HOME", "Base configuration directory", false},
+	{"XDG_CACHE_HOME", "Base cache directory", false},
+	{"XDG_DATA_HOME", "Base data directory (audit log)", false},
+	{"VISUAL", "Editor (after the editor setting)", false},
+	{"EDITOR", "Editor (after the editor setting and VISUAL)", false},
+}
+
+type envVar struct {
+	Name        string `json:"name"`
+	Value       string `json:"value,omitempty"`
+	Set         bool   `json:"set"`
+	Description string `json:"description"`
+}
+
+type configFile struct {
+	Layer  string `json:"layer"` // project, user, local
+	Path   string `json:"path"`
+	Exists bool   `json:"exists"`
+}
+
+type envOutput struct {
+	Env             []envVar          `json:"env"`
+	Credentials     string            `json:"credentials"`
+	CredentialsNote string            `json:"credentials_note,omitempty"`
+	Host            string            `json:"host"`
+	Profile         string            `json:"profile"`
+	Workspace       string            `json:"workspace,omitempty"`
+	WorkspaceSource string            `json:"workspace_source"`
+	ConfigFiles     []configFile      `json:"config_files"`
+	Config          map[string]string `json:"config"`
+	configKeys      []string
+}
+
+// NewCmdEnv creates the env command
+func NewCmdEnv(f *cmdutil.Factory) *cobra.Command {
+	opts := &envOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "env",
+		Short: "Show environment, credential source, and effective config",
+		Long: `Show the environment variables bbc recognizes, which credential source
+and workspace will be used, and the effective configuration.
+
+Secrets are masked. Nothing is read from the keyring, so this never prompts.
+
+Examples:
+  bbc env
+  bbc env --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runEnv(cmd.Context(), cmd, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of markdown")
+
+	return cmd
+}
+
+func runEnv(ctx context.Context, cmd *cobra.Command, opts *envOptions) error {
+	ios, _ := opts.factory.Streams()
+
+	cfg, err := opts.factory.Config()
+	if err != nil {
+		return err
+	}
+
+	var output envOutput
+
+	for _, v := range knownVars {
+		value, set := os.LookupEnv(v.name)
+		if v.secret && value != "" {
+			value = cmdutil.MaskSecret(value)
+		}
+		output.Env = append(output.Env, envVar{Name: v.name, Value: value, Set: set, Description: v.description})
+	}
+
+	host, err := opts.factory.Host()
+	if err != nil {
+		output.Host = fmt.Sprintf("error: %v", err)
+	} else {
+		output.Host = host.Name
+	}
+	profile, err := opts.factory.Profile()
+	if err != nil {
+		return err
+	}
+	output.Profile = profile
+	if output.Profile == "" {
+		output.Profile = "default"
+	}
+	if keys, err := opts.factory.CredentialKeys(); err != nil {
+		output.Credentials = fmt.Sprintf("error: %v", err)
+	} else {
+		output.Credentials = "keyring (" + strings.Join(keys, ", else ") + ")"
+		if opts.factory.UseAgent() {
+			output.Credentials = "session agent, else " + output.Credentials
+		}
+	}
+	if cmdutil.EnvCredentialsSet() {
+		output.Credentials = "environment (BB_WORKSPACE, BB_USERNAME, BB_TOKEN)"
+	} else if os.Getenv("BB_USERNAME") != "" || os.Getenv("BB_TOKEN") != "" {
+		output.CredentialsNote = "BB_USERNAME/BB_TOKEN are ignored unless BB_WORKSPACE, BB_USERNAME, and BB_TOKEN are all set"
+	}
+
+	override, _ := cmd.Flags().GetString("workspace")
+	output.Workspace, output.WorkspaceSource, err = opts.factory.ResolveWorkspace(override, false)
+	if err != nil {
+		return err
+	}
+
+	output.ConfigFiles = configFiles(ctx, opts.factory)
+
+	output.Config = make(map[string]string)
+	for _, opt := range config.Options {
+		if opt.IsPattern() {
+			continue
+		}
+		output.Config[opt.Key] = cfg.GetOrDefault(opt.Key)
+		output.configKeys = append(output.configKeys, opt.Key)
+	}
+
+	if opts.json {
+		return cmdutil.WriteJSON(ios.Out, output)
+	}
+	return renderMarkdownEnv(ios.Out, output)
+}
+
+// configFiles lists the config layers that apply here, lowest precedence first
+func configFiles(ctx context.Context, f *cmdutil.Factory) []configFile {
+	var files []configFile
+	add := func(layer, path string) {
+		_, err := os.Stat(path)
+		files = append(files, configFile{Layer: layer, Path: path, Exists: err == nil})
+	}
+
+	if root, err := f.GitClient.TopLevel(ctx); err == nil {
+		add("project", config.ProjectPath(root))
+	}
+	add("user", config.DefaultPath())
+	if gitDir, err := f.GitClient.CommonDir(ctx); err == nil {
+		add("local", config.LocalPath(gitDir))
+	}
+	return files
+}
+
+func renderMarkdownEnv(w io.Writer, output envOutput) error {
+	_, _ = fmt.Fprintln(w, "# Environment")
+	_, _ = fmt.Fprintln(w)
+	for _, v := range output.Env {
+		value := "(unset)"
+		if v.Set {
+			value = v.Value
+		}
+		_, _ = fmt.Fprintf(w, "- %s=%s — %s\n", v.Name, value, v.Description)
+	}
+
+	_, _ = fmt.Fprintln(w, "\n# Resolution")
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintf(w, "- Host: %s\n", output.Host)
+	_, _ = fmt.Fprintf(w, "- Profile: %s\n", output.Profile)
+	_, _ = fmt.Fprintf(w, "- Credentials: %s\n", output.Credentials)
+	if output.CredentialsNote != "" {
+		_, _ = fmt.Fprintf(w, "  - Note: %s\n", output.CredentialsNote)
+	}
+	workspace := output.Workspace
+	if workspace == "" {
+		workspace = "(from stored credentials)"
+	}
+	_, _ = fmt.Fprintf(w, "- Workspace: %s [%s]\n", workspace, output.WorkspaceSource)
+
+	_, _ = fmt.Fprintln(w, "\n# Config files (lowest precedence first)")
+	_, _ = fmt.Fprintln(w)
+	for _, f := range output.ConfigFiles {
+		state := "missing"
+		if f.Exists {
+			state = "found"
+		}
+		_, _ = fmt.Fprintf(w, "- %s: %s (%s)\n", f.Layer, f.Path, state)
+	}
+
+	_, _ = fmt.Fprintln(w, "\n# Effective config")
+	_, _ = fmt.Fprintln(w)
+	for _, k := range output.configKeys {
+		_, _ = fmt.Fprintf(w, "- %s=%s\n", k, output.Config[k])
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package env
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/iostreams"
+)
+
+func TestEnvCredentials(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv("BB_AGENT_SOCKET", "")
+	t.Setenv("BB_HOST", "")
+	t.Setenv("BB_PROFILE", "")
+	t.Setenv("BB_WORKSPACE", "acme")
+	t.Setenv("BB_USERNAME", "user")
+	t.Setenv("BB_TOKEN", "")
+
+	run := func(profile string) envOutput {
+		t.Helper()
+		out := &bytes.Buffer{}
+		f := cmdutil.NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: out, ErrOut: &bytes.Buffer{}})
+		f.ProfileOverride = profile
+		cmd := &cobra.Command{}
+		cmd.Flags().String("workspace", "", "")
+		if err := runEnv(context.Background(), cmd, &envOptions{json: true, factory: f}); err != nil {
+			t.Fatal(err)
+		}
+		var got envOutput
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	// Incomplete environment credentials are ignored; the workspace entry
+	// comes before the default profile's own
+	got := run("")
+	if want := "keyring (bb/workspaces/bitbucket.org/acme, else bb/credentials)"; got.Credentials != want {
+		t.Errorf("credentials = %q, want %q", got.Credentials, want)
+	}
+	if got.CredentialsNote == "" {
+		t.Error("expected a note about the ignored environment credentials")
+	}
+	if got := run("work"); got.Credentials != "keyring (bb/credentials/work)" {
+		t.Errorf("named profile credentials = %q", got.Credentials)
+	}
+
+	t.Setenv("BB_AGENT_SOCKET", "/tmp/bb.sock")
+	if got := run("work"); got.Credentials != "session agent, else keyring (bb/credentials/work)" {
+		t.Errorf("agent credentials = %q", got.Credentials)
+	}
+
+	t.Setenv("BB_TOKEN", "token")
+	if got := run(""); got.Credentials != "environment (BB_WORKSPACE, BB_USERNAME, BB_TOKEN)" {
+		t.Errorf("complete environment credentials = %q", got.Credentials)
+	}
+}
@@ -0,0 +1,305 @@
+package exec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/httpx"
+)
+
+type execOptions struct {
+	file            string
+	continueOnError bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdExec creates the exec command
+func NewCmdExec(f *cmdutil.Factory) *cobra.Command {
+	opts := &execOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "exec -f <plan>",
+		Short: "Run a plan of review operations",
+		Long: `Run the steps of a YAML or JSON plan in order and report the result of each.
+
+The whole plan is validated before the first step runs. By default the first
+failing step stops the plan and the remaining steps are reported as skipped;
+--continue-on-error runs them anyway. The exit status is 1 if any step failed.
+
+Actions and their fields:
+  create_pr        source, title, target, description, draft, close_source, reviewers
+  add_reviewers    reviewers (UUIDs or account IDs, added to the current ones)
+  comment          body; file and line (and line_end) for an inline comment
+  add_tasks        tasks
+  approve
+  request_changes
+
+Every step may set repo (default: the plan's repo, then default_repo).
+Steps other than create_pr act on pr: a PR number, the id of an earlier
+create_pr step, or by default the PR the latest create_pr step opened.
+
+Example plan:
+  repo: api
+  steps:
+    - action: create_pr
+      id: auth
+      source: feat/auth
+      title: Add JWT authentication
+    - action: add_reviewers
+      reviewers: ["{5f1c...}"]
+    - action: add_tasks
+      tasks: [Update the API docs, Rotate the signing key]
+    - action: comment
+      pr: "41"
+      body: Superseded by the JWT work
+
+Examples:
+  bbc exec -f plan.yaml
+  bbc exec -f plan.json --continue-on-error
+  bbc --dry-run exec -f plan.yaml   # Print the requests without sending them`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runExec(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.file, "file", "f", "", "Plan file, YAML or JSON (- for stdin)")
+	cmd.Flags().BoolVar(&opts.continueOnError, "continue-on-error", false, "Run the remaining steps after a step fails")
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// Step statuses
+const (
+	statusOK      = "ok"
+	statusFailed  = "failed"
+	statusSkipped = "skipped"
+	statusDryRun  = "dry_run" // printed, not sent (--dry-run)
+)
+
+type stepResult struct {
+	Step      int    `json:"step"` // position in the plan, from 1
+	Action    string `json:"action"`
+	ID        string `json:"id,omitempty"`
+	Repo      string `json:"repo"`
+	PR        int    `json:"pr,omitempty"`
+	Status    string `json:"status"`
+	URL       string `json:"url,omitempty"`        // create_pr
+	CommentID int    `json:"comment_id,omitempty"` // comment
+	TaskIDs   []int  `json:"task_ids,omitempty"`   // add_tasks
+	Error     string `json:"error,omitempty"`
+}
+
+type execOutput struct {
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Skipped   int          `json:"skipped"`
+	Steps     []stepResult `json:"steps"`
+}
+
+func runExec(ctx context.Context, opts *execOptions) error {
+	ios := opts.factory.IOStreams
+	var (
+		data []byte
+		err  error
+	)
+	if opts.file == "-" {
+		data, err = io.ReadAll(ios.In)
+	} else {
+		data, err = os.ReadFile(opts.file)
+	}
+	if err != nil {
+		return fmt.Errorf("read plan: %w", err)
+	}
+	cfg, err := opts.factory.Config()
+	if err != nil {
+		return err
+	}
+	p, err := parsePlan(data, cfg.Repo())
+	if err != nil {
+		return err
+	}
+
+	client, err := opts.factory.NewBBCloudClient("")
+	if err != nil {
+		return err
+	}
+
+	output := runPlan(ctx, client, p, opts.continueOnError)
+	if err := cmdutil.WriteJSON(ios.Out, output); err != nil {
+		return err
+	}
+	if output.Failed > 0 {
+		return cmdutil.NewExitError(1, "")
+	}
+	return nil
+}
+
+// runPlan runs the steps of p in order
+func runPlan(ctx context.Context, client *bbcloud.Client, p *plan, continueOnError bool) execOutput {
+	output := execOutput{Steps: make([]stepResult, len(p.Steps))}
+
+	// PRs opened by create_pr steps, by id; 0 when not opened (dry run)
+	created := make(map[string]int)
+	lastCreated := -1
+	stopped := false
+
+	for i := range p.Steps {
+		s := &p.Steps[i]
+		r := stepResult{Step: i + 1, Action: s.Action, ID: s.ID, Repo: s.Repo}
+		if r.Repo == "" {
+			r.Repo = p.Repo
+		}
+
+		if stopped || ctx.Err() != nil {
+			r.Status = statusSkipped
+			output.Skipped++
+			output.Steps[i] = r
+			continue
+		}
+
+		err := runStep(ctx, client, s, &r, created, lastCreated)
+		switch {
+		case errors.Is(err, httpx.ErrDryRun):
+			r.Status = statusDryRun
+		case err != nil:
+			r.Status, r.Error = statusFailed, err.Error()
+			output.Failed++
+			stopped = !continueOnError
+		default:
+			r.Status = statusOK
+			output.Succeeded++
+		}
+		if s.Action == actionCreatePR {
+			lastCreated = r.PR
+			if s.ID != "" {
+				created[s.ID] = r.PR
+			}
+		}
+		output.Steps[i] = r
+	}
+	return output
+}
+
+// runStep runs one step, filling in r
+func runStep(ctx context.Context, client *bbcloud.Client, s *step, r *stepResult, created map[string]int, lastCreated int) error {
+	if s.Action == actionCreatePR {
+		pr, err := client.CreatePR(ctx, r.Repo, bbcloud.CreatePROptions{
+			Title:             s.Title,
+			Description:       s.Description,
+			SourceBranch:      s.Source,
+			DestinationBranch: s.Target,
+			CloseSourceBranch: s.CloseSource,
+			Draft:             s.Draft,
+			Reviewers:         s.Reviewers,
+		})
+		if err != nil {
+			return err
+		}
+		r.PR = pr.ID
+		if pr.Links.HTML != nil {
+			r.URL = pr.Links.HTML.Href
+		}
+		return nil
+	}
+
+	pr, err := stepPR(s, created, lastCreated)
+	if err != nil {
+		return err
+	}
+	r.PR = pr
+
+	switch s.Action {
+	case actionAddReviewers:
+		current, err := client.GetPullRequest(ctx, r.Repo, pr)
+		if err != nil {
+			return err
+		}
+		reviewers := make([]string, 0, len(current.Reviewers)+len(s.Reviewers))
+		for _, u := range current.Reviewers {
+			reviewers = append(reviewers, u.UUID)
+		}
+		for _, id := range s.Reviewers {
+			if !containsReviewer(current.Reviewers, id) {
+				reviewers = append(reviewers, id)
+			}
+		}
+		_, err = client.UpdatePR(ctx, r.Repo, pr, bbcloud.UpdatePROptions{Reviewers: reviewers})
+		return err
+
+	case actionComment:
+		var comment *bbcloud.Comment
+		if s.File == "" {
+			comment, err = client.CreateComment(ctx, r.Repo, pr, s.Body)
+		} else if s.LineEnd == 0 {
+			comment, err = client.CreateInlineComment(ctx, r.Repo, pr, s.Body, s.File, 0, s.Line)
+		} else {
+			comment, err = client.CreateInlineComment(ctx, r.Repo, pr, s.Body, s.File, s.Line, s.LineEnd)
+		}
+		if err != nil {
+			return err
+		}
+		r.CommentID = comment.ID
+		return nil
+
+	case actionAddTasks:
+		var dryRun error
+		for _, content := range s.Tasks {
+			task, err := client.CreatePRTask(ctx, r.Repo, pr, content)
+			if errors.Is(err, httpx.ErrDryRun) {
+				// Print every task, not just the first
+				dryRun = err
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			r.TaskIDs = append(r.TaskIDs, task.ID)
+		}
+		return dryRun
+
+	case actionApprove:
+		_, err := client.ApprovePR(ctx, r.Repo, pr)
+		return err
+
+	case actionRequestChanges:
+		_, err := client.RequestChangesPR(ctx, r.Repo, pr)
+		return err
+	}
+	return fmt.Errorf("unknown action %q", s.Action)
+}
+
+// stepPR resolves the PR a step acts on
+func stepPR(s *step, created map[string]int, lastCreated int) (int, error) {
+	pr := lastCreated
+	if s.PR != "" {
+		if n, err := strconv.Atoi(s.PR); err == nil {
+			return n, nil
+		}
+		pr = created[s.PR]
+	}
+	if pr <= 0 {
+		return 0, fmt.Errorf("the PR of an earlier create_pr step was not opened")
+	}
+	return pr, nil
+}
+
+// containsReviewer reports whether id (UUID or account ID) is among users
+func containsReviewer(users []bbcloud.User, id string) bool {
+	for _, u := range users {
+		if u.UUID == id || u.AccountID == id {
+			return true
+		}
+	}
+	return false
+}
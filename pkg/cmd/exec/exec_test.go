@@ -0,0 +1,129 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+)
+
+func TestParsePlan(t *testing.T) {
+	p, err := parsePlan([]byte(`
+steps:
+  - action: create_pr
+    id: auth
+    source: feat/auth
+    title: Add JWT auth
+  - action: comment
+    pr: auth
+    body: Ready for review
+  - action: approve
+    repo: web
+    pr: 41
+`), "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Repo != "api" || len(p.Steps) != 3 || p.Steps[2].PR != "41" {
+		t.Errorf("plan = %+v", p)
+	}
+
+	// JSON plans are YAML too
+	if _, err := parsePlan([]byte(`{"repo":"api","steps":[{"action":"approve","pr":7}]}`), ""); err != nil {
+		t.Errorf("JSON plan: %v", err)
+	}
+
+	bad := []struct{ plan, want string }{
+		{`steps: []`, "no steps"},
+		{`steps: [{action: merge, pr: 1}]`, "unknown action"},
+		{`steps: [{action: approve}]`, "no earlier create_pr"},
+		{`steps: [{action: approve, pr: other}]`, "neither a number nor the id"},
+		{`steps: [{action: comment, pr: 1, body: hi, file: a.go}]`, "both file and line"},
+		{`steps: [{action: create_pr, title: x}]`, "source and title"},
+		{`steps: [{action: approve, pr: 1, bogus: true}]`, "field bogus not found"},
+	}
+	for _, tt := range bad {
+		if _, err := parsePlan([]byte(tt.plan), "api"); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("parsePlan(%s) = %v, want error containing %q", tt.plan, err, tt.want)
+		}
+	}
+	if _, err := parsePlan([]byte(`steps: [{action: approve, pr: 1}]`), ""); err == nil || !strings.Contains(err.Error(), "repo is required") {
+		t.Errorf("plan without repo: %v", err)
+	}
+}
+
+func TestRunPlan(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/pullrequests"):
+			_, _ = w.Write([]byte(`{"id":12,"links":{"html":{"href":"https://bitbucket.org/acme/api/pull-requests/12"}}}`))
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"id":12,"reviewers":[{"uuid":"{a}"}]}`))
+		case r.Method == http.MethodPut:
+			var body struct {
+				Reviewers []map[string]string `json:"reviewers"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if len(body.Reviewers) != 2 || body.Reviewers[0]["uuid"] != "{a}" || body.Reviewers[1]["uuid"] != "{b}" {
+				t.Errorf("reviewers = %v, want {a} kept and {b} added", body.Reviewers)
+			}
+			_, _ = w.Write([]byte(`{"id":12}`))
+		case strings.HasSuffix(r.URL.Path, "/tasks"):
+			_, _ = w.Write([]byte(`{"id":3}`))
+		case strings.HasSuffix(r.URL.Path, "/approve"):
+			http.Error(w, `{"error":{"message":"You can't approve your own pull request"}}`, http.StatusBadRequest)
+		default:
+			_, _ = w.Write([]byte(`{"id":99}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := bbcloud.New(bbcloud.Options{BaseURL: server.URL, Workspace: "acme", Username: "alice", Token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := parsePlan([]byte(`
+repo: api
+steps:
+  - {action: create_pr, source: feat/auth, title: Add JWT auth}
+  - {action: add_reviewers, reviewers: ["{a}", "{b}"]}
+  - {action: add_tasks, tasks: [Update docs]}
+  - {action: approve}
+  - {action: comment, body: Done}
+`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := runPlan(context.Background(), client, p, false)
+	if output.Succeeded != 3 || output.Failed != 1 || output.Skipped != 1 {
+		t.Errorf("succeeded/failed/skipped = %d/%d/%d, want 3/1/1", output.Succeeded, output.Failed, output.Skipped)
+	}
+	if r := output.Steps[0]; r.PR != 12 || r.URL == "" {
+		t.Errorf("create_pr result = %+v", r)
+	}
+	if r := output.Steps[2]; len(r.TaskIDs) != 1 || r.TaskIDs[0] != 3 || r.PR != 12 {
+		t.Errorf("add_tasks result = %+v", r)
+	}
+	if r := output.Steps[3]; r.Status != statusFailed || r.Error == "" {
+		t.Errorf("approve result = %+v, want failed", r)
+	}
+	if r := output.Steps[4]; r.Status != statusSkipped {
+		t.Errorf("comment result = %+v, want skipped", r)
+	}
+
+	requests = nil
+	output = runPlan(context.Background(), client, p, true)
+	if output.Succeeded != 4 || output.Failed != 1 || output.Skipped != 0 {
+		t.Errorf("with --continue-on-error: succeeded/failed/skipped = %d/%d/%d, want 4/1/0", output.Succeeded, output.Failed, output.Skipped)
+	}
+	if last := requests[len(requests)-1]; last != "POST /repositories/acme/api/pullrequests/12/comments" {
+		t.Errorf("last request = %q, want the comment on PR 12", last)
+	}
+}
@@ -0,0 +1,154 @@
+package exec
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Step actions
+const (
+	actionCreatePR       = "create_pr"
+	actionAddReviewers   = "add_reviewers"
+	actionComment        = "comment"
+	actionAddTasks       = "add_tasks"
+	actionApprove        = "approve"
+	actionRequestChanges = "request_changes"
+)
+
+var actions = []string{actionCreatePR, actionAddReviewers, actionComment, actionAddTasks, actionApprove, actionRequestChanges}
+
+// plan is a sequence of operations read from YAML or JSON
+type plan struct {
+	// Repo is the repository of steps that do not name one
+	Repo  string `yaml:"repo"`
+	Steps []step `yaml:"steps"`
+}
+
+// step is one operation. Which fields apply depends on the action.
+type step struct {
+	Action string `yaml:"action"`
+	// ID names a create_pr step so later steps can use its PR as "pr"
+	ID   string `yaml:"id"`
+	Repo string `yaml:"repo"`
+	// PR is a PR number or the id of an earlier create_pr step; empty means
+	// the PR of the latest create_pr step
+	PR string `yaml:"pr"`
+
+	// create_pr
+	Source      string `yaml:"source"`
+	Target      string `yaml:"target"`
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	Draft       bool   `yaml:"draft"`
+	CloseSource bool   `yaml:"close_source"`
+
+	// create_pr and add_reviewers: user UUIDs or account IDs
+	Reviewers []string `yaml:"reviewers"`
+
+	// comment; File and Line make it inline, LineEnd a range
+	Body    string `yaml:"body"`
+	File    string `yaml:"file"`
+	Line    int    `yaml:"line"`
+	LineEnd int    `yaml:"line_end"`
+
+	// add_tasks
+	Tasks []string `yaml:"tasks"`
+}
+
+// parsePlan decodes a plan (JSON is valid YAML) and validates every step,
+// so a plan with mistakes fails before anything runs. defaultRepo applies
+// when the plan names no repo.
+func parsePlan(data []byte, defaultRepo string) (*plan, error) {
+	var p plan
+	dec := yaml.NewDecoder(strings.NewReader(string(data)))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("parse plan: %w", err)
+	}
+	if len(p.Steps) == 0 {
+		return nil, fmt.Errorf("plan has no steps")
+	}
+	if p.Repo == "" {
+		p.Repo = defaultRepo
+	}
+
+	ids := make(map[string]bool)
+	created := false
+	for i := range p.Steps {
+		s := &p.Steps[i]
+		if err := validateStep(s, p.Repo, ids, created); err != nil {
+			return nil, fmt.Errorf("step %d (%s): %w", i+1, s.Action, err)
+		}
+		if s.Action == actionCreatePR {
+			created = true
+			if s.ID != "" {
+				ids[s.ID] = true
+			}
+		}
+	}
+	return &p, nil
+}
+
+func validateStep(s *step, defaultRepo string, ids map[string]bool, created bool) error {
+	if !slices.Contains(actions, s.Action) {
+		return fmt.Errorf("unknown action %q (allowed: %s)", s.Action, strings.Join(actions, ", "))
+	}
+	if s.Repo == "" && defaultRepo == "" {
+		return fmt.Errorf("repo is required (set it on the step or the plan, or configure default_repo)")
+	}
+	if s.ID != "" && s.Action != actionCreatePR {
+		return fmt.Errorf("id is only used by create_pr steps")
+	}
+	if s.ID != "" && ids[s.ID] {
+		return fmt.Errorf("duplicate id %q", s.ID)
+	}
+
+	if s.Action == actionCreatePR {
+		if s.PR != "" {
+			return fmt.Errorf("pr is not used by create_pr")
+		}
+		if s.Source == "" || s.Title == "" {
+			return fmt.Errorf("source and title are required")
+		}
+		return nil
+	}
+
+	switch {
+	case s.PR == "" && !created:
+		return fmt.Errorf("pr is required (no earlier create_pr step)")
+	case s.PR != "":
+		if n, err := strconv.Atoi(s.PR); err == nil {
+			if n <= 0 {
+				return fmt.Errorf("invalid pr %d", n)
+			}
+		} else if !ids[s.PR] {
+			return fmt.Errorf("pr %q is neither a number nor the id of an earlier create_pr step", s.PR)
+		}
+	}
+
+	switch s.Action {
+	case actionAddReviewers:
+		if len(s.Reviewers) == 0 {
+			return fmt.Errorf("reviewers are required")
+		}
+	case actionComment:
+		if strings.TrimSpace(s.Body) == "" {
+			return fmt.Errorf("body is required")
+		}
+		if (s.File == "") != (s.Line == 0) {
+			return fmt.Errorf("inline comments need both file and line")
+		}
+		if s.LineEnd != 0 && s.LineEnd < s.Line {
+			return fmt.Errorf("line_end is before line")
+		}
+	case actionAddTasks:
+		if len(s.Tasks) == 0 {
+			return fmt.Errorf("tasks are required")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package extension
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/internal/config"
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// prefix starts the repository and executable name of every extension
+const prefix = "bb-"
+
+// NewCmdExtension creates the extension command group
+func NewCmdExtension(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "extension <command>",
+		Aliases: []string{"ext"},
+		Short:   "Manage bbc extensions",
+		Long: `Extensions are custom bbc commands shipped as separate executables.
+
+Running 'bbc <name>' where <name> is neither a built-in command nor an alias
+runs the executable bb-<name>: an installed extension first, then one found on
+PATH. Arguments are passed through unchanged.
+
+An extension is a git repository named bb-<name> containing an executable
+bb-<name> at its root. Extensions run with the current environment plus:
+
+  BB_EXECUTABLE  path of bbc, to call back into it (e.g. "$BB_EXECUTABLE api")
+  BB_HOST        the active host
+  BB_API_URL     its API base URL
+  BB_AUTH        "basic" (username + App Password) or "bearer"
+  BB_PROFILE     the active profile, if not the default
+  BB_WORKSPACE   the workspace from flags, environment or config, if any
+
+Credentials are not put in the environment: extensions that call the API
+themselves read them with "$BB_EXECUTABLE auth token --json".`,
+	}
+
+	cmd.AddCommand(NewCmdInstall(f))
+	cmd.AddCommand(NewCmdList(f))
+	cmd.AddCommand(NewCmdRemove(f))
+
+	return cmd
+}
+
+// Dir returns the directory extensions are installed in
+func Dir() string {
+	return filepath.Join(config.Dir(), "extensions")
+}
+
+// Lookup finds the executable of extension name: the installed extension,
+// then bb-<name> on PATH
+func Lookup(name string) (string, bool) {
+	if validName(name) != nil {
+		return "", false
+	}
+	if exe, ok := installedExecutable(filepath.Join(Dir(), prefix+name)); ok {
+		return exe, true
+	}
+	if exe, err := exec.LookPath(prefix + name); err == nil {
+		return exe, true
+	}
+	return "", false
+}
+
+// Environ returns the environment extensions run with
+func Environ(f *cmdutil.Factory) []string {
+	env := os.Environ()
+	set := func(key, value string) {
+		if value != "" {
+			// The last value of a duplicated key wins
+			env = append(env, key+"="+value)
+		}
+	}
+
+	if exe, err := os.Executable(); err == nil {
+		set("BB_EXECUTABLE", exe)
+	}
+	if host, err := f.Host(); err == nil {
+		apiURL := host.APIURL
+		if apiURL == "" {
+			apiURL = bbcloud.DefaultBaseURL
+		}
+		set("BB_HOST", host.Name)
+		set("BB_API_URL", apiURL)
+		set("BB_AUTH", host.Auth)
+	}
+	if profile, err := f.Profile(); err == nil {
+		set("BB_PROFILE", profile)
+	}
+	if ws, _, err := f.ResolveWorkspace("", false); err == nil {
+		set("BB_WORKSPACE", ws)
+	}
+	return env
+}
+
+type installedExtension struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Source string `json:"source"` // git remote, or the directory of a local extension
+}
+
+// installed lists the installed extensions by name
+func installed() ([]installedExtension, error) {
+	entries, err := os.ReadDir(Dir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read extensions: %w", err)
+	}
+
+	var exts []installedExtension
+	for _, e := range entries {
+		name, ok := strings.CutPrefix(e.Name(), prefix)
+		if !ok {
+			continue
+		}
+		dir := filepath.Join(Dir(), e.Name())
+		exe, ok := installedExecutable(dir)
+		if !ok {
+			continue
+		}
+		exts = append(exts, installedExtension{Name: name, Path: exe, Source: source(dir)})
+	}
+	sort.Slice(exts, func(i, j int) bool { return exts[i].Name < exts[j].Name })
+	return exts, nil
+}
+
+// installedExecutable returns the executable of the extension installed in
+// dir, named like dir itself
+func installedExecutable(dir string) (string, bool) {
+	exe := filepath.Join(dir, filepath.Base(dir))
+	if runtime.GOOS == "windows" {
+		exe += ".exe"
+	}
+	info, err := os.Stat(exe)
+	if err != nil || info.IsDir() || (runtime.GOOS != "windows" && info.Mode()&0o111 == 0) {
+		return "", false
+	}
+	return exe, true
+}
+
+// source describes where an installed extension came from
+func source(dir string) string {
+	if target, err := os.Readlink(dir); err == nil {
+		return target
+	}
+	out, err := exec.Command("git", "-C", dir, "remote", "get-url", "origin").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// validName rejects extension names that cannot be typed as a command
+func validName(name string) error {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, `/\ .`) {
+		return fmt.Errorf("invalid extension name %q", name)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/iostreams"
+)
+
+func writeExecutable(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("extensions are looked up without .exe here")
+	}
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	pathDir := t.TempDir()
+	t.Setenv("PATH", pathDir)
+
+	writeExecutable(t, filepath.Join(Dir(), "bb-release", "bb-release"))
+	writeExecutable(t, filepath.Join(pathDir, "bb-release"))
+	writeExecutable(t, filepath.Join(pathDir, "bb-stats"))
+
+	if exe, ok := Lookup("release"); !ok || exe != filepath.Join(Dir(), "bb-release", "bb-release") {
+		t.Errorf("Lookup(release) = %q, %v, want the installed extension first", exe, ok)
+	}
+	if exe, ok := Lookup("stats"); !ok || exe != filepath.Join(pathDir, "bb-stats") {
+		t.Errorf("Lookup(stats) = %q, %v, want bb-stats on PATH", exe, ok)
+	}
+	for _, name := range []string{"missing", "-x", "../bb-release", ""} {
+		if exe, ok := Lookup(name); ok {
+			t.Errorf("Lookup(%q) = %q, want not found", name, exe)
+		}
+	}
+
+	exts, err := installed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exts) != 1 || exts[0].Name != "release" {
+		t.Errorf("installed = %+v, want only release", exts)
+	}
+}
+
+func TestResolveSource(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv("BB_HOST", "")
+	f := cmdutil.NewFactory("test", iostreams.System())
+
+	dir := t.TempDir()
+	tests := []struct {
+		arg, want string
+		local     bool
+	}{
+		{"acme/bb-release", "https://bitbucket.org/acme/bb-release.git", false},
+		{"git@github.com:acme/bb-release.git", "git@github.com:acme/bb-release.git", false},
+		{"https://example.com/bb-release", "https://example.com/bb-release", false},
+		{dir, dir, true},
+	}
+	for _, tt := range tests {
+		got, local, err := resolveSource(f, tt.arg)
+		if err != nil {
+			t.Errorf("resolveSource(%q): %v", tt.arg, err)
+			continue
+		}
+		if got != tt.want || local != tt.local {
+			t.Errorf("resolveSource(%q) = %q, %v, want %q, %v", tt.arg, got, local, tt.want, tt.local)
+		}
+	}
+
+	if _, _, err := resolveSource(f, "bb-release"); err == nil {
+		t.Error("bare name accepted")
+	}
+}
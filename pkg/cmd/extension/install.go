@@ -0,0 +1,108 @@
+package extension
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// NewCmdInstall creates the extension install command
+func NewCmdInstall(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "install <repo>",
+		Short: "Install an extension from a git repository",
+		Long: `Install an extension by cloning its repository, which must be named
+bb-<name> and contain an executable bb-<name> at its root.
+
+The repository is a Bitbucket workspace/repo on the active host, any git URL,
+or a local directory. A local directory is linked rather than copied, so
+changes to it take effect immediately — handy while writing an extension.
+
+An extension may not shadow a built-in command.
+
+Examples:
+  bbc extension install acme/bb-deploy
+  bbc extension install git@github.com:acme/bb-deploy.git
+  bbc extension install .`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			src, local, err := resolveSource(f, args[0])
+			if err != nil {
+				return err
+			}
+			repoName := strings.TrimSuffix(path.Base(filepath.ToSlash(src)), ".git")
+			name, ok := strings.CutPrefix(repoName, prefix)
+			if !ok {
+				return fmt.Errorf("extension repository must be named %s<name>, got %q", prefix, repoName)
+			}
+			if err := validName(name); err != nil {
+				return err
+			}
+			if found, _, err := cmd.Root().Find([]string{name}); err == nil && found != cmd.Root() {
+				return fmt.Errorf("%q is already a bbc command", name)
+			}
+
+			dir := filepath.Join(Dir(), repoName)
+			if _, err := os.Lstat(dir); err == nil {
+				return fmt.Errorf("extension %s is already installed (remove it first)", name)
+			}
+			if err := os.MkdirAll(Dir(), 0o755); err != nil {
+				return fmt.Errorf("create extensions directory: %w", err)
+			}
+
+			if local {
+				if err := os.Symlink(src, dir); err != nil {
+					return fmt.Errorf("link extension: %w", err)
+				}
+			} else {
+				git := exec.CommandContext(cmd.Context(), "git", "clone", "--depth", "1", "--quiet", src, dir)
+				git.Stdout, git.Stderr = f.IOStreams.ErrOut, f.IOStreams.ErrOut
+				if err := git.Run(); err != nil {
+					_ = os.RemoveAll(dir)
+					return fmt.Errorf("clone %s: %w", src, err)
+				}
+			}
+
+			exe, ok := installedExecutable(dir)
+			if !ok {
+				_ = os.RemoveAll(dir)
+				return fmt.Errorf("%s has no executable %s at its root", src, repoName)
+			}
+
+			return cmdutil.WriteJSON(f.IOStreams.Out, installedExtension{Name: name, Path: exe, Source: src})
+		},
+	}
+}
+
+// resolveSource turns the install argument into a clone URL, or an absolute
+// directory for a local extension
+func resolveSource(f *cmdutil.Factory, arg string) (src string, local bool, err error) {
+	if info, err := os.Stat(arg); err == nil && info.IsDir() {
+		abs, err := filepath.Abs(arg)
+		if err != nil {
+			return "", false, err
+		}
+		return abs, true, nil
+	}
+
+	if strings.Contains(arg, "://") || strings.HasPrefix(arg, "git@") {
+		return arg, false, nil
+	}
+
+	ws, repo, ok := strings.Cut(arg, "/")
+	if !ok || ws == "" || repo == "" || strings.Contains(repo, "/") {
+		return "", false, fmt.Errorf("expected workspace/repo, a git URL or a directory, got %q", arg)
+	}
+	host, err := f.Host()
+	if err != nil {
+		return "", false, err
+	}
+	return fmt.Sprintf("https://%s/%s/%s.git", host.Name, ws, repo), false, nil
+}
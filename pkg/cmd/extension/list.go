@@ -0,0 +1,48 @@
+package extension
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// NewCmdList creates the extension list command
+func NewCmdList(f *cmdutil.Factory) *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List installed extensions",
+		Long: `List installed extensions. Executables named bb-<name> on PATH also
+run as extensions but are not listed.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			exts, err := installed()
+			if err != nil {
+				return err
+			}
+
+			ios := f.IOStreams
+			if asJSON {
+				if exts == nil {
+					exts = []installedExtension{}
+				}
+				return cmdutil.WriteJSON(ios.Out, exts)
+			}
+			if len(exts) == 0 {
+				_, _ = fmt.Fprintln(ios.ErrOut, "no extensions installed")
+				return nil
+			}
+			for _, e := range exts {
+				_, _ = fmt.Fprintf(ios.Out, "%s: %s\n", e.Name, e.Source)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&asJSON, "json", false, "Output JSON instead of text")
+
+	return cmd
+}
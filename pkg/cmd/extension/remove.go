@@ -0,0 +1,43 @@
+package extension
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// NewCmdRemove creates the extension remove command
+func NewCmdRemove(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove an installed extension",
+		Long: `Remove an installed extension. A linked local extension is unlinked;
+its directory is left alone.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := strings.TrimPrefix(args[0], prefix)
+			if err := validName(name); err != nil {
+				return err
+			}
+
+			dir := filepath.Join(Dir(), prefix+name)
+			if _, err := os.Lstat(dir); err != nil {
+				return fmt.Errorf("no such extension: %s", name)
+			}
+			// RemoveAll removes a symlink itself, not what it points to
+			if err := os.RemoveAll(dir); err != nil {
+				return fmt.Errorf("remove extension: %w", err)
+			}
+
+			return cmdutil.WriteJSON(f.IOStreams.Out, map[string]interface{}{
+				"extension": name,
+				"deleted":   true,
+			})
+		},
+	}
+}
@@ -0,0 +1,473 @@
+package inbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ghoseb/bb/internal/config"
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/httpx"
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+const (
+	inboxMinInterval = 15 * time.Second
+	// firstCheckWindow is how far back the first check looks, before any
+	// watermark exists
+	firstCheckWindow = 24 * time.Hour
+)
+
+// Inbox item kinds
+const (
+	kindComment       = "comment"
+	kindReviewRequest = "review_request"
+	kindBuildFailed   = "build_failed"
+)
+
+type inboxOptions struct {
+	workspaces []string
+	since      string
+	peek       bool
+	watch      bool
+	interval   time.Duration
+	json       bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdInbox creates the inbox command
+func NewCmdInbox(f *cmdutil.Factory) *cobra.Command {
+	opts := &inboxOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "inbox",
+		Short: "Show what happened on your PRs since you last checked",
+		Long: `Show new activity across every workspace you belong to since the last
+check: comments by others on your open pull requests, new requests for your
+review, and failed builds on your pull requests.
+
+The time of each check is kept in the cache directory, per profile, so the
+next run only shows what is new; the first run looks back 24 hours. --since
+looks back from a date or age instead, and --peek leaves the watermark
+where it is.
+
+--watch keeps polling every --interval and prints new items as they arrive:
+as lines on a terminal, otherwise (or with --json) as one JSON object per
+line.
+
+Examples:
+  bb inbox
+  bb inbox --since 3d --peek
+  bb inbox --watch --interval 2m
+  bb inbox --workspace acme --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.watch && opts.interval < inboxMinInterval {
+				return fmt.Errorf("--interval must be at least %s", inboxMinInterval)
+			}
+			return runInbox(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&opts.workspaces, "workspace", "w", nil, "Only these workspaces (default: all you belong to)")
+	cmd.Flags().StringVar(&opts.since, "since", "", "Show activity since a date or age (e.g. 3d) instead of the last check")
+	cmd.Flags().BoolVar(&opts.peek, "peek", false, "Do not mark the items as seen")
+	cmd.Flags().BoolVar(&opts.watch, "watch", false, "Keep polling and print new items as they arrive")
+	cmd.Flags().DurationVar(&opts.interval, "interval", time.Minute, "Time between polls with --watch")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of markdown")
+
+	return cmd
+}
+
+type inboxItem struct {
+	Kind      string `json:"kind"` // comment, review_request or build_failed
+	Workspace string `json:"workspace"`
+	Repo      string `json:"repo"`
+	ID        int    `json:"id"`
+	Title     string `json:"title"`
+	Who       string `json:"who,omitempty"`    // commenter or PR author
+	Detail    string `json:"detail,omitempty"` // comment excerpt or build name
+	At        string `json:"at"`
+}
+
+type inboxOutput struct {
+	User            string      `json:"user"`
+	Since           string      `json:"since"`
+	Items           []inboxItem `json:"items"`
+	FailedWorkspace []string    `json:"failed_workspaces,omitempty"` // could not be queried
+}
+
+// inboxState is the watermark persisted between checks
+type inboxState struct {
+	LastCheck time.Time `json:"last_check"`
+	// ReviewRequests are the PRs (workspace/repo#id) that awaited your review
+	// at the last check, so only new requests are reported
+	ReviewRequests []string `json:"review_requests"`
+}
+
+func runInbox(ctx context.Context, opts *inboxOptions) error {
+	client, err := opts.factory.NewBBCloudClient("")
+	if err != nil {
+		return err
+	}
+	me, err := client.CurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	workspaces := opts.workspaces
+	if len(workspaces) == 0 {
+		permissions, err := client.ListWorkspaces(ctx)
+		if err != nil {
+			return err
+		}
+		for _, p := range permissions {
+			workspaces = append(workspaces, p.Workspace.Slug)
+		}
+	}
+
+	profile, err := opts.factory.Profile()
+	if err != nil {
+		return err
+	}
+	path := statePath(profile)
+	state, err := loadState(path)
+	if err != nil {
+		return err
+	}
+	if opts.since != "" {
+		since, err := cmdutil.ParseSince(opts.since, time.Now())
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		state = &inboxState{LastCheck: since}
+	} else if state.LastCheck.IsZero() {
+		state.LastCheck = time.Now().Add(-firstCheckWindow)
+	}
+
+	ios := opts.factory.IOStreams
+	enc := json.NewEncoder(ios.Out)
+	lines := opts.watch && !opts.json && ios.IsStdoutTTY()
+	if opts.watch && lines {
+		_, _ = fmt.Fprintf(ios.ErrOut, "Watching your inbox every %s · Ctrl-C to stop\n", opts.interval)
+	}
+
+	for {
+		checkedAt := time.Now()
+		// Each check reads afresh
+		output, seen := collect(httpx.WithMemo(ctx), opts.factory, me, workspaces, state)
+		for _, ws := range output.FailedWorkspace {
+			_, _ = fmt.Fprintf(ios.ErrOut, "warning: failed to query workspace %s\n", ws)
+		}
+		if ctx.Err() != nil {
+			// Interrupted mid-check: keep the watermark where it was
+			return nil
+		}
+
+		switch {
+		case !opts.watch && opts.json:
+			if err := cmdutil.WriteJSON(ios.Out, output); err != nil {
+				return fmt.Errorf("encode output: %w", err)
+			}
+		case !opts.watch:
+			renderMarkdownInbox(ios.Out, output)
+		case lines:
+			for _, item := range output.Items {
+				_, _ = fmt.Fprintf(ios.Out, "%s %s\n", item.At[11:16], formatItem(item))
+			}
+		default:
+			for _, item := range output.Items {
+				_ = enc.Encode(item)
+			}
+		}
+
+		// Failed workspaces are checked again from the old watermark next time
+		if len(output.FailedWorkspace) == 0 {
+			state = &inboxState{LastCheck: checkedAt, ReviewRequests: seen}
+			if !opts.peek {
+				if err := saveState(path, state); err != nil {
+					return err
+				}
+			}
+		}
+
+		if !opts.watch {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(opts.interval):
+		}
+	}
+}
+
+// collect gathers the inbox items newer than state, newest first, and the
+// review requests now open
+func collect(ctx context.Context, f *cmdutil.Factory, me *bbcloud.User, workspaces []string, state *inboxState) (inboxOutput, []string) {
+	output := inboxOutput{
+		User:  me.DisplayName,
+		Since: state.LastCheck.UTC().Format(time.RFC3339),
+		Items: make([]inboxItem, 0),
+	}
+
+	var (
+		mu   sync.Mutex
+		seen []string
+	)
+	results := make([][]inboxItem, len(workspaces))
+	var g errgroup.Group
+	g.SetLimit(2)
+	for i, ws := range workspaces {
+		g.Go(func() error {
+			items, requests, err := collectWorkspace(ctx, f, ws, me, state)
+			if err != nil {
+				return nil
+			}
+			results[i] = items
+			mu.Lock()
+			seen = append(seen, requests...)
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	for i, items := range results {
+		if items == nil {
+			output.FailedWorkspace = append(output.FailedWorkspace, workspaces[i])
+			continue
+		}
+		output.Items = append(output.Items, items...)
+	}
+	slices.SortStableFunc(output.Items, func(a, b inboxItem) int {
+		return strings.Compare(b.At, a.At)
+	})
+	slices.Sort(seen)
+	return output, seen
+}
+
+// collectWorkspace returns ws's new inbox items (never nil on success) and
+// the keys of the PRs awaiting my review there
+func collectWorkspace(ctx context.Context, f *cmdutil.Factory, ws string, me *bbcloud.User, state *inboxState) ([]inboxItem, []string, error) {
+	client, err := f.NewBBCloudClient(ws)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := fmt.Sprintf("(%s OR %s)", bbcloud.UserQueryTerm("author", me.UUID), bbcloud.UserQueryTerm("reviewers", me.UUID))
+	prs, err := client.ListWorkspacePullRequests(ctx, bbcloud.PRListOptions{State: "OPEN", Query: query}, f.Concurrency())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := make([]inboxItem, 0)
+	var requests []string
+	var authored []bbcloud.PullRequest
+	for _, pr := range prs {
+		if pr.Author != nil && pr.Author.UUID == me.UUID {
+			authored = append(authored, pr)
+			continue
+		}
+		if pr.ApprovedBy(me.UUID) {
+			continue
+		}
+		key := prKey(ws, &pr)
+		requests = append(requests, key)
+		if !slices.Contains(state.ReviewRequests, key) {
+			item := newItem(kindReviewRequest, ws, &pr, pr.UpdatedOn)
+			if pr.Author != nil {
+				item.Who = pr.Author.DisplayName
+			}
+			items = append(items, item)
+		}
+	}
+
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(f.Concurrency())
+	for i := range authored {
+		pr := &authored[i]
+		repo := repoSlug(pr)
+		g.Go(func() error {
+			found, err := newActivity(gctx, client, repo, pr, me, ws, state.LastCheck)
+			if err != nil {
+				return fmt.Errorf("%s#%d: %w", repo, pr.ID, err)
+			}
+			mu.Lock()
+			items = append(items, found...)
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+	return items, requests, nil
+}
+
+// newActivity returns the comments by others and failed builds on one of my
+// PRs since the last check
+func newActivity(ctx context.Context, client *bbcloud.Client, repo string, pr *bbcloud.PullRequest, me *bbcloud.User, ws string, since time.Time) ([]inboxItem, error) {
+	var items []inboxItem
+
+	statuses, err := client.GetPRPipelines(ctx, repo, pr.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get build status: %w", err)
+	}
+	for _, s := range statuses {
+		if s.State == "FAILED" && s.UpdatedOn.After(since) {
+			item := newItem(kindBuildFailed, ws, pr, s.UpdatedOn)
+			item.Detail = s.Name
+			if item.Detail == "" {
+				item.Detail = s.Key
+			}
+			items = append(items, item)
+		}
+	}
+
+	// Commenting bumps the PR, so untouched PRs have no new comments
+	if !pr.UpdatedOn.After(since) {
+		return items, nil
+	}
+	comments, err := client.ListPRComments(ctx, repo, pr.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list comments: %w", err)
+	}
+	for _, c := range comments {
+		if c.Deleted || !c.CreatedOn.After(since) || (c.User != nil && c.User.UUID == me.UUID) {
+			continue
+		}
+		item := newItem(kindComment, ws, pr, c.CreatedOn)
+		if c.User != nil {
+			item.Who = c.User.DisplayName
+		}
+		if c.Content != nil {
+			item.Detail = excerpt(c.Content.Raw, 80)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func newItem(kind, ws string, pr *bbcloud.PullRequest, at time.Time) inboxItem {
+	return inboxItem{
+		Kind:      kind,
+		Workspace: ws,
+		ID:        pr.ID,
+		Repo:      repoSlug(pr),
+		Title:     pr.Title,
+		At:        at.UTC().Format(time.RFC3339),
+	}
+}
+
+func repoSlug(pr *bbcloud.PullRequest) string {
+	if pr.Destination != nil && pr.Destination.Repository != nil {
+		return pr.Destination.Repository.Slug
+	}
+	return ""
+}
+
+// prKey identifies a PR across workspaces as workspace/repo#id
+func prKey(ws string, pr *bbcloud.PullRequest) string {
+	return fmt.Sprintf("%s/%s#%d", ws, repoSlug(pr), pr.ID)
+}
+
+// excerpt returns the first line of s, cut to n runes
+func excerpt(s string, n int) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
+
+// formatItem renders an item as one line
+func formatItem(item inboxItem) string {
+	pr := fmt.Sprintf("%s/%s#%d %s", item.Workspace, item.Repo, item.ID, text.Line(item.Title))
+	switch item.Kind {
+	case kindComment:
+		return fmt.Sprintf("💬 %s — %s: %s", pr, text.Line(item.Who), text.Line(item.Detail))
+	case kindReviewRequest:
+		return fmt.Sprintf("👀 %s — review requested by %s", pr, text.Line(item.Who))
+	default:
+		return fmt.Sprintf("❌ %s — %s failed", pr, text.Line(item.Detail))
+	}
+}
+
+func renderMarkdownInbox(w io.Writer, output inboxOutput) {
+	since := output.Since[:16]
+	if len(output.Items) == 0 {
+		_, _ = fmt.Fprintf(w, "# Inbox — nothing new since %s\n", since)
+		return
+	}
+	_, _ = fmt.Fprintf(w, "# Inbox — %d new since %s\n", len(output.Items), since)
+
+	section := func(title, kind string) {
+		var lines []string
+		for _, item := range output.Items {
+			if item.Kind == kind {
+				lines = append(lines, fmt.Sprintf("- %s %s", item.At[:10], formatItem(item)))
+			}
+		}
+		if len(lines) == 0 {
+			return
+		}
+		_, _ = fmt.Fprintf(w, "\n## %s (%d)\n%s\n", title, len(lines), strings.Join(lines, "\n"))
+	}
+	section("Comments on your PRs", kindComment)
+	section("Review requests", kindReviewRequest)
+	section("Failed builds", kindBuildFailed)
+}
+
+// statePath returns where the watermark of profile is kept
+func statePath(profile string) string {
+	if profile == "" {
+		profile = "default"
+	}
+	return filepath.Join(config.CacheDir(), "inbox", profile+".json")
+}
+
+// loadState reads the watermark, returning an empty state if none exists
+func loadState(path string) (*inboxState, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &inboxState{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read inbox state: %w", err)
+	}
+	var state inboxState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parse inbox state %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+// saveState writes the watermark, creating its directory if needed
+func saveState(path string, state *inboxState) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create inbox state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode inbox state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write inbox state: %w", err)
+	}
+	return nil
+}
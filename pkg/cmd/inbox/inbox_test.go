@@ -0,0 +1,74 @@
+package inbox
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inbox", "default.json")
+
+	state, err := loadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.LastCheck.IsZero() || len(state.ReviewRequests) != 0 {
+		t.Errorf("missing state = %+v, want empty", state)
+	}
+
+	checked := time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC)
+	want := &inboxState{LastCheck: checked, ReviewRequests: []string{"acme/api#7"}}
+	if err := saveState(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.LastCheck.Equal(checked) || strings.Join(got.ReviewRequests, ",") != "acme/api#7" {
+		t.Errorf("loaded state = %+v, want %+v", got, want)
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	if got := excerpt("  Looks good\n\nbut one nit", 80); got != "Looks good" {
+		t.Errorf("excerpt = %q, want the first line", got)
+	}
+	if got := excerpt("abcdefghij", 5); got != "abcd…" {
+		t.Errorf("excerpt = %q, want abcd…", got)
+	}
+}
+
+func TestRenderMarkdownInbox(t *testing.T) {
+	output := inboxOutput{
+		User:  "Alice",
+		Since: "2026-05-01T09:00:00Z",
+		Items: []inboxItem{
+			{Kind: kindComment, Workspace: "acme", Repo: "api", ID: 7, Title: "Fix login", Who: "Bob", Detail: "Why not reuse the session?", At: "2026-05-01T10:00:00Z"},
+			{Kind: kindBuildFailed, Workspace: "acme", Repo: "api", ID: 7, Title: "Fix login", Detail: "unit-tests", At: "2026-05-01T09:45:00Z"},
+			{Kind: kindReviewRequest, Workspace: "acme", Repo: "web", ID: 9, Title: "Add page", Who: "Carol", At: "2026-05-01T09:15:00Z"},
+		},
+	}
+
+	var out strings.Builder
+	renderMarkdownInbox(&out, output)
+	got := out.String()
+	for _, want := range []string{
+		"# Inbox — 3 new since 2026-05-01T09:00",
+		"## Comments on your PRs (1)\n- 2026-05-01 💬 acme/api#7 Fix login — Bob: Why not reuse the session?",
+		"## Review requests (1)\n- 2026-05-01 👀 acme/web#9 Add page — review requested by Carol",
+		"## Failed builds (1)\n- 2026-05-01 ❌ acme/api#7 Fix login — unit-tests failed",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+
+	out.Reset()
+	renderMarkdownInbox(&out, inboxOutput{Since: output.Since})
+	if got := out.String(); got != "# Inbox — nothing new since 2026-05-01T09:00\n" {
+		t.Errorf("empty inbox = %q", got)
+	}
+}
@@ -0,0 +1,250 @@
+package list
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+// maxCommitCount caps each ahead/behind count, which costs one API call per
+// 100 commits
+const maxCommitCount = 500
+
+type branchesOptions struct {
+	repo   string
+	base   string
+	merged bool
+	stale  string
+	json   bool
+	pages  cmdutil.PageFlags
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdBranches creates the list branches command
+func NewCmdBranches(f *cmdutil.Factory) *cobra.Command {
+	opts := &branchesOptions{
+		factory: f,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "branches",
+		Short: "List branches with ahead/behind counts",
+		Long: `List a repository's branches, most recently committed first, with the
+author and date of the last commit and how many commits each branch is ahead
+of and behind the main branch (or --base).
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+--merged keeps branches with no commits missing from the base branch, i.e.
+safe to delete. --stale keeps branches whose last commit is older than a
+date or age. Counts above ` + fmt.Sprint(maxCommitCount) + ` are shown as ` + fmt.Sprint(maxCommitCount) + `+.
+
+One page of branches is fetched at a time and the filters apply within it:
+use --page and --per-page to move through them, or --all to fetch every
+page.
+
+Example:
+  bb list branches --repo test_repo
+  bb list branches --repo test_repo --merged --all
+  bb list branches --repo test_repo --stale 90d --page 2 --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.pages.Validate(); err != nil {
+				return err
+			}
+			return runListBranches(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+	cmd.Flags().StringVar(&opts.base, "base", "", "Branch to compare against (default: the repository's main branch)")
+	cmd.Flags().BoolVar(&opts.merged, "merged", false, "Only branches fully merged into the base branch")
+	cmd.Flags().StringVar(&opts.stale, "stale", "", "Only branches with no commits since a date or age (e.g. 90d)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of markdown (or a table with format: table)")
+	cmdutil.AddPageFlags(cmd, &opts.pages, 30)
+
+	return cmd
+}
+
+type branchInfo struct {
+	Name       string `json:"name"`
+	Author     string `json:"author"`
+	LastCommit string `json:"last_commit"`
+	Commit     string `json:"commit"`
+	Ahead      int    `json:"ahead"`
+	Behind     int    `json:"behind"`
+	Capped     bool   `json:"capped,omitempty"` // ahead or behind is a lower bound
+}
+
+type branchesOutput struct {
+	Repo     string       `json:"repo"`
+	Base     string       `json:"base"`
+	Branches []branchInfo `json:"branches"`
+	cmdutil.PageInfo
+}
+
+func runListBranches(ctx context.Context, opts *branchesOptions) error {
+	var cutoff time.Time
+	if opts.stale != "" {
+		var err error
+		if cutoff, err = cmdutil.ParseSince(opts.stale, time.Now()); err != nil {
+			return fmt.Errorf("invalid --stale: %w", err)
+		}
+	}
+
+	client, err := opts.factory.NewBBCloudClient("")
+	if err != nil {
+		return err
+	}
+
+	base := opts.base
+	if base == "" {
+		repo, err := client.GetRepository(ctx, opts.repo)
+		if err != nil {
+			return err
+		}
+		if repo.MainBranch == nil || repo.MainBranch.Name == "" {
+			return fmt.Errorf("repository %s has no main branch; pass --base", opts.repo)
+		}
+		base = repo.MainBranch.Name
+	}
+
+	listOpts := bbcloud.BranchListOptions{Sort: "-target.date"}
+	branches, page, err := cmdutil.FetchPages(&opts.pages, func(page, perPage int) ([]bbcloud.Branch, int, error) {
+		return client.BranchesPage(ctx, opts.repo, listOpts, page, perPage)
+	})
+	if err != nil {
+		return err
+	}
+
+	var candidates []bbcloud.Branch
+	for _, b := range branches {
+		if b.Name == base || !isStale(b, cutoff) {
+			continue
+		}
+		candidates = append(candidates, b)
+	}
+
+	infos := make([]branchInfo, len(candidates))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(opts.factory.Concurrency())
+	for i, b := range candidates {
+		g.Go(func() error {
+			info := newBranchInfo(b)
+			var aheadCapped, behindCapped bool
+			var err error
+			if info.Ahead, aheadCapped, err = client.CountCommits(gctx, opts.repo, b.Name, base, maxCommitCount); err != nil {
+				return fmt.Errorf("compare %s with %s: %w", b.Name, base, err)
+			}
+			if info.Behind, behindCapped, err = client.CountCommits(gctx, opts.repo, base, b.Name, maxCommitCount); err != nil {
+				return fmt.Errorf("compare %s with %s: %w", base, b.Name, err)
+			}
+			info.Capped = aheadCapped || behindCapped
+			infos[i] = info
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	output := branchesOutput{Repo: opts.repo, Base: base, Branches: make([]branchInfo, 0, len(infos)), PageInfo: page}
+	for _, info := range infos {
+		if opts.merged && info.Ahead > 0 {
+			continue
+		}
+		output.Branches = append(output.Branches, info)
+	}
+
+	ios := opts.factory.IOStreams
+	if opts.json {
+		if err := cmdutil.WriteJSON(ios.Out, output); err != nil {
+			return fmt.Errorf("encode output: %w", err)
+		}
+		return nil
+	}
+
+	if opts.factory.Format() == cmdutil.FormatTable {
+		err = renderTableBranches(ios.Out, output.Branches)
+	} else {
+		err = renderMarkdownBranches(ios.Out, output)
+	}
+	if err != nil {
+		return err
+	}
+	cmdutil.WriteMoreHint(ios.ErrOut, page.NextPage)
+	return nil
+}
+
+// isStale reports whether the branch's last commit is before cutoff; every
+// branch is stale for a zero cutoff
+func isStale(b bbcloud.Branch, cutoff time.Time) bool {
+	if cutoff.IsZero() {
+		return true
+	}
+	return b.Target != nil && b.Target.Date.Before(cutoff)
+}
+
+func newBranchInfo(b bbcloud.Branch) branchInfo {
+	info := branchInfo{Name: b.Name}
+	if b.Target != nil {
+		info.Commit = shortHash(b.Target.Hash)
+		info.LastCommit = b.Target.Date.Format("2006-01-02T15:04:05Z07:00")
+		if b.Target.Author != nil {
+			info.Author = b.Target.Author.Name()
+		}
+	}
+	return info
+}
+
+// formatCount renders a commit count, marking lower bounds with +
+func formatCount(n int, capped bool) string {
+	if capped && n >= maxCommitCount {
+		return fmt.Sprintf("%d+", n)
+	}
+	return fmt.Sprint(n)
+}
+
+// renderTableBranches prints aligned columns for terminal reading
+func renderTableBranches(w io.Writer, branches []branchInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "BRANCH\tAHEAD\tBEHIND\tAUTHOR\tLAST COMMIT")
+	for _, b := range branches {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", text.Line(b.Name),
+			formatCount(b.Ahead, b.Capped), formatCount(b.Behind, b.Capped), text.Line(b.Author), dateOnly(b.LastCommit))
+	}
+	return tw.Flush()
+}
+
+func renderMarkdownBranches(w io.Writer, output branchesOutput) error {
+	if len(output.Branches) == 0 {
+		_, _ = fmt.Fprintf(w, "# No branches found — %s\n", output.Repo)
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(w, "# Branches — %s (vs %s)\n\n", output.Repo, output.Base)
+	_, _ = fmt.Fprintf(w, "| Branch | Ahead | Behind | Author | Last commit |\n")
+	_, _ = fmt.Fprintf(w, "|--------|-------|--------|--------|-------------|\n")
+	for _, b := range output.Branches {
+		_, _ = fmt.Fprintf(w, "| %s | %s | %s | %s | %s |\n", text.Line(b.Name),
+			formatCount(b.Ahead, b.Capped), formatCount(b.Behind, b.Capped), text.Line(b.Author), dateOnly(b.LastCommit))
+	}
+	return nil
+}
+
+// dateOnly trims an RFC 3339 timestamp to its date
+func dateOnly(ts string) string {
+	date, _, _ := strings.Cut(ts, "T")
+	return date
+}
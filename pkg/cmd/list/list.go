@@ -10,14 +10,21 @@ import (
 func NewCmdList(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "list <command>",
-		Short: "List repositories",
-		Long: `List repositories in your Bitbucket workspace.
+		Short: "List workspaces, projects, repositories, branches, pull requests and pipelines",
+		Long: `List the workspaces you belong to, or the projects, repositories, pull
+requests across all repositories, or the latest pipeline of each repository
+in your Bitbucket workspace, or the branches of one repository.
 
-For pull requests, use:
+For pull requests in one repository, use:
   bb review list --repo <repo>`,
 	}
 
 	cmd.AddCommand(NewCmdRepos(f))
+	cmd.AddCommand(NewCmdPRs(f))
+	cmd.AddCommand(NewCmdPipelines(f))
+	cmd.AddCommand(NewCmdWorkspaces(f))
+	cmd.AddCommand(NewCmdProjects(f))
+	cmd.AddCommand(NewCmdBranches(f))
 
 	return cmd
 }
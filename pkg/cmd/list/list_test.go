@@ -1,8 +1,14 @@
 package list
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
 	"github.com/ghoseb/bb/pkg/cmdutil"
 	"github.com/ghoseb/bb/pkg/iostreams"
 )
@@ -23,8 +29,8 @@ func TestCommandStructure(t *testing.T) {
 	
 	// Check subcommands
 	subcommands := cmd.Commands()
-	if len(subcommands) != 1 {
-		t.Errorf("expected 1 subcommand, got %d", len(subcommands))
+	if len(subcommands) != 6 {
+		t.Errorf("expected 6 subcommands, got %d", len(subcommands))
 	}
 	
 	names := make(map[string]bool)
@@ -35,6 +41,21 @@ func TestCommandStructure(t *testing.T) {
 	if !names["repos"] {
 		t.Error("expected 'repos' subcommand")
 	}
+	if !names["prs"] {
+		t.Error("expected 'prs' subcommand")
+	}
+	if !names["pipelines"] {
+		t.Error("expected 'pipelines' subcommand")
+	}
+	if !names["workspaces"] {
+		t.Error("expected 'workspaces' subcommand")
+	}
+	if !names["projects"] {
+		t.Error("expected 'projects' subcommand")
+	}
+	if !names["branches"] {
+		t.Error("expected 'branches' subcommand")
+	}
 }
 
 func TestReposCommandFlags(t *testing.T) {
@@ -77,3 +98,178 @@ func TestReposCommandArgValidation(t *testing.T) {
 		t.Errorf("expected no error with no args, got: %v", err)
 	}
 }
+
+func TestPageFlagsRegistered(t *testing.T) {
+	factory := cmdutil.NewFactory("test", iostreams.System())
+
+	for _, cmd := range []*cobra.Command{
+		NewCmdRepos(factory),
+		NewCmdWorkspaces(factory),
+		NewCmdProjects(factory),
+		NewCmdBranches(factory),
+	} {
+		for _, name := range []string{"page", "per-page", "all"} {
+			if cmd.Flags().Lookup(name) == nil {
+				t.Errorf("%s: expected --%s flag", cmd.Name(), name)
+			}
+		}
+	}
+}
+
+func TestNewWorkspacePRInfo(t *testing.T) {
+	pr := &bbcloud.PullRequest{
+		ID:          7,
+		Title:       "Fix login",
+		Author:      &bbcloud.User{DisplayName: "Alice"},
+		State:       "OPEN",
+		Source:      &bbcloud.PullRequestBranch{Branch: &bbcloud.Branch{Name: "fix"}},
+		Destination: &bbcloud.PullRequestBranch{Branch: &bbcloud.Branch{Name: "main"}, Repository: &bbcloud.Repository{Slug: "api"}},
+		UpdatedOn:   time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+	}
+
+	got := newWorkspacePRInfo(pr)
+	want := workspacePRInfo{Repo: "api", ID: 7, Title: "Fix login", Author: "Alice", State: "OPEN",
+		Source: "fix", Target: "main", Updated: "2026-03-01T09:00:00Z"}
+	if got != want {
+		t.Errorf("newWorkspacePRInfo() = %+v, want %+v", got, want)
+	}
+
+	// Missing nested fields must not panic
+	if got := newWorkspacePRInfo(&bbcloud.PullRequest{ID: 8}); got.ID != 8 || got.Repo != "" {
+		t.Errorf("newWorkspacePRInfo(empty) = %+v", got)
+	}
+}
+
+func TestNewRepoPipelineInfo(t *testing.T) {
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	p := &bbcloud.Pipeline{
+		BuildNumber: 42,
+		State:       &bbcloud.PipelineState{Name: "COMPLETED", Result: &bbcloud.PipelineResult{Name: "FAILED"}},
+		CreatedOn:   created,
+		Target:      &bbcloud.PipelineTarget{RefName: "main", Commit: &bbcloud.CommitReference{Hash: "0123456789abcdef"}},
+	}
+
+	got := newRepoPipelineInfo("api", p)
+	want := repoPipelineInfo{Repo: "api", Status: "failed", BuildNumber: 42, Branch: "main",
+		Commit: "0123456789ab", Created: "2026-03-01T09:00:00Z"}
+	if got != want {
+		t.Errorf("newRepoPipelineInfo() = %+v, want %+v", got, want)
+	}
+
+	running := &bbcloud.Pipeline{State: &bbcloud.PipelineState{Name: "IN_PROGRESS"}, CreatedOn: created}
+	if got := newRepoPipelineInfo("web", running); got.Status != "in_progress" {
+		t.Errorf("running pipeline status = %q, want in_progress", got.Status)
+	}
+}
+
+func TestReposBuildQuery(t *testing.T) {
+	tests := []struct {
+		opts reposOptions
+		want string
+	}{
+		{reposOptions{}, ""},
+		{reposOptions{language: "Go"}, `language = "go"`},
+		{reposOptions{language: "go", project: "api", query: `name ~ "svc"`},
+			`language = "go" AND project.key = "API" AND (name ~ "svc")`},
+	}
+	for _, tt := range tests {
+		if got := tt.opts.buildQuery(); got != tt.want {
+			t.Errorf("buildQuery(%+v) = %q, want %q", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestRenderTableRepos(t *testing.T) {
+	var out strings.Builder
+	repos := []bbcloud.Repository{
+		{Slug: "api", IsPrivate: true, Language: "go", Project: &bbcloud.Project{Key: "CORE"},
+			UpdatedOn: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)},
+		{Slug: "site", UpdatedOn: time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)},
+	}
+	if err := renderTableRepos(&out, repos, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want header and 2 rows:\n%s", len(lines), out.String())
+	}
+	for i, want := range [][]string{
+		{"NAME", "PROJECT", "VISIBILITY", "LANGUAGE", "UPDATED"},
+		{"api", "CORE", "private", "go", "2026-03-01"},
+		{"site", "—", "public", "—", "2026-02-01"},
+	} {
+		if got := strings.Fields(lines[i]); strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("line %d = %q, want fields %q", i, lines[i], want)
+		}
+	}
+}
+
+func TestRenderTableReposWithStats(t *testing.T) {
+	var out strings.Builder
+	repos := []bbcloud.Repository{
+		{Slug: "api", Language: "go", UpdatedOn: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)},
+		{Slug: "site", UpdatedOn: time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)},
+	}
+	stats := []repoStats{{OpenPRs: 4, Pipeline: "failed"}, {}}
+	if err := renderTableRepos(&out, repos, stats); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	for i, want := range [][]string{
+		{"NAME", "PROJECT", "VISIBILITY", "LANGUAGE", "UPDATED", "OPEN", "PRS", "PIPELINE"},
+		{"api", "—", "public", "go", "2026-03-01", "4", "failed"},
+		{"site", "—", "public", "—", "2026-02-01", "0", "—"},
+	} {
+		if got := strings.Fields(lines[i]); strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("line %d = %q, want fields %q", i, lines[i], want)
+		}
+	}
+}
+
+func TestCountProjectRepos(t *testing.T) {
+	projects := []bbcloud.Project{{Key: "WEB", Name: "Web"}, {Key: "API", Name: "API", IsPrivate: true}}
+	repos := []bbcloud.Repository{
+		{Slug: "a", Project: &bbcloud.Project{Key: "API"}},
+		{Slug: "b", Project: &bbcloud.Project{Key: "API"}},
+		{Slug: "c"},
+	}
+
+	got := countProjectRepos(projects, repos)
+	want := []projectInfo{
+		{Key: "API", Name: "API", IsPrivate: true, Repos: 2},
+		{Key: "WEB", Name: "Web", Repos: 0},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("countProjectRepos() = %+v, want %+v", got, want)
+	}
+}
+
+func TestBranchHelpers(t *testing.T) {
+	lastCommit := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	b := bbcloud.Branch{Name: "feature", Target: &bbcloud.CommitReference{
+		Hash:   "0123456789abcdef",
+		Date:   lastCommit,
+		Author: &bbcloud.CommitAuthor{Raw: "Alice Smith <alice@example.com>"},
+	}}
+
+	info := newBranchInfo(b)
+	if info.Author != "Alice Smith" || info.Commit != "0123456789ab" || info.LastCommit != "2026-01-05T09:00:00Z" {
+		t.Errorf("newBranchInfo() = %+v", info)
+	}
+
+	if !isStale(b, time.Time{}) {
+		t.Error("every branch should match without a cutoff")
+	}
+	if !isStale(b, lastCommit.Add(time.Hour)) || isStale(b, lastCommit.Add(-time.Hour)) {
+		t.Error("isStale should compare the last commit date with the cutoff")
+	}
+
+	if got := formatCount(maxCommitCount, true); got != fmt.Sprintf("%d+", maxCommitCount) {
+		t.Errorf("formatCount(capped) = %q", got)
+	}
+	if got := formatCount(3, true); got != "3" {
+		t.Errorf("formatCount(3, capped sibling) = %q, want 3", got)
+	}
+}
@@ -0,0 +1,213 @@
+package list
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+// pipelineStatuses are the values accepted by --status
+var pipelineStatuses = []string{"successful", "failed", "error", "stopped", "in_progress", "pending", "paused"}
+
+type pipelinesOptions struct {
+	workspace string
+	status    string
+	json      bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdPipelines creates the list pipelines command
+func NewCmdPipelines(f *cmdutil.Factory) *cobra.Command {
+	opts := &pipelinesOptions{
+		factory: f,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "pipelines",
+		Short: "Show the latest pipeline of every repository",
+		Long: `Show the most recent pipeline of every repository in a workspace, as a
+quick CI health overview.
+
+Repositories are queried concurrently (--concurrency) and those without
+pipelines are left out. --status keeps only repositories whose latest
+pipeline has that status: ` + strings.Join(pipelineStatuses, ", ") + `.
+
+Example:
+  bb list pipelines
+  bb list pipelines --status failed
+  bb list pipelines --workspace other-workspace --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.status = strings.ToLower(opts.status)
+			if opts.status != "" && !slices.Contains(pipelineStatuses, opts.status) {
+				return fmt.Errorf("invalid --status %q (use one of %s)", opts.status, strings.Join(pipelineStatuses, ", "))
+			}
+			return runListPipelines(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.workspace, "workspace", "w", "",
+		"Workspace to list pipelines from (uses authenticated workspace if not specified)")
+	cmd.Flags().StringVar(&opts.status, "status", "", "Only repositories whose latest pipeline has this status")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of markdown (or a table with format: table)")
+
+	return cmd
+}
+
+type repoPipelineInfo struct {
+	Repo        string `json:"repo"`
+	Status      string `json:"status"`
+	BuildNumber int    `json:"build_number"`
+	Branch      string `json:"branch,omitempty"`
+	Commit      string `json:"commit,omitempty"`
+	Created     string `json:"created"`
+	Completed   string `json:"completed,omitempty"`
+}
+
+type pipelinesOutput struct {
+	Workspace string             `json:"workspace"`
+	Pipelines []repoPipelineInfo `json:"pipelines"`
+	Counts    map[string]int     `json:"counts"` // latest pipelines per status, before --status filtering
+}
+
+func runListPipelines(ctx context.Context, opts *pipelinesOptions) error {
+	client, err := opts.factory.NewBBCloudClient(opts.workspace)
+	if err != nil {
+		return err
+	}
+
+	repos, err := client.ListRepositories(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("list repositories: %w", err)
+	}
+
+	ios := opts.factory.IOStreams
+	latest := make([]*bbcloud.Pipeline, len(repos))
+	var g errgroup.Group
+	g.SetLimit(opts.factory.Concurrency())
+	for i, repo := range repos {
+		g.Go(func() error {
+			pipelines, err := client.ListPipelines(ctx, repo.Slug, 1)
+			if err != nil {
+				// Non-critical: Pipelines may be disabled for this repository
+				_, _ = fmt.Fprintf(ios.ErrOut, "warning: failed to fetch pipelines for %s: %v\n", repo.Slug, err)
+				return nil
+			}
+			if len(pipelines) > 0 {
+				latest[i] = &pipelines[0]
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	output := pipelinesOutput{
+		Workspace: client.Workspace(),
+		Pipelines: make([]repoPipelineInfo, 0),
+		Counts:    make(map[string]int),
+	}
+	for i, p := range latest {
+		if p == nil {
+			continue
+		}
+		info := newRepoPipelineInfo(repos[i].Slug, p)
+		output.Counts[info.Status]++
+		if opts.status == "" || info.Status == opts.status {
+			output.Pipelines = append(output.Pipelines, info)
+		}
+	}
+	sort.Slice(output.Pipelines, func(i, j int) bool {
+		return output.Pipelines[i].Repo < output.Pipelines[j].Repo
+	})
+
+	if opts.json {
+		if err := cmdutil.WriteJSON(ios.Out, output); err != nil {
+			return fmt.Errorf("encode output: %w", err)
+		}
+		return nil
+	}
+
+	if opts.factory.Format() == cmdutil.FormatTable {
+		return renderTablePipelines(ios.Out, output.Pipelines)
+	}
+	return renderMarkdownPipelines(ios.Out, output)
+}
+
+func newRepoPipelineInfo(repo string, p *bbcloud.Pipeline) repoPipelineInfo {
+	info := repoPipelineInfo{
+		Repo:        repo,
+		Status:      strings.ToLower(p.Status()),
+		BuildNumber: p.BuildNumber,
+		Created:     p.CreatedOn.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if p.CompletedOn != nil {
+		info.Completed = p.CompletedOn.Format("2006-01-02T15:04:05Z07:00")
+	}
+	if p.Target != nil {
+		info.Branch = p.Target.RefName
+		if p.Target.Commit != nil {
+			info.Commit = shortHash(p.Target.Commit.Hash)
+		}
+	}
+	return info
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
+
+// renderTablePipelines prints aligned columns for terminal reading
+func renderTablePipelines(w io.Writer, pipelines []repoPipelineInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "REPO\tSTATUS\tBUILD\tBRANCH\tCREATED")
+	for _, p := range pipelines {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t#%d\t%s\t%s\n", p.Repo, p.Status, p.BuildNumber, text.Line(p.Branch), p.Created[:10])
+	}
+	return tw.Flush()
+}
+
+func renderMarkdownPipelines(w io.Writer, output pipelinesOutput) error {
+	if len(output.Counts) == 0 {
+		_, _ = fmt.Fprintf(w, "# No pipelines found — %s\n", output.Workspace)
+		return nil
+	}
+
+	statuses := make([]string, 0, len(output.Counts))
+	for status := range output.Counts {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	summary := make([]string, len(statuses))
+	for i, status := range statuses {
+		summary[i] = fmt.Sprintf("%d %s", output.Counts[status], status)
+	}
+
+	_, _ = fmt.Fprintf(w, "# Pipelines — %s\n\n", output.Workspace)
+	_, _ = fmt.Fprintf(w, "Latest per repository: %s\n\n", strings.Join(summary, ", "))
+	if len(output.Pipelines) == 0 {
+		_, _ = fmt.Fprintln(w, "No repositories match the status filter.")
+		return nil
+	}
+	_, _ = fmt.Fprintf(w, "| Repo | Status | Build | Branch | Commit | Created |\n")
+	_, _ = fmt.Fprintf(w, "|------|--------|-------|--------|--------|---------|\n")
+	for _, p := range output.Pipelines {
+		_, _ = fmt.Fprintf(w, "| %s | %s | #%d | %s | %s | %s |\n",
+			p.Repo, p.Status, p.BuildNumber, text.Line(p.Branch), p.Commit, p.Created[:10])
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package list
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+type projectsOptions struct {
+	workspace string
+	json      bool
+	pages     cmdutil.PageFlags
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdProjects creates the list projects command
+func NewCmdProjects(f *cmdutil.Factory) *cobra.Command {
+	opts := &projectsOptions{
+		factory: f,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "projects",
+		Short: "List projects with repository counts",
+		Long: `List the projects in a Bitbucket workspace with how many repositories
+each holds.
+
+One page of projects is fetched at a time, with one count query per
+project: use --page and --per-page to move through them. --all fetches
+every page and counts from one pass over the workspace's repositories.
+
+On a terminal the projects are shown as a table; otherwise, or with
+--json, they are printed as JSON.
+
+Example:
+  bb list projects
+  bb list projects --page 2
+  bb list projects --workspace other-workspace --all --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.pages.Validate(); err != nil {
+				return err
+			}
+			return runListProjects(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.workspace, "workspace", "w", "",
+		"Workspace to list projects from (uses authenticated workspace if not specified)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON even on a terminal")
+	cmdutil.AddPageFlags(cmd, &opts.pages, 30)
+
+	return cmd
+}
+
+type projectInfo struct {
+	Key       string `json:"key"`
+	Name      string `json:"name"`
+	IsPrivate bool   `json:"is_private"`
+	Repos     int    `json:"repos"`
+}
+
+type projectsOutput struct {
+	Projects []projectInfo `json:"projects"`
+	cmdutil.PageInfo
+}
+
+func runListProjects(ctx context.Context, opts *projectsOptions) error {
+	client, err := opts.factory.NewBBCloudClient(opts.workspace)
+	if err != nil {
+		return err
+	}
+
+	var output projectsOutput
+	if opts.pages.All {
+		output.Projects, err = countAllProjectRepos(ctx, client)
+	} else {
+		output.Projects, output.PageInfo, err = countPageProjectRepos(ctx, client, &opts.pages, opts.factory.Concurrency())
+	}
+	if err != nil {
+		return err
+	}
+
+	ios := opts.factory.IOStreams
+	if !opts.json && ios.IsStdoutTTY() {
+		if err := renderTableProjects(ios.Out, output.Projects); err != nil {
+			return err
+		}
+		cmdutil.WriteMoreHint(ios.ErrOut, output.NextPage)
+		return nil
+	}
+
+	if err := cmdutil.WriteJSON(ios.Out, output); err != nil {
+		return fmt.Errorf("encode output: %w", err)
+	}
+	return nil
+}
+
+// countAllProjectRepos lists every project, counting repositories from one
+// pass over the workspace
+func countAllProjectRepos(ctx context.Context, client *bbcloud.Client) ([]projectInfo, error) {
+	var (
+		projects []bbcloud.Project
+		repos    []bbcloud.Repository
+	)
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		projects, err = client.ListProjects(gctx)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		repos, err = client.ListRepositories(gctx, 0)
+		if err != nil {
+			return fmt.Errorf("list repositories: %w", err)
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return countProjectRepos(projects, repos), nil
+}
+
+// countPageProjectRepos lists one page of projects, counting each one's
+// repositories with its own query
+func countPageProjectRepos(ctx context.Context, client *bbcloud.Client, pages *cmdutil.PageFlags, concurrency int) ([]projectInfo, cmdutil.PageInfo, error) {
+	projects, page, err := cmdutil.FetchPages(pages, func(page, perPage int) ([]bbcloud.Project, int, error) {
+		return client.ProjectsPage(ctx, page, perPage)
+	})
+	if err != nil {
+		return nil, cmdutil.PageInfo{}, err
+	}
+
+	output := countProjectRepos(projects, nil)
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+	for i := range output {
+		g.Go(func() error {
+			query := fmt.Sprintf(`project.key = "%s"`, bbcloud.EscapeQueryString(output[i].Key))
+			n, err := client.CountRepositories(gctx, query)
+			if err != nil {
+				return fmt.Errorf("list repositories: %w", err)
+			}
+			output[i].Repos = n
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, cmdutil.PageInfo{}, err
+	}
+	return output, page, nil
+}
+
+// countProjectRepos pairs each project with its number of repositories,
+// sorted by key
+func countProjectRepos(projects []bbcloud.Project, repos []bbcloud.Repository) []projectInfo {
+	counts := make(map[string]int)
+	for _, repo := range repos {
+		if repo.Project != nil {
+			counts[repo.Project.Key]++
+		}
+	}
+
+	output := make([]projectInfo, len(projects))
+	for i, p := range projects {
+		output[i] = projectInfo{
+			Key:       p.Key,
+			Name:      p.Name,
+			IsPrivate: p.IsPrivate,
+			Repos:     counts[p.Key],
+		}
+	}
+	sort.Slice(output, func(i, j int) bool {
+		return output[i].Key < output[j].Key
+	})
+	return output
+}
+
+// renderTableProjects prints aligned columns for terminal reading
+func renderTableProjects(w io.Writer, projects []projectInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "KEY\tNAME\tVISIBILITY\tREPOS")
+	for _, p := range projects {
+		visibility := "public"
+		if p.IsPrivate {
+			visibility = "private"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%d\n", p.Key, text.Line(p.Name), visibility, p.Repos)
+	}
+	return tw.Flush()
+}
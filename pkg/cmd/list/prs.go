@@ -0,0 +1,180 @@
+package list
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+type prsOptions struct {
+	workspace string
+	state     string
+	author    string
+	limit     int
+	json      bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdPRs creates the list prs command
+func NewCmdPRs(f *cmdutil.Factory) *cobra.Command {
+	opts := &prsOptions{
+		factory: f,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "prs",
+		Short: "List pull requests across all repositories",
+		Long: `List pull requests across every repository in a workspace, most recently
+updated first.
+
+Repositories are queried concurrently, so large workspaces cost one API call
+per repository. --author takes "me", a nickname, an account ID or a {uuid}.
+
+Example:
+  bb list prs
+  bb list prs --author me
+  bb list prs --state MERGED --limit 50 --workspace other-workspace`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runListPRs(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.workspace, "workspace", "w", "",
+		"Workspace to list PRs from (uses authenticated workspace if not specified)")
+	cmd.Flags().StringVar(&opts.state, "state", "OPEN", "PR state (OPEN, MERGED, DECLINED)")
+	cmd.Flags().StringVar(&opts.author, "author", "", "Only PRs by this author (\"me\" for yourself)")
+	cmd.Flags().IntVar(&opts.limit, "limit", 50, "Maximum number of PRs to list (0 for all)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of markdown (or a table with format: table)")
+
+	return cmd
+}
+
+type workspacePRInfo struct {
+	Repo    string `json:"repo"`
+	ID      int    `json:"id"`
+	Title   string `json:"title"`
+	Author  string `json:"author"`
+	State   string `json:"state"`
+	Source  string `json:"source"`
+	Target  string `json:"target"`
+	Updated string `json:"updated"`
+}
+
+type prsOutput struct {
+	Workspace string            `json:"workspace"`
+	PRs       []workspacePRInfo `json:"prs"`
+}
+
+func runListPRs(ctx context.Context, opts *prsOptions) error {
+	if opts.limit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+
+	client, err := opts.factory.NewBBCloudClient(opts.workspace)
+	if err != nil {
+		return err
+	}
+
+	listOpts := bbcloud.PRListOptions{State: strings.ToUpper(opts.state), Limit: opts.limit}
+	if opts.author != "" {
+		author := opts.author
+		if author == "me" {
+			user, err := client.CurrentUser(ctx)
+			if err != nil {
+				return err
+			}
+			author = user.UUID
+		}
+		listOpts.Query = bbcloud.UserQueryTerm("author", author)
+	}
+
+	prs, err := client.ListWorkspacePullRequests(ctx, listOpts, opts.factory.Concurrency())
+	if err != nil {
+		return fmt.Errorf("list pull requests: %w", err)
+	}
+
+	output := prsOutput{Workspace: client.Workspace(), PRs: make([]workspacePRInfo, len(prs))}
+	for i, pr := range prs {
+		output.PRs[i] = newWorkspacePRInfo(&pr)
+	}
+
+	ios := opts.factory.IOStreams
+	if opts.json {
+		if err := cmdutil.WriteJSON(ios.Out, output); err != nil {
+			return fmt.Errorf("encode output: %w", err)
+		}
+		return nil
+	}
+
+	if opts.factory.Format() == cmdutil.FormatTable {
+		return renderTablePRs(ios.Out, output.PRs, ios.TerminalWidth())
+	}
+	return renderMarkdownPRs(ios.Out, output)
+}
+
+func newWorkspacePRInfo(pr *bbcloud.PullRequest) workspacePRInfo {
+	info := workspacePRInfo{
+		ID:      pr.ID,
+		Title:   pr.Title,
+		State:   pr.State,
+		Updated: pr.UpdatedOn.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if pr.Author != nil {
+		info.Author = pr.Author.DisplayName
+	}
+	if pr.Source != nil && pr.Source.Branch != nil {
+		info.Source = pr.Source.Branch.Name
+	}
+	if pr.Destination != nil {
+		if pr.Destination.Branch != nil {
+			info.Target = pr.Destination.Branch.Name
+		}
+		if pr.Destination.Repository != nil {
+			info.Repo = pr.Destination.Repository.Slug
+		}
+	}
+	return info
+}
+
+// renderTablePRs prints aligned columns for terminal reading, shortening
+// titles so rows fit in width
+func renderTablePRs(w io.Writer, prs []workspacePRInfo, width int) error {
+	rows := [][]string{{"REPO", "PR", "TITLE", "AUTHOR", "UPDATED"}}
+	for _, pr := range prs {
+		rows = append(rows, []string{pr.Repo, fmt.Sprintf("#%d", pr.ID), text.Line(pr.Title), text.Line(pr.Author), pr.Updated[:10]})
+	}
+	titleWidth := cmdutil.FitColumn(width, rows, 2, 20)
+
+	tw := tabwriter.NewWriter(w, 0, 0, cmdutil.TablePadding, ' ', 0)
+	for _, row := range rows {
+		row[2] = cmdutil.Truncate(row[2], titleWidth)
+		_, _ = fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func renderMarkdownPRs(w io.Writer, output prsOutput) error {
+	if len(output.PRs) == 0 {
+		_, _ = fmt.Fprintf(w, "# No PRs found — %s\n", output.Workspace)
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(w, "# PRs — %s (%d)\n\n", output.Workspace, len(output.PRs))
+	_, _ = fmt.Fprintf(w, "| Repo | PR | Title | Author | Source → Target | Updated |\n")
+	_, _ = fmt.Fprintf(w, "|------|----|-------|--------|-----------------|---------|\n")
+	for _, pr := range output.PRs {
+		_, _ = fmt.Fprintf(w, "| %s | %d | %s | %s | %s → %s | %s |\n",
+			pr.Repo, pr.ID, text.Line(pr.Title), text.Line(pr.Author), text.Line(pr.Source), text.Line(pr.Target), pr.Updated[:10])
+	}
+	return nil
+}
@@ -3,14 +3,36 @@ package list
 import (
 	"context"
 	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
 
+	"github.com/ghoseb/bb/pkg/bbcloud"
 	"github.com/ghoseb/bb/pkg/cmdutil"
 )
 
+// repoRoles and repoSortFields are the values accepted by --role and --sort
+var (
+	repoRoles      = []string{"member", "contributor", "admin", "owner"}
+	repoSortFields = []string{"name", "created_on", "updated_on", "size"}
+)
+
 type reposOptions struct {
 	workspace string
+	query     string
+	language  string
+	project   string
+	role      string
+	sort      string
+	json      bool
+	pages     cmdutil.PageFlags
+
+	withPRs bool
 
 	factory *cmdutil.Factory
 }
@@ -24,19 +46,57 @@ func NewCmdRepos(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "repos",
 		Short: "List repositories in a workspace",
-		Long: `List all repositories in a Bitbucket workspace.
+		Long: `List repositories in a Bitbucket workspace.
+
+On a terminal the repositories are shown as a table; otherwise, or with
+--json, they are printed as JSON.
+
+One page of results is fetched at a time: use --page and --per-page to move
+through them, or --all to fetch every page.
+
+Filters are applied server-side: --language and --project match exactly,
+--role keeps repositories where you are at least member, contributor, admin
+or owner, and --query takes a raw BBQL expression that is ANDed with them.
+--sort orders by name, created_on, updated_on or size; prefix the field with
+"-" for descending order.
+
+--with-prs adds each repository's open pull request count and the status of
+its latest pipeline. This costs two API calls per repository, made
+concurrently (--concurrency).
 
 Example:
   bb list repos
-  bb list repos --workspace other-workspace`,
+  bb list repos --workspace other-workspace
+  bb list repos --language go --project API --sort -updated_on
+  bb list repos --role admin --query 'name ~ "service"'
+  bb list repos --page 2 --per-page 50
+  bb list repos --all --json
+  bb list repos --with-prs --project API`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.pages.Validate(); err != nil {
+				return err
+			}
+			if opts.role != "" && !slices.Contains(repoRoles, opts.role) {
+				return fmt.Errorf("invalid --role %q (use one of %s)", opts.role, strings.Join(repoRoles, ", "))
+			}
+			if opts.sort != "" && !slices.Contains(repoSortFields, strings.TrimPrefix(opts.sort, "-")) {
+				return fmt.Errorf("invalid --sort %q (use one of %s, optionally prefixed with -)", opts.sort, strings.Join(repoSortFields, ", "))
+			}
 			return runListRepos(cmd.Context(), opts)
 		},
 	}
 
 	cmd.Flags().StringVarP(&opts.workspace, "workspace", "w", "", 
 		"Workspace to list repos from (uses authenticated workspace if not specified)")
+	cmd.Flags().StringVar(&opts.query, "query", "", "Raw BBQL filter, ANDed with the other filters")
+	cmd.Flags().StringVar(&opts.language, "language", "", "Only repositories in this language")
+	cmd.Flags().StringVar(&opts.project, "project", "", "Only repositories in the project with this key")
+	cmd.Flags().StringVar(&opts.role, "role", "", "Only repositories where you have this role or higher (member, contributor, admin, owner)")
+	cmd.Flags().StringVar(&opts.sort, "sort", "", "Sort field (name, created_on, updated_on, size); prefix with - for descending")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON even on a terminal")
+	cmd.Flags().BoolVar(&opts.withPRs, "with-prs", false, "Add open PR counts and latest pipeline status")
+	cmdutil.AddPageFlags(cmd, &opts.pages, 30)
 
 	return cmd
 }
@@ -47,6 +107,19 @@ type repoInfo struct {
 	Description string `json:"description,omitempty"`
 	IsPrivate   bool   `json:"is_private"`
 	Language    string `json:"language,omitempty"`
+	OpenPRs     *int   `json:"open_prs,omitempty"` // set with --with-prs
+	Pipeline    string `json:"pipeline,omitempty"` // latest pipeline status, with --with-prs
+}
+
+// repoStats is what --with-prs adds to a repository
+type repoStats struct {
+	OpenPRs  int
+	Pipeline string // status of the latest pipeline; empty without pipelines
+}
+
+type reposOutput struct {
+	Repos []repoInfo `json:"repos"`
+	cmdutil.PageInfo
 }
 
 func runListRepos(ctx context.Context, opts *reposOptions) error {
@@ -56,26 +129,166 @@ func runListRepos(ctx context.Context, opts *reposOptions) error {
 		return err
 	}
 
-	repos, err := client.ListRepositories(ctx, 0)
+	listOpts := bbcloud.RepoListOptions{
+		Query: opts.buildQuery(),
+		Role:  opts.role,
+		Sort:  opts.sort,
+	}
+	repos, page, err := cmdutil.FetchPages(&opts.pages, func(page, perPage int) ([]bbcloud.Repository, int, error) {
+		return client.RepositoriesPage(ctx, listOpts, page, perPage)
+	})
 	if err != nil {
 		return fmt.Errorf("list repositories: %w", err)
 	}
 
+	ios := opts.factory.IOStreams
+	var stats []repoStats
+	if opts.withPRs {
+		if stats, err = fetchRepoStats(ctx, opts, client, repos); err != nil {
+			return err
+		}
+	}
+
+	if !opts.json && ios.IsStdoutTTY() {
+		if err := renderTableRepos(ios.Out, repos, stats); err != nil {
+			return err
+		}
+		cmdutil.WriteMoreHint(ios.ErrOut, page.NextPage)
+		return nil
+	}
+
 	// Convert to output format
-	output := make([]repoInfo, len(repos))
+	output := reposOutput{Repos: make([]repoInfo, len(repos)), PageInfo: page}
 	for i, repo := range repos {
-		output[i] = repoInfo{
+		output.Repos[i] = repoInfo{
 			Name:        repo.Name,
 			Slug:        repo.Slug,
 			Description: repo.Description,
 			IsPrivate:   repo.IsPrivate,
 			Language:    repo.Language,
 		}
+		if stats != nil {
+			output.Repos[i].OpenPRs = &stats[i].OpenPRs
+			output.Repos[i].Pipeline = stats[i].Pipeline
+		}
 	}
 
-	if err := cmdutil.WriteJSON(opts.factory.IOStreams.Out, output); err != nil {
+	if err := cmdutil.WriteJSON(ios.Out, output); err != nil {
 		return fmt.Errorf("encode output: %w", err)
 	}
 
 	return nil
 }
+
+// fetchRepoStats counts each repository's open pull requests and looks up
+// its latest pipeline, showing progress on a terminal. Pipelines may be
+// disabled, so failing to list them only warns.
+func fetchRepoStats(ctx context.Context, opts *reposOptions, client *bbcloud.Client, repos []bbcloud.Repository) ([]repoStats, error) {
+	ios := opts.factory.IOStreams
+	stats := make([]repoStats, len(repos))
+	warnings := make([]error, len(repos))
+
+	var (
+		mu   sync.Mutex
+		done int
+	)
+	progress := func() {
+		if !ios.IsStderrTTY() {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = fmt.Fprintf(ios.ErrOut, "\rFetching pull requests and pipelines: %d/%d", done, len(repos))
+	}
+	progress()
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(opts.factory.Concurrency())
+	for i, repo := range repos {
+		g.Go(func() error {
+			n, err := client.CountPullRequests(gctx, repo.Slug, "OPEN")
+			if err != nil {
+				return fmt.Errorf("%s: %w", repo.Slug, err)
+			}
+			stats[i].OpenPRs = n
+
+			pipelines, err := client.ListPipelines(gctx, repo.Slug, 1)
+			if err != nil {
+				warnings[i] = err
+			} else if len(pipelines) > 0 {
+				stats[i].Pipeline = strings.ToLower(pipelines[0].Status())
+			}
+
+			mu.Lock()
+			done++
+			mu.Unlock()
+			progress()
+			return nil
+		})
+	}
+	err := g.Wait()
+
+	if ios.IsStderrTTY() && len(repos) > 0 {
+		_, _ = fmt.Fprint(ios.ErrOut, "\r\033[K")
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i, w := range warnings {
+		if w != nil {
+			_, _ = fmt.Fprintf(ios.ErrOut, "warning: failed to fetch pipelines for %s: %v\n", repos[i].Slug, w)
+		}
+	}
+	return stats, nil
+}
+
+// renderTableRepos prints aligned columns for terminal reading, with open PR
+// and pipeline columns when stats is not nil
+func renderTableRepos(w io.Writer, repos []bbcloud.Repository, stats []repoStats) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := "NAME\tPROJECT\tVISIBILITY\tLANGUAGE\tUPDATED"
+	if stats != nil {
+		header += "\tOPEN PRS\tPIPELINE"
+	}
+	_, _ = fmt.Fprintln(tw, header)
+	for i, repo := range repos {
+		project := "—"
+		if repo.Project != nil && repo.Project.Key != "" {
+			project = repo.Project.Key
+		}
+		visibility := "public"
+		if repo.IsPrivate {
+			visibility = "private"
+		}
+		language := repo.Language
+		if language == "" {
+			language = "—"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s",
+			repo.Slug, project, visibility, language, repo.UpdatedOn.Format("2006-01-02"))
+		if stats != nil {
+			pipeline := stats[i].Pipeline
+			if pipeline == "" {
+				pipeline = "—"
+			}
+			_, _ = fmt.Fprintf(tw, "\t%d\t%s", stats[i].OpenPRs, pipeline)
+		}
+		_, _ = fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+// buildQuery combines the filter flags into one BBQL expression
+func (opts *reposOptions) buildQuery() string {
+	var terms []string
+	if opts.language != "" {
+		terms = append(terms, fmt.Sprintf(`language = "%s"`, bbcloud.EscapeQueryString(strings.ToLower(opts.language))))
+	}
+	if opts.project != "" {
+		terms = append(terms, fmt.Sprintf(`project.key = "%s"`, bbcloud.EscapeQueryString(strings.ToUpper(opts.project))))
+	}
+	if opts.query != "" {
+		terms = append(terms, "("+opts.query+")")
+	}
+	return strings.Join(terms, " AND ")
+}
@@ -0,0 +1,121 @@
+package list
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+type workspacesOptions struct {
+	json  bool
+	pages cmdutil.PageFlags
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdWorkspaces creates the list workspaces command
+func NewCmdWorkspaces(f *cmdutil.Factory) *cobra.Command {
+	opts := &workspacesOptions{
+		factory: f,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "workspaces",
+		Short: "List workspaces you belong to",
+		Long: `List the workspaces your account belongs to, with your permission in each.
+These are the valid values for --workspace; the one commands currently use
+is marked.
+
+On a terminal the workspaces are shown as a table; otherwise, or with
+--json, they are printed as JSON. Use --page and --per-page to move through
+the results, or --all to fetch every page.
+
+Example:
+  bb list workspaces
+  bb list workspaces --all --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.pages.Validate(); err != nil {
+				return err
+			}
+			return runListWorkspaces(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON even on a terminal")
+	cmdutil.AddPageFlags(cmd, &opts.pages, 30)
+
+	return cmd
+}
+
+type workspaceInfo struct {
+	Slug       string `json:"slug"`
+	Name       string `json:"name"`
+	Permission string `json:"permission"`
+	Current    bool   `json:"current"`
+}
+
+type workspacesOutput struct {
+	Workspaces []workspaceInfo `json:"workspaces"`
+	cmdutil.PageInfo
+}
+
+func runListWorkspaces(ctx context.Context, opts *workspacesOptions) error {
+	client, err := opts.factory.NewBBCloudClient("")
+	if err != nil {
+		return err
+	}
+
+	workspaces, page, err := cmdutil.FetchPages(&opts.pages, func(page, perPage int) ([]bbcloud.WorkspacePermission, int, error) {
+		return client.WorkspacesPage(ctx, page, perPage)
+	})
+	if err != nil {
+		return err
+	}
+
+	output := workspacesOutput{Workspaces: make([]workspaceInfo, len(workspaces)), PageInfo: page}
+	for i, w := range workspaces {
+		output.Workspaces[i] = workspaceInfo{
+			Slug:       w.Workspace.Slug,
+			Name:       w.Workspace.Name,
+			Permission: w.Permission,
+			Current:    w.Workspace.Slug == client.Workspace(),
+		}
+	}
+
+	ios := opts.factory.IOStreams
+	if !opts.json && ios.IsStdoutTTY() {
+		if err := renderTableWorkspaces(ios.Out, output.Workspaces); err != nil {
+			return err
+		}
+		cmdutil.WriteMoreHint(ios.ErrOut, page.NextPage)
+		return nil
+	}
+
+	if err := cmdutil.WriteJSON(ios.Out, output); err != nil {
+		return fmt.Errorf("encode output: %w", err)
+	}
+	return nil
+}
+
+// renderTableWorkspaces prints aligned columns for terminal reading, marking
+// the current workspace with *
+func renderTableWorkspaces(w io.Writer, workspaces []workspaceInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "\tSLUG\tNAME\tPERMISSION")
+	for _, ws := range workspaces {
+		mark := ""
+		if ws.Current {
+			mark = "*"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", mark, ws.Slug, text.Line(ws.Name), ws.Permission)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/httpx"
+)
+
+type serveOptions struct {
+	workspace  string
+	allowWrite bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdMCP creates the mcp command
+func NewCmdMCP(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mcp <command>",
+		Short: "Serve Bitbucket tools to AI agents over the Model Context Protocol",
+	}
+
+	cmd.AddCommand(newCmdServe(f))
+
+	return cmd
+}
+
+func newCmdServe(f *cmdutil.Factory) *cobra.Command {
+	opts := &serveOptions{
+		factory: f,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run an MCP server on stdin and stdout",
+		Long: `Run a Model Context Protocol server on stdin and stdout, for editors and
+agents that launch it as a subprocess. It exposes pull requests, diffs,
+comments, builds, pipelines and repositories as tools, using the same
+credentials, host, profile and default repository as the CLI.
+
+Every tool takes an optional workspace argument (default --workspace or
+the configured workspace) and repository tools an optional repo argument
+(default: default_repo).
+
+Tools are read-only unless --allow-write is given, which adds tools to
+comment on, approve and request changes on pull requests.
+
+Example MCP client configuration:
+  {"mcpServers": {"bitbucket": {"command": "bbc", "args": ["mcp", "serve"]}}}`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.workspace, _ = cmd.Flags().GetString("workspace")
+			return runServe(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.allowWrite, "allow-write", false, "Also expose tools that comment on and review pull requests")
+
+	return cmd
+}
+
+func runServe(ctx context.Context, opts *serveOptions) error {
+	ts := &toolset{factory: opts.factory, workspace: opts.workspace}
+	tools := ts.tools(opts.allowWrite)
+	for i := range tools {
+		// The server outlives any one read: each call shares duplicate reads
+		// within it but not with earlier calls
+		handler := tools[i].handler
+		tools[i].handler = func(ctx context.Context, args arguments) (any, error) {
+			return handler(httpx.WithMemo(ctx), args)
+		}
+	}
+	s := &server{
+		name:    "bb",
+		version: opts.factory.AppVersion,
+		tools:   tools,
+	}
+
+	ios := opts.factory.IOStreams
+	return s.serve(ctx, ios.In, ios.Out)
+}
@@ -0,0 +1,253 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+)
+
+// protocolVersions are the MCP revisions the server speaks, newest first
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// tool is one MCP tool: its schema as listed to clients and the handler run
+// by tools/call. The handler's result is sent back as JSON text; an error is
+// reported to the model as a failed call rather than a protocol error.
+type tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+
+	handler func(ctx context.Context, args arguments) (any, error)
+}
+
+// server answers newline-delimited JSON-RPC 2.0 messages from an MCP client
+type server struct {
+	name    string
+	version string
+	tools   []tool
+
+	mu  sync.Mutex // serializes writes to out
+	out io.Writer
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type textContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type callResult struct {
+	Content []textContent `json:"content"`
+	IsError bool          `json:"isError,omitempty"`
+}
+
+// serve reads requests from in until it is closed or ctx is cancelled. Tool
+// calls run concurrently; every other request is answered in order.
+func (s *server) serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	s.out = out
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			s.reply(nil, nil, &rpcError{Code: codeParseError, Message: "parse error: " + err.Error()})
+			continue
+		}
+		if req.JSONRPC != "2.0" || req.Method == "" {
+			s.reply(req.ID, nil, &rpcError{Code: codeInvalidRequest, Message: "invalid JSON-RPC 2.0 request"})
+			continue
+		}
+		// Notifications (no id) need no answer
+		if len(req.ID) == 0 {
+			continue
+		}
+
+		if req.Method == "tools/call" {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := s.callTool(ctx, req.Params)
+				s.reply(req.ID, result, err)
+			}()
+			continue
+		}
+		result, err := s.handle(req)
+		s.reply(req.ID, result, err)
+	}
+	return scanner.Err()
+}
+
+// handle answers every method except tools/call
+func (s *server) handle(req request) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		version := protocolVersions[0]
+		if slices.Contains(protocolVersions, params.ProtocolVersion) {
+			version = params.ProtocolVersion
+		}
+		return map[string]any{
+			"protocolVersion": version,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]string{"name": s.name, "version": s.version},
+		}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		return map[string]any{"tools": s.tools}, nil
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+}
+
+func (s *server) callTool(ctx context.Context, raw json.RawMessage) (any, *rpcError) {
+	var params struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "invalid params: " + err.Error()}
+	}
+
+	idx := slices.IndexFunc(s.tools, func(t tool) bool { return t.Name == params.Name })
+	if idx < 0 {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "unknown tool: " + params.Name}
+	}
+
+	args := arguments{}
+	if len(params.Arguments) > 0 && string(params.Arguments) != "null" {
+		if err := json.Unmarshal(params.Arguments, &args); err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "invalid arguments: " + err.Error()}
+		}
+	}
+
+	value, err := s.tools[idx].handler(ctx, args)
+	if err != nil {
+		return callResult{Content: []textContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	text, ok := value.(string)
+	if !ok {
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return nil, &rpcError{Code: codeInternalError, Message: "encode result: " + err.Error()}
+		}
+		text = string(data)
+	}
+	return callResult{Content: []textContent{{Type: "text", Text: text}}}, nil
+}
+
+func (s *server) reply(id json.RawMessage, result any, rerr *rpcError) {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	resp := response{JSONRPC: "2.0", ID: id, Result: result}
+	if rerr != nil {
+		resp.Result, resp.Error = nil, rerr
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		data, _ = json.Marshal(response{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: codeInternalError, Message: err.Error()}})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, _ = s.out.Write(append(data, '\n'))
+}
+
+// arguments are a tools/call request's arguments
+type arguments map[string]any
+
+// getString returns the string argument name, or "" when it is absent
+func (a arguments) getString(name string) (string, error) {
+	v, ok := a[name]
+	if !ok || v == nil {
+		return "", nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("argument %q must be a string", name)
+	}
+	return s, nil
+}
+
+// getInt returns the integer argument name, or def when it is absent
+func (a arguments) getInt(name string, def int) (int, error) {
+	v, ok := a[name]
+	if !ok || v == nil {
+		return def, nil
+	}
+	f, ok := v.(float64)
+	if !ok || f != float64(int(f)) {
+		return 0, fmt.Errorf("argument %q must be an integer", name)
+	}
+	return int(f), nil
+}
+
+// requireInt returns the integer argument name, which must be present
+func (a arguments) requireInt(name string) (int, error) {
+	if _, ok := a[name]; !ok {
+		return 0, fmt.Errorf("argument %q is required", name)
+	}
+	return a.getInt(name, 0)
+}
+
+// requireString returns the string argument name, which must be non-empty
+func (a arguments) requireString(name string) (string, error) {
+	s, err := a.getString(name)
+	if err == nil && s == "" {
+		err = fmt.Errorf("argument %q is required", name)
+	}
+	return s, err
+}
+
+// errMissingRepo is returned when a tool needs a repository and neither its
+// arguments nor the configuration name one
+var errMissingRepo = errors.New(`argument "repo" is required (no default_repo is configured)`)
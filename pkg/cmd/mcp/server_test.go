@@ -0,0 +1,133 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestServe(t *testing.T) {
+	s := &server{
+		name:    "bb",
+		version: "test",
+		tools: []tool{{
+			Name:        "echo",
+			InputSchema: map[string]any{"type": "object"},
+			handler: func(ctx context.Context, args arguments) (any, error) {
+				n, err := args.requireInt("n")
+				if err != nil {
+					return nil, err
+				}
+				return map[string]int{"n": n}, nil
+			},
+		}},
+	}
+
+	in := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"echo","arguments":{"n":7}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"echo","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"missing"}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"resources/list"}`,
+		`not json`,
+	}, "\n")
+
+	var out strings.Builder
+	if err := s.serve(context.Background(), strings.NewReader(in), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	// Tool calls are answered concurrently, so index the replies by id
+	replies := make(map[string]map[string]any)
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var resp map[string]any
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatalf("invalid reply %q: %v", line, err)
+		}
+		id, _ := json.Marshal(resp["id"])
+		replies[string(id)] = resp
+	}
+	if len(replies) != 7 {
+		t.Fatalf("got %d replies, want 7 (no reply to the notification):\n%s", len(replies), out.String())
+	}
+
+	result := func(id string) map[string]any {
+		r, _ := replies[id]["result"].(map[string]any)
+		return r
+	}
+	errorCode := func(id string) float64 {
+		e, _ := replies[id]["error"].(map[string]any)
+		code, _ := e["code"].(float64)
+		return code
+	}
+
+	if v := result("1")["protocolVersion"]; v != "2025-03-26" {
+		t.Errorf("initialize protocolVersion = %v, want the client's 2025-03-26", v)
+	}
+	if tools, _ := result("2")["tools"].([]any); len(tools) != 1 {
+		t.Errorf("tools/list = %v, want 1 tool", result("2"))
+	}
+
+	content, _ := result("3")["content"].([]any)
+	if len(content) != 1 || !strings.Contains(content[0].(map[string]any)["text"].(string), `"n": 7`) || result("3")["isError"] != nil {
+		t.Errorf("tools/call echo = %v", result("3"))
+	}
+	if result("4")["isError"] != true {
+		t.Errorf("tools/call without n = %v, want isError", result("4"))
+	}
+
+	if code := errorCode("5"); code != codeInvalidParams {
+		t.Errorf("unknown tool error code = %v, want %d", code, codeInvalidParams)
+	}
+	if code := errorCode("6"); code != codeMethodNotFound {
+		t.Errorf("unknown method error code = %v, want %d", code, codeMethodNotFound)
+	}
+	if code := errorCode("null"); code != codeParseError {
+		t.Errorf("parse error code = %v, want %d", code, codeParseError)
+	}
+}
+
+func TestArguments(t *testing.T) {
+	args := arguments{"s": "x", "n": float64(3), "f": 1.5}
+
+	if s, err := args.getString("s"); err != nil || s != "x" {
+		t.Errorf("getString(s) = %q, %v", s, err)
+	}
+	if _, err := args.getString("n"); err == nil {
+		t.Error("getString(n) accepted a number")
+	}
+	if n, err := args.getInt("missing", 9); err != nil || n != 9 {
+		t.Errorf("getInt(missing) = %d, %v, want default 9", n, err)
+	}
+	if _, err := args.getInt("f", 0); err == nil {
+		t.Error("getInt(f) accepted 1.5")
+	}
+	if _, err := args.requireString("missing"); err == nil {
+		t.Error("requireString(missing) accepted a missing argument")
+	}
+}
+
+func TestToolsAllowWrite(t *testing.T) {
+	ts := &toolset{}
+	names := func(tools []tool) map[string]bool {
+		m := make(map[string]bool)
+		for _, tl := range tools {
+			m[tl.Name] = true
+		}
+		return m
+	}
+
+	read := names(ts.tools(false))
+	if read["approve_pull_request"] || read["comment_pull_request"] {
+		t.Error("write tools exposed without --allow-write")
+	}
+	if !read["get_pull_request"] || !read["list_pipelines"] {
+		t.Errorf("read tools = %v", read)
+	}
+	if write := names(ts.tools(true)); !write["approve_pull_request"] || !write["comment_pull_request"] {
+		t.Errorf("tools with --allow-write = %v", write)
+	}
+}
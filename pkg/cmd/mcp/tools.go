@@ -0,0 +1,422 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// toolset builds each tool call's API client from the factory, so calls share
+// the CLI's credentials, host and profile
+type toolset struct {
+	factory   *cmdutil.Factory
+	workspace string // --workspace, used when a call names none
+}
+
+// client returns an API client for the call's workspace argument, or the
+// default workspace
+func (t *toolset) client(args arguments) (*bbcloud.Client, error) {
+	ws, err := args.getString("workspace")
+	if err != nil {
+		return nil, err
+	}
+	if ws == "" {
+		ws = t.workspace
+	}
+	return t.factory.NewBBCloudClient(ws)
+}
+
+// repo returns the call's repo argument, falling back to the workspace's
+// default repo and then default_repo like the --repo flag
+func (t *toolset) repo(args arguments, client *bbcloud.Client) (string, error) {
+	repo, err := args.getString("repo")
+	if err != nil || repo != "" {
+		return repo, err
+	}
+	cfg, err := t.factory.Config()
+	if err != nil {
+		return "", err
+	}
+	if repo = cfg.WorkspaceRepo(client.Workspace()); repo == "" {
+		repo = cfg.Repo()
+	}
+	if repo == "" {
+		return "", errMissingRepo
+	}
+	return repo, nil
+}
+
+// pr resolves the client, repository and id argument of a pull request tool
+func (t *toolset) pr(args arguments) (*bbcloud.Client, string, int, error) {
+	client, err := t.client(args)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	repo, err := t.repo(args, client)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	id, err := args.requireInt("id")
+	if err != nil {
+		return nil, "", 0, err
+	}
+	return client, repo, id, nil
+}
+
+// Schema properties shared by the tools
+var (
+	workspaceProp = map[string]any{"type": "string", "description": "Workspace slug (default: the configured workspace)"}
+	repoProp      = map[string]any{"type": "string", "description": "Repository slug (default: default_repo)"}
+	idProp        = map[string]any{"type": "integer", "description": "Pull request ID"}
+)
+
+// schema returns an object schema with the given properties; workspace is
+// always accepted
+func schema(props map[string]any, required ...string) map[string]any {
+	props["workspace"] = workspaceProp
+	s := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// prSchema is the schema of a tool acting on one pull request
+func prSchema(extra map[string]any, required ...string) map[string]any {
+	props := map[string]any{"repo": repoProp, "id": idProp}
+	for k, v := range extra {
+		props[k] = v
+	}
+	return schema(props, append([]string{"id"}, required...)...)
+}
+
+// tools returns the read tools and, with allowWrite, the tools that comment
+// on and review pull requests
+func (t *toolset) tools(allowWrite bool) []tool {
+	tools := []tool{
+		{
+			Name:        "list_workspaces",
+			Description: "List the workspaces you belong to, with your permission in each.",
+			InputSchema: map[string]any{"type": "object", "properties": map[string]any{}},
+			handler: func(ctx context.Context, args arguments) (any, error) {
+				client, err := t.factory.NewBBCloudClient(t.workspace)
+				if err != nil {
+					return nil, err
+				}
+				return client.ListWorkspaces(ctx)
+			},
+		},
+		{
+			Name:        "list_repositories",
+			Description: "List one page of the repositories in a workspace. next_page is 0 on the last page.",
+			InputSchema: schema(map[string]any{
+				"query":    map[string]any{"type": "string", "description": `BBQL filter, e.g. language = "go"`},
+				"sort":     map[string]any{"type": "string", "description": "Sort field (name, created_on, updated_on, size); prefix with - for descending"},
+				"page":     map[string]any{"type": "integer", "description": "Page number (default 1)"},
+				"per_page": map[string]any{"type": "integer", "description": "Results per page (default 30, max 100)"},
+			}),
+			handler: func(ctx context.Context, args arguments) (any, error) {
+				client, err := t.client(args)
+				if err != nil {
+					return nil, err
+				}
+				var opts bbcloud.RepoListOptions
+				if opts.Query, err = args.getString("query"); err != nil {
+					return nil, err
+				}
+				if opts.Sort, err = args.getString("sort"); err != nil {
+					return nil, err
+				}
+				page, err := args.getInt("page", 1)
+				if err != nil {
+					return nil, err
+				}
+				perPage, err := args.getInt("per_page", 30)
+				if err != nil {
+					return nil, err
+				}
+				if page < 1 || perPage < 1 || perPage > 100 {
+					return nil, fmt.Errorf("page must be at least 1 and per_page between 1 and 100")
+				}
+				repos, next, err := client.RepositoriesPage(ctx, opts, page, perPage)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{"repositories": repos, "page": page, "next_page": next}, nil
+			},
+		},
+		{
+			Name:        "list_pull_requests",
+			Description: "List a repository's pull requests, most recently updated first.",
+			InputSchema: schema(map[string]any{
+				"repo":  repoProp,
+				"state": map[string]any{"type": "string", "enum": []string{"OPEN", "MERGED", "DECLINED"}, "description": "State (default OPEN)"},
+				"query": map[string]any{"type": "string", "description": `BBQL filter, e.g. author.nickname = "alice"`},
+				"limit": map[string]any{"type": "integer", "description": "Maximum pull requests (default 25)"},
+			}),
+			handler: func(ctx context.Context, args arguments) (any, error) {
+				client, err := t.client(args)
+				if err != nil {
+					return nil, err
+				}
+				repo, err := t.repo(args, client)
+				if err != nil {
+					return nil, err
+				}
+				opts, err := prListOptions(args)
+				if err != nil {
+					return nil, err
+				}
+				return client.QueryPullRequests(ctx, repo, opts)
+			},
+		},
+		{
+			Name:        "list_workspace_pull_requests",
+			Description: "List pull requests across every repository in a workspace, most recently updated first.",
+			InputSchema: schema(map[string]any{
+				"state": map[string]any{"type": "string", "enum": []string{"OPEN", "MERGED", "DECLINED"}, "description": "State (default OPEN)"},
+				"query": map[string]any{"type": "string", "description": "BBQL filter applied in every repository"},
+				"limit": map[string]any{"type": "integer", "description": "Maximum pull requests (default 25)"},
+			}),
+			handler: func(ctx context.Context, args arguments) (any, error) {
+				client, err := t.client(args)
+				if err != nil {
+					return nil, err
+				}
+				opts, err := prListOptions(args)
+				if err != nil {
+					return nil, err
+				}
+				return client.ListWorkspacePullRequests(ctx, opts, t.factory.Concurrency())
+			},
+		},
+		{
+			Name:        "get_pull_request",
+			Description: "Get a pull request's metadata, reviewers and changed files with line counts.",
+			InputSchema: prSchema(map[string]any{}),
+			handler: func(ctx context.Context, args arguments) (any, error) {
+				client, repo, id, err := t.pr(args)
+				if err != nil {
+					return nil, err
+				}
+				pr, err := client.GetPullRequest(ctx, repo, id)
+				if err != nil {
+					return nil, err
+				}
+				files, err := client.GetPRDiffStats(ctx, repo, id)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{"pull_request": pr, "files": files}, nil
+			},
+		},
+		{
+			Name:        "get_pull_request_diff",
+			Description: "Get a pull request's unified diff, or the diff of one file.",
+			InputSchema: prSchema(map[string]any{
+				"path":    map[string]any{"type": "string", "description": "Only this file's diff"},
+				"context": map[string]any{"type": "integer", "description": "Unchanged lines around each change (default 3)"},
+			}),
+			handler: func(ctx context.Context, args arguments) (any, error) {
+				client, repo, id, err := t.pr(args)
+				if err != nil {
+					return nil, err
+				}
+				path, err := args.getString("path")
+				if err != nil {
+					return nil, err
+				}
+				var opts bbcloud.DiffOptions
+				if _, ok := args["context"]; ok {
+					n, err := args.getInt("context", 3)
+					if err != nil {
+						return nil, err
+					}
+					opts.Context = &n
+				}
+				if path != "" {
+					return client.GetPRFileDiff(ctx, repo, id, path, opts)
+				}
+				return client.GetPRDiff(ctx, repo, id, opts)
+			},
+		},
+		{
+			Name:        "list_pull_request_comments",
+			Description: "List a pull request's general and inline comments.",
+			InputSchema: prSchema(map[string]any{}),
+			handler: func(ctx context.Context, args arguments) (any, error) {
+				client, repo, id, err := t.pr(args)
+				if err != nil {
+					return nil, err
+				}
+				return client.ListPRComments(ctx, repo, id)
+			},
+		},
+		{
+			Name:        "list_pull_request_tasks",
+			Description: "List a pull request's tasks.",
+			InputSchema: prSchema(map[string]any{}),
+			handler: func(ctx context.Context, args arguments) (any, error) {
+				client, repo, id, err := t.pr(args)
+				if err != nil {
+					return nil, err
+				}
+				return client.ListPRTasks(ctx, repo, id)
+			},
+		},
+		{
+			Name:        "get_pull_request_builds",
+			Description: "Get the build statuses reported on a pull request's source commit.",
+			InputSchema: prSchema(map[string]any{}),
+			handler: func(ctx context.Context, args arguments) (any, error) {
+				client, repo, id, err := t.pr(args)
+				if err != nil {
+					return nil, err
+				}
+				return client.GetPRPipelines(ctx, repo, id)
+			},
+		},
+		{
+			Name:        "list_pipelines",
+			Description: "List a repository's pipelines, newest first.",
+			InputSchema: schema(map[string]any{
+				"repo":  repoProp,
+				"limit": map[string]any{"type": "integer", "description": "Maximum pipelines (default 10)"},
+			}),
+			handler: func(ctx context.Context, args arguments) (any, error) {
+				client, err := t.client(args)
+				if err != nil {
+					return nil, err
+				}
+				repo, err := t.repo(args, client)
+				if err != nil {
+					return nil, err
+				}
+				limit, err := args.getInt("limit", 10)
+				if err != nil {
+					return nil, err
+				}
+				if limit < 1 {
+					return nil, fmt.Errorf("limit must be at least 1")
+				}
+				return client.ListPipelines(ctx, repo, limit)
+			},
+		},
+		{
+			Name:        "get_pipeline",
+			Description: "Get one pipeline's status by UUID.",
+			InputSchema: schema(map[string]any{
+				"repo": repoProp,
+				"uuid": map[string]any{"type": "string", "description": "Pipeline UUID, with braces"},
+			}, "uuid"),
+			handler: func(ctx context.Context, args arguments) (any, error) {
+				client, err := t.client(args)
+				if err != nil {
+					return nil, err
+				}
+				repo, err := t.repo(args, client)
+				if err != nil {
+					return nil, err
+				}
+				uuid, err := args.requireString("uuid")
+				if err != nil {
+					return nil, err
+				}
+				return client.GetPipelineStatus(ctx, repo, uuid)
+			},
+		},
+	}
+	if !allowWrite {
+		return tools
+	}
+
+	return append(tools,
+		tool{
+			Name:        "comment_pull_request",
+			Description: "Post a comment on a pull request: a general comment, or an inline one on a line (or line range) of a file in the new version.",
+			InputSchema: prSchema(map[string]any{
+				"body":       map[string]any{"type": "string", "description": "Comment text (markdown)"},
+				"path":       map[string]any{"type": "string", "description": "File to comment on; omit for a general comment"},
+				"line":       map[string]any{"type": "integer", "description": "Line to comment on (the last line of a range)"},
+				"start_line": map[string]any{"type": "integer", "description": "First line of a range"},
+			}, "body"),
+			handler: func(ctx context.Context, args arguments) (any, error) {
+				client, repo, id, err := t.pr(args)
+				if err != nil {
+					return nil, err
+				}
+				body, err := args.requireString("body")
+				if err != nil {
+					return nil, err
+				}
+				path, err := args.getString("path")
+				if err != nil {
+					return nil, err
+				}
+				if path == "" {
+					return client.CreateComment(ctx, repo, id, body)
+				}
+				line, err := args.requireInt("line")
+				if err != nil {
+					return nil, err
+				}
+				start, err := args.getInt("start_line", 0)
+				if err != nil {
+					return nil, err
+				}
+				return client.CreateInlineComment(ctx, repo, id, body, path, start, line)
+			},
+		},
+		tool{
+			Name:        "approve_pull_request",
+			Description: "Approve a pull request as the authenticated user.",
+			InputSchema: prSchema(map[string]any{}),
+			handler: func(ctx context.Context, args arguments) (any, error) {
+				client, repo, id, err := t.pr(args)
+				if err != nil {
+					return nil, err
+				}
+				return client.ApprovePR(ctx, repo, id)
+			},
+		},
+		tool{
+			Name:        "request_changes_pull_request",
+			Description: "Request changes on a pull request as the authenticated user.",
+			InputSchema: prSchema(map[string]any{}),
+			handler: func(ctx context.Context, args arguments) (any, error) {
+				client, repo, id, err := t.pr(args)
+				if err != nil {
+					return nil, err
+				}
+				return client.RequestChangesPR(ctx, repo, id)
+			},
+		},
+	)
+}
+
+// prListOptions reads the state, query and limit arguments of the pull
+// request list tools
+func prListOptions(args arguments) (bbcloud.PRListOptions, error) {
+	state, err := args.getString("state")
+	if err != nil {
+		return bbcloud.PRListOptions{}, err
+	}
+	if state == "" {
+		state = "OPEN"
+	}
+	query, err := args.getString("query")
+	if err != nil {
+		return bbcloud.PRListOptions{}, err
+	}
+	limit, err := args.getInt("limit", 25)
+	if err != nil {
+		return bbcloud.PRListOptions{}, err
+	}
+	if limit < 1 {
+		return bbcloud.PRListOptions{}, fmt.Errorf("limit must be at least 1")
+	}
+	return bbcloud.PRListOptions{State: strings.ToUpper(state), Query: query, Limit: limit}, nil
+}
@@ -0,0 +1,288 @@
+package release
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/httpx"
+)
+
+// commitHash matches a full or abbreviated commit hash
+var commitHash = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+type createOptions struct {
+	repo      string
+	tag       string
+	target    string
+	notes     string
+	notesFile string
+	attach    []string
+	json      bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdCreate creates the release create command
+func NewCmdCreate(f *cmdutil.Factory) *cobra.Command {
+	opts := &createOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "create <tag>",
+		Short: "Tag a commit and upload its artifacts",
+		Long: `Create a release: tag --target (a branch or commit, by default the main
+branch) with the release notes as the tag message, upload the --attach files
+to the repository's downloads, and print a summary with their links.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+--attach takes files or glob patterns and can be repeated. Every file is
+checked before the tag is created; downloads with the same name are
+replaced.
+
+Examples:
+  bbc release create v1.3.0 --repo api --notes-file notes.md --attach 'dist/*'
+  bbc changelog --repo api --since v1.2.0 | bbc release create v1.3.0 --repo api --notes-file -
+  bbc release create v1.3.1 --repo api --target 4f2a9c1 --notes "Hotfix for login"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.tag = args[0]
+			if opts.notes != "" && opts.notesFile != "" {
+				return fmt.Errorf("--notes and --notes-file cannot be used together")
+			}
+			return runCreate(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+	cmd.Flags().StringVar(&opts.target, "target", "", "Branch or commit to tag (default: the repository's main branch)")
+	cmd.Flags().StringVar(&opts.notes, "notes", "", "Release notes")
+	cmd.Flags().StringVar(&opts.notesFile, "notes-file", "", "File with the release notes (- for stdin)")
+	cmd.Flags().StringArrayVar(&opts.attach, "attach", nil, "File or glob to upload to the downloads (repeatable)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of markdown")
+
+	return cmd
+}
+
+type download struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	URL  string `json:"url,omitempty"`
+
+	path string
+}
+
+type createOutput struct {
+	Tag       string     `json:"tag"`
+	Commit    string     `json:"commit"`
+	Target    string     `json:"target"`
+	Notes     string     `json:"notes,omitempty"`
+	Downloads []download `json:"downloads"`
+}
+
+func runCreate(ctx context.Context, opts *createOptions) error {
+	ios := opts.factory.IOStreams
+
+	notes := opts.notes
+	if opts.notesFile != "" {
+		data, err := readNotes(opts.notesFile, ios.In)
+		if err != nil {
+			return err
+		}
+		notes = string(data)
+	}
+	notes = strings.TrimSpace(notes)
+
+	// Check the artifacts before anything is created
+	files, err := expandAttachments(opts.attach)
+	if err != nil {
+		return err
+	}
+
+	client, err := opts.factory.NewBBCloudClient("")
+	if err != nil {
+		return err
+	}
+	repo, err := client.GetRepository(ctx, opts.repo)
+	if err != nil {
+		return err
+	}
+
+	target := opts.target
+	if target == "" {
+		if repo.MainBranch == nil || repo.MainBranch.Name == "" {
+			return fmt.Errorf("repository %s has no main branch; pass --target", opts.repo)
+		}
+		target = repo.MainBranch.Name
+	}
+	hash, err := resolveTarget(ctx, client, opts.repo, target)
+	if err != nil {
+		return err
+	}
+
+	// Under --dry-run every request is printed rather than sent, and stdout
+	// holds only them
+	dryRun := false
+	tag, err := client.CreateTag(ctx, opts.repo, opts.tag, hash, notes)
+	switch {
+	case errors.Is(err, httpx.ErrDryRun):
+		dryRun = true
+	case err != nil:
+		return err
+	case tag.Target != nil && tag.Target.Hash != "":
+		hash = tag.Target.Hash
+	}
+
+	output := createOutput{Tag: opts.tag, Commit: hash, Target: target, Notes: notes, Downloads: []download{}}
+	for i, file := range files {
+		err := upload(ctx, client, opts.repo, file)
+		if errors.Is(err, httpx.ErrDryRun) {
+			dryRun = true
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("tag %s created, %d of %d files uploaded: %w", opts.tag, i, len(files), err)
+		}
+		if repo.Links.HTML != nil {
+			file.URL = strings.TrimSuffix(repo.Links.HTML.Href, "/") + "/downloads/" + file.Name
+		}
+		output.Downloads = append(output.Downloads, file)
+	}
+	if dryRun {
+		return httpx.ErrDryRun
+	}
+
+	if opts.json {
+		return cmdutil.WriteJSON(ios.Out, output)
+	}
+	renderMarkdown(ios.Out, output)
+	return nil
+}
+
+// resolveTarget returns the commit a branch points at; a target that is no
+// branch but looks like a commit hash is returned as is
+func resolveTarget(ctx context.Context, client *bbcloud.Client, repo, target string) (string, error) {
+	branch, err := client.GetBranch(ctx, repo, target)
+	if err == nil && branch.Target != nil && branch.Target.Hash != "" {
+		return branch.Target.Hash, nil
+	}
+	if commitHash.MatchString(target) {
+		return target, nil
+	}
+	if err == nil {
+		err = fmt.Errorf("branch %s has no head commit", target)
+	}
+	return "", fmt.Errorf("--target %s: %w", target, err)
+}
+
+// expandAttachments resolves files and glob patterns to the files to upload
+func expandAttachments(patterns []string) ([]download, error) {
+	var files []download
+	seen := make(map[string]string)
+	for _, pattern := range patterns {
+		paths := []string{pattern}
+		if strings.ContainsAny(pattern, "*?[") {
+			matches, err := filepath.Glob(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("--attach %s: %w", pattern, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("--attach %s matches no files", pattern)
+			}
+			paths = matches
+		}
+		for _, path := range paths {
+			info, err := os.Stat(path)
+			if err != nil {
+				return nil, fmt.Errorf("--attach: %w", err)
+			}
+			if !info.Mode().IsRegular() {
+				return nil, fmt.Errorf("--attach %s is not a regular file", path)
+			}
+			name := filepath.Base(path)
+			if prev, ok := seen[name]; ok {
+				if prev == path {
+					continue
+				}
+				return nil, fmt.Errorf("--attach %s and %s would both be uploaded as %s", prev, path, name)
+			}
+			seen[name] = path
+			files = append(files, download{Name: name, Size: info.Size(), path: path})
+		}
+	}
+	return files, nil
+}
+
+func upload(ctx context.Context, client *bbcloud.Client, repo string, file download) error {
+	data, err := os.ReadFile(file.path)
+	if err != nil {
+		return err
+	}
+	return client.UploadDownload(ctx, repo, file.Name, bytes.NewReader(data))
+}
+
+// readNotes reads a file, or stdin for "-"
+func readNotes(name string, stdin io.Reader) ([]byte, error) {
+	if name == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("read stdin: %w", err)
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", name, err)
+	}
+	return data, nil
+}
+
+func renderMarkdown(w io.Writer, output createOutput) {
+	_, _ = fmt.Fprintf(w, "## Release %s\n\n", output.Tag)
+	_, _ = fmt.Fprintf(w, "Tagged %s (%s)\n", shortHash(output.Commit), output.Target)
+	if len(output.Downloads) > 0 {
+		_, _ = fmt.Fprintln(w, "\n### Downloads")
+		_, _ = fmt.Fprintln(w)
+		for _, d := range output.Downloads {
+			if d.URL != "" {
+				_, _ = fmt.Fprintf(w, "- [%s](%s) (%s)\n", d.Name, d.URL, formatSize(d.Size))
+			} else {
+				_, _ = fmt.Fprintf(w, "- %s (%s)\n", d.Name, formatSize(d.Size))
+			}
+		}
+	}
+	if output.Notes != "" {
+		_, _ = fmt.Fprintf(w, "\n### Notes\n\n%s\n", output.Notes)
+	}
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
+
+// formatSize renders a byte count with a binary unit
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
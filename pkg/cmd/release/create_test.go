@@ -0,0 +1,100 @@
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/bbtest"
+)
+
+func TestExpandAttachments(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"app-linux.tar.gz", "app-darwin.tar.gz", "checksums.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := expandAttachments([]string{filepath.Join(dir, "*.tar.gz"), filepath.Join(dir, "checksums.txt")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name)
+	}
+	if got := strings.Join(names, " "); got != "app-darwin.tar.gz app-linux.tar.gz checksums.txt" {
+		t.Errorf("files = %s", got)
+	}
+
+	for _, bad := range []string{filepath.Join(dir, "*.zip"), filepath.Join(dir, "missing"), filepath.Join(dir, "sub")} {
+		if _, err := expandAttachments([]string{bad}); err == nil {
+			t.Errorf("--attach %s accepted", bad)
+		}
+	}
+}
+
+func TestResolveTarget(t *testing.T) {
+	srv := bbtest.NewServer(t)
+	client := srv.Client(t, "acme")
+	srv.Handle(http.MethodGet, "/repositories/acme/api/refs/branches/main", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(bbcloud.Branch{Name: "main", Target: &bbcloud.CommitReference{Hash: "4f2a9c1d"}})
+	})
+
+	ctx := context.Background()
+	if hash, err := resolveTarget(ctx, client, "api", "main"); err != nil || hash != "4f2a9c1d" {
+		t.Errorf("branch = %q, %v", hash, err)
+	}
+	if hash, err := resolveTarget(ctx, client, "api", "abc1234"); err != nil || hash != "abc1234" {
+		t.Errorf("commit = %q, %v", hash, err)
+	}
+	if _, err := resolveTarget(ctx, client, "api", "develop"); err == nil {
+		t.Error("unknown branch accepted")
+	}
+}
+
+func TestUpload(t *testing.T) {
+	srv := bbtest.NewServer(t)
+	client := srv.Client(t, "acme")
+	var got string
+	srv.Handle(http.MethodPost, "/repositories/acme/api/downloads", func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("files")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data := make([]byte, header.Size)
+		_, _ = file.Read(data)
+		got = header.Filename + ":" + string(data)
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	path := filepath.Join(t.TempDir(), "app.tar.gz")
+	if err := os.WriteFile(path, []byte("payload"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := upload(context.Background(), client, "api", download{Name: "app.tar.gz", path: path}); err != nil {
+		t.Fatal(err)
+	}
+	if got != "app.tar.gz:payload" {
+		t.Errorf("uploaded %q", got)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	for n, want := range map[int64]string{512: "512 B", 1536: "1.5 KiB", 5 << 20: "5.0 MiB"} {
+		if got := formatSize(n); got != want {
+			t.Errorf("formatSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package release
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// NewCmdRelease creates the release command group
+func NewCmdRelease(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "release <command>",
+		Short: "Publish releases as tags and downloads",
+		Long: `Bitbucket has no release objects, so a release here is an annotated tag
+carrying the release notes plus artifacts in the repository's downloads.`,
+	}
+
+	cmd.AddCommand(NewCmdCreate(f))
+
+	return cmd
+}
@@ -0,0 +1,245 @@
+package repo
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+type cloneOptions struct {
+	repo     string
+	dir      string
+	protocol string
+	all      bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdClone creates the repo clone command
+func NewCmdClone(f *cmdutil.Factory) *cobra.Command {
+	opts := &cloneOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "clone [<repo> [<directory>]]",
+		Short: "Clone a repository locally",
+		Long: `Clone a repository from the workspace.
+
+The repository is resolved via the API. HTTPS clones authenticate with the
+stored credentials without writing them to the cloned repository's config.
+
+Use --all to clone every repository in the workspace concurrently into the
+target directory (default: current directory). Existing directories are skipped.
+The command exits 1 when any repository failed to clone.
+
+Examples:
+  # Clone over HTTPS
+  bbc repo clone test_repo
+
+  # Clone over SSH into a specific directory
+  bbc repo clone test_repo ~/src/test_repo --protocol ssh
+
+  # Clone a repository from another workspace
+  bbc repo clone other-workspace/test_repo
+
+  # Clone the whole workspace
+  bbc repo clone --all ~/src/workspace`,
+		Args: cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Fall back to the configured protocol when --protocol is not given
+			if !cmd.Flags().Changed("protocol") {
+				cfg, err := opts.factory.Config()
+				if err != nil {
+					return err
+				}
+				opts.protocol = cfg.GitProtocol()
+			}
+			if opts.protocol != "https" && opts.protocol != "ssh" {
+				return &cmdutil.ValidationError{Field: "protocol", Msg: "must be https or ssh"}
+			}
+
+			workspace, _ := cmd.Flags().GetString("workspace")
+
+			if opts.all {
+				if len(args) > 1 {
+					return fmt.Errorf("--all accepts at most one argument (target directory)")
+				}
+				if len(args) == 1 {
+					opts.dir = args[0]
+				}
+				return runCloneAll(cmd.Context(), opts, workspace)
+			}
+
+			if len(args) == 0 {
+				return fmt.Errorf("repository is required (or use --all)")
+			}
+			opts.repo = args[0]
+			if len(args) > 1 {
+				opts.dir = args[1]
+			}
+
+			// Accept workspace/repo to clone from another workspace
+			if ws, slug, ok := strings.Cut(opts.repo, "/"); ok {
+				workspace, opts.repo = ws, slug
+			}
+
+			return runClone(cmd.Context(), opts, workspace)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.protocol, "protocol", "p", "https", "Clone protocol (https or ssh; default from git_protocol config)")
+	cmd.Flags().BoolVar(&opts.all, "all", false, "Clone every repository in the workspace")
+
+	return cmd
+}
+
+type cloneResult struct {
+	Repo   string `json:"repo"`
+	Dir    string `json:"dir"`
+	Status string `json:"status"` // cloned, skipped, failed
+	Error  string `json:"error,omitempty"`
+}
+
+func runClone(ctx context.Context, opts *cloneOptions, workspace string) error {
+	client, err := opts.factory.NewBBCloudClient(workspace)
+	if err != nil {
+		return err
+	}
+
+	repo, err := client.GetRepository(ctx, opts.repo)
+	if err != nil {
+		return err
+	}
+
+	dir := opts.dir
+	if dir == "" {
+		dir = repo.Slug
+	}
+
+	if err := cloneRepo(ctx, opts, repo, dir); err != nil {
+		return err
+	}
+
+	return cmdutil.WriteJSON(opts.factory.IOStreams.Out, cloneResult{
+		Repo:   repo.FullName,
+		Dir:    dir,
+		Status: "cloned",
+	})
+}
+
+func runCloneAll(ctx context.Context, opts *cloneOptions, workspace string) error {
+	client, err := opts.factory.NewBBCloudClient(workspace)
+	if err != nil {
+		return err
+	}
+	return cloneAll(ctx, opts, client)
+}
+
+// cloneAll clones every repository of the client's workspace, printing a
+// result per repository; it fails with exit code 1 when any clone failed
+func cloneAll(ctx context.Context, opts *cloneOptions, client *bbcloud.Client) error {
+	repos, err := client.ListRepositories(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("list repositories: %w", err)
+	}
+
+	results := make([]cloneResult, len(repos))
+
+	// Clone with at most cloneWorkers running at once; each worker writes
+	// only its own results
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(cloneWorkers, len(repos)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = cloneOne(ctx, opts, &repos[i])
+			}
+		}()
+	}
+	for i := range repos {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	if err := cmdutil.WriteJSON(opts.factory.IOStreams.Out, results); err != nil {
+		return err
+	}
+	for _, r := range results {
+		if r.Status == "failed" {
+			return cmdutil.NewExitError(1, "")
+		}
+	}
+	return nil
+}
+
+// cloneWorkers is the number of repositories clone --all clones at once
+const cloneWorkers = 5
+
+// cloneOne clones repo into its directory under opts.dir, skipping it when
+// the directory exists. A failure is recorded, not returned, so the other
+// clones carry on.
+func cloneOne(ctx context.Context, opts *cloneOptions, repo *bbcloud.Repository) cloneResult {
+	dir := filepath.Join(opts.dir, repo.Slug)
+	result := cloneResult{Repo: repo.FullName, Dir: dir, Status: "cloned"}
+	if _, err := os.Stat(dir); err == nil {
+		result.Status = "skipped"
+	} else if err := cloneRepo(ctx, opts, repo, dir); err != nil {
+		result.Status = "failed"
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// cloneRepo clones repo into dir using the configured protocol
+func cloneRepo(ctx context.Context, opts *cloneOptions, repo *bbcloud.Repository, dir string) error {
+	cloneURL := repo.CloneURL(opts.protocol)
+	if cloneURL == "" {
+		return fmt.Errorf("repository %s has no %s clone URL", repo.FullName, opts.protocol)
+	}
+
+	header := ""
+	if opts.protocol == "https" {
+		creds, err := opts.factory.GetCredentials()
+		if err != nil {
+			return err
+		}
+		host, err := opts.factory.Host()
+		if err != nil {
+			return err
+		}
+		cloneURL = stripUserinfo(cloneURL)
+		if host.Auth == bbcloud.AuthBearer {
+			header = "Authorization: Bearer " + creds.Token
+		} else {
+			auth := base64.StdEncoding.EncodeToString([]byte(creds.Username + ":" + creds.Token))
+			header = "Authorization: Basic " + auth
+		}
+	}
+
+	if err := opts.factory.GitClient.Clone(ctx, cloneURL, dir, header); err != nil {
+		return fmt.Errorf("clone %s: %w", repo.FullName, err)
+	}
+	return nil
+}
+
+// stripUserinfo removes the username Bitbucket embeds in HTTPS clone links
+func stripUserinfo(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	u.User = nil
+	return u.String()
+}
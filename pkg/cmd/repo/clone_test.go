@@ -0,0 +1,71 @@
+package repo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/bbtest"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/iostreams"
+)
+
+func TestStripUserinfo(t *testing.T) {
+	got := stripUserinfo("https://alice@bitbucket.org/ws/repo.git")
+	if want := "https://bitbucket.org/ws/repo.git"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestCloneCommandFlags(t *testing.T) {
+	ios := iostreams.System()
+	factory := cmdutil.NewFactory("test", ios)
+
+	cmd := NewCmdClone(factory)
+
+	if cmd.Flags().Lookup("all") == nil {
+		t.Error("expected --all flag")
+	}
+	protocol := cmd.Flags().Lookup("protocol")
+	if protocol == nil {
+		t.Fatal("expected --protocol flag")
+	}
+	if protocol.DefValue != "https" {
+		t.Errorf("expected --protocol default https, got %q", protocol.DefValue)
+	}
+}
+
+func TestCloneAllReportsFailures(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	srv := bbtest.NewServer(t)
+	// Without clone links every clone fails before git runs
+	srv.AddRepository("acme", bbcloud.Repository{Slug: "api", FullName: "acme/api"})
+	srv.AddRepository("acme", bbcloud.Repository{Slug: "web", FullName: "acme/web"})
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "web"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+	f := cmdutil.NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: out, ErrOut: &bytes.Buffer{}})
+	opts := &cloneOptions{dir: dir, protocol: "ssh", all: true, factory: f}
+	err := cloneAll(context.Background(), opts, srv.Client(t, "acme"))
+
+	var exitErr *cmdutil.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 1 {
+		t.Fatalf("err = %v, want exit code 1", err)
+	}
+	var results []cloneResult
+	if err := json.Unmarshal(out.Bytes(), &results); err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].Status != "failed" || results[1].Status != "skipped" {
+		t.Errorf("results = %+v, want api failed and web skipped", results)
+	}
+}
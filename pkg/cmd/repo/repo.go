@@ -0,0 +1,23 @@
+package repo
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// NewCmdRepo creates the repo command group
+func NewCmdRepo(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "repo <command>",
+		Short: "Work with repositories",
+		Long: `Work with Bitbucket repositories.
+
+To list repositories, use:
+  bb list repos`,
+	}
+
+	cmd.AddCommand(NewCmdClone(f))
+
+	return cmd
+}
@@ -0,0 +1,280 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+type activityOptions struct {
+	repo     string
+	prNumber int
+	json     bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdActivity creates the review activity command
+func NewCmdActivity(f *cmdutil.Factory) *cobra.Command {
+	opts := &activityOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "activity <pr-number>",
+		Short: "Show the PR activity timeline",
+		Long: `Show a chronological timeline of a pull request: when it was opened,
+pushes, title/description/target/state changes, approvals, change requests
+and comments.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+Pushes are derived from changes to the source commit between updates.
+
+Examples:
+  bbc review activity 450 --repo test_repo
+  bbc review activity 450 --repo test_repo --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Initialize client
+			client, err := opts.factory.NewBBCloudClient("")
+			if err != nil {
+				return err
+			}
+
+			// Parse PR number
+			prNum, err := parsePRNumber(args[0])
+			if err != nil {
+				return err
+			}
+			opts.prNumber = prNum
+
+			return runActivity(cmd.Context(), opts, client)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of markdown")
+
+	return cmd
+}
+
+type activityEvent struct {
+	Time      string `json:"time"`
+	Type      string `json:"type"` // opened, pushed, retitled, described, retargeted, state, approved, changes_requested, comment
+	Author    string `json:"author,omitempty"`
+	Detail    string `json:"detail,omitempty"`
+	CommentID int    `json:"comment_id,omitempty"`
+	ParentID  int    `json:"parent_id,omitempty"`
+	File      string `json:"file,omitempty"`
+	Line      int    `json:"line,omitempty"`
+
+	at time.Time
+}
+
+type activityOutput struct {
+	PR     int             `json:"pr"`
+	Events []activityEvent `json:"events"`
+}
+
+func runActivity(ctx context.Context, opts *activityOptions, client *bbcloud.Client) error {
+	activities, err := client.GetPRActivity(ctx, opts.repo, opts.prNumber)
+	if err != nil {
+		return fmt.Errorf("get activity: %w", err)
+	}
+
+	// Decode mentions in the comments that appear in the timeline
+	var comments []bbcloud.Comment
+	for _, a := range activities {
+		if a.Comment != nil {
+			comments = append(comments, *a.Comment)
+		}
+	}
+	comments = readableMentions(ctx, opts.factory, client, comments)
+	for i, j := 0, 0; i < len(activities); i++ {
+		if activities[i].Comment != nil {
+			activities[i].Comment = &comments[j]
+			j++
+		}
+	}
+
+	output := activityOutput{PR: opts.prNumber, Events: buildTimeline(activities)}
+
+	ios, _ := opts.factory.Streams()
+	if opts.json {
+		if err := cmdutil.WriteJSON(ios.Out, output); err != nil {
+			return fmt.Errorf("encode output: %w", err)
+		}
+		return nil
+	}
+
+	return renderMarkdownActivity(ios.Out, output)
+}
+
+// buildTimeline turns activity items (returned newest first) into events in
+// chronological order. Updates are snapshots of the PR, so each is compared
+// with the previous one to describe what changed.
+func buildTimeline(activities []bbcloud.Activity) []activityEvent {
+	var updates []*bbcloud.ActivityUpdate
+	events := make([]activityEvent, 0, len(activities))
+
+	for _, a := range activities {
+		switch {
+		case a.Update != nil:
+			updates = append(updates, a.Update)
+		case a.Approval != nil:
+			events = append(events, newActivityEvent(a.Approval.Date, "approved", a.Approval.User, ""))
+		case a.ChangesRequested != nil:
+			events = append(events, newActivityEvent(a.ChangesRequested.Date, "changes_requested", a.ChangesRequested.User, ""))
+		case a.Comment != nil && !a.Comment.Deleted:
+			events = append(events, newCommentEvent(a.Comment))
+		}
+	}
+
+	sort.SliceStable(updates, func(i, j int) bool { return updates[i].Date.Before(updates[j].Date) })
+	var prev *bbcloud.ActivityUpdate
+	for _, u := range updates {
+		events = append(events, updateEvents(prev, u)...)
+		prev = u
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].at.Before(events[j].at) })
+	return events
+}
+
+// updateEvents describes the changes from prev to u; a nil prev is the
+// update that opened the PR
+func updateEvents(prev, u *bbcloud.ActivityUpdate) []activityEvent {
+	if prev == nil {
+		return []activityEvent{newActivityEvent(u.Date, "opened", u.Author, u.Title)}
+	}
+
+	var events []activityEvent
+	if hash := commitHash(u.Source); hash != "" && hash != commitHash(prev.Source) {
+		events = append(events, newActivityEvent(u.Date, "pushed", u.Author, shortHash(hash)))
+	}
+	if u.Title != prev.Title {
+		events = append(events, newActivityEvent(u.Date, "retitled", u.Author, u.Title))
+	}
+	if u.Description != prev.Description {
+		events = append(events, newActivityEvent(u.Date, "described", u.Author, ""))
+	}
+	if branch := branchName(u.Destination); branch != "" && branch != branchName(prev.Destination) {
+		events = append(events, newActivityEvent(u.Date, "retargeted", u.Author, branch))
+	}
+	if u.State != "" && u.State != prev.State {
+		events = append(events, newActivityEvent(u.Date, "state", u.Author, u.State))
+	}
+	return events
+}
+
+func newActivityEvent(at time.Time, kind string, user *bbcloud.User, detail string) activityEvent {
+	e := activityEvent{
+		Time:   at.Format("2006-01-02T15:04:05Z07:00"),
+		Type:   kind,
+		Detail: detail,
+		at:     at,
+	}
+	if user != nil {
+		e.Author = user.DisplayName
+	}
+	return e
+}
+
+func newCommentEvent(c *bbcloud.Comment) activityEvent {
+	text := ""
+	if c.Content != nil {
+		text = c.Content.Raw
+	}
+	e := newActivityEvent(c.CreatedOn, "comment", c.User, text)
+	e.CommentID = c.ID
+	if c.Parent != nil {
+		e.ParentID = c.Parent.ID
+	}
+	if c.Inline != nil {
+		e.File = c.Inline.Path
+		if c.Inline.To != nil {
+			e.Line = *c.Inline.To
+		}
+	}
+	return e
+}
+
+func commitHash(b *bbcloud.PullRequestBranch) string {
+	if b == nil || b.Commit == nil {
+		return ""
+	}
+	return b.Commit.Hash
+}
+
+func branchName(b *bbcloud.PullRequestBranch) string {
+	if b == nil || b.Branch == nil {
+		return ""
+	}
+	return b.Branch.Name
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
+
+func renderMarkdownActivity(w io.Writer, output activityOutput) error {
+	_, _ = fmt.Fprintf(w, "# PR %d activity — %d events\n\n", output.PR, len(output.Events))
+
+	for _, e := range output.Events {
+		writeActivityEvent(w, e)
+	}
+	return nil
+}
+
+// writeActivityEvent writes one timeline entry as a markdown list item
+func writeActivityEvent(w io.Writer, e activityEvent) {
+	author := text.Line(e.Author)
+	if author == "" {
+		author = "someone"
+	}
+	_, _ = fmt.Fprintf(w, "- %s **%s** %s\n", e.at.Format("2006-01-02 15:04"), author, describeEvent(e))
+}
+
+// describeEvent returns the markdown phrase for an event, after its author
+func describeEvent(e activityEvent) string {
+	detail := text.Line(e.Detail)
+	switch e.Type {
+	case "opened":
+		return fmt.Sprintf("opened the PR: %s", detail)
+	case "pushed":
+		return fmt.Sprintf("pushed `%s`", detail)
+	case "retitled":
+		return fmt.Sprintf("changed the title to: %s", detail)
+	case "described":
+		return "edited the description"
+	case "retargeted":
+		return fmt.Sprintf("changed the target to `%s`", detail)
+	case "state":
+		return fmt.Sprintf("set the state to %s", detail)
+	case "approved":
+		return "approved"
+	case "changes_requested":
+		return "requested changes"
+	}
+
+	verb := "commented"
+	if e.ParentID != 0 {
+		verb = fmt.Sprintf("replied to comment:%d", e.ParentID)
+	}
+	if e.File != "" {
+		verb += fmt.Sprintf(" on %s:%d", text.Line(e.File), e.Line)
+	}
+	firstLine, _, _ := strings.Cut(text.Sanitize(unescapeBBMarkdown(e.Detail)), "\n")
+	return fmt.Sprintf("%s (comment:%d): %s", verb, e.CommentID, cmdutil.Truncate(firstLine, 100))
+}
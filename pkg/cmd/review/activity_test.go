@@ -0,0 +1,64 @@
+package review
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+)
+
+func TestBuildTimeline(t *testing.T) {
+	alice := &bbcloud.User{DisplayName: "Alice"}
+	bob := &bbcloud.User{DisplayName: "Bob"}
+	t0 := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	branch := func(name, hash string) *bbcloud.PullRequestBranch {
+		return &bbcloud.PullRequestBranch{Branch: &bbcloud.Branch{Name: name}, Commit: &bbcloud.CommitReference{Hash: hash}}
+	}
+	line := 12
+
+	// The API returns newest first
+	activities := []bbcloud.Activity{
+		{Approval: &bbcloud.ActivityApproval{Date: t0.Add(4 * time.Hour), User: bob}},
+		{Update: &bbcloud.ActivityUpdate{Date: t0.Add(3 * time.Hour), Author: alice, State: "OPEN", Title: "Fix login",
+			Source: branch("feature", "bbbbbbbbbbbbbbbb"), Destination: branch("main", "")}},
+		{Comment: &bbcloud.Comment{ID: 7, CreatedOn: t0.Add(2 * time.Hour), User: bob,
+			Content: &bbcloud.Content{Raw: "Typo here\nand more"}, Inline: &bbcloud.InlineLocation{Path: "a.go", To: &line}}},
+		{Comment: &bbcloud.Comment{ID: 8, CreatedOn: t0.Add(90 * time.Minute), User: bob, Deleted: true}},
+		{Update: &bbcloud.ActivityUpdate{Date: t0, Author: alice, State: "OPEN", Title: "Fix logn",
+			Source: branch("feature", "aaaaaaaaaaaaaaaa"), Destination: branch("main", "")}},
+	}
+
+	events := buildTimeline(activities)
+	var types []string
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	if got, want := strings.Join(types, ","), "opened,comment,pushed,retitled,approved"; got != want {
+		t.Fatalf("event types = %s, want %s", got, want)
+	}
+	if events[2].Detail != "bbbbbbbbbbbb" {
+		t.Errorf("pushed detail = %q, want short hash", events[2].Detail)
+	}
+	if events[1].File != "a.go" || events[1].Line != 12 || events[1].CommentID != 7 {
+		t.Errorf("comment event = %+v", events[1])
+	}
+
+	var buf bytes.Buffer
+	if err := renderMarkdownActivity(&buf, activityOutput{PR: 450, Events: events}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"# PR 450 activity — 5 events",
+		"- 2026-01-02 10:00 **Alice** opened the PR: Fix logn",
+		"**Bob** commented on a.go:12 (comment:7): Typo here\n",
+		"**Alice** pushed `bbbbbbbbbbbb`",
+		"**Bob** approved",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -2,18 +2,22 @@ package review
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ghoseb/bb/pkg/bbcloud"
 	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/httpx"
 )
 
 type approveOptions struct {
 	repo     string
 	prNumber int
 	undo     bool
+	message  string
 
 	factory *cmdutil.Factory
 }
@@ -27,9 +31,12 @@ func NewCmdApprove(f *cmdutil.Factory) *cobra.Command {
 		Short: "Approve a pull request",
 		Long: `Approve a pull request.
 
-Requires --repo flag to specify the repository.
+Requires --repo flag (or a default_repo setting) to specify the repository.
 
-To add a comment with your approval, use bb review comment separately.
+Use --message to post a general comment with your approval: the comment is
+posted first and the PR approved once it is, and the output reports the
+comment's ID. If approving then fails, the output names the posted comment so
+the command can be retried or the comment deleted.
 
 Use --undo to remove your approval.
 
@@ -37,14 +44,17 @@ Examples:
   # Approve PR
   bbc review approve 450 --repo test_repo
 
-  # Remove approval
-  bbc review approve 450 --repo test_repo --undo
+  # Approve with a comment
+  bbc review approve 450 --repo test_repo -m "LGTM, nice tests"
 
-  # Approve and comment (two commands)
-  bbc review approve 450 --repo test_repo
-  bbc review comment 450 --repo test_repo "LGTM! Ship it."`,
+  # Remove approval
+  bbc review approve 450 --repo test_repo --undo`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateReviewMessage(cmd, opts.message, opts.undo); err != nil {
+				return err
+			}
+
 			// Initialize client
 			client, err := opts.factory.NewBBCloudClient("")
 			if err != nil {
@@ -62,40 +72,85 @@ Examples:
 		},
 	}
 
-	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (required)")
-	_ = cmd.MarkFlagRequired("repo")
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
 	cmd.Flags().BoolVar(&opts.undo, "undo", false, "Remove approval instead of approving")
+	cmd.Flags().StringVarP(&opts.message, "message", "m", "", "General comment posted with the approval")
 
 	return cmd
 }
 
-func friendlyError(errMsg string) string {
+// friendlyError maps the error of a PR action to a short reason for the
+// structured output, by the API error's status and then its message
+func friendlyError(err error) string {
+	var apiErr *bbcloud.Error
+	switch {
+	case errors.Is(err, bbcloud.ErrNotFound):
+		return "PR not found"
+	case !errors.As(err, &apiErr):
+		return err.Error()
+	}
+
+	msg := apiErr.ErrorInfo.Message
 	switch {
-	case strings.Contains(errMsg, "already been merged"):
+	case strings.Contains(msg, "already been merged"):
 		return "PR is already merged"
-	case strings.Contains(errMsg, "already been declined"):
+	case strings.Contains(msg, "already been declined"):
 		return "PR is already declined"
-	case strings.Contains(errMsg, "haven't approved"):
+	case strings.Contains(msg, "haven't approved"):
 		return "no approval to remove"
-	case strings.Contains(errMsg, "Request changes"):
+	case strings.Contains(msg, "Request changes"):
 		return "no request-change to remove"
-	case strings.Contains(errMsg, "not found"):
-		return "PR not found"
 	default:
-		return errMsg
+		return err.Error()
 	}
 }
 
+// validateReviewMessage checks the --message of approve and request-change
+func validateReviewMessage(cmd *cobra.Command, message string, undo bool) error {
+	if !cmd.Flags().Changed("message") {
+		return nil
+	}
+	if undo {
+		return fmt.Errorf("--message cannot be combined with --undo")
+	}
+	if strings.TrimSpace(message) == "" {
+		return fmt.Errorf("--message cannot be empty")
+	}
+	return nil
+}
+
+// postReviewComment posts the general comment that goes with a review
+// decision and returns its ID. It is posted before the decision is set, so
+// the decision never stands without it.
+func postReviewComment(ctx context.Context, f *cmdutil.Factory, client *bbcloud.Client, repo string, pr int, message string) (int, error) {
+	comment, err := client.CreateComment(ctx, repo, pr, resolveMentions(ctx, f, client, message))
+	if err != nil {
+		return 0, err
+	}
+	return comment.ID, nil
+}
+
+// partialReview tells how to finish or undo a review whose comment was
+// posted but whose decision (the review subcommand) failed
+func partialReview(failed, subcommand string, pr int, repo string, commentID int) string {
+	return fmt.Sprintf("comment %d was posted but %s; retry with bbc review %s %d --repo %s, "+
+		"or remove the comment with bbc review comment %d --repo %s --delete %d",
+		commentID, failed, subcommand, pr, repo, pr, repo, commentID)
+}
+
 func runApprove(ctx context.Context, opts *approveOptions, client *bbcloud.Client) error {
 	if opts.undo {
 		// Remove approval
 		err := client.UnapprovePR(ctx, opts.repo, opts.prNumber)
+		if errors.Is(err, httpx.ErrDryRun) {
+			return err
+		}
 		if err != nil {
 			output := map[string]interface{}{
 				"pr":     opts.prNumber,
 				"repo":   opts.repo,
 				"action": "unapprove",
-				"error":  friendlyError(err.Error()),
+				"error":  friendlyError(err),
 			}
 			
 			return cmdutil.WriteJSON(opts.factory.IOStreams.Out, output)
@@ -110,14 +165,39 @@ func runApprove(ctx context.Context, opts *approveOptions, client *bbcloud.Clien
 		return cmdutil.WriteJSON(opts.factory.IOStreams.Out, output)
 	}
 
+	var commentID int
+	if opts.message != "" {
+		// Under --dry-run the comment was printed; go on to print the decision
+		id, err := postReviewComment(ctx, opts.factory, client, opts.repo, opts.prNumber, opts.message)
+		if err != nil && !errors.Is(err, httpx.ErrDryRun) {
+			output := map[string]interface{}{
+				"pr":     opts.prNumber,
+				"repo":   opts.repo,
+				"action": "approve",
+				"error":  "comment not posted, PR not approved: " + friendlyError(err),
+			}
+
+			return cmdutil.WriteJSON(opts.factory.IOStreams.Out, output)
+		}
+		commentID = id
+	}
+
 	// Approve PR
 	participant, err := client.ApprovePR(ctx, opts.repo, opts.prNumber)
+	if errors.Is(err, httpx.ErrDryRun) {
+		// Stdout holds only the printed requests
+		return err
+	}
 	if err != nil {
 		output := map[string]interface{}{
 			"pr":     opts.prNumber,
 			"repo":   opts.repo,
 			"action": "approve",
-			"error":  friendlyError(err.Error()),
+			"error":  friendlyError(err),
+		}
+		if commentID != 0 {
+			output["comment_id"] = commentID
+			output["partial"] = partialReview("the PR was not approved", "approve", opts.prNumber, opts.repo, commentID)
 		}
 		
 		return cmdutil.WriteJSON(opts.factory.IOStreams.Out, output)
@@ -131,6 +211,9 @@ func runApprove(ctx context.Context, opts *approveOptions, client *bbcloud.Clien
 		"state":    participant.State,
 	}
 
+	if commentID != 0 {
+		output["comment_id"] = commentID
+	}
 	if participant.User != nil {
 		output["user"] = participant.User.GetName()
 	}
@@ -0,0 +1,119 @@
+package review
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/bbtest"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/httpx"
+	"github.com/ghoseb/bb/pkg/iostreams"
+)
+
+func TestApproveWithMessage(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	srv := bbtest.NewServer(t)
+	srv.AddRepository("acme", bbcloud.Repository{Slug: "api"})
+	srv.AddPullRequest("acme", "api", bbcloud.PullRequest{Title: "Add auth"})
+	client := srv.Client(t, "acme")
+
+	run := func() map[string]any {
+		t.Helper()
+		out := &bytes.Buffer{}
+		f := cmdutil.NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: out, ErrOut: &bytes.Buffer{}})
+		opts := &approveOptions{repo: "api", prNumber: 1, message: "LGTM, nice tests", factory: f}
+		if err := runApprove(context.Background(), opts, client); err != nil {
+			t.Fatal(err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	got := run()
+	comments := srv.Comments("acme", "api", 1)
+	if got["action"] != "approved" || got["approved"] != true || len(comments) != 1 || got["comment_id"] != float64(comments[0].ID) {
+		t.Fatalf("output = %v, comments = %d", got, len(comments))
+	}
+	if comments[0].Content.Raw != "LGTM, nice tests" {
+		t.Errorf("comment = %q", comments[0].Content.Raw)
+	}
+
+	srv.Handle(http.MethodPost, "/repositories/acme/api/pullrequests/1/approve", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"type": "error", "error": {"message": "This pull request has already been declined."}}`))
+	})
+	got = run()
+	comments = srv.Comments("acme", "api", 1)
+	if got["error"] != "PR is already declined" || got["comment_id"] != float64(comments[1].ID) {
+		t.Fatalf("output = %v", got)
+	}
+	if partial, _ := got["partial"].(string); !strings.Contains(partial, "bbc review approve 1 --repo api") {
+		t.Errorf("partial = %q, want how to retry", partial)
+	}
+}
+
+func TestApproveDryRun(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	srv := bbtest.NewServer(t)
+	srv.AddRepository("acme", bbcloud.Repository{Slug: "api"})
+	srv.AddPullRequest("acme", "api", bbcloud.PullRequest{Title: "Add auth"})
+
+	out := &bytes.Buffer{}
+	client, err := bbcloud.New(bbcloud.Options{BaseURL: srv.BaseURL(), Workspace: "acme", Username: "alice", Token: "test-token", DryRun: out})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := cmdutil.NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: out, ErrOut: &bytes.Buffer{}})
+	f.DryRun = true
+	opts := &approveOptions{repo: "api", prNumber: 1, message: "LGTM", factory: f}
+	if err := runApprove(context.Background(), opts, client); !errors.Is(err, httpx.ErrDryRun) {
+		t.Fatalf("err = %v, want ErrDryRun", err)
+	}
+
+	// Both requests are printed, and nothing else
+	var urls []string
+	dec := json.NewDecoder(out)
+	for dec.More() {
+		var req map[string]any
+		if err := dec.Decode(&req); err != nil {
+			t.Fatalf("output is not a stream of requests: %v\n%s", err, out)
+		}
+		if req["dry_run"] != true {
+			t.Errorf("unexpected document %v", req)
+		}
+		url, _ := req["url"].(string)
+		urls = append(urls, url)
+	}
+	if len(urls) != 2 || !strings.HasSuffix(urls[0], "/pullrequests/1/comments") || !strings.HasSuffix(urls[1], "/pullrequests/1/approve") {
+		t.Errorf("printed requests = %v, want the comment then the approval", urls)
+	}
+	if comments := srv.Comments("acme", "api", 1); len(comments) != 0 {
+		t.Errorf("dry run posted %d comments", len(comments))
+	}
+}
+
+func TestValidateReviewMessage(t *testing.T) {
+	f := cmdutil.NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}})
+	for _, args := range [][]string{
+		{"1", "-m", " "},
+		{"1", "-m", "why", "--undo"},
+	} {
+		cmd := NewCmdApprove(f)
+		cmd.SetArgs(args)
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--message") {
+			t.Errorf("approve %v = %v, want a --message error", args, err)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package review
+
+import (
+	"strings"
+
+	"github.com/ghoseb/bb/pkg/iostreams"
+	"github.com/ghoseb/bb/pkg/markdown"
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+// bodyFormatter formats PR descriptions and comment bodies for markdown
+// output. The zero value leaves them as raw markdown; on a terminal they are
+// rendered with styled headings, lists and code blocks. Either way, terminal
+// control sequences in the untrusted body are removed.
+type bodyFormatter struct {
+	styled bool
+	width  int
+	color  bool
+}
+
+// newBodyFormatter renders bodies when stdout is a terminal, unless raw is set
+func newBodyFormatter(ios *iostreams.IOStreams, raw bool) bodyFormatter {
+	if raw || !ios.IsStdoutTTY() {
+		return bodyFormatter{}
+	}
+	return bodyFormatter{styled: true, width: ios.TerminalWidth(), color: ios.ColorEnabled()}
+}
+
+// description formats a PR description as a block of its own
+func (b bodyFormatter) description(s string) string {
+	s = text.Sanitize(unescapeBBMarkdown(s))
+	if !b.styled {
+		return s
+	}
+	return markdown.Render(s, b.width, b.color)
+}
+
+// comment formats a comment body following its header line. A rendered body
+// of more than one line starts on the next line, indented under indent.
+func (b bodyFormatter) comment(s, indent string) string {
+	s = text.Sanitize(unescapeBBMarkdown(s))
+	if !b.styled {
+		return s
+	}
+	indent += "  "
+	rendered := markdown.Render(s, b.width-len(indent), b.color)
+	if !strings.Contains(rendered, "\n") {
+		return rendered
+	}
+	lines := strings.Split(rendered, "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = indent + l
+		}
+	}
+	return "\n" + strings.Join(lines, "\n")
+}
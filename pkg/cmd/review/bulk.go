@@ -0,0 +1,223 @@
+package review
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/httpx"
+)
+
+type bulkOptions struct {
+	repo string
+
+	factory *cmdutil.Factory
+}
+
+// bulkResult reports the outcome for one PR
+type bulkResult struct {
+	PR        int    `json:"pr"`
+	Action    string `json:"action,omitempty"`
+	CommentID int    `json:"comment_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type bulkOutput struct {
+	Repo      string       `json:"repo"`
+	Results   []bulkResult `json:"results"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+}
+
+// NewCmdBulk creates the review bulk command group
+func NewCmdBulk(f *cmdutil.Factory) *cobra.Command {
+	opts := &bulkOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "bulk <command>",
+		Short: "Apply a review action to many pull requests",
+		Long: `Apply one review action to a list of pull requests, e.g. to sweep
+dependency-bot PRs.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+PR numbers are given as arguments, or read from stdin (whitespace or newline
+separated) when there are none or the only argument is "-". PRs are processed
+concurrently (--concurrency) and each gets its own result; a failure on one PR
+does not stop the others.
+
+Examples:
+  # Approve several PRs
+  bbc review bulk approve 12 15 19 --repo test_repo
+
+  # Decline stale PRs from a script (declining asks for confirmation)
+  bbc review bulk decline 7 8 --repo test_repo --yes
+
+  # Comment on every open PR by a bot
+  bbc review list --repo test_repo --author renovate-bot --json | jq '.prs[].id' |
+    bbc review bulk comment --repo test_repo --body "Batch-reviewed, merging after CI"`,
+	}
+
+	cmd.PersistentFlags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+
+	cmd.AddCommand(newCmdBulkApprove(opts))
+	cmd.AddCommand(newCmdBulkComment(opts))
+	cmd.AddCommand(newCmdBulkDecline(opts))
+
+	return cmd
+}
+
+// bulkAction acts on one PR
+type bulkAction func(ctx context.Context, pr int) bulkResult
+
+func newCmdBulkApprove(opts *bulkOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "approve [pr-number...]",
+		Short: "Approve several pull requests",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBulk(cmd.Context(), opts, args, func(_ context.Context, client *bbcloud.Client) bulkAction {
+				return func(ctx context.Context, pr int) bulkResult {
+					if _, err := client.ApprovePR(ctx, opts.repo, pr); err != nil {
+						return bulkResult{PR: pr, Error: friendlyError(err)}
+					}
+					return bulkResult{PR: pr, Action: "approved"}
+				}
+			}, "")
+		},
+	}
+}
+
+func newCmdBulkDecline(opts *bulkOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "decline [pr-number...]",
+		Short: "Decline several pull requests",
+		Long: `Decline several pull requests. Declined PRs cannot be reopened, so this
+asks for confirmation first; pass --yes when stdin is not a terminal.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBulk(cmd.Context(), opts, args, func(_ context.Context, client *bbcloud.Client) bulkAction {
+				return func(ctx context.Context, pr int) bulkResult {
+					if _, err := client.DeclinePR(ctx, opts.repo, pr); err != nil {
+						return bulkResult{PR: pr, Error: friendlyError(err)}
+					}
+					return bulkResult{PR: pr, Action: "declined"}
+				}
+			}, "Decline %d pull request(s) in %s?")
+		},
+	}
+}
+
+func newCmdBulkComment(opts *bulkOptions) *cobra.Command {
+	var body string
+
+	cmd := &cobra.Command{
+		Use:   "comment [pr-number...]",
+		Short: "Post the same comment on several pull requests",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(body) == "" {
+				return fmt.Errorf("--body is required")
+			}
+			return runBulk(cmd.Context(), opts, args, func(ctx context.Context, client *bbcloud.Client) bulkAction {
+				// Mentions are resolved once rather than per PR
+				message := resolveMentions(ctx, opts.factory, client, body)
+				return func(ctx context.Context, pr int) bulkResult {
+					comment, err := client.CreateComment(ctx, opts.repo, pr, message)
+					if err != nil {
+						return bulkResult{PR: pr, Error: friendlyError(err)}
+					}
+					return bulkResult{PR: pr, Action: "commented", CommentID: comment.ID}
+				}
+			}, "")
+		},
+	}
+
+	cmd.Flags().StringVarP(&body, "body", "b", "", "Comment text")
+
+	return cmd
+}
+
+// runBulk applies the action built by prepare to each PR with bounded
+// concurrency and writes the results in input order. A non-empty confirm
+// format (given the PR count and repo) asks for confirmation first.
+func runBulk(ctx context.Context, opts *bulkOptions, args []string, prepare func(context.Context, *bbcloud.Client) bulkAction, confirm string) error {
+	ios, _ := opts.factory.Streams()
+	prs, err := bulkPRNumbers(args, ios.In)
+	if err != nil {
+		return err
+	}
+
+	if confirm != "" {
+		if err := opts.factory.Confirm(fmt.Sprintf(confirm, len(prs), opts.repo)); err != nil {
+			return err
+		}
+	}
+
+	client, err := opts.factory.NewBBCloudClient("")
+	if err != nil {
+		return err
+	}
+	action := prepare(ctx, client)
+
+	output := bulkOutput{Repo: opts.repo, Results: make([]bulkResult, len(prs))}
+	var g errgroup.Group
+	g.SetLimit(opts.factory.Concurrency())
+	for i, pr := range prs {
+		g.Go(func() error {
+			output.Results[i] = action(ctx, pr)
+			return nil
+		})
+	}
+	_ = g.Wait()
+	if opts.factory.DryRun {
+		// The requests were printed instead of sent; stdout holds only them
+		return httpx.ErrDryRun
+	}
+
+	for _, r := range output.Results {
+		if r.Error != "" {
+			output.Failed++
+		} else {
+			output.Succeeded++
+		}
+	}
+	return cmdutil.WriteJSON(ios.Out, output)
+}
+
+// bulkPRNumbers parses PR numbers from args, or from stdin when args is empty
+// or "-". Duplicates are dropped so no PR is acted on twice.
+func bulkPRNumbers(args []string, stdin io.Reader) ([]int, error) {
+	if len(args) == 0 || (len(args) == 1 && args[0] == "-") {
+		args = nil
+		scanner := bufio.NewScanner(stdin)
+		scanner.Split(bufio.ScanWords)
+		for scanner.Scan() {
+			args = append(args, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("read PR numbers: %w", err)
+		}
+	}
+
+	seen := make(map[int]bool)
+	var prs []int
+	for _, arg := range args {
+		pr, err := parsePRNumber(strings.TrimPrefix(arg, "#"))
+		if err != nil {
+			return nil, err
+		}
+		if !seen[pr] {
+			seen[pr] = true
+			prs = append(prs, pr)
+		}
+	}
+	if len(prs) == 0 {
+		return nil, fmt.Errorf("no PR numbers given (pass them as arguments or on stdin)")
+	}
+	return prs, nil
+}
@@ -0,0 +1,26 @@
+package review
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBulkPRNumbers(t *testing.T) {
+	got, err := bulkPRNumbers([]string{"12", "#15", "12"}, nil)
+	if err != nil || !reflect.DeepEqual(got, []int{12, 15}) {
+		t.Errorf("args: got %v, %v; want [12 15]", got, err)
+	}
+
+	got, err = bulkPRNumbers([]string{"-"}, strings.NewReader("19\n20 21\n"))
+	if err != nil || !reflect.DeepEqual(got, []int{19, 20, 21}) {
+		t.Errorf("stdin: got %v, %v; want [19 20 21]", got, err)
+	}
+
+	if _, err := bulkPRNumbers(nil, strings.NewReader("")); err == nil {
+		t.Error("expected error for empty input")
+	}
+	if _, err := bulkPRNumbers([]string{"12", "abc"}, nil); err == nil {
+		t.Error("expected error for invalid PR number")
+	}
+}
@@ -0,0 +1,45 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// reviewChecklist returns the configured review_checklist items, from .bb.yml
+// or user config
+func reviewChecklist(f *cmdutil.Factory) ([]string, error) {
+	cfg, err := f.Config()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.ReviewChecklist(), nil
+}
+
+// formatChecklist renders items as a markdown task list, ticking the 1-based
+// item numbers in checked
+func formatChecklist(items []string, checked []int) (string, error) {
+	if len(items) == 0 {
+		return "", fmt.Errorf("no review checklist configured (set review_checklist in .bb.yml or the user config)")
+	}
+
+	ticked := make(map[int]bool, len(checked))
+	for _, n := range checked {
+		if n < 1 || n > len(items) {
+			return "", fmt.Errorf("--check %d out of range (checklist has %d items)", n, len(items))
+		}
+		ticked[n] = true
+	}
+
+	var b strings.Builder
+	b.WriteString("**Review checklist**\n\n")
+	for i, item := range items {
+		mark := " "
+		if ticked[i+1] {
+			mark = "x"
+		}
+		fmt.Fprintf(&b, "- [%s] %s\n", mark, item)
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
@@ -0,0 +1,23 @@
+package review
+
+import "testing"
+
+func TestFormatChecklist(t *testing.T) {
+	items := []string{"Tests cover the change", "Docs updated", "No secrets in logs"}
+
+	got, err := formatChecklist(items, []int{1, 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "**Review checklist**\n\n- [x] Tests cover the change\n- [ ] Docs updated\n- [x] No secrets in logs"
+	if got != want {
+		t.Errorf("formatChecklist() =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := formatChecklist(items, []int{4}); err == nil {
+		t.Error("expected error for out-of-range item")
+	}
+	if _, err := formatChecklist(nil, nil); err == nil {
+		t.Error("expected error without a configured checklist")
+	}
+}
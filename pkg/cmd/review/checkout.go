@@ -0,0 +1,175 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/git"
+)
+
+type checkoutOptions struct {
+	repo     string
+	prNumber int
+	worktree string
+	remote   string
+	branch   string
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdCheckout creates the review checkout command
+func NewCmdCheckout(f *cmdutil.Factory) *cobra.Command {
+	opts := &checkoutOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "checkout <pr-number>",
+		Short: "Check out a pull request branch locally",
+		Long: `Check out the source branch of a pull request.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+Must be run inside a local clone of the repository.
+
+Use --worktree to create a separate git worktree for the PR instead of
+switching the current checkout, so in-progress work is left untouched.
+
+The local branch is named after the PR's source branch, except for PRs from
+forks, which are checked out as pr-<number> so a fork's main never lands on
+yours. An existing branch is only fast-forwarded to the PR head.
+
+Examples:
+  # Switch the current checkout to the PR branch
+  bbc review checkout 450 --repo test_repo
+
+  # Check out the PR into a new worktree
+  bbc review checkout 450 --repo test_repo --worktree ../review-450`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.factory.NewBBCloudClient("")
+			if err != nil {
+				return err
+			}
+
+			prNum, err := parsePRNumber(args[0])
+			if err != nil {
+				return err
+			}
+			opts.prNumber = prNum
+
+			return runCheckout(cmd.Context(), opts, client)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+	cmd.Flags().StringVar(&opts.worktree, "worktree", "", "Create a git worktree at this directory instead of switching branches")
+	cmd.Flags().StringVar(&opts.remote, "remote", "origin", "Git remote to fetch the PR branch from")
+	cmd.Flags().StringVarP(&opts.branch, "branch", "b", "", "Local branch name (default: PR source branch)")
+
+	return cmd
+}
+
+func runCheckout(ctx context.Context, opts *checkoutOptions, client *bbcloud.Client) error {
+	pr, err := client.GetPullRequest(ctx, opts.repo, opts.prNumber)
+	if err != nil {
+		return fmt.Errorf("get pull request: %w", err)
+	}
+	if pr.Source == nil || pr.Source.Branch == nil || pr.Source.Branch.Name == "" {
+		return fmt.Errorf("PR %d has no source branch", opts.prNumber)
+	}
+	sourceBranch := pr.Source.Branch.Name
+
+	// PRs from forks live in another repository; fetch straight from it
+	remote := opts.remote
+	fork := isForkPR(pr)
+	if fork {
+		if remote, err = forkRemote(opts.factory, pr); err != nil {
+			return err
+		}
+	}
+
+	// A fork's branch name says nothing about the local branch of that name
+	// (a fork PR from main is not the user's main), so fork PRs get their own
+	// branch and only reuse one an earlier checkout of the PR created
+	branch := opts.branch
+	if branch == "" {
+		branch = sourceBranch
+		if fork {
+			branch = fmt.Sprintf("pr-%d", opts.prNumber)
+		}
+	}
+	gitClient := opts.factory.GitClient
+	prRef := strconv.Itoa(opts.prNumber)
+	if fork && gitClient.HasLocalBranch(ctx, branch) && gitClient.BranchConfig(ctx, branch, checkoutPRKey) != prRef {
+		return fmt.Errorf("local branch %s was not checked out for PR %d; pass --branch to use another name", branch, opts.prNumber)
+	}
+
+	commit, err := gitClient.Fetch(ctx, remote, "refs/heads/"+sourceBranch)
+	if err != nil {
+		return fmt.Errorf("fetch PR branch: %w", err)
+	}
+
+	output := map[string]interface{}{
+		"pr":     opts.prNumber,
+		"repo":   opts.repo,
+		"branch": branch,
+		"commit": commit,
+	}
+
+	switch {
+	case opts.worktree != "":
+		if err := gitClient.AddWorktree(ctx, opts.worktree, branch, commit); err != nil {
+			if errors.Is(err, git.ErrDiverged) {
+				return fmt.Errorf("add worktree: local branch %s has commits that are not in PR %d; pass --branch to use another name", branch, opts.prNumber)
+			}
+			return fmt.Errorf("add worktree: %w", err)
+		}
+		dir, err := filepath.Abs(opts.worktree)
+		if err != nil {
+			dir = opts.worktree
+		}
+		output["worktree"] = dir
+	case gitClient.HasLocalBranch(ctx, branch):
+		if err := gitClient.CheckoutBranch(ctx, branch, commit); err != nil {
+			return fmt.Errorf("checkout branch: %w", err)
+		}
+	default:
+		if err := gitClient.CheckoutNewBranch(ctx, branch, commit); err != nil {
+			return fmt.Errorf("checkout branch: %w", err)
+		}
+	}
+	if fork {
+		if err := gitClient.SetBranchConfig(ctx, branch, checkoutPRKey, prRef); err != nil {
+			return fmt.Errorf("record PR branch: %w", err)
+		}
+	}
+
+	return cmdutil.WriteJSON(opts.factory.IOStreams.Out, output)
+}
+
+// checkoutPRKey is the branch setting (branch.<name>.bb-pr) naming the fork
+// PR a branch was checked out for
+const checkoutPRKey = "bb-pr"
+
+// forkRemote returns the clone URL of a fork PR's source repository on the
+// resolved host
+func forkRemote(f *cmdutil.Factory, pr *bbcloud.PullRequest) (string, error) {
+	host, err := f.Host()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("https://%s/%s.git", host.Name, pr.Source.Repository.FullName), nil
+}
+
+// isForkPR reports whether the PR source lives in a different repository than its destination
+func isForkPR(pr *bbcloud.PullRequest) bool {
+	if pr.Source == nil || pr.Source.Repository == nil || pr.Destination == nil || pr.Destination.Repository == nil {
+		return false
+	}
+	return pr.Source.Repository.FullName != "" && pr.Source.Repository.FullName != pr.Destination.Repository.FullName
+}
@@ -0,0 +1,112 @@
+package review
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/bbtest"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/iostreams"
+)
+
+func TestCheckoutForkPR(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv("BB_HOST", "")
+	ctx := context.Background()
+	git := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(dir, file string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(file), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		git(dir, "add", file)
+		git(dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", file)
+	}
+
+	// The user's clone and a fork whose main moved on
+	clone := filepath.Join(t.TempDir(), "clone")
+	git(".", "init", "--quiet", "--initial-branch=main", clone)
+	commit(clone, "init.txt")
+	fork := filepath.Join(t.TempDir(), "fork.git")
+	git(".", "clone", "--quiet", "--bare", clone, fork)
+	forkWork := filepath.Join(t.TempDir(), "fork")
+	git(".", "clone", "--quiet", fork, forkWork)
+	commit(forkWork, "fork.txt")
+	git(forkWork, "push", "--quiet", "origin", "main")
+	forkHead := git(fork, "rev-parse", "main")
+	mainHead := git(clone, "rev-parse", "main")
+	// Fetches from the fork's Bitbucket URL go to the local fork
+	git(clone, "config", "url."+fork+".insteadOf", "https://bitbucket.org/fork/api.git")
+
+	srv := bbtest.NewServer(t)
+	srv.AddRepository("acme", bbcloud.Repository{Slug: "api"})
+	for _, id := range []int{9, 10} {
+		srv.AddPullRequest("acme", "api", bbcloud.PullRequest{
+			ID:          id,
+			Source:      &bbcloud.PullRequestBranch{Branch: &bbcloud.Branch{Name: "main"}, Repository: &bbcloud.Repository{FullName: "fork/api"}},
+			Destination: &bbcloud.PullRequestBranch{Branch: &bbcloud.Branch{Name: "main"}, Repository: &bbcloud.Repository{FullName: "acme/api"}},
+		})
+	}
+	client := srv.Client(t, "acme")
+
+	run := func(prNumber int) (map[string]any, error) {
+		t.Helper()
+		out := &bytes.Buffer{}
+		f := cmdutil.NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: out, ErrOut: &bytes.Buffer{}})
+		f.GitClient.Dir = clone
+		opts := &checkoutOptions{repo: "api", prNumber: prNumber, remote: "origin", factory: f}
+		if err := runCheckout(ctx, opts, client); err != nil {
+			return nil, err
+		}
+		var got map[string]any
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		return got, nil
+	}
+
+	got, err := run(9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["branch"] != "pr-9" || git(clone, "rev-parse", "pr-9") != forkHead {
+		t.Errorf("checkout = %v, want pr-9 at the fork head", got)
+	}
+	if head := git(clone, "rev-parse", "main"); head != mainHead {
+		t.Errorf("local main moved to %s", head)
+	}
+	// Checking the PR out again reuses its branch
+	git(clone, "checkout", "--quiet", "main")
+	if _, err := run(9); err != nil {
+		t.Errorf("second checkout: %v", err)
+	}
+
+	// A pr-10 branch of the user's own is left alone
+	git(clone, "branch", "pr-10", "main")
+	if _, err := run(10); err == nil || !strings.Contains(err.Error(), "--branch") {
+		t.Errorf("checkout over an unrelated pr-10: err = %v", err)
+	}
+	if head := git(clone, "rev-parse", "pr-10"); head != mainHead {
+		t.Errorf("unrelated pr-10 moved to %s", head)
+	}
+}
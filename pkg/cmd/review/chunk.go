@@ -0,0 +1,160 @@
+package review
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// defaultDiffMaxLines is the --max-lines default: longer diffs are cut into
+// pages so a huge file cannot flood a terminal or an agent's context window
+const defaultDiffMaxLines = 2000
+
+// diffTruncation is the structured marker of a page that is not the last.
+// Outputs embed it, so its fields sit at the top level and are left out on
+// the last page.
+type diffTruncation struct {
+	Truncated      bool   `json:"truncated,omitempty"`
+	RemainingLines int    `json:"remaining_lines,omitempty"`
+	Next           string `json:"next,omitempty"` // flags that fetch the next page
+}
+
+// defaultMaxFiles is the --max-files default of review view and list: a PR
+// touching more files lists (view) or counts (list) only the first ones
+const defaultMaxFiles = 1000
+
+// fileTruncation is the structured marker of a file list cut short by
+// --max-files
+type fileTruncation struct {
+	Shown int `json:"shown"`
+	Total int `json:"total"`
+}
+
+// truncateFiles returns the marker for showing shown of total files, or nil
+// when none are left out
+func truncateFiles(shown, total int) *fileTruncation {
+	if shown >= total {
+		return nil
+	}
+	return &fileTruncation{Shown: shown, Total: total}
+}
+
+// marker returns the line printed after a cut file list
+func (t *fileTruncation) marker() string {
+	return fmt.Sprintf("… showing %d of %d files; --max-files 0 lists them all", t.Shown, t.Total)
+}
+
+// diffPage is one bounded chunk of a unified diff
+type diffPage struct {
+	Diff       string `json:"diff"`
+	Page       int    `json:"page"`
+	Pages      int    `json:"pages"`
+	TotalLines int    `json:"total_lines"`
+	NextPage   int    `json:"next_page,omitempty"`
+}
+
+// paginateDiff returns page (1-based) of diff split into maxLines-line pages.
+// maxLines <= 0 returns the whole diff as a single page.
+func paginateDiff(diff string, maxLines, page int) (diffPage, error) {
+	lines := strings.Split(strings.TrimRight(diff, "\n"), "\n")
+	if diff == "" {
+		lines = nil
+	}
+	total := len(lines)
+
+	if maxLines <= 0 {
+		if page > 1 {
+			return diffPage{}, fmt.Errorf("--page requires --max-lines")
+		}
+		return diffPage{Diff: diff, Page: 1, Pages: 1, TotalLines: total}, nil
+	}
+
+	pages := (total + maxLines - 1) / maxLines
+	if pages == 0 {
+		pages = 1
+	}
+	if page < 1 || page > pages {
+		return diffPage{}, fmt.Errorf("page %d out of range (diff has %d pages of %d lines)", page, pages, maxLines)
+	}
+
+	start := (page - 1) * maxLines
+	end := min(start+maxLines, total)
+	p := diffPage{Page: page, Pages: pages, TotalLines: total}
+	if end > start {
+		p.Diff = strings.Join(lines[start:end], "\n") + "\n"
+	}
+	if page < pages {
+		p.NextPage = page + 1
+	}
+	return p, nil
+}
+
+// continuation returns the marker printed after a truncated page, or "" for
+// the last page
+func (p diffPage) continuation(maxLines int) string {
+	t := p.truncation(maxLines)
+	if t == nil {
+		return ""
+	}
+	first := (p.Page-1)*maxLines + 1
+	last := first + maxLines - 1
+	return fmt.Sprintf("… diff truncated: lines %d-%d of %d (page %d of %d); continue with %s",
+		first, last, p.TotalLines, p.Page, p.Pages, t.Next)
+}
+
+// truncation returns the structured marker of a truncated page, or nil for
+// the last page
+func (p diffPage) truncation(maxLines int) *diffTruncation {
+	if p.NextPage == 0 {
+		return nil
+	}
+	next := fmt.Sprintf("--page %d", p.NextPage)
+	if maxLines != defaultDiffMaxLines {
+		next = fmt.Sprintf("--max-lines %d %s", maxLines, next)
+	}
+	return &diffTruncation{
+		Truncated:      true,
+		RemainingLines: p.TotalLines - p.Page*maxLines,
+		Next:           next,
+	}
+}
+
+// splitDiffFiles splits a multi-file unified diff into per-file sections
+func splitDiffFiles(diff string) []string {
+	var files []string
+	var current strings.Builder
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git") && current.Len() > 0 {
+			files = append(files, current.String())
+			current.Reset()
+		}
+		current.WriteString(line)
+	}
+	if current.Len() > 0 {
+		files = append(files, current.String())
+	}
+	return files
+}
+
+// parseFileRange parses a 1-based inclusive "start:end" file range. Either
+// bound may be omitted ("5:", ":10"); end 0 means the last file.
+func parseFileRange(s string) (int, int, error) {
+	startStr, endStr, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid file range %q (expected start:end)", s)
+	}
+
+	start, end := 1, 0
+	var err error
+	if startStr != "" {
+		if start, err = strconv.Atoi(startStr); err != nil || start < 1 {
+			return 0, 0, fmt.Errorf("invalid file range start: %s", startStr)
+		}
+	}
+	if endStr != "" {
+		if end, err = strconv.Atoi(endStr); err != nil || end < start {
+			return 0, 0, fmt.Errorf("invalid file range end: %s", endStr)
+		}
+	}
+	return start, end, nil
+}
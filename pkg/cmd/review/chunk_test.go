@@ -0,0 +1,118 @@
+package review
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPaginateDiff(t *testing.T) {
+	diff := "l1\nl2\nl3\nl4\nl5\n"
+
+	p, err := paginateDiff(diff, 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Diff != "l1\nl2\n" || p.Pages != 3 || p.TotalLines != 5 || p.NextPage != 2 {
+		t.Errorf("page 1 = %+v", p)
+	}
+	if got := p.continuation(2); !strings.Contains(got, "lines 1-2 of 5") || !strings.Contains(got, "--max-lines 2 --page 2") {
+		t.Errorf("continuation = %q", got)
+	}
+	if got := p.truncation(2); got == nil || !got.Truncated || got.RemainingLines != 3 || got.Next != "--max-lines 2 --page 2" {
+		t.Errorf("truncation = %+v", got)
+	}
+
+	p, err = paginateDiff(diff, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Diff != "l5\n" || p.NextPage != 0 || p.continuation(2) != "" || p.truncation(2) != nil {
+		t.Errorf("last page = %+v", p)
+	}
+
+	if _, err := paginateDiff(diff, 2, 4); err == nil {
+		t.Error("expected out-of-range page error")
+	}
+	if _, err := paginateDiff(diff, 0, 2); err == nil {
+		t.Error("expected --page without --max-lines to fail")
+	}
+
+	long := strings.Repeat("line\n", defaultDiffMaxLines+10)
+	p, err = paginateDiff(long, defaultDiffMaxLines, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.truncation(defaultDiffMaxLines); got == nil || got.RemainingLines != 10 || got.Next != "--page 2" {
+		t.Errorf("default truncation = %+v", got)
+	}
+
+	p, err = paginateDiff(diff, 0, 1)
+	if err != nil || p.Diff != diff || p.Pages != 1 {
+		t.Errorf("unlimited = %+v, %v", p, err)
+	}
+}
+
+func TestSplitDiffFiles(t *testing.T) {
+	diff := "diff --git a/a b/a\n@@ -1 +1 @@\n-x\n+y\ndiff --git a/b b/b\n@@ -1 +1 @@\n-p\n+q\n"
+	files := splitDiffFiles(diff)
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(files))
+	}
+	if !strings.HasPrefix(files[1], "diff --git a/b b/b") || strings.Join(files, "") != diff {
+		t.Errorf("unexpected split: %q", files)
+	}
+}
+
+func TestParseFileRange(t *testing.T) {
+	tests := []struct {
+		in         string
+		start, end int
+		wantErr    bool
+	}{
+		{"1:10", 1, 10, false},
+		{"5:", 5, 0, false},
+		{":3", 1, 3, false},
+		{"3", 0, 0, true},
+		{"0:2", 0, 0, true},
+		{"4:2", 0, 0, true},
+	}
+	for _, tt := range tests {
+		start, end, err := parseFileRange(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseFileRange(%q) error = %v", tt.in, err)
+			continue
+		}
+		if !tt.wantErr && (start != tt.start || end != tt.end) {
+			t.Errorf("parseFileRange(%q) = %d:%d, want %d:%d", tt.in, start, end, tt.start, tt.end)
+		}
+	}
+}
+
+func TestDiffTruncationJSON(t *testing.T) {
+	p, err := paginateDiff("l1\nl2\nl3\n", 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := diffViewOutput{PR: 1, Diff: p.Diff, Page: p.Page, Pages: p.Pages}
+	output.diffTruncation = *p.truncation(2)
+	data, err := json.Marshal(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["truncated"] != true || got["remaining_lines"] != float64(1) || got["next"] != "--max-lines 2 --page 2" {
+		t.Errorf("truncated page JSON = %s", data)
+	}
+
+	data, err = json.Marshal(diffViewOutput{PR: 1, Page: 2, Pages: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "truncated") || strings.Contains(string(data), "remaining_lines") {
+		t.Errorf("last page JSON = %s, want no truncation marker", data)
+	}
+}
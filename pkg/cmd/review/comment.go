@@ -23,6 +23,7 @@ type commentOptions struct {
 	delete    int // comment ID to delete
 	resolve   int // comment ID to resolve
 	reopen    int // comment ID to reopen
+	pending   bool
 
 	factory *cmdutil.Factory
 }
@@ -32,11 +33,11 @@ func NewCmdComment(f *cmdutil.Factory) *cobra.Command {
 	opts := &commentOptions{factory: f}
 
 	cmd := &cobra.Command{
-		Use:   "comment <pr-number> [file-path line-start [line-end]] <message>",
+		Use:   "comment <pr-number> [file-path line-start [line-end]] [<message>]",
 		Short: "Manage comments on pull requests",
 		Long: `Add, edit, delete, resolve, or reopen comments on pull requests.
 
-Requires --repo flag to specify the repository.
+Requires --repo flag (or a default_repo setting) to specify the repository.
 
 General comment:
   bbc review comment <pr> --repo <repo> "message"
@@ -47,8 +48,15 @@ Inline comment (single line):
 Inline comment (line range):
   bbc review comment <pr> <file> <start> <end> --repo <repo> "message"
 
-Edit comment:
-  bbc review comment <pr> --repo <repo> --edit <comment-id> "updated message"
+Mentions written as @username, @nickname or @DisplayNameWithoutSpaces are
+translated to Bitbucket's @{account-id} syntax for workspace members.
+
+Omit the message to write it in your editor, with the PR and the commented
+lines shown for context (stdin must be a terminal). A numeric fourth argument
+is read as the end of a line range.
+
+Edit comment (omit the message to edit the current text in your editor):
+  bbc review comment <pr> --repo <repo> --edit <comment-id> ["updated message"]
 
 Delete comment:
   bbc review comment <pr> --repo <repo> --delete <comment-id>
@@ -59,6 +67,9 @@ Resolve comment:
 Reopen comment:
   bbc review comment <pr> --repo <repo> --reopen <comment-id>
 
+Queue a comment in a pending review (see bb review start):
+  bbc review comment <pr> [file line-start [line-end]] --repo <repo> --pending "message"
+
 Examples:
   # General comment
   bbc review comment 450 --repo test_repo "Looks good overall"
@@ -79,7 +90,10 @@ Examples:
   bbc review comment 450 --repo test_repo --resolve 753222173
 
   # Reopen comment
-  bbc review comment 450 --repo test_repo --reopen 753222173`,
+  bbc review comment 450 --repo test_repo --reopen 753222173
+
+  # Queue an inline comment until bb review submit
+  bbc review comment 450 src/auth.ts 23 --repo test_repo --pending "Fix this typo"`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Initialize client
@@ -95,20 +109,42 @@ Examples:
 			}
 			opts.prNumber = prNum
 
+			if opts.pending && (opts.edit > 0 || opts.delete > 0 || opts.resolve > 0 || opts.reopen > 0) {
+				return fmt.Errorf("--pending only applies to new comments")
+			}
+
 			// Handle --edit flag
 			if opts.edit > 0 {
 				if len(args) < 2 {
-					return fmt.Errorf("message is required when editing a comment")
+					// No new text: edit the current comment body in the editor
+					existing, err := client.GetComment(cmd.Context(), opts.repo, opts.prNumber, opts.edit)
+					if err != nil {
+						return fmt.Errorf("get comment: %w", err)
+					}
+					current := ""
+					if existing.Content != nil {
+						current = existing.Content.Raw
+					}
+					if opts.message, err = opts.factory.Editor("bb-comment-*.md", current); err != nil {
+						return fmt.Errorf("message is required when editing a comment: %w", err)
+					}
+				} else {
+					opts.message = args[1]
 				}
-				opts.message = args[1]
 				if strings.TrimSpace(opts.message) == "" {
 					return fmt.Errorf("message cannot be empty")
 				}
+				opts.message = resolveMentions(cmd.Context(), opts.factory, client, opts.message)
 				return runUpdateComment(cmd.Context(), opts, client)
 			}
 
-			// Handle --delete flag
+			// Handle --delete flag; scripts are not asked
 			if opts.delete > 0 {
+				if opts.factory.IOStreams.CanPrompt() {
+					if err := opts.factory.Confirm(fmt.Sprintf("Delete comment %d on PR %d?", opts.delete, opts.prNumber)); err != nil {
+						return err
+					}
+				}
 				return runDeleteComment(cmd.Context(), opts, client)
 			}
 
@@ -122,49 +158,43 @@ Examples:
 				return runReopenComment(cmd.Context(), opts, client)
 			}
 
-			// Validate minimum args for create operations
-			if len(args) < 2 {
-				return fmt.Errorf("message is required")
+			// The trailing message is optional: without it the comment is composed
+			// in an editor. A numeric fourth argument is a line range end.
+			loc := args[1:]
+			hasMessage := len(loc) == 1 || len(loc) == 4 || (len(loc) == 3 && !isLineNumber(loc[2]))
+			if hasMessage {
+				opts.message = loc[len(loc)-1]
+				loc = loc[:len(loc)-1]
 			}
 
-			// Determine comment type based on args count
-			switch len(args) {
-			case 2:
-				// General comment: pr + message
-				opts.message = args[1]
-				if strings.TrimSpace(opts.message) == "" {
-					return fmt.Errorf("message cannot be empty")
-				}
-				return runGeneralComment(cmd.Context(), opts, client)
+			// Determine comment type based on remaining args
+			switch len(loc) {
+			case 0:
+				// General comment
 
-			case 4:
-				// Inline comment: pr + file + line + message
-				opts.file = args[1]
-				line, err := strconv.Atoi(args[2])
+			case 2:
+				// Inline comment: file + line
+				opts.file = loc[0]
+				line, err := strconv.Atoi(loc[1])
 				if err != nil {
-					return fmt.Errorf("invalid line number: %s", args[2])
+					return fmt.Errorf("invalid line number: %s", loc[1])
 				}
 				if line <= 0 {
 					return fmt.Errorf("line number must be positive, got %d", line)
 				}
 				opts.lineStart = line
 				opts.lineEnd = 0 // Single line
-				opts.message = args[3]
-				if strings.TrimSpace(opts.message) == "" {
-					return fmt.Errorf("message cannot be empty")
-				}
-				return runInlineComment(cmd.Context(), opts, client)
 
-			case 5:
-				// Line range comment: pr + file + start + end + message
-				opts.file = args[1]
-				start, err := strconv.Atoi(args[2])
+			case 3:
+				// Line range comment: file + start + end
+				opts.file = loc[0]
+				start, err := strconv.Atoi(loc[1])
 				if err != nil {
-					return fmt.Errorf("invalid line start: %s", args[2])
+					return fmt.Errorf("invalid line start: %s", loc[1])
 				}
-				end, err := strconv.Atoi(args[3])
+				end, err := strconv.Atoi(loc[2])
 				if err != nil {
-					return fmt.Errorf("invalid line end: %s", args[3])
+					return fmt.Errorf("invalid line end: %s", loc[2])
 				}
 				if start <= 0 || end <= 0 {
 					return fmt.Errorf("line numbers must be positive")
@@ -179,24 +209,36 @@ Examples:
 					opts.lineEnd = end
 				}
 
-				opts.message = args[4]
-				if strings.TrimSpace(opts.message) == "" {
-					return fmt.Errorf("message cannot be empty")
-				}
-				return runInlineComment(cmd.Context(), opts, client)
-
 			default:
 				return fmt.Errorf("invalid number of arguments (expected 2, 4, or 5)")
 			}
+
+			if !hasMessage {
+				if opts.message, err = composeComment(cmd.Context(), opts, client); err != nil {
+					return err
+				}
+			}
+			if strings.TrimSpace(opts.message) == "" {
+				return fmt.Errorf("message cannot be empty")
+			}
+			opts.message = resolveMentions(cmd.Context(), opts.factory, client, opts.message)
+
+			if opts.pending {
+				return runPendingComment(opts, client)
+			}
+			if opts.file == "" {
+				return runGeneralComment(cmd.Context(), opts, client)
+			}
+			return runInlineComment(cmd.Context(), opts, client)
 		},
 	}
 
-	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (required)")
-	_ = cmd.MarkFlagRequired("repo")
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
 	cmd.Flags().IntVar(&opts.edit, "edit", 0, "Edit existing comment by ID")
 	cmd.Flags().IntVar(&opts.delete, "delete", 0, "Delete existing comment by ID")
 	cmd.Flags().IntVar(&opts.resolve, "resolve", 0, "Resolve comment by ID")
 	cmd.Flags().IntVar(&opts.reopen, "reopen", 0, "Reopen comment by ID")
+	cmd.Flags().BoolVar(&opts.pending, "pending", false, "Queue the comment in the pending review instead of posting it")
 
 	return cmd
 }
@@ -218,18 +260,7 @@ func runGeneralComment(ctx context.Context, opts *commentOptions, client *bbclou
 }
 
 func runInlineComment(ctx context.Context, opts *commentOptions, client *bbcloud.Client) error {
-	// Determine line range for API call
-	var lineStart, lineEnd int
-	if opts.lineEnd == 0 {
-		// Single-line comment
-		lineStart = 0
-		lineEnd = opts.lineStart
-	} else {
-		// Range comment
-		lineStart = opts.lineStart
-		lineEnd = opts.lineEnd
-	}
-	
+	lineStart, lineEnd := inlineRange(opts.lineStart, opts.lineEnd)
 	comment, err := client.CreateInlineComment(ctx, opts.repo, opts.prNumber,
 		opts.message, opts.file, lineStart, lineEnd)
 	if err != nil {
@@ -252,6 +283,48 @@ func runInlineComment(ctx context.Context, opts *commentOptions, client *bbcloud
 	return cmdutil.WriteJSON(opts.factory.IOStreams.Out, output)
 }
 
+// inlineRange maps a CLI line range to the API's from/to lines. Single-line
+// comments (end 0) are anchored with "to" only.
+func inlineRange(start, end int) (int, int) {
+	if end == 0 {
+		return 0, start
+	}
+	return start, end
+}
+
+func runPendingComment(opts *commentOptions, client *bbcloud.Client) error {
+	review, err := loadPendingReview(client.Workspace(), opts.repo, opts.prNumber)
+	if err != nil {
+		return err
+	}
+
+	review.Comments = append(review.Comments, pendingComment{
+		Message:   opts.message,
+		File:      opts.file,
+		LineStart: opts.lineStart,
+		LineEnd:   opts.lineEnd,
+	})
+	if err := review.save(); err != nil {
+		return err
+	}
+
+	output := map[string]interface{}{
+		"pr":      opts.prNumber,
+		"repo":    opts.repo,
+		"action":  "queued",
+		"pending": len(review.Comments),
+	}
+	if opts.file != "" {
+		output["file"] = opts.file
+		output["line_start"] = opts.lineStart
+		if opts.lineEnd > 0 {
+			output["line_end"] = opts.lineEnd
+		}
+	}
+
+	return cmdutil.WriteJSON(opts.factory.IOStreams.Out, output)
+}
+
 func runUpdateComment(ctx context.Context, opts *commentOptions, client *bbcloud.Client) error {
 	comment, err := client.UpdateComment(ctx, opts.repo, opts.prNumber, opts.edit, opts.message)
 	if err != nil {
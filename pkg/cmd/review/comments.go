@@ -0,0 +1,186 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+type commentsOptions struct {
+	repo       string
+	prNumber   int
+	unresolved bool
+	json       bool
+	raw        bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdComments creates the review comments command
+func NewCmdComments(f *cmdutil.Factory) *cobra.Command {
+	opts := &commentsOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "comments <pr-number>",
+		Short: "List comment threads on a pull request",
+		Long: `List comment threads on a pull request with their resolution state.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+Only inline threads can be resolved; general comments are never unresolved.
+Use --unresolved to show just the open inline threads, i.e. outstanding
+review feedback.
+
+On a terminal, comment bodies are rendered as styled markdown; --raw prints
+them as written.
+
+Examples:
+  # All threads
+  bbc review comments 450 --repo test_repo
+
+  # Outstanding feedback only
+  bbc review comments 450 --repo test_repo --unresolved`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Initialize client
+			client, err := opts.factory.NewBBCloudClient("")
+			if err != nil {
+				return err
+			}
+
+			// Parse PR number
+			prNum, err := parsePRNumber(args[0])
+			if err != nil {
+				return err
+			}
+			opts.prNumber = prNum
+
+			return runComments(cmd.Context(), opts, client)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+	cmd.Flags().BoolVar(&opts.unresolved, "unresolved", false, "Only show unresolved inline threads")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of markdown")
+	cmd.Flags().BoolVar(&opts.raw, "raw", false, "Print comment bodies as raw markdown, even on a terminal")
+
+	return cmd
+}
+
+type threadInfo struct {
+	ID       int         `json:"id"`
+	File     string      `json:"file,omitempty"`
+	Line     int         `json:"line,omitempty"`
+	Author   string      `json:"author"`
+	AuthorID string      `json:"author_id"` // UUID for @mentions
+	Text     string      `json:"text"`
+	Created  string      `json:"created"`
+	Resolved bool        `json:"resolved"`
+	Replies  []replyInfo `json:"replies"`
+}
+
+type commentsOutput struct {
+	PR         int          `json:"pr"`
+	Threads    []threadInfo `json:"threads"`
+	Unresolved int          `json:"unresolved"`
+}
+
+func runComments(ctx context.Context, opts *commentsOptions, client *bbcloud.Client) error {
+	comments, err := client.ListPRComments(ctx, opts.repo, opts.prNumber)
+	if err != nil {
+		return fmt.Errorf("get comments: %w", err)
+	}
+	comments = readableMentions(ctx, opts.factory, client, comments)
+
+	output := commentsOutput{
+		PR:         opts.prNumber,
+		Threads:    make([]threadInfo, 0),
+		Unresolved: countUnresolvedThreads(comments),
+	}
+	for _, thread := range buildThreads(comments) {
+		if opts.unresolved && !isUnresolvedThread(&thread.Comment) {
+			continue
+		}
+		output.Threads = append(output.Threads, newThreadInfo(thread))
+	}
+
+	ios, _ := opts.factory.Streams()
+	if opts.json {
+		if err := cmdutil.WriteJSON(ios.Out, output); err != nil {
+			return fmt.Errorf("encode output: %w", err)
+		}
+		return nil
+	}
+
+	return renderMarkdownComments(ios.Out, newBodyFormatter(ios, opts.raw), output)
+}
+
+// isUnresolvedThread reports whether c starts an inline thread that is still open
+func isUnresolvedThread(c *bbcloud.Comment) bool {
+	return c.Parent == nil && !c.Deleted && c.IsInline() && !c.IsResolved()
+}
+
+// countUnresolvedThreads counts open inline threads among comments
+func countUnresolvedThreads(comments []bbcloud.Comment) int {
+	n := 0
+	for i := range comments {
+		if isUnresolvedThread(&comments[i]) {
+			n++
+		}
+	}
+	return n
+}
+
+func newThreadInfo(n *commentNode) threadInfo {
+	c := n.Comment
+	t := threadInfo{
+		ID:       c.ID,
+		Created:  c.CreatedOn.Format("2006-01-02T15:04:05Z07:00"),
+		Resolved: c.IsResolved(),
+		Replies:  newReplyInfos(n.Replies),
+	}
+	if c.User != nil {
+		t.Author, t.AuthorID = c.User.DisplayName, c.User.UUID
+	}
+	if c.Content != nil {
+		t.Text = c.Content.Raw
+	}
+	if c.Inline != nil {
+		t.File = c.Inline.Path
+		if c.Inline.To != nil {
+			t.Line = *c.Inline.To
+		}
+	}
+	return t
+}
+
+func renderMarkdownComments(w io.Writer, body bodyFormatter, output commentsOutput) error {
+	_, _ = fmt.Fprintf(w, "# PR %d — %d threads, %d unresolved\n", output.PR, len(output.Threads), output.Unresolved)
+
+	for _, t := range output.Threads {
+		renderThread(w, body, t)
+	}
+	return nil
+}
+
+// renderThread writes a thread's root comment followed by its nested replies
+func renderThread(w io.Writer, body bodyFormatter, t threadInfo) {
+	where := "general"
+	if t.File != "" {
+		where = fmt.Sprintf("%s:%d", text.Line(t.File), t.Line)
+		if t.Resolved {
+			where += ", resolved"
+		} else {
+			where += ", unresolved"
+		}
+	}
+	_, _ = fmt.Fprintf(w, "\n**%s** (id:%s) on %s (comment:%d): %s\n",
+		text.Line(t.Author), t.AuthorID, where, t.ID, body.comment(t.Text, ""))
+	renderReplies(w, body, t.Replies, t.ID, 1)
+}
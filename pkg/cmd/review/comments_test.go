@@ -0,0 +1,27 @@
+package review
+
+import (
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+)
+
+func TestCountUnresolvedThreads(t *testing.T) {
+	line := 3
+	comments := []bbcloud.Comment{
+		{ID: 1, Inline: &bbcloud.InlineLocation{Path: "a.go", To: &line}},
+		{ID: 2, Inline: &bbcloud.InlineLocation{Path: "a.go", To: &line}, Resolution: &bbcloud.CommentResolution{Type: "comment_resolution"}},
+		{ID: 3}, // general comments cannot be resolved
+		{ID: 4, Inline: &bbcloud.InlineLocation{Path: "a.go"}, Parent: &bbcloud.CommentRef{ID: 1}},
+		{ID: 5, Inline: &bbcloud.InlineLocation{Path: "b.go"}, Deleted: true},
+	}
+
+	if got := countUnresolvedThreads(comments); got != 1 {
+		t.Errorf("countUnresolvedThreads = %d, want 1", got)
+	}
+
+	thread := newThreadInfo(buildThreads(comments)[0])
+	if thread.File != "a.go" || thread.Line != 3 || thread.Resolved || len(thread.Replies) != 1 {
+		t.Errorf("unexpected thread: %+v", thread)
+	}
+}
@@ -0,0 +1,145 @@
+package review
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// compactDiff rewrites a unified diff into a token-lean form for agents:
+//
+//	src/auth/login.go [M]
+//	@ -12,2 +12,3
+//	-removed line
+//	+added line
+//	./session.go [A]
+//
+// Git headers collapse into one line per file, and a file in the same
+// directory as the previous one is written as ./name. Context lines are
+// dropped and each hunk is split into runs of changes, each with its own
+// exact line ranges (a count of 1 is omitted, as in unified diffs). Text
+// outside any file diff, such as the rename note from review view, is kept.
+func compactDiff(diff string) string {
+	var b strings.Builder
+	var (
+		oldPath, newPath, status string
+		binary, headerDone       bool
+		inFile, inHunk           bool
+		prevDir                  string
+		oldLine, newLine         int
+		removed, added           []string
+	)
+
+	writeHeader := func() {
+		if headerDone {
+			return
+		}
+		headerDone = true
+		name := newPath
+		if status == "D" {
+			name = oldPath
+		}
+		dir := path.Dir(name)
+		label := name
+		if prevDir != "" && dir == prevDir {
+			label = "./" + path.Base(name)
+		}
+		prevDir = dir
+		if status == "R" {
+			label = oldPath + " → " + label
+		}
+		if binary {
+			status += ", binary"
+		}
+		fmt.Fprintf(&b, "%s [%s]\n", label, status)
+	}
+
+	flush := func() {
+		if len(removed) == 0 && len(added) == 0 {
+			return
+		}
+		oldStart, newStart := oldLine-len(removed), newLine-len(added)
+		fmt.Fprintf(&b, "@ -%s +%s\n", compactRange(oldStart, len(removed)), compactRange(newStart, len(added)))
+		for _, l := range removed {
+			b.WriteString("-" + l + "\n")
+		}
+		for _, l := range added {
+			b.WriteString("+" + l + "\n")
+		}
+		removed, added = nil, nil
+	}
+
+	endFile := func() {
+		flush()
+		if inFile {
+			writeHeader()
+		}
+	}
+
+	for _, l := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(l, "diff --git "):
+			endFile()
+			oldPath, newPath = gitDiffPaths(l)
+			status, binary, headerDone = "M", false, false
+			inFile, inHunk = true, false
+		case strings.HasPrefix(l, "@@"):
+			flush()
+			if inFile {
+				writeHeader()
+			}
+			oldLine, newLine = hunkStarts(l)
+			inHunk = true
+		case inHunk && strings.HasPrefix(l, "-"):
+			removed = append(removed, l[1:])
+			oldLine++
+		case inHunk && strings.HasPrefix(l, "+"):
+			added = append(added, l[1:])
+			newLine++
+		case inHunk && strings.HasPrefix(l, `\`):
+			// "\ No newline at end of file"
+		case inHunk:
+			flush()
+			oldLine++
+			newLine++
+		case inFile:
+			switch {
+			case strings.HasPrefix(l, "new file mode"):
+				status = "A"
+			case strings.HasPrefix(l, "deleted file mode"):
+				status = "D"
+			case strings.HasPrefix(l, "rename from "):
+				status, oldPath = "R", strings.TrimPrefix(l, "rename from ")
+			case strings.HasPrefix(l, "rename to "):
+				newPath = strings.TrimPrefix(l, "rename to ")
+			case strings.HasPrefix(l, "Binary files"):
+				binary = true
+			}
+		case strings.TrimSpace(l) != "":
+			b.WriteString(l + "\n")
+		}
+	}
+	endFile()
+	return b.String()
+}
+
+// compactRange formats a hunk range; a count of 1 is implied
+func compactRange(start, count int) string {
+	if count == 0 {
+		// Pure insertions and deletions anchor after the preceding line
+		return fmt.Sprintf("%d,0", max(start-1, 0))
+	}
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// gitDiffPaths extracts the old and new paths from "diff --git a/x b/y"
+func gitDiffPaths(header string) (string, string) {
+	rest := strings.TrimPrefix(header, "diff --git ")
+	if i := strings.LastIndex(rest, " b/"); i >= 0 {
+		return strings.TrimPrefix(rest[:i], "a/"), rest[i+3:]
+	}
+	return rest, rest
+}
@@ -0,0 +1,61 @@
+package review
+
+import "testing"
+
+func TestCompactDiff(t *testing.T) {
+	diff := `diff --git a/src/auth/login.go b/src/auth/login.go
+index 1111111..2222222 100644
+--- a/src/auth/login.go
++++ b/src/auth/login.go
+@@ -10,7 +10,8 @@ func Login() {
+ 	a := 1
+-	b := 2
++	b := 3
++	c := 4
+ 	d := 5
+ 	e := 6
+ 	f := 7
+-	g := 8
+ }
+diff --git a/src/auth/session.go b/src/auth/session.go
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/src/auth/session.go
+@@ -0,0 +1,2 @@
++package auth
++
+\ No newline at end of file
+diff --git a/docs/old.md b/docs/new.md
+similarity index 100%
+rename from docs/old.md
+rename to docs/new.md
+diff --git a/logo.png b/logo.png
+deleted file mode 100644
+Binary files a/logo.png and /dev/null differ
+`
+	want := `src/auth/login.go [M]
+@ -11 +11,2
+-	b := 2
++	b := 3
++	c := 4
+@ -15 +15,0
+-	g := 8
+./session.go [A]
+@ -0,0 +1,2
++package auth
++
+docs/old.md → docs/new.md [R]
+logo.png [D, binary]
+`
+	if got := compactDiff(diff); got != want {
+		t.Errorf("compactDiff() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCompactDiffKeepsNotes(t *testing.T) {
+	diff := "renamed: a.go → b.go\n"
+	if got := compactDiff(diff); got != diff {
+		t.Errorf("compactDiff() = %q, want %q", got, diff)
+	}
+}
@@ -0,0 +1,165 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// scissors separates the message from the read-only context in an editor
+// template, as in git commit --verbose. Markdown headings start with '#', so
+// comment lines cannot be stripped individually.
+const scissors = "# ------------------------ >8 ------------------------"
+
+// composeTemplate returns editor contents with an empty message area followed
+// by context lines below the scissors line
+func composeTemplate(context []string) string {
+	var b strings.Builder
+	b.WriteString("\n\n")
+	b.WriteString(scissors + "\n")
+	b.WriteString("# Do not modify or remove the line above.\n")
+	b.WriteString("# Everything below it will be ignored.\n")
+	if len(context) > 0 {
+		b.WriteString("#\n")
+	}
+	for _, line := range context {
+		b.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+	}
+	return b.String()
+}
+
+// stripScissors returns the message written above the scissors line
+func stripScissors(text string) string {
+	if i := strings.Index(text, scissors); i >= 0 {
+		text = text[:i]
+	}
+	return strings.TrimSpace(text)
+}
+
+// editMessage opens the editor on a template built from context and returns the
+// message, failing if it was left empty
+func editMessage(f *cmdutil.Factory, context []string) (string, error) {
+	edited, err := f.Editor("bb-comment-*.md", composeTemplate(context))
+	if err != nil {
+		return "", fmt.Errorf("message is required: %w", err)
+	}
+	message := stripScissors(edited)
+	if message == "" {
+		return "", fmt.Errorf("aborting comment due to empty message")
+	}
+	return message, nil
+}
+
+// composeComment opens the editor for a new comment, showing the PR and, for
+// inline comments, the commented lines of the diff. Context is best effort.
+func composeComment(ctx context.Context, opts *commentOptions, client *bbcloud.Client) (string, error) {
+	if !opts.factory.IOStreams.CanPrompt() {
+		return "", cmdutil.MissingInputError("the message argument")
+	}
+	var lines []string
+	if pr, err := client.GetPullRequest(ctx, opts.repo, opts.prNumber); err == nil {
+		lines = append(lines, fmt.Sprintf("PR #%d: %s", pr.ID, pr.Title))
+	} else {
+		lines = append(lines, fmt.Sprintf("PR #%d", opts.prNumber))
+	}
+
+	if opts.file != "" {
+		end := opts.lineEnd
+		if end == 0 {
+			end = opts.lineStart
+		}
+		if end == opts.lineStart {
+			lines = append(lines, fmt.Sprintf("File: %s, line %d", opts.file, opts.lineStart))
+		} else {
+			lines = append(lines, fmt.Sprintf("File: %s, lines %d-%d", opts.file, opts.lineStart, end))
+		}
+		if diff, err := client.GetPRFileDiff(ctx, opts.repo, opts.prNumber, opts.file, bbcloud.DiffOptions{}); err == nil {
+			if quoted := quoteDiffLines(diff, opts.lineStart, end); len(quoted) > 0 {
+				lines = append(lines, "")
+				lines = append(lines, quoted...)
+			}
+		}
+	}
+
+	return editMessage(opts.factory, lines)
+}
+
+// composeReply opens the editor for a reply, quoting the parent comment
+func composeReply(ctx context.Context, opts *replyOptions, client *bbcloud.Client) (string, error) {
+	if !opts.factory.IOStreams.CanPrompt() {
+		return "", cmdutil.MissingInputError("the message argument")
+	}
+	lines := []string{fmt.Sprintf("Reply to comment %d on PR #%d", opts.commentID, opts.prNumber)}
+
+	if parent, err := client.GetComment(ctx, opts.repo, opts.prNumber, opts.commentID); err == nil {
+		if parent.Inline != nil && parent.Inline.To != nil {
+			lines = append(lines, fmt.Sprintf("File: %s, line %d", parent.Inline.Path, *parent.Inline.To))
+		}
+		author := "unknown"
+		if parent.User != nil {
+			author = parent.User.DisplayName
+		}
+		lines = append(lines, "", author+" wrote:")
+		if parent.Content != nil {
+			for _, l := range strings.Split(strings.TrimRight(parent.Content.Raw, "\n"), "\n") {
+				lines = append(lines, "> "+l)
+			}
+		}
+	}
+
+	return editMessage(opts.factory, lines)
+}
+
+// quoteDiffLines returns the new-side lines start..end of a unified diff,
+// numbered. Lines outside the diff's hunks are not available and are skipped.
+func quoteDiffLines(diff string, start, end int) []string {
+	var out []string
+	line := 0
+	inHunk := false
+	for _, l := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(l, "@@") {
+			line, inHunk = hunkNewStart(l), true
+			continue
+		}
+		if !inHunk || l == "" || strings.HasPrefix(l, "-") || strings.HasPrefix(l, `\`) {
+			continue
+		}
+		if strings.HasPrefix(l, "diff --git") {
+			inHunk = false
+			continue
+		}
+		if line >= start && line <= end {
+			// Drop the '+' or ' ' marker
+			out = append(out, fmt.Sprintf("%5d | %s", line, l[1:]))
+		}
+		line++
+	}
+	return out
+}
+
+// hunkNewStart parses the new-file start line from a hunk header such as
+// "@@ -10,7 +12,8 @@"
+func hunkNewStart(header string) int {
+	fields := strings.Fields(header)
+	for _, f := range fields {
+		if strings.HasPrefix(f, "+") {
+			n, _, _ := strings.Cut(f[1:], ",")
+			start, err := strconv.Atoi(n)
+			if err != nil {
+				return 0
+			}
+			return start
+		}
+	}
+	return 0
+}
+
+// isLineNumber reports whether s is a positive integer
+func isLineNumber(s string) bool {
+	n, err := strconv.Atoi(s)
+	return err == nil && n > 0
+}
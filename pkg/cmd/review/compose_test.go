@@ -0,0 +1,44 @@
+package review
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComposeTemplateRoundTrip(t *testing.T) {
+	tmpl := composeTemplate([]string{"PR #7: Add auth", "", "   12 | return nil"})
+	if !strings.Contains(tmpl, "# PR #7: Add auth\n#\n#    12 | return nil\n") {
+		t.Errorf("context not quoted:\n%s", tmpl)
+	}
+
+	// Markdown headings above the scissors survive; everything below is dropped
+	edited := "## Nit\nUse errors.Is here.\n\n" + tmpl
+	if got, want := stripScissors(edited), "## Nit\nUse errors.Is here."; got != want {
+		t.Errorf("stripScissors = %q, want %q", got, want)
+	}
+	if got := stripScissors(tmpl); got != "" {
+		t.Errorf("untouched template should be empty, got %q", got)
+	}
+}
+
+func TestQuoteDiffLines(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -10,4 +10,5 @@ func main() {
+ 	a := 1
+-	b := 2
++	b := 3
++	c := 4
+ 	return
+`
+	got := quoteDiffLines(diff, 11, 12)
+	want := []string{"   11 | \tb := 3", "   12 | \tc := 4"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("quoteDiffLines = %q, want %q", got, want)
+	}
+
+	if got := quoteDiffLines(diff, 40, 41); len(got) != 0 {
+		t.Errorf("lines outside hunks should be skipped, got %q", got)
+	}
+}
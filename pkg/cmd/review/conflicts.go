@@ -0,0 +1,186 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+type conflictsOptions struct {
+	repo     string
+	prNumber int
+	json     bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdConflicts creates the review conflicts command
+func NewCmdConflicts(f *cmdutil.Factory) *cobra.Command {
+	opts := &conflictsOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "conflicts [pr-number]",
+		Short: "List the files of a pull request that conflict",
+		Long: `List the files of a pull request that conflict with its destination branch,
+with the lines of each conflict, so you know what to resolve before pushing
+again.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+Bitbucket flags conflicting files in the PR's diffstat and shows the conflicts
+in its diff between <<<<<<< and >>>>>>> markers; the line ranges are those of
+the markers in the diff's new side. Files deleted on one side and changed on
+the other have no markers, only their status.
+
+When the PR number is omitted inside a git checkout, the open PR for the
+current branch is used.
+
+Examples:
+  bbc review conflicts 450 --repo test_repo
+  bbc review conflicts --repo test_repo --json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Initialize client
+			client, err := opts.factory.NewBBCloudClient("")
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 0 {
+				opts.prNumber, err = currentBranchPR(cmd.Context(), opts.factory, client, opts.repo)
+			} else {
+				opts.prNumber, err = parsePRNumber(args[0])
+			}
+			if err != nil {
+				return err
+			}
+
+			return runConflicts(cmd.Context(), opts, client)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of markdown")
+
+	return cmd
+}
+
+// conflictRange is the span of one conflict, from its <<<<<<< line to its
+// >>>>>>> line, in the new side of the diff
+type conflictRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+type conflictFile struct {
+	Path string `json:"path"`
+	// Status is the diffstat status: "merge conflict", "local deleted" or
+	// "remote deleted"
+	Status    string          `json:"status"`
+	Conflicts []conflictRange `json:"conflicts"`
+}
+
+type conflictsOutput struct {
+	PR    int            `json:"pr"`
+	Files []conflictFile `json:"files"`
+}
+
+func runConflicts(ctx context.Context, opts *conflictsOptions, client *bbcloud.Client) error {
+	stats, _, err := client.ListPRDiffStats(ctx, opts.repo, opts.prNumber, 0)
+	if err != nil {
+		return err
+	}
+
+	output := conflictsOutput{PR: opts.prNumber, Files: []conflictFile{}}
+	for _, f := range stats {
+		if f.Conflicted() {
+			output.Files = append(output.Files, conflictFile{Path: f.GetPath(), Status: f.Status, Conflicts: []conflictRange{}})
+		}
+	}
+
+	// The markers are only in the diff when the API reports conflicts
+	if len(output.Files) > 0 {
+		diff, err := client.GetPRDiff(ctx, opts.repo, opts.prNumber, bbcloud.DiffOptions{})
+		if err != nil {
+			return fmt.Errorf("get diff: %w", err)
+		}
+		ranges := make(map[string][]conflictRange)
+		for _, section := range splitDiffFiles(diff) {
+			if found := conflictRanges(section); len(found) > 0 {
+				ranges[diffFilePath(section)] = found
+			}
+		}
+		for i, f := range output.Files {
+			if found, ok := ranges[f.Path]; ok {
+				output.Files[i].Conflicts = found
+			}
+		}
+	}
+
+	ios, _ := opts.factory.Streams()
+	if opts.json {
+		if err := cmdutil.WriteJSON(ios.Out, output); err != nil {
+			return fmt.Errorf("encode output: %w", err)
+		}
+		return nil
+	}
+
+	return renderMarkdownConflicts(ios.Out, output)
+}
+
+// conflictRanges finds the conflict markers in the file section of a diff
+// and returns the new-side lines each conflict spans
+func conflictRanges(section string) []conflictRange {
+	var (
+		ranges []conflictRange
+		line   int  // new-side number of the next context or added line
+		inHunk bool // past the file header, whose "+++" line is not content
+		start  int
+	)
+	for _, l := range strings.Split(section, "\n") {
+		if strings.HasPrefix(l, "@@") {
+			line, inHunk = hunkNewStart(l), true
+			continue
+		}
+		if !inHunk || l == "" || (l[0] != '+' && l[0] != ' ') {
+			continue
+		}
+		switch content := l[1:]; {
+		case strings.HasPrefix(content, "<<<<<<<"):
+			start = line
+		case strings.HasPrefix(content, ">>>>>>>") && start > 0:
+			ranges = append(ranges, conflictRange{Start: start, End: line})
+			start = 0
+		}
+		line++
+	}
+	return ranges
+}
+
+func renderMarkdownConflicts(w io.Writer, output conflictsOutput) error {
+	if len(output.Files) == 0 {
+		_, _ = fmt.Fprintf(w, "PR %d has no conflicts\n", output.PR)
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(w, "# PR %d: %d conflicting files\n\n", output.PR, len(output.Files))
+	for _, f := range output.Files {
+		var spans []string
+		for _, r := range f.Conflicts {
+			spans = append(spans, fmt.Sprintf("%d-%d", r.Start, r.End))
+		}
+		if len(spans) == 0 {
+			_, _ = fmt.Fprintf(w, "- %s (%s)\n", text.Line(f.Path), f.Status)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "- %s (%s): lines %s\n", text.Line(f.Path), f.Status, strings.Join(spans, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package review
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/bbtest"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/iostreams"
+)
+
+const conflictDiff = `diff --git a/go.mod b/go.mod
+--- a/go.mod
++++ b/go.mod
+@@ -1,4 +1,10 @@
+ module example.com/api
+ 
++<<<<<<< destination:1a2b3c
+ go 1.21
++=======
++go 1.22
++>>>>>>> source:4d5e6f
+ 
+ require (
+@@ -20,3 +26,7 @@ require (
+ 	golang.org/x/sync v0.5.0
++<<<<<<< destination:1a2b3c
++	golang.org/x/text v0.14.0
++=======
++>>>>>>> source:4d5e6f
+ )
+diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,1 +1,1 @@
+-package old
++package main
+`
+
+func TestConflictRanges(t *testing.T) {
+	files := splitDiffFiles(conflictDiff)
+	want := []conflictRange{{Start: 3, End: 7}, {Start: 27, End: 30}}
+	if got := conflictRanges(files[0]); !reflect.DeepEqual(got, want) {
+		t.Errorf("conflictRanges(go.mod) = %v, want %v", got, want)
+	}
+	if got := conflictRanges(files[1]); got != nil {
+		t.Errorf("conflictRanges(main.go) = %v, want none", got)
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	srv := bbtest.NewServer(t)
+	srv.AddRepository("acme", bbcloud.Repository{Slug: "api"})
+	srv.AddPullRequest("acme", "api", bbcloud.PullRequest{Title: "Bump Go"})
+	srv.AddDiffStat("acme", "api", 1,
+		bbcloud.FileStats{New: &bbcloud.FileInfo{Path: "go.mod"}, Status: "merge conflict"},
+		bbcloud.FileStats{New: &bbcloud.FileInfo{Path: "main.go"}},
+		bbcloud.FileStats{Old: &bbcloud.FileInfo{Path: "legacy.go"}, Status: "remote deleted"},
+	)
+	srv.Handle(http.MethodGet, "/repositories/acme/api/pullrequests/1/diff", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(conflictDiff))
+	})
+	client := srv.Client(t, "acme")
+
+	run := func(asJSON bool) string {
+		t.Helper()
+		out := &bytes.Buffer{}
+		f := cmdutil.NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: out, ErrOut: &bytes.Buffer{}})
+		if err := runConflicts(context.Background(), &conflictsOptions{repo: "api", prNumber: 1, json: asJSON, factory: f}, client); err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+
+	var got conflictsOutput
+	if err := json.Unmarshal([]byte(run(true)), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []conflictFile{
+		{Path: "go.mod", Status: "merge conflict", Conflicts: []conflictRange{{3, 7}, {27, 30}}},
+		{Path: "legacy.go", Status: "remote deleted", Conflicts: []conflictRange{}},
+	}
+	if !reflect.DeepEqual(got.Files, want) {
+		t.Errorf("files = %+v, want %+v", got.Files, want)
+	}
+
+	md := run(false)
+	for _, line := range []string{"- go.mod (merge conflict): lines 3-7, 27-30", "- legacy.go (remote deleted)"} {
+		if !strings.Contains(md, line) {
+			t.Errorf("markdown missing %q:\n%s", line, md)
+		}
+	}
+}
+
+func TestConflictsNone(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	srv := bbtest.NewServer(t)
+	srv.AddRepository("acme", bbcloud.Repository{Slug: "api"})
+	srv.AddPullRequest("acme", "api", bbcloud.PullRequest{Title: "Clean"})
+	srv.AddDiffStat("acme", "api", 1, bbcloud.FileStats{New: &bbcloud.FileInfo{Path: "main.go"}})
+
+	out := &bytes.Buffer{}
+	f := cmdutil.NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: out, ErrOut: &bytes.Buffer{}})
+	if err := runConflicts(context.Background(), &conflictsOptions{repo: "api", prNumber: 1, factory: f}, srv.Client(t, "acme")); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "PR 1 has no conflicts\n" {
+		t.Errorf("output = %q", out.String())
+	}
+	// The diff is only read for conflicts the API reports
+	srv.AssertNotRequested(t, http.MethodGet, "/repositories/acme/api/pullrequests/1/diff")
+}
@@ -3,6 +3,10 @@ package review
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -19,6 +23,7 @@ type createOptions struct {
 	description       string
 	closeSourceBranch bool
 	draft             bool
+	reviewers         []string
 
 	factory *cmdutil.Factory
 }
@@ -32,8 +37,17 @@ func NewCmdCreate(f *cmdutil.Factory) *cobra.Command {
 		Short: "Create a new pull request",
 		Long: `Create a new pull request.
 
-Requires --repo flag to specify the repository.
-If --target is not specified, the repository's main branch is used.
+Requires --repo flag (or a default_repo setting) to specify the repository.
+If --target is not specified, the target_branch setting is used, falling back
+to the repository's main branch.
+
+Project defaults from .bb.yml (or user config) apply when flags are omitted:
+reviewers are added unless --reviewer is given, and the pr_template file
+(relative to the repository root, and inside it) becomes the description
+when --description is empty. When run interactively without --description, the description is
+composed in your editor (editor config, $VISUAL, or $EDITOR), and without
+--reviewer the reviewers are picked from the workspace members, starting
+from the configured ones.
 
 Examples:
   # Create PR to main branch
@@ -61,20 +75,142 @@ Examples:
 				return fmt.Errorf("title cannot be empty")
 			}
 
+			if err := applyCreateDefaults(cmd, opts); err != nil {
+				return err
+			}
+
+			if !cmd.Flags().Changed("reviewer") && opts.factory.IOStreams.CanPrompt() {
+				if err := pickReviewers(cmd.Context(), opts, client); err != nil {
+					return err
+				}
+			}
+
+			// Compose the description interactively, seeded with any template
+			if !cmd.Flags().Changed("description") && opts.factory.IOStreams.CanPrompt() {
+				opts.description, err = opts.factory.Editor("bb-pr-*.md", opts.description)
+				if err != nil {
+					return err
+				}
+			}
+
 			return runCreate(cmd.Context(), opts, client)
 		},
 	}
 
-	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (required)")
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
 	cmd.Flags().StringVarP(&opts.targetBranch, "target", "t", "", "Target branch (default: repo main branch)")
 	cmd.Flags().StringVarP(&opts.description, "description", "d", "", "Pull request description")
 	cmd.Flags().BoolVar(&opts.closeSourceBranch, "close-source", false, "Close source branch after merge")
 	cmd.Flags().BoolVar(&opts.draft, "draft", false, "Create as draft pull request")
-	_ = cmd.MarkFlagRequired("repo")
+	cmd.Flags().StringSliceVar(&opts.reviewers, "reviewer", nil, "Reviewer UUID or account ID (repeatable; default from reviewers config)")
 
 	return cmd
 }
 
+// applyCreateDefaults fills omitted flags from the effective configuration
+func applyCreateDefaults(cmd *cobra.Command, opts *createOptions) error {
+	cfg, err := opts.factory.Config()
+	if err != nil {
+		return err
+	}
+
+	if opts.targetBranch == "" {
+		opts.targetBranch = cfg.TargetBranch()
+	}
+	if !cmd.Flags().Changed("reviewer") {
+		opts.reviewers = cfg.Reviewers()
+	}
+
+	if opts.description == "" && cfg.PRTemplate() != "" {
+		root, err := opts.factory.GitClient.TopLevel(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("resolve pr_template: %w", err)
+		}
+		path, err := repoFile(root, cfg.PRTemplate())
+		if err != nil {
+			return fmt.Errorf("resolve pr_template: %w", err)
+		}
+		template, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read pr_template: %w", err)
+		}
+		opts.description = string(template)
+	}
+
+	return nil
+}
+
+// repoFile resolves name, relative to the repository root, to a file inside
+// the repository. A .bb.yml can set it, so absolute paths and paths that leave
+// the repository (through .. or a symlink) are rejected rather than uploaded.
+func repoFile(root, name string) (string, error) {
+	if filepath.IsAbs(name) {
+		return "", fmt.Errorf("%s must be relative to the repository root", name)
+	}
+	root, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", err
+	}
+	path, err := filepath.EvalSymlinks(filepath.Join(root, name))
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the repository", name)
+	}
+	return path, nil
+}
+
+// pickReviewers lets the user choose reviewers among the workspace members,
+// with the configured reviewers preselected. Configured reviewers who are
+// not members are offered by ID.
+func pickReviewers(ctx context.Context, opts *createOptions, client *bbcloud.Client) error {
+	members, err := client.ListWorkspaceMembers(ctx)
+	if err != nil {
+		return fmt.Errorf("list reviewers: %w", err)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return strings.ToLower(members[i].GetName()) < strings.ToLower(members[j].GetName())
+	})
+
+	var names, ids []string
+	var defaults []int
+	for _, m := range members {
+		name := m.GetName()
+		if m.DisplayName != "" && m.DisplayName != name {
+			name += " (" + m.DisplayName + ")"
+		}
+		if slices.ContainsFunc(opts.reviewers, func(r string) bool {
+			return r == m.UUID || (m.AccountID != "" && r == m.AccountID)
+		}) {
+			defaults = append(defaults, len(names))
+		}
+		names = append(names, name)
+		ids = append(ids, m.UUID)
+	}
+	for _, r := range opts.reviewers {
+		if !slices.ContainsFunc(members, func(m bbcloud.User) bool { return r == m.UUID || r == m.AccountID }) {
+			defaults = append(defaults, len(names))
+			names = append(names, r)
+			ids = append(ids, r)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	chosen, err := opts.factory.Prompter.MultiSelect("Reviewers:", names, defaults)
+	if err != nil {
+		return fmt.Errorf("select reviewers: %w", err)
+	}
+	opts.reviewers = make([]string, 0, len(chosen))
+	for _, i := range chosen {
+		opts.reviewers = append(opts.reviewers, ids[i])
+	}
+	return nil
+}
+
 func runCreate(ctx context.Context, opts *createOptions, client *bbcloud.Client) error {
 	pr, err := client.CreatePR(ctx, opts.repo, bbcloud.CreatePROptions{
 		Title:             opts.title,
@@ -83,6 +219,7 @@ func runCreate(ctx context.Context, opts *createOptions, client *bbcloud.Client)
 		DestinationBranch: opts.targetBranch,
 		CloseSourceBranch: opts.closeSourceBranch,
 		Draft:             opts.draft,
+		Reviewers:         opts.reviewers,
 	})
 	if err != nil {
 		return fmt.Errorf("create PR: %w", err)
@@ -0,0 +1,32 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRepoFile(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "repo")
+	if err := os.MkdirAll(filepath.Join(root, ".bitbucket"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{filepath.Join(root, ".bitbucket", "template.md"), filepath.Join(dir, "secret")} {
+		if err := os.WriteFile(name, []byte("x"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(dir, "secret"), filepath.Join(root, "link.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repoFile(root, ".bitbucket/template.md"); err != nil {
+		t.Errorf("template inside the repository: %v", err)
+	}
+	for _, name := range []string{"../secret", ".bitbucket/../../secret", filepath.Join(dir, "secret"), "link.md"} {
+		if path, err := repoFile(root, name); err == nil {
+			t.Errorf("repoFile(%q) = %q, want an error", name, path)
+		}
+	}
+}
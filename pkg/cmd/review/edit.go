@@ -0,0 +1,196 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/httpx"
+)
+
+type editOptions struct {
+	repo        string
+	prNumber    int
+	appendBody  string
+	prependBody string
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdEdit creates the review edit command
+func NewCmdEdit(f *cmdutil.Factory) *cobra.Command {
+	opts := &editOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "edit <pr-number>",
+		Short: "Edit a PR's title and description in your editor",
+		Long: `Edit a pull request's title and description in your editor.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+The editor (editor config, $VISUAL, or $EDITOR) opens with the title on the
+first line and the description after a blank line, like a git commit message.
+Saving an empty title aborts the edit. For non-interactive updates use
+'bbc review update'.
+
+--append-body and --prepend-body skip the editor: the current description
+is fetched and the text added at its end or start, so bots can add
+checklists or links without clobbering human-written content. Text the
+description already starts or ends with is not added again, making re-runs
+safe. The update is not atomic: the PR is read again just before it, and the
+edit starts over when someone changed the PR in between.
+
+Examples:
+  bbc review edit 450 --repo test_repo
+
+  # Add a link below the existing description
+  bbc review edit 450 --repo test_repo --append-body "Deploy preview: https://preview.example.com/450"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.factory.NewBBCloudClient("")
+			if err != nil {
+				return err
+			}
+
+			prNum, err := parsePRNumber(args[0])
+			if err != nil {
+				return err
+			}
+			opts.prNumber = prNum
+
+			if opts.appendBody != "" || opts.prependBody != "" {
+				return runEditBody(cmd.Context(), opts, client)
+			}
+			if !opts.factory.IOStreams.CanPrompt() {
+				return cmdutil.MissingInputError("--append-body or --prepend-body (or use 'bbc review update')")
+			}
+			return runEdit(cmd.Context(), opts, client)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+	cmd.Flags().StringVar(&opts.appendBody, "append-body", "", "Add text to the end of the description, without an editor")
+	cmd.Flags().StringVar(&opts.prependBody, "prepend-body", "", "Add text to the start of the description, without an editor")
+
+	return cmd
+}
+
+func runEdit(ctx context.Context, opts *editOptions, client *bbcloud.Client) error {
+	pr, err := client.GetPullRequest(ctx, opts.repo, opts.prNumber)
+	if err != nil {
+		return fmt.Errorf("get pull request: %w", err)
+	}
+
+	edited, err := opts.factory.Editor("bb-pr-*.md", pr.Title+"\n\n"+pr.Description+"\n")
+	if err != nil {
+		return err
+	}
+
+	title, description := splitTitleBody(edited)
+	if title == "" {
+		return fmt.Errorf("aborting edit: title is empty")
+	}
+
+	updated, err := client.UpdatePR(ctx, opts.repo, opts.prNumber, bbcloud.UpdatePROptions{
+		Title:       title,
+		Description: &description,
+	})
+	if err != nil {
+		return fmt.Errorf("update PR: %w", err)
+	}
+
+	return cmdutil.WriteJSON(opts.factory.IOStreams.Out, map[string]interface{}{
+		"pr":          updated.ID,
+		"repo":        opts.repo,
+		"title":       updated.Title,
+		"description": updated.Description,
+	})
+}
+
+// editBodyAttempts caps how often runEditBody starts over on a PR that changed
+// while it was being edited
+const editBodyAttempts = 3
+
+// runEditBody adds text around the current description in a single update.
+// Bitbucket has no conditional update, so the read and the update are not
+// atomic: the PR is read again just before the update, and the edit starts
+// over from the new description when updated_on moved in between.
+func runEditBody(ctx context.Context, opts *editOptions, client *bbcloud.Client) error {
+	pr, err := client.GetPullRequest(ctx, opts.repo, opts.prNumber)
+	if err != nil {
+		return fmt.Errorf("get pull request: %w", err)
+	}
+
+	var description string
+	for attempt := 1; ; attempt++ {
+		description = extendDescription(pr.Description, opts.prependBody, opts.appendBody)
+		if description == pr.Description {
+			return cmdutil.WriteJSON(opts.factory.IOStreams.Out, map[string]interface{}{
+				"pr":          pr.ID,
+				"repo":        opts.repo,
+				"action":      "unchanged",
+				"description": pr.Description,
+			})
+		}
+
+		// A new memo scope, so the read is not answered from the first one
+		current, err := client.GetPullRequest(httpx.WithMemo(ctx), opts.repo, opts.prNumber)
+		if err != nil {
+			return fmt.Errorf("get pull request: %w", err)
+		}
+		if current.UpdatedOn.Equal(pr.UpdatedOn) {
+			break
+		}
+		if attempt == editBodyAttempts {
+			return fmt.Errorf("PR %d keeps changing while it is edited; try again later", opts.prNumber)
+		}
+		pr = current
+	}
+
+	updated, err := client.UpdatePR(ctx, opts.repo, opts.prNumber, bbcloud.UpdatePROptions{Description: &description})
+	if err != nil {
+		return fmt.Errorf("update PR: %w", err)
+	}
+
+	return cmdutil.WriteJSON(opts.factory.IOStreams.Out, map[string]interface{}{
+		"pr":          updated.ID,
+		"repo":        opts.repo,
+		"action":      "updated",
+		"description": updated.Description,
+	})
+}
+
+// extendDescription adds prepend and appendText around description, separated
+// by blank lines. A block the description already starts (prepend) or ends
+// (appendText) with, as whole lines, is skipped.
+func extendDescription(description, prepend, appendText string) string {
+	body := strings.TrimSpace(description)
+	parts := []string{body}
+	if p := strings.TrimSpace(prepend); p != "" && body != p && !strings.HasPrefix(body, p+"\n") {
+		parts = append([]string{p}, parts...)
+	}
+	if a := strings.TrimSpace(appendText); a != "" && body != a && !strings.HasSuffix(body, "\n"+a) {
+		parts = append(parts, a)
+	}
+	if len(parts) == 1 {
+		return description
+	}
+
+	var kept []string
+	for _, p := range parts {
+		if p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, "\n\n")
+}
+
+// splitTitleBody splits editor text into its first line and the remaining body
+func splitTitleBody(text string) (string, string) {
+	title, body, _ := strings.Cut(strings.TrimLeft(text, "\r\n"), "\n")
+	return strings.TrimSpace(title), strings.TrimSpace(body)
+}
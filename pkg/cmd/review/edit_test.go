@@ -0,0 +1,90 @@
+package review
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/bbtest"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/iostreams"
+)
+
+func TestSplitTitleBody(t *testing.T) {
+	tests := []struct {
+		in, title, body string
+	}{
+		{"Title\n\nBody line 1\nBody line 2\n", "Title", "Body line 1\nBody line 2"},
+		{"\n\nTitle only", "Title only", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		title, body := splitTitleBody(tt.in)
+		if title != tt.title || body != tt.body {
+			t.Errorf("splitTitleBody(%q) = %q, %q; want %q, %q", tt.in, title, body, tt.title, tt.body)
+		}
+	}
+}
+
+func TestExtendDescription(t *testing.T) {
+	tests := []struct {
+		name, desc, prepend, appendText, want string
+	}{
+		{"append", "Human text", "", "Bot link", "Human text\n\nBot link"},
+		{"prepend", "Human text\n", "> Summary", "", "> Summary\n\nHuman text"},
+		{"both", "Body", "Top", "Bottom", "Top\n\nBody\n\nBottom"},
+		{"empty description", "", "", "Checklist", "Checklist"},
+		{"already present", "Body\n\nBot link", "", "Bot link", "Body\n\nBot link"},
+		{"already prepended", "> Summary\n\nBody", "> Summary", "", "> Summary\n\nBody"},
+		{"longer reference", "Fixes #12", "", "Fixes #1", "Fixes #12\n\nFixes #1"},
+		{"text mid-body", "LGTM from Ann\n\nBody", "", "LGTM", "LGTM from Ann\n\nBody\n\nLGTM"},
+	}
+	for _, tt := range tests {
+		if got := extendDescription(tt.desc, tt.prepend, tt.appendText); got != tt.want {
+			t.Errorf("%s: extendDescription() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEditBodyRereadsChangedPR(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	srv := bbtest.NewServer(t)
+
+	// Another bot edits the description between the first two reads
+	reads := 0
+	srv.Handle(http.MethodGet, "/repositories/acme/api/pullrequests/1", func(w http.ResponseWriter, r *http.Request) {
+		reads++
+		pr := bbcloud.PullRequest{ID: 1, Title: "Add auth", Description: "Body", UpdatedOn: time.Unix(100, 0)}
+		if reads > 1 {
+			pr.Description, pr.UpdatedOn = "Body\n\nOther bot", time.Unix(200, 0)
+		}
+		_ = json.NewEncoder(w).Encode(pr)
+	})
+	srv.Handle(http.MethodPut, "/repositories/acme/api/pullrequests/1", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(w, r.Body)
+	})
+
+	out := &bytes.Buffer{}
+	f := cmdutil.NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: out, ErrOut: &bytes.Buffer{}})
+	opts := &editOptions{repo: "api", prNumber: 1, appendBody: "Checklist", factory: f}
+	if err := runEditBody(context.Background(), opts, srv.Client(t, "acme")); err != nil {
+		t.Fatal(err)
+	}
+
+	put := srv.AssertRequested(t, http.MethodPut, "/repositories/acme/api/pullrequests/1")
+	var body map[string]any
+	if err := json.Unmarshal(put.Body, &body); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Body\n\nOther bot\n\nChecklist"; body["description"] != want {
+		t.Errorf("updated description = %q, want %q", body["description"], want)
+	}
+	if reads != 3 {
+		t.Errorf("read the PR %d times, want 3", reads)
+	}
+}
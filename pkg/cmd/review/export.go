@@ -0,0 +1,339 @@
+package review
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+type exportOptions struct {
+	repo     string
+	prNumber int
+	out      string
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdExport creates the review export command
+func NewCmdExport(f *cmdutil.Factory) *cobra.Command {
+	opts := &exportOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "export <pr-number>",
+		Short: "Export a pull request review bundle to a file",
+		Long: `Export a pull request as a single self-contained file for offline review
+or archiving: metadata, reviewers, build statuses, tasks, all comment threads
+and the full diff.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+The format follows the --out extension: .json for a machine-readable bundle,
+.md for a readable document.
+
+Examples:
+  bbc review export 450 --repo test_repo --out pr450.md
+  bbc review export 450 --repo test_repo --out pr450.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := exportFormat(opts.out); err != nil {
+				return err
+			}
+
+			// Initialize client
+			client, err := opts.factory.NewBBCloudClient("")
+			if err != nil {
+				return err
+			}
+
+			// Parse PR number
+			prNum, err := parsePRNumber(args[0])
+			if err != nil {
+				return err
+			}
+			opts.prNumber = prNum
+
+			return runExport(cmd.Context(), opts, client)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+	cmd.Flags().StringVarP(&opts.out, "out", "o", "", "Output file (.md or .json)")
+
+	return cmd
+}
+
+type exportBuild struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+	URL   string `json:"url,omitempty"`
+}
+
+type exportTask struct {
+	ID        int    `json:"id"`
+	Text      string `json:"text"`
+	Resolved  bool   `json:"resolved"`
+	Creator   string `json:"creator,omitempty"`
+	CommentID int    `json:"comment_id,omitempty"` // comment the task is attached to
+}
+
+// exportBundle is everything known about a PR at export time
+type exportBundle struct {
+	Repo        string         `json:"repo"`
+	ExportedAt  string         `json:"exported_at"`
+	ID          int            `json:"id"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Author      string         `json:"author"`
+	State       string         `json:"state"`
+	Source      string         `json:"source"`
+	Target      string         `json:"target"`
+	Created     string         `json:"created"`
+	Updated     string         `json:"updated"`
+	URL         string         `json:"url,omitempty"`
+	Reviewers   []reviewerInfo `json:"reviewers"`
+	Builds      []exportBuild  `json:"builds"`
+	Tasks       []exportTask   `json:"tasks"`
+	Threads     []threadInfo   `json:"threads"`
+	Unresolved  int            `json:"unresolved_threads"`
+	Diff        string         `json:"diff"`
+}
+
+type exportOutput struct {
+	PR     int    `json:"pr"`
+	Repo   string `json:"repo"`
+	File   string `json:"file"`
+	Format string `json:"format"`
+}
+
+// exportFormat infers the bundle format from the output file name
+func exportFormat(out string) (string, error) {
+	if out == "" {
+		return "", fmt.Errorf("--out is required")
+	}
+	switch strings.ToLower(filepath.Ext(out)) {
+	case ".json":
+		return "json", nil
+	case ".md", ".markdown":
+		return "markdown", nil
+	default:
+		return "", fmt.Errorf("cannot infer format from %q (use a .md or .json file)", out)
+	}
+}
+
+func runExport(ctx context.Context, opts *exportOptions, client *bbcloud.Client) error {
+	format, err := exportFormat(opts.out)
+	if err != nil {
+		return err
+	}
+
+	var (
+		pr        *bbcloud.PullRequest
+		diff      string
+		comments  []bbcloud.Comment
+		tasks     []bbcloud.Task
+		pipelines []bbcloud.CommitStatus
+	)
+
+	// An archive must be complete, so every fetch is critical
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		pr, err = client.GetPullRequest(gctx, opts.repo, opts.prNumber)
+		if err != nil {
+			return fmt.Errorf("get pull request: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var err error
+		diff, err = client.GetPRDiff(gctx, opts.repo, opts.prNumber, bbcloud.DiffOptions{})
+		if err != nil {
+			return fmt.Errorf("get diff: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var err error
+		comments, err = client.ListPRComments(gctx, opts.repo, opts.prNumber)
+		if err != nil {
+			return fmt.Errorf("get comments: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var err error
+		tasks, err = client.ListPRTasks(gctx, opts.repo, opts.prNumber)
+		if err != nil {
+			return fmt.Errorf("get tasks: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var err error
+		pipelines, err = client.GetPRPipelines(gctx, opts.repo, opts.prNumber)
+		if err != nil {
+			return fmt.Errorf("get build status: %w", err)
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	comments = readableMentions(ctx, opts.factory, client, comments)
+	bundle := newExportBundle(pr, diff, comments, tasks, pipelines)
+	bundle.Repo = opts.repo
+	bundle.ExportedAt = time.Now().UTC().Format(time.RFC3339)
+
+	var buf bytes.Buffer
+	if format == "json" {
+		err = cmdutil.WriteJSON(&buf, bundle)
+	} else {
+		err = renderMarkdownExport(&buf, bundle)
+	}
+	if err != nil {
+		return fmt.Errorf("encode export: %w", err)
+	}
+	if err := os.WriteFile(opts.out, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("write export: %w", err)
+	}
+
+	ios, _ := opts.factory.Streams()
+	return cmdutil.WriteJSON(ios.Out, exportOutput{
+		PR:     opts.prNumber,
+		Repo:   opts.repo,
+		File:   opts.out,
+		Format: format,
+	})
+}
+
+// newExportBundle assembles the bundle from the fetched PR data
+func newExportBundle(pr *bbcloud.PullRequest, diff string, comments []bbcloud.Comment, tasks []bbcloud.Task, pipelines []bbcloud.CommitStatus) exportBundle {
+	b := exportBundle{
+		ID:          pr.ID,
+		Title:       pr.Title,
+		Description: pr.Description,
+		State:       pr.State,
+		Created:     pr.CreatedOn.Format(time.RFC3339),
+		Updated:     pr.UpdatedOn.Format(time.RFC3339),
+		Reviewers:   make([]reviewerInfo, 0),
+		Builds:      make([]exportBuild, 0, len(pipelines)),
+		Tasks:       make([]exportTask, 0, len(tasks)),
+		Threads:     make([]threadInfo, 0),
+		Unresolved:  countUnresolvedThreads(comments),
+		Diff:        diff,
+	}
+	if pr.Author != nil {
+		b.Author = pr.Author.DisplayName
+	}
+	b.Source, b.Target = branchName(pr.Source), branchName(pr.Destination)
+	if link, err := prWebURL(pr, ""); err == nil {
+		b.URL = link
+	}
+
+	for _, p := range pr.Participants {
+		if p.Role == "REVIEWER" && p.User != nil {
+			state := p.State
+			if state == "" {
+				state = "pending"
+			}
+			b.Reviewers = append(b.Reviewers, reviewerInfo{Username: p.User.DisplayName, State: state})
+		}
+	}
+	for _, p := range pipelines {
+		name := p.Name
+		if name == "" {
+			name = p.Key
+		}
+		b.Builds = append(b.Builds, exportBuild{Name: name, State: p.State, URL: p.URL})
+	}
+	for _, t := range tasks {
+		task := exportTask{ID: t.ID, Resolved: t.State == "RESOLVED"}
+		if t.Content != nil {
+			task.Text = t.Content.Raw
+		}
+		if t.Creator != nil {
+			task.Creator = t.Creator.DisplayName
+		}
+		if t.Comment != nil {
+			task.CommentID = t.Comment.ID
+		}
+		b.Tasks = append(b.Tasks, task)
+	}
+	for _, thread := range buildThreads(comments) {
+		b.Threads = append(b.Threads, newThreadInfo(thread))
+	}
+	return b
+}
+
+func renderMarkdownExport(w io.Writer, b exportBundle) error {
+	_, _ = fmt.Fprintf(w, "# PR %d: %s\n\n", b.ID, text.Line(b.Title))
+	_, _ = fmt.Fprintf(w, "Repository: %s | Author: %s | State: %s\n", b.Repo, text.Line(b.Author), b.State)
+	_, _ = fmt.Fprintf(w, "Source: %s → %s\n", text.Line(b.Source), text.Line(b.Target))
+	_, _ = fmt.Fprintf(w, "Created: %s | Updated: %s | Exported: %s\n", b.Created, b.Updated, b.ExportedAt)
+	if b.URL != "" {
+		_, _ = fmt.Fprintf(w, "URL: %s\n", b.URL)
+	}
+
+	if len(b.Reviewers) > 0 {
+		_, _ = fmt.Fprintln(w, "\n## Reviewers")
+		for _, r := range b.Reviewers {
+			_, _ = fmt.Fprintf(w, "- %s (%s)\n", text.Line(r.Username), r.State)
+		}
+	}
+
+	if b.Description != "" {
+		_, _ = fmt.Fprintf(w, "\n## Description\n%s\n", (bodyFormatter{}).description(b.Description))
+	}
+
+	if len(b.Builds) > 0 {
+		_, _ = fmt.Fprintln(w, "\n## Builds")
+		for _, build := range b.Builds {
+			_, _ = fmt.Fprintf(w, "- %s: %s", text.Line(build.Name), build.State)
+			if build.URL != "" {
+				_, _ = fmt.Fprintf(w, " (%s)", build.URL)
+			}
+			_, _ = fmt.Fprintln(w)
+		}
+	}
+
+	if len(b.Tasks) > 0 {
+		_, _ = fmt.Fprintln(w, "\n## Tasks")
+		for _, t := range b.Tasks {
+			mark := " "
+			if t.Resolved {
+				mark = "x"
+			}
+			_, _ = fmt.Fprintf(w, "- [%s] %s (task:%d", mark, text.Line(unescapeBBMarkdown(t.Text)), t.ID)
+			if t.CommentID != 0 {
+				_, _ = fmt.Fprintf(w, ", comment:%d", t.CommentID)
+			}
+			_, _ = fmt.Fprintln(w, ")")
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "\n## Comments (%d threads, %d unresolved)\n", len(b.Threads), b.Unresolved)
+	for _, t := range b.Threads {
+		renderThread(w, bodyFormatter{}, t)
+	}
+
+	diff := text.Sanitize(b.Diff)
+	_, _ = fmt.Fprintf(w, "\n## Diff\n\n```diff\n%s", diff)
+	if !strings.HasSuffix(diff, "\n") {
+		_, _ = fmt.Fprintln(w)
+	}
+	_, _ = fmt.Fprintln(w, "```")
+	return nil
+}
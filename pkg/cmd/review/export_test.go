@@ -0,0 +1,92 @@
+package review
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+)
+
+func TestExportFormat(t *testing.T) {
+	tests := []struct {
+		out     string
+		want    string
+		wantErr bool
+	}{
+		{"pr450.json", "json", false},
+		{"pr450.md", "markdown", false},
+		{"out/PR450.MD", "markdown", false},
+		{"pr450.txt", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := exportFormat(tt.out)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("exportFormat(%q) = %q, %v; want %q, error %v", tt.out, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestNewExportBundle(t *testing.T) {
+	line := 12
+	user := &bbcloud.User{UUID: "{bob}", DisplayName: "Bob"}
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	pr := &bbcloud.PullRequest{
+		ID:          450,
+		Title:       "Add login",
+		Author:      &bbcloud.User{DisplayName: "Alice"},
+		Source:      &bbcloud.PullRequestBranch{Branch: &bbcloud.Branch{Name: "feature"}},
+		Destination: &bbcloud.PullRequestBranch{Branch: &bbcloud.Branch{Name: "main"}},
+		Participants: []bbcloud.Participant{
+			{User: user, Role: "REVIEWER", State: "approved"},
+			{User: &bbcloud.User{DisplayName: "Carol"}, Role: "REVIEWER"},
+			{User: &bbcloud.User{DisplayName: "Dan"}, Role: "PARTICIPANT"},
+		},
+		CreatedOn: created,
+		UpdatedOn: created,
+	}
+	comments := []bbcloud.Comment{
+		{ID: 1, User: user, Content: &bbcloud.Content{Raw: "Check this"}, Inline: &bbcloud.InlineLocation{Path: "login.go", To: &line}},
+		{ID: 2, User: user, Content: &bbcloud.Content{Raw: "Done"}, Parent: &bbcloud.CommentRef{ID: 1}},
+	}
+	tasks := []bbcloud.Task{
+		{ID: 9, State: "RESOLVED", Content: &bbcloud.Content{Raw: "Add tests"}, Comment: &bbcloud.CommentRef{ID: 1}},
+	}
+	pipelines := []bbcloud.CommitStatus{{Key: "ci", State: "SUCCESSFUL"}}
+
+	b := newExportBundle(pr, "diff --git a/login.go b/login.go\n", comments, tasks, pipelines)
+
+	if b.Source != "feature" || b.Target != "main" || b.Author != "Alice" {
+		t.Errorf("source/target/author = %q/%q/%q", b.Source, b.Target, b.Author)
+	}
+	if len(b.Reviewers) != 2 || b.Reviewers[1].State != "pending" {
+		t.Errorf("reviewers = %+v, want Bob approved and Carol pending", b.Reviewers)
+	}
+	if len(b.Builds) != 1 || b.Builds[0].Name != "ci" {
+		t.Errorf("builds = %+v, want key used as name", b.Builds)
+	}
+	if len(b.Threads) != 1 || len(b.Threads[0].Replies) != 1 || b.Unresolved != 1 {
+		t.Errorf("threads = %+v, unresolved = %d", b.Threads, b.Unresolved)
+	}
+	if len(b.Tasks) != 1 || !b.Tasks[0].Resolved || b.Tasks[0].CommentID != 1 {
+		t.Errorf("tasks = %+v", b.Tasks)
+	}
+
+	var out strings.Builder
+	if err := renderMarkdownExport(&out, b); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# PR 450: Add login",
+		"- Carol (pending)",
+		"- ci: SUCCESSFUL",
+		"- [x] Add tests (task:9, comment:1)",
+		"on login.go:12, unresolved (comment:1): Check this",
+		"```diff\ndiff --git a/login.go b/login.go\n```",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("markdown missing %q:\n%s", want, out.String())
+		}
+	}
+}
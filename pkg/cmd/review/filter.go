@@ -0,0 +1,92 @@
+package review
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// fileFilter selects PR files by --include and --exclude glob patterns.
+// Patterns use path.Match syntax plus ** for any number of directories; a
+// pattern without a slash matches the file name at any depth.
+type fileFilter struct {
+	include []string
+	exclude []string
+}
+
+// validate reports the first malformed pattern
+func (ff fileFilter) validate() error {
+	for _, p := range append(append([]string{}, ff.include...), ff.exclude...) {
+		for _, seg := range strings.Split(p, "/") {
+			if _, err := path.Match(seg, ""); err != nil {
+				return fmt.Errorf("invalid glob pattern %q: %w", p, err)
+			}
+		}
+	}
+	return nil
+}
+
+// active reports whether any pattern is set
+func (ff fileFilter) active() bool {
+	return len(ff.include) > 0 || len(ff.exclude) > 0
+}
+
+// matches reports whether file passes the filter: it must match an include
+// pattern (when any are given) and no exclude pattern
+func (ff fileFilter) matches(file string) bool {
+	if len(ff.include) > 0 && !matchAny(ff.include, file) {
+		return false
+	}
+	return !matchAny(ff.exclude, file)
+}
+
+func matchAny(patterns []string, file string) bool {
+	for _, p := range patterns {
+		if matchGlob(p, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches file against pattern, where a ** segment matches zero or
+// more directories
+func matchGlob(pattern, file string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(file))
+		return ok
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(file, "/"))
+}
+
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(pattern[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+// diffFilePath returns the new path of a file section from a unified diff
+// ("diff --git a/old b/new")
+func diffFilePath(section string) string {
+	header, _, _ := strings.Cut(section, "\n")
+	header = strings.TrimPrefix(header, "diff --git ")
+	if i := strings.LastIndex(header, " b/"); i >= 0 {
+		return header[i+3:]
+	}
+	return strings.TrimPrefix(header, "a/")
+}
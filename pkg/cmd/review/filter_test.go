@@ -0,0 +1,49 @@
+package review
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, file string
+		want          bool
+	}{
+		{"*.go", "pkg/cmd/review/view.go", true},
+		{"*_test.go", "pkg/cmd/review/view.go", false},
+		{"src/**/*.go", "src/main.go", true},
+		{"src/**/*.go", "src/a/b/c.go", true},
+		{"src/**/*.go", "lib/a.go", false},
+		{"docs/*", "docs/a/b.md", false},
+		{"**/vendor/**", "x/vendor/y/z.go", true},
+	}
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.file); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestFileFilter(t *testing.T) {
+	ff := fileFilter{include: []string{"src/**/*.go"}, exclude: []string{"*_test.go"}}
+	if err := ff.validate(); err != nil {
+		t.Fatal(err)
+	}
+	if !ff.matches("src/auth/login.go") {
+		t.Error("expected src/auth/login.go to be included")
+	}
+	if ff.matches("src/auth/login_test.go") {
+		t.Error("expected tests to be excluded")
+	}
+	if ff.matches("README.md") {
+		t.Error("expected files outside include to be filtered")
+	}
+
+	if err := (fileFilter{include: []string{"src/[.go"}}).validate(); err == nil {
+		t.Error("expected malformed pattern to be rejected")
+	}
+}
+
+func TestDiffFilePath(t *testing.T) {
+	if got := diffFilePath("diff --git a/old name.go b/new name.go\nindex 1..2\n"); got != "new name.go" {
+		t.Errorf("diffFilePath = %q", got)
+	}
+}
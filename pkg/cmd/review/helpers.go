@@ -1,8 +1,14 @@
 package review
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strconv"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/git"
 )
 
 // parsePRNumber parses and validates a PR number from a string
@@ -16,3 +22,53 @@ func parsePRNumber(s string) (int, error) {
 	}
 	return n, nil
 }
+
+// currentBranchPR resolves the open PR whose source is the checked-out git branch
+func currentBranchPR(ctx context.Context, f *cmdutil.Factory, client *bbcloud.Client, repo string) (int, error) {
+	branch, err := f.GitClient.CurrentBranch(ctx)
+	if err != nil {
+		if errors.Is(err, git.ErrNotRepository) || errors.Is(err, git.ErrNotOnBranch) {
+			return 0, fmt.Errorf("PR number is required (could not detect current branch: %w)", err)
+		}
+		return 0, fmt.Errorf("detect current branch: %w", err)
+	}
+
+	prs, err := client.FindPullRequestsForBranch(ctx, repo, branch)
+	if err != nil {
+		return 0, err
+	}
+	if len(prs) == 0 {
+		return 0, fmt.Errorf("no open pull request for branch %q (create one with: bbc review create %s --repo %s \"title\")",
+			branch, branch, repo)
+	}
+
+	// Several PRs from one branch (different targets) are rare; prefer the most recently updated
+	return prs[0].ID, nil
+}
+
+// pickPR lets the user choose among the open PRs of repo
+func pickPR(ctx context.Context, f *cmdutil.Factory, client *bbcloud.Client, repo string) (int, error) {
+	prs, err := client.ListPullRequests(ctx, repo, "OPEN", 0)
+	if err != nil {
+		return 0, fmt.Errorf("list pull requests: %w", err)
+	}
+	if len(prs) == 0 {
+		return 0, fmt.Errorf("no open pull requests in %s", repo)
+	}
+
+	options := make([]string, len(prs))
+	for i, pr := range prs {
+		options[i] = fmt.Sprintf("#%d %s", pr.ID, pr.Title)
+		if pr.Source != nil && pr.Source.Branch != nil {
+			options[i] += " [" + pr.Source.Branch.Name + "]"
+		}
+		if pr.Author != nil && pr.Author.GetName() != "" {
+			options[i] += " by " + pr.Author.GetName()
+		}
+	}
+	choice, err := f.Prompter.FuzzySelect("Pull request:", options)
+	if err != nil {
+		return 0, fmt.Errorf("select pull request: %w", err)
+	}
+	return prs[choice].ID, nil
+}
@@ -0,0 +1,274 @@
+package review
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/httpx"
+)
+
+type importOptions struct {
+	repo     string
+	prNumber int
+	file     string
+	dryRun   bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdImport creates the review import command
+func NewCmdImport(f *cmdutil.Factory) *cobra.Command {
+	opts := &importOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "import <pr-number>",
+		Short: "Post a batch of comments from a JSON file",
+		Long: `Post general and inline comments from a JSON file, so external analyzers
+and AI reviewers can deliver their findings in one command.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+The file holds an array of comments, or an object with a "comments" array
+(the format of a pending review). Each comment has a message and, for inline
+comments, a file and line range:
+
+  [
+    {"message": "Consider a constant here"},
+    {"message": "Off by one?", "file": "src/auth.ts", "line_start": 23},
+    {"message": "Extract this", "file": "src/auth.ts", "line_start": 40, "line_end": 52}
+  ]
+
+Every comment is validated before anything is posted: messages must be
+non-empty, line numbers positive, and files part of the PR diff. Use --dry-run
+to validate without posting. Comments are posted in file order; a failure on
+one does not stop the rest. Use --file - to read from stdin.
+
+Examples:
+  bbc review import 450 --repo test_repo --file findings.json --dry-run
+  bbc review import 450 --repo test_repo --file findings.json
+  analyzer --json | bbc review import 450 --repo test_repo --file -`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.file == "" {
+				return fmt.Errorf("--file is required")
+			}
+
+			// Initialize client
+			client, err := opts.factory.NewBBCloudClient("")
+			if err != nil {
+				return err
+			}
+
+			// Parse PR number
+			prNum, err := parsePRNumber(args[0])
+			if err != nil {
+				return err
+			}
+			opts.prNumber = prNum
+
+			return runImport(cmd.Context(), opts, client)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+	cmd.Flags().StringVarP(&opts.file, "file", "f", "", "JSON file of comments (- for stdin)")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Validate the comments without posting them")
+
+	return cmd
+}
+
+// importResult reports the outcome for one imported comment
+type importResult struct {
+	Index     int    `json:"index"` // position in the file, from 1
+	Type      string `json:"type"`  // "general" or "inline"
+	File      string `json:"file,omitempty"`
+	LineStart int    `json:"line_start,omitempty"`
+	LineEnd   int    `json:"line_end,omitempty"`
+	CommentID int    `json:"comment_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type importOutput struct {
+	PR      int            `json:"pr"`
+	Repo    string         `json:"repo"`
+	DryRun  bool           `json:"dry_run,omitempty"`
+	Results []importResult `json:"results"`
+	Posted  int            `json:"posted"`
+	Failed  int            `json:"failed"`
+}
+
+func runImport(ctx context.Context, opts *importOptions, client *bbcloud.Client) error {
+	ios, _ := opts.factory.Streams()
+
+	data, err := readImportFile(opts.file, ios.In)
+	if err != nil {
+		return err
+	}
+	comments, err := parseImportComments(data)
+	if err != nil {
+		return err
+	}
+
+	var paths map[string]bool
+	if hasInlineComments(comments) {
+		diffstats, err := client.GetPRDiffStats(ctx, opts.repo, opts.prNumber)
+		if err != nil {
+			return fmt.Errorf("get diffstat: %w", err)
+		}
+		paths = diffPaths(diffstats)
+	}
+	if errs := validateImportComments(comments, paths); len(errs) > 0 {
+		for _, e := range errs {
+			_, _ = fmt.Fprintf(ios.ErrOut, "error: %s\n", e)
+		}
+		return fmt.Errorf("%d invalid comments in %s; nothing was posted", len(errs), opts.file)
+	}
+
+	output := importOutput{
+		PR:      opts.prNumber,
+		Repo:    opts.repo,
+		DryRun:  opts.dryRun,
+		Results: make([]importResult, len(comments)),
+	}
+	for i, c := range comments {
+		r := importResult{Index: i + 1, Type: "general", File: c.File, LineStart: c.LineStart, LineEnd: c.LineEnd}
+		if c.File != "" {
+			r.Type = "inline"
+		}
+		if !opts.dryRun {
+			c.Message = resolveMentions(ctx, opts.factory, client, c.Message)
+			if id, err := postPendingComment(ctx, client, opts.repo, opts.prNumber, c); err != nil {
+				r.Error = friendlyError(err)
+				output.Failed++
+			} else {
+				r.CommentID = id
+				output.Posted++
+			}
+		}
+		output.Results[i] = r
+	}
+	if opts.factory.DryRun && !opts.dryRun {
+		// The global --dry-run printed the requests; stdout holds only them
+		return httpx.ErrDryRun
+	}
+
+	return cmdutil.WriteJSON(ios.Out, output)
+}
+
+// readImportFile reads the comments file, or stdin for "-"
+func readImportFile(name string, stdin io.Reader) ([]byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if name == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read comments file: %w", err)
+	}
+	return data, nil
+}
+
+// parseImportComments decodes either a bare array of comments or an object
+// with a "comments" array. Unknown fields are rejected to catch typos such as
+// "line" for "line_start".
+func parseImportComments(data []byte) ([]pendingComment, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("comments file is empty")
+	}
+
+	var comments []pendingComment
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	dec.DisallowUnknownFields()
+	if trimmed[0] == '[' {
+		if err := dec.Decode(&comments); err != nil {
+			return nil, fmt.Errorf("parse comments file: %w", err)
+		}
+	} else {
+		// Other pending review fields (workspace, pr, ...) are tolerated
+		var wrapper struct {
+			Comments []json.RawMessage `json:"comments"`
+		}
+		if err := json.Unmarshal(trimmed, &wrapper); err != nil {
+			return nil, fmt.Errorf("parse comments file: %w", err)
+		}
+		for i, raw := range wrapper.Comments {
+			var c pendingComment
+			d := json.NewDecoder(bytes.NewReader(raw))
+			d.DisallowUnknownFields()
+			if err := d.Decode(&c); err != nil {
+				return nil, fmt.Errorf("parse comment %d: %w", i+1, err)
+			}
+			comments = append(comments, c)
+		}
+	}
+	if len(comments) == 0 {
+		return nil, fmt.Errorf("comments file contains no comments")
+	}
+	return comments, nil
+}
+
+func hasInlineComments(comments []pendingComment) bool {
+	for _, c := range comments {
+		if c.File != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// diffPaths collects the old and new paths of every file in the PR diff
+func diffPaths(diffstats []bbcloud.FileStats) map[string]bool {
+	paths := make(map[string]bool, len(diffstats))
+	for _, stat := range diffstats {
+		if stat.Old != nil {
+			paths[stat.Old.Path] = true
+		}
+		if stat.New != nil {
+			paths[stat.New.Path] = true
+		}
+		if stat.Path != "" {
+			paths[stat.Path] = true
+		}
+	}
+	return paths
+}
+
+// validateImportComments checks every comment and returns one message per
+// problem, numbered by position in the file
+func validateImportComments(comments []pendingComment, paths map[string]bool) []string {
+	var errs []string
+	for i, c := range comments {
+		fail := func(format string, args ...any) {
+			errs = append(errs, fmt.Sprintf("comment %d: ", i+1)+fmt.Sprintf(format, args...))
+		}
+		if strings.TrimSpace(c.Message) == "" {
+			fail("message is empty")
+		}
+		switch {
+		case c.File == "" && (c.LineStart != 0 || c.LineEnd != 0):
+			fail("line numbers given without a file")
+		case c.File == "":
+		case c.LineStart <= 0:
+			fail("inline comment on %s needs a positive line_start", c.File)
+		case c.LineEnd != 0 && c.LineEnd < c.LineStart:
+			fail("line_end %d is before line_start %d", c.LineEnd, c.LineStart)
+		case !paths[c.File]:
+			fail("%s is not part of the PR diff", c.File)
+		}
+	}
+	return errs
+}
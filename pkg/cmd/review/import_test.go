@@ -0,0 +1,66 @@
+package review
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+)
+
+func TestParseImportComments(t *testing.T) {
+	want := []pendingComment{
+		{Message: "General"},
+		{Message: "Inline", File: "a.go", LineStart: 3, LineEnd: 5},
+	}
+
+	got, err := parseImportComments([]byte(`[
+		{"message": "General"},
+		{"message": "Inline", "file": "a.go", "line_start": 3, "line_end": 5}
+	]`))
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("array: got %+v, %v", got, err)
+	}
+
+	got, err = parseImportComments([]byte(`{"workspace": "ws", "pr": 1, "comments": [
+		{"message": "General"},
+		{"message": "Inline", "file": "a.go", "line_start": 3, "line_end": 5}
+	]}`))
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("pending review: got %+v, %v", got, err)
+	}
+
+	for _, bad := range []string{``, `[]`, `[{"message": "x", "line": 3}]`, `{"comments": [{"body": "x"}]}`, `not json`} {
+		if _, err := parseImportComments([]byte(bad)); err == nil {
+			t.Errorf("parseImportComments(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestValidateImportComments(t *testing.T) {
+	paths := diffPaths([]bbcloud.FileStats{
+		{New: &bbcloud.FileInfo{Path: "a.go"}},
+		{Old: &bbcloud.FileInfo{Path: "old.go"}, New: &bbcloud.FileInfo{Path: "new.go"}},
+	})
+	comments := []pendingComment{
+		{Message: "ok"},
+		{Message: "ok", File: "a.go", LineStart: 1},
+		{Message: "ok", File: "old.go", LineStart: 2, LineEnd: 4},
+		{Message: "  "},
+		{Message: "x", LineStart: 3},
+		{Message: "x", File: "a.go"},
+		{Message: "x", File: "a.go", LineStart: 5, LineEnd: 2},
+		{Message: "x", File: "missing.go", LineStart: 1},
+	}
+
+	errs := validateImportComments(comments, paths)
+	wantPrefixes := []string{"comment 4:", "comment 5:", "comment 6:", "comment 7:", "comment 8:"}
+	if len(errs) != len(wantPrefixes) {
+		t.Fatalf("got %d errors, want %d: %q", len(errs), len(wantPrefixes), errs)
+	}
+	for i, prefix := range wantPrefixes {
+		if !strings.HasPrefix(errs[i], prefix) {
+			t.Errorf("error %d = %q, want prefix %q", i, errs[i], prefix)
+		}
+	}
+}
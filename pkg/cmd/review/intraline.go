@@ -0,0 +1,115 @@
+package review
+
+import (
+	"unicode"
+
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+// segment is a run of text within a line; changed marks text that differs
+// from the paired line
+type segment struct {
+	text    string
+	changed bool
+}
+
+// maxIntraLineCells bounds the token LCS table so pathological lines don't
+// slow rendering; such pairs are shown without intra-line highlights
+const maxIntraLineCells = 40000
+
+// intraLineDiff splits a removed/added line pair into segments, marking the
+// tokens that differ. Lines with nothing in common get no highlights, since
+// marking every token adds noise rather than signal.
+func intraLineDiff(oldText, newText string) ([]segment, []segment) {
+	a, b := tokenize(oldText), tokenize(newText)
+	if len(a)*len(b) > maxIntraLineCells {
+		return []segment{{text: oldText}}, []segment{{text: newText}}
+	}
+
+	// Longest common subsequence of tokens
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	if lcs[0][0] == 0 {
+		return []segment{{text: oldText}}, []segment{{text: newText}}
+	}
+
+	var oldSegs, newSegs []segment
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			oldSegs = appendSegment(oldSegs, a[i], false)
+			newSegs = appendSegment(newSegs, b[j], false)
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			newSegs = appendSegment(newSegs, b[j], true)
+			j++
+		default:
+			oldSegs = appendSegment(oldSegs, a[i], true)
+			i++
+		}
+	}
+	return oldSegs, newSegs
+}
+
+// appendSegment adds text, merging it into the previous segment when the
+// changed state matches
+func appendSegment(segs []segment, text string, changed bool) []segment {
+	if n := len(segs); n > 0 && segs[n-1].changed == changed {
+		segs[n-1].text += text
+		return segs
+	}
+	return append(segs, segment{text: text, changed: changed})
+}
+
+// tokenize splits s into words, whitespace runs and single punctuation marks
+func tokenize(s string) []string {
+	var tokens []string
+	start := 0
+	class := -1
+	for i, r := range s {
+		c := runeClass(r)
+		if i > start && (c != class || c == 2) {
+			tokens = append(tokens, s[start:i])
+			start = i
+		}
+		class = c
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+// runeClass groups runes for tokenizing: 0 word, 1 space, 2 punctuation
+func runeClass(r rune) int {
+	switch {
+	case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+		return 0
+	case unicode.IsSpace(r):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// segmentsWidth returns the terminal columns segs occupy
+func segmentsWidth(segs []segment) int {
+	n := 0
+	for _, s := range segs {
+		n += text.Width(s.text)
+	}
+	return n
+}
@@ -0,0 +1,65 @@
+package review
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestIntraLineDiff(t *testing.T) {
+	oldSegs, newSegs := intraLineDiff("return fmt.Errorf(\"get user: %w\", err)", "return fmt.Errorf(\"fetch user: %w\", err)")
+
+	if got := changedText(oldSegs); got != "get" {
+		t.Errorf("old changed = %q, want %q", got, "get")
+	}
+	if got := changedText(newSegs); got != "fetch" {
+		t.Errorf("new changed = %q, want %q", got, "fetch")
+	}
+	if joinSegments(oldSegs) != "return fmt.Errorf(\"get user: %w\", err)" {
+		t.Error("segments must reassemble the original line")
+	}
+
+	// Nothing in common: no highlights
+	oldSegs, newSegs = intraLineDiff("alpha", "beta")
+	if changedText(oldSegs) != "" || changedText(newSegs) != "" {
+		t.Error("unrelated lines should not be highlighted")
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	got := strings.Join(tokenize("a.b(c_d,  e)"), "|")
+	if want := "a|.|b|(|c_d|,|  |e|)"; got != want {
+		t.Errorf("tokenize = %q, want %q", got, want)
+	}
+}
+
+func TestRenderSplitDiffHighlightsChangedTokens(t *testing.T) {
+	var buf bytes.Buffer
+	renderSplitDiff(&buf, "@@ -1 +1 @@\n-x := 1\n+x := 2\n", 60, true)
+
+	out := buf.String()
+	if !strings.Contains(out, ansiReverse+"1"+ansiNoReverse) || !strings.Contains(out, ansiReverse+"2"+ansiNoReverse) {
+		t.Errorf("expected changed tokens to be highlighted: %q", out)
+	}
+	if strings.Contains(out, ansiReverse+"x") {
+		t.Errorf("unchanged tokens should not be highlighted: %q", out)
+	}
+}
+
+func changedText(segs []segment) string {
+	var b strings.Builder
+	for _, s := range segs {
+		if s.changed {
+			b.WriteString(s.text)
+		}
+	}
+	return b.String()
+}
+
+func joinSegments(segs []segment) string {
+	var b strings.Builder
+	for _, s := range segs {
+		b.WriteString(s.text)
+	}
+	return b.String()
+}
@@ -4,13 +4,18 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 	"sync"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/ghoseb/bb/pkg/bbcloud"
 	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/text"
 )
 
 type listOptions struct {
@@ -19,6 +24,15 @@ type listOptions struct {
 	limit int
 	json  bool
 
+	maxFiles int
+
+	filters listFilters
+	sort    string
+	order   string
+
+	mine          bool
+	needsMyReview bool
+
 	factory *cmdutil.Factory
 	client  *bbcloud.Client
 }
@@ -34,9 +48,15 @@ func NewCmdList(f *cmdutil.Factory) *cobra.Command {
 		Short: "List pull requests with review stats",
 		Long: `List pull requests with token-efficient output for agent review.
 
-Requires --repo flag to specify the repository.
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+Includes file counts, line changes, and reviewer approval status. Stats
+are read for at most --max-files files per PR (1000 by default); a PR with
+more shows "N of M" files, its line changes counting the first N.
 
-Includes file counts, line changes, and reviewer approval status.
+Filters are applied by Bitbucket (the API's q= parameter), so large
+repositories are narrowed before anything is downloaded. --author and
+--reviewer take a nickname, an account ID, or a {UUID}.
 
 Examples:
   # List open PRs in a repository
@@ -46,8 +66,37 @@ Examples:
   bbc review list --repo test_repo --state MERGED
 
   # List more PRs
-  bbc review list --repo test_repo --limit 50`,
+  bbc review list --repo test_repo --limit 50
+
+  # Narrow server-side: open PRs into main touched in the last week
+  bbc review list --repo test_repo --target main --updated-since 7d
+
+  # Daily triage: my PRs, and PRs waiting on my approval
+  bbc review list --repo test_repo --mine
+  bbc review list --repo test_repo --needs-my-review
+
+  # Oldest first, for triage
+  bbc review list --repo test_repo --sort created --order asc
+
+  # Raw Bitbucket query language, ANDed with the other filters
+  bbc review list --repo test_repo --query 'title ~ "hotfix"'`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, ok := listSortFields[opts.sort]; !ok {
+				return fmt.Errorf("invalid --sort %q (allowed: created, updated, id)", opts.sort)
+			}
+			if opts.order != "asc" && opts.order != "desc" {
+				return fmt.Errorf("invalid --order %q (allowed: asc, desc)", opts.order)
+			}
+			if opts.maxFiles < 0 {
+				return fmt.Errorf("--max-files must not be negative")
+			}
+			if opts.mine && opts.filters.author != "" {
+				return fmt.Errorf("--mine cannot be combined with --author")
+			}
+			if opts.needsMyReview && opts.filters.reviewer != "" {
+				return fmt.Errorf("--needs-my-review cannot be combined with --reviewer")
+			}
+
 			client, err := opts.factory.NewBBCloudClient("")
 			if err != nil {
 				return err
@@ -57,15 +106,52 @@ Examples:
 		},
 	}
 
-	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (required)")
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
 	cmd.Flags().StringVar(&opts.state, "state", "OPEN", "PR state (OPEN, MERGED, DECLINED)")
 	cmd.Flags().IntVar(&opts.limit, "limit", 20, "Maximum number of PRs to list")
-	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of markdown")
-	_ = cmd.MarkFlagRequired("repo")
+	cmd.Flags().IntVar(&opts.maxFiles, "max-files", defaultMaxFiles, "Read stats for at most this many files per PR (0 for no limit)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of markdown (or a table with format: table)")
+	cmd.Flags().StringVar(&opts.sort, "sort", "updated", "Sort by created, updated or id")
+	cmd.Flags().StringVar(&opts.order, "order", "desc", "Sort order: asc or desc")
+	cmd.Flags().StringVar(&opts.filters.author, "author", "", "Only PRs by this user (nickname, account ID or {UUID})")
+	cmd.Flags().StringVar(&opts.filters.reviewer, "reviewer", "", "Only PRs with this reviewer (nickname, account ID or {UUID})")
+	cmd.Flags().StringVar(&opts.filters.source, "source", "", "Only PRs from this source branch")
+	cmd.Flags().StringVar(&opts.filters.target, "target", "", "Only PRs into this target branch")
+	cmd.Flags().StringVar(&opts.filters.updatedSince, "updated-since", "", "Only PRs updated since a date, timestamp or age (e.g. 2026-01-31, 7d, 36h)")
+	cmd.Flags().BoolVar(&opts.mine, "mine", false, "Only PRs authored by you")
+	cmd.Flags().BoolVar(&opts.needsMyReview, "needs-my-review", false, "Only PRs where you are a reviewer and have not approved")
+	cmd.Flags().StringVar(&opts.filters.query, "query", "", "Raw Bitbucket query language filter, ANDed with the others")
 
 	return cmd
 }
 
+// listSortFields maps --sort values to API sort fields
+var listSortFields = map[string]string{
+	"created": "created_on",
+	"updated": "updated_on",
+	"id":      "id",
+}
+
+// sortParam returns the API sort parameter, "-" prefixed for descending order
+func (opts *listOptions) sortParam() string {
+	field := listSortFields[opts.sort]
+	if opts.order == "desc" {
+		return "-" + field
+	}
+	return field
+}
+
+// awaitingReview keeps the PRs that user has not approved yet
+func awaitingReview(prs []bbcloud.PullRequest, user *bbcloud.User) []bbcloud.PullRequest {
+	var out []bbcloud.PullRequest
+	for _, pr := range prs {
+		if !pr.ApprovedBy(user.UUID) {
+			out = append(out, pr)
+		}
+	}
+	return out
+}
+
 type prListItem struct {
 	ID        int    `json:"id"`
 	Title     string `json:"title"`
@@ -80,6 +166,10 @@ type prListItem struct {
 	Deletions int    `json:"deletions"`
 	Approved  int    `json:"approved"`
 	Declined  int    `json:"declined"`
+	Stack     *stackLink `json:"stack,omitempty"`
+
+	// FilesTruncated marks stats that count only the first --max-files files
+	FilesTruncated *fileTruncation `json:"files_truncated,omitempty"`
 }
 
 type listOutput struct {
@@ -87,11 +177,58 @@ type listOutput struct {
 }
 
 func runList(ctx context.Context, opts *listOptions) error {
-	// Fetch PRs from Bitbucket
-	prs, err := opts.client.ListPullRequests(ctx, opts.repo, opts.state, opts.limit)
+	// --mine and --needs-my-review are author/reviewer filters for the current user
+	var me *bbcloud.User
+	if opts.mine || opts.needsMyReview {
+		var err error
+		if me, err = opts.client.CurrentUser(ctx); err != nil {
+			return fmt.Errorf("get current user: %w", err)
+		}
+		if opts.mine {
+			opts.filters.author = me.UUID
+		}
+		if opts.needsMyReview {
+			opts.filters.reviewer = me.UUID
+		}
+	}
+
+	// Fetch PRs from Bitbucket, filtered server-side when filters are set
+	listOpts := bbcloud.PRListOptions{State: opts.state, Sort: opts.sortParam(), Limit: opts.limit}
+	if opts.needsMyReview {
+		// Approval is filtered client-side, so the limit applies afterwards
+		listOpts.Limit = 0
+	}
+	if opts.filters.active() {
+		query, err := opts.filters.buildQuery(opts.state, time.Now())
+		if err != nil {
+			return err
+		}
+		listOpts.State, listOpts.Query = "", query
+	}
+	prs, err := opts.client.QueryPullRequests(ctx, opts.repo, listOpts)
 	if err != nil {
 		return fmt.Errorf("list pull requests: %w", err)
 	}
+	if opts.needsMyReview {
+		prs = awaitingReview(prs, me)
+		if opts.limit > 0 && len(prs) > opts.limit {
+			prs = prs[:opts.limit]
+		}
+	}
+
+	ios, _ := opts.factory.Streams()
+
+	// Stacks are detected among open PRs; reuse the listing when it already
+	// holds all of them, else look up only the PRs next to the listed ones
+	openPRs := prs
+	if opts.filters.active() || !strings.EqualFold(opts.state, "OPEN") || (opts.limit > 0 && len(prs) >= opts.limit) {
+		openPRs, err = fetchStackNeighbours(ctx, opts.client, opts.repo, prs)
+		if err != nil {
+			// Non-critical: list without stack info
+			_, _ = fmt.Fprintf(ios.ErrOut, "warning: failed to fetch open PRs for stack detection: %v\n", err)
+		}
+	}
+	stack := newPRStack(openPRs)
 
 	// Transform to agent-optimized format
 	items := make([]prListItem, len(prs))
@@ -123,23 +260,22 @@ func runList(ctx context.Context, opts *listOptions) error {
 			Deletions: 0, // Will be populated below
 			Approved:  approved,
 			Declined:  declined,
+			Stack:     stack.link(&prs[i]),
 		}
 	}
 
-	// Fetch diffstats concurrently with rate limiting (max 5 concurrent)
-	sem := make(chan struct{}, 5)
+	// Fetch diffstats concurrently, bounded by --concurrency
+	sem := make(chan struct{}, opts.factory.Concurrency())
 	g, gctx := errgroup.WithContext(ctx)
 	var mu sync.Mutex
 
-	ios, _ := opts.factory.Streams()
-
 	for i := range items {
 		i := i // capture loop variable
 		sem <- struct{}{} // acquire semaphore
 		g.Go(func() error {
 			defer func() { <-sem }() // release semaphore
 
-			diffstats, err := opts.client.GetPRDiffStats(gctx, opts.repo, items[i].ID)
+			diffstats, totalFiles, err := opts.client.ListPRDiffStats(gctx, opts.repo, items[i].ID, opts.maxFiles)
 			if err != nil {
 				// Non-critical: log warning and continue
 				_, _ = fmt.Fprintf(ios.ErrOut, "warning: failed to fetch stats for PR %d: %v\n", items[i].ID, err)
@@ -147,7 +283,6 @@ func runList(ctx context.Context, opts *listOptions) error {
 			}
 
 			// Calculate totals
-			totalFiles := len(diffstats)
 			totalAdds := 0
 			totalDels := 0
 			for _, stat := range diffstats {
@@ -160,6 +295,7 @@ func runList(ctx context.Context, opts *listOptions) error {
 			items[i].Files = totalFiles
 			items[i].Additions = totalAdds
 			items[i].Deletions = totalDels
+			items[i].FilesTruncated = truncateFiles(len(diffstats), totalFiles)
 			mu.Unlock()
 
 			return nil
@@ -184,10 +320,45 @@ func runList(ctx context.Context, opts *listOptions) error {
 		return nil
 	}
 
+	if opts.factory.Format() == cmdutil.FormatTable {
+		return renderTableList(ios.Out, items, ios.TerminalWidth())
+	}
+
 	// Output markdown (default)
 	return renderMarkdownList(ios.Out, opts.repo, items)
 }
 
+// renderTableList prints aligned columns for terminal reading, shortening
+// titles so rows fit in width
+func renderTableList(w io.Writer, items []prListItem, width int) error {
+	rows := [][]string{{"PR", "TITLE", "AUTHOR", "FILES", "+/-"}}
+	for _, item := range items {
+		title := text.Line(item.Title)
+		if item.Stack != nil && item.Stack.Parent != 0 {
+			title = fmt.Sprintf("%s (stacked on #%d)", title, item.Stack.Parent)
+		}
+		rows = append(rows, []string{fmt.Sprintf("#%d", item.ID), title, text.Line(item.Author),
+			item.fileCount(), fmt.Sprintf("+%d/-%d", item.Additions, item.Deletions)})
+	}
+	titleWidth := cmdutil.FitColumn(width, rows, 1, 20)
+
+	tw := tabwriter.NewWriter(w, 0, 0, cmdutil.TablePadding, ' ', 0)
+	for _, row := range rows {
+		row[1] = cmdutil.Truncate(row[1], titleWidth)
+		_, _ = fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// fileCount returns the FILES cell: the count, or "N of M" when only the
+// first N files' stats were read
+func (item prListItem) fileCount() string {
+	if t := item.FilesTruncated; t != nil {
+		return fmt.Sprintf("%d of %d", t.Shown, t.Total)
+	}
+	return strconv.Itoa(item.Files)
+}
+
 func renderMarkdownList(w io.Writer, repo string, items []prListItem) error {
 	if len(items) == 0 {
 		_, _ = fmt.Fprintf(w, "# No PRs found — %s\n", repo)
@@ -204,12 +375,17 @@ func renderMarkdownList(w io.Writer, repo string, items []prListItem) error {
 		// We don't have build status in the current data structure
 		// This will be added when available
 		
-		_, _ = fmt.Fprintf(w, "| %d | %s | %s | %s | %d | +%d/-%d |\n",
+		title := text.Line(item.Title)
+		if item.Stack != nil && item.Stack.Parent != 0 {
+			title = fmt.Sprintf("%s (stacked on #%d)", title, item.Stack.Parent)
+		}
+
+		_, _ = fmt.Fprintf(w, "| %d | %s | %s | %s | %s | +%d/-%d |\n",
 			item.ID,
-			item.Title,
-			item.Author,
+			title,
+			text.Line(item.Author),
 			buildStatus,
-			item.Files,
+			item.fileCount(),
 			item.Additions,
 			item.Deletions,
 		)
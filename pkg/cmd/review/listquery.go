@@ -0,0 +1,58 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// listFilters are the review list flags translated into a BBQL query
+type listFilters struct {
+	author       string
+	reviewer     string
+	source       string
+	target       string
+	updatedSince string
+	query        string
+}
+
+// active reports whether any filter is set
+func (f listFilters) active() bool {
+	return f.author != "" || f.reviewer != "" || f.source != "" || f.target != "" ||
+		f.updatedSince != "" || f.query != ""
+}
+
+// buildQuery combines state and the filters into one BBQL expression; a raw
+// --query is ANDed in parentheses
+func (f listFilters) buildQuery(state string, now time.Time) (string, error) {
+	var terms []string
+	if state != "" {
+		terms = append(terms, fmt.Sprintf(`state = "%s"`, bbcloud.EscapeQueryString(strings.ToUpper(state))))
+	}
+	if f.author != "" {
+		terms = append(terms, bbcloud.UserQueryTerm("author", f.author))
+	}
+	if f.reviewer != "" {
+		terms = append(terms, bbcloud.UserQueryTerm("reviewers", f.reviewer))
+	}
+	if f.source != "" {
+		terms = append(terms, fmt.Sprintf(`source.branch.name = "%s"`, bbcloud.EscapeQueryString(f.source)))
+	}
+	if f.target != "" {
+		terms = append(terms, fmt.Sprintf(`destination.branch.name = "%s"`, bbcloud.EscapeQueryString(f.target)))
+	}
+	if f.updatedSince != "" {
+		since, err := cmdutil.ParseSince(f.updatedSince, now)
+		if err != nil {
+			return "", fmt.Errorf("invalid --updated-since: %w", err)
+		}
+		terms = append(terms, "updated_on >= "+since.UTC().Format(time.RFC3339))
+	}
+	if f.query != "" {
+		terms = append(terms, "("+f.query+")")
+	}
+	return strings.Join(terms, " AND "), nil
+}
@@ -0,0 +1,67 @@
+package review
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+)
+
+func TestListFiltersBuildQuery(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	f := listFilters{
+		author:       "alice",
+		reviewer:     "{d5b1c7e2-0000-0000-0000-000000000000}",
+		target:       "main",
+		updatedSince: "7d",
+		query:        `title ~ "fix"`,
+	}
+	got, err := f.buildQuery("open", now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `state = "OPEN" AND author.nickname = "alice" AND reviewers.uuid = "{d5b1c7e2-0000-0000-0000-000000000000}"` +
+		` AND destination.branch.name = "main" AND updated_on >= 2026-03-03T12:00:00Z AND (title ~ "fix")`
+	if got != want {
+		t.Errorf("buildQuery() =\n%s\nwant\n%s", got, want)
+	}
+
+	got, _ = listFilters{author: "557058:abc", source: `feat/"x"`}.buildQuery("", now)
+	if want := `author.account_id = "557058:abc" AND source.branch.name = "feat/\"x\""`; got != want {
+		t.Errorf("buildQuery() = %s, want %s", got, want)
+	}
+
+	if _, err := (listFilters{updatedSince: "last week"}).buildQuery("", now); err == nil {
+		t.Error("expected error for invalid --updated-since")
+	}
+}
+
+func TestListSortParam(t *testing.T) {
+	tests := []struct{ sort, order, want string }{
+		{"updated", "desc", "-updated_on"},
+		{"created", "asc", "created_on"},
+		{"id", "desc", "-id"},
+	}
+	for _, tt := range tests {
+		opts := &listOptions{sort: tt.sort, order: tt.order}
+		if got := opts.sortParam(); got != tt.want {
+			t.Errorf("sortParam(%s, %s) = %q, want %q", tt.sort, tt.order, got, tt.want)
+		}
+	}
+}
+
+func TestAwaitingReview(t *testing.T) {
+	me := &bbcloud.User{UUID: "{me}"}
+	other := &bbcloud.User{UUID: "{other}"}
+	prs := []bbcloud.PullRequest{
+		{ID: 1, Participants: []bbcloud.Participant{{User: me, Role: "REVIEWER", Approved: true}}},
+		{ID: 2, Participants: []bbcloud.Participant{{User: me, Role: "REVIEWER"}, {User: other, Approved: true}}},
+		{ID: 3, Participants: []bbcloud.Participant{{User: me, Role: "REVIEWER", State: "changes_requested"}}},
+	}
+
+	got := awaitingReview(prs, me)
+	if len(got) != 2 || got[0].ID != 2 || got[1].ID != 3 {
+		t.Errorf("awaitingReview() = %v, want PRs 2 and 3", got)
+	}
+}
@@ -0,0 +1,162 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+type localDiffOptions struct {
+	repo     string
+	prNumber int
+	remote   string
+	json     bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdLocalDiff creates the review local-diff command
+func NewCmdLocalDiff(f *cmdutil.Factory) *cobra.Command {
+	opts := &localDiffOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "local-diff <pr-number>",
+		Short: "Compare the local working tree against a PR",
+		Long: `Compare the local working tree against the PR's source commit.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+Must be run inside a local clone of the repository.
+
+Reports every file whose local contents differ from what was pushed to
+the PR, including uncommitted and untracked files, so reviewers can verify
+they are testing exactly the code under review.
+
+Examples:
+  bbc review local-diff 450 --repo test_repo
+  bbc review local-diff 450 --repo test_repo --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.factory.NewBBCloudClient("")
+			if err != nil {
+				return err
+			}
+
+			prNum, err := parsePRNumber(args[0])
+			if err != nil {
+				return err
+			}
+			opts.prNumber = prNum
+
+			return runLocalDiff(cmd.Context(), opts, client)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+	cmd.Flags().StringVar(&opts.remote, "remote", "origin", "Git remote to fetch the PR commit from if missing locally")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of markdown")
+
+	return cmd
+}
+
+type localDiffFile struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+}
+
+type localDiffOutput struct {
+	PR          int             `json:"pr"`
+	Repo        string          `json:"repo"`
+	PRCommit    string          `json:"pr_commit"`
+	LocalCommit string          `json:"local_commit"`
+	Matches     bool            `json:"matches"`
+	Files       []localDiffFile `json:"files"`
+}
+
+func runLocalDiff(ctx context.Context, opts *localDiffOptions, client *bbcloud.Client) error {
+	pr, err := client.GetPullRequest(ctx, opts.repo, opts.prNumber)
+	if err != nil {
+		return fmt.Errorf("get pull request: %w", err)
+	}
+	if pr.Source == nil || pr.Source.Commit == nil || pr.Source.Commit.Hash == "" {
+		return fmt.Errorf("PR %d has no source commit", opts.prNumber)
+	}
+	if pr.Source.Branch == nil || pr.Source.Branch.Name == "" {
+		return fmt.Errorf("PR %d has no source branch", opts.prNumber)
+	}
+
+	gitClient := opts.factory.GitClient
+	prCommit := pr.Source.Commit.Hash
+
+	// The PR commit may not be present locally yet
+	if !gitClient.HasCommit(ctx, prCommit) {
+		remote := opts.remote
+		if isForkPR(pr) {
+			if remote, err = forkRemote(opts.factory, pr); err != nil {
+				return err
+			}
+		}
+		if _, err := gitClient.Fetch(ctx, remote, "refs/heads/"+pr.Source.Branch.Name); err != nil {
+			return fmt.Errorf("fetch PR branch: %w", err)
+		}
+		if !gitClient.HasCommit(ctx, prCommit) {
+			return fmt.Errorf("PR commit %s not found after fetch (branch may have moved)", prCommit)
+		}
+	}
+
+	head, err := gitClient.HeadCommit(ctx)
+	if err != nil {
+		return fmt.Errorf("read local HEAD: %w", err)
+	}
+
+	changes, err := gitClient.DiffWorkingTree(ctx, prCommit)
+	if err != nil {
+		return fmt.Errorf("diff working tree: %w", err)
+	}
+
+	files := make([]localDiffFile, 0, len(changes))
+	for _, c := range changes {
+		files = append(files, localDiffFile{Path: c.Path, Status: c.Status})
+	}
+
+	output := localDiffOutput{
+		PR:          opts.prNumber,
+		Repo:        opts.repo,
+		PRCommit:    prCommit,
+		LocalCommit: head,
+		Matches:     len(files) == 0,
+		Files:       files,
+	}
+
+	ios, _ := opts.factory.Streams()
+	if opts.json {
+		if err := cmdutil.WriteJSON(ios.Out, output); err != nil {
+			return fmt.Errorf("encode output: %w", err)
+		}
+		return nil
+	}
+
+	return renderMarkdownLocalDiff(ios.Out, output)
+}
+
+func renderMarkdownLocalDiff(w io.Writer, output localDiffOutput) error {
+	_, _ = fmt.Fprintf(w, "# PR %d — local vs %s\n", output.PR, output.PRCommit)
+	_, _ = fmt.Fprintf(w, "Local HEAD: %s\n", output.LocalCommit)
+
+	if output.Matches {
+		_, _ = fmt.Fprintf(w, "\nWorking tree matches the PR source commit.\n")
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(w, "\n## Differing files (%d)\n", len(output.Files))
+	for _, f := range output.Files {
+		_, _ = fmt.Fprintf(w, "- %s (%s)\n", text.Line(f.Path), f.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package review
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/bbtest"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/iostreams"
+)
+
+func TestLocalDiffWithoutSourceBranch(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	srv := bbtest.NewServer(t)
+	srv.AddRepository("acme", bbcloud.Repository{Slug: "api"})
+	srv.AddPullRequest("acme", "api", bbcloud.PullRequest{
+		Title:  "Deleted branch",
+		Source: &bbcloud.PullRequestBranch{Commit: &bbcloud.CommitReference{Hash: "abc123"}},
+	})
+
+	f := cmdutil.NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}})
+	opts := &localDiffOptions{repo: "api", prNumber: 1, remote: "origin", factory: f}
+	err := runLocalDiff(context.Background(), opts, srv.Client(t, "acme"))
+	if err == nil || !strings.Contains(err.Error(), "no source branch") {
+		t.Fatalf("err = %v, want a missing source branch error", err)
+	}
+}
+
+func TestForkRemoteUsesHost(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("BB_CONFIG_DIR", dir)
+	t.Setenv("BB_HOST", "")
+	config := "hosts:\n  git.example.com:\n    api_url: https://git.example.com/api/2.0\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yml"), []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	pr := &bbcloud.PullRequest{Source: &bbcloud.PullRequestBranch{Repository: &bbcloud.Repository{FullName: "fork/api"}}}
+	f := cmdutil.NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}})
+	for host, want := range map[string]string{
+		"":                "https://bitbucket.org/fork/api.git",
+		"git.example.com": "https://git.example.com/fork/api.git",
+	} {
+		f.HostOverride = host
+		got, err := forkRemote(f, pr)
+		if err != nil || got != want {
+			t.Errorf("host %q: forkRemote = %q, %v, want %q", host, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+var (
+	// nameMention matches @name not preceded by a word character, so email
+	// addresses are left alone
+	nameMention = regexp.MustCompile(`(^|[^\w@{])@([\w.-]*\w)`)
+	// idMention matches Bitbucket's stored mention syntax, @{account-id or uuid}
+	idMention = regexp.MustCompile(`@\{([^{}\s]+)\}`)
+)
+
+// mentionIndex maps workspace members' names and IDs to users
+type mentionIndex struct {
+	byName map[string][]*bbcloud.User
+	byID   map[string]*bbcloud.User
+}
+
+// newMentionIndex indexes users by lower-cased username, nickname and display
+// name without spaces, and by account ID and brace-less UUID
+func newMentionIndex(users []bbcloud.User) *mentionIndex {
+	idx := &mentionIndex{
+		byName: make(map[string][]*bbcloud.User),
+		byID:   make(map[string]*bbcloud.User),
+	}
+	for i := range users {
+		u := &users[i]
+		seen := make(map[string]bool)
+		for _, name := range []string{u.Username, u.Nickname, strings.Join(strings.Fields(u.DisplayName), "")} {
+			key := strings.ToLower(name)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			idx.byName[key] = append(idx.byName[key], u)
+		}
+		if u.AccountID != "" {
+			idx.byID[u.AccountID] = u
+		}
+		if uuid := strings.Trim(u.UUID, "{}"); uuid != "" {
+			idx.byID[uuid] = u
+		}
+	}
+	return idx
+}
+
+// mentionID returns the identifier Bitbucket expects inside @{...}: the account
+// ID, or the UUID for users without one
+func mentionID(u *bbcloud.User) string {
+	if u.AccountID != "" {
+		return u.AccountID
+	}
+	return strings.Trim(u.UUID, "{}")
+}
+
+// encode rewrites @name mentions of workspace members to @{id}. Unknown names
+// are left untouched (they may be code, e.g. @Override); names matching several
+// members are returned as ambiguous.
+func (idx *mentionIndex) encode(text string) (string, []string) {
+	var ambiguous []string
+	out := nameMention.ReplaceAllStringFunc(text, func(m string) string {
+		sub := nameMention.FindStringSubmatch(m)
+		users := idx.byName[strings.ToLower(sub[2])]
+		switch len(users) {
+		case 1:
+			return sub[1] + "@{" + mentionID(users[0]) + "}"
+		case 0:
+			return m
+		default:
+			ambiguous = append(ambiguous, sub[2])
+			return m
+		}
+	})
+	return out, ambiguous
+}
+
+// decode rewrites @{id} mentions to @nickname (or display name) for reading
+func (idx *mentionIndex) decode(text string) string {
+	return idMention.ReplaceAllStringFunc(text, func(m string) string {
+		u := idx.byID[strings.Trim(idMention.FindStringSubmatch(m)[1], "{}")]
+		if u == nil {
+			return m
+		}
+		switch {
+		case u.Nickname != "":
+			return "@" + u.Nickname
+		case u.Username != "":
+			return "@" + u.Username
+		default:
+			return "@" + u.DisplayName
+		}
+	})
+}
+
+// resolveMentions translates @name mentions in a message before posting. The
+// member list is only fetched when the message contains an @; lookup failures
+// leave the message unchanged with a warning.
+func resolveMentions(ctx context.Context, f *cmdutil.Factory, client *bbcloud.Client, message string) string {
+	if !strings.Contains(message, "@") {
+		return message
+	}
+	members, err := client.ListWorkspaceMembers(ctx)
+	if err != nil {
+		_, _ = fmt.Fprintf(f.IOStreams.ErrOut, "warning: failed to resolve @mentions: %v\n", err)
+		return message
+	}
+
+	encoded, ambiguous := newMentionIndex(members).encode(message)
+	for _, name := range ambiguous {
+		_, _ = fmt.Fprintf(f.IOStreams.ErrOut, "warning: @%s matches several workspace members; left unresolved\n", name)
+	}
+	return encoded
+}
+
+// readableMentions rewrites @{id} mentions in comment bodies to member names.
+// The member list is only fetched when some comment contains a mention.
+func readableMentions(ctx context.Context, f *cmdutil.Factory, client *bbcloud.Client, comments []bbcloud.Comment) []bbcloud.Comment {
+	found := false
+	for _, c := range comments {
+		if c.Content != nil && idMention.MatchString(c.Content.Raw) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return comments
+	}
+
+	members, err := client.ListWorkspaceMembers(ctx)
+	if err != nil {
+		_, _ = fmt.Fprintf(f.IOStreams.ErrOut, "warning: failed to resolve @mentions: %v\n", err)
+		return comments
+	}
+	idx := newMentionIndex(members)
+
+	out := make([]bbcloud.Comment, len(comments))
+	for i, c := range comments {
+		if c.Content != nil {
+			content := *c.Content
+			content.Raw = idx.decode(content.Raw)
+			c.Content = &content
+		}
+		out[i] = c
+	}
+	return out
+}
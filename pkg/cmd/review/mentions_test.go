@@ -0,0 +1,29 @@
+package review
+
+import (
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+)
+
+func TestMentionIndex(t *testing.T) {
+	idx := newMentionIndex([]bbcloud.User{
+		{UUID: "{u-1}", AccountID: "557058:aaa", Nickname: "alice", DisplayName: "Alice Smith"},
+		{UUID: "{u-2}", Nickname: "bob", DisplayName: "Bob Jones"},
+		{UUID: "{u-3}", AccountID: "557058:ccc", Nickname: "sam", DisplayName: "Sam Lee"},
+		{UUID: "{u-4}", AccountID: "557058:ddd", Nickname: "sam2", DisplayName: "Sam Lee"},
+	})
+
+	got, ambiguous := idx.encode("@alice and @AliceSmith, cc @bob. @SamLee? mail a@alice.com, @Override")
+	want := "@{557058:aaa} and @{557058:aaa}, cc @{u-2}. @SamLee? mail a@alice.com, @Override"
+	if got != want {
+		t.Errorf("encode =\n%q\nwant\n%q", got, want)
+	}
+	if len(ambiguous) != 1 || ambiguous[0] != "SamLee" {
+		t.Errorf("ambiguous = %v", ambiguous)
+	}
+
+	if got := idx.decode("thanks @{557058:aaa} and @{u-2}, not @{unknown}"); got != "thanks @alice and @bob, not @{unknown}" {
+		t.Errorf("decode = %q", got)
+	}
+}
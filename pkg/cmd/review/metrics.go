@@ -0,0 +1,302 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+type metricsOptions struct {
+	repo  string
+	since string
+	limit int
+	json  bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdMetrics creates the review metrics command
+func NewCmdMetrics(f *cmdutil.Factory) *cobra.Command {
+	opts := &metricsOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "metrics",
+		Short: "Report review health metrics for merged PRs",
+		Long: `Report review metrics for pull requests merged in a time window:
+cycle time (opened to merged), time to first review, size, and approvals.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+The first review is the earliest approval, change request or comment by
+someone other than the author. Each PR costs two extra API calls (activity
+and diffstat), so --limit caps how many merged PRs are examined.
+
+Examples:
+  bbc review metrics --repo test_repo --since 30d
+  bbc review metrics --repo test_repo --since 2026-01-01 --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.limit < 0 {
+				return fmt.Errorf("--limit must not be negative")
+			}
+
+			client, err := opts.factory.NewBBCloudClient("")
+			if err != nil {
+				return err
+			}
+			return runMetrics(cmd.Context(), opts, client)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+	cmd.Flags().StringVar(&opts.since, "since", "30d", "Only PRs merged since a date, timestamp or age (e.g. 2026-01-31, 30d)")
+	cmd.Flags().IntVar(&opts.limit, "limit", 100, "Maximum number of merged PRs to examine (0 for all)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of markdown")
+
+	return cmd
+}
+
+// prMetrics are the measurements for one merged PR; durations are in hours
+type prMetrics struct {
+	ID               int      `json:"id"`
+	Title            string   `json:"title"`
+	Author           string   `json:"author"`
+	CycleHours       float64  `json:"cycle_hours"`
+	FirstReviewHours *float64 `json:"first_review_hours"` // nil when merged without review
+	Files            int      `json:"files"`
+	LinesChanged     int      `json:"lines_changed"`
+	Approvals        int      `json:"approvals"`
+}
+
+// durationStats summarises a set of durations in hours
+type durationStats struct {
+	Median float64 `json:"median"`
+	Mean   float64 `json:"mean"`
+}
+
+type metricsOutput struct {
+	Repo              string         `json:"repo"`
+	Since             string         `json:"since"`
+	Merged            int            `json:"merged"`
+	CycleTime         *durationStats `json:"cycle_time_hours"`
+	TimeToFirstReview *durationStats `json:"time_to_first_review_hours"`
+	Unreviewed        int            `json:"unreviewed"` // merged without any review
+	AvgFiles          float64        `json:"avg_files"`
+	AvgLinesChanged   float64        `json:"avg_lines_changed"`
+	AvgApprovals      float64        `json:"avg_approvals"`
+	PRs               []prMetrics    `json:"prs"`
+}
+
+func runMetrics(ctx context.Context, opts *metricsOptions, client *bbcloud.Client) error {
+	since, err := cmdutil.ParseSince(opts.since, time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+
+	// A merged PR's updated_on is at or after its merge, so this bounds the window server-side
+	query := fmt.Sprintf(`state = "MERGED" AND updated_on >= %s`, since.UTC().Format(time.RFC3339))
+	prs, err := client.SearchPullRequests(ctx, opts.repo, query, opts.limit)
+	if err != nil {
+		return fmt.Errorf("list merged pull requests: %w", err)
+	}
+
+	ios, _ := opts.factory.Streams()
+	metrics := make([]*prMetrics, len(prs))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(opts.factory.Concurrency())
+	for i := range prs {
+		g.Go(func() error {
+			pr := &prs[i]
+			activities, err := client.GetPRActivity(gctx, opts.repo, pr.ID)
+			if err != nil {
+				return fmt.Errorf("get activity for PR %d: %w", pr.ID, err)
+			}
+			diffstats, err := client.GetPRDiffStats(gctx, opts.repo, pr.ID)
+			if err != nil {
+				// Non-critical: size is reported as zero
+				_, _ = fmt.Fprintf(ios.ErrOut, "warning: failed to fetch stats for PR %d: %v\n", pr.ID, err)
+			}
+			if m, ok := measurePR(pr, activities, diffstats, since); ok {
+				metrics[i] = &m
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	var measured []prMetrics
+	for _, m := range metrics {
+		if m != nil {
+			measured = append(measured, *m)
+		}
+	}
+	output := summarizeMetrics(measured)
+	output.Repo = opts.repo
+	output.Since = since.UTC().Format(time.RFC3339)
+
+	if opts.json {
+		if err := cmdutil.WriteJSON(ios.Out, output); err != nil {
+			return fmt.Errorf("encode output: %w", err)
+		}
+		return nil
+	}
+
+	return renderMarkdownMetrics(ios.Out, output)
+}
+
+// measurePR computes a merged PR's metrics from its activity. It reports
+// false when the PR was merged before since (it was only updated later).
+func measurePR(pr *bbcloud.PullRequest, activities []bbcloud.Activity, diffstats []bbcloud.FileStats, since time.Time) (prMetrics, bool) {
+	authorID := ""
+	m := prMetrics{ID: pr.ID, Title: pr.Title}
+	if pr.Author != nil {
+		authorID, m.Author = pr.Author.UUID, pr.Author.DisplayName
+	}
+
+	mergedAt := pr.UpdatedOn
+	var firstReview time.Time
+	review := func(at time.Time, user *bbcloud.User) {
+		if user != nil && user.UUID == authorID {
+			return
+		}
+		if firstReview.IsZero() || at.Before(firstReview) {
+			firstReview = at
+		}
+	}
+	approvers := make(map[string]bool)
+	for _, a := range activities {
+		switch {
+		case a.Update != nil && a.Update.State == "MERGED":
+			if a.Update.Date.Before(mergedAt) {
+				mergedAt = a.Update.Date
+			}
+		case a.Approval != nil:
+			review(a.Approval.Date, a.Approval.User)
+			if a.Approval.User != nil {
+				approvers[a.Approval.User.UUID] = true
+			}
+		case a.ChangesRequested != nil:
+			review(a.ChangesRequested.Date, a.ChangesRequested.User)
+		case a.Comment != nil && !a.Comment.Deleted:
+			review(a.Comment.CreatedOn, a.Comment.User)
+		}
+	}
+	if mergedAt.Before(since) {
+		return prMetrics{}, false
+	}
+
+	m.CycleHours = hours(mergedAt.Sub(pr.CreatedOn))
+	if !firstReview.IsZero() && !firstReview.After(mergedAt) {
+		h := hours(firstReview.Sub(pr.CreatedOn))
+		m.FirstReviewHours = &h
+	}
+	m.Approvals = len(approvers)
+	m.Files = len(diffstats)
+	for _, stat := range diffstats {
+		m.LinesChanged += stat.LinesAdded + stat.LinesRemoved
+	}
+	return m, true
+}
+
+// summarizeMetrics aggregates per-PR metrics
+func summarizeMetrics(prs []prMetrics) metricsOutput {
+	output := metricsOutput{Merged: len(prs), PRs: prs}
+	if output.PRs == nil {
+		output.PRs = make([]prMetrics, 0)
+	}
+	if len(prs) == 0 {
+		return output
+	}
+
+	var cycle, firstReview []float64
+	var files, lines, approvals int
+	for _, m := range prs {
+		cycle = append(cycle, m.CycleHours)
+		if m.FirstReviewHours != nil {
+			firstReview = append(firstReview, *m.FirstReviewHours)
+		} else {
+			output.Unreviewed++
+		}
+		files += m.Files
+		lines += m.LinesChanged
+		approvals += m.Approvals
+	}
+
+	n := float64(len(prs))
+	output.CycleTime = newDurationStats(cycle)
+	output.TimeToFirstReview = newDurationStats(firstReview)
+	output.AvgFiles = round1(float64(files) / n)
+	output.AvgLinesChanged = round1(float64(lines) / n)
+	output.AvgApprovals = round1(float64(approvals) / n)
+	return output
+}
+
+func newDurationStats(values []float64) *durationStats {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	}
+	sum := 0.0
+	for _, v := range sorted {
+		sum += v
+	}
+	return &durationStats{Median: round1(median), Mean: round1(sum / float64(len(sorted)))}
+}
+
+func hours(d time.Duration) float64 {
+	return round1(d.Hours())
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+func renderMarkdownMetrics(w io.Writer, output metricsOutput) error {
+	_, _ = fmt.Fprintf(w, "# Review metrics — %s since %s\n\n", output.Repo, output.Since)
+	if output.Merged == 0 {
+		_, _ = fmt.Fprintln(w, "No PRs merged in this window.")
+		return nil
+	}
+
+	formatStats := func(s *durationStats) string {
+		if s == nil {
+			return "n/a"
+		}
+		return fmt.Sprintf("median %.1fh, mean %.1fh", s.Median, s.Mean)
+	}
+	_, _ = fmt.Fprintf(w, "Merged PRs: %d (%d without review)\n", output.Merged, output.Unreviewed)
+	_, _ = fmt.Fprintf(w, "Cycle time: %s\n", formatStats(output.CycleTime))
+	_, _ = fmt.Fprintf(w, "Time to first review: %s\n", formatStats(output.TimeToFirstReview))
+	_, _ = fmt.Fprintf(w, "Average size: %.1f files, %.1f lines changed\n", output.AvgFiles, output.AvgLinesChanged)
+	_, _ = fmt.Fprintf(w, "Average approvals: %.1f\n", output.AvgApprovals)
+
+	_, _ = fmt.Fprintln(w, "\n| PR | Author | Cycle (h) | First review (h) | Files | Lines | Approvals |")
+	_, _ = fmt.Fprintln(w, "|---|---|---|---|---|---|---|")
+	for _, m := range output.PRs {
+		firstReview := "—"
+		if m.FirstReviewHours != nil {
+			firstReview = fmt.Sprintf("%.1f", *m.FirstReviewHours)
+		}
+		_, _ = fmt.Fprintf(w, "| #%d %s | %s | %.1f | %s | %d | %d | %d |\n",
+			m.ID, cmdutil.Truncate(text.Line(m.Title), 40), text.Line(m.Author), m.CycleHours, firstReview, m.Files, m.LinesChanged, m.Approvals)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package review
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+)
+
+func TestMeasurePR(t *testing.T) {
+	author := &bbcloud.User{UUID: "{author}", DisplayName: "Alice"}
+	reviewer := &bbcloud.User{UUID: "{reviewer}"}
+	opened := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	pr := &bbcloud.PullRequest{ID: 7, Title: "Fix", Author: author, CreatedOn: opened, UpdatedOn: opened.Add(48 * time.Hour)}
+
+	activities := []bbcloud.Activity{
+		{Update: &bbcloud.ActivityUpdate{Date: opened.Add(10 * time.Hour), State: "MERGED"}},
+		{Approval: &bbcloud.ActivityApproval{Date: opened.Add(6 * time.Hour), User: reviewer}},
+		{Approval: &bbcloud.ActivityApproval{Date: opened.Add(7 * time.Hour), User: reviewer}},
+		{Comment: &bbcloud.Comment{CreatedOn: opened.Add(3 * time.Hour), User: reviewer}},
+		{Comment: &bbcloud.Comment{CreatedOn: opened.Add(time.Hour), User: author}},
+	}
+	diffstats := []bbcloud.FileStats{{LinesAdded: 10, LinesRemoved: 2}, {LinesAdded: 5}}
+
+	m, ok := measurePR(pr, activities, diffstats, opened)
+	if !ok {
+		t.Fatal("expected PR to be measured")
+	}
+	if m.CycleHours != 10 {
+		t.Errorf("CycleHours = %v, want 10 (merge update, not updated_on)", m.CycleHours)
+	}
+	if m.FirstReviewHours == nil || *m.FirstReviewHours != 3 {
+		t.Errorf("FirstReviewHours = %v, want 3 (author comments don't count)", m.FirstReviewHours)
+	}
+	if m.Approvals != 1 || m.Files != 2 || m.LinesChanged != 17 {
+		t.Errorf("approvals/files/lines = %d/%d/%d, want 1/2/17", m.Approvals, m.Files, m.LinesChanged)
+	}
+
+	if _, ok := measurePR(pr, activities, nil, opened.Add(24*time.Hour)); ok {
+		t.Error("expected PR merged before the window to be skipped")
+	}
+}
+
+func TestSummarizeMetrics(t *testing.T) {
+	two, four := 2.0, 4.0
+	out := summarizeMetrics([]prMetrics{
+		{CycleHours: 10, FirstReviewHours: &two, Files: 2, LinesChanged: 20, Approvals: 1},
+		{CycleHours: 20, FirstReviewHours: &four, Files: 4, LinesChanged: 40, Approvals: 2},
+		{CycleHours: 60, Files: 3, LinesChanged: 30},
+	})
+	if out.Merged != 3 || out.Unreviewed != 1 {
+		t.Errorf("merged/unreviewed = %d/%d, want 3/1", out.Merged, out.Unreviewed)
+	}
+	if out.CycleTime.Median != 20 || out.CycleTime.Mean != 30 {
+		t.Errorf("cycle time = %+v, want median 20 mean 30", out.CycleTime)
+	}
+	if out.TimeToFirstReview.Median != 3 {
+		t.Errorf("first review median = %v, want 3", out.TimeToFirstReview.Median)
+	}
+	if out.AvgFiles != 3 || out.AvgLinesChanged != 30 || out.AvgApprovals != 1 {
+		t.Errorf("averages = %v/%v/%v", out.AvgFiles, out.AvgLinesChanged, out.AvgApprovals)
+	}
+
+	if empty := summarizeMetrics(nil); empty.PRs == nil || empty.CycleTime != nil {
+		t.Errorf("empty summary = %+v", empty)
+	}
+}
@@ -0,0 +1,82 @@
+package review
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/ghoseb/bb/internal/config"
+)
+
+// pendingComment is a comment queued locally until the review is submitted
+type pendingComment struct {
+	Message   string `json:"message"`
+	File      string `json:"file,omitempty"`
+	LineStart int    `json:"line_start,omitempty"`
+	LineEnd   int    `json:"line_end,omitempty"` // 0 means single line
+}
+
+// pendingReview is the local buffer for a review started with bb review start
+type pendingReview struct {
+	Workspace string           `json:"workspace"`
+	Repo      string           `json:"repo"`
+	PR        int              `json:"pr"`
+	Started   time.Time        `json:"started"`
+	Comments  []pendingComment `json:"comments"`
+}
+
+// errNoPendingReview is returned when no review has been started for a PR
+var errNoPendingReview = errors.New("no pending review")
+
+// pendingPath returns where the pending review for a PR is stored. Reviews live
+// under the config directory so they survive across shells and checkouts.
+func pendingPath(workspace, repo string, pr int) string {
+	return filepath.Join(config.Dir(), "pending", workspace, repo, strconv.Itoa(pr)+".json")
+}
+
+// loadPendingReview reads a pending review, returning errNoPendingReview if none exists
+func loadPendingReview(workspace, repo string, pr int) (*pendingReview, error) {
+	data, err := os.ReadFile(pendingPath(workspace, repo, pr))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w for PR %d (run 'bb review start %d')", errNoPendingReview, pr, pr)
+		}
+		return nil, fmt.Errorf("read pending review: %w", err)
+	}
+
+	var review pendingReview
+	if err := json.Unmarshal(data, &review); err != nil {
+		return nil, fmt.Errorf("parse pending review: %w", err)
+	}
+	return &review, nil
+}
+
+// save writes the pending review, creating its directory if needed
+func (r *pendingReview) save() error {
+	path := pendingPath(r.Workspace, r.Repo, r.PR)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create pending review directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode pending review: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write pending review: %w", err)
+	}
+	return nil
+}
+
+// discard removes the pending review from disk
+func (r *pendingReview) discard() error {
+	err := os.Remove(pendingPath(r.Workspace, r.Repo, r.PR))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove pending review: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package review
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPendingReviewRoundTrip(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+
+	if _, err := loadPendingReview("acme", "api", 7); !errors.Is(err, errNoPendingReview) {
+		t.Fatalf("expected errNoPendingReview, got %v", err)
+	}
+
+	review := &pendingReview{Workspace: "acme", Repo: "api", PR: 7}
+	review.Comments = append(review.Comments,
+		pendingComment{Message: "general"},
+		pendingComment{Message: "inline", File: "main.go", LineStart: 3, LineEnd: 5},
+	)
+	if err := review.save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	got, err := loadPendingReview("acme", "api", 7)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(got.Comments) != 2 || got.Comments[1].File != "main.go" || got.Comments[1].LineEnd != 5 {
+		t.Errorf("unexpected comments: %+v", got.Comments)
+	}
+
+	// Reviews are keyed per PR
+	if _, err := loadPendingReview("acme", "api", 8); !errors.Is(err, errNoPendingReview) {
+		t.Errorf("PR 8 should have no pending review, got %v", err)
+	}
+
+	if err := got.discard(); err != nil {
+		t.Fatalf("discard: %v", err)
+	}
+	if _, err := loadPendingReview("acme", "api", 7); !errors.Is(err, errNoPendingReview) {
+		t.Errorf("expected review to be discarded, got %v", err)
+	}
+}
+
+func TestInlineRange(t *testing.T) {
+	if from, to := inlineRange(12, 0); from != 0 || to != 12 {
+		t.Errorf("single line: got %d-%d", from, to)
+	}
+	if from, to := inlineRange(12, 20); from != 12 || to != 20 {
+		t.Errorf("range: got %d-%d", from, to)
+	}
+}
@@ -26,18 +26,24 @@ func NewCmdReply(f *cmdutil.Factory) *cobra.Command {
 	opts := &replyOptions{factory: f}
 
 	cmd := &cobra.Command{
-		Use:   "reply <pr-number> <comment-id> <message>",
+		Use:   "reply <pr-number> <comment-id> [<message>]",
 		Short: "Reply to a comment on a pull request",
 		Long: `Reply to an existing comment on a pull request.
 
-Requires --repo flag to specify the repository.
+Requires --repo flag (or a default_repo setting) to specify the repository.
 
 The comment ID can be found in the output of bb review view commands.
 
+Without a message, your editor opens with the comment being replied to quoted
+below the message area (stdin must be a terminal).
+
 Examples:
   bbc review reply 450 123456 --repo test_repo "Fixed in latest commit"
-  bbc review reply 450 789012 --repo test_repo "Good catch, updated"`,
-		Args: cobra.ExactArgs(3),
+  bbc review reply 450 789012 --repo test_repo "Good catch, updated"
+
+  # Compose the reply in your editor
+  bbc review reply 450 789012 --repo test_repo`,
+		Args: cobra.RangeArgs(2, 3),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Initialize client
 			client, err := opts.factory.NewBBCloudClient("")
@@ -62,18 +68,23 @@ Examples:
 			}
 			opts.commentID = commentID
 
-			// Get message
-			opts.message = args[2]
+			// Get message, composing it in the editor when omitted
+			if len(args) > 2 {
+				opts.message = args[2]
+			} else if opts.message, err = composeReply(cmd.Context(), opts, client); err != nil {
+				return err
+			}
 			if strings.TrimSpace(opts.message) == "" {
 				return fmt.Errorf("message cannot be empty")
 			}
 
+			opts.message = resolveMentions(cmd.Context(), opts.factory, client, opts.message)
+
 			return runReply(cmd.Context(), opts, client)
 		},
 	}
 
-	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (required)")
-	_ = cmd.MarkFlagRequired("repo")
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
 
 	return cmd
 }
@@ -2,17 +2,20 @@ package review
 
 import (
 	"context"
+	"errors"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ghoseb/bb/pkg/bbcloud"
 	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/httpx"
 )
 
 type requestChangeOptions struct {
 	repo     string
 	prNumber int
 	undo     bool
+	message  string
 
 	factory *cmdutil.Factory
 }
@@ -26,24 +29,30 @@ func NewCmdRequestChange(f *cmdutil.Factory) *cobra.Command {
 		Short: "Request changes on a pull request",
 		Long: `Request changes on a pull request.
 
-Requires --repo flag to specify the repository.
+Requires --repo flag (or a default_repo setting) to specify the repository.
 
-To explain what needs to change, use bb review comment separately.
+Use --message to explain what needs to change: the comment is posted first
+and changes are requested only once it is. If requesting changes then fails,
+the output names the posted comment so the command can be retried or the
+comment deleted.
 
 Use --undo to remove your request-change status.
 
 Examples:
+  # Request changes, explaining why
+  bbc review request-change 450 --repo test_repo -m "Please add tests for the new feature"
+
   # Request changes
   bbc review request-change 450 --repo test_repo
 
   # Remove request-change
-  bbc review request-change 450 --repo test_repo --undo
-
-  # Request changes with explanation (two commands)
-  bbc review request-change 450 --repo test_repo
-  bbc review comment 450 --repo test_repo "Please add tests for the new feature"`,
+  bbc review request-change 450 --repo test_repo --undo`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateReviewMessage(cmd, opts.message, opts.undo); err != nil {
+				return err
+			}
+
 			// Initialize client
 			client, err := opts.factory.NewBBCloudClient("")
 			if err != nil {
@@ -61,9 +70,9 @@ Examples:
 		},
 	}
 
-	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (required)")
-	_ = cmd.MarkFlagRequired("repo")
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
 	cmd.Flags().BoolVar(&opts.undo, "undo", false, "Remove request-change instead of requesting changes")
+	cmd.Flags().StringVarP(&opts.message, "message", "m", "", "Comment explaining the changes, posted before they are requested")
 
 	return cmd
 }
@@ -72,12 +81,15 @@ func runRequestChange(ctx context.Context, opts *requestChangeOptions, client *b
 	if opts.undo {
 		// Remove request-change
 		err := client.UnrequestChangesPR(ctx, opts.repo, opts.prNumber)
+		if errors.Is(err, httpx.ErrDryRun) {
+			return err
+		}
 		if err != nil {
 			output := map[string]interface{}{
 				"pr":     opts.prNumber,
 				"repo":   opts.repo,
 				"action": "unrequest-change",
-				"error":  friendlyError(err.Error()),
+				"error":  friendlyError(err),
 			}
 			
 			return cmdutil.WriteJSON(opts.factory.IOStreams.Out, output)
@@ -92,16 +104,42 @@ func runRequestChange(ctx context.Context, opts *requestChangeOptions, client *b
 		return cmdutil.WriteJSON(opts.factory.IOStreams.Out, output)
 	}
 
+	// The reason goes first, so changes are never requested without it
+	var commentID int
+	if opts.message != "" {
+		// Under --dry-run the comment was printed; go on to print the decision
+		id, err := postReviewComment(ctx, opts.factory, client, opts.repo, opts.prNumber, opts.message)
+		if err != nil && !errors.Is(err, httpx.ErrDryRun) {
+			output := map[string]interface{}{
+				"pr":     opts.prNumber,
+				"repo":   opts.repo,
+				"action": "request-change",
+				"error":  "comment not posted, changes not requested: " + friendlyError(err),
+			}
+
+			return cmdutil.WriteJSON(opts.factory.IOStreams.Out, output)
+		}
+		commentID = id
+	}
+
 	// Request changes on PR
 	participant, err := client.RequestChangesPR(ctx, opts.repo, opts.prNumber)
+	if errors.Is(err, httpx.ErrDryRun) {
+		// Stdout holds only the printed requests
+		return err
+	}
 	if err != nil {
 		output := map[string]interface{}{
 			"pr":     opts.prNumber,
 			"repo":   opts.repo,
 			"action": "request-change",
-			"error":  friendlyError(err.Error()),
+			"error":  friendlyError(err),
+		}
+		if commentID != 0 {
+			output["comment_id"] = commentID
+			output["partial"] = partialReview("changes were not requested", "request-change", opts.prNumber, opts.repo, commentID)
 		}
-		
+
 		return cmdutil.WriteJSON(opts.factory.IOStreams.Out, output)
 	}
 
@@ -113,6 +151,9 @@ func runRequestChange(ctx context.Context, opts *requestChangeOptions, client *b
 		"state":    participant.State,
 	}
 
+	if commentID != 0 {
+		output["comment_id"] = commentID
+	}
 	if participant.User != nil {
 		output["user"] = participant.User.GetName()
 	}
@@ -0,0 +1,77 @@
+package review
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/bbtest"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/iostreams"
+)
+
+func TestRequestChangeWithMessage(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	srv := bbtest.NewServer(t)
+	srv.AddRepository("acme", bbcloud.Repository{Slug: "api"})
+	srv.AddPullRequest("acme", "api", bbcloud.PullRequest{Title: "Add auth"})
+	client := srv.Client(t, "acme")
+
+	run := func() map[string]any {
+		t.Helper()
+		out := &bytes.Buffer{}
+		f := cmdutil.NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: out, ErrOut: &bytes.Buffer{}})
+		opts := &requestChangeOptions{repo: "api", prNumber: 1, message: "Please add tests", factory: f}
+		if err := runRequestChange(context.Background(), opts, client); err != nil {
+			t.Fatal(err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	got := run()
+	comments := srv.Comments("acme", "api", 1)
+	if got["action"] != "changes_requested" || len(comments) != 1 || got["comment_id"] != float64(comments[0].ID) {
+		t.Fatalf("output = %v, comments = %d", got, len(comments))
+	}
+	if comments[0].Content.Raw != "Please add tests" {
+		t.Errorf("comment = %q", comments[0].Content.Raw)
+	}
+	if pr, _ := srv.PullRequest("acme", "api", 1); len(pr.Participants) != 1 || pr.Participants[0].State != "changes_requested" {
+		t.Errorf("participants = %+v", pr.Participants)
+	}
+
+	// Requesting changes fails after the comment was posted
+	srv.Handle(http.MethodPost, "/repositories/acme/api/pullrequests/1/request-changes", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"type": "error", "error": {"message": "This pull request has already been merged."}}`))
+	})
+	got = run()
+	comments = srv.Comments("acme", "api", 1)
+	if got["error"] != "PR is already merged" || got["comment_id"] != float64(comments[1].ID) {
+		t.Fatalf("output = %v", got)
+	}
+	if partial, _ := got["partial"].(string); !strings.Contains(partial, "--delete") {
+		t.Errorf("partial = %q, want how to remove the comment", partial)
+	}
+
+	// Nothing is requested when the comment cannot be posted
+	srv.Reset()
+	srv.Handle(http.MethodPost, "/repositories/acme/api/pullrequests/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"type": "error", "error": {"message": "Forbidden"}}`))
+	})
+	got = run()
+	if msg, _ := got["error"].(string); !strings.HasPrefix(msg, "comment not posted, changes not requested") {
+		t.Errorf("output = %v", got)
+	}
+	srv.AssertNotRequested(t, http.MethodPost, "/repositories/acme/api/pullrequests/1/request-changes")
+}
@@ -20,12 +20,29 @@ designed for efficient LLM consumption during code review workflows.`,
 	// Add subcommands
 	cmd.AddCommand(NewCmdList(f))
 	cmd.AddCommand(NewCmdView(f))
+	cmd.AddCommand(NewCmdStatus(f))
+	cmd.AddCommand(NewCmdConflicts(f))
 	cmd.AddCommand(NewCmdComment(f))
+	cmd.AddCommand(NewCmdComments(f))
+	cmd.AddCommand(NewCmdThread(f))
+	cmd.AddCommand(NewCmdActivity(f))
+	cmd.AddCommand(NewCmdWatch(f))
 	cmd.AddCommand(NewCmdReply(f))
 	cmd.AddCommand(NewCmdCreate(f))
 	cmd.AddCommand(NewCmdUpdate(f))
+	cmd.AddCommand(NewCmdUpdateBranch(f))
+	cmd.AddCommand(NewCmdEdit(f))
 	cmd.AddCommand(NewCmdApprove(f))
 	cmd.AddCommand(NewCmdRequestChange(f))
+	cmd.AddCommand(NewCmdStart(f))
+	cmd.AddCommand(NewCmdSubmit(f))
+	cmd.AddCommand(NewCmdCheckout(f))
+	cmd.AddCommand(NewCmdLocalDiff(f))
+	cmd.AddCommand(NewCmdStack(f))
+	cmd.AddCommand(NewCmdBulk(f))
+	cmd.AddCommand(NewCmdMetrics(f))
+	cmd.AddCommand(NewCmdExport(f))
+	cmd.AddCommand(NewCmdImport(f))
 
 	return cmd
 }
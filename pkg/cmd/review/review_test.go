@@ -1,8 +1,18 @@
 package review
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/bbtest"
 	"github.com/ghoseb/bb/pkg/cmdutil"
 	"github.com/ghoseb/bb/pkg/iostreams"
 )
@@ -24,8 +34,8 @@ func TestCommandStructure(t *testing.T) {
 	
 	// Check subcommands are registered
 	subcommands := cmd.Commands()
-	if len(subcommands) != 7 {
-		t.Errorf("expected 7 subcommands, got %d", len(subcommands))
+	if len(subcommands) != 25 {
+		t.Errorf("expected 25 subcommands, got %d", len(subcommands))
 	}
 	
 	// Verify subcommand names
@@ -55,6 +65,21 @@ func TestCommandStructure(t *testing.T) {
 	if !names["request-change"] {
 		t.Error("expected 'request-change' subcommand")
 	}
+	if !names["update"] {
+		t.Error("expected 'update' subcommand")
+	}
+	if !names["checkout"] {
+		t.Error("expected 'checkout' subcommand")
+	}
+	if !names["local-diff"] {
+		t.Error("expected 'local-diff' subcommand")
+	}
+	if !names["stack"] {
+		t.Error("expected 'stack' subcommand")
+	}
+	if !names["edit"] {
+		t.Error("expected 'edit' subcommand")
+	}
 }
 
 func TestListCommand(t *testing.T) {
@@ -90,8 +115,8 @@ func TestViewCommand(t *testing.T) {
 	
 	cmd := NewCmdView(factory)
 	
-	if cmd.Use != "view <pr-number> [file-path]" {
-		t.Errorf("expected Use to be 'view <pr-number> [file-path]', got %q", cmd.Use)
+	if cmd.Use != "view [pr-number] [file-path]" {
+		t.Errorf("expected Use to be 'view [pr-number] [file-path]', got %q", cmd.Use)
 	}
 	
 	// Check flags
@@ -109,9 +134,15 @@ func TestViewCommand(t *testing.T) {
 		t.Error("--comments flag should not exist")
 	}
 	
-	// Verify at least 1 arg required
+	// Verify arg count is validated (PR number is optional)
 	if cmd.Args == nil {
-		t.Error("expected Args validator to be set")
+		t.Fatal("expected Args validator to be set")
+	}
+	if err := cmd.Args(cmd, []string{}); err != nil {
+		t.Errorf("expected no args to be accepted, got: %v", err)
+	}
+	if err := cmd.Args(cmd, []string{"1", "a.go", "extra"}); err == nil {
+		t.Error("expected error with 3 args, got nil")
 	}
 }
 
@@ -134,3 +165,126 @@ func TestViewCommandNoApprovalFlags(t *testing.T) {
 		t.Error("--comment flag should not exist on view command")
 	}
 }
+
+func TestRenderTableList(t *testing.T) {
+	var buf bytes.Buffer
+	items := []prListItem{
+		{ID: 7, Title: "Add auth", Author: "alice", Files: 3, Additions: 10, Deletions: 2},
+		{ID: 12, Title: "Fix a very long title that keeps going and going well past the column limit", Author: "bob", Stack: &stackLink{Parent: 7}},
+		{ID: 15, Title: "Vendor deps", Author: "carol", Files: 3120, FilesTruncated: &fileTruncation{Shown: 1000, Total: 3120}},
+	}
+	if err := renderTableList(&buf, items, 80); err != nil {
+		t.Fatalf("renderTableList: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header + 3 rows, got %d lines:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "PR ") || !strings.Contains(lines[0], "TITLE") {
+		t.Errorf("unexpected header %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "#7") || !strings.Contains(lines[1], "+10/-2") {
+		t.Errorf("unexpected row %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "…") {
+		t.Errorf("expected long title to be truncated: %q", lines[2])
+	}
+	if !strings.Contains(lines[3], "1000 of 3120") {
+		t.Errorf("expected partial file count: %q", lines[3])
+	}
+	for _, line := range lines {
+		if n := utf8.RuneCountInString(line); n > 80 {
+			t.Errorf("row is %d columns wide, want at most 80: %q", n, line)
+		}
+	}
+
+	// A wide terminal shows the whole title
+	buf.Reset()
+	if err := renderTableList(&buf, items, 200); err != nil {
+		t.Fatalf("renderTableList: %v", err)
+	}
+	if strings.Contains(buf.String(), "…") {
+		t.Errorf("expected no truncation at 200 columns:\n%s", buf.String())
+	}
+}
+
+func TestPRWebURL(t *testing.T) {
+	pr := &bbcloud.PullRequest{ID: 450}
+	if _, err := prWebURL(pr, ""); err == nil {
+		t.Error("expected error for PR without web link")
+	}
+
+	pr.Links.HTML = &bbcloud.Link{Href: "https://bitbucket.org/ws/repo/pull-requests/450"}
+	got, err := prWebURL(pr, "")
+	if err != nil || got != "https://bitbucket.org/ws/repo/pull-requests/450" {
+		t.Errorf("prWebURL() = %q, %v", got, err)
+	}
+	got, _ = prWebURL(pr, "src/auth.ts")
+	if want := "https://bitbucket.org/ws/repo/pull-requests/450/diff#chg-src/auth.ts"; got != want {
+		t.Errorf("prWebURL() with file = %q, want %q", got, want)
+	}
+}
+
+func TestFriendlyError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("approve: %w", &bbcloud.Error{StatusCode: 404}), "PR not found"},
+		{&bbcloud.Error{StatusCode: 400, ErrorInfo: bbcloud.ErrorDetail{Message: "This pull request has already been merged."}}, "PR is already merged"},
+		{&bbcloud.Error{StatusCode: 400, ErrorInfo: bbcloud.ErrorDetail{Message: "You haven't approved this pull request."}}, "no approval to remove"},
+		// Only the API's message is matched, not text elsewhere in the error
+		{errors.New("dial tcp: lookup not found"), "dial tcp: lookup not found"},
+	}
+	for _, tt := range tests {
+		if got := friendlyError(tt.err); got != tt.want {
+			t.Errorf("friendlyError(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestViewPRMaxFiles(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	srv := bbtest.NewServer(t)
+	srv.AddRepository("acme", bbcloud.Repository{Slug: "api"})
+	srv.AddPullRequest("acme", "api", bbcloud.PullRequest{
+		Title:       "Vendor everything",
+		Author:      &bbtest.DefaultUser,
+		Source:      &bbcloud.PullRequestBranch{Branch: &bbcloud.Branch{Name: "vendor"}},
+		Destination: &bbcloud.PullRequestBranch{Branch: &bbcloud.Branch{Name: "main"}},
+	})
+	// Three pages of diffstat
+	for i := 0; i < 25; i++ {
+		srv.AddDiffStat("acme", "api", 1, bbcloud.FileStats{LinesAdded: 2, New: &bbcloud.FileInfo{Path: fmt.Sprintf("vendor/f%d.go", i)}})
+	}
+
+	run := func(maxFiles int, json bool) string {
+		t.Helper()
+		out := &bytes.Buffer{}
+		f := cmdutil.NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: out, ErrOut: &bytes.Buffer{}})
+		opts := &viewOptions{repo: "api", prNumber: 1, maxFiles: maxFiles, json: json, factory: f, client: srv.Client(t, "acme")}
+		if err := runViewPR(context.Background(), opts); err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+
+	var got prViewOutput
+	if err := json.Unmarshal([]byte(run(10, true)), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Files) != 10 || got.TotalFiles != 25 || got.TotalAdds != 50 {
+		t.Errorf("got %d files of %d, +%d", len(got.Files), got.TotalFiles, got.TotalAdds)
+	}
+	if got.FilesTruncated == nil || got.FilesTruncated.Shown != 10 || got.FilesTruncated.Total != 25 {
+		t.Errorf("files_truncated = %+v", got.FilesTruncated)
+	}
+
+	if md := run(10, false); !strings.Contains(md, "## Files (25 files, +50, -0)") || !strings.Contains(md, "… showing 10 of 25 files") {
+		t.Errorf("markdown:\n%s", md)
+	}
+	if md := run(0, false); strings.Contains(md, "showing") || !strings.Contains(md, "vendor/f24.go") {
+		t.Errorf("unlimited markdown:\n%s", md)
+	}
+}
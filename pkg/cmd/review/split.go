@@ -0,0 +1,183 @@
+package review
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+const (
+	splitSeparator = " │ "
+	splitMinWidth  = 40
+
+	ansiRed   = "\x1b[31m"
+	ansiGreen = "\x1b[32m"
+	ansiCyan  = "\x1b[36m"
+	ansiReset = "\x1b[0m"
+
+	// Changed tokens within a line are shown in reverse video
+	ansiReverse   = "\x1b[7m"
+	ansiNoReverse = "\x1b[27m"
+)
+
+// splitCell is one side of a side-by-side diff row; line 0 means blank
+type splitCell struct {
+	line int
+	kind byte // ' ', '-' or '+'
+	segs []segment
+}
+
+// renderSplitDiff writes a unified diff as old/new columns. Removed and added
+// runs are paired row by row so modified lines line up; long lines are
+// truncated to fit width. With color, the tokens that changed within a
+// paired line are highlighted.
+func renderSplitDiff(w io.Writer, diff string, width int, color bool) {
+	diff = text.Sanitize(diff)
+	width = max(width, splitMinWidth)
+	col := (width - utf8.RuneCountInString(splitSeparator)) / 2
+
+	var oldLine, newLine int
+	var removed, added []splitCell
+	inHunk := false
+
+	flush := func() {
+		for i := 0; i < max(len(removed), len(added)); i++ {
+			var left, right splitCell
+			if i < len(removed) {
+				left = removed[i]
+			}
+			if i < len(added) {
+				right = added[i]
+			}
+			if color && left.line != 0 && right.line != 0 {
+				left.segs, right.segs = intraLineDiff(left.segs[0].text, right.segs[0].text)
+			}
+			writeSplitRow(w, left, right, col, color)
+		}
+		removed, added = nil, nil
+	}
+
+	for _, l := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(l, "@@"):
+			flush()
+			oldLine, newLine = hunkStarts(l)
+			inHunk = true
+			header := cmdutil.Truncate(l, width)
+			if color {
+				header = ansiCyan + header + ansiReset
+			}
+			_, _ = fmt.Fprintln(w, header)
+		case strings.HasPrefix(l, "diff --git"):
+			flush()
+			inHunk = false
+		case !inHunk || l == "" || strings.HasPrefix(l, `\`):
+			// File headers and "\ No newline at end of file"
+		case l[0] == '-':
+			removed = append(removed, splitCell{line: oldLine, kind: '-', segs: []segment{{text: l[1:]}}})
+			oldLine++
+		case l[0] == '+':
+			added = append(added, splitCell{line: newLine, kind: '+', segs: []segment{{text: l[1:]}}})
+			newLine++
+		default:
+			flush()
+			cell := splitCell{kind: ' ', segs: []segment{{text: l[1:]}}}
+			left, right := cell, cell
+			left.line, right.line = oldLine, newLine
+			writeSplitRow(w, left, right, col, color)
+			oldLine++
+			newLine++
+		}
+	}
+	flush()
+}
+
+func writeSplitRow(w io.Writer, left, right splitCell, col int, color bool) {
+	_, _ = fmt.Fprintf(w, "%s%s%s\n",
+		formatSplitCell(left, col, color), splitSeparator, strings.TrimRight(formatSplitCell(right, col, color), " "))
+}
+
+// formatSplitCell renders "  12 - text" padded or truncated to exactly col columns
+func formatSplitCell(c splitCell, col int, color bool) string {
+	if c.line == 0 {
+		return strings.Repeat(" ", col)
+	}
+
+	prefix := fmt.Sprintf("%4d %c ", c.line, c.kind)
+	budget := col - utf8.RuneCountInString(prefix)
+	segs := make([]segment, len(c.segs))
+	for i, seg := range c.segs {
+		segs[i] = segment{text: strings.ReplaceAll(seg.text, "\t", "    "), changed: seg.changed}
+	}
+	if segmentsWidth(segs) > budget {
+		segs = truncateSegments(segs, budget-1)
+		segs = append(segs, segment{text: "…"})
+	}
+	pad := strings.Repeat(" ", budget-segmentsWidth(segs))
+
+	var b strings.Builder
+	base := ""
+	if color {
+		switch c.kind {
+		case '-':
+			base = ansiRed
+		case '+':
+			base = ansiGreen
+		}
+	}
+	b.WriteString(base + prefix)
+	for _, seg := range segs {
+		if color && seg.changed {
+			b.WriteString(ansiReverse + seg.text + ansiNoReverse)
+		} else {
+			b.WriteString(seg.text)
+		}
+	}
+	b.WriteString(pad)
+	if base != "" {
+		b.WriteString(ansiReset)
+	}
+	return b.String()
+}
+
+// truncateSegments keeps the first n terminal columns of segs, never
+// splitting a wide character
+func truncateSegments(segs []segment, n int) []segment {
+	var out []segment
+	for _, seg := range segs {
+		if n <= 0 {
+			break
+		}
+		end := len(seg.text)
+		for i, r := range seg.text {
+			w := text.RuneWidth(r)
+			if w > n {
+				end = i
+				break
+			}
+			n -= w
+		}
+		out = append(out, segment{text: seg.text[:end], changed: seg.changed})
+		if end < len(seg.text) {
+			break
+		}
+	}
+	return out
+}
+
+// hunkStarts parses the old and new start lines from "@@ -10,7 +12,8 @@"
+func hunkStarts(header string) (int, int) {
+	oldStart := 0
+	for _, f := range strings.Fields(header) {
+		if strings.HasPrefix(f, "-") {
+			n, _, _ := strings.Cut(f[1:], ",")
+			_, _ = fmt.Sscanf(n, "%d", &oldStart)
+			break
+		}
+	}
+	return oldStart, hunkNewStart(header)
+}
@@ -0,0 +1,60 @@
+package review
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRenderSplitDiff(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -10,3 +10,4 @@
+ keep
+-old
++new
++extra
+`
+	var buf bytes.Buffer
+	renderSplitDiff(&buf, diff, 60, false)
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected hunk header + 3 rows, got %d:\n%s", len(lines), buf.String())
+	}
+	if lines[0] != "@@ -10,3 +10,4 @@" {
+		t.Errorf("header = %q", lines[0])
+	}
+
+	col := (60 - 3) / 2
+	want := []string{
+		pad("  10   keep", col) + " │   10   keep",
+		pad("  11 - old", col) + " │   11 + new",
+		strings.Repeat(" ", col) + " │   12 + extra",
+	}
+	for i, w := range want {
+		if lines[i+1] != w {
+			t.Errorf("row %d =\n%q\nwant\n%q", i, lines[i+1], w)
+		}
+	}
+}
+
+func TestRenderSplitDiffTruncates(t *testing.T) {
+	diff := "@@ -1 +1 @@\n-" + strings.Repeat("x", 100) + "\n+y\n"
+	var buf bytes.Buffer
+	renderSplitDiff(&buf, diff, 40, false)
+
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		if n := len([]rune(line)); n > 40 {
+			t.Errorf("line exceeds width (%d): %q", n, line)
+		}
+	}
+	if !strings.Contains(buf.String(), "…") {
+		t.Error("expected truncation marker")
+	}
+}
+
+func pad(s string, n int) string {
+	return s + strings.Repeat(" ", n-len(s))
+}
@@ -0,0 +1,388 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+type stackOptions struct {
+	repo     string
+	prNumber int
+	json     bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdStack creates the review stack command
+func NewCmdStack(f *cmdutil.Factory) *cobra.Command {
+	opts := &stackOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "stack <pr-number>",
+		Short: "Show the chain of stacked PRs",
+		Long: `Show the full chain of stacked pull requests containing a PR.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+A PR is stacked when its target branch is the source branch of another open
+PR. The chain is listed from the bottom (closest to the main branch) to the
+top. When the bottom PR targets a branch whose PR has already merged, a
+retarget hint is included.
+
+Examples:
+  bbc review stack 451 --repo test_repo
+
+  # Retarget after the parent merged
+  bbc review update 451 --repo test_repo --base main`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.factory.NewBBCloudClient("")
+			if err != nil {
+				return err
+			}
+
+			prNum, err := parsePRNumber(args[0])
+			if err != nil {
+				return err
+			}
+			opts.prNumber = prNum
+
+			return runStack(cmd.Context(), opts, client)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of markdown")
+
+	return cmd
+}
+
+// stackLink places a PR within a stack; omitted from output when the PR is not stacked
+type stackLink struct {
+	Parent   int   `json:"parent,omitempty"`   // open PR whose source is this PR's target
+	Children []int `json:"children,omitempty"` // open PRs targeting this PR's source
+}
+
+// prStack indexes open PRs by branch to walk stacks without extra API calls
+type prStack struct {
+	bySource map[string]*bbcloud.PullRequest
+	byTarget map[string][]*bbcloud.PullRequest
+}
+
+func newPRStack(prs []bbcloud.PullRequest) *prStack {
+	s := &prStack{
+		bySource: make(map[string]*bbcloud.PullRequest),
+		byTarget: make(map[string][]*bbcloud.PullRequest),
+	}
+	for i := range prs {
+		pr := &prs[i]
+		if src := sourceBranch(pr); src != "" {
+			s.bySource[src] = pr
+		}
+		if dst := targetBranch(pr); dst != "" {
+			s.byTarget[dst] = append(s.byTarget[dst], pr)
+		}
+	}
+	return s
+}
+
+func sourceBranch(pr *bbcloud.PullRequest) string {
+	if pr.Source == nil || pr.Source.Branch == nil {
+		return ""
+	}
+	return pr.Source.Branch.Name
+}
+
+func targetBranch(pr *bbcloud.PullRequest) string {
+	if pr.Destination == nil || pr.Destination.Branch == nil {
+		return ""
+	}
+	return pr.Destination.Branch.Name
+}
+
+// parent returns the open PR whose source branch is pr's target, if any
+func (s *prStack) parent(pr *bbcloud.PullRequest) *bbcloud.PullRequest {
+	p := s.bySource[targetBranch(pr)]
+	if p == nil || p.ID == pr.ID {
+		return nil
+	}
+	return p
+}
+
+// children returns open PRs targeting pr's source branch
+func (s *prStack) children(pr *bbcloud.PullRequest) []*bbcloud.PullRequest {
+	return s.byTarget[sourceBranch(pr)]
+}
+
+// link returns pr's position in a stack, or nil when it is not stacked
+func (s *prStack) link(pr *bbcloud.PullRequest) *stackLink {
+	var l stackLink
+	if p := s.parent(pr); p != nil {
+		l.Parent = p.ID
+	}
+	for _, c := range s.children(pr) {
+		l.Children = append(l.Children, c.ID)
+	}
+	if l.Parent == 0 && len(l.Children) == 0 {
+		return nil
+	}
+	return &l
+}
+
+// chain returns every PR in pr's stack, from the bottom-most ancestor upwards
+func (s *prStack) chain(pr *bbcloud.PullRequest) []*bbcloud.PullRequest {
+	seen := map[int]bool{pr.ID: true}
+
+	var ancestors []*bbcloud.PullRequest
+	for p := s.parent(pr); p != nil && !seen[p.ID]; p = s.parent(p) {
+		seen[p.ID] = true
+		ancestors = append([]*bbcloud.PullRequest{p}, ancestors...)
+	}
+
+	chain := append(ancestors, pr)
+	queue := []*bbcloud.PullRequest{pr}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, c := range s.children(cur) {
+			if seen[c.ID] {
+				continue
+			}
+			seen[c.ID] = true
+			chain = append(chain, c)
+			queue = append(queue, c)
+		}
+	}
+	return chain
+}
+
+// fetchStackLink finds pr's open parent and child PRs with two targeted queries
+func fetchStackLink(ctx context.Context, client *bbcloud.Client, repo string, pr *bbcloud.PullRequest) (*stackLink, error) {
+	var open []bbcloud.PullRequest
+
+	if target := targetBranch(pr); target != "" {
+		parents, err := client.FindPullRequestsForBranch(ctx, repo, target)
+		if err != nil {
+			return nil, err
+		}
+		open = append(open, parents...)
+	}
+
+	if source := sourceBranch(pr); source != "" {
+		query := fmt.Sprintf(`destination.branch.name = "%s" AND state = "OPEN"`, bbcloud.EscapeQueryString(source))
+		children, err := client.SearchPullRequests(ctx, repo, query, 0)
+		if err != nil {
+			return nil, err
+		}
+		open = append(open, children...)
+	}
+
+	return newPRStack(open).link(pr), nil
+}
+
+// stackQueryBranches caps the branch names in one stack lookup query, keeping
+// its URL short
+const stackQueryBranches = 20
+
+// fetchStackNeighbours returns the open PRs that can be a parent or child of
+// one of prs: those from a branch prs target, or into a branch prs come from.
+// It sends a query per stackQueryBranches branch names rather than listing
+// every open PR of the repository.
+func fetchStackNeighbours(ctx context.Context, client *bbcloud.Client, repo string, prs []bbcloud.PullRequest) ([]bbcloud.PullRequest, error) {
+	var open []bbcloud.PullRequest
+	seen := map[int]bool{}
+	for _, query := range stackQueries(prs) {
+		found, err := client.SearchPullRequests(ctx, repo, query, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, pr := range found {
+			if !seen[pr.ID] {
+				seen[pr.ID] = true
+				open = append(open, pr)
+			}
+		}
+	}
+	return open, nil
+}
+
+// stackQueries returns the BBQL queries fetchStackNeighbours sends for prs
+func stackQueries(prs []bbcloud.PullRequest) []string {
+	var terms []string
+	seen := map[string]bool{}
+	add := func(field, branch string) {
+		if branch == "" || seen[field+branch] {
+			return
+		}
+		seen[field+branch] = true
+		terms = append(terms, fmt.Sprintf(`%s = "%s"`, field, bbcloud.EscapeQueryString(branch)))
+	}
+	for i := range prs {
+		add("source.branch.name", targetBranch(&prs[i]))
+		add("destination.branch.name", sourceBranch(&prs[i]))
+	}
+
+	var queries []string
+	for len(terms) > 0 {
+		n := min(stackQueryBranches, len(terms))
+		queries = append(queries, fmt.Sprintf(`state = "OPEN" AND (%s)`, strings.Join(terms[:n], " OR ")))
+		terms = terms[n:]
+	}
+	return queries
+}
+
+func formatPRRef(id int) string {
+	if id == 0 {
+		return "none"
+	}
+	return fmt.Sprintf("#%d", id)
+}
+
+func formatPRRefs(ids []int) string {
+	if len(ids) == 0 {
+		return "none"
+	}
+	refs := make([]string, len(ids))
+	for i, id := range ids {
+		refs[i] = formatPRRef(id)
+	}
+	return strings.Join(refs, ", ")
+}
+
+type stackItem struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	Author string `json:"author"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+type stackOutput struct {
+	PR       int         `json:"pr"`
+	Stack    []stackItem `json:"stack"`
+	Retarget string      `json:"retarget,omitempty"` // suggested --base when the bottom PR's parent merged
+}
+
+func runStack(ctx context.Context, opts *stackOptions, client *bbcloud.Client) error {
+	ios, _ := opts.factory.Streams()
+
+	pr, err := client.GetPullRequest(ctx, opts.repo, opts.prNumber)
+	if err != nil {
+		return fmt.Errorf("get pull request: %w", err)
+	}
+
+	open, err := client.ListPullRequests(ctx, opts.repo, "OPEN", 0)
+	if err != nil {
+		return fmt.Errorf("list pull requests: %w", err)
+	}
+
+	chain := newPRStack(open).chain(pr)
+
+	items := make([]stackItem, len(chain))
+	for i, p := range chain {
+		author := ""
+		if p.Author != nil {
+			author = p.Author.DisplayName
+		}
+		items[i] = stackItem{
+			ID:     p.ID,
+			Title:  p.Title,
+			Author: author,
+			Source: sourceBranch(p),
+			Target: targetBranch(p),
+		}
+	}
+
+	output := stackOutput{PR: opts.prNumber, Stack: items}
+
+	// A bottom PR targeting a merged branch should be retargeted onto that branch's target
+	bottom := chain[0]
+	if parent, err := mergedParent(ctx, client, opts.repo, bottom); err != nil {
+		_, _ = fmt.Fprintf(ios.ErrOut, "warning: failed to check for merged parent: %v\n", err)
+	} else if parent != nil {
+		output.Retarget = targetBranch(parent)
+	}
+
+	if opts.json {
+		if err := cmdutil.WriteJSON(ios.Out, output); err != nil {
+			return fmt.Errorf("encode output: %w", err)
+		}
+		return nil
+	}
+
+	return renderMarkdownStack(ios.Out, opts.repo, output)
+}
+
+// mergedParent returns the merged PR that bottom was stacked on: it came from
+// bottom's target branch, at the commit bottom still targets. Branch names
+// are reused, so an older merged PR from a branch of the same name does not
+// count.
+func mergedParent(ctx context.Context, client *bbcloud.Client, repo string, bottom *bbcloud.PullRequest) (*bbcloud.PullRequest, error) {
+	target := targetBranch(bottom)
+	if target == "" || bottom.Destination.Commit == nil || bottom.Destination.Commit.Hash == "" {
+		return nil, nil
+	}
+	query := fmt.Sprintf(`source.branch.name = "%s" AND destination.branch.name != "%s" AND state = "MERGED"`,
+		bbcloud.EscapeQueryString(target), bbcloud.EscapeQueryString(target))
+	merged, err := client.SearchPullRequests(ctx, repo, query, mergedParentLimit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range merged {
+		if isMergedParent(&merged[i], bottom) {
+			return &merged[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// mergedParentLimit caps the merged PRs from the target branch that are checked
+const mergedParentLimit = 10
+
+// isMergedParent reports whether parent merged bottom's target branch at the
+// commit bottom is based on, into another branch
+func isMergedParent(parent, bottom *bbcloud.PullRequest) bool {
+	if sourceBranch(parent) != targetBranch(bottom) || targetBranch(parent) == "" || targetBranch(parent) == targetBranch(bottom) {
+		return false
+	}
+	if parent.Source.Commit == nil || bottom.Destination.Commit == nil {
+		return false
+	}
+	return sameCommit(parent.Source.Commit.Hash, bottom.Destination.Commit.Hash)
+}
+
+// sameCommit compares commit hashes that may be abbreviated, as the API
+// returns them
+func sameCommit(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+func renderMarkdownStack(w io.Writer, repo string, output stackOutput) error {
+	_, _ = fmt.Fprintf(w, "# Stack for PR %d — %s\n\n", output.PR, repo)
+	for i, item := range output.Stack {
+		marker := ""
+		if item.ID == output.PR {
+			marker = " ←"
+		}
+		_, _ = fmt.Fprintf(w, "%d. PR %d: %s (%s → %s)%s\n", i+1, item.ID, text.Line(item.Title), text.Line(item.Source), text.Line(item.Target), marker)
+	}
+
+	if output.Retarget != "" && len(output.Stack) > 0 {
+		bottom := output.Stack[0]
+		_, _ = fmt.Fprintf(w, "\nParent of PR %d has merged. Retarget with:\n  bbc review update %d --repo %s --base %s\n",
+			bottom.ID, bottom.ID, repo, output.Retarget)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package review
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+)
+
+func testPR(id int, source, target string) bbcloud.PullRequest {
+	return bbcloud.PullRequest{
+		ID:          id,
+		Source:      &bbcloud.PullRequestBranch{Branch: &bbcloud.Branch{Name: source}},
+		Destination: &bbcloud.PullRequestBranch{Branch: &bbcloud.Branch{Name: target}},
+	}
+}
+
+func TestPRStackChain(t *testing.T) {
+	prs := []bbcloud.PullRequest{
+		testPR(1, "feat/a", "main"),
+		testPR(2, "feat/b", "feat/a"),
+		testPR(3, "feat/c", "feat/b"),
+		testPR(4, "other", "main"),
+	}
+	stack := newPRStack(prs)
+
+	chain := stack.chain(&prs[1])
+	var ids []int
+	for _, pr := range chain {
+		ids = append(ids, pr.ID)
+	}
+	want := []int{1, 2, 3}
+	if len(ids) != len(want) {
+		t.Fatalf("chain = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("chain = %v, want %v", ids, want)
+		}
+	}
+}
+
+func TestPRStackLink(t *testing.T) {
+	prs := []bbcloud.PullRequest{
+		testPR(1, "feat/a", "main"),
+		testPR(2, "feat/b", "feat/a"),
+		testPR(4, "other", "main"),
+	}
+	stack := newPRStack(prs)
+
+	if link := stack.link(&prs[2]); link != nil {
+		t.Errorf("expected unstacked PR to have nil link, got %+v", link)
+	}
+
+	link := stack.link(&prs[1])
+	if link == nil || link.Parent != 1 || len(link.Children) != 0 {
+		t.Errorf("PR 2 link = %+v, want parent 1", link)
+	}
+
+	link = stack.link(&prs[0])
+	if link == nil || link.Parent != 0 || len(link.Children) != 1 || link.Children[0] != 2 {
+		t.Errorf("PR 1 link = %+v, want children [2]", link)
+	}
+}
+
+func TestPRStackChainCycle(t *testing.T) {
+	prs := []bbcloud.PullRequest{
+		testPR(1, "a", "b"),
+		testPR(2, "b", "a"),
+	}
+	stack := newPRStack(prs)
+
+	if chain := stack.chain(&prs[0]); len(chain) != 2 {
+		t.Errorf("expected cycle to terminate with 2 PRs, got %d", len(chain))
+	}
+}
+
+func TestIsMergedParent(t *testing.T) {
+	withCommits := func(pr bbcloud.PullRequest, source, target string) *bbcloud.PullRequest {
+		pr.Source.Commit = &bbcloud.CommitReference{Hash: source}
+		pr.Destination.Commit = &bbcloud.CommitReference{Hash: target}
+		return &pr
+	}
+	bottom := withCommits(testPR(2, "feat/b", "feat/a"), "bbbbbbbbbbbb", "aaaaaaaaaaaa")
+
+	tests := []struct {
+		name   string
+		parent *bbcloud.PullRequest
+		want   bool
+	}{
+		{"merged at the base commit", withCommits(testPR(1, "feat/a", "main"), "aaaaaaaaaaaa0000", "cccc"), true},
+		{"reused branch name", withCommits(testPR(1, "feat/a", "main"), "dddddddddddd", "cccc"), false},
+		{"into the same branch", withCommits(testPR(1, "feat/a", "feat/a"), "aaaaaaaaaaaa", "cccc"), false},
+		{"other branch", withCommits(testPR(1, "feat/x", "main"), "aaaaaaaaaaaa", "cccc"), false},
+		{"no commit", func() *bbcloud.PullRequest { pr := testPR(1, "feat/a", "main"); return &pr }(), false},
+	}
+	for _, tt := range tests {
+		if got := isMergedParent(tt.parent, bottom); got != tt.want {
+			t.Errorf("%s: isMergedParent = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStackQueries(t *testing.T) {
+	if got := stackQueries(nil); len(got) != 0 {
+		t.Errorf("stackQueries(nil) = %q", got)
+	}
+
+	got := stackQueries([]bbcloud.PullRequest{testPR(1, "feat/a", "main"), testPR(2, "feat/b", "main")})
+	want := `state = "OPEN" AND (source.branch.name = "main" OR destination.branch.name = "feat/a" OR destination.branch.name = "feat/b")`
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("stackQueries = %q, want [%q]", got, want)
+	}
+
+	var prs []bbcloud.PullRequest
+	for i := range stackQueryBranches {
+		prs = append(prs, testPR(i+1, fmt.Sprintf("feat/%d", i), "main"))
+	}
+	if got := stackQueries(prs); len(got) != 2 {
+		t.Errorf("%d branch names: %d queries, want 2", stackQueryBranches+1, len(got))
+	}
+}
@@ -0,0 +1,120 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+type startOptions struct {
+	repo     string
+	prNumber int
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdStart creates the review start command
+func NewCmdStart(f *cmdutil.Factory) *cobra.Command {
+	opts := &startOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "start <pr-number>",
+		Short: "Start a pending review",
+		Long: `Start a pending review on a pull request.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+Comments added with bb review comment --pending are queued locally instead of
+being posted, then published together by bb review submit. Reviewers are
+notified once rather than per comment. Starting a review that is already
+pending keeps its queued comments.
+
+If a review_checklist is configured (in .bb.yml or the user config), its
+items are listed so every reviewer works through the same criteria; post it
+with bb review submit --checklist.
+
+Examples:
+  # Review a PR in one batch
+  bbc review start 450 --repo test_repo
+  bbc review comment 450 src/auth.ts 23 --repo test_repo --pending "Fix this typo"
+  bbc review comment 450 --repo test_repo --pending "A few nits, otherwise fine"
+  bbc review submit 450 --repo test_repo --approve`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Initialize client
+			client, err := opts.factory.NewBBCloudClient("")
+			if err != nil {
+				return err
+			}
+
+			// Parse PR number
+			prNum, err := parsePRNumber(args[0])
+			if err != nil {
+				return err
+			}
+			opts.prNumber = prNum
+
+			return runStart(cmd.Context(), opts, client)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+
+	return cmd
+}
+
+func runStart(ctx context.Context, opts *startOptions, client *bbcloud.Client) error {
+	checklist, err := reviewChecklist(opts.factory)
+	if err != nil {
+		return err
+	}
+
+	review, err := loadPendingReview(client.Workspace(), opts.repo, opts.prNumber)
+	if err == nil {
+		return writeStartOutput(opts, "resumed", len(review.Comments), checklist)
+	}
+	if !errors.Is(err, errNoPendingReview) {
+		return err
+	}
+
+	// Fail early on a missing or closed PR rather than at submit time
+	pr, err := client.GetPullRequest(ctx, opts.repo, opts.prNumber)
+	if err != nil {
+		return fmt.Errorf("get pull request: %w", err)
+	}
+	if pr.State != "OPEN" {
+		return fmt.Errorf("PR %d is %s", opts.prNumber, pr.State)
+	}
+
+	review = &pendingReview{
+		Workspace: client.Workspace(),
+		Repo:      opts.repo,
+		PR:        opts.prNumber,
+		Started:   time.Now().UTC(),
+		Comments:  []pendingComment{},
+	}
+	if err := review.save(); err != nil {
+		return err
+	}
+
+	return writeStartOutput(opts, "started", 0, checklist)
+}
+
+func writeStartOutput(opts *startOptions, action string, pending int, checklist []string) error {
+	output := map[string]interface{}{
+		"pr":      opts.prNumber,
+		"repo":    opts.repo,
+		"action":  action,
+		"pending": pending,
+	}
+	if len(checklist) > 0 {
+		output["checklist"] = checklist
+	}
+	return cmdutil.WriteJSON(opts.factory.IOStreams.Out, output)
+}
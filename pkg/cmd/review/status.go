@@ -0,0 +1,363 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/httpx"
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+type statusOptions struct {
+	repo     string
+	prNumber int
+	json     bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdStatus creates the review status command
+func NewCmdStatus(f *cmdutil.Factory) *cobra.Command {
+	opts := &statusOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "status [pr-number]",
+		Short: "Show what is blocking a pull request",
+		Long: `Show a compact readiness summary for a pull request: state, build status,
+approvals, change requests, and unresolved inline threads.
+
+It also says whether the PR can be merged as it stands: mergeable is false
+while files conflict with the destination branch (conflicted_files) or, when
+the repository enforces merge checks, while its merge checks for the
+destination branch are unmet (unmet_checks). Reading merge checks takes
+repository admin access; without it checks_unknown is set and only conflicts
+decide mergeable.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+When the PR number is omitted inside a git checkout, the open PR for the
+current branch is used.
+
+Examples:
+  bbc review status 450 --repo test_repo
+  bbc review status --repo test_repo --json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Initialize client
+			client, err := opts.factory.NewBBCloudClient("")
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 0 {
+				opts.prNumber, err = currentBranchPR(cmd.Context(), opts.factory, client, opts.repo)
+			} else {
+				opts.prNumber, err = parsePRNumber(args[0])
+			}
+			if err != nil {
+				return err
+			}
+
+			return runStatus(cmd.Context(), opts, client)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of markdown")
+
+	return cmd
+}
+
+type statusOutput struct {
+	PR                int    `json:"pr"`
+	Title             string `json:"title"`
+	State             string `json:"state"`
+	BuildStatus       string `json:"build_status"`
+	Approvals         int    `json:"approvals"`
+	ChangesRequested  int    `json:"changes_requested"`
+	UnresolvedThreads int    `json:"unresolved_threads"`
+}
+
+// mergeStatus says whether a PR can be merged as it stands
+type mergeStatus struct {
+	Mergeable       bool     `json:"mergeable"`
+	ConflictedFiles []string `json:"conflicted_files"`
+	// UnmetChecks describes the destination branch's merge checks the PR
+	// does not meet; they block the merge only when ChecksEnforced
+	UnmetChecks    []string `json:"unmet_checks"`
+	ChecksEnforced bool     `json:"checks_enforced"`
+	// ChecksUnknown is set when the merge checks could not be read
+	ChecksUnknown bool `json:"checks_unknown,omitempty"`
+}
+
+// statusReport is the output of review status
+type statusReport struct {
+	statusOutput
+	mergeStatus
+}
+
+func runStatus(ctx context.Context, opts *statusOptions, client *bbcloud.Client) error {
+	// The PR and its builds are read once for the status and the merge checks
+	ctx = httpx.WithMemo(ctx)
+	status, err := fetchStatus(ctx, client, opts.repo, opts.prNumber)
+	if err != nil {
+		return err
+	}
+	merge, err := fetchMergeStatus(ctx, client, opts.repo, opts.prNumber, status)
+	if err != nil {
+		return err
+	}
+
+	ios, _ := opts.factory.Streams()
+	if opts.json {
+		if err := cmdutil.WriteJSON(ios.Out, statusReport{status, merge}); err != nil {
+			return fmt.Errorf("encode output: %w", err)
+		}
+		return nil
+	}
+
+	if err := renderMarkdownStatus(ios.Out, status); err != nil {
+		return err
+	}
+	return renderMarkdownMergeStatus(ios.Out, merge)
+}
+
+// fetchStatus gathers the PR, its build status and comments concurrently into
+// a readiness summary
+func fetchStatus(ctx context.Context, client *bbcloud.Client, repo string, prNumber int) (statusOutput, error) {
+	var (
+		pr        *bbcloud.PullRequest
+		pipelines []bbcloud.CommitStatus
+		comments  []bbcloud.Comment
+	)
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		pr, err = client.GetPullRequest(gctx, repo, prNumber)
+		if err != nil {
+			return fmt.Errorf("get pull request: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var err error
+		pipelines, err = client.GetPRPipelines(gctx, repo, prNumber)
+		if err != nil {
+			return fmt.Errorf("get build status: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var err error
+		comments, err = client.ListPRComments(gctx, repo, prNumber)
+		if err != nil {
+			return fmt.Errorf("get comments: %w", err)
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return statusOutput{}, err
+	}
+
+	output := statusOutput{
+		PR:                pr.ID,
+		Title:             pr.Title,
+		State:             pr.State,
+		BuildStatus:       "unknown",
+		UnresolvedThreads: countUnresolvedThreads(comments),
+	}
+	if len(pipelines) > 0 && pipelines[0].State != "" {
+		output.BuildStatus = pipelines[0].State
+	}
+	for _, participant := range pr.Participants {
+		if participant.Approved {
+			output.Approvals++
+		}
+		if participant.State == "changes_requested" {
+			output.ChangesRequested++
+		}
+	}
+	return output, nil
+}
+
+// fetchMergeStatus works out whether the PR can be merged: its conflicts,
+// from the diffstat, and its unmet merge checks
+func fetchMergeStatus(ctx context.Context, client *bbcloud.Client, repo string, prNumber int, status statusOutput) (mergeStatus, error) {
+	var merge mergeStatus
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		merge.ConflictedFiles, err = client.GetPRConflicts(gctx, repo, prNumber)
+		if err != nil {
+			return fmt.Errorf("get conflicts: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		checks, err := client.GetPRMergeRestrictions(gctx, repo, prNumber)
+		if errors.Is(err, bbcloud.ErrForbidden) {
+			merge.ChecksUnknown = true
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("get merge checks: %w", err)
+		}
+		for _, check := range checks {
+			if check.Kind == bbcloud.CheckEnforced {
+				merge.ChecksEnforced = true
+				continue
+			}
+			unmet, err := unmetCheck(gctx, client, repo, prNumber, status, check)
+			if err != nil {
+				return fmt.Errorf("check %s: %w", check.Kind, err)
+			}
+			if unmet != "" {
+				merge.UnmetChecks = append(merge.UnmetChecks, unmet)
+			}
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return mergeStatus{}, err
+	}
+
+	// Automation reads empty lists, not nulls
+	if merge.ConflictedFiles == nil {
+		merge.ConflictedFiles = []string{}
+	}
+	if merge.UnmetChecks == nil {
+		merge.UnmetChecks = []string{}
+	}
+	merge.Mergeable = status.State == "OPEN" && len(merge.ConflictedFiles) == 0 &&
+		(!merge.ChecksEnforced || len(merge.UnmetChecks) == 0)
+	return merge, nil
+}
+
+// unmetCheck describes how the PR falls short of a merge check, or returns
+// "" when it meets it
+func unmetCheck(ctx context.Context, client *bbcloud.Client, repo string, prNumber int, status statusOutput, check bbcloud.BranchRestriction) (string, error) {
+	want := 0
+	if check.Value != nil {
+		want = *check.Value
+	}
+
+	switch check.Kind {
+	case bbcloud.CheckApprovals:
+		if status.Approvals < want {
+			return fmt.Sprintf("needs %d approvals, has %d", want, status.Approvals), nil
+		}
+	case bbcloud.CheckNoChangesRequested:
+		if status.ChangesRequested > 0 {
+			return fmt.Sprintf("needs no change requests, has %d", status.ChangesRequested), nil
+		}
+	case bbcloud.CheckDefaultReviewerApprovals:
+		reviewers, err := client.ListDefaultReviewers(ctx, repo)
+		if err != nil {
+			return "", err
+		}
+		pr, err := client.GetPullRequest(ctx, repo, prNumber)
+		if err != nil {
+			return "", err
+		}
+		approvals := 0
+		for _, p := range pr.Participants {
+			for _, r := range reviewers {
+				if p.Approved && p.User != nil && p.User.UUID == r.UUID {
+					approvals++
+				}
+			}
+		}
+		if approvals < want {
+			return fmt.Sprintf("needs %d default reviewer approvals, has %d", want, approvals), nil
+		}
+	case bbcloud.CheckPassingBuilds:
+		builds, err := client.GetPRPipelines(ctx, repo, prNumber)
+		if err != nil {
+			return "", err
+		}
+		passed, other := 0, 0
+		for _, b := range builds {
+			if b.State == "SUCCESSFUL" {
+				passed++
+			} else {
+				other++
+			}
+		}
+		if passed < want || other > 0 {
+			return fmt.Sprintf("needs %d passing builds and none failing or running, has %d passing and %d other", want, passed, other), nil
+		}
+	case bbcloud.CheckTasksCompleted:
+		tasks, err := client.ListPRTasks(ctx, repo, prNumber)
+		if err != nil {
+			return "", err
+		}
+		open := 0
+		for _, t := range tasks {
+			if t.State != "RESOLVED" {
+				open++
+			}
+		}
+		if open > 0 {
+			return fmt.Sprintf("needs every task done, has %d open", open), nil
+		}
+	case bbcloud.CheckCommitsBehind:
+		pr, err := client.GetPullRequest(ctx, repo, prNumber)
+		if err != nil {
+			return "", err
+		}
+		if pr.Source == nil || pr.Source.Commit == nil || pr.Destination == nil || pr.Destination.Branch == nil {
+			return "", nil
+		}
+		dest := pr.Destination.Branch.Name
+		behind, _, err := client.CountCommits(ctx, repo, dest, pr.Source.Commit.Hash, want+1)
+		if err != nil {
+			return "", err
+		}
+		if behind > want {
+			return fmt.Sprintf("needs to be at most %d commits behind %s, is %d or more", want, dest, behind), nil
+		}
+	}
+	return "", nil
+}
+
+func renderMarkdownStatus(w io.Writer, output statusOutput) error {
+	_, _ = fmt.Fprintf(w, "# PR %d: %s\n", output.PR, text.Line(output.Title))
+	_, _ = fmt.Fprintf(w, "State: %s | Build: %s\n", output.State, output.BuildStatus)
+	_, _ = fmt.Fprintf(w, "Approvals: %d | Changes requested: %d\n", output.Approvals, output.ChangesRequested)
+	_, _ = fmt.Fprintf(w, "Unresolved threads: %d\n", output.UnresolvedThreads)
+	return nil
+}
+
+func renderMarkdownMergeStatus(w io.Writer, output mergeStatus) error {
+	mergeable := "no"
+	if output.Mergeable {
+		mergeable = "yes"
+	}
+	_, _ = fmt.Fprintf(w, "Mergeable: %s | Conflicts: %d\n", mergeable, len(output.ConflictedFiles))
+	for _, path := range output.ConflictedFiles {
+		_, _ = fmt.Fprintf(w, "- conflict: %s\n", text.Line(path))
+	}
+	switch {
+	case output.ChecksUnknown:
+		_, _ = fmt.Fprintln(w, "Merge checks: unknown (reading them takes repository admin access)")
+	case len(output.UnmetChecks) > 0:
+		enforced := "not enforced"
+		if output.ChecksEnforced {
+			enforced = "enforced"
+		}
+		_, _ = fmt.Fprintf(w, "Unmet merge checks (%s):\n", enforced)
+		for _, check := range output.UnmetChecks {
+			_, _ = fmt.Fprintf(w, "- %s\n", check)
+		}
+	}
+	return nil
+}
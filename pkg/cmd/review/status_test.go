@@ -0,0 +1,82 @@
+package review
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/bbtest"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/iostreams"
+)
+
+func TestStatusMergeability(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	srv := bbtest.NewServer(t)
+	srv.AddRepository("acme", bbcloud.Repository{Slug: "api"})
+	srv.AddPullRequest("acme", "api", bbcloud.PullRequest{
+		Title:        "Add auth",
+		Source:       &bbcloud.PullRequestBranch{Branch: &bbcloud.Branch{Name: "auth"}, Commit: &bbcloud.CommitReference{Hash: "abc123"}},
+		Destination:  &bbcloud.PullRequestBranch{Branch: &bbcloud.Branch{Name: "main"}},
+		Participants: []bbcloud.Participant{{User: &bbcloud.User{UUID: "{r1}"}, Approved: true}},
+	})
+	srv.AddDiffStat("acme", "api", 1, bbcloud.FileStats{New: &bbcloud.FileInfo{Path: "auth.go"}})
+	srv.Handle(http.MethodGet, "/repositories/acme/api/commit/abc123/statuses", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"values": [{"state": "SUCCESSFUL"}]}`))
+	})
+	two := 2
+	srv.AddBranchRestriction("acme", "api", bbcloud.BranchRestriction{Kind: bbcloud.CheckApprovals, Pattern: "main", Value: &two})
+	srv.AddBranchRestriction("acme", "api", bbcloud.BranchRestriction{Kind: bbcloud.CheckPassingBuilds, Pattern: "main", Value: new(int)})
+	client := srv.Client(t, "acme")
+
+	run := func(asJSON bool) string {
+		t.Helper()
+		out := &bytes.Buffer{}
+		f := cmdutil.NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: out, ErrOut: &bytes.Buffer{}})
+		if err := runStatus(context.Background(), &statusOptions{repo: "api", prNumber: 1, json: asJSON, factory: f}, client); err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+	status := func() map[string]any {
+		t.Helper()
+		var got map[string]any
+		if err := json.Unmarshal([]byte(run(true)), &got); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	// Unmet checks that are not enforced only warn
+	got := status()
+	if got["mergeable"] != true || got["approvals"] != float64(1) {
+		t.Errorf("status = %v, want mergeable", got)
+	}
+	if unmet, _ := got["unmet_checks"].([]any); len(unmet) != 1 || unmet[0] != "needs 2 approvals, has 1" {
+		t.Errorf("unmet_checks = %v", got["unmet_checks"])
+	}
+
+	srv.AddBranchRestriction("acme", "api", bbcloud.BranchRestriction{Kind: bbcloud.CheckEnforced, Pattern: "*"})
+	if got := status(); got["mergeable"] != false || got["checks_enforced"] != true {
+		t.Errorf("status = %v, want blocked by the enforced check", got)
+	}
+
+	// Conflicts block the merge; checks only admins can read are unknown
+	srv.AddDiffStat("acme", "api", 1, bbcloud.FileStats{New: &bbcloud.FileInfo{Path: "go.mod"}, Status: "merge conflict"})
+	srv.Handle(http.MethodGet, "/repositories/acme/api/branch-restrictions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"type": "error", "error": {"message": "Access denied"}}`))
+	})
+	got = status()
+	if files, _ := got["conflicted_files"].([]any); got["mergeable"] != false || got["checks_unknown"] != true || len(files) != 1 || files[0] != "go.mod" {
+		t.Errorf("status = %v, want go.mod conflicting and checks unknown", got)
+	}
+	if md := run(false); !strings.Contains(md, "Mergeable: no | Conflicts: 1") || !strings.Contains(md, "- conflict: go.mod") {
+		t.Errorf("markdown:\n%s", md)
+	}
+}
@@ -0,0 +1,215 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/httpx"
+)
+
+type submitOptions struct {
+	repo           string
+	prNumber       int
+	approve        bool
+	requestChanges bool
+	body           string
+	discard        bool
+	checklist      bool
+	check          []int
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdSubmit creates the review submit command
+func NewCmdSubmit(f *cmdutil.Factory) *cobra.Command {
+	opts := &submitOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "submit <pr-number>",
+		Short: "Submit a pending review",
+		Long: `Post every comment queued with bb review comment --pending in one step.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+Use --approve or --request-changes to set your review status after the
+comments are posted, and --body to add a summary comment. If a comment fails
+to post, the ones not yet posted stay pending so submit can be retried.
+
+Use --checklist to post the configured review_checklist as a task list
+comment; --check ticks items by number (and implies --checklist).
+
+Use --discard to drop the pending review without posting anything.
+
+Examples:
+  # Post queued comments and approve
+  bbc review submit 450 --repo test_repo --approve
+
+  # Post queued comments with a summary and request changes
+  bbc review submit 450 --repo test_repo --request-changes --body "Needs tests"
+
+  # Post the team checklist with items 1 and 3 ticked
+  bbc review submit 450 --repo test_repo --approve --check 1,3
+
+  # Throw the pending review away
+  bbc review submit 450 --repo test_repo --discard`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.approve && opts.requestChanges {
+				return fmt.Errorf("--approve and --request-changes are mutually exclusive")
+			}
+			if len(opts.check) > 0 {
+				opts.checklist = true
+			}
+			if opts.discard && (opts.approve || opts.requestChanges || opts.body != "" || opts.checklist) {
+				return fmt.Errorf("--discard cannot be combined with other submit flags")
+			}
+
+			// Initialize client
+			client, err := opts.factory.NewBBCloudClient("")
+			if err != nil {
+				return err
+			}
+
+			// Parse PR number
+			prNum, err := parsePRNumber(args[0])
+			if err != nil {
+				return err
+			}
+			opts.prNumber = prNum
+
+			return runSubmit(cmd.Context(), opts, client)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+	cmd.Flags().BoolVar(&opts.approve, "approve", false, "Approve the PR after posting comments")
+	cmd.Flags().BoolVar(&opts.requestChanges, "request-changes", false, "Request changes after posting comments")
+	cmd.Flags().StringVarP(&opts.body, "body", "b", "", "Summary comment posted with the review")
+	cmd.Flags().BoolVar(&opts.checklist, "checklist", false, "Post the configured review checklist as a comment")
+	cmd.Flags().IntSliceVar(&opts.check, "check", nil, "Checklist item numbers to tick (implies --checklist)")
+	cmd.Flags().BoolVar(&opts.discard, "discard", false, "Discard the pending review without posting")
+
+	return cmd
+}
+
+func runSubmit(ctx context.Context, opts *submitOptions, client *bbcloud.Client) error {
+	review, err := loadPendingReview(client.Workspace(), opts.repo, opts.prNumber)
+	if err != nil {
+		return err
+	}
+
+	if opts.discard {
+		if err := review.discard(); err != nil {
+			return err
+		}
+		return cmdutil.WriteJSON(opts.factory.IOStreams.Out, map[string]interface{}{
+			"pr":        opts.prNumber,
+			"repo":      opts.repo,
+			"action":    "discarded",
+			"discarded": len(review.Comments),
+		})
+	}
+
+	comments := review.Comments
+	if opts.checklist {
+		items, err := reviewChecklist(opts.factory)
+		if err != nil {
+			return err
+		}
+		checklist, err := formatChecklist(items, opts.check)
+		if err != nil {
+			return err
+		}
+		comments = append(comments, pendingComment{Message: checklist})
+	}
+	if strings.TrimSpace(opts.body) != "" {
+		comments = append(comments, pendingComment{Message: resolveMentions(ctx, opts.factory, client, opts.body)})
+	}
+
+	// Under --dry-run each request is printed rather than sent: the comments
+	// stay pending, and stdout holds only the printed requests
+	dryRun := opts.factory.DryRun
+	var ids []int
+	for i, c := range comments {
+		id, err := postPendingComment(ctx, client, opts.repo, opts.prNumber, c)
+		if errors.Is(err, httpx.ErrDryRun) {
+			dryRun = true
+			continue
+		}
+		if err != nil {
+			// Keep what was not posted so the submit can be retried
+			if i < len(review.Comments) {
+				review.Comments = review.Comments[i:]
+			} else {
+				review.Comments = nil
+			}
+			if saveErr := review.save(); saveErr != nil {
+				return fmt.Errorf("post comment: %w (and %v)", err, saveErr)
+			}
+			return fmt.Errorf("post comment %d of %d: %w (unposted comments remain pending)", i+1, len(comments), err)
+		}
+		ids = append(ids, id)
+	}
+
+	if !dryRun {
+		if err := review.discard(); err != nil {
+			return err
+		}
+	}
+
+	output := map[string]interface{}{
+		"pr":          opts.prNumber,
+		"repo":        opts.repo,
+		"action":      "submitted",
+		"comment_ids": ids,
+	}
+
+	// Status is set last so reviewers see the comments alongside the decision
+	switch {
+	case opts.approve:
+		if _, err := client.ApprovePR(ctx, opts.repo, opts.prNumber); errors.Is(err, httpx.ErrDryRun) {
+			dryRun = true
+		} else if err != nil {
+			output["error"] = friendlyError(err)
+		} else {
+			output["status"] = "approved"
+		}
+	case opts.requestChanges:
+		if _, err := client.RequestChangesPR(ctx, opts.repo, opts.prNumber); errors.Is(err, httpx.ErrDryRun) {
+			dryRun = true
+		} else if err != nil {
+			output["error"] = friendlyError(err)
+		} else {
+			output["status"] = "changes_requested"
+		}
+	}
+	if dryRun {
+		return httpx.ErrDryRun
+	}
+
+	return cmdutil.WriteJSON(opts.factory.IOStreams.Out, output)
+}
+
+// postPendingComment publishes a queued comment and returns its ID
+func postPendingComment(ctx context.Context, client *bbcloud.Client, repo string, pr int, c pendingComment) (int, error) {
+	if c.File == "" {
+		comment, err := client.CreateComment(ctx, repo, pr, c.Message)
+		if err != nil {
+			return 0, err
+		}
+		return comment.ID, nil
+	}
+
+	lineStart, lineEnd := inlineRange(c.LineStart, c.LineEnd)
+	comment, err := client.CreateInlineComment(ctx, repo, pr, c.Message, c.File, lineStart, lineEnd)
+	if err != nil {
+		return 0, err
+	}
+	return comment.ID, nil
+}
@@ -0,0 +1,102 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+type threadOptions struct {
+	repo      string
+	prNumber  int
+	commentID int
+	json      bool
+	raw       bool
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdThread creates the review thread command
+func NewCmdThread(f *cmdutil.Factory) *cobra.Command {
+	opts := &threadOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "thread <pr-number> <comment-id>",
+		Short: "Show the comment thread containing a comment",
+		Long: `Show a whole comment thread: the root comment and every nested reply.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+The comment ID may be the root or any reply in the thread. On a terminal,
+comment bodies are rendered as styled markdown; --raw prints them as written.
+
+Examples:
+  bbc review thread 450 753222173 --repo test_repo
+  bbc review thread 450 753222173 --repo test_repo --json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Initialize client
+			client, err := opts.factory.NewBBCloudClient("")
+			if err != nil {
+				return err
+			}
+
+			// Parse PR number
+			prNum, err := parsePRNumber(args[0])
+			if err != nil {
+				return err
+			}
+			opts.prNumber = prNum
+
+			// Parse comment ID
+			commentID, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid comment ID: %s", args[1])
+			}
+			if commentID <= 0 {
+				return fmt.Errorf("comment ID must be positive")
+			}
+			opts.commentID = commentID
+
+			return runThread(cmd.Context(), opts, client)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of markdown")
+	cmd.Flags().BoolVar(&opts.raw, "raw", false, "Print comment bodies as raw markdown, even on a terminal")
+
+	return cmd
+}
+
+func runThread(ctx context.Context, opts *threadOptions, client *bbcloud.Client) error {
+	// The full list is needed anyway to find replies, so no per-comment lookups
+	comments, err := client.ListPRComments(ctx, opts.repo, opts.prNumber)
+	if err != nil {
+		return fmt.Errorf("get comments: %w", err)
+	}
+	comments = readableMentions(ctx, opts.factory, client, comments)
+
+	root := findThread(buildThreads(comments), opts.commentID)
+	if root == nil {
+		return fmt.Errorf("comment %d not found on PR %d", opts.commentID, opts.prNumber)
+	}
+	thread := newThreadInfo(root)
+
+	ios, _ := opts.factory.Streams()
+	if opts.json {
+		if err := cmdutil.WriteJSON(ios.Out, thread); err != nil {
+			return fmt.Errorf("encode output: %w", err)
+		}
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(ios.Out, "# PR %d — thread %d\n", opts.prNumber, thread.ID)
+	renderThread(ios.Out, newBodyFormatter(ios, opts.raw), thread)
+	return nil
+}
@@ -0,0 +1,107 @@
+package review
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+// commentNode is a comment with its direct replies
+type commentNode struct {
+	Comment bbcloud.Comment
+	Replies []*commentNode
+}
+
+// buildThreads arranges a PR's flat comment list into reply trees, preserving
+// API order. Deleted comments are dropped and their replies attached to the
+// nearest live ancestor, so no feedback disappears. Replies whose parent is
+// missing become roots.
+func buildThreads(comments []bbcloud.Comment) []*commentNode {
+	nodes := make(map[int]*commentNode, len(comments))
+	for _, c := range comments {
+		nodes[c.ID] = &commentNode{Comment: c}
+	}
+
+	var roots []*commentNode
+	for _, c := range comments {
+		node := nodes[c.ID]
+		var parent *commentNode
+		if c.Parent != nil {
+			parent = nodes[c.Parent.ID]
+			// Skip over deleted ancestors
+			for parent != nil && parent.Comment.Deleted {
+				if parent.Comment.Parent == nil {
+					parent = nil
+					break
+				}
+				parent = nodes[parent.Comment.Parent.ID]
+			}
+		}
+		if c.Deleted {
+			continue
+		}
+		if parent == nil {
+			roots = append(roots, node)
+		} else {
+			parent.Replies = append(parent.Replies, node)
+		}
+	}
+	return roots
+}
+
+// findThread returns the root of the thread containing comment id
+func findThread(roots []*commentNode, id int) *commentNode {
+	for _, root := range roots {
+		if root.contains(id) {
+			return root
+		}
+	}
+	return nil
+}
+
+func (n *commentNode) contains(id int) bool {
+	if n.Comment.ID == id {
+		return true
+	}
+	for _, r := range n.Replies {
+		if r.contains(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// newReplyInfos converts reply nodes to their nested output form
+func newReplyInfos(nodes []*commentNode) []replyInfo {
+	replies := make([]replyInfo, 0, len(nodes))
+	for _, n := range nodes {
+		r := replyInfo{
+			ID:      n.Comment.ID,
+			Created: n.Comment.CreatedOn.Format("2006-01-02T15:04:05Z07:00"),
+		}
+		if n.Comment.User != nil {
+			r.Author, r.AuthorID = n.Comment.User.DisplayName, n.Comment.User.UUID
+		}
+		if n.Comment.Content != nil {
+			r.Text = n.Comment.Content.Raw
+		}
+		if len(n.Replies) > 0 {
+			r.Replies = newReplyInfos(n.Replies)
+		}
+		replies = append(replies, r)
+	}
+	return replies
+}
+
+// renderReplies writes replies as nested quotes, one level of indentation per depth
+func renderReplies(w io.Writer, body bodyFormatter, replies []replyInfo, parentID, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, r := range replies {
+		_, _ = fmt.Fprintf(w, "%s> **%s** (id:%s, reply to comment:%d) (comment:%d): %s\n",
+			indent, text.Line(r.Author), r.AuthorID, parentID, r.ID, body.comment(r.Text, indent))
+		renderReplies(w, body, r.Replies, r.ID, depth+1)
+	}
+}
@@ -0,0 +1,89 @@
+package review
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+)
+
+func TestBuildThreads(t *testing.T) {
+	ref := func(id int) *bbcloud.CommentRef { return &bbcloud.CommentRef{ID: id} }
+	comments := []bbcloud.Comment{
+		{ID: 1},
+		{ID: 2, Parent: ref(1), User: &bbcloud.User{DisplayName: "Bob"}, Content: &bbcloud.Content{Raw: "why?"}},
+		{ID: 3, Parent: ref(2), User: &bbcloud.User{DisplayName: "Ann"}, Content: &bbcloud.Content{Raw: "because"}},
+		{ID: 4},
+		{ID: 5, Parent: ref(4), Deleted: true},
+		{ID: 6, Parent: ref(5)},  // reply to a deleted comment
+		{ID: 7, Parent: ref(99)}, // parent not in the list
+	}
+
+	roots := buildThreads(comments)
+	if len(roots) != 3 || roots[0].Comment.ID != 1 || roots[1].Comment.ID != 4 || roots[2].Comment.ID != 7 {
+		t.Fatalf("unexpected roots: %v", rootIDs(roots))
+	}
+	if len(roots[0].Replies) != 1 || len(roots[0].Replies[0].Replies) != 1 || roots[0].Replies[0].Replies[0].Comment.ID != 3 {
+		t.Error("expected 1 → 2 → 3 nesting")
+	}
+	if len(roots[1].Replies) != 1 || roots[1].Replies[0].Comment.ID != 6 {
+		t.Error("expected reply to deleted comment to attach to its live ancestor")
+	}
+
+	if root := findThread(roots, 3); root == nil || root.Comment.ID != 1 {
+		t.Error("findThread(3) should return root 1")
+	}
+	if findThread(roots, 5) != nil {
+		t.Error("deleted comments should not be found")
+	}
+
+	var buf bytes.Buffer
+	renderReplies(&buf, bodyFormatter{}, newReplyInfos(roots[0].Replies), 1, 1)
+	want := "  > **Bob** (id:, reply to comment:1) (comment:2): why?\n    > **Ann** (id:, reply to comment:2) (comment:3): because\n"
+	if buf.String() != want {
+		t.Errorf("renderReplies =\n%q\nwant\n%q", buf.String(), want)
+	}
+}
+
+func rootIDs(roots []*commentNode) []int {
+	var ids []int
+	for _, r := range roots {
+		ids = append(ids, r.Comment.ID)
+	}
+	return ids
+}
+
+func TestBodyFormatterComment(t *testing.T) {
+	if got := (bodyFormatter{}).comment(`see \[docs\]`, "  "); got != "see [docs]" {
+		t.Errorf("raw comment = %q", got)
+	}
+
+	styled := bodyFormatter{styled: true, width: 80}
+	if got := styled.comment("**LGTM**", ""); got != "LGTM" {
+		t.Errorf("one-line comment = %q, want it inline", got)
+	}
+	got := styled.comment("Two things:\n\n- naming\n- tests", "  ")
+	want := "\n    Two things:\n\n    • naming\n    • tests"
+	if got != want {
+		t.Errorf("multi-line comment =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestRenderThreadStripsControlSequences(t *testing.T) {
+	var buf bytes.Buffer
+	renderThread(&buf, bodyFormatter{}, threadInfo{
+		ID:     1,
+		Author: "mallory\x1b]0;pwned\a",
+		File:   "main.go\u202e",
+		Line:   3,
+		Text:   "looks fine\x1b[2J\x1b[H\rapproved",
+	})
+	got := buf.String()
+	if strings.ContainsAny(got, "\x1b\r\a\u202e") {
+		t.Errorf("control characters survived: %q", got)
+	}
+	if !strings.Contains(got, "**mallory** (id:) on main.go:3, unresolved (comment:1): looks fineapproved") {
+		t.Errorf("got %q", got)
+	}
+}
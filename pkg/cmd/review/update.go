@@ -17,6 +17,7 @@ type updateOptions struct {
 	prID        int
 	title       string
 	description string
+	base        string
 
 	factory *cmdutil.Factory
 }
@@ -28,16 +29,19 @@ func NewCmdUpdate(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "update <pr-id>",
 		Short: "Update a pull request",
-		Long: `Update an existing pull request's title or description.
+		Long: `Update an existing pull request's title, description, or target branch.
 
-Requires --repo flag to specify the repository.
+Requires --repo flag (or a default_repo setting) to specify the repository.
 
 Examples:
   # Update PR title
   bbc review update 123 --repo test_repo --title "New title"
 
   # Update PR description
-  bbc review update 123 --repo test_repo --description "New description"`,
+  bbc review update 123 --repo test_repo --description "New description"
+
+  # Retarget a stacked PR after its parent merged
+  bbc review update 123 --repo test_repo --base main`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client, err := opts.factory.NewBBCloudClient("")
@@ -51,26 +55,31 @@ Examples:
 			}
 			opts.prID = id
 
-			if strings.TrimSpace(opts.title) == "" && strings.TrimSpace(opts.description) == "" {
-				return fmt.Errorf("at least one of --title or --description must be provided")
+			if strings.TrimSpace(opts.title) == "" && strings.TrimSpace(opts.description) == "" && strings.TrimSpace(opts.base) == "" {
+				return fmt.Errorf("at least one of --title, --description, or --base must be provided")
 			}
 
 			return runUpdate(cmd.Context(), opts, client)
 		},
 	}
 
-	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (required)")
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
 	cmd.Flags().StringVarP(&opts.title, "title", "t", "", "Pull request title")
 	cmd.Flags().StringVarP(&opts.description, "description", "d", "", "Pull request description")
-	_ = cmd.MarkFlagRequired("repo")
+	cmd.Flags().StringVar(&opts.base, "base", "", "Retarget the pull request to this destination branch")
 
 	return cmd
 }
 
 func runUpdate(ctx context.Context, opts *updateOptions, client *bbcloud.Client) error {
+	var description *string
+	if opts.description != "" {
+		description = &opts.description
+	}
 	pr, err := client.UpdatePR(ctx, opts.repo, opts.prID, bbcloud.UpdatePROptions{
-		Title:       opts.title,
-		Description: opts.description,
+		Title:             opts.title,
+		Description:       description,
+		DestinationBranch: opts.base,
 	})
 	if err != nil {
 		return fmt.Errorf("update PR: %w", err)
@@ -83,5 +92,9 @@ func runUpdate(ctx context.Context, opts *updateOptions, client *bbcloud.Client)
 		"description": pr.Description,
 	}
 
+	if pr.Destination != nil && pr.Destination.Branch != nil {
+		output["target"] = pr.Destination.Branch.Name
+	}
+
 	return cmdutil.WriteJSON(opts.factory.IOStreams.Out, output)
 }
@@ -0,0 +1,177 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/git"
+)
+
+type updateBranchOptions struct {
+	repo     string
+	prNumber int
+	rebase   bool
+	remote   string
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdUpdateBranch creates the review update-branch command
+func NewCmdUpdateBranch(f *cmdutil.Factory) *cobra.Command {
+	opts := &updateBranchOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "update-branch <pr-number>",
+		Short: "Bring a PR branch up to date with its destination",
+		Long: `Merge the destination branch of a pull request into its source branch and
+push the result, so a PR that is out of date can be merged.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+Must be run inside a local clone of the repository. The work happens in a
+temporary worktree, so the current checkout is left untouched.
+
+--rebase replays the source branch onto the destination instead and
+force-pushes it, failing if someone pushed to the branch in the meantime.
+When run interactively without --rebase, the strategy is asked for;
+--rebase=false merges without asking.
+
+On conflicts nothing is pushed: check the PR out with review checkout and
+resolve them there. With --dry-run the merge or rebase is tried but not
+pushed.
+
+Examples:
+  bbc review update-branch 450 --repo test_repo
+  bbc review update-branch 450 --repo test_repo --rebase`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.factory.NewBBCloudClient("")
+			if err != nil {
+				return err
+			}
+
+			prNum, err := parsePRNumber(args[0])
+			if err != nil {
+				return err
+			}
+			opts.prNumber = prNum
+
+			if !cmd.Flags().Changed("rebase") && opts.factory.IOStreams.CanPrompt() {
+				choice, err := opts.factory.Prompter.Select("Update strategy:", []string{
+					"merge: merge the destination into the branch",
+					"rebase: rebase onto the destination and force-push",
+				})
+				if err != nil {
+					return fmt.Errorf("select strategy: %w", err)
+				}
+				opts.rebase = choice == 1
+			}
+
+			return runUpdateBranch(cmd.Context(), opts, client)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+	cmd.Flags().BoolVar(&opts.rebase, "rebase", false, "Rebase onto the destination branch and force-push instead of merging")
+	cmd.Flags().StringVar(&opts.remote, "remote", "origin", "Git remote of the repository")
+
+	return cmd
+}
+
+type updateBranchOutput struct {
+	PR        int    `json:"pr"`
+	Repo      string `json:"repo"`
+	Branch    string `json:"branch"`
+	Base      string `json:"base"`
+	Strategy  string `json:"strategy"` // merge or rebase
+	OldCommit string `json:"old_commit"`
+	NewCommit string `json:"new_commit"`
+	UpToDate  bool   `json:"up_to_date,omitempty"` // nothing to do
+	Pushed    bool   `json:"pushed"`
+}
+
+func runUpdateBranch(ctx context.Context, opts *updateBranchOptions, client *bbcloud.Client) error {
+	pr, err := client.GetPullRequest(ctx, opts.repo, opts.prNumber)
+	if err != nil {
+		return fmt.Errorf("get pull request: %w", err)
+	}
+	if pr.Source == nil || pr.Source.Branch == nil || pr.Source.Branch.Name == "" ||
+		pr.Destination == nil || pr.Destination.Branch == nil || pr.Destination.Branch.Name == "" {
+		return fmt.Errorf("PR %d has no source or destination branch", opts.prNumber)
+	}
+	if pr.State != "" && pr.State != "OPEN" {
+		return fmt.Errorf("PR %d is %s", opts.prNumber, pr.State)
+	}
+	if isForkPR(pr) {
+		return fmt.Errorf("PR %d comes from the fork %s; update its branch there", opts.prNumber, pr.Source.Repository.FullName)
+	}
+	branch, base := pr.Source.Branch.Name, pr.Destination.Branch.Name
+
+	output := updateBranchOutput{PR: opts.prNumber, Repo: opts.repo, Branch: branch, Base: base, Strategy: "merge"}
+	if opts.rebase {
+		output.Strategy = "rebase"
+	}
+
+	gitClient := opts.factory.GitClient
+	baseCommit, err := gitClient.Fetch(ctx, opts.remote, "refs/heads/"+base)
+	if err != nil {
+		return fmt.Errorf("fetch %s: %w", base, err)
+	}
+	headCommit, err := gitClient.Fetch(ctx, opts.remote, "refs/heads/"+branch)
+	if err != nil {
+		return fmt.Errorf("fetch %s: %w", branch, err)
+	}
+	output.OldCommit, output.NewCommit = headCommit, headCommit
+
+	ios := opts.factory.IOStreams
+	if gitClient.IsAncestor(ctx, baseCommit, headCommit) {
+		output.UpToDate = true
+		return cmdutil.WriteJSON(ios.Out, output)
+	}
+
+	tmp, err := os.MkdirTemp("", "bb-update-branch-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+	dir := filepath.Join(tmp, "worktree")
+	if err := gitClient.AddDetachedWorktree(ctx, dir, headCommit); err != nil {
+		return fmt.Errorf("add worktree: %w", err)
+	}
+	defer func() { _ = gitClient.RemoveWorktree(context.Background(), dir) }()
+
+	worktree := git.New(dir)
+	worktree.GitPath = gitClient.GitPath
+	if opts.rebase {
+		err = worktree.Rebase(ctx, baseCommit)
+	} else {
+		err = worktree.Merge(ctx, baseCommit, fmt.Sprintf("Merge branch '%s' into %s", base, branch))
+	}
+	if errors.Is(err, git.ErrConflict) {
+		return fmt.Errorf("%s %s into %s: %w; resolve them after 'bbc review checkout %d'", output.Strategy, base, branch, err, opts.prNumber)
+	}
+	if err != nil {
+		return fmt.Errorf("%s %s into %s: %w", output.Strategy, base, branch, err)
+	}
+	if output.NewCommit, err = worktree.HeadCommit(ctx); err != nil {
+		return err
+	}
+
+	if !opts.factory.DryRun {
+		lease := ""
+		if opts.rebase {
+			lease = headCommit
+		}
+		if err := worktree.Push(ctx, opts.remote, output.NewCommit, branch, lease); err != nil {
+			return fmt.Errorf("push %s: %w", branch, err)
+		}
+		output.Pushed = true
+	}
+	return cmdutil.WriteJSON(ios.Out, output)
+}
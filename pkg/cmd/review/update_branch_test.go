@@ -0,0 +1,102 @@
+package review
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/bbtest"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/iostreams"
+)
+
+func TestUpdateBranch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+	git := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(dir, file string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(file), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		git(dir, "add", file)
+		git(dir, "commit", "--quiet", "-m", file)
+	}
+
+	// A remote where main moved on after feature branched off
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	git(".", "init", "--quiet", "--bare", "--initial-branch=main", remote)
+	clone := filepath.Join(t.TempDir(), "clone")
+	git(".", "clone", "--quiet", remote, clone)
+	git(clone, "config", "user.name", "test")
+	git(clone, "config", "user.email", "test@example.com")
+	git(clone, "checkout", "--quiet", "-b", "main")
+	commit(clone, "init.txt")
+	git(clone, "checkout", "--quiet", "-b", "feature")
+	commit(clone, "feature.txt")
+	git(clone, "checkout", "--quiet", "main")
+	commit(clone, "main.txt")
+	git(clone, "push", "--quiet", "origin", "main", "feature")
+	oldHead := git(clone, "rev-parse", "feature")
+
+	srv := bbtest.NewServer(t)
+	srv.AddRepository("acme", bbcloud.Repository{Slug: "api"})
+	srv.AddPullRequest("acme", "api", bbcloud.PullRequest{
+		ID:          7,
+		Source:      &bbcloud.PullRequestBranch{Branch: &bbcloud.Branch{Name: "feature"}},
+		Destination: &bbcloud.PullRequestBranch{Branch: &bbcloud.Branch{Name: "main"}},
+	})
+	client := srv.Client(t, "acme")
+
+	run := func() updateBranchOutput {
+		t.Helper()
+		out := &bytes.Buffer{}
+		f := cmdutil.NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: out, ErrOut: &bytes.Buffer{}})
+		f.GitClient.Dir = clone
+		opts := &updateBranchOptions{repo: "api", prNumber: 7, remote: "origin", factory: f}
+		if err := runUpdateBranch(ctx, opts, client); err != nil {
+			t.Fatal(err)
+		}
+		var got updateBranchOutput
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	got := run()
+	if !got.Pushed || got.UpToDate || got.OldCommit != oldHead || got.NewCommit == oldHead {
+		t.Fatalf("update = %+v", got)
+	}
+	if head := git(remote, "rev-parse", "feature"); head != got.NewCommit {
+		t.Errorf("remote feature = %s, want %s", head, got.NewCommit)
+	}
+	if branch := git(clone, "rev-parse", "--abbrev-ref", "HEAD"); branch != "main" {
+		t.Errorf("checkout switched to %s", branch)
+	}
+	if worktrees := git(clone, "worktree", "list"); strings.Count(worktrees, "\n") != 0 {
+		t.Errorf("temporary worktree left behind:\n%s", worktrees)
+	}
+
+	if got := run(); !got.UpToDate || got.Pushed {
+		t.Errorf("second update = %+v, want up to date", got)
+	}
+}
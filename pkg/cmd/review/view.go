@@ -11,13 +11,26 @@ import (
 
 	"github.com/ghoseb/bb/pkg/bbcloud"
 	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/text"
 )
 
 type viewOptions struct {
 	repo     string
 	prNumber int
-	file     string
-	json     bool
+	file      string
+	json      bool
+	diff      bool
+	maxLines  int
+	maxFiles  int
+	page      int
+	fileRange string
+	include   []string
+	exclude   []string
+	context   int
+	split     bool
+	web       bool
+	format    string
+	raw       bool
 
 	factory *cmdutil.Factory
 	client  *bbcloud.Client
@@ -30,15 +43,33 @@ func NewCmdView(f *cmdutil.Factory) *cobra.Command {
 	}
 
 	cmd := &cobra.Command{
-		Use:   "view <pr-number> [file-path]",
+		Use:   "view [pr-number] [file-path]",
 		Short: "View PR details or specific file diff",
 		Long: `View pull request with complete context for review.
 
-Requires --repo flag to specify the repository.
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+When the PR number is omitted inside a git checkout, the open PR for the
+current branch is viewed. Without one, a terminal offers a fuzzy-filterable
+list of the open PRs to pick from; likewise without --repo or a default,
+the repository is picked from the workspace.
 
 Without file argument: Shows PR metadata, files, build status, and review status.
 With file argument: Shows file diff and inline comments.
 
+On a terminal, the description and comments are rendered as styled markdown
+(headings, lists, code blocks) wrapped to its width; --raw prints them as
+written.
+
+Diffs longer than --max-lines (2000 by default) are cut into pages. A page
+that is not the last ends with a marker naming the flags that fetch the next
+one; with --json it is {"truncated": true, "remaining_lines": N, "next":
+"--page 2"}.
+
+The summary lists at most --max-files files (1000 by default); totals still
+count every file, and a longer list ends with "showing N of M files" (with
+--json, "files_truncated": {"shown": N, "total": M}).
+
 For actions, use dedicated commands:
   bbc review comment <pr> --repo <repo> "message"
   bbc review approve <pr> --repo <repo>
@@ -48,10 +79,71 @@ Examples:
   # View complete PR context
   bbc review view 450 --repo test_repo
 
+  # View the PR for the current branch
+  bbc review view --repo test_repo
+
   # View specific file diff with comments
-  bbc review view 450 src/auth.ts --repo test_repo`,
-		Args: cobra.MinimumNArgs(1),
+  bbc review view 450 src/auth.ts --repo test_repo
+
+  # Read the full PR diff in 500-line pages, files 1-10 only
+  bbc review view 450 --repo test_repo --diff --file-range 1:10 --max-lines 500
+  bbc review view 450 --repo test_repo --diff --file-range 1:10 --max-lines 500 --page 2
+
+  # Minimal context to save tokens, or wide context for dense changes
+  bbc review view 450 src/auth.ts --repo test_repo --context 0
+  bbc review view 450 --repo test_repo --diff --context 10
+
+  # Compact diff for agents: changed lines only, exact ranges, short headers
+  bbc review view 450 --repo test_repo --diff --format compact
+
+  # Side-by-side file diff for reading in a terminal
+  bbc review view 450 src/auth.ts --repo test_repo --split
+
+  # Open the PR, or a file's diff, in the browser
+  bbc review view 450 --repo test_repo --web
+  bbc review view 450 src/auth.ts --repo test_repo --web
+
+  # Focus on Go sources, skipping tests
+  bbc review view 450 --repo test_repo --include "src/**/*.go" --exclude "*_test.go"`,
+		Args: cobra.RangeArgs(0, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.maxLines < 0 {
+				return fmt.Errorf("--max-lines must not be negative")
+			}
+			if opts.maxFiles < 0 {
+				return fmt.Errorf("--max-files must not be negative")
+			}
+			if opts.fileRange != "" && (!opts.diff || len(args) > 1) {
+				return fmt.Errorf("--file-range requires --diff without a file argument")
+			}
+			if opts.diff && len(args) > 1 {
+				return fmt.Errorf("--diff views the whole PR; omit the file argument")
+			}
+			if err := opts.filter().validate(); err != nil {
+				return err
+			}
+			if opts.context < 0 {
+				return fmt.Errorf("--context must not be negative")
+			}
+			if opts.split && (len(args) < 2 || opts.json) {
+				return fmt.Errorf("--split requires a file argument and markdown output")
+			}
+			switch opts.format {
+			case "unified":
+			case "compact":
+				if !opts.diff && len(args) < 2 {
+					return fmt.Errorf("--format compact requires --diff or a file argument")
+				}
+				if opts.split {
+					return fmt.Errorf("--format compact cannot be combined with --split")
+				}
+			default:
+				return fmt.Errorf("invalid --format %q (allowed: unified, compact)", opts.format)
+			}
+			if opts.web && (opts.json || opts.diff || opts.split) {
+				return fmt.Errorf("--web cannot be combined with --json, --diff or --split")
+			}
+
 			// Initialize client
 			client, err := opts.factory.NewBBCloudClient("")
 			if err != nil {
@@ -59,6 +151,30 @@ Examples:
 			}
 			opts.client = client
 
+			// No PR number: fall back to the PR for the current branch, or
+			// pick one of the open PRs on a terminal
+			if len(args) == 0 {
+				prNum, err := currentBranchPR(cmd.Context(), opts.factory, client, opts.repo)
+				if err != nil && opts.factory.IOStreams.CanPrompt() {
+					prNum, err = pickPR(cmd.Context(), opts.factory, client, opts.repo)
+				}
+				if err != nil {
+					return err
+				}
+				opts.prNumber = prNum
+				if opts.web {
+					return runViewWeb(cmd.Context(), opts)
+				}
+				if err := startPager(opts); err != nil {
+					return err
+				}
+				defer opts.factory.IOStreams.StopPager()
+				if opts.diff {
+					return runViewDiff(cmd.Context(), opts)
+				}
+				return runViewPR(cmd.Context(), opts)
+			}
+
 			// Parse PR number
 			prNum, err := parsePRNumber(args[0])
 			if err != nil {
@@ -66,24 +182,62 @@ Examples:
 			}
 			opts.prNumber = prNum
 
-			// Check for file argument
 			if len(args) > 1 {
 				opts.file = args[1]
+			}
+			if opts.web {
+				return runViewWeb(cmd.Context(), opts)
+			}
+			if err := startPager(opts); err != nil {
+				return err
+			}
+			defer opts.factory.IOStreams.StopPager()
+
+			// Check for file argument
+			if opts.file != "" {
 				return runViewFile(cmd.Context(), opts)
 			}
 
+			if opts.diff {
+				return runViewDiff(cmd.Context(), opts)
+			}
+
 			// Default: full PR view
 			return runViewPR(cmd.Context(), opts)
 		},
 	}
 
-	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (required)")
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
 	cmd.Flags().BoolVar(&opts.json, "json", false, "Output JSON instead of markdown")
-	_ = cmd.MarkFlagRequired("repo")
+	cmd.Flags().BoolVar(&opts.diff, "diff", false, "Show the full PR diff instead of the summary")
+	cmd.Flags().IntVar(&opts.maxLines, "max-lines", defaultDiffMaxLines, "Split diffs into pages of at most this many lines (0 for no limit)")
+	cmd.Flags().IntVar(&opts.page, "page", 1, "Diff page to show")
+	cmd.Flags().IntVar(&opts.maxFiles, "max-files", defaultMaxFiles, "List at most this many files in the PR summary (0 for no limit)")
+	cmd.Flags().StringVar(&opts.fileRange, "file-range", "", "Files of the --diff to include, 1-based start:end (e.g. 1:10)")
+	cmd.Flags().StringSliceVar(&opts.include, "include", nil, "Only show files matching these globs (** matches directories)")
+	cmd.Flags().StringSliceVar(&opts.exclude, "exclude", nil, "Hide files matching these globs")
+	cmd.Flags().IntVar(&opts.context, "context", 3, "Unchanged lines of context around each change in diffs")
+	cmd.Flags().BoolVar(&opts.split, "split", false, "Show a file diff side by side, fitted to the terminal width")
+	cmd.Flags().StringVar(&opts.format, "format", "unified", "Diff format: unified, or compact (changed lines only, for agents)")
+	cmd.Flags().BoolVar(&opts.web, "web", false, "Open the PR (or the file's diff) in the browser")
+	cmd.Flags().BoolVar(&opts.raw, "raw", false, "Print the description and comments as raw markdown, even on a terminal")
 
 	return cmd
 }
 
+func (opts *viewOptions) filter() fileFilter {
+	return fileFilter{include: opts.include, exclude: opts.exclude}
+}
+
+// diffOptions requests the chosen context size, leaving the API default alone
+// when it matches
+func (opts *viewOptions) diffOptions() bbcloud.DiffOptions {
+	if opts.context == 3 {
+		return bbcloud.DiffOptions{}
+	}
+	return bbcloud.DiffOptions{Context: &opts.context}
+}
+
 type reviewerInfo struct {
 	Username string `json:"username"`
 	State    string `json:"state"` // "approved" or "changes_requested"
@@ -115,6 +269,10 @@ type prViewOutput struct {
 	TotalAdds   int            `json:"total_additions"`
 	TotalDels   int            `json:"total_deletions"`
 	TotalComments int          `json:"total_comments"`
+	UnresolvedThreads int        `json:"unresolved_threads"`
+	FilteredOut   int            `json:"filtered_out,omitempty"` // files hidden by --include/--exclude
+	FilesTruncated *fileTruncation `json:"files_truncated,omitempty"` // files left out by --max-files
+	Stack       *stackLink     `json:"stack,omitempty"`
 }
 
 func runViewPR(ctx context.Context, opts *viewOptions) error {
@@ -131,6 +289,7 @@ func runViewPR(ctx context.Context, opts *viewOptions) error {
 		diffstat    []bbcloud.FileStats
 		pipelines   []bbcloud.CommitStatus
 		comments    []bbcloud.Comment
+		stack       *stackLink
 		buildStatus = "unknown"
 	)
 
@@ -166,11 +325,23 @@ func runViewPR(ctx context.Context, opts *viewOptions) error {
 		return nil
 	})
 
+	// Fetch stack neighbours (non-critical - log warning on failure, return nil)
+	g.Go(func() error {
+		var err error
+		stack, err = fetchStackLink(gctx, opts.client, opts.repo, pr)
+		if err != nil {
+			_, _ = fmt.Fprintf(ios.ErrOut, "warning: failed to detect PR stack: %v\n", err)
+		}
+		return nil
+	})
+
 	// Wait for all goroutines
 	if err := g.Wait(); err != nil {
 		return err
 	}
 
+	comments = readableMentions(ctx, opts.factory, opts.client, comments)
+
 	// Process pipeline status
 	if len(pipelines) > 0 && pipelines[0].State != "" {
 		buildStatus = pipelines[0].State
@@ -189,8 +360,14 @@ func runViewPR(ctx context.Context, opts *viewOptions) error {
 	files := make([]fileInfo, 0, len(diffstat))
 	totalAdds := 0
 	totalDels := 0
+	filteredOut := 0
+	filter := opts.filter()
 	for _, stat := range diffstat {
 		path := stat.GetPath()
+		if !filter.matches(path) {
+			filteredOut++
+			continue
+		}
 		fi := fileInfo{
 			Path:      path,
 			Status:    stat.Status,
@@ -234,6 +411,13 @@ func runViewPR(ctx context.Context, opts *viewOptions) error {
 		TotalAdds:   totalAdds,
 		TotalDels:   totalDels,
 		TotalComments: totalComments,
+		UnresolvedThreads: countUnresolvedThreads(comments),
+		FilteredOut:   filteredOut,
+		Stack:       stack,
+	}
+	if opts.maxFiles > 0 && len(files) > opts.maxFiles {
+		output.Files = files[:opts.maxFiles]
+		output.FilesTruncated = truncateFiles(opts.maxFiles, len(files))
 	}
 
 	// Output format based on flag
@@ -246,7 +430,7 @@ func runViewPR(ctx context.Context, opts *viewOptions) error {
 	}
 
 	// Output markdown (default)
-	return renderMarkdownPRView(ios.Out, output, comments)
+	return renderMarkdownPRView(ios.Out, newBodyFormatter(ios, opts.raw), output, comments)
 }
 
 type fileViewOutput struct {
@@ -255,8 +439,14 @@ type fileViewOutput struct {
 	Status    string         `json:"status"`
 	Additions int            `json:"additions"`
 	Deletions int            `json:"deletions"`
-	Diff      string         `json:"diff"`      // Raw unified diff
+	Diff      string         `json:"diff"`      // Raw unified diff, or compact with --format compact
+	Format    string         `json:"format"`
 	Comments  []commentInfo  `json:"comments"`
+	Page      int            `json:"page,omitempty"`
+	Pages     int            `json:"pages,omitempty"`
+	NextPage  int            `json:"next_page,omitempty"`
+	More      string         `json:"more,omitempty"` // continuation marker when truncated
+	diffTruncation
 }
 
 type commentInfo struct {
@@ -267,6 +457,7 @@ type commentInfo struct {
 	Text      string          `json:"text"`
 	Created   string          `json:"created"`
 	Inline    bool            `json:"inline"`
+	Resolved  bool            `json:"resolved"`
 	Replies   []replyInfo     `json:"replies"`
 }
 
@@ -276,6 +467,7 @@ type replyInfo struct {
 	AuthorID  string `json:"author_id"`  // UUID for @mentions
 	Text      string `json:"text"`
 	Created   string `json:"created"`
+	Replies   []replyInfo `json:"replies,omitempty"`
 }
 
 // extractFileDiff extracts the diff section for a renamed file from the full PR diff.
@@ -313,7 +505,7 @@ func extractFileDiff(fullDiff, oldPath, newPath string) string {
 
 func runViewFile(ctx context.Context, opts *viewOptions) error {
 	// Fetch diff for this file
-	diff, err := opts.client.GetPRFileDiff(ctx, opts.repo, opts.prNumber, opts.file)
+	diff, err := opts.client.GetPRFileDiff(ctx, opts.repo, opts.prNumber, opts.file, opts.diffOptions())
 	if err != nil {
 		return fmt.Errorf("get file diff: %w", err)
 	}
@@ -348,7 +540,7 @@ func runViewFile(ctx context.Context, opts *viewOptions) error {
 			diff = header
 		} else {
 			// Real changes alongside rename — extract from full PR diff which has proper hunks
-			fullDiff, err := opts.client.GetPRDiff(ctx, opts.repo, opts.prNumber)
+			fullDiff, err := opts.client.GetPRDiff(ctx, opts.repo, opts.prNumber, opts.diffOptions())
 			if err == nil {
 				if section := extractFileDiff(fullDiff, oldPath, opts.file); section != "" {
 					diff = header + "\n" + section
@@ -367,14 +559,13 @@ func runViewFile(ctx context.Context, opts *viewOptions) error {
 		return fmt.Errorf("get comments: %w", err)
 	}
 
-	// Filter comments for this file
+	allComments = readableMentions(ctx, opts.factory, opts.client, allComments)
+
+	// Collect threads rooted on this file, with their nested replies
 	comments := make([]commentInfo, 0)
-	for _, comment := range allComments {
+	for _, thread := range buildThreads(allComments) {
+		comment := thread.Comment
 		if comment.Inline != nil && comment.Inline.Path == opts.file {
-			replies := make([]replyInfo, 0)
-			// Note: Bitbucket API doesn't support nested replies in the same call
-			// Would need separate API call for each comment's replies
-
 			line := 0
 			if comment.Inline.To != nil {
 				line = *comment.Inline.To
@@ -388,7 +579,8 @@ func runViewFile(ctx context.Context, opts *viewOptions) error {
 				Text:     comment.Content.Raw,
 				Created:  comment.CreatedOn.Format("2006-01-02T15:04:05Z07:00"),
 				Inline:   true,
-				Replies:  replies,
+				Resolved: comment.IsResolved(),
+				Replies:  newReplyInfos(thread.Replies),
 			})
 		}
 	}
@@ -400,8 +592,26 @@ func runViewFile(ctx context.Context, opts *viewOptions) error {
 		Additions: additions,
 		Deletions: deletions,
 		Diff:      diff,
+		Format:    opts.format,
 		Comments:  comments,
 	}
+	if opts.format == "compact" {
+		diff = compactDiff(diff)
+		output.Diff = diff
+	}
+
+	if opts.maxLines > 0 || opts.page > 1 {
+		page, err := paginateDiff(diff, opts.maxLines, opts.page)
+		if err != nil {
+			return err
+		}
+		output.Diff = page.Diff
+		output.Page, output.Pages, output.NextPage = page.Page, page.Pages, page.NextPage
+		output.More = page.continuation(opts.maxLines)
+		if t := page.truncation(opts.maxLines); t != nil {
+			output.diffTruncation = *t
+		}
+	}
 
 	// Output format based on flag
 	ios, _ := opts.factory.Streams()
@@ -413,8 +623,12 @@ func runViewFile(ctx context.Context, opts *viewOptions) error {
 		return nil
 	}
 
+	if opts.split {
+		return renderSplitFileView(ios.Out, newBodyFormatter(ios, opts.raw), output, ios.TerminalWidth(), ios.ColorEnabled())
+	}
+
 	// Output markdown (default)
-	return renderMarkdownFileView(ios.Out, output)
+	return renderMarkdownFileView(ios.Out, newBodyFormatter(ios, opts.raw), output)
 }
 
 // unescapeBBMarkdown reverses Bitbucket's markdown escaping for clean output.
@@ -433,10 +647,13 @@ func unescapeBBMarkdown(s string) string {
 	return r.Replace(s)
 }
 
-func renderMarkdownPRView(w io.Writer, output prViewOutput, comments []bbcloud.Comment) error {
-	_, _ = fmt.Fprintf(w, "# PR %d: %s\n", output.ID, output.Title)
-	_, _ = fmt.Fprintf(w, "Author: %s | State: %s | Build: %s\n", output.Author, output.State, output.BuildStatus)
-	_, _ = fmt.Fprintf(w, "Source: %s → %s\n", output.Source, output.Target)
+func renderMarkdownPRView(w io.Writer, body bodyFormatter, output prViewOutput, comments []bbcloud.Comment) error {
+	_, _ = fmt.Fprintf(w, "# PR %d: %s\n", output.ID, text.Line(output.Title))
+	_, _ = fmt.Fprintf(w, "Author: %s | State: %s | Build: %s\n", text.Line(output.Author), output.State, output.BuildStatus)
+	_, _ = fmt.Fprintf(w, "Source: %s → %s\n", text.Line(output.Source), text.Line(output.Target))
+	if output.Stack != nil {
+		_, _ = fmt.Fprintf(w, "Stack: parent %s, children %s\n", formatPRRef(output.Stack.Parent), formatPRRefs(output.Stack.Children))
+	}
 
 	if len(output.Reviewers) > 0 {
 		_, _ = fmt.Fprintf(w, "Reviewers: ")
@@ -444,78 +661,104 @@ func renderMarkdownPRView(w io.Writer, output prViewOutput, comments []bbcloud.C
 			if i > 0 {
 				_, _ = fmt.Fprintf(w, ", ")
 			}
-			_, _ = fmt.Fprintf(w, "%s (%s)", r.Username, r.State)
+			_, _ = fmt.Fprintf(w, "%s (%s)", text.Line(r.Username), r.State)
 		}
 		_, _ = fmt.Fprintf(w, "\n")
 	}
 	
 	if output.Description != "" {
-		_, _ = fmt.Fprintf(w, "\n## Description\n%s\n", unescapeBBMarkdown(output.Description))
+		_, _ = fmt.Fprintf(w, "\n## Description\n%s\n", body.description(output.Description))
 	}
 
-	_, _ = fmt.Fprintf(w, "\n## Files (%d files, +%d, -%d)\n", output.TotalFiles, output.TotalAdds, output.TotalDels)
+	if output.FilteredOut > 0 {
+		_, _ = fmt.Fprintf(w, "\n## Files (%d files, +%d, -%d; %d filtered out)\n", output.TotalFiles, output.TotalAdds, output.TotalDels, output.FilteredOut)
+	} else {
+		_, _ = fmt.Fprintf(w, "\n## Files (%d files, +%d, -%d)\n", output.TotalFiles, output.TotalAdds, output.TotalDels)
+	}
 	for _, f := range output.Files {
+		path, oldPath := text.Line(f.Path), text.Line(f.OldPath)
 		commentStr := ""
 		if f.Comments > 0 {
 			commentStr = fmt.Sprintf(", %d comments", f.Comments)
 		}
 		switch {
 		case f.Status == "renamed" && f.OldPath != "" && f.Additions == 0 && f.Deletions == 0:
-			_, _ = fmt.Fprintf(w, "- %s ← %s (renamed%s)\n", f.Path, f.OldPath, commentStr)
+			_, _ = fmt.Fprintf(w, "- %s ← %s (renamed%s)\n", path, oldPath, commentStr)
 		case f.Status == "renamed" && f.OldPath != "":
-			_, _ = fmt.Fprintf(w, "- %s ← %s (renamed, +%d/-%d%s)\n", f.Path, f.OldPath, f.Additions, f.Deletions, commentStr)
+			_, _ = fmt.Fprintf(w, "- %s ← %s (renamed, +%d/-%d%s)\n", path, oldPath, f.Additions, f.Deletions, commentStr)
 		default:
-			_, _ = fmt.Fprintf(w, "- %s (+%d/-%d%s)\n", f.Path, f.Additions, f.Deletions, commentStr)
+			_, _ = fmt.Fprintf(w, "- %s (+%d/-%d%s)\n", path, f.Additions, f.Deletions, commentStr)
 		}
 	}
+	if output.FilesTruncated != nil {
+		_, _ = fmt.Fprintln(w, output.FilesTruncated.marker())
+	}
 	
 	if output.TotalComments > 0 {
-		_, _ = fmt.Fprintf(w, "\n## Comments (%d)\n", output.TotalComments)
-		for _, comment := range comments {
+		_, _ = fmt.Fprintf(w, "\n## Comments (%d, %d unresolved threads)\n", output.TotalComments, output.UnresolvedThreads)
+		for _, thread := range buildThreads(comments) {
+			comment := thread.Comment
 			if comment.Inline != nil {
 				line := 0
 				if comment.Inline.To != nil {
 					line = *comment.Inline.To
 				}
-				_, _ = fmt.Fprintf(w, "**%s** (id:%s) on %s:%d (comment:%d): %s\n",
-					comment.User.DisplayName,
+				resolved := ""
+				if comment.IsResolved() {
+					resolved = ", resolved"
+				}
+				_, _ = fmt.Fprintf(w, "**%s** (id:%s) on %s:%d%s (comment:%d): %s\n",
+					text.Line(comment.User.DisplayName),
 					comment.User.UUID,
-					comment.Inline.Path,
+					text.Line(comment.Inline.Path),
 					line,
+					resolved,
 					comment.ID,
-					unescapeBBMarkdown(comment.Content.Raw))
+					body.comment(comment.Content.Raw, ""))
 			} else {
 				_, _ = fmt.Fprintf(w, "**%s** (id:%s, general) (comment:%d): %s\n",
-					comment.User.DisplayName,
+					text.Line(comment.User.DisplayName),
 					comment.User.UUID,
 					comment.ID,
-					unescapeBBMarkdown(comment.Content.Raw))
+					body.comment(comment.Content.Raw, ""))
 			}
 			
 			// Render replies
-			if comment.Parent == nil {
-				for _, reply := range comments {
-					if reply.Parent != nil && reply.Parent.ID == comment.ID {
-						_, _ = fmt.Fprintf(w, "  > **%s** (id:%s, reply to comment:%d): %s\n",
-							reply.User.DisplayName,
-							reply.User.UUID,
-							comment.ID,
-							unescapeBBMarkdown(reply.Content.Raw))
-					}
-				}
-			}
+			renderReplies(w, body, newReplyInfos(thread.Replies), comment.ID, 1)
 		}
 	}
 	
 	return nil
 }
 
-func renderMarkdownFileView(w io.Writer, output fileViewOutput) error {
-	_, _ = fmt.Fprintf(w, "# PR %d — %s\n", output.PR, output.File)
+func renderMarkdownFileView(w io.Writer, body bodyFormatter, output fileViewOutput) error {
+	_, _ = fmt.Fprintf(w, "# PR %d — %s\n", output.PR, text.Line(output.File))
 	_, _ = fmt.Fprintf(w, "Status: %s | +%d -%d\n\n", output.Status, output.Additions, output.Deletions)
 	
-	_, _ = fmt.Fprintf(w, "```diff\n%s```\n", output.Diff)
-	
+	_, _ = fmt.Fprintf(w, "```%s\n%s```\n", fenceLang(output.Format), text.Sanitize(output.Diff))
+	if output.More != "" {
+		_, _ = fmt.Fprintf(w, "%s\n", output.More)
+	}
+
+	renderFileComments(w, body, output)
+	return nil
+}
+
+// renderSplitFileView renders the file diff as old/new columns fitted to width
+func renderSplitFileView(w io.Writer, body bodyFormatter, output fileViewOutput, width int, color bool) error {
+	_, _ = fmt.Fprintf(w, "# PR %d — %s\n", output.PR, text.Line(output.File))
+	_, _ = fmt.Fprintf(w, "Status: %s | +%d -%d\n\n", output.Status, output.Additions, output.Deletions)
+
+	renderSplitDiff(w, output.Diff, width, color)
+	if output.More != "" {
+		_, _ = fmt.Fprintf(w, "%s\n", output.More)
+	}
+
+	renderFileComments(w, body, output)
+	return nil
+}
+
+func renderFileComments(w io.Writer, body bodyFormatter, output fileViewOutput) {
 	if len(output.Comments) > 0 {
 		_, _ = fmt.Fprintf(w, "\n## Comments (%d)\n", len(output.Comments))
 		for _, comment := range output.Comments {
@@ -524,22 +767,160 @@ func renderMarkdownFileView(w io.Writer, output fileViewOutput) error {
 				lineStr = fmt.Sprintf(", line %d", comment.Line)
 			}
 			_, _ = fmt.Fprintf(w, "**%s** (id:%s%s) (comment:%d): %s\n",
-				comment.Author,
+				text.Line(comment.Author),
 				comment.AuthorID,
 				lineStr,
 				comment.ID,
-				unescapeBBMarkdown(comment.Text))
+				body.comment(comment.Text, ""))
 			
 			// Render replies
-			for _, reply := range comment.Replies {
-				_, _ = fmt.Fprintf(w, "  > **%s** (id:%s, reply to comment:%d): %s\n",
-					reply.Author,
-					reply.AuthorID,
-					comment.ID,
-					unescapeBBMarkdown(reply.Text))
+			renderReplies(w, body, comment.Replies, comment.ID, 1)
+		}
+	}
+}
+
+type diffViewOutput struct {
+	PR          int    `json:"pr"`
+	Files       int    `json:"files"` // files in the PR diff, after filtering
+	FilteredOut int    `json:"filtered_out,omitempty"`
+	FirstFile   int    `json:"first_file,omitempty"` // --file-range bounds, 1-based
+	LastFile    int    `json:"last_file,omitempty"`
+	Diff        string `json:"diff"`
+	Format      string `json:"format"` // unified or compact
+	Page        int    `json:"page"`
+	Pages       int    `json:"pages"`
+	TotalLines  int    `json:"total_lines"`
+	NextPage    int    `json:"next_page,omitempty"`
+	More        string `json:"more,omitempty"` // continuation marker when truncated
+	diffTruncation
+}
+
+func runViewDiff(ctx context.Context, opts *viewOptions) error {
+	diff, err := opts.client.GetPRDiff(ctx, opts.repo, opts.prNumber, opts.diffOptions())
+	if err != nil {
+		return fmt.Errorf("get diff: %w", err)
+	}
+
+	output := diffViewOutput{PR: opts.prNumber, Format: opts.format}
+	files := splitDiffFiles(diff)
+	if filter := opts.filter(); filter.active() {
+		kept := files[:0]
+		for _, f := range files {
+			if filter.matches(diffFilePath(f)) {
+				kept = append(kept, f)
 			}
 		}
+		output.FilteredOut = len(files) - len(kept)
+		files = kept
+		diff = strings.Join(files, "")
+	}
+	output.Files = len(files)
+
+	if opts.fileRange != "" {
+		start, end, err := parseFileRange(opts.fileRange)
+		if err != nil {
+			return err
+		}
+		if end == 0 || end > len(files) {
+			end = len(files)
+		}
+		if start > len(files) {
+			return fmt.Errorf("file range starts at %d but the PR diff has %d files", start, len(files))
+		}
+		diff = strings.Join(files[start-1:end], "")
+		output.FirstFile, output.LastFile = start, end
+	}
+
+	if opts.format == "compact" {
+		diff = compactDiff(diff)
+	}
+
+	page, err := paginateDiff(diff, opts.maxLines, opts.page)
+	if err != nil {
+		return err
+	}
+	output.Diff = page.Diff
+	output.Page, output.Pages, output.TotalLines, output.NextPage = page.Page, page.Pages, page.TotalLines, page.NextPage
+	output.More = page.continuation(opts.maxLines)
+	if t := page.truncation(opts.maxLines); t != nil {
+		output.diffTruncation = *t
+	}
+
+	ios, _ := opts.factory.Streams()
+	if opts.json {
+		if err := cmdutil.WriteJSON(ios.Out, output); err != nil {
+			return fmt.Errorf("encode output: %w", err)
+		}
+		return nil
+	}
+
+	return renderMarkdownDiffView(ios.Out, output)
+}
+
+func renderMarkdownDiffView(w io.Writer, output diffViewOutput) error {
+	_, _ = fmt.Fprintf(w, "# PR %d — diff", output.PR)
+	if output.FirstFile > 0 {
+		_, _ = fmt.Fprintf(w, " (files %d-%d of %d)", output.FirstFile, output.LastFile, output.Files)
+	} else {
+		_, _ = fmt.Fprintf(w, " (%d files)", output.Files)
+	}
+	if output.FilteredOut > 0 {
+		_, _ = fmt.Fprintf(w, " — %d filtered out", output.FilteredOut)
+	}
+	if output.Pages > 1 {
+		_, _ = fmt.Fprintf(w, " — page %d of %d", output.Page, output.Pages)
+	}
+	_, _ = fmt.Fprintf(w, "\n\n```%s\n%s```\n", fenceLang(output.Format), text.Sanitize(output.Diff))
+	if output.More != "" {
+		_, _ = fmt.Fprintf(w, "%s\n", output.More)
 	}
-	
 	return nil
 }
+
+// fenceLang returns the code fence language for a diff format; compact diffs
+// are not valid unified diffs, so they get no highlighting hint
+func fenceLang(format string) string {
+	if format == "compact" {
+		return ""
+	}
+	return "diff"
+}
+
+// startPager pages markdown output through the pager setting on a terminal;
+// JSON is left unpaged for scripts
+func startPager(opts *viewOptions) error {
+	if opts.json {
+		return nil
+	}
+	return opts.factory.IOStreams.StartPager()
+}
+
+// runViewWeb opens the PR page, or the file's anchor within its diff, in the browser
+func runViewWeb(ctx context.Context, opts *viewOptions) error {
+	pr, err := opts.client.GetPullRequest(ctx, opts.repo, opts.prNumber)
+	if err != nil {
+		return fmt.Errorf("get PR: %w", err)
+	}
+
+	link, err := prWebURL(pr, opts.file)
+	if err != nil {
+		return err
+	}
+
+	ios, _ := opts.factory.Streams()
+	_, _ = fmt.Fprintf(ios.ErrOut, "Opening %s in your browser.\n", link)
+	return opts.factory.Browser.Browse(link)
+}
+
+// prWebURL returns the PR's web URL; with a file, it points at the file's
+// section of the diff tab via Bitbucket's #chg-<path> anchor
+func prWebURL(pr *bbcloud.PullRequest, file string) (string, error) {
+	if pr.Links.HTML == nil || pr.Links.HTML.Href == "" {
+		return "", fmt.Errorf("PR %d has no web link", pr.ID)
+	}
+	link := pr.Links.HTML.Href
+	if file != "" {
+		link += "/diff#chg-" + file
+	}
+	return link, nil
+}
@@ -0,0 +1,299 @@
+package review
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/httpx"
+	"github.com/ghoseb/bb/pkg/iostreams"
+)
+
+const (
+	watchMinInterval = 5 * time.Second
+	// watchRecentEvents is how much activity the TTY display keeps on screen
+	watchRecentEvents = 10
+)
+
+type watchOptions struct {
+	repo     string
+	prNumber int
+	interval time.Duration
+	timeout  time.Duration
+	until    string
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdWatch creates the review watch command
+func NewCmdWatch(f *cmdutil.Factory) *cobra.Command {
+	opts := &watchOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "watch [pr-number]",
+		Short: "Watch a pull request for activity and status changes",
+		Long: `Poll a pull request and report new activity, comments and build status.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+On a terminal the summary refreshes in place. Otherwise one JSON object is
+written per line (NDJSON): {"event":"status",...} whenever the readiness
+summary changes, {"event":"activity",...} for each new timeline entry, and a
+final {"event":"done",...}.
+
+Use --until to exit once a condition holds, for "wait until approved/green"
+automation:
+  approved  at least one approval and no change requests
+  green     the latest build succeeded
+  ready     approved, green and no unresolved threads
+  merged    the PR was merged
+
+Without --until, watching stops when the PR is no longer open. The command
+fails if the PR closes, or the build fails, before the condition is met.
+
+When the PR number is omitted inside a git checkout, the open PR for the
+current branch is watched.
+
+Examples:
+  # Follow a PR in the terminal
+  bbc review watch 450 --repo test_repo
+
+  # Block until the build is green, then merge in a script
+  bbc review watch 450 --repo test_repo --until green --timeout 30m`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.interval < watchMinInterval {
+				return fmt.Errorf("--interval must be at least %s", watchMinInterval)
+			}
+			if opts.timeout < 0 {
+				return fmt.Errorf("--timeout must not be negative")
+			}
+			switch opts.until {
+			case "", "approved", "green", "ready", "merged":
+			default:
+				return fmt.Errorf("invalid --until %q (allowed: approved, green, ready, merged)", opts.until)
+			}
+
+			// Initialize client
+			client, err := opts.factory.NewBBCloudClient("")
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 0 {
+				opts.prNumber, err = currentBranchPR(cmd.Context(), opts.factory, client, opts.repo)
+			} else {
+				opts.prNumber, err = parsePRNumber(args[0])
+			}
+			if err != nil {
+				return err
+			}
+
+			return runWatch(cmd.Context(), opts, client)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+	cmd.Flags().DurationVar(&opts.interval, "interval", 30*time.Second, "Time between polls")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", 0, "Give up after this long (0 waits indefinitely)")
+	cmd.Flags().StringVar(&opts.until, "until", "", "Exit when the PR is approved, green, ready or merged")
+
+	return cmd
+}
+
+// watchEvent is one NDJSON line in non-TTY mode
+type watchEvent struct {
+	Event    string         `json:"event"` // status, activity or done
+	Status   *statusOutput  `json:"status,omitempty"`
+	Activity *activityEvent `json:"activity,omitempty"`
+	Reason   string         `json:"reason,omitempty"`
+}
+
+// watchState tracks what has already been reported between polls
+type watchState struct {
+	status   *statusOutput
+	lastSeen time.Time
+	recent   []activityEvent
+}
+
+// observe records a poll and returns the status if it changed, plus timeline
+// events newer than the previous poll. The first poll sets the baseline, so
+// existing activity is not replayed.
+func (s *watchState) observe(status statusOutput, timeline []activityEvent) (*statusOutput, []activityEvent) {
+	var changed *statusOutput
+	if s.status == nil || *s.status != status {
+		changed = &status
+		s.status = &status
+	}
+
+	first := s.lastSeen.IsZero()
+	var fresh []activityEvent
+	for _, e := range timeline {
+		if !e.at.After(s.lastSeen) {
+			continue
+		}
+		if !first {
+			fresh = append(fresh, e)
+		}
+		s.lastSeen = e.at
+	}
+	if first {
+		fresh = nil
+		s.recent = timeline
+	} else {
+		s.recent = append(s.recent, fresh...)
+	}
+	if len(s.recent) > watchRecentEvents {
+		s.recent = s.recent[len(s.recent)-watchRecentEvents:]
+	}
+	return changed, fresh
+}
+
+// watchDone reports whether watching should stop, and the reason. An error
+// means the condition can no longer be met.
+func watchDone(until string, s statusOutput) (string, error) {
+	if s.State != "OPEN" {
+		if until == "" || (until == "merged" && s.State == "MERGED") {
+			return fmt.Sprintf("PR %s", s.State), nil
+		}
+		return "", fmt.Errorf("PR %d is %s", s.PR, s.State)
+	}
+
+	approved := s.Approvals > 0 && s.ChangesRequested == 0
+	buildFailed := s.BuildStatus == "FAILED" || s.BuildStatus == "STOPPED"
+	green := s.BuildStatus == "SUCCESSFUL"
+
+	switch until {
+	case "approved":
+		if approved {
+			return "approved", nil
+		}
+	case "green", "ready":
+		if buildFailed {
+			return "", fmt.Errorf("PR %d build %s", s.PR, s.BuildStatus)
+		}
+		if until == "green" && green {
+			return "green", nil
+		}
+		if until == "ready" && approved && green && s.UnresolvedThreads == 0 {
+			return "ready", nil
+		}
+	}
+	return "", nil
+}
+
+func runWatch(ctx context.Context, opts *watchOptions, client *bbcloud.Client) error {
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+
+	ios, _ := opts.factory.Streams()
+	tty := ios.IsStdoutTTY()
+	enc := json.NewEncoder(ios.Out)
+
+	if tty {
+		ios.StartAlternateScreenBuffer()
+	}
+	state := &watchState{}
+	reason, err := pollWatch(ctx, opts, client, state, func(status *statusOutput, fresh []activityEvent) {
+		if tty {
+			renderWatchScreen(ios, opts, state)
+			return
+		}
+		if status != nil {
+			_ = enc.Encode(watchEvent{Event: "status", Status: status})
+		}
+		for i := range fresh {
+			_ = enc.Encode(watchEvent{Event: "activity", Activity: &fresh[i]})
+		}
+	})
+	if tty {
+		ios.StopAlternateScreenBuffer()
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) && ctx.Err() != nil:
+		err = fmt.Errorf("timed out after %s waiting for PR %d", opts.timeout, opts.prNumber)
+	case errors.Is(err, context.Canceled):
+		// Interrupted: leave the last summary on screen
+		reason, err = "interrupted", nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if tty {
+		if state.status != nil {
+			_ = renderMarkdownStatus(ios.Out, *state.status)
+		}
+		_, _ = fmt.Fprintf(ios.Out, "Done: %s\n", reason)
+		return nil
+	}
+	return enc.Encode(watchEvent{Event: "done", Reason: reason})
+}
+
+// pollWatch polls until the stop condition holds, reporting each poll
+func pollWatch(ctx context.Context, opts *watchOptions, client *bbcloud.Client, state *watchState, report func(*statusOutput, []activityEvent)) (string, error) {
+	for {
+		// Each poll reads the PR afresh
+		round := httpx.WithMemo(ctx)
+		status, err := fetchStatus(round, client, opts.repo, opts.prNumber)
+		if err != nil {
+			return "", err
+		}
+		activities, err := client.GetPRActivity(round, opts.repo, opts.prNumber)
+		if err != nil {
+			return "", fmt.Errorf("get activity: %w", err)
+		}
+
+		changed, fresh := state.observe(status, buildTimeline(activities))
+		report(changed, fresh)
+
+		reason, err := watchDone(opts.until, status)
+		if err != nil || reason != "" {
+			return reason, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(opts.interval):
+		}
+	}
+}
+
+// renderWatchScreen redraws the TTY display with the status and recent activity
+func renderWatchScreen(ios *iostreams.IOStreams, opts *watchOptions, state *watchState) {
+	ios.ClearScreen()
+	w := ios.Out
+	if state.status != nil {
+		_ = renderMarkdownStatus(w, *state.status)
+	}
+	renderRecentActivity(w, state.recent)
+
+	until := ""
+	if opts.until != "" {
+		until = " until " + opts.until
+	}
+	_, _ = fmt.Fprintf(w, "\nWatching every %s%s · updated %s · Ctrl-C to stop\n",
+		opts.interval, until, time.Now().Format("15:04:05"))
+}
+
+func renderRecentActivity(w io.Writer, events []activityEvent) {
+	if len(events) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "\n## Recent activity")
+	for _, e := range events {
+		writeActivityEvent(w, e)
+	}
+}
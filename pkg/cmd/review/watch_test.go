@@ -0,0 +1,67 @@
+package review
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWatchStateObserve(t *testing.T) {
+	t0 := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	old := activityEvent{Type: "opened", at: t0}
+	status := statusOutput{PR: 450, State: "OPEN", BuildStatus: "INPROGRESS"}
+
+	s := &watchState{}
+	changed, fresh := s.observe(status, []activityEvent{old})
+	if changed == nil || len(fresh) != 0 {
+		t.Fatalf("first poll: changed=%v fresh=%v, want status and no replayed activity", changed, fresh)
+	}
+
+	changed, fresh = s.observe(status, []activityEvent{old})
+	if changed != nil || len(fresh) != 0 {
+		t.Errorf("unchanged poll: changed=%v fresh=%v", changed, fresh)
+	}
+
+	status.Approvals = 1
+	approval := activityEvent{Type: "approved", at: t0.Add(time.Minute)}
+	changed, fresh = s.observe(status, []activityEvent{old, approval})
+	if changed == nil || changed.Approvals != 1 {
+		t.Errorf("expected changed status, got %v", changed)
+	}
+	if len(fresh) != 1 || fresh[0].Type != "approved" {
+		t.Errorf("fresh = %v, want the approval", fresh)
+	}
+	if len(s.recent) != 2 {
+		t.Errorf("recent = %d events, want 2", len(s.recent))
+	}
+}
+
+func TestWatchDone(t *testing.T) {
+	open := statusOutput{PR: 450, State: "OPEN", BuildStatus: "SUCCESSFUL", Approvals: 1}
+
+	tests := []struct {
+		name    string
+		until   string
+		status  statusOutput
+		reason  string
+		wantErr bool
+	}{
+		{"no condition keeps watching", "", open, "", false},
+		{"no condition stops when closed", "", statusOutput{State: "DECLINED"}, "PR DECLINED", false},
+		{"approved", "approved", open, "approved", false},
+		{"change requests block approval", "approved", statusOutput{State: "OPEN", Approvals: 1, ChangesRequested: 1}, "", false},
+		{"green", "green", open, "green", false},
+		{"failed build", "green", statusOutput{State: "OPEN", BuildStatus: "FAILED"}, "", true},
+		{"ready needs resolved threads", "ready", statusOutput{State: "OPEN", BuildStatus: "SUCCESSFUL", Approvals: 1, UnresolvedThreads: 2}, "", false},
+		{"ready", "ready", open, "ready", false},
+		{"merged", "merged", statusOutput{State: "MERGED"}, "PR MERGED", false},
+		{"declined before condition", "approved", statusOutput{State: "DECLINED"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, err := watchDone(tt.until, tt.status)
+			if (err != nil) != tt.wantErr || reason != tt.reason {
+				t.Errorf("watchDone() = %q, %v; want %q, error %v", reason, err, tt.reason, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,15 +2,39 @@ package root
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ghoseb/bb/internal/build"
+	"github.com/ghoseb/bb/internal/secret"
+	"github.com/ghoseb/bb/pkg/cmd/agent"
+	"github.com/ghoseb/bb/pkg/cmd/alias"
+	"github.com/ghoseb/bb/pkg/cmd/api"
+	"github.com/ghoseb/bb/pkg/cmd/audit"
 	"github.com/ghoseb/bb/pkg/cmd/auth"
+	"github.com/ghoseb/bb/pkg/cmd/browse"
+	"github.com/ghoseb/bb/pkg/cmd/cache"
+	"github.com/ghoseb/bb/pkg/cmd/changelog"
+	"github.com/ghoseb/bb/pkg/cmd/config"
+	"github.com/ghoseb/bb/pkg/cmd/dashboard"
+	"github.com/ghoseb/bb/pkg/cmd/deploy"
+	"github.com/ghoseb/bb/pkg/cmd/doctor"
+	"github.com/ghoseb/bb/pkg/cmd/env"
+	"github.com/ghoseb/bb/pkg/cmd/exec"
+	"github.com/ghoseb/bb/pkg/cmd/extension"
+	"github.com/ghoseb/bb/pkg/cmd/inbox"
 	"github.com/ghoseb/bb/pkg/cmd/list"
+	"github.com/ghoseb/bb/pkg/cmd/mcp"
+	"github.com/ghoseb/bb/pkg/cmd/release"
+	"github.com/ghoseb/bb/pkg/cmd/repo"
 	"github.com/ghoseb/bb/pkg/cmd/review"
+	"github.com/ghoseb/bb/pkg/cmd/webhook"
 	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/prompter"
 )
 
 // NewCmdRoot creates the root command for the bb CLI.
@@ -26,6 +50,49 @@ more efficient.`,
 		Version:       fmt.Sprintf("%s (%s, %s)", build.Version, build.Commit, build.Date),
 		SilenceUsage:  true,
 		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			f.HostOverride, _ = cmd.Flags().GetString("host")
+			f.WorkspaceOverride, _ = cmd.Flags().GetString("workspace")
+			f.ProfileOverride, _ = cmd.Flags().GetString("profile")
+			f.RecordPath, _ = cmd.Flags().GetString("record")
+			f.ReplayPath, _ = cmd.Flags().GetString("replay")
+			f.DryRun, _ = cmd.Flags().GetBool("dry-run")
+			f.Offline, _ = cmd.Flags().GetBool("offline")
+			f.NoCache, _ = cmd.Flags().GetBool("no-cache")
+			if maxTime, _ := cmd.Flags().GetDuration("max-time"); cmd.Flags().Changed("max-time") {
+				if maxTime <= 0 {
+					return fmt.Errorf("--max-time must be positive")
+				}
+				f.Deadline = time.Now().Add(maxTime)
+			}
+			f.Yes, _ = cmd.Flags().GetBool("yes")
+			f.NoInput, _ = cmd.Flags().GetBool("no-input")
+			if !f.NoInput {
+				f.NoInput = noInputEnv() || !f.IOStreams.CanPrompt()
+			}
+			if f.NoInput {
+				f.IOStreams.SetNeverPrompt(true)
+				f.Prompter = prompter.Disabled()
+			}
+			f.KeyringBackend, _ = cmd.Flags().GetString("keyring-backend")
+			if f.KeyringBackend != "" {
+				if err := secret.ValidateBackends(f.KeyringBackend); err != nil {
+					return fmt.Errorf("--keyring-backend: %w", err)
+				}
+			}
+			f.Command = strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+
+			// Fill unset flags (including --repo) from config defaults
+			if err := f.ApplyConfigDefaults(cmd); err != nil {
+				return err
+			}
+
+			f.ConcurrencyOverride, _ = cmd.Flags().GetInt("concurrency")
+			if cmd.Flags().Changed("concurrency") && f.ConcurrencyOverride < 1 {
+				return fmt.Errorf("--concurrency must be at least 1")
+			}
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Show help when no subcommand is provided
 			return cmd.Help()
@@ -41,11 +108,60 @@ more efficient.`,
 	// Global flags
 	cmd.PersistentFlags().StringP("workspace", "w", "", 
 		"Override workspace (env: BB_WORKSPACE, or from stored credentials)")
+	cmd.PersistentFlags().String("host", "",
+		"Bitbucket host from the hosts config (env: BB_HOST, default: bitbucket.org)")
+	cmd.PersistentFlags().String("profile", "",
+		"Auth profile for credentials and profile-scoped config (env: BB_PROFILE)")
+	cmd.PersistentFlags().String("record", "",
+		"Record API requests and responses, sanitized, to a cassette file")
+	cmd.PersistentFlags().String("replay", "",
+		"Answer API requests from a cassette file instead of the network")
+	cmd.MarkFlagsMutuallyExclusive("record", "replay")
+	cmd.PersistentFlags().Bool("dry-run", false,
+		"Print the API requests that would change something instead of sending them")
+	cmd.PersistentFlags().Bool("offline", false,
+		"Answer API reads from the response cache and send nothing")
+	cmd.MarkFlagsMutuallyExclusive("offline", "replay")
+	cmd.PersistentFlags().Bool("no-cache", false,
+		"Fetch fresh data from the API without reading or writing the response cache")
+	cmd.MarkFlagsMutuallyExclusive("offline", "no-cache")
+	cmd.PersistentFlags().Duration("max-time", 0,
+		"Give up on API requests after this long in total, retries and later pages included (e.g. 30s, 2m)")
+	cmd.PersistentFlags().Int("concurrency", 0,
+		"Maximum API requests sent at once by commands that fan out (config: concurrency, default 5)")
+	cmd.PersistentFlags().Bool("no-input", false,
+		"Fail instead of prompting when input is missing (env: BB_NO_INPUT; implied when stdin is not a terminal)")
+	cmd.PersistentFlags().BoolP("yes", "y", false,
+		"Accept confirmation prompts without asking (needed for destructive commands when not on a terminal)")
+	cmd.PersistentFlags().String("keyring-backend", "",
+		"Keyring backends to try, comma-separated: keychain, wincred, secret-service, kwallet, keyctl, pass, file (env: KEYRING_BACKEND)")
 
 	// Add command groups
 	cmd.AddCommand(auth.NewCmdAuth(f))
+	cmd.AddCommand(agent.NewCmdAgent(f))
 	cmd.AddCommand(review.NewCmdReview(f))
 	cmd.AddCommand(list.NewCmdList(f))
+	cmd.AddCommand(dashboard.NewCmdDashboard(f))
+	cmd.AddCommand(inbox.NewCmdInbox(f))
+	cmd.AddCommand(browse.NewCmdBrowse(f))
+	cmd.AddCommand(repo.NewCmdRepo(f))
+	cmd.AddCommand(config.NewCmdConfig(f))
+	cmd.AddCommand(alias.NewCmdAlias(f))
+	cmd.AddCommand(extension.NewCmdExtension(f))
+	cmd.AddCommand(env.NewCmdEnv(f))
+	cmd.AddCommand(mcp.NewCmdMCP(f))
+	cmd.AddCommand(api.NewCmdAPI(f))
+	cmd.AddCommand(exec.NewCmdExec(f))
+	cmd.AddCommand(webhook.NewCmdWebhook(f))
+	cmd.AddCommand(audit.NewCmdAudit(f))
+	cmd.AddCommand(doctor.NewCmdDoctor(f))
+	cmd.AddCommand(cache.NewCmdCache(f))
+	cmd.AddCommand(changelog.NewCmdChangelog(f))
+	cmd.AddCommand(release.NewCmdRelease(f))
+	cmd.AddCommand(deploy.NewCmdDeploy(f))
+
+	// Complete --repo, --workspace and PR numbers from the API
+	f.RegisterCompletions(cmd)
 
 	// Custom help that shows subcommand usage inline
 	cmd.SetHelpFunc(expandedHelp)
@@ -56,6 +172,17 @@ more efficient.`,
 // skipCommands are utility commands excluded from expanded help.
 var skipCommands = map[string]bool{"completion": true, "help": true}
 
+// noInputEnv reports whether BB_NO_INPUT asks for non-interactive mode; any
+// value but an empty or false one does
+func noInputEnv() bool {
+	value := os.Getenv("BB_NO_INPUT")
+	if value == "" {
+		return false
+	}
+	on, err := strconv.ParseBool(value)
+	return err != nil || on
+}
+
 func expandedHelp(cmd *cobra.Command, _ []string) {
 	var b strings.Builder
 
@@ -0,0 +1,381 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/bbwebhook"
+	"github.com/ghoseb/bb/pkg/cmdutil"
+	"github.com/ghoseb/bb/pkg/httpx"
+)
+
+const forwardMinInterval = 5 * time.Second
+
+// forwardedHeaders are the delivery headers passed on to the local server
+var forwardedHeaders = []string{
+	"Content-Type",
+	"User-Agent",
+	bbwebhook.EventHeader,
+	bbwebhook.HookUUIDHeader,
+	bbwebhook.RequestUUIDHeader,
+	bbwebhook.AttemptHeader,
+	bbwebhook.SignatureHeader,
+}
+
+// pollEvents are the events the polling fallback can reconstruct from the
+// pull request list
+var pollEvents = []string{"pullrequest:created", "pullrequest:updated", "pullrequest:fulfilled", "pullrequest:rejected"}
+
+type forwardOptions struct {
+	repo      string
+	events    []string
+	url       string
+	publicURL string
+	listen    string
+	secret    string
+	interval  time.Duration
+
+	factory *cmdutil.Factory
+}
+
+// NewCmdForward creates the webhook forward command
+func NewCmdForward(f *cmdutil.Factory) *cobra.Command {
+	opts := &forwardOptions{factory: f}
+
+	cmd := &cobra.Command{
+		Use:   "forward",
+		Short: "Forward repository webhook deliveries to a local server",
+		Long: `Receive a repository's webhook deliveries and forward them to a local
+server, for developing webhook handlers.
+
+Requires --repo flag (or a default_repo setting) to specify the repository.
+
+Bitbucket must be able to reach the receiver, so pass --public-url with the
+address of a tunnel (e.g. ngrok or cloudflared) to the --listen address. A
+temporary webhook for --events is registered at that URL with a signing
+secret and deleted on exit. Deliveries whose X-Hub-Signature does not match
+are rejected; the rest are POSTed to --url with their original body and
+headers, and the local server's status is passed back to Bitbucket.
+
+Without --public-url, pull requests are polled every --interval instead and
+pullrequest:created, updated, fulfilled and rejected events are rebuilt
+from them, with a {"pullrequest", "repository"} body and an X-Bb-Forwarder:
+poll header. They are signed when --secret is set.
+
+--events takes event keys or patterns such as pullrequest:*.
+
+Examples:
+  # Through a tunnel to localhost:8750
+  bbc webhook forward --repo test_repo --events 'pullrequest:*' \
+    --url http://localhost:3000/hook --public-url https://abc.ngrok.app
+
+  # No tunnel: poll for pull request events
+  bbc webhook forward --repo test_repo --events pullrequest:created \
+    --url http://localhost:3000/hook --secret dev`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.url == "" {
+				return fmt.Errorf("--url is required")
+			}
+			if len(opts.events) == 0 {
+				return fmt.Errorf("--events is required")
+			}
+			events, err := expandEvents(opts.events)
+			if err != nil {
+				return err
+			}
+			opts.events = events
+			if opts.publicURL == "" && opts.interval < forwardMinInterval {
+				return fmt.Errorf("--interval must be at least %s", forwardMinInterval)
+			}
+
+			client, err := opts.factory.NewBBCloudClient("")
+			if err != nil {
+				return err
+			}
+			if opts.publicURL != "" {
+				return runForwardHook(cmd.Context(), opts, client)
+			}
+			return runForwardPoll(cmd.Context(), opts, client)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.repo, "repo", "r", "", "Repository slug (default from default_repo config)")
+	cmd.Flags().StringSliceVar(&opts.events, "events", nil, "Events to forward, comma-separated (e.g. repo:push,pullrequest:*)")
+	cmd.Flags().StringVar(&opts.url, "url", "", "Local URL to POST deliveries to")
+	cmd.Flags().StringVar(&opts.publicURL, "public-url", "", "Public URL that reaches --listen; registers a temporary webhook")
+	cmd.Flags().StringVar(&opts.listen, "listen", "localhost:8750", "Address to receive deliveries on with --public-url")
+	cmd.Flags().StringVar(&opts.secret, "secret", "", "Webhook signing secret (default: random with --public-url)")
+	cmd.Flags().DurationVar(&opts.interval, "interval", 30*time.Second, "Time between polls without --public-url")
+
+	return cmd
+}
+
+// runForwardHook registers a temporary webhook at the public URL and relays
+// its deliveries until interrupted
+func runForwardHook(ctx context.Context, opts *forwardOptions, client *bbcloud.Client) error {
+	ios := opts.factory.IOStreams
+	if opts.secret == "" {
+		secret, err := randomSecret()
+		if err != nil {
+			return err
+		}
+		opts.secret = secret
+	}
+
+	listener, err := net.Listen("tcp", opts.listen)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", opts.listen, err)
+	}
+
+	hook, err := client.CreateWebhook(ctx, opts.repo, bbcloud.Webhook{
+		URL:         opts.publicURL,
+		Description: "bbc webhook forward",
+		Active:      true,
+		Events:      opts.events,
+		Secret:      opts.secret,
+	})
+	if err != nil {
+		_ = listener.Close()
+		return err
+	}
+	defer func() {
+		// The command context is already cancelled on Ctrl-C
+		cleanup, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := client.DeleteWebhook(cleanup, opts.repo, hook.UUID); err != nil {
+			_, _ = fmt.Fprintf(ios.ErrOut, "warning: %v; remove webhook %s in the repository settings\n", err, hook.UUID)
+			return
+		}
+		_, _ = fmt.Fprintf(ios.ErrOut, "Deleted webhook %s\n", hook.UUID)
+	}()
+
+	_, _ = fmt.Fprintf(ios.ErrOut, "Registered webhook %s for %s\n", hook.UUID, strings.Join(opts.events, ", "))
+	_, _ = fmt.Fprintf(ios.ErrOut, "Forwarding %s → %s → %s · Ctrl-C to stop\n", opts.publicURL, listener.Addr(), opts.url)
+
+	srv := &http.Server{
+		Handler:           &relay{secret: opts.secret, target: opts.url, client: http.DefaultClient, log: ios.ErrOut},
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdown)
+	}()
+	if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// relay verifies webhook deliveries and forwards them to the local target
+type relay struct {
+	secret string
+	target string
+	client *http.Client
+	log    io.Writer
+}
+
+func (r *relay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	event, err := bbwebhook.ParseRequest(req, r.secret)
+	switch {
+	case errors.Is(err, bbwebhook.ErrNoSignature), errors.Is(err, bbwebhook.ErrInvalidSignature):
+		_, _ = fmt.Fprintf(r.log, "✗ %s rejected: %v\n", req.Header.Get(bbwebhook.EventHeader), err)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case err != nil:
+		_, _ = fmt.Fprintf(r.log, "✗ %s rejected: %v\n", req.Header.Get(bbwebhook.EventHeader), err)
+		http.Error(w, "bad delivery", http.StatusBadRequest)
+		return
+	}
+
+	header := make(http.Header)
+	for _, name := range forwardedHeaders {
+		if v := req.Header.Get(name); v != "" {
+			header.Set(name, v)
+		}
+	}
+	status, err := forward(req.Context(), r.client, r.target, header, event.Body)
+	if err != nil {
+		_, _ = fmt.Fprintf(r.log, "✗ %s: %v\n", event.Key, err)
+		http.Error(w, "forward failed", http.StatusBadGateway)
+		return
+	}
+	_, _ = fmt.Fprintf(r.log, "→ %s %d\n", event.Key, status)
+	w.WriteHeader(status)
+}
+
+// forward POSTs body to target and returns the response status
+func forward(ctx context.Context, client *http.Client, target string, header http.Header, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header = header
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// runForwardPoll rebuilds pull request events from the pull request list
+// until interrupted
+func runForwardPoll(ctx context.Context, opts *forwardOptions, client *bbcloud.Client) error {
+	ios := opts.factory.IOStreams
+
+	var events []string
+	for _, e := range opts.events {
+		if slices.Contains(pollEvents, e) {
+			events = append(events, e)
+		}
+	}
+	if len(events) == 0 {
+		return fmt.Errorf("without --public-url only %s can be forwarded", strings.Join(pollEvents, ", "))
+	}
+	if len(events) < len(opts.events) {
+		_, _ = fmt.Fprintf(ios.ErrOut, "warning: without --public-url only %s are forwarded\n", strings.Join(events, ", "))
+	}
+
+	_, _ = fmt.Fprintf(ios.ErrOut, "Polling %s every %s → %s · Ctrl-C to stop\n", opts.repo, opts.interval, opts.url)
+
+	since := time.Now().UTC()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(opts.interval):
+		}
+
+		// Each poll reads afresh
+		prs, err := updatedPRs(httpx.WithMemo(ctx), client, opts.repo, events, since)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			_, _ = fmt.Fprintf(ios.ErrOut, "warning: %v\n", err)
+			continue
+		}
+		for _, pr := range prs {
+			if pr.UpdatedOn.After(since) {
+				since = pr.UpdatedOn
+			}
+			event := prEvent(pr)
+			if !slices.Contains(events, event) {
+				continue
+			}
+			if err := forwardPolled(ctx, opts, event, pr); err != nil {
+				_, _ = fmt.Fprintf(ios.ErrOut, "✗ %s #%d: %v\n", event, pr.ID, err)
+			}
+		}
+	}
+}
+
+// updatedPRs lists the pull requests updated after since, oldest first, in
+// the states the events need
+func updatedPRs(ctx context.Context, client *bbcloud.Client, repo string, events []string, since time.Time) ([]bbcloud.PullRequest, error) {
+	var states []string
+	if slices.Contains(events, "pullrequest:created") || slices.Contains(events, "pullrequest:updated") {
+		states = append(states, "OPEN")
+	}
+	if slices.Contains(events, "pullrequest:fulfilled") {
+		states = append(states, "MERGED")
+	}
+	if slices.Contains(events, "pullrequest:rejected") {
+		states = append(states, "DECLINED")
+	}
+
+	// BBQL compares whole seconds; newer-than-since is checked exactly below
+	query := fmt.Sprintf("updated_on >= %s", since.Truncate(time.Second).Format(time.RFC3339))
+	var updated []bbcloud.PullRequest
+	for _, state := range states {
+		prs, err := client.QueryPullRequests(ctx, repo, bbcloud.PRListOptions{State: state, Query: query, Sort: "updated_on"})
+		if err != nil {
+			return nil, fmt.Errorf("list pull requests: %w", err)
+		}
+		for _, pr := range prs {
+			if pr.UpdatedOn.After(since) {
+				updated = append(updated, pr)
+			}
+		}
+	}
+	slices.SortFunc(updated, func(a, b bbcloud.PullRequest) int {
+		return a.UpdatedOn.Compare(b.UpdatedOn)
+	})
+	return updated, nil
+}
+
+// prEvent names the webhook event a polled pull request change stands for
+func prEvent(pr bbcloud.PullRequest) string {
+	switch pr.State {
+	case "MERGED":
+		return "pullrequest:fulfilled"
+	case "DECLINED":
+		return "pullrequest:rejected"
+	}
+	// A PR created since the last poll has not been updated since creation
+	if pr.UpdatedOn.Sub(pr.CreatedOn) < time.Second {
+		return "pullrequest:created"
+	}
+	return "pullrequest:updated"
+}
+
+// forwardPolled POSTs a rebuilt event to the local server
+func forwardPolled(ctx context.Context, opts *forwardOptions, event string, pr bbcloud.PullRequest) error {
+	payload := bbwebhook.Payload{PullRequest: &pr}
+	if pr.Destination != nil {
+		payload.Repository = pr.Destination.Repository
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	header.Set(bbwebhook.EventHeader, event)
+	header.Set("X-Bb-Forwarder", "poll")
+	if opts.secret != "" {
+		header.Set(bbwebhook.SignatureHeader, bbwebhook.Sign(opts.secret, body))
+	}
+
+	status, err := forward(ctx, http.DefaultClient, opts.url, header, body)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(opts.factory.IOStreams.ErrOut, "→ %s #%d %d\n", event, pr.ID, status)
+	return nil
+}
+
+// randomSecret returns a hex-encoded random signing secret
+func randomSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/bbwebhook"
+)
+
+func TestExpandEvents(t *testing.T) {
+	events, err := expandEvents([]string{"repo:push", "pullrequest:comment_*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "repo:push pullrequest:comment_created pullrequest:comment_updated pullrequest:comment_deleted pullrequest:comment_resolved pullrequest:comment_reopened"
+	if got := strings.Join(events, " "); got != want {
+		t.Errorf("expandEvents = %q, want %q", got, want)
+	}
+
+	if _, err := expandEvents([]string{"pullrequest:merged"}); err == nil {
+		t.Error("unknown event accepted")
+	}
+}
+
+func TestRelay(t *testing.T) {
+	var gotBody, gotEvent, gotSig string
+	local := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		gotBody, gotEvent, gotSig = string(data), r.Header.Get("X-Event-Key"), r.Header.Get(bbwebhook.SignatureHeader)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer local.Close()
+
+	var log strings.Builder
+	r := &relay{secret: "s3cret", target: local.URL, client: local.Client(), log: &log}
+	body := `{"pullrequest":{"id":7}}`
+
+	deliver := func(sig string) int {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("X-Event-Key", "pullrequest:created")
+		req.Header.Set(bbwebhook.SignatureHeader, sig)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := deliver("sha256=bad"); code != http.StatusUnauthorized || gotBody != "" {
+		t.Errorf("bad signature: status %d, forwarded %q", code, gotBody)
+	}
+
+	sig := bbwebhook.Sign("s3cret", []byte(body))
+	if code := deliver(sig); code != http.StatusAccepted {
+		t.Errorf("status = %d, want the local server's 202", code)
+	}
+	if gotBody != body || gotEvent != "pullrequest:created" || gotSig != sig {
+		t.Errorf("forwarded body %q, event %q, signature %q", gotBody, gotEvent, gotSig)
+	}
+	if !strings.Contains(log.String(), "→ pullrequest:created 202") {
+		t.Errorf("log = %q", log.String())
+	}
+}
+
+func TestPREvent(t *testing.T) {
+	created := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		pr   bbcloud.PullRequest
+		want string
+	}{
+		{bbcloud.PullRequest{State: "OPEN", CreatedOn: created, UpdatedOn: created.Add(200 * time.Millisecond)}, "pullrequest:created"},
+		{bbcloud.PullRequest{State: "OPEN", CreatedOn: created, UpdatedOn: created.Add(time.Hour)}, "pullrequest:updated"},
+		{bbcloud.PullRequest{State: "MERGED", CreatedOn: created, UpdatedOn: created.Add(time.Hour)}, "pullrequest:fulfilled"},
+		{bbcloud.PullRequest{State: "DECLINED", CreatedOn: created, UpdatedOn: created.Add(time.Hour)}, "pullrequest:rejected"},
+	}
+	for _, tt := range tests {
+		if got := prEvent(tt.pr); got != tt.want {
+			t.Errorf("prEvent(%s) = %q, want %q", tt.pr.State, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/pkg/cmdutil"
+)
+
+// knownEvents are the repository webhook events Bitbucket Cloud delivers
+var knownEvents = []string{
+	"repo:push",
+	"repo:fork",
+	"repo:updated",
+	"repo:commit_comment_created",
+	"repo:commit_status_created",
+	"repo:commit_status_updated",
+	"issue:created",
+	"issue:updated",
+	"issue:comment_created",
+	"pullrequest:created",
+	"pullrequest:updated",
+	"pullrequest:approved",
+	"pullrequest:unapproved",
+	"pullrequest:fulfilled",
+	"pullrequest:rejected",
+	"pullrequest:changes_request_created",
+	"pullrequest:changes_request_removed",
+	"pullrequest:comment_created",
+	"pullrequest:comment_updated",
+	"pullrequest:comment_deleted",
+	"pullrequest:comment_resolved",
+	"pullrequest:comment_reopened",
+}
+
+// NewCmdWebhook creates the webhook command
+func NewCmdWebhook(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "webhook <command>",
+		Short: "Work with repository webhooks",
+	}
+
+	cmd.AddCommand(NewCmdForward(f))
+
+	return cmd
+}
+
+// expandEvents resolves event patterns such as pullrequest:* to the known
+// events they match, in knownEvents order
+func expandEvents(patterns []string) ([]string, error) {
+	selected := make(map[string]bool)
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		matched := false
+		for _, e := range knownEvents {
+			if ok, err := path.Match(p, e); err != nil {
+				return nil, fmt.Errorf("invalid event pattern %q: %w", p, err)
+			} else if ok {
+				selected[e], matched = true, true
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("unknown event %q (e.g. repo:push, pullrequest:created, pullrequest:*)", p)
+		}
+	}
+
+	var events []string
+	for _, e := range knownEvents {
+		if selected[e] {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
@@ -5,9 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/ghoseb/bb/internal/agent"
+	"github.com/ghoseb/bb/internal/config"
 	"github.com/ghoseb/bb/internal/secret"
 	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/httpx"
 )
 
 // Credentials holds Bitbucket Cloud authentication credentials
@@ -15,15 +22,112 @@ type Credentials struct {
 	Workspace string
 	Username  string
 	Token     string
+	// Host is the host the credentials were verified against; unset for
+	// logins stored before credentials were kept per workspace
+	Host string `json:",omitempty"`
+	// CreatedAt is when the credentials were stored; unset for logins
+	// made before it was recorded
+	CreatedAt time.Time `json:",omitzero"`
 }
 
-// LoadCredentialsFromStore loads credentials from an existing secret store.
+// CredentialsKey returns the secret store key holding a profile's credentials.
+// The empty profile maps to the original single-account key.
+func CredentialsKey(profile string) string {
+	if profile == "" {
+		return "bb/credentials"
+	}
+	return "bb/credentials/" + profile
+}
+
+// workspaceKeyPrefix starts the keys of credentials stored per workspace
+const workspaceKeyPrefix = "bb/workspaces/"
+
+// WorkspaceCredentialsKey returns the secret store key holding the credentials
+// of a workspace on a host.
+func WorkspaceCredentialsKey(host, workspace string) string {
+	return workspaceKeyPrefix + host + "/" + workspace
+}
+
+// StoredWorkspaces returns the host/workspace pairs with credentials in the
+// store, sorted.
+func StoredWorkspaces(store secret.Store) ([]string, error) {
+	keys, err := store.Keys()
+	if err != nil {
+		return nil, fmt.Errorf("list stored credentials: %w", err)
+	}
+
+	var workspaces []string
+	for _, key := range keys {
+		if hostWorkspace, ok := strings.CutPrefix(key, workspaceKeyPrefix); ok && strings.Count(hostWorkspace, "/") == 1 {
+			workspaces = append(workspaces, hostWorkspace)
+		}
+	}
+	slices.Sort(workspaces)
+	return workspaces, nil
+}
+
+// StoredProfiles returns the profiles with credentials in the store, sorted,
+// with the empty (default) profile first.
+func StoredProfiles(store secret.Store) ([]string, error) {
+	keys, err := store.Keys()
+	if err != nil {
+		return nil, fmt.Errorf("list stored credentials: %w", err)
+	}
+
+	var profiles []string
+	for _, key := range keys {
+		if key == CredentialsKey("") {
+			profiles = append(profiles, "")
+		} else if profile, ok := strings.CutPrefix(key, CredentialsKey("")+"/"); ok && profile != "" {
+			profiles = append(profiles, profile)
+		}
+	}
+	slices.Sort(profiles)
+	return profiles, nil
+}
+
+// MaskSecret hides a secret, keeping only its last four characters when it is
+// long enough
+func MaskSecret(s string) string {
+	if len(s) < 12 {
+		return "****"
+	}
+	return "****" + s[len(s)-4:]
+}
+
+// ErrNotAuthenticated indicates there are no stored credentials to use.
+var ErrNotAuthenticated = errors.New("not authenticated (run 'bb auth')")
+
+// LoadCredentialsFromStore loads a profile's credentials from an existing secret store.
 // Credentials are stored as a single JSON blob to avoid multiple keyring unlock prompts.
-func LoadCredentialsFromStore(store *secret.Store) (*Credentials, error) {
-	credsJSON, err := store.Get("bb/credentials")
+func LoadCredentialsFromStore(store secret.Store, profile string) (*Credentials, error) {
+	return loadCredentialsKey(store, CredentialsKey(profile))
+}
+
+// SaveCredentialsToStore saves a profile's credentials to the secret store as a single
+// JSON blob to avoid multiple keyring unlock prompts on subsequent reads.
+func SaveCredentialsToStore(store secret.Store, profile string, creds *Credentials) error {
+	return saveCredentialsKey(store, CredentialsKey(profile), creds)
+}
+
+// LoadWorkspaceCredentials loads the credentials stored for a workspace on a host.
+func LoadWorkspaceCredentials(store secret.Store, host, workspace string) (*Credentials, error) {
+	return loadCredentialsKey(store, WorkspaceCredentialsKey(host, workspace))
+}
+
+// SaveWorkspaceCredentials stores credentials under their Host and Workspace.
+func SaveWorkspaceCredentials(store secret.Store, creds *Credentials) error {
+	if creds.Host == "" || creds.Workspace == "" {
+		return errors.New("workspace credentials need a host and a workspace")
+	}
+	return saveCredentialsKey(store, WorkspaceCredentialsKey(creds.Host, creds.Workspace), creds)
+}
+
+func loadCredentialsKey(store secret.Store, key string) (*Credentials, error) {
+	credsJSON, err := store.Get(key)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			return nil, fmt.Errorf("not authenticated (run 'bb auth')")
+			return nil, ErrNotAuthenticated
 		}
 		return nil, fmt.Errorf("read credentials: %w", err)
 	}
@@ -36,42 +140,150 @@ func LoadCredentialsFromStore(store *secret.Store) (*Credentials, error) {
 	return &creds, nil
 }
 
-// SaveCredentialsToStore saves credentials to the secret store as a single JSON blob
-// to avoid multiple keyring unlock prompts on subsequent reads.
-func SaveCredentialsToStore(store *secret.Store, creds *Credentials) error {
+func saveCredentialsKey(store secret.Store, key string, creds *Credentials) error {
 	credsJSON, err := json.Marshal(creds)
 	if err != nil {
 		return fmt.Errorf("marshal credentials: %w", err)
 	}
 
-	if err := store.Set("bb/credentials", string(credsJSON)); err != nil {
+	if err := store.Set(key, string(credsJSON)); err != nil {
 		return fmt.Errorf("store credentials: %w", err)
 	}
 
+	// Don't let a session agent keep serving the old credentials
+	_ = agent.Forget(key)
+
 	return nil
 }
 
+// Workspace sources reported by ResolveWorkspace
+const (
+	WorkspaceFromFlag        = "flag"
+	WorkspaceFromEnv         = "env"
+	WorkspaceFromConfig      = "config"
+	WorkspaceFromCredentials = "credentials"
+)
+
+// ResolveWorkspace returns the workspace commands run against and its source.
+// Precedence: override (or the --workspace flag), BB_WORKSPACE, default_workspace
+// config, stored credentials. Credentials are only read when loadCreds is set,
+// since that may unlock the keyring; otherwise an empty workspace is returned
+// with the credentials source.
+func (f *Factory) ResolveWorkspace(override string, loadCreds bool) (string, string, error) {
+	if override == "" {
+		override = f.WorkspaceOverride
+	}
+	if override != "" {
+		return override, WorkspaceFromFlag, nil
+	}
+	if ws := os.Getenv("BB_WORKSPACE"); ws != "" {
+		return ws, WorkspaceFromEnv, nil
+	}
+
+	cfg, err := f.Config()
+	if err != nil {
+		return "", "", err
+	}
+	if ws := cfg.Workspace(); ws != "" {
+		return ws, WorkspaceFromConfig, nil
+	}
+
+	if !loadCreds {
+		return "", WorkspaceFromCredentials, nil
+	}
+	creds, err := f.GetCredentials()
+	if err != nil {
+		return "", "", err
+	}
+	return creds.Workspace, WorkspaceFromCredentials, nil
+}
+
+// AuditLogPath returns the path of the log of successful mutating requests
+func AuditLogPath() string {
+	return filepath.Join(config.DataDir(), "audit.jsonl")
+}
+
 // NewBBCloudClient creates a new Bitbucket Cloud API client using cached credentials
-// If workspace is provided, it overrides the stored workspace
+// If workspace is provided, it overrides the configured and stored workspace
 func (f *Factory) NewBBCloudClient(workspaceOverride string) (*bbcloud.Client, error) {
-	creds, err := f.GetCredentials()
+	cassette, err := f.Cassette()
+	if err != nil {
+		return nil, err
+	}
+	replaying := cassette != nil && cassette.Replaying()
+
+	// Replays never reach the API, so they need no credentials and default
+	// to the workspace recorded with the cassette
+	creds := &Credentials{Username: "replay", Token: "replay"}
+	if !replaying {
+		if creds, err = f.GetCredentials(); err != nil {
+			return nil, err
+		}
+	}
+
+	workspace, _, err := f.ResolveWorkspace(workspaceOverride, !replaying)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case replaying && workspace == "":
+		workspace = cassette.Workspace()
+	case cassette != nil && !replaying:
+		cassette.SetWorkspace(workspace)
+	}
+
+	host, err := f.Host()
 	if err != nil {
 		return nil, err
 	}
 
-	workspace := creds.Workspace
-	if workspaceOverride != "" {
-		workspace = workspaceOverride
+	// One client per API, workspace and account serves the whole invocation,
+	// so concurrent calls share its connections and state
+	key := strings.Join([]string{host.APIURL, host.Auth, workspace, creds.Username, creds.Token}, "\x00")
+	f.clientsMu.Lock()
+	defer f.clientsMu.Unlock()
+	if client, ok := f.clients[key]; ok {
+		return client, nil
 	}
 
-	client, err := bbcloud.New(bbcloud.Options{
+	opts := bbcloud.Options{
+		BaseURL:   host.APIURL,
 		Workspace: workspace,
 		Username:  creds.Username,
 		Token:     creds.Token,
-	})
+		AuthType:  host.Auth,
+		Cassette:  cassette,
+	}
+	if f.DryRun {
+		opts.DryRun = f.IOStreams.Out
+	}
+	if !f.Deadline.IsZero() {
+		// A budget already spent still fails the requests, straight away
+		opts.TotalTimeout = max(time.Until(f.Deadline), time.Nanosecond)
+	}
+	if !replaying {
+		cfg, err := f.Config()
+		if err != nil {
+			return nil, err
+		}
+		if cfg.AuditLog() {
+			opts.Audit = httpx.NewAuditLog(AuditLogPath(), f.Command)
+		}
+		opts.RateLimitWait = cfg.RateLimitWait()
+		opts.RateLimitProgress = f.IOStreams.ErrOut
+		opts.RateLimitPacing = cfg.RateLimitPacing()
+		if !f.NoCache {
+			opts.ResponseCache = f.ResponseCache()
+		}
+	}
+	client, err := bbcloud.New(opts)
 	if err != nil {
 		return nil, fmt.Errorf("create API client: %w", err)
 	}
 
+	if f.clients == nil {
+		f.clients = make(map[string]*bbcloud.Client)
+	}
+	f.clients[key] = client
 	return client, nil
 }
@@ -1,11 +1,18 @@
 package cmdutil
 
 import (
+	"context"
 	"encoding/json"
+	"io"
+	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 
+	"github.com/ghoseb/bb/internal/agent"
 	"github.com/ghoseb/bb/internal/secret"
+	"github.com/ghoseb/bb/pkg/iostreams"
 )
 
 func TestCredentialsSerialization(t *testing.T) {
@@ -63,12 +70,12 @@ func TestSaveAndLoadCredentials(t *testing.T) {
 		Token:     "my-secret-token",
 	}
 
-	if err := SaveCredentialsToStore(store, original); err != nil {
+	if err := SaveCredentialsToStore(store, "", original); err != nil {
 		t.Fatalf("save credentials: %v", err)
 	}
 
 	// Load credentials back
-	loaded, err := LoadCredentialsFromStore(store)
+	loaded, err := LoadCredentialsFromStore(store, "")
 	if err != nil {
 		t.Fatalf("load credentials: %v", err)
 	}
@@ -84,3 +91,190 @@ func TestSaveAndLoadCredentials(t *testing.T) {
 		t.Errorf("token mismatch: got %q, want %q", loaded.Token, original.Token)
 	}
 }
+
+func TestCredentialsKey(t *testing.T) {
+	if got := CredentialsKey(""); got != "bb/credentials" {
+		t.Errorf("default profile key = %q", got)
+	}
+	if got := CredentialsKey("work"); got != "bb/credentials/work" {
+		t.Errorf("work profile key = %q", got)
+	}
+}
+
+func TestStoredProfiles(t *testing.T) {
+	keyringDir := filepath.Join(t.TempDir(), "test-keyring")
+	t.Setenv("BB_ALLOW_INSECURE_STORE", "1")
+
+	store, err := secret.Open(
+		secret.WithAllowFileFallback(true),
+		secret.WithFileDir(keyringDir),
+		secret.WithPassphrase("test-passphrase"),
+	)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+
+	for _, profile := range []string{"work", "", "oss"} {
+		if err := SaveCredentialsToStore(store, profile, &Credentials{Workspace: "ws", Token: "t"}); err != nil {
+			t.Fatalf("save credentials: %v", err)
+		}
+	}
+	if err := store.Set("bb/other", "x"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	profiles, err := StoredProfiles(store)
+	if err != nil {
+		t.Fatalf("stored profiles: %v", err)
+	}
+	if want := []string{"", "oss", "work"}; !slices.Equal(profiles, want) {
+		t.Errorf("profiles = %q, want %q", profiles, want)
+	}
+}
+
+func TestMaskSecret(t *testing.T) {
+	if got := MaskSecret("short"); got != "****" {
+		t.Errorf("MaskSecret(short) = %q", got)
+	}
+	if got := MaskSecret("abcdefghijkl1234"); got != "****1234" {
+		t.Errorf("MaskSecret(long) = %q", got)
+	}
+}
+
+func TestCredentialsFromAgent(t *testing.T) {
+	dir, err := os.MkdirTemp("", "bbag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "agent.sock")
+	t.Setenv("BB_AGENT_SOCKET", socket)
+	ln, err := agent.Listen(socket)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = agent.NewServer(time.Minute).Serve(ctx, ln) }()
+
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv("BB_WORKSPACE", "")
+	t.Setenv("BB_PROFILE", "")
+	t.Setenv("BB_ALLOW_INSECURE_STORE", "1")
+	t.Setenv("KEYRING_BACKEND", "file")
+	t.Setenv("KEYRING_FILE_DIR", filepath.Join(t.TempDir(), "keyring"))
+	t.Setenv("BB_KEYRING_PASSPHRASE", "test-passphrase")
+
+	if err := agent.Put(CredentialsKey(""), `{"Workspace":"acme","Username":"alice","Token":"agent-token"}`); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	f := NewFactory("test", &iostreams.IOStreams{In: os.Stdin, Out: io.Discard, ErrOut: io.Discard})
+	creds, err := f.GetCredentials()
+	if err != nil {
+		t.Fatalf("credentials: %v", err)
+	}
+	if creds.Token != "agent-token" {
+		t.Errorf("token = %q, want the agent's", creds.Token)
+	}
+
+	// Saving credentials drops the agent's copy
+	store, err := f.GetSecretStore()
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	if err := SaveCredentialsToStore(store, "", &Credentials{Workspace: "acme", Token: "new-token"}); err != nil {
+		t.Fatalf("save credentials: %v", err)
+	}
+	if _, ok, _ := agent.Get(CredentialsKey("")); ok {
+		t.Error("expected saving credentials to clear the agent entry")
+	}
+}
+
+func TestWorkspaceCredentials(t *testing.T) {
+	t.Setenv("BB_AGENT_SOCKET", filepath.Join(t.TempDir(), "none.sock"))
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv("BB_HOST", "")
+	t.Setenv("BB_PROFILE", "")
+	t.Setenv("BB_WORKSPACE", "")
+	t.Setenv("BB_TOKEN", "")
+
+	store := secret.NewMemoryStore()
+	newFactory := func(workspace string) *Factory {
+		f := NewFactory("test", &iostreams.IOStreams{In: os.Stdin, Out: io.Discard, ErrOut: io.Discard})
+		f.SecretStore = store
+		f.WorkspaceOverride = workspace
+		return f
+	}
+
+	// A login from before credentials were kept per workspace
+	if err := SaveCredentialsToStore(store, "", &Credentials{Workspace: "acme", Token: "legacy-token"}); err != nil {
+		t.Fatalf("save credentials: %v", err)
+	}
+	creds, err := newFactory("").GetCredentials()
+	if err != nil || creds.Token != "legacy-token" {
+		t.Fatalf("profile credentials: %+v err=%v", creds, err)
+	}
+
+	// Loading it migrated it to the workspace entry
+	migrated, err := LoadWorkspaceCredentials(store, "bitbucket.org", "acme")
+	if err != nil || migrated.Token != "legacy-token" {
+		t.Fatalf("migrated credentials: %+v err=%v", migrated, err)
+	}
+	if profile, _ := LoadCredentialsFromStore(store, ""); profile.Host != "bitbucket.org" {
+		t.Errorf("profile entry host = %q, want it recorded", profile.Host)
+	}
+
+	if err := SaveWorkspaceCredentials(store, &Credentials{Host: "bitbucket.org", Workspace: "other", Token: "other-token"}); err != nil {
+		t.Fatalf("save workspace credentials: %v", err)
+	}
+	workspaces, err := StoredWorkspaces(store)
+	if err != nil {
+		t.Fatalf("stored workspaces: %v", err)
+	}
+	if want := []string{"bitbucket.org/acme", "bitbucket.org/other"}; !slices.Equal(workspaces, want) {
+		t.Errorf("workspaces = %q, want %q", workspaces, want)
+	}
+
+	tests := []struct {
+		workspace string
+		want      string
+	}{
+		{"other", "other-token"},
+		{"acme", "legacy-token"},
+		{"unknown", "legacy-token"}, // no entry: the profile's login
+	}
+	for _, tt := range tests {
+		creds, err := newFactory(tt.workspace).GetCredentials()
+		if err != nil {
+			t.Fatalf("--workspace %s: %v", tt.workspace, err)
+		}
+		if creds.Token != tt.want {
+			t.Errorf("--workspace %s: token = %q, want %q", tt.workspace, creds.Token, tt.want)
+		}
+	}
+
+	// A second profile on the same workspace keeps its own login, and loading
+	// it leaves the workspace entry alone
+	if err := SaveCredentialsToStore(store, "work", &Credentials{Workspace: "acme", Token: "work-token"}); err != nil {
+		t.Fatalf("save credentials: %v", err)
+	}
+	for _, viaEnv := range []bool{false, true} {
+		f := newFactory("acme")
+		if viaEnv {
+			t.Setenv("BB_PROFILE", "work")
+		} else {
+			f.ProfileOverride = "work"
+		}
+		creds, err := f.GetCredentials()
+		if err != nil || creds.Token != "work-token" {
+			t.Errorf("profile work (env %v): %+v err=%v, want its own login", viaEnv, creds, err)
+		}
+	}
+	t.Setenv("BB_PROFILE", "")
+	if ws, err := LoadWorkspaceCredentials(store, "bitbucket.org", "acme"); err != nil || ws.Token != "legacy-token" {
+		t.Errorf("workspace entry = %+v err=%v, want the default profile's", ws, err)
+	}
+	if creds, err := newFactory("acme").GetCredentials(); err != nil || creds.Token != "legacy-token" {
+		t.Errorf("default profile: %+v err=%v", creds, err)
+	}
+}
@@ -0,0 +1,231 @@
+package cmdutil
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/internal/config"
+	"github.com/ghoseb/bb/pkg/bbcloud"
+)
+
+const (
+	// completionTTL is how long candidates fetched for completion are reused,
+	// so repeated Tab presses do not each wait for the API
+	completionTTL = 2 * time.Minute
+
+	completionTimeout = 5 * time.Second
+
+	// completionRepoLimit caps the repositories offered, most recently updated first
+	completionRepoLimit = 1000
+)
+
+// prArgUsages are the first argument names, in a command's Use, of commands
+// taking PR numbers
+var prArgUsages = []string{"<pr-number>", "[pr-number]", "<pr-id>", "[pr-number...]"}
+
+// RegisterCompletions adds dynamic shell completion to root and every command
+// below it: --repo completes repository slugs, --workspace workspace slugs, and
+// commands whose first argument is a PR number complete open PR numbers with
+// their titles. Candidates are cached for a short while in the cache dir.
+func (f *Factory) RegisterCompletions(root *cobra.Command) {
+	var visit func(cmd *cobra.Command)
+	visit = func(cmd *cobra.Command) {
+		// Inherited flags are registered where they are defined
+		local := cmd.LocalFlags()
+		if local.Lookup("repo") != nil {
+			_ = cmd.RegisterFlagCompletionFunc("repo", f.completeRepos)
+		}
+		if local.Lookup("workspace") != nil {
+			_ = cmd.RegisterFlagCompletionFunc("workspace", f.completeWorkspaces)
+		}
+
+		if words := strings.Fields(cmd.Use); cmd.ValidArgsFunction == nil && len(words) > 1 && slices.Contains(prArgUsages, words[1]) {
+			multiple := strings.HasSuffix(words[1], "...]")
+			cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+				if len(args) > 0 && !multiple {
+					return nil, cobra.ShellCompDirectiveDefault
+				}
+				return f.completePRs(cmd, args), cobra.ShellCompDirectiveNoFileComp
+			}
+		}
+
+		for _, c := range cmd.Commands() {
+			visit(c)
+		}
+	}
+	visit(root)
+}
+
+func (f *Factory) completeWorkspaces(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	f.applyCompletionOverrides(cmd)
+	candidates := f.cachedCompletions(cmd, "workspaces", "", func(ctx context.Context, client *bbcloud.Client) ([]string, error) {
+		permissions, err := client.ListWorkspaces(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var out []string
+		for _, p := range permissions {
+			if p.Workspace != nil {
+				out = append(out, completion(p.Workspace.Slug, p.Workspace.Name))
+			}
+		}
+		return out, nil
+	})
+	return candidates, cobra.ShellCompDirectiveNoFileComp
+}
+
+func (f *Factory) completeRepos(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	f.applyCompletionOverrides(cmd)
+	candidates := f.cachedCompletions(cmd, "repos", "", func(ctx context.Context, client *bbcloud.Client) ([]string, error) {
+		repos, err := client.QueryRepositories(ctx, bbcloud.RepoListOptions{Sort: "-updated_on", Limit: completionRepoLimit})
+		if err != nil {
+			return nil, err
+		}
+		out := make([]string, 0, len(repos))
+		for _, r := range repos {
+			out = append(out, completion(r.Slug, r.Name))
+		}
+		return out, nil
+	})
+	return candidates, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completePRs returns the open PRs of the command's repository, less the
+// numbers already given
+func (f *Factory) completePRs(cmd *cobra.Command, args []string) []string {
+	f.applyCompletionOverrides(cmd)
+
+	repo, _ := cmd.Flags().GetString("repo")
+	if repo == "" {
+		cfg, err := f.Config()
+		if err != nil {
+			return nil
+		}
+		if repo = cfg.WorkspaceRepo(f.workspace(cmd, cfg)); repo == "" {
+			repo = cfg.Repo()
+		}
+	}
+	if repo == "" {
+		return nil
+	}
+
+	candidates := f.cachedCompletions(cmd, "prs", repo, func(ctx context.Context, client *bbcloud.Client) ([]string, error) {
+		prs, err := client.QueryPullRequests(ctx, repo, bbcloud.PRListOptions{State: "OPEN", Limit: 100})
+		if err != nil {
+			return nil, err
+		}
+		out := make([]string, 0, len(prs))
+		for _, pr := range prs {
+			out = append(out, completion(strconv.Itoa(pr.ID), pr.Title))
+		}
+		return out, nil
+	})
+	return slices.DeleteFunc(candidates, func(c string) bool {
+		id, _, _ := strings.Cut(c, "\t")
+		return slices.Contains(args, id)
+	})
+}
+
+// applyCompletionOverrides applies the global flags, as the root command's
+// pre-run does for normal execution
+func (f *Factory) applyCompletionOverrides(cmd *cobra.Command) {
+	f.HostOverride, _ = cmd.Flags().GetString("host")
+	f.ProfileOverride, _ = cmd.Flags().GetString("profile")
+}
+
+// cachedCompletions returns the candidates of kind for the command's host,
+// profile and workspace (and scope, if any), fetching them when the cache
+// is missing or stale. Errors yield no candidates.
+func (f *Factory) cachedCompletions(cmd *cobra.Command, kind, scope string, fetch func(context.Context, *bbcloud.Client) ([]string, error)) []string {
+	cfg, err := f.Config()
+	if err != nil {
+		return nil
+	}
+	host, err := f.Host()
+	if err != nil {
+		return nil
+	}
+	profile, _ := f.Profile()
+	ws := ""
+	if kind != "workspaces" {
+		ws = f.workspace(cmd, cfg)
+	}
+
+	key := strings.Join([]string{host.Name, profile, ws, kind, scope}, "\x00")
+	sum := sha256.Sum256([]byte(key))
+	path := filepath.Join(config.CacheDir(), "completion", hex.EncodeToString(sum[:8])+".json")
+
+	if candidates, ok := readCompletionCache(path, time.Now()); ok {
+		return candidates
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, completionTimeout)
+	defer cancel()
+
+	client, err := f.NewBBCloudClient(ws)
+	if err != nil {
+		cobra.CompDebugln(err.Error(), false)
+		return nil
+	}
+	candidates, err := fetch(ctx, client)
+	if err != nil {
+		cobra.CompDebugln(fmt.Sprintf("complete %s: %v", kind, err), false)
+		return nil
+	}
+	writeCompletionCache(path, candidates)
+	return candidates
+}
+
+// completion formats a candidate with its description
+func completion(value, description string) string {
+	description = strings.Join(strings.Fields(description), " ")
+	if description == "" || description == value {
+		return value
+	}
+	return value + "\t" + description
+}
+
+// readCompletionCache returns the candidates cached at path unless they are
+// older than completionTTL
+func readCompletionCache(path string, now time.Time) ([]string, bool) {
+	info, err := os.Stat(path)
+	if err != nil || now.Sub(info.ModTime()) > completionTTL {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var candidates []string
+	if err := json.Unmarshal(data, &candidates); err != nil {
+		return nil, false
+	}
+	return candidates, true
+}
+
+// writeCompletionCache caches candidates at path; failures only cost a refetch
+func writeCompletionCache(path string, candidates []string) {
+	data, err := json.Marshal(candidates)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0o600)
+}
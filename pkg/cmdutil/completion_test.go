@@ -0,0 +1,93 @@
+package cmdutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/internal/config"
+	"github.com/ghoseb/bb/pkg/iostreams"
+)
+
+func TestRegisterCompletions(t *testing.T) {
+	f := NewFactory("test", iostreams.System())
+
+	root := &cobra.Command{Use: "bbc"}
+	root.PersistentFlags().String("workspace", "", "")
+	view := &cobra.Command{Use: "view [pr-number] [file-path]", Run: func(*cobra.Command, []string) {}}
+	view.Flags().String("repo", "", "")
+	create := &cobra.Command{Use: "create <source-branch> <title>", Run: func(*cobra.Command, []string) {}}
+	root.AddCommand(view, create)
+
+	f.RegisterCompletions(root)
+
+	if _, ok := root.GetFlagCompletionFunc("workspace"); !ok {
+		t.Error("--workspace has no completion")
+	}
+	if _, ok := view.GetFlagCompletionFunc("repo"); !ok {
+		t.Error("--repo has no completion")
+	}
+	if view.ValidArgsFunction == nil {
+		t.Error("view [pr-number] does not complete PR numbers")
+	}
+	if create.ValidArgsFunction != nil {
+		t.Error("create completes PR numbers")
+	}
+}
+
+func TestCompletePRs(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/acme/api/pullrequests" {
+			http.NotFound(w, r)
+			return
+		}
+		hits++
+		_, _ = w.Write([]byte(`{"values":[{"id":7,"title":"Fix  login"},{"id":9,"title":"Add page"}]}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv("BB_HOST", "test")
+	t.Setenv("BB_WORKSPACE", "acme")
+	t.Setenv("BB_USERNAME", "alice")
+	t.Setenv("BB_TOKEN", "secret")
+	cfg := config.New(config.DefaultPath())
+	cfg.Set("hosts.test.api_url", server.URL)
+	cfg.Set("default_repo", "api")
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	f := NewFactory("test", iostreams.System())
+	f.GitClient.Dir = t.TempDir()
+	bulk := &cobra.Command{Use: "approve [pr-number...]"}
+	bulk.Flags().String("repo", "", "")
+
+	if got := strings.Join(f.completePRs(bulk, nil), ","); got != "7\tFix login,9\tAdd page" {
+		t.Errorf("completePRs = %q", got)
+	}
+	if got := strings.Join(f.completePRs(bulk, []string{"7"}), ","); got != "9\tAdd page" {
+		t.Errorf("completePRs after 7 = %q, want only 9", got)
+	}
+	if hits != 1 {
+		t.Errorf("API hit %d times, want 1 (second completion cached)", hits)
+	}
+}
+
+func TestCompletionCacheExpiry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "completion", "x.json")
+	writeCompletionCache(path, []string{"api"})
+
+	if got, ok := readCompletionCache(path, time.Now()); !ok || len(got) != 1 || got[0] != "api" {
+		t.Errorf("fresh cache = %v, %v", got, ok)
+	}
+	if _, ok := readCompletionCache(path, time.Now().Add(completionTTL+time.Second)); ok {
+		t.Error("stale cache used")
+	}
+}
@@ -0,0 +1,16 @@
+package cmdutil
+
+// Concurrency returns how many API requests a command may send at once: the
+// --concurrency flag, else the concurrency setting (default 5). The HTTP
+// client sends requests one at a time whenever the rate limit runs low, so
+// commands need not check it themselves.
+func (f *Factory) Concurrency() int {
+	n := f.ConcurrencyOverride
+	if n < 1 {
+		n = 5
+		if cfg, err := f.Config(); err == nil {
+			n = cfg.Concurrency()
+		}
+	}
+	return n
+}
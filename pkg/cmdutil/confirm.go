@@ -0,0 +1,30 @@
+package cmdutil
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCancelled indicates the user declined a confirmation prompt.
+var ErrCancelled = errors.New("cancelled")
+
+// Confirm asks the user to confirm a destructive action, defaulting to no,
+// and returns ErrCancelled if they decline. With --yes it accepts without
+// asking; without it, it fails when stdin is not a terminal, so scripts must
+// pass --yes.
+func (f *Factory) Confirm(prompt string) error {
+	if f.Yes {
+		return nil
+	}
+	if !f.IOStreams.CanPrompt() {
+		return fmt.Errorf("%s: pass --yes to confirm when stdin is not a terminal", prompt)
+	}
+	ok, err := f.Prompter.Confirm(prompt, false)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrCancelled
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/ghoseb/bb/internal/config"
+)
+
+// ApplyConfigDefaults fills flags the user did not set from the configuration.
+//
+// A flag takes the most specific config key formed from the command path and the
+// flag name: for "bbc review list --state" that is review.list.state, then
+// review.state. Only keys of options marked Flag apply, and only from the user
+// and local config, so neither a stray key nor a repository's .bb.yml can turn
+// on a flag such as --allow-write. A --repo flag further falls back to the current workspace's
+// default repo and then default_repo; when none of these apply it is picked
+// from the workspace's repositories on a terminal, and required otherwise.
+// A --json flag defaults to true when the format setting is json.
+func (f *Factory) ApplyConfigDefaults(cmd *cobra.Command) error {
+	cfg, err := f.Config()
+	if err != nil {
+		return err
+	}
+	user, err := f.UserConfig()
+	if err != nil {
+		return err
+	}
+
+	path := strings.Fields(cmd.CommandPath())[1:]
+
+	var setErr error
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if setErr != nil || flag.Changed || flag.Name == "help" {
+			return
+		}
+		for i := len(path); i >= 1; i-- {
+			key := strings.Join(path[:i], ".") + "." + flag.Name
+			if opt, ok := config.LookupOption(key); !ok || !opt.Flag {
+				continue
+			}
+			value := user.Get(key)
+			if value == "" {
+				continue
+			}
+			if err := cmd.Flags().Set(flag.Name, value); err != nil {
+				setErr = fmt.Errorf("config %s: %w", key, err)
+			}
+			return
+		}
+	})
+	if setErr != nil {
+		return setErr
+	}
+
+	// The format setting makes JSON the default wherever --json is available
+	if jsonFlag := cmd.Flags().Lookup("json"); jsonFlag != nil && !jsonFlag.Changed && cfg.Format() == FormatJSON {
+		if err := cmd.Flags().Set("json", "true"); err != nil {
+			return err
+		}
+	}
+
+	repoFlag := cmd.Flags().Lookup("repo")
+	if repoFlag == nil || repoFlag.Value.String() != "" {
+		return nil
+	}
+
+	repo := user.WorkspaceRepo(f.workspace(cmd, user))
+	if repo == "" {
+		repo = user.Repo()
+	}
+	if repo == "" && f.IOStreams.CanPrompt() && f.Prompter != nil {
+		if repo, err = f.pickRepo(cmd.Context()); err != nil {
+			return err
+		}
+	}
+	if repo == "" {
+		return &ValidationError{Field: "repo", Msg: "is required (set --repo or 'bbc config set default_repo <repo>')"}
+	}
+	return cmd.Flags().Set("repo", repo)
+}
+
+// workspace resolves the workspace a command will run against without touching
+// the keyring unless workspace-scoped settings exist
+func (f *Factory) workspace(cmd *cobra.Command, cfg *config.Config) string {
+	override, _ := cmd.Flags().GetString("workspace")
+	ws, _, err := f.ResolveWorkspace(override, cfg.HasWorkspaceSettings())
+	if err != nil {
+		return ""
+	}
+	return ws
+}
+
+// pickRepo lets the user choose a repository of the workspace
+func (f *Factory) pickRepo(ctx context.Context) (string, error) {
+	client, err := f.NewBBCloudClient("")
+	if err != nil {
+		return "", err
+	}
+	repos, err := client.ListRepositories(ctx, 0)
+	if err != nil {
+		return "", fmt.Errorf("list repositories: %w", err)
+	}
+	if len(repos) == 0 {
+		return "", nil
+	}
+
+	slugs := make([]string, len(repos))
+	for i, r := range repos {
+		slugs[i] = r.Slug
+	}
+	choice, err := f.Prompter.FuzzySelect("Repository:", slugs)
+	if err != nil {
+		return "", fmt.Errorf("select repository: %w", err)
+	}
+	return slugs[choice], nil
+}
@@ -0,0 +1,46 @@
+package cmdutil
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/ghoseb/bb/pkg/prompter"
+)
+
+// Editor opens the user's editor on a temporary file seeded with initial and
+// returns the saved text. The editor is the editor setting of the user or local
+// config (never .bb.yml), else $VISUAL, else $EDITOR, else a platform default. It fails when stdin is not a terminal,
+// so non-interactive callers must supply text via flags or arguments.
+func (f *Factory) Editor(pattern, initial string) (string, error) {
+	if !f.IOStreams.CanPrompt() {
+		return "", fmt.Errorf("no text supplied, and prompts are disabled (--no-input, BB_NO_INPUT, or stdin is not a terminal) so no editor is opened")
+	}
+
+	editor, err := f.editorCommand()
+	if err != nil {
+		return "", err
+	}
+
+	ios := f.IOStreams
+	return prompter.Edit(editor, pattern, initial, ios.In, ios.Out, ios.ErrOut)
+}
+
+func (f *Factory) editorCommand() (string, error) {
+	cfg, err := f.UserConfig()
+	if err != nil {
+		return "", err
+	}
+	if editor := cfg.Editor(); editor != "" {
+		return editor, nil
+	}
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := os.Getenv(env); editor != "" {
+			return editor, nil
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad", nil
+	}
+	return "vi", nil
+}
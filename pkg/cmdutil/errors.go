@@ -1,6 +1,9 @@
 package cmdutil
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // ExitError carries an exit code and optional user-facing message.
 type ExitError struct {
@@ -29,3 +32,9 @@ func (e *ValidationError) Error() string {
 	}
 	return fmt.Sprintf("%s: %s", e.Field, e.Msg)
 }
+
+// MissingInputError reports input that would have been prompted for, naming
+// the flags that supply it, when prompts are disabled.
+func MissingInputError(flags ...string) error {
+	return &ValidationError{Msg: fmt.Sprintf("missing %s; prompts are disabled (--no-input, BB_NO_INPUT, or stdin is not a terminal)", strings.Join(flags, ", "))}
+}
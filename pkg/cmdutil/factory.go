@@ -1,10 +1,23 @@
 package cmdutil
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/ghoseb/bb/internal/agent"
+	"github.com/ghoseb/bb/internal/config"
 	"github.com/ghoseb/bb/internal/secret"
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/browser"
+	"github.com/ghoseb/bb/pkg/git"
+	"github.com/ghoseb/bb/pkg/httpx"
 	"github.com/ghoseb/bb/pkg/iostreams"
 	"github.com/ghoseb/bb/pkg/prompter"
 )
@@ -14,16 +27,97 @@ type Factory struct {
 	AppVersion string
 	IOStreams  *iostreams.IOStreams
 	Prompter   prompter.Prompter
+	GitClient  *git.Client
+	Browser    browser.Browser
+
+	// HostOverride and ProfileOverride are the --host and --profile flag values,
+	// set by the root command before dispatch
+	HostOverride    string
+	ProfileOverride string
+
+	// RecordPath and ReplayPath are the --record and --replay flag values,
+	// set by the root command before dispatch
+	RecordPath string
+	ReplayPath string
+
+	// DryRun is the --dry-run flag: API clients print mutating requests
+	// instead of sending them
+	DryRun bool
+
+	// Offline is the --offline flag: API clients answer GET requests from the
+	// response cache and send nothing
+	Offline bool
+
+	// NoCache is the --no-cache flag: API clients neither read nor write the
+	// response cache, so every read comes fresh from the API
+	NoCache bool
+
+	// Deadline is when the --max-time budget of the invocation runs out, or
+	// zero without one; API clients fail requests past it
+	Deadline time.Time
+
+	// ConcurrencyOverride is the --concurrency flag; 0 uses the concurrency
+	// setting (see Concurrency)
+	ConcurrencyOverride int
+
+	// Yes is the --yes flag: confirmation prompts accept without asking
+	Yes bool
+
+	// NoInput is set by --no-input, BB_NO_INPUT, or stdin not being a
+	// terminal: commands fail naming the missing flag instead of prompting
+	NoInput bool
+
+	// WorkspaceOverride is the --workspace flag value, set by the root command
+	// before dispatch; it also selects the credentials stored for the workspace
+	WorkspaceOverride string
+
+	// SecretStore, when set, holds credentials instead of the keyring, e.g. a
+	// secret.MemoryStore in tests
+	SecretStore secret.Store
+
+	// KeyringBackend is the --keyring-backend flag: the comma-separated
+	// keyring backends to try, overriding KEYRING_BACKEND
+	KeyringBackend string
+
+	// Command is the path of the running command without the program name
+	// (e.g. "review approve"), recorded in the audit log
+	Command string
 
 	// secret store cache - keeps keyring unlocked for the session
 	storeOnce sync.Once
-	store     *secret.Store
+	store     secret.Store
 	storeErr  error
 
 	// credentials cache
 	credsOnce sync.Once
 	creds     *Credentials
 	credsErr  error
+
+	// configuration cache: the allowed settings of .bb.yml, and the user and
+	// local configs merged; the profile overlay is recomputed if the profile
+	// changes
+	configOnce    sync.Once
+	project       *config.Config
+	config        *config.Config
+	configErr     error
+	overlayMu     sync.Mutex
+	overlay       *config.Config
+	overlayFor    string
+	effective     *config.Config
+	effectiveFrom *config.Config
+
+	// HTTP cassette, shared by every client of the invocation
+	cassetteOnce sync.Once
+	cassette     *httpx.Cassette
+	cassetteErr  error
+
+	// on-disk response cache, shared by every client of the invocation
+	responseCacheOnce sync.Once
+	responseCache     *httpx.ResponseCache
+
+	// API clients of the invocation, by API URL, workspace and credentials
+	clientsMu sync.Mutex
+	clients   map[string]*bbcloud.Client
 }
 
 // NewFactory constructs a new Factory instance.
@@ -32,6 +126,8 @@ func NewFactory(appVersion string, ios *iostreams.IOStreams) *Factory {
 		AppVersion: appVersion,
 		IOStreams:  ios,
 		Prompter:   prompter.New(ios.In, ios.Out, ios.ErrOut),
+		GitClient:  git.New(""),
+		Browser:    browser.New(""),
 	}
 }
 
@@ -42,13 +138,146 @@ func (f *Factory) Streams() (*iostreams.IOStreams, error) {
 
 // GetSecretStore opens the secret store once and caches it for the lifetime of the Factory.
 // This keeps the keyring session open and prevents multiple unlock prompts.
-func (f *Factory) GetSecretStore() (*secret.Store, error) {
+func (f *Factory) GetSecretStore() (secret.Store, error) {
 	f.storeOnce.Do(func() {
-		f.store, f.storeErr = secret.Open(secret.WithAllowFileFallback(true))
+		if f.SecretStore != nil {
+			f.store = f.SecretStore
+			return
+		}
+		f.store, f.storeErr = secret.Open(f.SecretStoreOptions()...)
 	})
 	return f.store, f.storeErr
 }
 
+// SecretStoreOptions returns the options GetSecretStore opens the store with
+func (f *Factory) SecretStoreOptions() []secret.Option {
+	return []secret.Option{secret.WithAllowFileFallback(true), secret.WithBackend(f.KeyringBackend)}
+}
+
+// Config returns the effective configuration, loaded once and cached for the lifetime of
+// the Factory. Layers, lowest precedence first: the checked-in .bb.yml at the repository
+// root, the user config, and the repository's local config, with the active profile's
+// profiles.<name> settings applied on top. Missing files are treated as empty. The result
+// is read-only — write settings through config.Load.
+//
+// Only the config.ProjectKeys settings are read from .bb.yml; see UserConfig.
+func (f *Factory) Config() (*config.Config, error) {
+	user, err := f.UserConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	f.overlayMu.Lock()
+	defer f.overlayMu.Unlock()
+	if f.effective == nil || f.effectiveFrom != user {
+		f.effective, f.effectiveFrom = config.Merge(f.project, user), user
+	}
+	return f.effective, nil
+}
+
+// UserConfig returns the configuration without the checked-in .bb.yml: the user
+// config and the repository's local config, with the profile overlay. Settings a
+// cloned repository must not choose, such as aliases and the editor, are read
+// from it.
+func (f *Factory) UserConfig() (*config.Config, error) {
+	base, err := f.baseConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	profile := f.profile(base)
+	overlay := base.Profile(profile)
+	if overlay == nil {
+		return base, nil
+	}
+
+	f.overlayMu.Lock()
+	defer f.overlayMu.Unlock()
+	if f.overlay == nil || f.overlayFor != profile {
+		f.overlay, f.overlayFor = config.Merge(base, overlay), profile
+	}
+	return f.overlay, nil
+}
+
+// baseConfig loads the config files once and returns the user and local
+// configs merged, without any profile overlay
+func (f *Factory) baseConfig() (*config.Config, error) {
+	f.configOnce.Do(func() {
+		f.project, f.config, f.configErr = f.loadConfig()
+	})
+	return f.config, f.configErr
+}
+
+// loadConfig returns the allowed settings of the project config, and the user
+// and local configs merged
+func (f *Factory) loadConfig() (*config.Config, *config.Config, error) {
+	user, err := config.Load(config.DefaultPath())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx := context.Background()
+	gitDir, err := f.GitClient.CommonDir(ctx)
+	if err != nil {
+		// Not inside a repository (or git is unavailable): only the user config applies
+		return nil, config.Merge(user), nil
+	}
+	local, err := config.Load(config.LocalPath(gitDir))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Bare repositories have no working tree and so no project config
+	var project *config.Config
+	if root, err := f.GitClient.TopLevel(ctx); err == nil {
+		if project, err = config.Load(config.ProjectPath(root)); err != nil {
+			return nil, nil, err
+		}
+		var dropped []string
+		if project, dropped = project.ProjectSettings(); len(dropped) > 0 {
+			_, _ = fmt.Fprintf(f.IOStreams.ErrOut, "warning: ignoring %s in %s (a project config may only set %s)\n",
+				strings.Join(dropped, ", "), project.Path(), strings.Join(config.ProjectKeys(), ", "))
+		}
+	}
+
+	return project, config.Merge(user, local), nil
+}
+
+// Cassette returns the cassette API clients record to (--record) or replay
+// from (--replay), or nil when neither is set
+func (f *Factory) Cassette() (*httpx.Cassette, error) {
+	f.cassetteOnce.Do(func() {
+		switch {
+		case f.RecordPath != "" && f.ReplayPath != "":
+			f.cassetteErr = fmt.Errorf("--record and --replay cannot be used together")
+		case f.RecordPath != "":
+			f.cassette = httpx.NewRecorder(f.RecordPath)
+		case f.ReplayPath != "":
+			f.cassette, f.cassetteErr = httpx.LoadCassette(f.ReplayPath)
+		}
+	})
+	return f.cassette, f.cassetteErr
+}
+
+// ResponseCache returns the on-disk cache of GET responses API clients serve
+// from when offline (--offline) or when the network fails
+func (f *Factory) ResponseCache() *httpx.ResponseCache {
+	f.responseCacheOnce.Do(func() {
+		f.responseCache = httpx.NewResponseCache(filepath.Join(config.CacheDir(), "http"), f.Offline)
+	})
+	return f.responseCache
+}
+
+// StaleAsOf returns when the oldest response served from the response cache
+// instead of the API was stored, or the zero time if the API answered every
+// request. Call it once the command has finished.
+func (f *Factory) StaleAsOf() time.Time {
+	if f.responseCache == nil {
+		return time.Time{}
+	}
+	return f.responseCache.StaleAsOf()
+}
+
 // GetCredentials loads credentials from the keyring once and caches them for the lifetime of the Factory.
 // This prevents multiple keyring unlock prompts during a single CLI invocation.
 func (f *Factory) GetCredentials() (*Credentials, error) {
@@ -70,16 +299,183 @@ func loadCredentialsFromEnv() *Credentials {
 	return nil
 }
 
-// loadCredentials loads credentials from env vars first, then falls back to keyring.
+// EnvCredentialsSet reports whether credentials come from the environment,
+// bypassing the keyring
+func EnvCredentialsSet() bool {
+	return loadCredentialsFromEnv() != nil
+}
+
+// CredentialKeys returns the keyring keys stored credentials are read from,
+// first found wins. A named profile always uses its own entry. The default
+// profile uses the entry of the workspace asked for on the active host when
+// there is one, else its own; workspace entries are the default profile's, so
+// they never stand in for the login of another profile. Nothing is read from
+// the keyring.
+func (f *Factory) CredentialKeys() ([]string, error) {
+	host, err := f.Host()
+	if err != nil {
+		return nil, err
+	}
+	profile, err := f.Profile()
+	if err != nil {
+		return nil, err
+	}
+	if profile != "" {
+		return []string{CredentialsKey(profile)}, nil
+	}
+
+	// Without loading credentials, the workspace is only known if asked for
+	ws, _, err := f.ResolveWorkspace("", false)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	if ws != "" {
+		keys = append(keys, WorkspaceCredentialsKey(host.Name, ws))
+	}
+	return append(keys, CredentialsKey("")), nil
+}
+
+// loadCredentials loads credentials from env vars first, then from the
+// keyring entries of CredentialKeys.
 func (f *Factory) loadCredentials() (*Credentials, error) {
 	if creds := loadCredentialsFromEnv(); creds != nil {
 		return creds, nil
 	}
 
+	// Surface unknown or misconfigured hosts before touching the keyring
+	keys, err := f.CredentialKeys()
+	if err != nil {
+		return nil, err
+	}
+	for i, key := range keys {
+		creds, err := f.storedCredentials(key)
+		if errors.Is(err, ErrNotAuthenticated) && i < len(keys)-1 {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if key == CredentialsKey("") && creds.Host == "" && creds.Workspace != "" {
+			if host, err := f.Host(); err == nil {
+				f.migrateCredentials(host.Name, creds)
+			}
+		}
+		return creds, nil
+	}
+	return nil, ErrNotAuthenticated
+}
+
+// storedCredentials reads the credentials at key from a running session
+// agent, which answers without touching the keyring, or else the keyring.
+// The agent is only asked when it is enabled (see UseAgent).
+func (f *Factory) storedCredentials(key string) (*Credentials, error) {
+	useAgent := f.UseAgent()
+	if useAgent {
+		if data, ok, err := agent.Get(key); err == nil && ok {
+			var creds Credentials
+			if json.Unmarshal([]byte(data), &creds) == nil {
+				return &creds, nil
+			}
+		}
+	}
+
 	store, err := f.GetSecretStore()
 	if err != nil {
 		return nil, err
 	}
+	creds, err := loadCredentialsKey(store, key)
+	if err != nil {
+		return nil, err
+	}
+	if !useAgent {
+		return creds, nil
+	}
+	if data, err := json.Marshal(creds); err == nil {
+		_ = agent.Put(key, string(data))
+	}
+	return creds, nil
+}
+
+// UseAgent reports whether credentials are cached in the session agent: only
+// when BB_AGENT_SOCKET is set or the agent setting of the user config is true
+func (f *Factory) UseAgent() bool {
+	if os.Getenv("BB_AGENT_SOCKET") != "" {
+		return true
+	}
+	cfg, err := f.UserConfig()
+	return err == nil && cfg.Agent()
+}
+
+// migrateCredentials copies the default profile's login from before
+// credentials were kept per workspace to the entry of its workspace on host,
+// unless that workspace has its own login, and records the host in the
+// profile's entry so this happens once. Failures only postpone the migration.
+func (f *Factory) migrateCredentials(host string, creds *Credentials) {
+	store, err := f.GetSecretStore()
+	if err != nil {
+		return
+	}
+	migrated := *creds
+	migrated.Host = host
+	if _, err := LoadWorkspaceCredentials(store, host, creds.Workspace); errors.Is(err, ErrNotAuthenticated) {
+		if SaveWorkspaceCredentials(store, &migrated) != nil {
+			return
+		}
+	}
+	_ = SaveCredentialsToStore(store, "", &migrated)
+}
+
+// Profile returns the active auth profile: --profile, then BB_PROFILE, then the
+// profile bound to the host entry, then the profile setting. The empty string is
+// the default profile.
+func (f *Factory) Profile() (string, error) {
+	cfg, err := f.baseConfig()
+	if err != nil {
+		return "", err
+	}
+	return f.profile(cfg), nil
+}
+
+func (f *Factory) profile(cfg *config.Config) string {
+	if f.ProfileOverride != "" {
+		return normalizeProfile(f.ProfileOverride)
+	}
+	if p := os.Getenv("BB_PROFILE"); p != "" {
+		return normalizeProfile(p)
+	}
+	if host, err := f.host(cfg); err == nil && host.Profile != "" {
+		return normalizeProfile(host.Profile)
+	}
+	return normalizeProfile(cfg.Get("profile"))
+}
+
+// normalizeProfile maps the explicit "default" name to the default profile
+func normalizeProfile(name string) string {
+	if name == "default" {
+		return ""
+	}
+	return name
+}
+
+// Host resolves the Bitbucket host to talk to: --host, then BB_HOST, then the
+// host config setting, defaulting to bitbucket.org.
+func (f *Factory) Host() (config.Host, error) {
+	cfg, err := f.baseConfig()
+	if err != nil {
+		return config.Host{}, err
+	}
+	return f.host(cfg)
+}
+
+func (f *Factory) host(cfg *config.Config) (config.Host, error) {
+	name := f.HostOverride
+	if name == "" {
+		name = os.Getenv("BB_HOST")
+	}
+	if name == "" {
+		name = cfg.GetOrDefault("host")
+	}
 
-	return LoadCredentialsFromStore(store)
+	return cfg.LookupHost(name)
 }
@@ -0,0 +1,318 @@
+package cmdutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ghoseb/bb/internal/config"
+	"github.com/ghoseb/bb/pkg/iostreams"
+)
+
+func TestConfigLayering(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+
+	f := NewFactory("test", iostreams.System())
+	f.GitClient.Dir = t.TempDir()
+	if _, err := f.GitClient.Run(context.Background(), "init", "--quiet"); err != nil {
+		t.Fatalf("git init: %v", err)
+	}
+
+	project := "target_branch: develop\nreviewers:\n  - \"{a}\"\n  - \"{b}\"\ngit_protocol: ssh\neditor: nano\n"
+	if err := os.WriteFile(filepath.Join(f.GitClient.Dir, ".bb.yml"), []byte(project), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	user := config.New(config.DefaultPath())
+	user.Set("git_protocol", "https")
+	user.Set("editor", "vim")
+	if err := user.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	local := config.New(config.LocalPath(filepath.Join(f.GitClient.Dir, ".git")))
+	local.Set("editor", "emacs")
+	if err := local.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := f.Config()
+	if err != nil {
+		t.Fatalf("Config: %v", err)
+	}
+
+	if got := cfg.TargetBranch(); got != "develop" {
+		t.Errorf("target_branch = %q, want project value %q", got, "develop")
+	}
+	if got := cfg.Reviewers(); len(got) != 2 || got[0] != "{a}" || got[1] != "{b}" {
+		t.Errorf("reviewers = %v", got)
+	}
+	if got := cfg.GitProtocol(); got != "https" {
+		t.Errorf("git_protocol = %q, want user override %q", got, "https")
+	}
+	if got := cfg.Editor(); got != "emacs" {
+		t.Errorf("editor = %q, want local override %q", got, "emacs")
+	}
+}
+
+func TestProjectConfigIgnoresUnsafeKeys(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+
+	errOut := &bytes.Buffer{}
+	f := NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: &bytes.Buffer{}, ErrOut: errOut})
+	f.GitClient.Dir = t.TempDir()
+	if _, err := f.GitClient.Run(context.Background(), "init", "--quiet"); err != nil {
+		t.Fatalf("git init: %v", err)
+	}
+
+	project := `target_branch: develop
+hosts:
+  bitbucket.org:
+    api_url: http://127.0.0.1:18765/api
+aliases:
+  co: "!echo PWNED"
+editor: "sh -c 'echo PWNED'"
+profile: work
+review:
+  list:
+    state: MERGED
+`
+	if err := os.WriteFile(filepath.Join(f.GitClient.Dir, ".bb.yml"), []byte(project), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := f.Config()
+	if err != nil {
+		t.Fatalf("Config: %v", err)
+	}
+	if got := cfg.TargetBranch(); got != "develop" {
+		t.Errorf("target_branch = %q, want project value %q", got, "develop")
+	}
+	if host, err := f.Host(); err != nil || host.APIURL != "" {
+		t.Errorf("host = %+v, %v, want the default API", host, err)
+	}
+	if aliases := cfg.Aliases(); len(aliases) != 0 {
+		t.Errorf("aliases = %v, want none", aliases)
+	}
+	if cfg.Editor() != "" || cfg.Get("profile") != "" || cfg.Get("review.list.state") != "" {
+		t.Errorf("editor = %q, profile = %q, review.list.state = %q, want unset", cfg.Editor(), cfg.Get("profile"), cfg.Get("review.list.state"))
+	}
+	if warning := errOut.String(); !strings.Contains(warning, "ignoring aliases.co, editor, hosts.bitbucket.org.api_url, profile, review.list.state") {
+		t.Errorf("warning = %q", warning)
+	}
+}
+
+func TestApplyConfigDefaults(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("BB_CONFIG_DIR", dir)
+	t.Setenv("BB_WORKSPACE", "acme")
+
+	cfg := config.New(config.DefaultPath())
+	cfg.Set("review.list.state", "MERGED")
+	cfg.Set("review.limit", "50")
+	cfg.Set("review.list.allow-write", "true")
+	cfg.Set("default_repo", "fallback")
+	cfg.Set("workspaces.acme.default_repo", "api")
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	f := NewFactory("test", iostreams.System())
+	f.GitClient.Dir = t.TempDir()
+
+	var repo, state string
+	var limit int
+	var allowWrite bool
+	root := &cobra.Command{Use: "bbc"}
+	review := &cobra.Command{Use: "review"}
+	list := &cobra.Command{Use: "list", Run: func(*cobra.Command, []string) {}}
+	list.Flags().StringVar(&repo, "repo", "", "")
+	list.Flags().StringVar(&state, "state", "OPEN", "")
+	list.Flags().IntVar(&limit, "limit", 20, "")
+	list.Flags().BoolVar(&allowWrite, "allow-write", false, "")
+	root.AddCommand(review)
+	review.AddCommand(list)
+
+	if err := list.ParseFlags([]string{"--limit", "5"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.ApplyConfigDefaults(list); err != nil {
+		t.Fatalf("ApplyConfigDefaults: %v", err)
+	}
+
+	if state != "MERGED" {
+		t.Errorf("state = %q, want review.list.state %q", state, "MERGED")
+	}
+	if limit != 5 {
+		t.Errorf("limit = %d, want explicit flag value 5", limit)
+	}
+	if repo != "api" {
+		t.Errorf("repo = %q, want workspace default %q", repo, "api")
+	}
+	if allowWrite {
+		t.Error("allow-write set from review.list.allow-write, which is not a flag default option")
+	}
+}
+
+func TestApplyConfigDefaults_RepoRequired(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv("BB_WORKSPACE", "")
+
+	// Not a terminal, so no repository picker
+	f := NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}})
+	f.GitClient.Dir = t.TempDir()
+
+	root := &cobra.Command{Use: "bbc"}
+	view := &cobra.Command{Use: "view", Run: func(*cobra.Command, []string) {}}
+	view.Flags().String("repo", "", "")
+	root.AddCommand(view)
+
+	var verr *ValidationError
+	if err := f.ApplyConfigDefaults(view); !errors.As(err, &verr) || verr.Field != "repo" {
+		t.Errorf("got %v, want repo ValidationError", err)
+	}
+}
+
+func TestConfigProfileOverlay(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv("BB_PROFILE", "")
+
+	cfg := config.New(config.DefaultPath())
+	cfg.Set("default_workspace", "personal")
+	cfg.Set("format", "markdown")
+	cfg.Set("profiles.work.default_workspace", "acme")
+	cfg.Set("profiles.work.format", "json")
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	f := NewFactory("test", iostreams.System())
+	f.GitClient.Dir = t.TempDir()
+
+	got, err := f.Config()
+	if err != nil {
+		t.Fatalf("Config: %v", err)
+	}
+	if ws := got.Workspace(); ws != "personal" {
+		t.Errorf("default profile workspace = %q, want %q", ws, "personal")
+	}
+
+	f.ProfileOverride = "work"
+	got, err = f.Config()
+	if err != nil {
+		t.Fatalf("Config: %v", err)
+	}
+	if ws := got.Workspace(); ws != "acme" {
+		t.Errorf("work profile workspace = %q, want %q", ws, "acme")
+	}
+	if out := got.Format(); out != "json" {
+		t.Errorf("work profile format = %q, want %q", out, "json")
+	}
+	if profile, _ := f.Profile(); profile != "work" {
+		t.Errorf("Profile() = %q, want %q", profile, "work")
+	}
+}
+
+func TestApplyConfigDefaults_FormatJSON(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+
+	cfg := config.New(config.DefaultPath())
+	cfg.Set("format", "json")
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	f := NewFactory("test", iostreams.System())
+	f.GitClient.Dir = t.TempDir()
+
+	var asJSON bool
+	root := &cobra.Command{Use: "bbc"}
+	status := &cobra.Command{Use: "status", Run: func(*cobra.Command, []string) {}}
+	status.Flags().BoolVar(&asJSON, "json", false, "")
+	root.AddCommand(status)
+
+	if err := f.ApplyConfigDefaults(status); err != nil {
+		t.Fatalf("ApplyConfigDefaults: %v", err)
+	}
+	if !asJSON {
+		t.Error("format: json should enable --json")
+	}
+
+	asJSON = false
+	if err := status.ParseFlags([]string{"--json=false"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.ApplyConfigDefaults(status); err != nil {
+		t.Fatalf("ApplyConfigDefaults: %v", err)
+	}
+	if asJSON {
+		t.Error("explicit --json=false should win over format: json")
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	f := NewFactory("test", &iostreams.IOStreams{In: io.NopCloser(&bytes.Buffer{}), Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}})
+
+	// Without a terminal only --yes confirms
+	if err := f.Confirm("Decline 2 PRs?"); err == nil || !strings.Contains(err.Error(), "--yes") {
+		t.Errorf("Confirm without a terminal = %v, want a hint to pass --yes", err)
+	}
+	f.Yes = true
+	if err := f.Confirm("Decline 2 PRs?"); err != nil {
+		t.Errorf("Confirm with --yes = %v", err)
+	}
+}
+
+func TestNewBBCloudClientShared(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	t.Setenv("BB_WORKSPACE", "acme")
+	t.Setenv("BB_USERNAME", "u")
+	t.Setenv("BB_TOKEN", "t")
+
+	f := NewFactory("test", iostreams.System())
+	first, err := f.NewBBCloudClient("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := f.NewBBCloudClient(""); again != first {
+		t.Error("second client for the same workspace was not shared")
+	}
+	if other, _ := f.NewBBCloudClient("other"); other == first || other.Workspace() != "other" {
+		t.Errorf("client for another workspace = %p (%s), want a new one", other, other.Workspace())
+	}
+}
+
+func TestConcurrency(t *testing.T) {
+	t.Setenv("BB_CONFIG_DIR", t.TempDir())
+	f := NewFactory("test", iostreams.System())
+	if got := f.Concurrency(); got != 5 {
+		t.Errorf("default concurrency = %d, want 5", got)
+	}
+
+	cfg, err := f.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Set("concurrency", "8")
+	if got := f.Concurrency(); got != 8 {
+		t.Errorf("configured concurrency = %d, want 8", got)
+	}
+	f.ConcurrencyOverride = 3
+	if got := f.Concurrency(); got != 3 {
+		t.Errorf("--concurrency 3 gave %d", got)
+	}
+}
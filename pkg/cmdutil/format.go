@@ -0,0 +1,20 @@
+package cmdutil
+
+// Output formats selectable with the format setting
+const (
+	FormatMarkdown = "markdown"
+	FormatJSON     = "json"
+	FormatTable    = "table"
+)
+
+// Format returns the configured output format for human-readable output.
+// Commands with a --json flag get it set from the format setting by
+// ApplyConfigDefaults, so this only matters when choosing between markdown
+// and table; commands without a table view render markdown.
+func (f *Factory) Format() string {
+	cfg, err := f.Config()
+	if err != nil {
+		return FormatMarkdown
+	}
+	return cfg.Format()
+}
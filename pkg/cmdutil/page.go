@@ -0,0 +1,75 @@
+package cmdutil
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// maxPerPage is the largest page Bitbucket Cloud serves.
+const maxPerPage = 100
+
+// PageFlags holds the --page, --per-page and --all flags shared by list
+// commands. Without --all a list command fetches a single page.
+type PageFlags struct {
+	Page    int
+	PerPage int
+	All     bool
+}
+
+// AddPageFlags registers the pagination flags on cmd with the given default
+// page size.
+func AddPageFlags(cmd *cobra.Command, p *PageFlags, perPage int) {
+	cmd.Flags().IntVar(&p.Page, "page", 1, "Page of results to fetch")
+	cmd.Flags().IntVar(&p.PerPage, "per-page", perPage, fmt.Sprintf("Results per page (max %d)", maxPerPage))
+	cmd.Flags().BoolVar(&p.All, "all", false, "Fetch every page")
+	cmd.MarkFlagsMutuallyExclusive("page", "all")
+}
+
+// Validate checks the page number and size.
+func (p *PageFlags) Validate() error {
+	if p.Page < 1 {
+		return fmt.Errorf("--page must be at least 1")
+	}
+	if p.PerPage < 1 || p.PerPage > maxPerPage {
+		return fmt.Errorf("--per-page must be between 1 and %d", maxPerPage)
+	}
+	return nil
+}
+
+// PageInfo locates a list command's results for JSON output; embed it in
+// the output struct. Both fields are omitted when every page was fetched.
+type PageInfo struct {
+	Page     int `json:"page,omitempty"`
+	NextPage int `json:"next_page,omitempty"`
+}
+
+// FetchPages fetches the page selected by p, or every page with --all.
+// fetch returns one page and the number of the next page, 0 on the last.
+func FetchPages[T any](p *PageFlags, fetch func(page, perPage int) ([]T, int, error)) ([]T, PageInfo, error) {
+	if !p.All {
+		values, next, err := fetch(p.Page, p.PerPage)
+		return values, PageInfo{Page: p.Page, NextPage: next}, err
+	}
+
+	var all []T
+	for page := 1; page != 0; {
+		values, next, err := fetch(page, maxPerPage)
+		if err != nil {
+			return nil, PageInfo{}, err
+		}
+		all = append(all, values...)
+		page = next
+	}
+	return all, PageInfo{}, nil
+}
+
+// WriteMoreHint tells the user how to fetch the rest of the results when
+// next, the number of the following page, is not 0.
+func WriteMoreHint(w io.Writer, next int) {
+	if next == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "More results available: use --page %d, or --all to fetch everything\n", next)
+}
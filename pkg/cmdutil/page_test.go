@@ -0,0 +1,68 @@
+package cmdutil
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestPageFlags(t *testing.T) {
+	var p PageFlags
+	cmd := &cobra.Command{Use: "test", RunE: func(*cobra.Command, []string) error { return p.Validate() }}
+	AddPageFlags(cmd, &p, 30)
+
+	if err := cmd.ParseFlags(nil); err != nil {
+		t.Fatal(err)
+	}
+	if p.Page != 1 || p.PerPage != 30 || p.All {
+		t.Errorf("defaults = %+v, want page 1 of 30", p)
+	}
+
+	for _, args := range [][]string{
+		{"--page", "0"},
+		{"--per-page", "101"},
+		{"--page", "2", "--all"},
+	} {
+		p = PageFlags{}
+		cmd.SetArgs(args)
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		if err := cmd.Execute(); err == nil {
+			t.Errorf("%v accepted, want error", args)
+		}
+	}
+}
+
+func TestFetchPages(t *testing.T) {
+	// Three pages of two values each
+	fetch := func(page, perPage int) ([]int, int, error) {
+		next := page + 1
+		if page == 3 {
+			next = 0
+		}
+		return []int{page*10 + 1, page*10 + 2}, next, nil
+	}
+
+	values, info, err := FetchPages(&PageFlags{Page: 2, PerPage: 2}, fetch)
+	if err != nil || len(values) != 2 || values[0] != 21 || info != (PageInfo{Page: 2, NextPage: 3}) {
+		t.Errorf("page 2 = %v, %+v, %v", values, info, err)
+	}
+
+	values, info, err = FetchPages(&PageFlags{Page: 1, PerPage: 2, All: true}, fetch)
+	if err != nil || len(values) != 6 || values[5] != 32 || info != (PageInfo{}) {
+		t.Errorf("all = %v, %+v, %v", values, info, err)
+	}
+}
+
+func TestWriteMoreHint(t *testing.T) {
+	var buf bytes.Buffer
+	WriteMoreHint(&buf, 0)
+	if buf.Len() != 0 {
+		t.Errorf("hint on last page: %q", buf.String())
+	}
+	WriteMoreHint(&buf, 3)
+	if got := buf.String(); got != "More results available: use --page 3, or --all to fetch everything\n" {
+		t.Errorf("hint = %q", got)
+	}
+}
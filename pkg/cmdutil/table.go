@@ -0,0 +1,52 @@
+package cmdutil
+
+import "github.com/ghoseb/bb/pkg/text"
+
+// TablePadding is the space between columns of the tabwriter tables the
+// table format renders
+const TablePadding = 2
+
+// FitColumn returns how wide column col of rows may be for the table to fit
+// in width columns, given the widest cell of every other column, and never
+// less than minWidth. Cells are measured in terminal columns.
+func FitColumn(width int, rows [][]string, col, minWidth int) int {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], text.Width(cell))
+		}
+	}
+
+	rest := 0
+	for i, w := range widths {
+		if i == col {
+			continue
+		}
+		rest += w
+	}
+	// Every column but the last is followed by padding
+	if len(widths) > 1 {
+		rest += TablePadding * (len(widths) - 1)
+	}
+	return max(width-rest, minWidth)
+}
+
+// Truncate shortens s to at most n terminal columns, marking the cut with an
+// ellipsis. Wide characters are never split.
+func Truncate(s string, n int) string {
+	if text.Width(s) <= n {
+		return s
+	}
+	w := 0
+	for i, r := range s {
+		rw := text.RuneWidth(r)
+		if w+rw > n-1 {
+			return s[:i] + "…"
+		}
+		w += rw
+	}
+	return s
+}
@@ -0,0 +1,32 @@
+package cmdutil
+
+import "testing"
+
+func TestFitColumn(t *testing.T) {
+	rows := [][]string{
+		{"PR", "TITLE", "AUTHOR"},
+		{"#7", "Add auth", "alice"},
+		{"#12", "A much longer title", "bob"},
+	}
+
+	// 3 (PR) + 2 + title + 2 + 6 (AUTHOR) = 80
+	if got := FitColumn(80, rows, 1, 20); got != 67 {
+		t.Errorf("FitColumn(80) = %d, want 67", got)
+	}
+	if got := FitColumn(30, rows, 1, 20); got != 20 {
+		t.Errorf("FitColumn(30) = %d, want the minimum 20", got)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := Truncate("short", 10); got != "short" {
+		t.Errorf("got %q", got)
+	}
+	if got := Truncate("héllo wörld", 6); got != "héllo…" {
+		t.Errorf("got %q, want %q", got, "héllo…")
+	}
+	// Wide characters take two columns and are not split
+	if got := Truncate("修复登录问题", 6); got != "修复…" {
+		t.Errorf("got %q, want %q", got, "修复…")
+	}
+}
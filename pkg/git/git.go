@@ -0,0 +1,330 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ErrNotOnBranch indicates HEAD is detached and no branch is checked out.
+var ErrNotOnBranch = errors.New("git: not on any branch")
+
+// ErrConflict indicates a merge or rebase stopped on conflicting changes.
+var ErrConflict = errors.New("git: conflicts")
+
+// ErrDiverged indicates a local branch has commits that a fast-forward to
+// the requested commit would lose.
+var ErrDiverged = errors.New("git: branch has diverged")
+
+// ErrNotRepository indicates the working directory is not inside a git repository.
+var ErrNotRepository = errors.New("git: not a git repository")
+
+// Client runs git commands against a local checkout.
+type Client struct {
+	// Dir is the working directory for git commands (defaults to the current directory)
+	Dir string
+
+	// GitPath is the git executable to run (defaults to "git")
+	GitPath string
+}
+
+// New creates a git client rooted at dir.
+func New(dir string) *Client {
+	return &Client{Dir: dir}
+}
+
+// Run executes a git subcommand and returns its trimmed standard output.
+func (c *Client) Run(ctx context.Context, args ...string) (string, error) {
+	return c.run(ctx, nil, args...)
+}
+
+// run executes git with extra environment variables appended to the process environment.
+func (c *Client) run(ctx context.Context, env []string, args ...string) (string, error) {
+	gitPath := c.GitPath
+	if gitPath == "" {
+		gitPath = "git"
+	}
+
+	cmd := exec.CommandContext(ctx, gitPath, args...)
+	cmd.Dir = c.Dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if strings.Contains(msg, "not a git repository") {
+			return "", ErrNotRepository
+		}
+		if msg == "" {
+			return "", fmt.Errorf("git %s: %w", args[0], err)
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
+
+// CurrentBranch returns the name of the checked-out branch.
+func (c *Client) CurrentBranch(ctx context.Context) (string, error) {
+	ref, err := c.Run(ctx, "symbolic-ref", "--quiet", "HEAD")
+	if err != nil {
+		if errors.Is(err, ErrNotRepository) {
+			return "", err
+		}
+		return "", ErrNotOnBranch
+	}
+	return strings.TrimPrefix(ref, "refs/heads/"), nil
+}
+
+// CommonDir returns the absolute path of the repository's shared git directory.
+// Linked worktrees resolve to the main repository's directory.
+func (c *Client) CommonDir(ctx context.Context) (string, error) {
+	return c.Run(ctx, "rev-parse", "--path-format=absolute", "--git-common-dir")
+}
+
+// TopLevel returns the absolute path of the working tree root.
+func (c *Client) TopLevel(ctx context.Context) (string, error) {
+	return c.Run(ctx, "rev-parse", "--show-toplevel")
+}
+
+// Remote is a configured remote and its fetch URL.
+type Remote struct {
+	Name string
+	URL  string
+}
+
+// Remotes returns the repository's remotes in the order git lists them.
+func (c *Client) Remotes(ctx context.Context) ([]Remote, error) {
+	out, err := c.Run(ctx, "remote", "-v")
+	if err != nil {
+		return nil, err
+	}
+	var remotes []Remote
+	for _, line := range splitLines(out) {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[2] == "(fetch)" {
+			remotes = append(remotes, Remote{Name: fields[0], URL: fields[1]})
+		}
+	}
+	return remotes, nil
+}
+
+// Fetch fetches refspec from remote (a remote name or URL) and returns the fetched commit.
+func (c *Client) Fetch(ctx context.Context, remote, refspec string) (string, error) {
+	if _, err := c.Run(ctx, "fetch", "--quiet", remote, refspec); err != nil {
+		return "", err
+	}
+	return c.Run(ctx, "rev-parse", "FETCH_HEAD")
+}
+
+// HasLocalBranch reports whether a local branch named branch exists.
+func (c *Client) HasLocalBranch(ctx context.Context, branch string) bool {
+	_, err := c.Run(ctx, "show-ref", "--verify", "--quiet", "refs/heads/"+branch)
+	return err == nil
+}
+
+// CheckoutBranch switches to an existing local branch and fast-forwards it to commit.
+func (c *Client) CheckoutBranch(ctx context.Context, branch, commit string) error {
+	if _, err := c.Run(ctx, "checkout", "--quiet", branch); err != nil {
+		return err
+	}
+	_, err := c.Run(ctx, "merge", "--quiet", "--ff-only", commit)
+	return err
+}
+
+// CheckoutNewBranch creates branch at commit and switches to it.
+func (c *Client) CheckoutNewBranch(ctx context.Context, branch, commit string) error {
+	_, err := c.Run(ctx, "checkout", "--quiet", "-b", branch, commit)
+	return err
+}
+
+// AddWorktree creates a worktree at dir with branch checked out at commit. An
+// existing branch is fast-forwarded to commit first; when it has diverged the
+// error wraps ErrDiverged and nothing is changed.
+func (c *Client) AddWorktree(ctx context.Context, dir, branch, commit string) error {
+	args := []string{"worktree", "add", "--quiet"}
+	if c.HasLocalBranch(ctx, branch) {
+		if !c.IsAncestor(ctx, "refs/heads/"+branch, commit) {
+			return fmt.Errorf("%w: %s cannot be fast-forwarded to %s", ErrDiverged, branch, commit)
+		}
+		if _, err := c.Run(ctx, "branch", "--quiet", "--force", branch, commit); err != nil {
+			return err
+		}
+		args = append(args, dir, branch)
+	} else {
+		args = append(args, "-b", branch, dir, commit)
+	}
+	_, err := c.Run(ctx, args...)
+	return err
+}
+
+// BranchConfig returns the branch.<branch>.<key> setting, or "" when unset.
+func (c *Client) BranchConfig(ctx context.Context, branch, key string) string {
+	value, err := c.Run(ctx, "config", "--get", "branch."+branch+"."+key)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+// SetBranchConfig sets branch.<branch>.<key> to value.
+func (c *Client) SetBranchConfig(ctx context.Context, branch, key, value string) error {
+	_, err := c.Run(ctx, "config", "branch."+branch+"."+key, value)
+	return err
+}
+
+// AddDetachedWorktree creates a worktree at dir with commit checked out and no
+// branch, for work that must not touch the current checkout.
+func (c *Client) AddDetachedWorktree(ctx context.Context, dir, commit string) error {
+	_, err := c.Run(ctx, "worktree", "add", "--quiet", "--detach", dir, commit)
+	return err
+}
+
+// RemoveWorktree deletes the worktree at dir, discarding any changes in it.
+func (c *Client) RemoveWorktree(ctx context.Context, dir string) error {
+	_, err := c.Run(ctx, "worktree", "remove", "--force", dir)
+	return err
+}
+
+// IsAncestor reports whether ancestor is reachable from commit.
+func (c *Client) IsAncestor(ctx context.Context, ancestor, commit string) bool {
+	_, err := c.Run(ctx, "merge-base", "--is-ancestor", ancestor, commit)
+	return err == nil
+}
+
+// Merge merges commit into HEAD with message. On a conflict the merge is
+// aborted and the error wraps ErrConflict, naming the conflicting files.
+func (c *Client) Merge(ctx context.Context, commit, message string) error {
+	if _, err := c.Run(ctx, "merge", "--quiet", "--no-ff", "--no-edit", "-m", message, commit); err != nil {
+		return c.abortOnConflict(ctx, err, "merge")
+	}
+	return nil
+}
+
+// Rebase replays HEAD's commits onto commit. On a conflict the rebase is
+// aborted and the error wraps ErrConflict, naming the conflicting files.
+func (c *Client) Rebase(ctx context.Context, onto string) error {
+	if _, err := c.Run(ctx, "rebase", "--quiet", onto); err != nil {
+		return c.abortOnConflict(ctx, err, "rebase")
+	}
+	return nil
+}
+
+// abortOnConflict aborts a failed merge or rebase, reporting the conflicting
+// files when there are any
+func (c *Client) abortOnConflict(ctx context.Context, err error, op string) error {
+	files, _ := c.Run(ctx, "diff", "--name-only", "--diff-filter=U")
+	_, _ = c.Run(ctx, op, "--abort")
+	if conflicts := splitLines(files); len(conflicts) > 0 {
+		return fmt.Errorf("%w in %s", ErrConflict, strings.Join(conflicts, ", "))
+	}
+	return err
+}
+
+// Push pushes commit to branch on remote. A non-empty lease is the commit the
+// remote branch must still point at, allowing a forced push (--force-with-lease).
+func (c *Client) Push(ctx context.Context, remote, commit, branch, lease string) error {
+	args := []string{"push", "--quiet"}
+	if lease != "" {
+		args = append(args, "--force-with-lease=refs/heads/"+branch+":"+lease)
+	}
+	args = append(args, remote, commit+":refs/heads/"+branch)
+	_, err := c.Run(ctx, args...)
+	return err
+}
+
+// FileChange describes a path that differs between two trees.
+type FileChange struct {
+	Status string // added, modified, deleted, renamed, untracked, ...
+	Path   string
+}
+
+// HasCommit reports whether commit exists in the local object database.
+func (c *Client) HasCommit(ctx context.Context, commit string) bool {
+	_, err := c.Run(ctx, "cat-file", "-e", commit+"^{commit}")
+	return err == nil
+}
+
+// HeadCommit returns the full hash of HEAD.
+func (c *Client) HeadCommit(ctx context.Context) (string, error) {
+	return c.Run(ctx, "rev-parse", "HEAD")
+}
+
+// DiffWorkingTree lists tracked files in the working tree that differ from commit,
+// followed by untracked files that are not ignored.
+func (c *Client) DiffWorkingTree(ctx context.Context, commit string) ([]FileChange, error) {
+	out, err := c.Run(ctx, "diff", "--name-status", "--no-renames", commit)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []FileChange
+	for _, line := range splitLines(out) {
+		status, path, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		changes = append(changes, FileChange{Status: statusName(status), Path: path})
+	}
+
+	untracked, err := c.Run(ctx, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range splitLines(untracked) {
+		changes = append(changes, FileChange{Status: "untracked", Path: path})
+	}
+
+	return changes, nil
+}
+
+func statusName(code string) string {
+	switch code[:1] {
+	case "A":
+		return "added"
+	case "M":
+		return "modified"
+	case "D":
+		return "deleted"
+	case "R":
+		return "renamed"
+	case "C":
+		return "copied"
+	case "T":
+		return "type_changed"
+	default:
+		return code
+	}
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// Clone clones url into dir. When header is non-empty it is sent as an extra HTTP
+// header for the clone only; it is passed via the environment so it never
+// appears in the process list or the cloned repository's config.
+func (c *Client) Clone(ctx context.Context, url, dir, header string) error {
+	var env []string
+	if header != "" {
+		env = []string{
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=" + header,
+		}
+	}
+	_, err := c.run(ctx, env, "clone", "--quiet", url, dir)
+	return err
+}
@@ -0,0 +1,279 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initRepo creates an empty repository with one commit on branch main
+func initRepo(t *testing.T) *Client {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	c := New(t.TempDir())
+	ctx := context.Background()
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch=main"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "--allow-empty", "-m", "init"},
+	} {
+		if _, err := c.Run(ctx, args...); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+	return c
+}
+
+func TestCurrentBranch(t *testing.T) {
+	c := initRepo(t)
+
+	branch, err := c.CurrentBranch(context.Background())
+	if err != nil {
+		t.Fatalf("CurrentBranch: %v", err)
+	}
+	if branch != "main" {
+		t.Errorf("got %q, want %q", branch, "main")
+	}
+}
+
+func TestCurrentBranch_Detached(t *testing.T) {
+	c := initRepo(t)
+	ctx := context.Background()
+
+	if _, err := c.Run(ctx, "checkout", "--quiet", "--detach"); err != nil {
+		t.Fatalf("detach: %v", err)
+	}
+
+	_, err := c.CurrentBranch(ctx)
+	if !errors.Is(err, ErrNotOnBranch) {
+		t.Errorf("got %v, want ErrNotOnBranch", err)
+	}
+}
+
+func TestCurrentBranch_NotRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	c := New(t.TempDir())
+
+	_, err := c.CurrentBranch(context.Background())
+	if !errors.Is(err, ErrNotRepository) {
+		t.Errorf("got %v, want ErrNotRepository", err)
+	}
+}
+
+func TestAddWorktree(t *testing.T) {
+	c := initRepo(t)
+	ctx := context.Background()
+
+	head, err := c.Run(ctx, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatalf("rev-parse: %v", err)
+	}
+
+	dir := t.TempDir() + "/wt"
+	if err := c.AddWorktree(ctx, dir, "feature", head); err != nil {
+		t.Fatalf("AddWorktree: %v", err)
+	}
+	if !c.HasLocalBranch(ctx, "feature") {
+		t.Error("expected branch 'feature' to be created")
+	}
+
+	branch, err := New(dir).CurrentBranch(ctx)
+	if err != nil {
+		t.Fatalf("CurrentBranch in worktree: %v", err)
+	}
+	if branch != "feature" {
+		t.Errorf("worktree branch = %q, want %q", branch, "feature")
+	}
+}
+
+func TestDiffWorkingTree(t *testing.T) {
+	c := initRepo(t)
+	ctx := context.Background()
+
+	head, err := c.HeadCommit(ctx)
+	if err != nil {
+		t.Fatalf("HeadCommit: %v", err)
+	}
+
+	changes, err := c.DiffWorkingTree(ctx, head)
+	if err != nil {
+		t.Fatalf("DiffWorkingTree: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Fatalf("expected clean tree, got %v", changes)
+	}
+
+	if err := os.WriteFile(filepath.Join(c.Dir, "new.txt"), []byte("x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err = c.DiffWorkingTree(ctx, head)
+	if err != nil {
+		t.Fatalf("DiffWorkingTree: %v", err)
+	}
+	want := []FileChange{{Status: "untracked", Path: "new.txt"}}
+	if len(changes) != 1 || changes[0] != want[0] {
+		t.Errorf("got %v, want %v", changes, want)
+	}
+}
+
+func TestRemotes(t *testing.T) {
+	c := initRepo(t)
+	ctx := context.Background()
+	for _, args := range [][]string{
+		{"remote", "add", "origin", "git@bitbucket.org:acme/api.git"},
+		{"remote", "add", "fork", "https://bitbucket.org/alice/api.git"},
+	} {
+		if _, err := c.Run(ctx, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	remotes, err := c.Remotes(ctx)
+	if err != nil {
+		t.Fatalf("Remotes: %v", err)
+	}
+	want := []Remote{{"fork", "https://bitbucket.org/alice/api.git"}, {"origin", "git@bitbucket.org:acme/api.git"}}
+	if len(remotes) != len(want) || remotes[0] != want[0] || remotes[1] != want[1] {
+		t.Errorf("Remotes = %v, want %v", remotes, want)
+	}
+}
+
+func TestMergeRebasePush(t *testing.T) {
+	c := initRepo(t)
+	ctx := context.Background()
+	run := func(c *Client, args ...string) string {
+		t.Helper()
+		out, err := c.Run(ctx, args...)
+		if err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+		return out
+	}
+	commit := func(c *Client, file, content string) string {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(c.Dir, file), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		run(c, "add", file)
+		run(c, "commit", "--quiet", "-m", file)
+		return run(c, "rev-parse", "HEAD")
+	}
+	run(c, "config", "user.name", "test")
+	run(c, "config", "user.email", "test@example.com")
+
+	run(c, "checkout", "--quiet", "-b", "feature")
+	feature := commit(c, "feature.txt", "feature")
+	run(c, "checkout", "--quiet", "main")
+	main := commit(c, "main.txt", "main")
+	if c.IsAncestor(ctx, main, feature) || !c.IsAncestor(ctx, run(c, "rev-parse", "main~1"), feature) {
+		t.Fatal("IsAncestor got the history wrong")
+	}
+
+	// Merge in a detached worktree and push the result to a bare remote
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	run(c, "init", "--quiet", "--bare", remote)
+	run(c, "push", "--quiet", remote, "feature")
+
+	dir := filepath.Join(t.TempDir(), "wt")
+	if err := c.AddDetachedWorktree(ctx, dir, feature); err != nil {
+		t.Fatal(err)
+	}
+	wt := New(dir)
+	if err := wt.Merge(ctx, main, "Merge main into feature"); err != nil {
+		t.Fatal(err)
+	}
+	merged := run(wt, "rev-parse", "HEAD")
+	if !wt.IsAncestor(ctx, main, merged) || !wt.IsAncestor(ctx, feature, merged) {
+		t.Error("merge commit does not contain both branches")
+	}
+	if err := wt.Push(ctx, remote, merged, "feature", ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := run(New(remote), "rev-parse", "feature"); got != merged {
+		t.Errorf("remote feature = %s, want %s", got, merged)
+	}
+
+	// A rebase rewrites history, so it needs the lease to push
+	run(wt, "checkout", "--quiet", "--detach", feature)
+	if err := wt.Rebase(ctx, main); err != nil {
+		t.Fatal(err)
+	}
+	rebased := run(wt, "rev-parse", "HEAD")
+	if err := wt.Push(ctx, remote, rebased, "feature", feature); err == nil {
+		t.Error("push with a stale lease succeeded")
+	}
+	if err := wt.Push(ctx, remote, rebased, "feature", merged); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RemoveWorktree(ctx, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Conflicting changes abort cleanly
+	run(c, "checkout", "--quiet", "feature")
+	commit(c, "main.txt", "other")
+	err := c.Merge(ctx, main, "Merge main into feature")
+	if !errors.Is(err, ErrConflict) || !strings.Contains(err.Error(), "main.txt") {
+		t.Errorf("conflicting merge = %v", err)
+	}
+	if _, err := c.Run(ctx, "rev-parse", "--verify", "--quiet", "MERGE_HEAD"); err == nil {
+		t.Error("merge left in progress")
+	}
+}
+
+func TestAddWorktreeExistingBranch(t *testing.T) {
+	c := initRepo(t)
+	ctx := context.Background()
+	commit := func(msg string) string {
+		t.Helper()
+		if _, err := c.Run(ctx, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "--allow-empty", "-m", msg); err != nil {
+			t.Fatalf("commit: %v", err)
+		}
+		head, err := c.HeadCommit(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return head
+	}
+
+	// feature sits at init; main moves on to the fetched PR head
+	if _, err := c.Run(ctx, "branch", "feature"); err != nil {
+		t.Fatal(err)
+	}
+	prHead := commit("pr")
+
+	dir := t.TempDir() + "/wt"
+	if err := c.AddWorktree(ctx, dir, "feature", prHead); err != nil {
+		t.Fatalf("AddWorktree: %v", err)
+	}
+	if head, _ := New(dir).HeadCommit(ctx); head != prHead {
+		t.Errorf("worktree HEAD = %s, want the PR head %s", head, prHead)
+	}
+	if err := c.RemoveWorktree(ctx, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// A branch with commits of its own is not moved
+	if _, err := c.Run(ctx, "checkout", "--quiet", "-b", "local"); err != nil {
+		t.Fatal(err)
+	}
+	local := commit("local work")
+	if _, err := c.Run(ctx, "checkout", "--quiet", "main"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddWorktree(ctx, t.TempDir()+"/wt2", "local", prHead); !errors.Is(err, ErrDiverged) {
+		t.Errorf("AddWorktree on a diverged branch: err = %v, want ErrDiverged", err)
+	}
+	if head, _ := c.Run(ctx, "rev-parse", "local"); head != local {
+		t.Errorf("diverged branch moved to %s", head)
+	}
+}
@@ -0,0 +1,163 @@
+package httpx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditLog appends a record of every successful request that changes
+// something (anything but GET and HEAD) to a JSON Lines file, so users can
+// review what they and their automation have done.
+type AuditLog struct {
+	path    string
+	command string
+
+	mu sync.Mutex
+}
+
+// AuditEntry is one line of an audit log
+type AuditEntry struct {
+	Time time.Time `json:"time"`
+	// Command is the CLI command that made the request, e.g. "review approve"
+	Command string `json:"command,omitempty"`
+	Method  string `json:"method"`
+	URL     string `json:"url"`
+	Status  int    `json:"status"`
+	// ID and UUID identify the resource in the response, when it has them
+	ID   string `json:"id,omitempty"`
+	UUID string `json:"uuid,omitempty"`
+}
+
+// NewAuditLog returns an audit log appending to path on behalf of command
+func NewAuditLog(path, command string) *AuditLog {
+	return &AuditLog{path: path, command: command}
+}
+
+// Path returns the file the log appends to
+func (a *AuditLog) Path() string {
+	return a.path
+}
+
+// Transport returns a round tripper that logs the successful mutations sent
+// through next. A failure to write the log is reported on stderr but does not
+// fail the request, which has already taken effect.
+func (a *AuditLog) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &auditTransport{log: a, next: next}
+}
+
+type auditTransport struct {
+	log  *AuditLog
+	next http.RoundTripper
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || req.Method == http.MethodGet || req.Method == http.MethodHead ||
+		resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp, err
+	}
+
+	entry := AuditEntry{
+		Time:    time.Now().UTC(),
+		Command: t.log.command,
+		Method:  req.Method,
+		URL:     req.URL.Redacted(),
+		Status:  resp.StatusCode,
+	}
+	if strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		entry.ID, entry.UUID = responseIDs(body)
+	}
+
+	if err := t.log.Append(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+	return resp, nil
+}
+
+// responseIDs returns the id and uuid of a JSON object response
+func responseIDs(body []byte) (id, uuid string) {
+	var ids struct {
+		ID   json.RawMessage `json:"id"`
+		UUID string          `json:"uuid"`
+	}
+	if json.Unmarshal(body, &ids) != nil {
+		return "", ""
+	}
+	id = strings.Trim(string(ids.ID), `"`)
+	if id == "null" {
+		id = ""
+	}
+	return id, ids.UUID
+}
+
+// Append writes entry as one line at the end of the log
+func (a *AuditLog) Append(entry AuditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encode audit entry: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(a.path), 0o700); err != nil {
+		return fmt.Errorf("create audit log directory: %w", err)
+	}
+	file, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	return nil
+}
+
+// ReadAuditLog returns the entries of the audit log at path, oldest first. A
+// missing log has no entries; lines that do not parse are skipped.
+func ReadAuditLog(path string) ([]AuditEntry, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read audit log: %w", err)
+	}
+	return entries, nil
+}
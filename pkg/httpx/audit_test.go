@@ -0,0 +1,68 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestAuditLog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/comments":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":42,"content":{"raw":"LGTM"}}`))
+		case "/approve":
+			_, _ = w.Write([]byte(`{"approved":true,"user":{"uuid":"{u}"}}`))
+		case "/merge":
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error":{"message":"checks failed"}}`))
+		default:
+			_, _ = w.Write([]byte(`{"uuid":"{repo}"}`))
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "data", "audit.jsonl")
+	client, err := New(Options{BaseURL: server.URL, Audit: NewAuditLog(path, "review comment")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(method, p string, v any) error {
+		req, _ := client.NewRequest(context.Background(), method, p, nil)
+		return client.Do(req, v)
+	}
+
+	var created struct {
+		ID int `json:"id"`
+	}
+	if err := do(http.MethodPost, "/comments", &created); err != nil || created.ID != 42 {
+		t.Fatalf("POST /comments = %+v, %v; the response must still decode", created, err)
+	}
+	_ = do(http.MethodGet, "/repo", nil)
+	_ = do(http.MethodPost, "/approve", nil)
+	if err := do(http.MethodPost, "/merge", nil); err == nil {
+		t.Fatal("POST /merge succeeded")
+	}
+
+	entries, err := ReadAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want the 2 successful mutations: %+v", len(entries), entries)
+	}
+	if e := entries[0]; e.Command != "review comment" || e.Method != "POST" || e.Status != 201 || e.ID != "42" {
+		t.Errorf("first entry = %+v", e)
+	}
+	if e := entries[1]; e.URL != server.URL+"/approve" || e.ID != "" {
+		t.Errorf("second entry = %+v", e)
+	}
+
+	if entries, err := ReadAuditLog(filepath.Join(t.TempDir(), "missing.jsonl")); err != nil || entries != nil {
+		t.Errorf("missing log = %v, %v", entries, err)
+	}
+}
@@ -0,0 +1,277 @@
+package httpx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	cassetteVersion = 1
+	redacted        = "REDACTED"
+)
+
+// Cassette records HTTP interactions to a file, or replays them from one,
+// so commands can run deterministically without network or credentials.
+//
+// Recorded interactions are sanitized: request headers are not kept, response
+// headers are limited to recordedHeaders, and the values of secret-looking
+// JSON fields and any registered secrets are replaced with REDACTED.
+type Cassette struct {
+	path   string
+	replay bool
+
+	mu      sync.Mutex
+	file    cassetteFile
+	used    []bool
+	secrets []string
+}
+
+type cassetteFile struct {
+	Version    int       `json:"version"`
+	RecordedAt time.Time `json:"recorded_at"`
+	// Workspace is the workspace the recorded commands ran against, so
+	// replays resolve the same one without credentials
+	Workspace    string        `json:"workspace,omitempty"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the part of a request replays are matched on
+type RecordedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// RecordedResponse is a sanitized response
+type RecordedResponse struct {
+	Status  int                 `json:"status"`
+	Headers map[string][]string `json:"headers,omitempty"`
+	Body    string              `json:"body,omitempty"`
+}
+
+// recordedHeaders are the response headers kept in cassettes
+var recordedHeaders = []string{"Content-Type", "ETag", "Location", "Retry-After"}
+
+// secretFieldRE matches JSON string fields whose values are redacted
+var secretFieldRE = regexp.MustCompile(`("[a-z_]*(?:token|secret|password)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// NewRecorder returns a cassette that records to path, replacing it; each
+// interaction is written as it completes
+func NewRecorder(path string) *Cassette {
+	return &Cassette{
+		path: path,
+		file: cassetteFile{Version: cassetteVersion, RecordedAt: time.Now().UTC(), Interactions: []Interaction{}},
+	}
+}
+
+// LoadCassette returns a cassette replaying the interactions recorded at path
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read cassette: %w", err)
+	}
+	c := &Cassette{path: path, replay: true}
+	if err := json.Unmarshal(data, &c.file); err != nil {
+		return nil, fmt.Errorf("parse cassette %s: %w", path, err)
+	}
+	if c.file.Version != cassetteVersion {
+		return nil, fmt.Errorf("cassette %s: unsupported version %d", path, c.file.Version)
+	}
+	c.used = make([]bool, len(c.file.Interactions))
+	return c, nil
+}
+
+// Replaying reports whether the cassette replays rather than records
+func (c *Cassette) Replaying() bool {
+	return c.replay
+}
+
+// Workspace returns the workspace recorded with the cassette
+func (c *Cassette) Workspace() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.file.Workspace
+}
+
+// SetWorkspace records the workspace the commands run against
+func (c *Cassette) SetWorkspace(ws string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file.Workspace == "" {
+		c.file.Workspace = ws
+	}
+}
+
+// Redact registers secrets, such as tokens, to scrub from recordings
+func (c *Cassette) Redact(secrets ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range secrets {
+		if s != "" {
+			c.secrets = append(c.secrets, s)
+		}
+	}
+}
+
+// Transport returns a round tripper that records through next, or replays
+// without touching the network
+func (c *Cassette) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &cassetteTransport{cassette: c, next: next}
+}
+
+type cassetteTransport struct {
+	cassette *Cassette
+	next     http.RoundTripper
+}
+
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if t.cassette.replay {
+		return t.cassette.play(req, reqBody)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	if err := t.cassette.record(req, reqBody, resp, respBody); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// readBody reads a request body, leaving it readable for the transport
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+func (c *Cassette) record(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	headers := make(map[string][]string)
+	for _, h := range recordedHeaders {
+		if v := resp.Header.Values(h); len(v) > 0 {
+			headers[h] = v
+		}
+	}
+	c.file.Interactions = append(c.file.Interactions, Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    c.sanitize(req.URL.Redacted()),
+			Body:   c.sanitize(string(reqBody)),
+		},
+		Response: RecordedResponse{
+			Status:  resp.StatusCode,
+			Headers: headers,
+			Body:    c.sanitize(string(respBody)),
+		},
+	})
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false) // keep URLs readable
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(c.file); err != nil {
+		return fmt.Errorf("encode cassette: %w", err)
+	}
+	if dir := filepath.Dir(c.path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("create cassette directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(c.path, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("write cassette: %w", err)
+	}
+	return nil
+}
+
+// sanitize redacts secret fields and registered secrets from s
+func (c *Cassette) sanitize(s string) string {
+	s = secretFieldRE.ReplaceAllString(s, `${1}"`+redacted+`"`)
+	for _, secret := range c.secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	return s
+}
+
+// play answers req with the first unused interaction recorded for the same
+// method, URL and body
+func (c *Cassette) play(req *http.Request, reqBody []byte) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	url := c.sanitize(req.URL.Redacted())
+	body := c.sanitize(string(reqBody))
+	for i, in := range c.file.Interactions {
+		if c.used[i] || in.Request.Method != req.Method || in.Request.URL != url || in.Request.Body != body {
+			continue
+		}
+		c.used[i] = true
+
+		header := make(http.Header)
+		for k, v := range in.Response.Headers {
+			header[http.CanonicalHeaderKey(k)] = v
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+			StatusCode:    in.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, &ReplayMissError{Method: req.Method, URL: url, Cassette: c.path}
+}
+
+// ReplayMissError reports a request with no recorded interaction left to replay
+type ReplayMissError struct {
+	Method   string
+	URL      string
+	Cassette string
+}
+
+func (e *ReplayMissError) Error() string {
+	return fmt.Sprintf("no recorded response for %s %s in %s", e.Method, e.URL, e.Cassette)
+}
@@ -0,0 +1,101 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		switch r.Method {
+		case http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":7,"secret":"whsec-123"}`))
+		default:
+			_, _ = w.Write([]byte(`{"message":"hello s3cret-token"}`))
+		}
+	}))
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	recorder := NewRecorder(path)
+	recorder.SetWorkspace("acme")
+	client, err := New(Options{BaseURL: server.URL, Username: "alice", Password: "s3cret-token", Cassette: recorder})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got payload
+	req, _ := client.NewRequest(context.Background(), http.MethodGet, "/api", nil)
+	if err := client.Do(req, &got); err != nil || got.Message != "hello s3cret-token" {
+		t.Fatalf("recorded GET = %+v, %v", got, err)
+	}
+	req, _ = client.NewRequest(context.Background(), http.MethodPost, "/hooks", map[string]string{"url": "https://example.com"})
+	if err := client.Do(req, nil); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leak := range []string{"s3cret-token", "whsec-123", "Authorization", "session=abc"} {
+		if strings.Contains(string(data), leak) {
+			t.Errorf("cassette contains %q:\n%s", leak, data)
+		}
+	}
+
+	// Replay with the server gone and no credentials
+	player, err := LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if player.Workspace() != "acme" {
+		t.Errorf("workspace = %q, want acme", player.Workspace())
+	}
+	client, err = New(Options{BaseURL: server.URL, Cassette: player})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ = client.NewRequest(context.Background(), http.MethodGet, "/api", nil)
+	if err := client.Do(req, &got); err != nil || got.Message != "hello REDACTED" {
+		t.Fatalf("replayed GET = %+v, %v", got, err)
+	}
+	req, _ = client.NewRequest(context.Background(), http.MethodPost, "/hooks", map[string]string{"url": "https://example.com"})
+	var created struct {
+		ID int `json:"id"`
+	}
+	if err := client.Do(req, &created); err != nil || created.ID != 7 {
+		t.Fatalf("replayed POST = %+v, %v", created, err)
+	}
+
+	// Each interaction is replayed once; a miss fails at once, without retries
+	start := time.Now()
+	req, _ = client.NewRequest(context.Background(), http.MethodGet, "/api", nil)
+	var miss *ReplayMissError
+	if err := client.Do(req, &got); !errors.As(err, &miss) {
+		t.Fatalf("second replayed GET: got %v, want ReplayMissError", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("replay miss was retried")
+	}
+}
+
+func TestCassetteSanitize(t *testing.T) {
+	c := NewRecorder("unused.json")
+	c.Redact("tok-123")
+	in := `{"access_token": "abc", "refresh_token":"d\"ef", "key":"PROJ", "note":"tok-123 here"}`
+	want := `{"access_token": "REDACTED", "refresh_token":"REDACTED", "key":"PROJ", "note":"REDACTED here"}`
+	if got := c.sanitize(in); got != want {
+		t.Errorf("sanitize =\n%s\nwant\n%s", got, want)
+	}
+}
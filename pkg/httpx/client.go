@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -21,6 +22,7 @@ type Client struct {
 	baseURL   *url.URL
 	username  string
 	password  string
+	bearer    string
 	userAgent string
 
 	httpClient *http.Client
@@ -34,7 +36,24 @@ type Client struct {
 
 	retry RetryPolicy
 
+	// rateLimitWait bounds the time one request waits for rate limits to
+	// reset; rateLimitProgress, when set, is told about each wait
+	rateLimitWait     time.Duration
+	rateLimitProgress io.Writer
+
+	// pacer, when set, spaces requests out before the rate limit runs out
+	pacer *pacer
+	// serial makes requests go one at a time while the rate limit runs low
+	serial sync.Mutex
+
+	// deadline, when set, ends every request, retries and waits included
+	// (see Options.TotalTimeout)
+	deadline     time.Time
+	totalTimeout time.Duration
+
 	debug bool
+
+	dryRun io.Writer
 }
 
 // Options configures a Client.
@@ -43,13 +62,57 @@ type Options struct {
 	Username  string
 	Password  string
 	UserAgent string
-	Timeout   time.Duration
+
+	// BearerToken, when set, authenticates with "Authorization: Bearer" instead of Basic Auth
+	BearerToken string
+	Timeout     time.Duration
 
 	EnableCache bool
 	Retry       RetryPolicy
 	Debug       bool
+
+	// Transport tunes connection reuse; clients with the default share one
+	// pool of connections
+	Transport TransportOptions
+
+	// Cassette, when set, records every exchange or replays recorded ones
+	Cassette *Cassette
+
+	// DryRun, when set, receives a description of each request that would
+	// change something (anything but GET and HEAD) instead of it being sent;
+	// such requests fail with ErrDryRun
+	DryRun io.Writer
+
+	// Audit, when set, logs every successful request that changes something
+	Audit *AuditLog
+
+	// ResponseCache, when set, keeps GET responses to serve offline or when
+	// the network fails
+	ResponseCache *ResponseCache
+
+	// RateLimitWait is the longest a request waits in total for the API's
+	// rate limit to reset before failing with a RateLimitError (default
+	// DefaultRateLimitWait); negative means never wait
+	RateLimitWait time.Duration
+
+	// RateLimitProgress, when set, receives a "rate limited until <time>"
+	// line before each wait
+	RateLimitProgress io.Writer
+
+	// Pace, when set, spaces requests out once the X-RateLimit headers say
+	// the limit is running low, so large fan-outs slow down to the rate the
+	// API sustains instead of running into 429s
+	Pace bool
+
+	// TotalTimeout, when positive, is the wall-clock budget of every request
+	// the client makes from its creation on, retries, rate limit waits and
+	// later pages included; requests past it fail with ErrTotalTimeout
+	TotalTimeout time.Duration
 }
 
+// ErrTotalTimeout is returned for requests cut short by Options.TotalTimeout
+var ErrTotalTimeout = errors.New("total time budget exceeded")
+
 // RetryPolicy defines exponential backoff characteristics for retries.
 type RetryPolicy struct {
 	MaxAttempts    int
@@ -93,6 +156,7 @@ func New(opts Options) (*Client, error) {
 		baseURL:  base,
 		username: strings.TrimSpace(opts.Username),
 		password: opts.Password,
+		bearer:   opts.BearerToken,
 		userAgent: func() string {
 			if opts.UserAgent != "" {
 				return opts.UserAgent
@@ -100,11 +164,40 @@ func New(opts Options) (*Client, error) {
 			return "bb-cli"
 		}(),
 		httpClient: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: transport(opts.Transport),
 		},
 		enableCache: opts.EnableCache,
 		cache:       make(map[string]*cacheEntry),
+		dryRun:      opts.DryRun,
+
+		rateLimitWait:     opts.RateLimitWait,
+		rateLimitProgress: opts.RateLimitProgress,
+	}
+	if client.rateLimitWait == 0 {
+		client.rateLimitWait = DefaultRateLimitWait
+	}
+	if opts.Pace {
+		client.pacer = &pacer{}
+	}
+	if opts.TotalTimeout > 0 {
+		client.deadline = time.Now().Add(opts.TotalTimeout)
+		client.totalTimeout = opts.TotalTimeout
+	}
+
+	if opts.Cassette != nil {
+		client.httpClient.Transport = opts.Cassette.Transport(client.httpClient.Transport)
+		if !opts.Cassette.Replaying() {
+			opts.Cassette.Redact(opts.Password, opts.BearerToken)
+		}
+	}
+	if opts.ResponseCache != nil {
+		client.httpClient.Transport = opts.ResponseCache.Transport(client.httpClient.Transport)
 	}
+	if opts.Audit != nil {
+		client.httpClient.Transport = opts.Audit.Transport(client.httpClient.Transport)
+	}
+	client.httpClient.Transport = &memoTransport{next: client.httpClient.Transport}
 
 	if opts.Debug || os.Getenv("BB_HTTP_DEBUG") != "" {
 		client.debug = true
@@ -204,11 +297,20 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, body any)
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", c.userAgent)
 
+	c.setAuth(req)
+
+	return req, nil
+}
+
+// setAuth adds the configured credentials to req
+func (c *Client) setAuth(req *http.Request) {
+	if c.bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearer)
+		return
+	}
 	if c.username != "" || c.password != "" {
 		req.SetBasicAuth(c.username, c.password)
 	}
-
-	return req, nil
 }
 
 // Do executes the HTTP request and decodes the response into v when provided.
@@ -219,11 +321,35 @@ func (c *Client) Do(req *http.Request, v any) error {
 
 // DoWithHeaders executes the request and returns both the response headers and any error
 func (c *Client) DoWithHeaders(req *http.Request, v any) (http.Header, error) {
+	if req == nil || c.deadline.IsZero() {
+		return c.doWithHeaders(req, v)
+	}
+
+	ctx, cancel := context.WithDeadline(req.Context(), c.deadline)
+	defer cancel()
+	headers, err := c.doWithHeaders(req.WithContext(ctx), v)
+	if err != nil && ctx.Err() != nil && req.Context().Err() == nil {
+		return headers, fmt.Errorf("%w (%s): %s %s", ErrTotalTimeout, c.totalTimeout, req.Method, req.URL.Redacted())
+	}
+	return headers, err
+}
+
+// doWithHeaders is DoWithHeaders without the total time budget
+func (c *Client) doWithHeaders(req *http.Request, v any) (http.Header, error) {
 	if req == nil {
 		return nil, fmt.Errorf("request is nil")
 	}
+	if c.dryRun != nil && req.Method != http.MethodGet && req.Method != http.MethodHead {
+		if err := writeDryRun(c.dryRun, req); err != nil {
+			return nil, err
+		}
+		return nil, ErrDryRun
+	}
 
 	attempts := 0
+	// 429s are retried for as long as the wait budget allows, not by attempts
+	limited := 0
+	var waited time.Duration
 	for {
 		attemptReq, err := cloneRequest(req)
 		if err != nil {
@@ -236,12 +362,25 @@ func (c *Client) DoWithHeaders(req *http.Request, v any) (http.Header, error) {
 			}
 		}
 
+		if err := c.pace(req.Context()); err != nil {
+			return nil, err
+		}
+
 		if c.debug {
 			fmt.Fprintf(os.Stderr, "--> %s %s\n", attemptReq.Method, attemptReq.URL.String())
 		}
-
-		resp, err := c.httpClient.Do(attemptReq)
+		resp, err := c.send(attemptReq)
 		if err != nil {
+			c.pacer.release()
+			// A request missing from a cassette or the offline cache stays missing
+			var miss *ReplayMissError
+			if errors.As(err, &miss) {
+				return nil, miss
+			}
+			var offline *OfflineError
+			if errors.As(err, &offline) {
+				return nil, offline
+			}
 			if !c.shouldRetry(attempts, 0) {
 				if c.debug {
 					fmt.Fprintf(os.Stderr, "<-- network error: %v\n", err)
@@ -263,12 +402,26 @@ func (c *Client) DoWithHeaders(req *http.Request, v any) (http.Header, error) {
 		}
 
 		c.updateRateLimit(resp)
-		c.applyAdaptiveThrottle()
 
 		if c.debug {
 			fmt.Fprintf(os.Stderr, "<-- %d %s\n", resp.StatusCode, http.StatusText(resp.StatusCode))
 		}
 
+		if resp.StatusCode == http.StatusTooManyRequests {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+			until, delay := c.rateLimitDelay(resp, limited)
+			deadline, bounded := req.Context().Deadline()
+			if waited+delay > c.rateLimitWait || bounded && time.Now().Add(delay).After(deadline) {
+				return resp.Header, &RateLimitError{Until: until, Budget: max(c.rateLimitWait, 0)}
+			}
+			if err := c.waitForRateLimit(req.Context(), until, delay); err != nil {
+				return nil, err
+			}
+			waited += delay
+			limited++
+			continue
+		}
 		if resp.StatusCode == http.StatusNotModified && c.enableCache && attemptReq.Method == http.MethodGet {
 			_ = resp.Body.Close()
 			if err := c.applyCachedResponse(attemptReq, v); err != nil {
@@ -347,6 +500,25 @@ func (c *Client) DoWithHeaders(req *http.Request, v any) (http.Header, error) {
 	}
 }
 
+// StatusError is the error for a response with a non-2xx status
+type StatusError struct {
+	StatusCode int
+	Status     string // e.g. "404 Not Found"
+	// Message is the error the body reported, or the body itself when it is
+	// not a recognised error document; empty for an empty body
+	Message string
+	// Body is the raw response body, for callers that parse their API's
+	// own error document
+	Body []byte
+}
+
+func (e *StatusError) Error() string {
+	if e.Message == "" {
+		return e.Status
+	}
+	return e.Status + ": " + e.Message
+}
+
 func decodeError(resp *http.Response) error {
 	type apiErrEntry struct {
 		Message       string `json:"message"`
@@ -358,7 +530,10 @@ func decodeError(resp *http.Response) error {
 
 	var payload apiErr
 	data, err := io.ReadAll(resp.Body)
-	if err == nil && len(data) > 0 {
+	if err != nil {
+		data = nil
+	}
+	if len(data) > 0 {
 		// Attempt to parse structured error; intentionally ignore unmarshal errors and fall back to raw text
 		_ = json.Unmarshal(data, &payload)
 	}
@@ -378,14 +553,10 @@ func decodeError(resp *http.Response) error {
 		if isCaptchaException(bestErr.ExceptionName) && !strings.Contains(strings.ToLower(msg), "captcha") {
 			msg = "CAPTCHA verification required: " + msg
 		}
-		return fmt.Errorf("%s: %s", resp.Status, msg)
+		return &StatusError{StatusCode: resp.StatusCode, Status: resp.Status, Message: msg, Body: data}
 	}
 
-	if err == nil && len(data) > 0 {
-		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
-	}
-
-	return fmt.Errorf("%s", resp.Status)
+	return &StatusError{StatusCode: resp.StatusCode, Status: resp.Status, Message: strings.TrimSpace(string(data)), Body: data}
 }
 
 // isCaptchaException checks if the exception name indicates a CAPTCHA-locked account.
@@ -409,10 +580,9 @@ func cloneRequest(req *http.Request) (*http.Request, error) {
 	return newReq, nil
 }
 
+// shouldRetryStatus reports server errors worth retrying; 429s are waited
+// out separately (see rateLimitDelay)
 func shouldRetryStatus(code int) bool {
-	if code == http.StatusTooManyRequests {
-		return true
-	}
 	return code >= 500 && code <= 599
 }
 
@@ -504,6 +674,12 @@ func (c *Client) applyCachedResponse(req *http.Request, v any) error {
 	return json.Unmarshal(entry.body, v)
 }
 
+// BaseURL returns a copy of the URL relative paths are resolved against.
+func (c *Client) BaseURL() *url.URL {
+	u := *c.baseURL
+	return &u
+}
+
 // RateLimitState returns the last observed rate limit headers.
 func (c *Client) RateLimitState() RateLimit {
 	c.rateMu.RLock()
@@ -562,31 +738,15 @@ func (c *Client) updateRateLimit(resp *http.Response) {
 	}
 
 	if limit == 0 && remaining == 0 {
+		c.pacer.observe(RateLimit{}, false, time.Now())
 		return
 	}
 
+	rate := RateLimit{Limit: limit, Remaining: remaining, Reset: reset, Source: source}
 	c.rateMu.Lock()
-	c.rate = RateLimit{Limit: limit, Remaining: remaining, Reset: reset, Source: source}
+	c.rate = rate
 	c.rateMu.Unlock()
-}
-
-func (c *Client) applyAdaptiveThrottle() {
-	c.rateMu.RLock()
-	rl := c.rate
-	c.rateMu.RUnlock()
-
-	if rl.Remaining > 1 || rl.Reset.IsZero() {
-		return
-	}
-
-	sleep := time.Until(rl.Reset)
-	if sleep <= 0 {
-		return
-	}
-	if sleep > 5*time.Second {
-		sleep = 5 * time.Second
-	}
-	time.Sleep(sleep)
+	c.pacer.observe(rate, true, time.Now())
 }
 
 // MultipartFile represents a file for multipart/form-data upload.
@@ -682,9 +842,7 @@ func (c *Client) NewMultipartRequest(ctx context.Context, method, path string, f
 		return io.NopCloser(bytes.NewReader(payload)), nil
 	}
 
-	if c.username != "" || c.password != "" {
-		req.SetBasicAuth(c.username, c.password)
-	}
+	c.setAuth(req)
 
 	return req, nil
 }
@@ -1,11 +1,14 @@
 package httpx
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -117,6 +120,225 @@ func TestClientRetriesOnServerError(t *testing.T) {
 	}
 }
 
+func TestClientWaitsOutRateLimit(t *testing.T) {
+	var hits int32
+	retryAfter := "1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1)%2 == 1 {
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(payload{Message: "ok"})
+	}))
+	t.Cleanup(server.Close)
+
+	var progress bytes.Buffer
+	client, err := New(Options{BaseURL: server.URL, RateLimitWait: 5 * time.Second, RateLimitProgress: &progress})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	get := func() error {
+		req, _ := client.NewRequest(context.Background(), http.MethodGet, "/api", nil)
+		return client.Do(req, &payload{})
+	}
+
+	if err := get(); err != nil {
+		t.Fatalf("Do after a short rate limit: %v", err)
+	}
+	if hits != 2 || !strings.HasPrefix(progress.String(), "rate limited until ") {
+		t.Errorf("hits = %d, progress = %q; want one wait reported", hits, progress.String())
+	}
+
+	retryAfter = "3600"
+	err = get()
+	var limited *RateLimitError
+	if !errors.As(err, &limited) || time.Until(limited.Until) < 59*time.Minute || limited.Budget != 5*time.Second {
+		t.Fatalf("Do with an hour-long rate limit = %v, want a RateLimitError without waiting", err)
+	}
+	if hits != 3 {
+		t.Errorf("hits = %d, want no retry past the budget", hits)
+	}
+}
+
+func TestPacer(t *testing.T) {
+	now := time.Now()
+	p := &pacer{}
+	if d := p.reserve(now); d != 0 {
+		t.Fatalf("reserve before any rate limit = %s, want no wait", d)
+	}
+	// 3600 an hour refills one token a second; one request is left
+	p.observe(RateLimit{Limit: 3600, Remaining: 1}, true, now)
+	if d := p.reserve(now); d != 0 {
+		t.Errorf("reserve with a request left = %s, want no wait", d)
+	}
+	if d := p.reserve(now); d != time.Second {
+		t.Errorf("reserve past the headroom = %s, want 1s", d)
+	}
+	// Requests reserved but not yet answered count against the headroom
+	p.observe(RateLimit{Limit: 3600, Remaining: 2}, true, now)
+	if d := p.reserve(now); d != 0 {
+		t.Errorf("reserve with one left and one in flight = %s, want no wait", d)
+	}
+	if d := p.reserve(now); d != time.Second {
+		t.Errorf("second reserve = %s, want 1s", d)
+	}
+	if d := p.reserve(now.Add(3 * time.Second)); d != 0 {
+		t.Errorf("reserve after refilling = %s, want no wait", d)
+	}
+	// The whole limit is back at the reset
+	p.observe(RateLimit{Limit: 3600, Remaining: 0, Reset: now.Add(200 * time.Millisecond)}, true, now)
+	if d := p.reserve(now); d != 200*time.Millisecond {
+		t.Errorf("reserve before a reset = %s, want 200ms", d)
+	}
+
+	var nilPacer *pacer
+	if d := nilPacer.reserve(now); d != 0 {
+		t.Errorf("nil pacer waits %s", d)
+	}
+}
+
+func TestClientPacesRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 36000 an hour is one request every 100ms once the headroom is gone
+		w.Header().Set("X-RateLimit-Limit", "36000")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	get := func(client *Client) time.Duration {
+		t.Helper()
+		start := time.Now()
+		req, _ := client.NewRequest(context.Background(), http.MethodGet, "/api", nil)
+		if err := client.Do(req, nil); err != nil {
+			t.Fatalf("Do: %v", err)
+		}
+		return time.Since(start)
+	}
+
+	var progress bytes.Buffer
+	client, err := New(Options{BaseURL: server.URL, Pace: true, RateLimitProgress: &progress})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	get(client)
+	if elapsed := get(client) + get(client); elapsed < 150*time.Millisecond {
+		t.Errorf("two paced requests took %s, want about 200ms", elapsed)
+	}
+	if got := progress.String(); strings.Count(got, "\n") != 1 || !strings.Contains(got, "one every 100ms") {
+		t.Errorf("progress = %q, want the pacing reported once", got)
+	}
+
+	unpaced, err := New(Options{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	get(unpaced)
+	if elapsed := get(unpaced) + get(unpaced); elapsed >= 100*time.Millisecond {
+		t.Errorf("unpaced requests took %s", elapsed)
+	}
+}
+
+func TestClientSerializesOnLowRateLimit(t *testing.T) {
+	var remaining atomic.Int64
+	remaining.Store(900)
+	var inFlight, maxInFlight atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("X-RateLimit-Limit", "1000")
+		w.Header().Set("X-RateLimit-Remaining", strconv.FormatInt(remaining.Add(-1), 10))
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(Options{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	fanOut := func() int64 {
+		t.Helper()
+		maxInFlight.Store(0)
+		var wg sync.WaitGroup
+		for range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				req, _ := client.NewRequest(context.Background(), http.MethodGet, "/api", nil)
+				if err := client.Do(req, nil); err != nil {
+					t.Errorf("Do: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+		return maxInFlight.Load()
+	}
+
+	// A fresh client knows no limit, so the first fan-out runs at once
+	if got := fanOut(); got < 2 {
+		t.Errorf("with headroom, %d requests were in flight at once, want several", got)
+	}
+	// The limit runs low during the run; the same client now sends one at a time
+	remaining.Store(60)
+	req, _ := client.NewRequest(context.Background(), http.MethodGet, "/api", nil)
+	if err := client.Do(req, nil); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if got := fanOut(); got != 1 {
+		t.Errorf("with the limit low, %d requests were in flight at once, want 1", got)
+	}
+}
+
+func TestClientTotalTimeout(t *testing.T) {
+	var failing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(Options{
+		BaseURL:      server.URL,
+		Retry:        RetryPolicy{MaxAttempts: 100, InitialBackoff: 50 * time.Millisecond, MaxBackoff: 50 * time.Millisecond},
+		TotalTimeout: 300 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	get := func() error {
+		req, _ := client.NewRequest(context.Background(), http.MethodGet, "/api", nil)
+		return client.Do(req, nil)
+	}
+
+	if err := get(); err != nil {
+		t.Fatalf("Do within the budget: %v", err)
+	}
+	failing.Store(true)
+	start := time.Now()
+	if err := get(); !errors.Is(err, ErrTotalTimeout) {
+		t.Fatalf("Do retrying past the budget = %v, want ErrTotalTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("retries ran %s, past the 300ms budget", elapsed)
+	}
+	failing.Store(false)
+	if err := get(); !errors.Is(err, ErrTotalTimeout) {
+		t.Errorf("Do after the budget = %v, want ErrTotalTimeout", err)
+	}
+}
+
 func TestClientNewRequestPreservesQuery(t *testing.T) {
 	client, err := New(Options{BaseURL: "https://example.com/api"})
 	if err != nil {
@@ -137,6 +359,32 @@ func TestClientNewRequestPreservesQuery(t *testing.T) {
 	}
 }
 
+func TestClientNewRequestAuth(t *testing.T) {
+	basic, err := New(Options{BaseURL: "https://api.example.com", Username: "user", Password: "pass"})
+	if err != nil {
+		t.Fatalf("New client: %v", err)
+	}
+	req, err := basic.NewRequest(context.Background(), http.MethodGet, "/user", nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	if user, pass, ok := req.BasicAuth(); !ok || user != "user" || pass != "pass" {
+		t.Errorf("expected basic auth user:pass, got %q", req.Header.Get("Authorization"))
+	}
+
+	bearer, err := New(Options{BaseURL: "https://api.example.com", BearerToken: "tok"})
+	if err != nil {
+		t.Fatalf("New client: %v", err)
+	}
+	req, err = bearer.NewRequest(context.Background(), http.MethodGet, "/user", nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer tok" {
+		t.Errorf("Authorization = %q, want %q", got, "Bearer tok")
+	}
+}
+
 func TestClientNewRequestHandlesRelativeWithoutSlash(t *testing.T) {
 	client, err := New(Options{BaseURL: "https://example.com/api"})
 	if err != nil {
@@ -407,6 +655,24 @@ func TestDecodeErrorPrioritizesCaptchaException(t *testing.T) {
 			if err.Error() != tt.wantMsg {
 				t.Errorf("got %q, want %q", err.Error(), tt.wantMsg)
 			}
+			var statusErr *StatusError
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.status || string(statusErr.Body) != tt.body {
+				t.Errorf("error %#v is not a StatusError for the response", err)
+			}
 		})
 	}
 }
+
+func TestNewTransport(t *testing.T) {
+	tr := NewTransport(TransportOptions{})
+	if tr.MaxIdleConnsPerHost != defaultMaxIdleConnsPerHost || tr.IdleConnTimeout != defaultIdleConnTimeout {
+		t.Errorf("defaults = %d idle per host, %v timeout", tr.MaxIdleConnsPerHost, tr.IdleConnTimeout)
+	}
+	tr = NewTransport(TransportOptions{MaxIdleConnsPerHost: 4, MaxConnsPerHost: 8})
+	if tr.MaxIdleConnsPerHost != 4 || tr.MaxConnsPerHost != 8 {
+		t.Errorf("tuned = %d idle, %d max per host", tr.MaxIdleConnsPerHost, tr.MaxConnsPerHost)
+	}
+	if transport(TransportOptions{}) != transport(TransportOptions{}) {
+		t.Error("clients with default options do not share a transport")
+	}
+}
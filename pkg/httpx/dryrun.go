@@ -0,0 +1,56 @@
+package httpx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// ErrDryRun is returned for requests a dry-run client did not send
+var ErrDryRun = errors.New("dry run: request not sent")
+
+// dryRunRequest describes a request that was not sent
+type dryRunRequest struct {
+	DryRun bool            `json:"dry_run"`
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// writeDryRun writes req to w as a JSON object. JSON bodies are embedded
+// as-is; other bodies, such as file uploads, only by their type and size.
+func writeDryRun(w io.Writer, req *http.Request) error {
+	out := dryRunRequest{DryRun: true, Method: req.Method, URL: req.URL.Redacted()}
+
+	body, err := readBody(req)
+	if err != nil {
+		return err
+	}
+	if len(body) > 0 {
+		if json.Valid(body) {
+			out.Body = body
+		} else if out.Body, err = json.Marshal(bodyPlaceholder(req, len(body))); err != nil {
+			return fmt.Errorf("encode request body: %w", err)
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return fmt.Errorf("write dry run: %w", err)
+	}
+	return nil
+}
+
+// bodyPlaceholder stands for a body that is not JSON, e.g. "[multipart/form-data,
+// 2048 bytes]"
+func bodyPlaceholder(req *http.Request, size int) string {
+	if mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type")); err == nil {
+		return fmt.Sprintf("[%s, %d bytes]", mediaType, size)
+	}
+	return fmt.Sprintf("[%d bytes]", size)
+}
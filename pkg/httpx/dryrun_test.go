@@ -0,0 +1,66 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		_, _ = w.Write([]byte(`{"message":"hello"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	var out strings.Builder
+	client, err := New(Options{BaseURL: server.URL, Username: "alice", Password: "secret", DryRun: &out})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got payload
+	req, _ := client.NewRequest(context.Background(), http.MethodGet, "/api", nil)
+	if err := client.Do(req, &got); err != nil || got.Message != "hello" {
+		t.Fatalf("GET = %+v, %v; want it sent", got, err)
+	}
+
+	req, _ = client.NewRequest(context.Background(), http.MethodPost, "/api/comments?a=1&b=2", map[string]any{"content": map[string]string{"raw": "LGTM"}})
+	if err := client.Do(req, nil); !errors.Is(err, ErrDryRun) {
+		t.Fatalf("POST err = %v, want ErrDryRun", err)
+	}
+	if len(methods) != 1 {
+		t.Errorf("server saw %v, want only the GET", methods)
+	}
+
+	want := `{
+  "dry_run": true,
+  "method": "POST",
+  "url": "` + server.URL + `/api/comments?a=1&b=2",
+  "body": {
+    "content": {
+      "raw": "LGTM"
+    }
+  }
+}
+`
+	if out.String() != want {
+		t.Errorf("dry run output =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	// Uploads are summarised rather than dumped
+	out.Reset()
+	req, _ = client.NewMultipartRequest(context.Background(), http.MethodPost, "/api/downloads", []MultipartFile{
+		{FieldName: "files", FileName: "app.tar.gz", Reader: strings.NewReader(strings.Repeat("x", 2048))},
+	})
+	if err := client.Do(req, nil); !errors.Is(err, ErrDryRun) {
+		t.Fatalf("upload err = %v, want ErrDryRun", err)
+	}
+	if got := out.String(); !strings.Contains(got, `"body": "[multipart/form-data, `) || strings.Contains(got, "xxxx") {
+		t.Errorf("upload dry run output =\n%s", out.String())
+	}
+}
@@ -0,0 +1,123 @@
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+type memoKey struct{}
+
+// memo holds the GET responses of one scope (see WithMemo)
+type memo struct {
+	mu      sync.Mutex
+	entries map[string]*memoEntry
+}
+
+// memoEntry is one GET request, in flight until done is closed
+type memoEntry struct {
+	done   chan struct{}
+	status int
+	header http.Header
+	body   []byte
+	ok     bool // the request succeeded and its response can be shared
+}
+
+// WithMemo starts a memo scope: a successful GET request made with the
+// returned context, or one derived from it, is sent once and its response
+// shared by every identical request of the scope, including ones made while
+// it is in flight. Any successful request that changes something ends the
+// sharing of what was fetched so far. A command runs in one scope; commands
+// that poll start a new one per round so each round sees fresh data.
+func WithMemo(ctx context.Context) context.Context {
+	return context.WithValue(ctx, memoKey{}, &memo{entries: make(map[string]*memoEntry)})
+}
+
+// memoTransport answers GET requests from the memo scope of their context
+type memoTransport struct {
+	next http.RoundTripper
+}
+
+func (t *memoTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	m, _ := req.Context().Value(memoKey{}).(*memo)
+	if m == nil {
+		return t.next.RoundTrip(req)
+	}
+	if req.Method != http.MethodGet {
+		resp, err := t.next.RoundTrip(req)
+		if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			m.clear()
+		}
+		return resp, err
+	}
+
+	// The key covers the credentials and the representation asked for
+	key := req.Header.Get("Authorization") + " " + req.Header.Get("Accept") + " " + req.URL.String()
+	m.mu.Lock()
+	if e, ok := m.entries[key]; ok {
+		m.mu.Unlock()
+		select {
+		case <-e.done:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		if e.ok {
+			return e.response(req), nil
+		}
+		// The first request failed; this one tries on its own
+		return t.next.RoundTrip(req)
+	}
+	e := &memoEntry{done: make(chan struct{})}
+	m.entries[key] = e
+	m.mu.Unlock()
+	defer close(e.done)
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		m.forget(key, e)
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		m.forget(key, e)
+		return nil, err
+	}
+	e.status, e.header, e.body, e.ok = resp.StatusCode, resp.Header.Clone(), body, true
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// forget drops e, if it is still the entry of key, so later requests are sent
+func (m *memo) forget(key string, e *memoEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.entries[key] == e {
+		delete(m.entries, key)
+	}
+}
+
+// clear drops every entry; requests in flight still answer their waiters
+func (m *memo) clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[string]*memoEntry)
+}
+
+// response rebuilds the shared response for req
+func (e *memoEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.status, http.StatusText(e.status)),
+		StatusCode:    e.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
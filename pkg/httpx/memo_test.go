@@ -0,0 +1,79 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMemo(t *testing.T) {
+	var hits atomic.Int32
+	arrived, release := make(chan struct{}, 4), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.URL.Path == "/slow" {
+			arrived <- struct{}{}
+			<-release
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"path":"` + r.URL.Path + `"}`))
+	}))
+	defer server.Close()
+
+	client, err := New(Options{BaseURL: server.URL, Username: "u", Password: "p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	get := func(ctx context.Context, method, path string) string {
+		req, _ := client.NewRequest(ctx, method, path, nil)
+		var got struct{ Path string }
+		if err := client.Do(req, &got); err != nil {
+			t.Error(err)
+		}
+		return got.Path
+	}
+
+	scope := WithMemo(context.Background())
+	get(scope, http.MethodGet, "/pr")
+	if got := get(scope, http.MethodGet, "/pr"); got != "/pr" || hits.Load() != 1 {
+		t.Errorf("repeat GET = %q after %d hits, want the response shared", got, hits.Load())
+	}
+	get(scope, http.MethodGet, "/pr/diffstat")
+	if hits.Load() != 2 {
+		t.Errorf("hits = %d, want another path fetched", hits.Load())
+	}
+
+	// Identical requests in flight together are sent once
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := get(scope, http.MethodGet, "/slow"); got != "/slow" {
+				t.Errorf("concurrent GET = %q", got)
+			}
+		}()
+	}
+	<-arrived
+	close(release)
+	wg.Wait()
+	if hits.Load() != 3 {
+		t.Errorf("hits = %d, want concurrent GETs sent once", hits.Load())
+	}
+
+	get(scope, http.MethodPost, "/pr/comments")
+	get(scope, http.MethodGet, "/pr")
+	if hits.Load() != 5 {
+		t.Errorf("hits = %d, want a change to end the sharing", hits.Load())
+	}
+
+	get(WithMemo(context.Background()), http.MethodGet, "/pr")
+	get(context.Background(), http.MethodGet, "/pr")
+	get(context.Background(), http.MethodGet, "/pr")
+	if hits.Load() != 8 {
+		t.Errorf("hits = %d, want new scopes and unscoped GETs fetched", hits.Load())
+	}
+}
@@ -0,0 +1,285 @@
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// responseCacheMaxAge is how long cached responses are kept
+	responseCacheMaxAge = 30 * 24 * time.Hour
+	// responseCachePruneEvery is how often expired responses are deleted
+	responseCachePruneEvery = 24 * time.Hour
+	// mutatedMarker is touched after every successful request that changes
+	// something; fresh responses stored before it are not served
+	mutatedMarker = ".mutated"
+)
+
+// ResponseCache keeps the responses of successful GET requests on disk so they
+// can be served without the network: for every request when offline, and as a
+// fallback when a request cannot reach the API. Responses are cached per
+// credentials and kept for 30 days.
+type ResponseCache struct {
+	dir     string
+	offline bool
+
+	mu        sync.Mutex
+	staleAsOf time.Time
+}
+
+type cachedResponse struct {
+	URL         string    `json:"url"`
+	StoredAt    time.Time `json:"stored_at"`
+	Version     string    `json:"version,omitempty"`
+	Status      int       `json:"status"`
+	ContentType string    `json:"content_type,omitempty"`
+	Body        []byte    `json:"body"`
+}
+
+// NewResponseCache returns a cache storing responses in dir. When offline is
+// set no request reaches the network.
+func NewResponseCache(dir string, offline bool) *ResponseCache {
+	c := &ResponseCache{dir: dir, offline: offline}
+	c.prune()
+	return c
+}
+
+// Offline reports whether every request is answered from the cache
+func (c *ResponseCache) Offline() bool {
+	return c.offline
+}
+
+// StaleAsOf returns when the oldest response served from the cache instead
+// of the network was stored, or the zero time if none was
+func (c *ResponseCache) StaleAsOf() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.staleAsOf
+}
+
+type freshnessKey struct{}
+
+// freshness is how recent a cached response must be to answer a request
+// without the network
+type freshness struct {
+	version string
+	maxAge  time.Duration
+}
+
+// WithFreshResponse lets the response cache answer the GET requests made
+// with ctx, without asking the API, from a response stored less than maxAge
+// ago under the same version (such as a pull request's updated_on). The
+// responses fetched are stored under version. Any successful request that
+// changes something makes earlier responses stale.
+func WithFreshResponse(ctx context.Context, version string, maxAge time.Duration) context.Context {
+	return context.WithValue(ctx, freshnessKey{}, freshness{version: version, maxAge: maxAge})
+}
+
+// Transport returns a round tripper that caches GET responses from next and
+// answers from the cache when offline or when next fails
+func (c *ResponseCache) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &responseCacheTransport{cache: c, next: next}
+}
+
+type responseCacheTransport struct {
+	cache *ResponseCache
+	next  http.RoundTripper
+}
+
+func (t *responseCacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		if t.cache.offline {
+			return nil, &OfflineError{Method: req.Method, URL: req.URL.Redacted()}
+		}
+		resp, err := t.next.RoundTrip(req)
+		if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			t.cache.markMutated()
+		}
+		return resp, err
+	}
+
+	path := t.cache.path(req)
+	if t.cache.offline {
+		if resp := t.cache.serve(req, path); resp != nil {
+			return resp, nil
+		}
+		return nil, &OfflineError{Method: req.Method, URL: req.URL.Redacted()}
+	}
+
+	fresh, wantFresh := req.Context().Value(freshnessKey{}).(freshness)
+	if wantFresh {
+		if resp := t.cache.serveFresh(req, path, fresh); resp != nil {
+			return resp, nil
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		// Network failure: fall back to the cache, unless the user gave up
+		if req.Context().Err() == nil {
+			if cached := t.cache.serve(req, path); cached != nil {
+				return cached, nil
+			}
+		}
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	t.cache.store(path, cachedResponse{
+		URL:         req.URL.Redacted(),
+		StoredAt:    time.Now().UTC(),
+		Version:     fresh.version,
+		Status:      resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        body,
+	})
+	return resp, nil
+}
+
+// path returns the cache file of req. The key covers the credentials, so
+// accounts never see each other's responses.
+func (c *ResponseCache) path(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Header.Get("Authorization") + " " + req.URL.String()))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// serve returns the cached response at path, or nil if there is none
+func (c *ResponseCache) serve(req *http.Request, path string) *http.Response {
+	cached, ok := c.load(path)
+	if !ok || time.Since(cached.StoredAt) > responseCacheMaxAge {
+		return nil
+	}
+
+	c.mu.Lock()
+	if c.staleAsOf.IsZero() || cached.StoredAt.Before(c.staleAsOf) {
+		c.staleAsOf = cached.StoredAt
+	}
+	c.mu.Unlock()
+
+	return cached.response(req)
+}
+
+// serveFresh returns the cached response at path if it is recent enough for
+// fresh, or nil
+func (c *ResponseCache) serveFresh(req *http.Request, path string, fresh freshness) *http.Response {
+	cached, ok := c.load(path)
+	if !ok || cached.Version != fresh.version || time.Since(cached.StoredAt) > fresh.maxAge {
+		return nil
+	}
+	if info, err := os.Stat(filepath.Join(c.dir, mutatedMarker)); err == nil && !cached.StoredAt.After(info.ModTime()) {
+		return nil
+	}
+	return cached.response(req)
+}
+
+// load reads the cached response at path
+func (c *ResponseCache) load(path string) (cachedResponse, bool) {
+	var cached cachedResponse
+	data, err := os.ReadFile(path)
+	if err != nil || json.Unmarshal(data, &cached) != nil {
+		return cached, false
+	}
+	return cached, true
+}
+
+// markMutated makes the fresh responses stored so far stale
+func (c *ResponseCache) markMutated() {
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return
+	}
+	_ = os.WriteFile(filepath.Join(c.dir, mutatedMarker), nil, 0o600)
+}
+
+// response rebuilds the HTTP response for req
+func (cached cachedResponse) response(req *http.Request) *http.Response {
+	header := make(http.Header)
+	if cached.ContentType != "" {
+		header.Set("Content-Type", cached.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", cached.Status, http.StatusText(cached.Status)),
+		StatusCode:    cached.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       req,
+	}
+}
+
+// store writes a response to path; failures only cost a future cache miss
+func (c *ResponseCache) store(path string, cached cachedResponse) {
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+	}
+}
+
+// prune deletes expired responses, at most once a day
+func (c *ResponseCache) prune() {
+	marker := filepath.Join(c.dir, ".pruned")
+	if info, err := os.Stat(marker); err == nil && time.Since(info.ModTime()) < responseCachePruneEvery {
+		return
+	}
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		if info, err := e.Info(); err == nil && time.Since(info.ModTime()) > responseCacheMaxAge {
+			_ = os.Remove(filepath.Join(c.dir, e.Name()))
+		}
+	}
+	_ = os.WriteFile(marker, nil, 0o600)
+}
+
+// OfflineError reports a request that could not be answered offline: a GET
+// with no cached response, or a request that would change something
+type OfflineError struct {
+	Method string
+	URL    string
+}
+
+func (e *OfflineError) Error() string {
+	if e.Method == http.MethodGet {
+		return fmt.Sprintf("offline: no cached response for GET %s (fetch it once while online)", e.URL)
+	}
+	return fmt.Sprintf("offline: %s %s not sent", e.Method, e.URL)
+}
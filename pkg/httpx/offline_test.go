@@ -0,0 +1,123 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestResponseCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		_, _ = w.Write([]byte(`{"title":"Fix the build"}`))
+	}))
+
+	dir := filepath.Join(t.TempDir(), "http")
+	get := func(cache *ResponseCache, p string) (string, error) {
+		client, err := New(Options{BaseURL: server.URL, Username: "u", Password: "p", ResponseCache: cache})
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, _ := client.NewRequest(context.Background(), http.MethodGet, p, nil)
+		var pr struct {
+			Title string `json:"title"`
+		}
+		err = client.Do(req, &pr)
+		return pr.Title, err
+	}
+
+	online := NewResponseCache(dir, false)
+	if title, err := get(online, "/pr"); err != nil || title != "Fix the build" {
+		t.Fatalf("online GET = %q, %v", title, err)
+	}
+	_, _ = get(online, "/missing")
+	if !online.StaleAsOf().IsZero() {
+		t.Error("online responses reported as stale")
+	}
+
+	// Network failure falls back to the cache
+	server.Close()
+	fallback := NewResponseCache(dir, false)
+	if title, err := get(fallback, "/pr"); err != nil || title != "Fix the build" {
+		t.Fatalf("GET with the server down = %q, %v", title, err)
+	}
+	if fallback.StaleAsOf().IsZero() {
+		t.Error("fallback response not reported as stale")
+	}
+
+	offline := NewResponseCache(dir, true)
+	if title, err := get(offline, "/pr"); err != nil || title != "Fix the build" {
+		t.Fatalf("offline GET = %q, %v", title, err)
+	}
+	var offlineErr *OfflineError
+	if _, err := get(offline, "/missing"); !errors.As(err, &offlineErr) {
+		t.Errorf("offline GET of an error response = %v, want OfflineError", err)
+	}
+
+	client, _ := New(Options{BaseURL: server.URL, Username: "u", Password: "p", ResponseCache: offline})
+	req, _ := client.NewRequest(context.Background(), http.MethodPost, "/pr/approve", nil)
+	if err := client.Do(req, nil); !errors.As(err, &offlineErr) || offlineErr.Method != http.MethodPost {
+		t.Errorf("offline POST = %v, want OfflineError", err)
+	}
+
+	// Another account never sees these responses
+	other, _ := New(Options{BaseURL: server.URL, Username: "someone", Password: "else", ResponseCache: offline})
+	req, _ = other.NewRequest(context.Background(), http.MethodGet, "/pr", nil)
+	if err := other.Do(req, nil); !errors.As(err, &offlineErr) {
+		t.Errorf("offline GET with other credentials = %v, want OfflineError", err)
+	}
+}
+
+func TestResponseCacheFresh(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, err := New(Options{BaseURL: server.URL, Username: "u", Password: "p", ResponseCache: NewResponseCache(t.TempDir(), false)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(ctx context.Context, method string) {
+		req, _ := client.NewRequest(ctx, method, "/pr/diffstat", nil)
+		if err := client.Do(req, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	v1 := WithFreshResponse(context.Background(), "v1", time.Minute)
+
+	do(v1, http.MethodGet)
+	do(v1, http.MethodGet)
+	if hits != 1 {
+		t.Errorf("hits = %d, want the second GET served from the cache", hits)
+	}
+	do(WithFreshResponse(context.Background(), "v2", time.Minute), http.MethodGet)
+	if hits != 2 {
+		t.Errorf("hits = %d, want a new version fetched", hits)
+	}
+	do(context.Background(), http.MethodGet)
+	if hits != 3 {
+		t.Errorf("hits = %d, want plain GETs always fetched", hits)
+	}
+
+	do(v1, http.MethodGet)
+	do(v1, http.MethodGet)
+	if hits != 4 {
+		t.Fatalf("hits = %d, want 4", hits)
+	}
+	do(context.Background(), http.MethodPost)
+	do(v1, http.MethodGet)
+	if hits != 6 {
+		t.Errorf("hits = %d, want a change to make cached responses stale", hits)
+	}
+}
@@ -0,0 +1,209 @@
+package httpx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultRateLimitWait is how long a request waits in total for the rate
+// limit to reset when Options.RateLimitWait is zero
+const DefaultRateLimitWait = time.Minute
+
+// RateLimitError reports a request the API kept rate limiting for longer than
+// the client was willing to wait (see Options.RateLimitWait)
+type RateLimitError struct {
+	// Until is when the API said the limit resets; zero if it did not say
+	Until time.Time
+	// Budget is how long the request could wait in total
+	Budget time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Until.IsZero() {
+		return fmt.Sprintf("rate limited by the API; still limited after waiting %s", e.Budget)
+	}
+	return fmt.Sprintf("rate limited by the API until %s, beyond the %s wait budget",
+		e.Until.Local().Format(time.RFC3339), e.Budget)
+}
+
+// rateLimitDelay returns when a 429 response says the limit resets and how
+// long to wait for it: Retry-After, then X-RateLimit-Reset, and without
+// either the retry backoff for the number of 429s seen so far
+func (c *Client) rateLimitDelay(resp *http.Response, limited int) (time.Time, time.Duration) {
+	now := time.Now()
+	var until time.Time
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if secs, err := strconv.Atoi(retryAfter); err == nil {
+			until = now.Add(time.Duration(secs) * time.Second)
+		} else if at, err := http.ParseTime(retryAfter); err == nil {
+			until = at
+		}
+	}
+	if until.IsZero() {
+		until = c.RateLimitState().Reset
+	}
+	if until.IsZero() {
+		delay := c.retry.InitialBackoff << min(limited, 16)
+		return time.Time{}, min(delay, c.retry.MaxBackoff)
+	}
+	// The reset may have passed while the response travelled; still pause
+	return until, max(time.Until(until), time.Second)
+}
+
+// waitForRateLimit reports and waits out delay, returning an error if the
+// context ends first
+func (c *Client) waitForRateLimit(ctx context.Context, until time.Time, delay time.Duration) error {
+	if c.rateLimitProgress != nil {
+		if until.IsZero() {
+			_, _ = fmt.Fprintf(c.rateLimitProgress, "rate limited; retrying in %s\n", delay.Round(time.Second))
+		} else {
+			_, _ = fmt.Fprintf(c.rateLimitProgress, "rate limited until %s; retrying in %s\n",
+				until.Local().Format(time.RFC3339), delay.Round(time.Second))
+		}
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// lowRateLimitShare is the share of the rate limit below which requests go
+// one at a time
+const lowRateLimitShare = 10
+
+// send sends one attempt of a request. While the latest response reported
+// less than a tenth of the rate limit remaining, attempts go one at a time
+// (each waits for the previous one's response headers), so a fan-out stops
+// spending the rest of the limit in bursts as soon as it runs low, whatever
+// concurrency it started with.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if rl := c.RateLimitState(); rl.Limit > 0 && rl.Remaining < rl.Limit/lowRateLimitShare {
+		c.serial.Lock()
+		defer c.serial.Unlock()
+	}
+	return c.httpClient.Do(req)
+}
+
+// defaultRateWindow is the period the API's rate limits refill over; a
+// limit of N requests an hour refills at N per hour
+const defaultRateWindow = time.Hour
+
+// pacer is a token bucket fed by the rate limit headers (see Options.Pace).
+// Its tokens mirror the requests the API says are left, less those in
+// flight, and refill at the limit's hourly rate. A fan-out runs at full speed
+// while the headroom lasts and is spaced out to the sustainable rate once it
+// runs low, rather than sending requests the API is about to refuse.
+type pacer struct {
+	mu       sync.Mutex
+	known    bool // a response carried rate limit headers
+	tokens   float64
+	burst    float64
+	rate     float64   // tokens per second
+	reset    time.Time // when the API says the limit resets, if it did
+	last     time.Time // when tokens was last refilled
+	inFlight int
+	reported bool
+}
+
+// reserve takes a token for a request about to be sent and returns how long
+// to wait before sending it. A nil pacer never waits.
+func (p *pacer) reserve(now time.Time) time.Duration {
+	if p == nil {
+		return 0
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.inFlight++
+	if !p.known {
+		return 0
+	}
+	if elapsed := now.Sub(p.last); elapsed > 0 {
+		p.tokens = min(p.burst, p.tokens+elapsed.Seconds()*p.rate)
+		p.last = now
+	}
+	p.tokens--
+	if p.tokens >= 0 {
+		return 0
+	}
+	delay := time.Duration(-p.tokens / p.rate * float64(time.Second))
+	// The whole limit is back at the reset
+	if until := p.reset.Sub(now); until > 0 && until < delay {
+		delay = until
+	}
+	return delay
+}
+
+// observe updates the bucket from the rate limit of a response to a reserved
+// request; ok is false when the response carried no rate limit headers
+func (p *pacer) observe(rl RateLimit, ok bool, now time.Time) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.inFlight = max(p.inFlight-1, 0)
+	if !ok || rl.Limit <= 0 {
+		return
+	}
+	p.known = true
+	p.burst = float64(rl.Limit)
+	p.rate = float64(rl.Limit) / defaultRateWindow.Seconds()
+	p.tokens = float64(rl.Remaining - p.inFlight)
+	p.reset = rl.Reset
+	p.last = now
+}
+
+// release returns the slot of a reserved request that got no response
+func (p *pacer) release() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.inFlight = max(p.inFlight-1, 0)
+	p.mu.Unlock()
+}
+
+// firstWait reports whether this is the pacer's first wait, which is
+// reported, and the interval requests are spaced out to
+func (p *pacer) firstWait() (time.Duration, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	first := !p.reported
+	p.reported = true
+	return time.Duration(float64(time.Second) / p.rate), first
+}
+
+// pace waits for the pacer's go-ahead to send a request. Waits longer than
+// the rate limit budget or the request's deadline are skipped; the API
+// answers 429 instead if it must.
+func (c *Client) pace(ctx context.Context) error {
+	delay := c.pacer.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); delay > c.rateLimitWait || ok && time.Now().Add(delay).After(deadline) {
+		return nil
+	}
+	if interval, first := c.pacer.firstWait(); first && c.rateLimitProgress != nil {
+		_, _ = fmt.Fprintf(c.rateLimitProgress, "rate limit nearly spent; spacing requests out to one every %s\n",
+			interval.Round(100*time.Millisecond))
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		c.pacer.release()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
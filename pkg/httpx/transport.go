@@ -0,0 +1,74 @@
+package httpx
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Commands fan requests out with errgroup, several at a time to the one API
+// host, while http.DefaultTransport keeps just two idle connections per host:
+// the rest are closed after each burst and dialled, TLS handshake included,
+// again on the next. The transport tuned here keeps enough of them alive.
+const (
+	defaultMaxIdleConnsPerHost = 16
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultKeepAlive           = 30 * time.Second
+)
+
+// TransportOptions tunes the connections of a Client. Zero fields keep the
+// defaults.
+type TransportOptions struct {
+	// MaxIdleConnsPerHost is how many idle keep-alive connections are kept
+	// per host for reuse (default 16)
+	MaxIdleConnsPerHost int
+
+	// MaxConnsPerHost caps the connections per host, idle or active; 0
+	// means no limit
+	MaxConnsPerHost int
+
+	// IdleConnTimeout is how long an idle connection is kept (default 90s)
+	IdleConnTimeout time.Duration
+
+	// KeepAlive is the TCP keep-alive period of connections (default 30s)
+	KeepAlive time.Duration
+}
+
+var (
+	sharedTransportOnce sync.Once
+	sharedTransport     *http.Transport
+)
+
+// transport returns the transport for opts. Clients with default options
+// share one, and so its pool of connections.
+func transport(opts TransportOptions) *http.Transport {
+	if opts == (TransportOptions{}) {
+		sharedTransportOnce.Do(func() { sharedTransport = NewTransport(opts) })
+		return sharedTransport
+	}
+	return NewTransport(opts)
+}
+
+// NewTransport returns an HTTP transport tuned for parallel requests to one
+// host, based on http.DefaultTransport (proxy settings, HTTP/2 and timeouts
+// included)
+func NewTransport(opts TransportOptions) *http.Transport {
+	if opts.MaxIdleConnsPerHost == 0 {
+		opts.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	}
+	if opts.IdleConnTimeout == 0 {
+		opts.IdleConnTimeout = defaultIdleConnTimeout
+	}
+	if opts.KeepAlive == 0 {
+		opts.KeepAlive = defaultKeepAlive
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.DialContext = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: opts.KeepAlive}).DialContext
+	t.MaxIdleConns = max(t.MaxIdleConns, opts.MaxIdleConnsPerHost)
+	t.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+	t.MaxConnsPerHost = opts.MaxConnsPerHost
+	t.IdleConnTimeout = opts.IdleConnTimeout
+	return t
+}
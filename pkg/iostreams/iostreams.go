@@ -3,6 +3,8 @@ package iostreams
 import (
 	"io"
 	"os"
+	"os/exec"
+	"strconv"
 	"sync"
 
 	"golang.org/x/term"
@@ -23,8 +25,23 @@ type IOStreams struct {
 	isStdoutTTY bool
 	isStderrTTY bool
 
+	neverPrompt bool
+
 	colorEnabled bool
 	once         sync.Once
+
+	// terminal size, kept current by watchResize where supported
+	sizeOnce sync.Once
+	sizeMu   sync.Mutex
+	width    int
+	height   int
+	watched  bool
+
+	// pager started by StartPager, restored by StopPager
+	pagerCommand string
+	pagerProcess *exec.Cmd
+	pagerIn      io.WriteCloser
+	pagerOut     io.Writer
 }
 
 // System returns IOStreams bound to the current process standard streams and
@@ -49,9 +66,17 @@ func System() *IOStreams {
 }
 
 // CanPrompt reports whether stdin is a TTY and therefore suitable for
-// interactive prompts.
+// interactive prompts, and prompting was not disabled with SetNeverPrompt.
 func (s *IOStreams) CanPrompt() bool {
-	return s != nil && s.isStdinTTY
+	return s != nil && s.isStdinTTY && !s.neverPrompt
+}
+
+// SetNeverPrompt disables interactive prompts even on a terminal.
+func (s *IOStreams) SetNeverPrompt(never bool) {
+	if s == nil {
+		return
+	}
+	s.neverPrompt = never
 }
 
 // ColorEnabled returns true when ANSI colour output should be rendered. The
@@ -85,6 +110,56 @@ func (s *IOStreams) IsStderrTTY() bool {
 	return s != nil && s.isStderrTTY
 }
 
+// TerminalSize returns the width and height of the terminal attached to
+// stdout. The size is read once and refreshed when the terminal is resized
+// (SIGWINCH); without a terminal it falls back to $COLUMNS and $LINES, then
+// 80x24.
+func (s *IOStreams) TerminalSize() (width, height int) {
+	if s != nil && s.isStdoutTTY {
+		if f, ok := s.Out.(*os.File); ok {
+			s.sizeOnce.Do(func() {
+				s.readSize(f)
+				s.watched = watchResize(func() { s.readSize(f) })
+			})
+			if !s.watched {
+				s.readSize(f)
+			}
+			s.sizeMu.Lock()
+			width, height = s.width, s.height
+			s.sizeMu.Unlock()
+			if width > 0 && height > 0 {
+				return width, height
+			}
+		}
+	}
+	return envSize("COLUMNS", 80), envSize("LINES", 24)
+}
+
+// TerminalWidth returns the width reported by TerminalSize.
+func (s *IOStreams) TerminalWidth() int {
+	width, _ := s.TerminalSize()
+	return width
+}
+
+// readSize records the current size of the terminal f
+func (s *IOStreams) readSize(f *os.File) {
+	width, height, err := term.GetSize(int(f.Fd()))
+	if err != nil {
+		return
+	}
+	s.sizeMu.Lock()
+	s.width, s.height = width, height
+	s.sizeMu.Unlock()
+}
+
+// envSize reads a positive size from the environment variable name
+func envSize(name string, fallback int) int {
+	if n, err := strconv.Atoi(os.Getenv(name)); err == nil && n > 0 {
+		return n
+	}
+	return fallback
+}
+
 // ANSI escape sequences for alternate screen buffer
 const (
 	enterAltScreen = "\x1b[?1049h"
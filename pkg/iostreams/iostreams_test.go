@@ -88,3 +88,51 @@ func TestAlternateScreenBuffer(t *testing.T) {
 		ios.ClearScreen()
 	})
 }
+
+func TestTerminalSizeFallback(t *testing.T) {
+	ios := &IOStreams{Out: &bytes.Buffer{}}
+
+	t.Setenv("COLUMNS", "")
+	t.Setenv("LINES", "")
+	if w, h := ios.TerminalSize(); w != 80 || h != 24 {
+		t.Errorf("TerminalSize() = %dx%d, want 80x24", w, h)
+	}
+
+	t.Setenv("COLUMNS", "132")
+	t.Setenv("LINES", "50")
+	if w, h := ios.TerminalSize(); w != 132 || h != 50 {
+		t.Errorf("TerminalSize() = %dx%d, want 132x50 from COLUMNS and LINES", w, h)
+	}
+}
+
+func TestPager(t *testing.T) {
+	t.Run("no-op when not TTY", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		ios := &IOStreams{Out: buf}
+		ios.SetPager("less")
+		if err := ios.StartPager(); err != nil {
+			t.Fatalf("StartPager() error = %v", err)
+		}
+		defer ios.StopPager()
+		if ios.Out != buf {
+			t.Error("expected stdout to stay unpaged off a terminal")
+		}
+	})
+
+	t.Run("pipes output through the pager", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		ios := &IOStreams{Out: buf, ErrOut: &bytes.Buffer{}, isStdoutTTY: true}
+		ios.SetPager("tr a-z A-Z")
+		if err := ios.StartPager(); err != nil {
+			t.Fatalf("StartPager() error = %v", err)
+		}
+		_, _ = ios.Out.Write([]byte("paged\n"))
+		ios.StopPager()
+		if ios.Out != buf {
+			t.Error("expected StopPager to restore stdout")
+		}
+		if got := buf.String(); got != "PAGED\n" {
+			t.Errorf("output = %q, want %q", got, "PAGED\n")
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package iostreams
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// SetPager sets the command that StartPager runs; "" or "cat" disables paging.
+func (s *IOStreams) SetPager(cmd string) {
+	if s == nil {
+		return
+	}
+	s.pagerCommand = cmd
+}
+
+// StartPager pipes stdout through the configured pager until StopPager is
+// called. It is a no-op when stdout is not a terminal or no pager is set.
+func (s *IOStreams) StartPager() error {
+	if s == nil || !s.isStdoutTTY || s.pagerProcess != nil {
+		return nil
+	}
+	args := strings.Fields(s.pagerCommand)
+	if len(args) == 0 || args[0] == "cat" {
+		return nil
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env = os.Environ()
+	// Colours pass through and short output does not wait for a keypress
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+	cmd.Stdout = s.Out
+	cmd.Stderr = s.ErrOut
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("start pager: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start pager %q: %w", args[0], err)
+	}
+
+	s.pagerProcess = cmd
+	s.pagerOut = s.Out
+	s.pagerIn = stdin
+	s.Out = &pagerWriter{stdin}
+	return nil
+}
+
+// StopPager closes the pager's input and waits for the user to quit it.
+func (s *IOStreams) StopPager() {
+	if s == nil || s.pagerProcess == nil {
+		return
+	}
+	_ = s.pagerIn.Close()
+	_ = s.pagerProcess.Wait()
+	s.Out = s.pagerOut
+	s.pagerProcess, s.pagerIn, s.pagerOut = nil, nil, nil
+}
+
+// pagerWriter drops output once the user has quit the pager, so a closed
+// pipe is not reported as a command failure
+type pagerWriter struct {
+	w io.Writer
+}
+
+func (p *pagerWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	if err != nil && (errors.Is(err, syscall.EPIPE) || errors.Is(err, os.ErrClosed)) {
+		return len(b), nil
+	}
+	return n, err
+}
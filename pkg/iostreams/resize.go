@@ -0,0 +1,22 @@
+//go:build !windows
+
+package iostreams
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// watchResize calls refresh whenever the terminal is resized and reports
+// that it will
+func watchResize(refresh func()) bool {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGWINCH)
+	go func() {
+		for range ch {
+			refresh()
+		}
+	}()
+	return true
+}
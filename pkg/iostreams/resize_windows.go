@@ -0,0 +1,7 @@
+package iostreams
+
+// watchResize reports that resizes are not signalled on Windows, so the size
+// is read on every call instead
+func watchResize(func()) bool {
+	return false
+}
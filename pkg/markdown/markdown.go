@@ -0,0 +1,243 @@
+// Package markdown renders the markdown of PR descriptions and comments for
+// reading in a terminal: headings are styled, list markers become bullets,
+// code blocks are indented, links show their target and paragraphs wrap to
+// the terminal width.
+//
+// It handles the subset of CommonMark that Bitbucket users write; anything
+// it does not recognise, such as tables and HTML, is passed through as is.
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ghoseb/bb/pkg/text"
+)
+
+const (
+	ansiBold      = "\x1b[1m"
+	ansiItalic    = "\x1b[3m"
+	ansiUnderline = "\x1b[4m"
+	ansiStrike    = "\x1b[9m"
+	ansiDim       = "\x1b[2m"
+	ansiCyan      = "\x1b[36m"
+	ansiMagenta   = "\x1b[35m"
+	ansiReset     = "\x1b[0m"
+
+	codeIndent = "    "
+)
+
+var (
+	heading     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	fence       = regexp.MustCompile("^\\s*(```|~~~)")
+	rule        = regexp.MustCompile(`^\s*(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	bullet      = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	ordered     = regexp.MustCompile(`^(\s*)(\d+)[.)]\s+(.*)$`)
+	task        = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+	quote       = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	tableRow    = regexp.MustCompile(`^\s*\|`)
+	ansiEscape  = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+	inlineToken = regexp.MustCompile("`[^`]+`|\\*\\*[^*]+\\*\\*|__[^_]+__|~~[^~]+~~|\\*[^*\\s][^*]*\\*|\\b_[^_\\s][^_]*_\\b|!?\\[[^\\]]*\\]\\([^)\\s]+\\)")
+)
+
+// Render formats src for a terminal width columns wide, with ANSI styles
+// when color is set. Control sequences in src are removed first, so the only
+// escapes in the result are the renderer's own.
+func Render(src string, width int, color bool) string {
+	r := &renderer{width: max(width, 20), color: color}
+	r.render(strings.Split(text.Sanitize(src), "\n"))
+	return strings.TrimRight(r.out.String(), "\n")
+}
+
+type renderer struct {
+	width int
+	color bool
+	out   strings.Builder
+
+	para  []string // lines of the paragraph being collected
+	blank bool     // the last block ended with a blank line
+}
+
+func (r *renderer) render(lines []string) {
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			r.flush()
+			r.gap()
+		case fence.MatchString(line):
+			r.flush()
+			marker := fence.FindStringSubmatch(line)[1]
+			i++
+			for ; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), marker); i++ {
+				r.line(codeIndent + r.style(lines[i], ansiCyan))
+			}
+		case heading.MatchString(line):
+			r.flush()
+			m := heading.FindStringSubmatch(line)
+			r.heading(len(m[1]), m[2])
+		case rule.MatchString(line):
+			r.flush()
+			r.line(r.style(strings.Repeat("─", min(r.width, 40)), ansiDim))
+		case quote.MatchString(line):
+			r.flush()
+			var inner []string
+			for ; i < len(lines) && quote.MatchString(lines[i]); i++ {
+				inner = append(inner, quote.FindStringSubmatch(lines[i])[1])
+			}
+			i--
+			sub := &renderer{width: r.width - 2, color: r.color}
+			sub.render(inner)
+			for _, l := range strings.Split(strings.TrimRight(sub.out.String(), "\n"), "\n") {
+				r.line(r.style("│ ", ansiDim) + l)
+			}
+		case bullet.MatchString(line):
+			r.flush()
+			m := bullet.FindStringSubmatch(line)
+			marker := "• "
+			text := m[2]
+			if t := task.FindStringSubmatch(text); t != nil {
+				marker, text = "☐ ", t[2]
+				if t[1] != " " {
+					marker = "☑ "
+				}
+			}
+			r.item(m[1], marker, text)
+		case ordered.MatchString(line):
+			r.flush()
+			m := ordered.FindStringSubmatch(line)
+			r.item(m[1], m[2]+". ", m[3])
+		case tableRow.MatchString(line):
+			r.flush()
+			r.line(line)
+		default:
+			r.para = append(r.para, strings.TrimSpace(line))
+		}
+	}
+	r.flush()
+}
+
+// flush writes the collected paragraph, wrapped
+func (r *renderer) flush() {
+	if len(r.para) == 0 {
+		return
+	}
+	text := r.inline(strings.Join(r.para, " "))
+	r.para = nil
+	for _, l := range wrap(text, r.width) {
+		r.line(l)
+	}
+}
+
+func (r *renderer) heading(level int, text string) {
+	r.gap()
+	text = r.inline(text)
+	if r.color {
+		style := ansiBold
+		if level <= 2 {
+			style = ansiBold + ansiMagenta
+		}
+		r.line(style + ansiEscape.ReplaceAllString(text, "") + ansiReset)
+	} else {
+		r.line(text)
+	}
+	if level <= 2 {
+		underline := "═"
+		if level == 2 {
+			underline = "─"
+		}
+		r.line(r.style(strings.Repeat(underline, min(visibleWidth(text), r.width)), ansiDim))
+	}
+}
+
+// item writes a list item with its marker, wrapping the text under itself
+func (r *renderer) item(indent, marker, text string) {
+	prefix := strings.Repeat(" ", utf8.RuneCountInString(strings.ReplaceAll(indent, "\t", "    ")))
+	hang := prefix + strings.Repeat(" ", utf8.RuneCountInString(marker))
+	for i, l := range wrap(r.inline(text), r.width-utf8.RuneCountInString(hang)) {
+		if i == 0 {
+			r.line(prefix + r.style(marker, ansiCyan) + l)
+		} else {
+			r.line(hang + l)
+		}
+	}
+}
+
+// gap separates blocks with one blank line
+func (r *renderer) gap() {
+	if r.out.Len() > 0 && !r.blank {
+		r.out.WriteString("\n")
+		r.blank = true
+	}
+}
+
+func (r *renderer) line(s string) {
+	r.out.WriteString(s)
+	r.out.WriteString("\n")
+	r.blank = false
+}
+
+func (r *renderer) style(s, code string) string {
+	if !r.color {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// inline replaces emphasis, code spans and links in text with their styled
+// rendering
+func (r *renderer) inline(text string) string {
+	return inlineToken.ReplaceAllStringFunc(text, func(tok string) string {
+		switch {
+		case strings.HasPrefix(tok, "`"):
+			return r.style(strings.Trim(tok, "`"), ansiCyan)
+		case strings.HasPrefix(tok, "**"), strings.HasPrefix(tok, "__"):
+			return r.style(tok[2:len(tok)-2], ansiBold)
+		case strings.HasPrefix(tok, "~~"):
+			return r.style(tok[2:len(tok)-2], ansiStrike)
+		case strings.HasPrefix(tok, "*"), strings.HasPrefix(tok, "_"):
+			return r.style(tok[1:len(tok)-1], ansiItalic)
+		}
+		// [text](url) or ![alt](url)
+		label, url, _ := strings.Cut(strings.TrimPrefix(tok, "!")[1:], "](")
+		url = strings.TrimSuffix(url, ")")
+		if label == "" || label == url {
+			return r.style(url, ansiUnderline)
+		}
+		return r.style(label, ansiUnderline) + " " + r.style("("+url+")", ansiDim)
+	})
+}
+
+// wrap breaks text at spaces into lines of at most width visible columns;
+// words longer than width get a line of their own
+func wrap(text string, width int) []string {
+	width = max(width, 10)
+	var lines []string
+	var cur strings.Builder
+	curWidth := 0
+	for _, word := range strings.Fields(text) {
+		w := visibleWidth(word)
+		if curWidth > 0 && curWidth+1+w > width {
+			lines = append(lines, cur.String())
+			cur.Reset()
+			curWidth = 0
+		}
+		if curWidth > 0 {
+			cur.WriteByte(' ')
+			curWidth++
+		}
+		cur.WriteString(word)
+		curWidth += w
+	}
+	if cur.Len() > 0 {
+		lines = append(lines, cur.String())
+	}
+	return lines
+}
+
+// visibleWidth returns the terminal columns s occupies outside ANSI escape
+// sequences
+func visibleWidth(s string) int {
+	return text.Width(ansiEscape.ReplaceAllString(s, ""))
+}
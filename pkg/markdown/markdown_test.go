@@ -0,0 +1,70 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	src := "## Summary\n" +
+		"Fixes the **login** flow; see [the docs](https://example.com/docs) and `auth.go`.\n" +
+		"\n" +
+		"- first\n" +
+		"- [x] done\n" +
+		"- [ ] todo\n" +
+		"1. one\n" +
+		"\n" +
+		"```go\n" +
+		"x := 1\n" +
+		"```\n" +
+		"> quoted *text*\n" +
+		"---\n"
+	want := "Summary\n" +
+		"───────\n" +
+		"Fixes the login flow; see the docs (https://example.com/docs) and auth.go.\n" +
+		"\n" +
+		"• first\n" +
+		"☑ done\n" +
+		"☐ todo\n" +
+		"1. one\n" +
+		"\n" +
+		"    x := 1\n" +
+		"│ quoted text\n" +
+		strings.Repeat("─", 40)
+
+	if got := Render(src, 80, false); got != want {
+		t.Errorf("Render =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderWraps(t *testing.T) {
+	got := Render("- one two three four five six seven eight nine ten", 24, true)
+	lines := strings.Split(got, "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", got)
+	}
+	for _, l := range lines {
+		if w := visibleWidth(l); w > 24 {
+			t.Errorf("line %q is %d columns wide", l, w)
+		}
+	}
+	if !strings.HasPrefix(lines[1], "  ") {
+		t.Errorf("continuation %q should hang under the item text", lines[1])
+	}
+}
+
+func TestRenderColor(t *testing.T) {
+	if got := Render("use `go test`", 80, true); got != "use "+ansiCyan+"go test"+ansiReset {
+		t.Errorf("got %q", got)
+	}
+	if got := Render("snake_case_name", 80, true); got != "snake_case_name" {
+		t.Errorf("underscores inside words should not be italic, got %q", got)
+	}
+}
+
+func TestRenderStripsEscapes(t *testing.T) {
+	got := Render("**hi**\x1b[2J\x1b]0;pwned\a there\r\n", 80, true)
+	if want := ansiBold + "hi" + ansiReset + " there"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,22 @@
+package prompter
+
+import "errors"
+
+// ErrNoInput is returned by every prompt of a Disabled prompter.
+var ErrNoInput = errors.New("input required, but prompts are disabled (--no-input, BB_NO_INPUT, or stdin is not a terminal)")
+
+// Disabled returns a Prompter whose prompts fail with ErrNoInput instead of
+// waiting for input that will never come, e.g. in CI. Commands check for
+// missing flags first so their errors can name them; this catches the rest.
+func Disabled() Prompter {
+	return disabledPrompter{}
+}
+
+type disabledPrompter struct{}
+
+func (disabledPrompter) Input(string, ...InputOption) (string, error)       { return "", ErrNoInput }
+func (disabledPrompter) Password(string) (string, error)                    { return "", ErrNoInput }
+func (disabledPrompter) Confirm(string, bool) (bool, error)                 { return false, ErrNoInput }
+func (disabledPrompter) Select(string, []string) (int, error)               { return 0, ErrNoInput }
+func (disabledPrompter) MultiSelect(string, []string, []int) ([]int, error) { return nil, ErrNoInput }
+func (disabledPrompter) FuzzySelect(string, []string) (int, error)          { return 0, ErrNoInput }
@@ -0,0 +1,50 @@
+package prompter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Edit opens editorCmd on a temporary file seeded with initial and returns the
+// saved contents with trailing whitespace removed. pattern names the temporary
+// file (as in os.CreateTemp) so editors can pick suitable syntax highlighting.
+//
+// editorCmd may include arguments, e.g. "code --wait"; the file path is appended.
+func Edit(editorCmd, pattern, initial string, in io.Reader, out, errOut io.Writer) (string, error) {
+	args := strings.Fields(editorCmd)
+	if len(args) == 0 {
+		return "", fmt.Errorf("no editor configured")
+	}
+
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf("create temp file: %w", err)
+	}
+	path := f.Name()
+	defer func() { _ = os.Remove(path) }()
+
+	if _, err := f.WriteString(initial); err != nil {
+		_ = f.Close()
+		return "", fmt.Errorf("write temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("write temp file: %w", err)
+	}
+
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	cmd.Stdin = in
+	cmd.Stdout = out
+	cmd.Stderr = errOut
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("run editor %s: %w", args[0], err)
+	}
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read temp file: %w", err)
+	}
+	return strings.TrimRight(string(edited), " \t\r\n"), nil
+}
@@ -0,0 +1,228 @@
+package prompter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxListed caps the matches shown at once by FuzzySelect
+const maxListed = 15
+
+// FuzzySelect shows a filterable picker on a terminal: typing narrows the
+// options to fuzzy matches, the arrow keys move and enter chooses. Otherwise
+// each line entered filters the options, a number chooses among the listed
+// matches, and a filter matching exactly one option chooses it.
+func (p *stdPrompter) FuzzySelect(prompt string, options []string) (int, error) {
+	if len(options) == 0 {
+		return 0, fmt.Errorf("no options to select from")
+	}
+	if in, ok := p.menuTerminal(); ok {
+		fd := &finder{prompt: prompt, options: options, matches: fuzzyFilter("", options)}
+		if err := runRaw(in, p.errOut, fd); err != nil {
+			return 0, err
+		}
+		return fd.matches[fd.cursor], nil
+	}
+
+	listed := fuzzyFilter("", options)
+	_, _ = fmt.Fprintln(p.errOut, prompt)
+	listMatches(p.errOut, options, listed)
+
+	reader := bufio.NewReader(p.in)
+	for {
+		_, _ = fmt.Fprint(p.errOut, "Choose a number, or type to filter: ")
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return 0, err
+		}
+		answer := strings.TrimSpace(line)
+
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= min(len(listed), maxListed) {
+			return listed[n-1], nil
+		}
+		for i, option := range options {
+			if answer == option {
+				return i, nil
+			}
+		}
+
+		matches := fuzzyFilter(answer, options)
+		switch len(matches) {
+		case 0:
+			_, _ = fmt.Fprintf(p.errOut, "No match for %q\n", answer)
+		case 1:
+			return matches[0], nil
+		default:
+			listed = matches
+			listMatches(p.errOut, options, listed)
+		}
+	}
+}
+
+// listMatches prints the first maxListed matches numbered from 1
+func listMatches(out io.Writer, options []string, matches []int) {
+	for n, i := range matches {
+		if n == maxListed {
+			_, _ = fmt.Fprintf(out, "  ... %d more; type to filter\n", len(matches)-maxListed)
+			break
+		}
+		_, _ = fmt.Fprintf(out, "  %d) %s\n", n+1, options[i])
+	}
+}
+
+// fuzzyFilter returns the indexes of the options matching query, best first.
+// Every space-separated term of the query must match.
+func fuzzyFilter(query string, options []string) []int {
+	terms := strings.Fields(strings.ToLower(query))
+	type match struct{ index, score int }
+	var matches []match
+	for i, option := range options {
+		total, ok := 0, true
+		for _, term := range terms {
+			score, found := fuzzyScore(term, strings.ToLower(option))
+			if !found {
+				ok = false
+				break
+			}
+			total += score
+		}
+		if ok {
+			matches = append(matches, match{i, total})
+		}
+	}
+	sort.SliceStable(matches, func(a, b int) bool { return matches[a].score > matches[b].score })
+
+	indexes := make([]int, len(matches))
+	for n, m := range matches {
+		indexes[n] = m.index
+	}
+	return indexes
+}
+
+// fuzzyScore reports whether the runes of term appear in order in s, scoring
+// runs of consecutive runes and runes at the start of a word higher
+func fuzzyScore(term, s string) (int, bool) {
+	want := []rune(term)
+	text := []rune(s)
+	score, prev, next := 0, -2, 0
+	for i, r := range text {
+		if next == len(want) {
+			break
+		}
+		if r != want[next] {
+			continue
+		}
+		score++
+		if i == prev+1 {
+			score += 4
+		}
+		if i == 0 || !unicode.IsLetter(text[i-1]) && !unicode.IsDigit(text[i-1]) {
+			score += 2
+		}
+		prev = i
+		next++
+	}
+	return score, next == len(want)
+}
+
+// finder is the interactive state of FuzzySelect: the query typed so far,
+// the options matching it and the highlighted match
+type finder struct {
+	prompt  string
+	options []string
+	query   string
+	matches []int
+	cursor  int
+	drawn   int
+}
+
+// run reads keys from in until a match is chosen
+func (fd *finder) run(in io.Reader, out io.Writer) error {
+	fd.draw(out)
+	buf := make([]byte, 8)
+	for {
+		n, err := in.Read(buf)
+		if err != nil {
+			return err
+		}
+		done, err := fd.key(buf[:n])
+		fd.clear(out)
+		if err != nil {
+			return err
+		}
+		if done {
+			_, _ = fmt.Fprintf(out, "%s %s\r\n", fd.prompt, fd.options[fd.matches[fd.cursor]])
+			return nil
+		}
+		fd.draw(out)
+	}
+}
+
+// key applies one keypress and reports whether a match was chosen
+func (fd *finder) key(k []byte) (bool, error) {
+	switch {
+	case len(k) == 0:
+	case string(k) == "\x1b[A" || string(k) == "\x1bOA" || k[0] == 16: // up, ctrl-p
+		if fd.cursor > 0 {
+			fd.cursor--
+		}
+	case string(k) == "\x1b[B" || string(k) == "\x1bOB" || k[0] == 14: // down, ctrl-n
+		if fd.cursor < min(len(fd.matches), maxListed)-1 {
+			fd.cursor++
+		}
+	case k[0] == '\r' || k[0] == '\n':
+		return len(fd.matches) > 0, nil
+	case k[0] == 3 || string(k) == "\x1b":
+		return false, ErrInterrupted
+	case k[0] == 127 || k[0] == 8: // backspace
+		if r := []rune(fd.query); len(r) > 0 {
+			fd.setQuery(string(r[:len(r)-1]))
+		}
+	case k[0] == 21: // ctrl-u
+		fd.setQuery("")
+	case k[0] >= ' ' && k[0] != 127:
+		fd.setQuery(fd.query + string(k))
+	}
+	return false, nil
+}
+
+func (fd *finder) setQuery(query string) {
+	fd.query = query
+	fd.matches = fuzzyFilter(query, fd.options)
+	fd.cursor = 0
+}
+
+func (fd *finder) draw(out io.Writer) {
+	_, _ = fmt.Fprintf(out, "%s %s\r\n", fd.prompt, fd.query)
+	fd.drawn = 1
+	for n, i := range fd.matches {
+		if n == maxListed {
+			_, _ = fmt.Fprintf(out, "  ... %d more\r\n", len(fd.matches)-maxListed)
+			fd.drawn++
+			break
+		}
+		pointer := "  "
+		if n == fd.cursor {
+			pointer = "> "
+		}
+		_, _ = fmt.Fprintf(out, "%s%s\r\n", pointer, fd.options[i])
+		fd.drawn++
+	}
+	if len(fd.matches) == 0 {
+		_, _ = fmt.Fprint(out, "  (no matches)\r\n")
+		fd.drawn++
+	}
+}
+
+// clear erases what draw printed, leaving the cursor where it started
+func (fd *finder) clear(out io.Writer) {
+	for range fd.drawn {
+		_, _ = fmt.Fprint(out, "\x1b[1A\x1b[2K")
+	}
+	fd.drawn = 0
+}
@@ -0,0 +1,168 @@
+package prompter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ErrInterrupted is returned when a menu is cancelled with Ctrl-C or Esc
+var ErrInterrupted = errors.New("selection cancelled")
+
+// menuTerminal returns the terminal to draw an arrow-key menu on, or false
+// when input or output is not one or TERM=dumb asks for plain prompts
+func (p *stdPrompter) menuTerminal() (*os.File, bool) {
+	if os.Getenv("TERM") == "dumb" {
+		return nil, false
+	}
+	in, ok := p.in.(*os.File)
+	if !ok || !term.IsTerminal(int(in.Fd())) {
+		return nil, false
+	}
+	out, ok := p.errOut.(*os.File)
+	if !ok || !term.IsTerminal(int(out.Fd())) {
+		return nil, false
+	}
+	return in, true
+}
+
+// keyReader is an interactive widget reading keypresses from a raw terminal
+type keyReader interface {
+	run(in io.Reader, out io.Writer) error
+}
+
+// runRaw puts the terminal in raw mode for the duration of m.run
+func runRaw(in *os.File, out io.Writer, m keyReader) error {
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = term.Restore(int(in.Fd()), state) }()
+	return m.run(in, out)
+}
+
+// menu is a list navigated with the arrow keys (or j/k). Enter confirms;
+// in a multi-select menu space toggles the option under the cursor.
+type menu struct {
+	prompt   string
+	options  []string
+	multi    bool
+	cursor   int
+	selected []bool
+}
+
+func newMenu(prompt string, options []string, multi bool, defaults []int) *menu {
+	m := &menu{prompt: prompt, options: options, multi: multi, selected: make([]bool, len(options))}
+	for _, i := range defaults {
+		if i >= 0 && i < len(options) {
+			m.selected[i] = true
+		}
+	}
+	if !multi && len(defaults) > 0 && defaults[0] >= 0 && defaults[0] < len(options) {
+		m.cursor = defaults[0]
+	}
+	return m
+}
+
+// run reads keys from in until the choice is confirmed. Lines end in \r\n
+// since the terminal is raw.
+func (m *menu) run(in io.Reader, out io.Writer) error {
+	help := "↑/↓ to move, enter to choose"
+	if m.multi {
+		help = "↑/↓ to move, space to toggle, enter to confirm"
+	}
+	_, _ = fmt.Fprintf(out, "%s  (%s)\r\n", m.prompt, help)
+	m.draw(out)
+
+	buf := make([]byte, 8)
+	for {
+		n, err := in.Read(buf)
+		if err != nil {
+			return err
+		}
+		done, err := m.key(buf[:n])
+		if err != nil {
+			_, _ = fmt.Fprint(out, "\r\n")
+			return err
+		}
+		if done {
+			m.clear(out)
+			_, _ = fmt.Fprintf(out, "\x1b[1A\x1b[2K%s %s\r\n", m.prompt, strings.Join(m.chosenNames(), ", "))
+			return nil
+		}
+		m.clear(out)
+		m.draw(out)
+	}
+}
+
+// key applies one keypress and reports whether the choice was confirmed
+func (m *menu) key(k []byte) (bool, error) {
+	switch {
+	case len(k) == 0:
+	case string(k) == "\x1b[A" || string(k) == "\x1bOA" || k[0] == 'k':
+		m.cursor = (m.cursor + len(m.options) - 1) % len(m.options)
+	case string(k) == "\x1b[B" || string(k) == "\x1bOB" || k[0] == 'j':
+		m.cursor = (m.cursor + 1) % len(m.options)
+	case k[0] == ' ' && m.multi:
+		m.selected[m.cursor] = !m.selected[m.cursor]
+	case k[0] == '\r' || k[0] == '\n':
+		return true, nil
+	case k[0] == 3 || string(k) == "\x1b":
+		return false, ErrInterrupted
+	case k[0] >= '1' && k[0] <= '9':
+		if i := int(k[0] - '1'); i < len(m.options) {
+			m.cursor = i
+		}
+	}
+	return false, nil
+}
+
+func (m *menu) draw(out io.Writer) {
+	for i, option := range m.options {
+		pointer := "  "
+		if i == m.cursor {
+			pointer = "> "
+		}
+		box := ""
+		if m.multi {
+			box = "[ ] "
+			if m.selected[i] {
+				box = "[x] "
+			}
+		}
+		_, _ = fmt.Fprintf(out, "%s%s%s\r\n", pointer, box, option)
+	}
+}
+
+// clear erases the drawn options, leaving the cursor where they started
+func (m *menu) clear(out io.Writer) {
+	for range m.options {
+		_, _ = fmt.Fprint(out, "\x1b[1A\x1b[2K")
+	}
+}
+
+// chosen returns the selected indexes: the cursor for a single choice
+func (m *menu) chosen() []int {
+	if !m.multi {
+		return []int{m.cursor}
+	}
+	indexes := []int{}
+	for i, on := range m.selected {
+		if on {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func (m *menu) chosenNames() []string {
+	var names []string
+	for _, i := range m.chosen() {
+		names = append(names, m.options[i])
+	}
+	return names
+}
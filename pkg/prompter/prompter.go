@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 
 	"golang.org/x/term"
@@ -13,13 +14,24 @@ import (
 // Prompter provides methods for interactive user input
 type Prompter interface {
 	// Input prompts for a text input value
-	Input(prompt string) (string, error)
+	Input(prompt string, opts ...InputOption) (string, error)
 
 	// Password prompts for a password/token (hidden input)
 	Password(prompt string) (string, error)
 
 	// Confirm prompts for a yes/no confirmation
 	Confirm(prompt string, defaultYes bool) (bool, error)
+
+	// Select prompts for a choice among options and returns its index
+	Select(prompt string, options []string) (int, error)
+
+	// MultiSelect prompts for any number of options, starting from the
+	// defaults, and returns the chosen indexes in order
+	MultiSelect(prompt string, options []string, defaults []int) ([]int, error)
+
+	// FuzzySelect prompts for a choice among many options, narrowed by a
+	// fuzzy filter, and returns its index
+	FuzzySelect(prompt string, options []string) (int, error)
 }
 
 // New creates a new prompter using the given input and output streams
@@ -37,17 +49,67 @@ type stdPrompter struct {
 	errOut io.Writer
 }
 
+// InputOption configures an Input prompt.
+type InputOption func(*inputOptions)
+
+type inputOptions struct {
+	defaultValue string
+	validate     func(string) error
+}
+
+// WithDefault shows value in the prompt and returns it when the answer is
+// empty.
+func WithDefault(value string) InputOption {
+	return func(o *inputOptions) { o.defaultValue = value }
+}
+
+// WithValidator checks each answer, after the default is applied; a rejected
+// answer is reported and asked for again.
+func WithValidator(validate func(string) error) InputOption {
+	return func(o *inputOptions) { o.validate = validate }
+}
+
 // Input prompts for text input
-func (p *stdPrompter) Input(prompt string) (string, error) {
-	_, _ = fmt.Fprint(p.errOut, prompt)
+func (p *stdPrompter) Input(prompt string, opts ...InputOption) (string, error) {
+	var o inputOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.defaultValue != "" {
+		prompt = withDefaultLabel(prompt, o.defaultValue)
+	}
 
+	// One reader across attempts so buffered input is not lost
 	reader := bufio.NewReader(p.in)
-	line, err := reader.ReadString('\n')
-	if err != nil {
-		return "", err
+	for {
+		_, _ = fmt.Fprint(p.errOut, prompt)
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			answer = o.defaultValue
+		}
+		if o.validate != nil {
+			if err := o.validate(answer); err != nil {
+				_, _ = fmt.Fprintf(p.errOut, "Invalid input: %v\n", err)
+				continue
+			}
+		}
+		return answer, nil
 	}
+}
 
-	return strings.TrimSpace(line), nil
+// withDefaultLabel shows value before the prompt's trailing colon:
+// "Workspace: " becomes "Workspace [acme]: "
+func withDefaultLabel(prompt, value string) string {
+	label := strings.TrimRight(prompt, " ")
+	if strings.HasSuffix(label, ":") {
+		return fmt.Sprintf("%s [%s]: ", strings.TrimSuffix(label, ":"), value)
+	}
+	return fmt.Sprintf("%s[%s] ", prompt, value)
 }
 
 // Password prompts for hidden password/token input
@@ -101,3 +163,122 @@ func (p *stdPrompter) Confirm(prompt string, defaultYes bool) (bool, error) {
 
 	return defaultYes, nil
 }
+
+// Select shows an arrow-key menu on a terminal. Otherwise it lists the
+// options numbered from 1 and prompts until a valid number or an exact
+// option is entered.
+func (p *stdPrompter) Select(prompt string, options []string) (int, error) {
+	if len(options) == 0 {
+		return 0, fmt.Errorf("no options to select from")
+	}
+	if in, ok := p.menuTerminal(); ok {
+		m := newMenu(prompt, options, false, nil)
+		if err := runRaw(in, p.errOut, m); err != nil {
+			return 0, err
+		}
+		return m.cursor, nil
+	}
+
+	_, _ = fmt.Fprintln(p.errOut, prompt)
+	for i, option := range options {
+		_, _ = fmt.Fprintf(p.errOut, "  %d) %s\n", i+1, option)
+	}
+
+	// One reader across attempts so buffered input is not lost
+	reader := bufio.NewReader(p.in)
+	for {
+		_, _ = fmt.Fprintf(p.errOut, "Choose 1-%d: ", len(options))
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return 0, err
+		}
+		answer := strings.TrimSpace(line)
+
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			return n - 1, nil
+		}
+		for i, option := range options {
+			if answer == option {
+				return i, nil
+			}
+		}
+		_, _ = fmt.Fprintf(p.errOut, "Invalid choice %q\n", answer)
+	}
+}
+
+// MultiSelect shows an arrow-key menu on a terminal. Otherwise it lists the
+// options numbered from 1, marking the defaults, and prompts for numbers
+// separated by commas or spaces; an empty answer keeps the defaults and
+// "none" chooses nothing.
+func (p *stdPrompter) MultiSelect(prompt string, options []string, defaults []int) ([]int, error) {
+	if len(options) == 0 {
+		return nil, fmt.Errorf("no options to select from")
+	}
+	if in, ok := p.menuTerminal(); ok {
+		m := newMenu(prompt, options, true, defaults)
+		if err := runRaw(in, p.errOut, m); err != nil {
+			return nil, err
+		}
+		return m.chosen(), nil
+	}
+
+	marked := newMenu(prompt, options, true, defaults)
+	_, _ = fmt.Fprintln(p.errOut, prompt)
+	for i, option := range options {
+		mark := " "
+		if marked.selected[i] {
+			mark = "*"
+		}
+		_, _ = fmt.Fprintf(p.errOut, " %s%d) %s\n", mark, i+1, option)
+	}
+
+	reader := bufio.NewReader(p.in)
+	for {
+		_, _ = fmt.Fprintf(p.errOut, "Choose from 1-%d, separated by commas (enter keeps the marked ones, none for none): ", len(options))
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		answer := strings.TrimSpace(line)
+
+		switch answer {
+		case "":
+			return marked.chosen(), nil
+		case "none":
+			return []int{}, nil
+		}
+
+		chosen := make([]bool, len(options))
+		valid := true
+		for _, field := range strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' }) {
+			n, err := strconv.Atoi(field)
+			if err != nil || n < 1 || n > len(options) {
+				_, _ = fmt.Fprintf(p.errOut, "Invalid choice %q\n", field)
+				valid = false
+				break
+			}
+			chosen[n-1] = true
+		}
+		if !valid {
+			continue
+		}
+		indexes := []int{}
+		for i, on := range chosen {
+			if on {
+				indexes = append(indexes, i)
+			}
+		}
+		return indexes, nil
+	}
+}
+
+// Required is a validator for Input that rejects empty answers, naming what
+// was asked for.
+func Required(what string) func(string) error {
+	return func(answer string) error {
+		if answer == "" {
+			return fmt.Errorf("%s is required", what)
+		}
+		return nil
+	}
+}
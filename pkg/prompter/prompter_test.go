@@ -2,6 +2,11 @@ package prompter
 
 import (
 	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -43,6 +48,48 @@ func TestInputWithWhitespace(t *testing.T) {
 	}
 }
 
+func TestInputDefaultAndValidator(t *testing.T) {
+	notAdmin := func(answer string) error {
+		if answer == "admin" {
+			return errors.New("admin is reserved")
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name       string
+		input      string
+		opts       []InputOption
+		want       string
+		wantPrompt string
+	}{
+		{"default_on_empty", "\n", []InputOption{WithDefault("acme")}, "acme", "Workspace [acme]: "},
+		{"answer_over_default", "other\n", []InputOption{WithDefault("acme")}, "other", "Workspace [acme]: "},
+		{"required_retries", "\nacme\n", []InputOption{WithValidator(Required("workspace"))},
+			"acme", "Workspace: Invalid input: workspace is required\nWorkspace: "},
+		{"validator_sees_default", "admin\n\n", []InputOption{WithDefault("acme"), WithValidator(notAdmin)},
+			"acme", "Workspace [acme]: Invalid input: admin is reserved\nWorkspace [acme]: "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errOut := &bytes.Buffer{}
+			p := New(strings.NewReader(tt.input), &bytes.Buffer{}, errOut)
+
+			got, err := p.Input("Workspace: ", tt.opts...)
+			if err != nil {
+				t.Fatalf("Input: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if errOut.String() != tt.wantPrompt {
+				t.Errorf("prompt = %q, want %q", errOut.String(), tt.wantPrompt)
+			}
+		})
+	}
+}
+
 func TestPasswordFallback(t *testing.T) {
 	// When input is not a TTY (e.g., pipe), Password falls back to regular input
 	in := strings.NewReader("secret-token\n")
@@ -149,3 +196,217 @@ func TestConfirmInvalidInput(t *testing.T) {
 		t.Errorf("got %v, want %v (invalid input should use default)", result, true)
 	}
 }
+
+func TestEdit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the editor")
+	}
+
+	// The "editor" appends a line to the file it is given
+	script := filepath.Join(t.TempDir(), "editor.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho 'edited' >> \"$1\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Edit(script, "bb-*.md", "initial\n", strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("Edit: %v", err)
+	}
+	if want := "initial\nedited"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"by_number", "2\n", 1, false},
+		{"by_name", "gamma\n", 2, false},
+		{"retry_after_invalid", "9\nx\n1\n", 0, false},
+		{"eof", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errOut := &bytes.Buffer{}
+			p := New(strings.NewReader(tt.input), &bytes.Buffer{}, errOut)
+
+			got, err := p.Select("Pick one:", []string{"alpha", "beta", "gamma"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+			if !strings.Contains(errOut.String(), "  2) beta\n") {
+				t.Errorf("options not listed: %q", errOut.String())
+			}
+		})
+	}
+}
+
+func TestMultiSelect(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []int
+	}{
+		{"keeps_defaults", "\n", []int{0, 2}},
+		{"by_numbers", "3, 2\n", []int{1, 2}},
+		{"space_separated", "1 3\n", []int{0, 2}},
+		{"none", "none\n", []int{}},
+		{"retry_after_invalid", "4\n2\n", []int{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errOut := &bytes.Buffer{}
+			p := New(strings.NewReader(tt.input), &bytes.Buffer{}, errOut)
+
+			got, err := p.MultiSelect("Pick some:", []string{"alpha", "beta", "gamma"}, []int{0, 2})
+			if err != nil {
+				t.Fatalf("MultiSelect: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if !strings.Contains(errOut.String(), " *1) alpha\n") || !strings.Contains(errOut.String(), "  2) beta\n") {
+				t.Errorf("options not listed with defaults marked: %q", errOut.String())
+			}
+		})
+	}
+}
+
+func TestMenuKeys(t *testing.T) {
+	options := []string{"alpha", "beta", "gamma"}
+
+	single := newMenu("Pick one:", options, false, nil)
+	if err := single.run(strings.NewReader("\x1b[B"), &bytes.Buffer{}); err == nil {
+		t.Fatal("expected EOF before enter")
+	}
+	if _, err := single.key([]byte("\x1b[A")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := single.key([]byte("\x1b[A")); err != nil {
+		t.Fatal(err)
+	}
+	if got := single.chosen(); !slices.Equal(got, []int{2}) {
+		t.Errorf("up from the top wraps to the bottom: got %v", got)
+	}
+
+	multi := newMenu("Pick some:", options, true, []int{0})
+	for _, k := range []string{"j", " ", "j", " ", "k", " "} {
+		if _, err := multi.key([]byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	done, err := multi.key([]byte("\r"))
+	if err != nil || !done {
+		t.Fatalf("enter: done=%v err=%v", done, err)
+	}
+	if got := multi.chosen(); !slices.Equal(got, []int{0, 2}) {
+		t.Errorf("got %v, want [0 2]", got)
+	}
+
+	if _, err := multi.key([]byte{3}); !errors.Is(err, ErrInterrupted) {
+		t.Errorf("ctrl-c: got %v, want ErrInterrupted", err)
+	}
+}
+
+func TestFuzzyFilter(t *testing.T) {
+	options := []string{"web-frontend", "backend-api", "infra", "api-gateway"}
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"", []int{0, 1, 2, 3}},
+		{"api", []int{3, 1}}, // a word start beats the same run later
+		{"bkapi", []int{1}},
+		{"API", []int{3, 1}},
+		{"end fro", []int{0}},
+		{"zzz", []int{}},
+	}
+	for _, tt := range tests {
+		if got := fuzzyFilter(tt.query, options); !slices.Equal(got, tt.want) {
+			t.Errorf("fuzzyFilter(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestFuzzySelect(t *testing.T) {
+	options := []string{"web-frontend", "backend-api", "infra", "api-gateway"}
+
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{"by_number", "3\n", 2},
+		{"by_name", "infra\n", 2},
+		{"single_match", "gate\n", 3},
+		{"number_among_matches", "api\n2\n", 1},
+		{"retry_after_no_match", "zzz\ninf\n", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errOut := &bytes.Buffer{}
+			p := New(strings.NewReader(tt.input), &bytes.Buffer{}, errOut)
+
+			got, err := p.FuzzySelect("Repository:", options)
+			if err != nil {
+				t.Fatalf("FuzzySelect: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFinderKeys(t *testing.T) {
+	fd := &finder{prompt: "Repository:", options: []string{"web-frontend", "backend-api", "api-gateway"}}
+	fd.setQuery("")
+
+	out := &bytes.Buffer{}
+	if err := fd.run(strings.NewReader("x"), out); err == nil {
+		t.Fatal("expected EOF before enter")
+	}
+	for _, k := range []string{"\x7f", "a", "p", "i", "\x1b[B"} {
+		if _, err := fd.key([]byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if fd.query != "api" {
+		t.Errorf("query = %q, want %q", fd.query, "api")
+	}
+	done, err := fd.key([]byte("\r"))
+	if err != nil || !done {
+		t.Fatalf("enter: done=%v err=%v", done, err)
+	}
+	if got := fd.options[fd.matches[fd.cursor]]; got != "backend-api" {
+		t.Errorf("chose %q, want backend-api", got)
+	}
+
+	fd.setQuery("zzz")
+	if done, _ := fd.key([]byte("\r")); done {
+		t.Error("enter with no matches should not choose")
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	p := Disabled()
+	if _, err := p.Input("Name: "); !errors.Is(err, ErrNoInput) {
+		t.Errorf("Input: got %v, want ErrNoInput", err)
+	}
+	if _, err := p.Confirm("Continue?", true); !errors.Is(err, ErrNoInput) {
+		t.Errorf("Confirm: got %v, want ErrNoInput", err)
+	}
+	if _, err := p.FuzzySelect("Repository:", []string{"api"}); !errors.Is(err, ErrNoInput) {
+		t.Errorf("FuzzySelect: got %v, want ErrNoInput", err)
+	}
+}
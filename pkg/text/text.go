@@ -0,0 +1,240 @@
+// Package text prepares untrusted text, such as PR titles and comment bodies,
+// for printing to a terminal: control sequences that could move the cursor,
+// clear the screen, rewrite the window title or reorder what is displayed are
+// removed, and widths are measured in terminal columns rather than runes.
+package text
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Sanitize removes terminal control sequences from s: ANSI escape sequences
+// (CSI, OSC, DCS and the like), C0 and C1 control characters other than
+// newline and tab, and the Unicode bidirectional overrides that make text
+// display in a different order than it reads. CRLF line endings become LF.
+func Sanitize(s string) string {
+	if isClean(s) {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\x1b':
+			i = skipEscape(runes, i)
+		case r == '\x9b': // C1 CSI
+			i = skipCSI(runes, i+1)
+		case r == '\x9d', r == '\x90', r == '\x98', r == '\x9e', r == '\x9f': // C1 OSC, DCS, SOS, PM, APC
+			i = skipString(runes, i+1)
+		case r == '\n', r == '\t':
+			b.WriteRune(r)
+		case isControl(r), isBidi(r):
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Line is Sanitize for text shown on a single line, such as a title or a
+// table cell: newlines and tabs become spaces.
+func Line(s string) string {
+	s = Sanitize(s)
+	if !strings.ContainsAny(s, "\n\t") {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// Width returns how many terminal columns s occupies: East Asian wide
+// characters and most emoji take two, combining marks and other zero-width
+// characters none. s is assumed to be sanitized.
+func Width(s string) int {
+	n := 0
+	for _, r := range s {
+		n += RuneWidth(r)
+	}
+	return n
+}
+
+// RuneWidth returns how many terminal columns r occupies
+func RuneWidth(r rune) int {
+	switch {
+	case r < 0x20 || (r >= 0x7f && r < 0xa0):
+		return 0
+	case r < 0x300:
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case isWide(r):
+		return 2
+	}
+	return 1
+}
+
+// isClean reports whether s has nothing for Sanitize to remove, so the
+// common case does not allocate
+func isClean(s string) bool {
+	for _, r := range s {
+		if (isControl(r) && r != '\n' && r != '\t') || isBidi(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isControl reports whether r is a C0 or C1 control character or DEL
+func isControl(r rune) bool {
+	return r < 0x20 || (r >= 0x7f && r < 0xa0)
+}
+
+// isBidi reports whether r is a bidirectional embedding, override or
+// isolate control
+func isBidi(r rune) bool {
+	return (r >= 0x202a && r <= 0x202e) || (r >= 0x2066 && r <= 0x2069)
+}
+
+// skipEscape returns the index of the last rune of the escape sequence
+// starting at runes[i], an ESC
+func skipEscape(runes []rune, i int) int {
+	if i+1 >= len(runes) {
+		return i
+	}
+	switch runes[i+1] {
+	case '[':
+		return skipCSI(runes, i+2)
+	case ']', 'P', 'X', '^', '_':
+		return skipString(runes, i+2)
+	}
+	// Two-character sequences, and those with intermediate bytes such as
+	// the character set designations "ESC ( B"
+	j := i + 1
+	for j < len(runes) && runes[j] >= 0x20 && runes[j] <= 0x2f {
+		j++
+	}
+	if j < len(runes) && runes[j] >= 0x30 && runes[j] <= 0x7e {
+		return j
+	}
+	return j - 1
+}
+
+// skipCSI returns the index of the final byte of the control sequence whose
+// parameters start at runes[i]
+func skipCSI(runes []rune, i int) int {
+	for ; i < len(runes); i++ {
+		if runes[i] >= 0x40 && runes[i] <= 0x7e {
+			return i
+		}
+		if runes[i] < 0x20 || runes[i] > 0x3f {
+			// Malformed: drop what was read and resume at this rune
+			return i - 1
+		}
+	}
+	return len(runes) - 1
+}
+
+// skipString returns the index of the terminator of the control string
+// (OSC, DCS, ...) whose content starts at runes[i]: BEL, ST (ESC \) or C1 ST.
+// An unterminated string runs to the end of the text.
+func skipString(runes []rune, i int) int {
+	for ; i < len(runes); i++ {
+		switch runes[i] {
+		case '\a', '\x9c':
+			return i
+		case '\x1b':
+			if i+1 < len(runes) && runes[i+1] == '\\' {
+				return i + 1
+			}
+		}
+	}
+	return len(runes) - 1
+}
+
+// isWide reports whether r is an East Asian wide or fullwidth character, or
+// an emoji presented as one
+func isWide(r rune) bool {
+	for _, w := range wideRanges {
+		if r < w[0] {
+			return false
+		}
+		if r <= w[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// wideRanges are the East Asian Wide (W) and Fullwidth (F) ranges, sorted
+var wideRanges = [][2]rune{
+	{0x1100, 0x115f},   // Hangul Jamo initial consonants
+	{0x231a, 0x231b},   // watch, hourglass
+	{0x2329, 0x232a},   // angle brackets
+	{0x23e9, 0x23ec},   // media controls
+	{0x23f0, 0x23f0},   // alarm clock
+	{0x23f3, 0x23f3},   // hourglass flowing
+	{0x25fd, 0x25fe},   // medium small squares
+	{0x2614, 0x2615},   // umbrella, hot beverage
+	{0x2648, 0x2653},   // zodiac
+	{0x267f, 0x267f},   // wheelchair
+	{0x2693, 0x2693},   // anchor
+	{0x26a1, 0x26a1},   // high voltage
+	{0x26aa, 0x26ab},   // circles
+	{0x26bd, 0x26be},   // soccer, baseball
+	{0x26c4, 0x26c5},   // snowman, sun behind cloud
+	{0x26ce, 0x26ce},   // ophiuchus
+	{0x26d4, 0x26d4},   // no entry
+	{0x26ea, 0x26ea},   // church
+	{0x26f2, 0x26f3},   // fountain, golf
+	{0x26f5, 0x26f5},   // sailboat
+	{0x26fa, 0x26fa},   // tent
+	{0x26fd, 0x26fd},   // fuel pump
+	{0x2705, 0x2705},   // check mark button
+	{0x270a, 0x270b},   // raised fists
+	{0x2728, 0x2728},   // sparkles
+	{0x274c, 0x274c},   // cross mark
+	{0x274e, 0x274e},   // cross mark button
+	{0x2753, 0x2755},   // question and exclamation marks
+	{0x2757, 0x2757},   // exclamation mark
+	{0x2795, 0x2797},   // plus, minus, divide
+	{0x27b0, 0x27b0},   // curly loop
+	{0x27bf, 0x27bf},   // double curly loop
+	{0x2b1b, 0x2b1c},   // large squares
+	{0x2b50, 0x2b50},   // star
+	{0x2b55, 0x2b55},   // hollow red circle
+	{0x2e80, 0x303e},   // CJK radicals, punctuation
+	{0x3041, 0x33ff},   // kana, CJK compatibility
+	{0x3400, 0x4dbf},   // CJK extension A
+	{0x4e00, 0x9fff},   // CJK unified ideographs
+	{0xa000, 0xa4cf},   // Yi
+	{0xa960, 0xa97f},   // Hangul Jamo extended A
+	{0xac00, 0xd7a3},   // Hangul syllables
+	{0xf900, 0xfaff},   // CJK compatibility ideographs
+	{0xfe10, 0xfe19},   // vertical forms
+	{0xfe30, 0xfe6f},   // CJK compatibility forms, small forms
+	{0xff00, 0xff60},   // fullwidth forms
+	{0xffe0, 0xffe6},   // fullwidth signs
+	{0x16fe0, 0x16fe4}, // ideographic symbols
+	{0x17000, 0x18cff}, // Tangut
+	{0x1b000, 0x1b2ff}, // kana supplement and extensions
+	{0x1f004, 0x1f004}, // mahjong tile
+	{0x1f0cf, 0x1f0cf}, // joker
+	{0x1f18e, 0x1f18e}, // AB button
+	{0x1f191, 0x1f19a}, // squared words
+	{0x1f200, 0x1f251}, // enclosed ideographic supplement
+	{0x1f300, 0x1f64f}, // pictographs, emoticons
+	{0x1f680, 0x1f6ff}, // transport and map symbols
+	{0x1f7e0, 0x1f7eb}, // coloured circles and squares
+	{0x1f90c, 0x1f9ff}, // supplemental symbols and pictographs
+	{0x1fa70, 0x1faff}, // symbols and pictographs extended A
+	{0x20000, 0x2fffd}, // CJK extensions B-F
+	{0x30000, 0x3fffd}, // CJK extension G
+}
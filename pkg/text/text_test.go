@@ -0,0 +1,62 @@
+package text
+
+import "testing"
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"plain", "LGTM, ship it", "LGTM, ship it"},
+		{"newlines and tabs kept", "a\n\tb", "a\n\tb"},
+		{"CRLF", "a\r\nb", "a\nb"},
+		{"SGR", "\x1b[31mred\x1b[0m", "red"},
+		{"clear screen", "before\x1b[2J\x1b[Hafter", "beforeafter"},
+		{"cursor up and erase", "ok\x1b[1A\x1b[2Kfake", "okfake"},
+		{"OSC title with BEL", "\x1b]0;pwned\atext", "text"},
+		{"OSC hyperlink with ST", "\x1b]8;;http://evil\x1b\\click\x1b]8;;\x1b\\", "click"},
+		{"unterminated OSC", "text\x1b]0;title", "text"},
+		{"DCS", "\x1bPq#0;2;0;0;0\x1b\\done", "done"},
+		{"charset designation", "\x1b(Bx", "x"},
+		{"reset", "\x1bcx", "x"},
+		{"C1 CSI", "a\u009b2Jb", "ab"},
+		{"bell and backspace", "a\a\bb", "ab"},
+		{"carriage return overwrite", "approved\rrejected", "approvedrejected"},
+		{"bidi override", "file\u202egnp.exe", "filegnp.exe"},
+		{"bidi isolate", "a\u2066b\u2069", "ab"},
+		{"unicode kept", "héllo 世界 👍", "héllo 世界 👍"},
+		{"trailing ESC", "x\x1b", "x"},
+	}
+	for _, tt := range tests {
+		if got := Sanitize(tt.in); got != tt.want {
+			t.Errorf("%s: Sanitize(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLine(t *testing.T) {
+	if got := Line("Fix\tthe\nbug\x1b[2J"); got != "Fix the bug" {
+		t.Errorf("Line = %q", got)
+	}
+}
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"hello", 5},
+		{"héllo", 5},
+		{"e\u0301", 1}, // combining acute
+		{"世界", 4},
+		{"ｆｕｌｌ", 8},
+		{"한국어", 6},
+		{"👍 ok", 5},
+		{"a\u200bb", 2}, // zero-width space
+	}
+	for _, tt := range tests {
+		if got := Width(tt.in); got != tt.want {
+			t.Errorf("Width(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
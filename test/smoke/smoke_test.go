@@ -4,82 +4,14 @@ import (
 	"bytes"
 	"encoding/json"
 	"net/http"
-	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"testing"
-)
-
-// MockServer creates a test HTTP server that mocks Bitbucket Cloud API
-type MockServer struct {
-	*httptest.Server
-	Requests []*http.Request
-}
-
-// NewMockServer creates a new mock Bitbucket API server
-func NewMockServer() *MockServer {
-	ms := &MockServer{
-		Requests: make([]*http.Request, 0),
-	}
 
-	mux := http.NewServeMux()
-
-	// Mock /user endpoint
-	mux.HandleFunc("/2.0/user", func(w http.ResponseWriter, r *http.Request) {
-		ms.Requests = append(ms.Requests, r)
-		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(map[string]interface{}{
-			"uuid":         "{test-uuid}",
-			"username":     "testuser",
-			"display_name": "Test User",
-			"account_id":   "test-account-id",
-			"type":         "user",
-		})
-	})
-
-	// Mock /repositories/{workspace} endpoint
-	mux.HandleFunc("/2.0/repositories/", func(w http.ResponseWriter, r *http.Request) {
-		ms.Requests = append(ms.Requests, r)
-		w.Header().Set("Content-Type", "application/json")
-
-		// Check if it's listing repos or getting a specific repo
-		switch r.URL.Path {
-		case "/2.0/repositories/testworkspace":
-			// List repositories
-			_ = json.NewEncoder(w).Encode(map[string]interface{}{
-				"pagelen": 10,
-				"values": []map[string]interface{}{
-					{
-						"uuid":      "{repo-uuid-1}",
-						"name":      "test-repo",
-						"slug":      "test-repo",
-						"full_name": "testworkspace/test-repo",
-						"is_private": true,
-						"type":      "repository",
-					},
-				},
-				"page": 1,
-				"size": 1,
-			})
-		case "/2.0/repositories/testworkspace/test-repo":
-			// Get specific repository
-			_ = json.NewEncoder(w).Encode(map[string]interface{}{
-				"uuid":      "{repo-uuid-1}",
-				"name":      "test-repo",
-				"slug":      "test-repo",
-				"full_name": "testworkspace/test-repo",
-				"is_private": true,
-				"type":      "repository",
-			})
-		default:
-			w.WriteHeader(http.StatusNotFound)
-		}
-	})
-
-	ms.Server = httptest.NewServer(mux)
-	return ms
-}
+	"github.com/ghoseb/bb/pkg/bbcloud"
+	"github.com/ghoseb/bb/pkg/bbtest"
+)
 
 // RunCLI runs the bb CLI command and returns stdout, stderr, and error
 func RunCLI(args ...string) (string, string, error) {
@@ -171,6 +103,33 @@ func TestRepoListRequiresAuth(t *testing.T) {
 	}
 }
 
+// TestRepoListAgainstMockServer runs list repos against a bbtest server
+// configured as a custom host
+func TestRepoListAgainstMockServer(t *testing.T) {
+	srv := bbtest.NewServer(t)
+	srv.AddRepository("testworkspace", bbcloud.Repository{UUID: "{repo-uuid-1}", Slug: "test-repo", IsPrivate: true})
+
+	dir := t.TempDir()
+	config := "hosts:\n  mock:\n    api_url: " + srv.BaseURL() + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yml"), []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BB_CONFIG_DIR", dir)
+	t.Setenv("BB_HOST", "mock")
+	t.Setenv("BB_WORKSPACE", "testworkspace")
+	t.Setenv("BB_USERNAME", "testuser")
+	t.Setenv("BB_TOKEN", "test-token")
+
+	stdout, stderr, err := RunCLI("list", "repos", "--json")
+	if err != nil {
+		t.Fatalf("list repos: %v\n%s", err, stderr)
+	}
+	if !bytes.Contains([]byte(stdout), []byte(`"slug": "test-repo"`)) {
+		t.Errorf("list repos output missing test-repo: %s", stdout)
+	}
+	srv.AssertRequested(t, http.MethodGet, "/repositories/testworkspace")
+}
+
 // TestPRViewHelp tests PR view help
 func TestPRViewHelp(t *testing.T) {
 	stdout, _, err := RunCLI("review", "--help")